@@ -348,6 +348,10 @@ func processOptionsForArgs(inOpts tree.BackupOptions, outOpts *tree.BackupOption
 		outOpts.IncludeAllSecondaryTenants = inOpts.IncludeAllSecondaryTenants
 	}
 
+	if inOpts.Fingerprints {
+		outOpts.Fingerprints = true
+	}
+
 	// If a string-y option is set to empty, interpret this as "unset."
 	if inOpts.EncryptionPassphrase != nil {
 		if tree.AsStringWithFlags(inOpts.EncryptionPassphrase, tree.FmtBareStrings) == "" {
@@ -0,0 +1,749 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprofiler"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+	gogotypes "github.com/gogo/protobuf/types"
+)
+
+// compactedLayerTimeFormat is the format of the start time appended to the
+// end time of the run of layers a compacted layer replaces in its folder name.
+// Using the end time as the prefix sorts the compacted layer directly after the
+// last layer it replaces when listing the chain, which is the order in which
+// backupinfo.ElideSkippedLayers expects to find it.
+const compactedLayerTimeFormat = "20060102-150405.00"
+
+// selectCompactionRun returns the indexes of the first and last incremental
+// layers of the chain whose intervals lie within [start, end]. An empty start
+// or end leaves that side of the interval unbounded. The chain must not
+// already contain layers that have been replaced by a compacted layer.
+func selectCompactionRun(
+	manifests []backuppb.BackupManifest, start, end hlc.Timestamp,
+) (first, last int, _ error) {
+	first, last = -1, -1
+	for i := 1; i < len(manifests); i++ {
+		m := manifests[i]
+		if m.StartTime.Less(start) || (!end.IsEmpty() && end.Less(m.EndTime)) {
+			continue
+		}
+		if first == -1 {
+			first = i
+		}
+		last = i
+	}
+	if n := last - first + 1; first == -1 || n < 2 {
+		if first == -1 {
+			n = 0
+		}
+		return 0, 0, errors.Newf(
+			"at least two incremental backups are required to compact, found %d in the specified interval", n)
+	}
+	return first, last, nil
+}
+
+// makeCompactedManifest returns the manifest of a layer that replaces the run
+// of layers in run. Its files are added as they are written.
+func makeCompactedManifest(
+	run []backuppb.BackupManifest, revisionHistory bool, spans roachpb.Spans,
+) backuppb.BackupManifest {
+	m := run[len(run)-1]
+	m.ID = uuid.MakeV4()
+	m.StartTime = run[0].StartTime
+	m.Spans = spans
+	m.Files = nil
+	m.EntryCounts = roachpb.RowCount{}
+	m.LocalityKVs = nil
+	m.PartitionDescriptorFilenames = nil
+	m.DescriptorChanges = nil
+	m.RevisionStartTime = hlc.Timestamp{}
+	// The compacted files are not fingerprinted.
+	m.HasFileFingerprints = false
+	m.TableFingerprints = nil
+
+	var introduced roachpb.SpanGroup
+	for i := range run {
+		introduced.Add(run[i].IntroducedSpans...)
+		m.RevisionStartTime.Forward(run[i].RevisionStartTime)
+		if revisionHistory {
+			m.DescriptorChanges = append(m.DescriptorChanges, run[i].DescriptorChanges...)
+		}
+	}
+	m.IntroducedSpans = introduced.Slice()
+	if revisionHistory {
+		m.MVCCFilter = backuppb.MVCCFilter_All
+	} else {
+		m.MVCCFilter = backuppb.MVCCFilter_Latest
+	}
+	return m
+}
+
+// compactBackups is the entry point of a backup job that merges a run of
+// incremental layers of an existing chain into a single layer, instead of
+// backing up new data.
+//
+// The compacted layer is written alongside the layers it replaces, which are
+// left in place. Once its manifest has been written, readers of the chain use
+// the compacted layer in place of the run; see backupinfo.ElideSkippedLayers.
+func (b *backupResumer) compactBackups(
+	ctx context.Context, p sql.JobExecContext, details jobspb.BackupDetails, kmsEnv cloud.KMSEnv,
+) error {
+	execCfg := p.ExecCfg()
+	user := p.User()
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+
+	// If the compacted layer's manifest was written before a prior resumption
+	// of the job was interrupted, the compaction has already completed.
+	if details.URI != "" {
+		done, err := func() (bool, error) {
+			store, err := mkStore(ctx, details.URI, user)
+			if err != nil {
+				return false, err
+			}
+			defer store.Close()
+			r, err := store.ReadFile(ctx, backupbase.BackupManifestName)
+			if err == nil {
+				r.Close(ctx)
+				return true, nil
+			} else if errors.Is(err, cloud.ErrFileDoesNotExist) {
+				return false, nil
+			}
+			return false, err
+		}()
+		if err != nil || done {
+			return err
+		}
+	}
+
+	subdir := details.Destination.Subdir
+	baseDirs, err := backuputils.AppendPaths(details.Destination.To, subdir)
+	if err != nil {
+		return err
+	}
+	incDirs, err := backupdest.ResolveIncrementalsBackupLocation(
+		ctx, user, execCfg, details.Destination.IncrementalStorage, details.Destination.To, subdir,
+	)
+	if err != nil {
+		return err
+	}
+	baseStores, cleanupBase, err := backupdest.MakeBackupDestinationStores(ctx, user, mkStore, baseDirs)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cleanupBase(); err != nil {
+			log.Warningf(ctx, "failed to close base store: %+v", err)
+		}
+	}()
+	incStores, cleanupInc, err := backupdest.MakeBackupDestinationStores(ctx, user, mkStore, incDirs)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cleanupInc(); err != nil {
+			log.Warningf(ctx, "failed to close incremental store: %+v", err)
+		}
+	}()
+
+	var encryption *jobspb.BackupEncryptionOptions
+	if details.EncryptionOptions != nil {
+		encryption, err = backupencryption.GetEncryptionFromBase(ctx, user, mkStore, baseDirs[0],
+			*details.EncryptionOptions, kmsEnv)
+		if err != nil {
+			return err
+		}
+	}
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+	_, manifests, localityInfo, memSize, err := backupdest.ResolveBackupManifests(
+		ctx, &mem, baseStores, incStores, mkStore, baseDirs, incDirs, hlc.Timestamp{}, encryption,
+		kmsEnv, user,
+	)
+	if err != nil {
+		return err
+	}
+	defer mem.Shrink(ctx, memSize)
+
+	first, last, err := selectCompactionRun(manifests, details.StartTime, details.EndTime)
+	if err != nil {
+		return err
+	}
+	run := manifests[first : last+1]
+	revisionHistory := true
+	for i := range run {
+		if len(run[i].LocalityKVs) > 0 {
+			return errors.New("cannot compact locality-aware backups")
+		}
+		if run[i].MVCCFilter != backuppb.MVCCFilter_All {
+			revisionHistory = false
+		}
+	}
+
+	// Claim the directory of the compacted layer and record it, along with the
+	// exact interval of the run, so that a resumption of the job compacts the
+	// same run into the same directory.
+	if details.URI == "" {
+		start, end := run[0].StartTime, run[len(run)-1].EndTime
+		partName := end.GoTime().Format(backupbase.DateBasedIncFolderName) + "_" +
+			start.GoTime().Format(compactedLayerTimeFormat)
+		defaultURI, _, err := backupdest.GetURIsByLocalityKV(incDirs, partName)
+		if err != nil {
+			return err
+		}
+		if err := backupinfo.CheckForPreviousBackup(ctx, execCfg, defaultURI, b.job.ID(), user); err != nil {
+			return err
+		}
+		if err := backupinfo.WriteBackupLock(ctx, execCfg, defaultURI, b.job.ID(), user); err != nil {
+			return err
+		}
+		details.URI = defaultURI
+		details.StartTime = start
+		details.EndTime = end
+		if err := b.job.NoTxn().Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+			if err := md.CheckRunningOrReverting(); err != nil {
+				return err
+			}
+			md.Payload.Details = jobspb.WrapPayloadDetails(details)
+			ju.UpdatePayload(md.Payload)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Without revision history, the compacted layer only needs to cover the
+	// spans backed up by the last layer of the run, as it is as of that layer's
+	// end time that the rest of the run is read.
+	var requiredSpans roachpb.Spans
+	if revisionHistory {
+		var g roachpb.SpanGroup
+		for i := range run {
+			g.Add(run[i].Spans...)
+		}
+		requiredSpans = g.Slice()
+	} else {
+		requiredSpans = append(requiredSpans, run[len(run)-1].Spans...)
+	}
+
+	layerToIterFactory, err := backupinfo.GetBackupManifestIterFactories(
+		ctx, execCfg.DistSQLSrv.ExternalStorage, run, encryption, kmsEnv,
+	)
+	if err != nil {
+		return err
+	}
+	backupLocalityMap, err := makeBackupLocalityMap(localityInfo[first:last+1], user)
+	if err != nil {
+		return err
+	}
+	introducedSpanFrontier, err := createIntroducedSpanFrontier(run, hlc.Timestamp{})
+	if err != nil {
+		return err
+	}
+	filter, err := makeSpanCoveringFilter(
+		nil, /* checkpointFrontier */
+		nil, /* highWater */
+		introducedSpanFrontier,
+		targetRestoreSpanSize.Get(&execCfg.Settings.SV),
+		false, /* useFrontierCheckpointing */
+	)
+	if err != nil {
+		return err
+	}
+	cover, err := makeSimpleImportSpans(ctx, requiredSpans, run, layerToIterFactory, backupLocalityMap, filter)
+	if err != nil {
+		return err
+	}
+
+	compacted := makeCompactedManifest(run, revisionHistory, requiredSpans)
+	pkIDs := make(map[uint64]bool)
+	for i := range compacted.Descriptors {
+		if t, _, _, _, _ := descpb.GetDescriptors(&compacted.Descriptors[i]); t != nil {
+			pkIDs[kvpb.BulkOpSummaryID(uint64(t.ID), uint64(t.PrimaryIndex.ID))] = true
+		}
+	}
+
+	var fileEncryption *kvpb.FileEncryptionOptions
+	if encryption != nil {
+		key, err := backupencryption.GetEncryptionKey(ctx, encryption, kmsEnv)
+		if err != nil {
+			return err
+		}
+		fileEncryption = &kvpb.FileEncryptionOptions{Key: key}
+	}
+
+	spec := execinfrapb.CompactBackupsSpec{
+		JobID:           int64(b.job.ID()),
+		DefaultURI:      details.URI,
+		Encryption:      fileEncryption,
+		StartTime:       details.StartTime,
+		EndTime:         details.EndTime,
+		RevisionHistory: revisionHistory,
+		PKIDs:           pkIDs,
+		UserProto:       user.EncodeProto(),
+	}
+
+	progressLogger := jobs.NewChunkProgressLogger(b.job, len(cover), b.job.FractionCompleted(), jobs.ProgressUpdateOnly)
+	requestFinishedCh := make(chan struct{}, len(cover)) // enough buffer to never block
+	progCh := make(chan *execinfrapb.RemoteProducerMetadata_BulkProcessorProgress)
+	var jobProgressLoop func(ctx context.Context) error
+	if len(cover) > 0 {
+		jobProgressLoop = func(ctx context.Context) error {
+			return progressLogger.Loop(ctx, requestFinishedCh)
+		}
+	}
+	collectLoop := func(ctx context.Context) error {
+		defer close(requestFinishedCh)
+		for progress := range progCh {
+			var progDetails backuppb.BackupManifest_Progress
+			if err := gogotypes.UnmarshalAny(&progress.ProgressDetails, &progDetails); err != nil {
+				return errors.Wrap(err, "unable to unmarshal compaction progress details")
+			}
+			for _, file := range progDetails.Files {
+				compacted.Files = append(compacted.Files, file)
+				compacted.EntryCounts.Add(file.EntryCounts)
+			}
+			for i := int32(0); i < progDetails.CompletedSpans; i++ {
+				requestFinishedCh <- struct{}{}
+			}
+		}
+		return nil
+	}
+	runCompaction := func(ctx context.Context) error {
+		return distCompactBackups(ctx, p, spec, cover, progCh)
+	}
+	if err := ctxgroup.GoAndWait(ctx, jobProgressLoop, collectLoop, runCompaction); err != nil {
+		return errors.Wrapf(err, "compacting %d backups", errors.Safe(len(run)))
+	}
+
+	defaultStore, err := mkStore(ctx, details.URI, user)
+	if err != nil {
+		return err
+	}
+	defer defaultStore.Close()
+	if err := writeBackupMetadata(
+		ctx, execCfg.Settings, defaultStore, encryption, kmsEnv, execCfg.TableStatsCache, &compacted,
+	); err != nil {
+		return err
+	}
+
+	b.backupStats = compacted.EntryCounts
+	log.Infof(ctx, "compacted %d backups in %s into %s", len(run), subdir,
+		backuputils.RedactURIForErrorMessage(details.URI))
+	return nil
+}
+
+// distCompactBackups plans and runs a compactBackupsProcessor on every
+// instance, each of which compacts a contiguous chunk of the cover. Progress
+// is streamed back over progCh, which is closed once the flow completes.
+func distCompactBackups(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	spec execinfrapb.CompactBackupsSpec,
+	cover []execinfrapb.RestoreSpanEntry,
+	progCh chan *execinfrapb.RemoteProducerMetadata_BulkProcessorProgress,
+) error {
+	ctx, span := tracing.ChildSpan(ctx, "backupccl.distCompactBackups")
+	defer span.Finish()
+	defer close(progCh)
+
+	if len(cover) == 0 {
+		return nil
+	}
+
+	evalCtx := execCtx.ExtendedEvalContext()
+	dsp := execCtx.DistSQLPlanner()
+	planCtx, sqlInstanceIDs, err := dsp.SetupAllNodesPlanning(ctx, evalCtx, execCtx.ExecCfg())
+	if err != nil {
+		return err
+	}
+
+	specs := make(map[base.SQLInstanceID]*execinfrapb.CompactBackupsSpec)
+	chunkSize := (len(cover) + len(sqlInstanceIDs) - 1) / len(sqlInstanceIDs)
+	for i, id := range sqlInstanceIDs {
+		start := i * chunkSize
+		if start >= len(cover) {
+			break
+		}
+		end := start + chunkSize
+		if end > len(cover) {
+			end = len(cover)
+		}
+		s := spec
+		s.Entries = cover[start:end]
+		specs[id] = &s
+	}
+
+	corePlacement := make([]physicalplan.ProcessorCorePlacement, 0, len(specs))
+	for id, s := range specs {
+		corePlacement = append(corePlacement, physicalplan.ProcessorCorePlacement{
+			SQLInstanceID: id,
+			Core:          execinfrapb.ProcessorCoreUnion{CompactBackups: s},
+		})
+	}
+
+	p := planCtx.NewPhysicalPlan()
+	// All of the progress information is sent through the metadata stream, so we
+	// have an empty result stream.
+	p.AddNoInputStage(corePlacement, execinfrapb.PostProcessSpec{}, []*types.T{}, execinfrapb.Ordering{})
+	p.PlanToStreamColMap = []int{}
+	sql.FinalizePlan(ctx, planCtx, p)
+
+	metaFn := func(_ context.Context, meta *execinfrapb.ProducerMetadata) error {
+		if meta.BulkProcessorProgress != nil {
+			progCh <- meta.BulkProcessorProgress
+		}
+		return nil
+	}
+	rowResultWriter := sql.NewRowResultWriter(nil)
+	var noTxn *kv.Txn
+	recv := sql.MakeDistSQLReceiver(
+		ctx,
+		sql.NewMetadataCallbackWriter(rowResultWriter, metaFn),
+		tree.Rows,
+		nil,   /* rangeCache */
+		noTxn, /* txn - the flow does not read or write the database */
+		nil,   /* clockUpdater */
+		evalCtx.Tracing,
+	)
+	defer recv.Release()
+
+	execCfg := execCtx.ExecCfg()
+	jobsprofiler.StorePlanDiagram(ctx, execCfg.DistSQLSrv.Stopper, p, execCfg.InternalDB, jobspb.JobID(spec.JobID))
+
+	// Copy the evalCtx, as dsp.Run() might change it.
+	evalCtxCopy := *evalCtx
+	dsp.Run(ctx, planCtx, noTxn, p, recv, &evalCtxCopy, nil /* finishedSetupFn */)
+	return rowResultWriter.Err()
+}
+
+const compactBackupsProcessorName = "compactBackupsProcessor"
+
+// compactBackupsProcessor merges the keys in the files of each of its entries
+// into files of the compacted layer. It streams back the files it writes
+// through the metadata channel provided by DistSQL.
+type compactBackupsProcessor struct {
+	execinfra.ProcessorBase
+
+	flowCtx *execinfra.FlowCtx
+	spec    execinfrapb.CompactBackupsSpec
+
+	// cancelAndWaitForWorker cancels the producer goroutine and waits for it to
+	// finish. It can be called multiple times.
+	cancelAndWaitForWorker func()
+	progCh                 chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress
+	compactErr             error
+
+	// BoundAccount that reserves the memory used to buffer compacted entries.
+	memAcc *mon.BoundAccount
+}
+
+var (
+	_ execinfra.Processor = &compactBackupsProcessor{}
+	_ execinfra.RowSource = &compactBackupsProcessor{}
+)
+
+func newCompactBackupsProcessor(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.CompactBackupsSpec,
+	post *execinfrapb.PostProcessSpec,
+) (execinfra.Processor, error) {
+	ba := flowCtx.Cfg.BackupMonitor.MakeBoundAccount()
+	cp := &compactBackupsProcessor{
+		flowCtx: flowCtx,
+		spec:    spec,
+		progCh:  make(chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress),
+		memAcc:  &ba,
+	}
+	if err := cp.Init(ctx, cp, post, backupOutputTypes, flowCtx, processorID, nil, /* memMonitor */
+		execinfra.ProcStateOpts{
+			// This processor doesn't have any inputs to drain.
+			InputsToDrain: nil,
+			TrailingMetaCallback: func() []execinfrapb.ProducerMetadata {
+				cp.close()
+				return nil
+			},
+		}); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// Start is part of the RowSource interface.
+func (cp *compactBackupsProcessor) Start(ctx context.Context) {
+	ctx = logtags.AddTag(ctx, "job", cp.spec.JobID)
+	ctx = cp.StartInternal(ctx, compactBackupsProcessorName)
+	ctx, cancel := context.WithCancel(ctx)
+
+	cp.cancelAndWaitForWorker = func() {
+		cancel()
+		for range cp.progCh {
+		}
+	}
+	if err := cp.flowCtx.Stopper().RunAsyncTaskEx(ctx, stop.TaskOpts{
+		TaskName: "compactBackupsProcessor.runCompactBackups",
+		SpanOpt:  stop.ChildSpan,
+	}, func(ctx context.Context) {
+		cp.compactErr = runCompactBackups(ctx, cp.flowCtx, &cp.spec, cp.progCh, cp.memAcc)
+		cancel()
+		close(cp.progCh)
+	}); err != nil {
+		// The closure above hasn't run, so we have to do the cleanup.
+		cp.compactErr = err
+		cancel()
+		close(cp.progCh)
+	}
+}
+
+// Next is part of the RowSource interface.
+func (cp *compactBackupsProcessor) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	if cp.State != execinfra.StateRunning {
+		return nil, cp.DrainHelper()
+	}
+
+	for prog := range cp.progCh {
+		// Take a copy so that we can send the progress address to the output
+		// processor.
+		p := prog
+		return nil, &execinfrapb.ProducerMetadata{BulkProcessorProgress: &p}
+	}
+
+	cp.MoveToDraining(cp.compactErr)
+	return nil, cp.DrainHelper()
+}
+
+func (cp *compactBackupsProcessor) close() {
+	cp.cancelAndWaitForWorker()
+	if cp.InternalClose() {
+		cp.memAcc.Close(cp.Ctx())
+	}
+}
+
+// ConsumerClosed is part of the RowSource interface. We have to override the
+// implementation provided by ProcessorBase.
+func (cp *compactBackupsProcessor) ConsumerClosed() {
+	cp.close()
+}
+
+func runCompactBackups(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	spec *execinfrapb.CompactBackupsSpec,
+	progCh chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress,
+	memAcc *mon.BoundAccount,
+) error {
+	store, err := flowCtx.Cfg.ExternalStorageFromURI(ctx, spec.DefaultURI, spec.User())
+	if err != nil {
+		return err
+	}
+	defer logClose(ctx, store, "compaction destination")
+
+	sink := makeFileSSTSink(sstSinkConf{
+		progCh:   progCh,
+		enc:      spec.Encryption,
+		id:       flowCtx.NodeID.SQLInstanceID(),
+		settings: &flowCtx.Cfg.Settings.SV,
+	}, store)
+	defer logClose(ctx, sink, "SST sink")
+
+	for _, entry := range spec.Entries {
+		resp, sz, err := compactSpanEntry(ctx, flowCtx, spec, entry, memAcc)
+		if err != nil {
+			return err
+		}
+		err = sink.write(ctx, resp)
+		memAcc.Shrink(ctx, sz)
+		if err != nil {
+			return err
+		}
+	}
+	return sink.flush(ctx)
+}
+
+// compactSpanEntry merges the keys in the entry's span from the entry's files
+// into a single in-memory SST. Unless the spec asks for revision history, only
+// the latest revision of each point key is kept; all range keys are kept. The
+// SST is reserved in memAcc as it is buffered, and the size of the reservation
+// is returned for the caller to release once the SST is written out.
+func compactSpanEntry(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	spec *execinfrapb.CompactBackupsSpec,
+	entry execinfrapb.RestoreSpanEntry,
+	memAcc *mon.BoundAccount,
+) (_ exportedSpan, _ int64, retErr error) {
+	storeFiles := make([]storageccl.StoreFile, 0, len(entry.Files))
+	for _, file := range entry.Files {
+		dir, err := flowCtx.Cfg.ExternalStorage(ctx, file.Dir)
+		if err != nil {
+			return exportedSpan{}, 0, err
+		}
+		defer logClose(ctx, dir, "compaction source")
+		storeFiles = append(storeFiles, storageccl.StoreFile{Store: dir, FilePath: file.Path})
+	}
+
+	var data storage.MemObject
+	sst := storage.MakeBackupSSTWriter(ctx, flowCtx.Cfg.Settings, &data)
+	defer sst.Close()
+
+	var reserved int64
+	defer func() {
+		if retErr != nil {
+			memAcc.Shrink(ctx, reserved)
+		}
+	}()
+	// reserve grows the reservation to the capacity of the SST's buffer.
+	reserve := func() error {
+		if sz := int64(data.Cap()); sz > reserved {
+			if err := memAcc.Grow(ctx, sz-reserved); err != nil {
+				return err
+			}
+			reserved = sz
+		}
+		return nil
+	}
+
+	var rows storage.RowCounter
+	// To speed up SST reading, write all the point keys first, then all the
+	// range keys, as the file SST sink does.
+	if err := func() error {
+		iter, err := storageccl.ExternalSSTReader(ctx, storeFiles, spec.Encryption, storage.IterOptions{
+			KeyTypes:   storage.IterKeyTypePointsOnly,
+			LowerBound: entry.Span.Key,
+			UpperBound: entry.Span.EndKey,
+		})
+		if err != nil {
+			return err
+		}
+		defer iter.Close()
+		for iter.SeekGE(storage.MVCCKey{Key: entry.Span.Key}); ; {
+			if ok, err := iter.Valid(); err != nil {
+				return err
+			} else if !ok {
+				return nil
+			}
+			key := iter.UnsafeKey()
+			v, err := iter.UnsafeValue()
+			if err != nil {
+				return err
+			}
+			if err := rows.Count(key.Key); err != nil {
+				return errors.Wrapf(err, "decoding %s", key)
+			}
+			rows.DataSize += int64(len(key.Key) + len(v))
+			if key.Timestamp.IsEmpty() {
+				err = sst.PutUnversioned(key.Key, v)
+			} else {
+				err = sst.PutRawMVCC(key, v)
+			}
+			if err != nil {
+				return err
+			}
+			if err := reserve(); err != nil {
+				return err
+			}
+			if spec.RevisionHistory {
+				iter.Next()
+			} else {
+				iter.NextKey()
+			}
+		}
+	}(); err != nil {
+		return exportedSpan{}, 0, err
+	}
+	if err := func() error {
+		iter, err := storageccl.ExternalSSTReader(ctx, storeFiles, spec.Encryption, storage.IterOptions{
+			KeyTypes:   storage.IterKeyTypeRangesOnly,
+			LowerBound: entry.Span.Key,
+			UpperBound: entry.Span.EndKey,
+		})
+		if err != nil {
+			return err
+		}
+		defer iter.Close()
+		for iter.SeekGE(storage.MVCCKey{Key: entry.Span.Key}); ; iter.Next() {
+			if ok, err := iter.Valid(); err != nil {
+				return err
+			} else if !ok {
+				return nil
+			}
+			rangeKeys := iter.RangeKeys()
+			for _, v := range rangeKeys.Versions {
+				if err := sst.PutRawMVCCRangeKey(rangeKeys.AsRangeKey(v), v.Value); err != nil {
+					return err
+				}
+				if err := reserve(); err != nil {
+					return err
+				}
+			}
+		}
+	}(); err != nil {
+		return exportedSpan{}, 0, err
+	}
+	if err := sst.Finish(); err != nil {
+		return exportedSpan{}, 0, err
+	}
+	if err := reserve(); err != nil {
+		return exportedSpan{}, 0, err
+	}
+
+	return exportedSpan{
+		metadata: backuppb.BackupManifest_File{
+			Span:        entry.Span,
+			EntryCounts: countRows(rows.BulkOpSummary, spec.PKIDs),
+			StartTime:   spec.StartTime,
+			EndTime:     spec.EndTime,
+		},
+		dataSST:        data.Bytes(),
+		completedSpans: 1,
+		atKeyBoundary:  true,
+	}, reserved, nil
+}
+
+func init() {
+	rowexec.NewCompactBackupsProcessor = newCompactBackupsProcessor
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompactBackups tests that compacting the incremental layers of a chain
+// produces a chain that restores to the same data, with and without revision
+// history.
+func TestCompactBackups(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	for _, revisionHistory := range []bool{false, true} {
+		t.Run(fmt.Sprintf("revision_history=%t", revisionHistory), func(t *testing.T) {
+			collection := fmt.Sprintf("nodelocal://1/compact-%t", revisionHistory)
+			opts := ""
+			if revisionHistory {
+				opts = " WITH revision_history"
+			}
+			numLayers := func() int {
+				var n int
+				sqlDB.QueryRow(t,
+					`SELECT count(DISTINCT end_time) FROM [SHOW BACKUP LATEST IN $1]`, collection,
+				).Scan(&n)
+				return n
+			}
+
+			// Take a full backup followed by four incremental backups, each of
+			// which sees updates, deletes and inserts, recording the time of each.
+			ts := make([]string, 5)
+			sqlDB.Exec(t, `CREATE TABLE data.compact AS SELECT * FROM data.bank`)
+			sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&ts[0])
+			sqlDB.Exec(t, fmt.Sprintf(`BACKUP TABLE data.compact INTO $1 AS OF SYSTEM TIME %s%s`,
+				ts[0], opts), collection)
+			for i := 1; i < len(ts); i++ {
+				sqlDB.Exec(t, `UPDATE data.compact SET balance = balance + $1 WHERE id % 2 = 0`, i)
+				sqlDB.Exec(t, `DELETE FROM data.compact WHERE id = $1`, i)
+				sqlDB.Exec(t, `INSERT INTO data.compact VALUES ($1, $1, 'new')`, numAccounts+i)
+				sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&ts[i])
+				sqlDB.Exec(t, fmt.Sprintf(`BACKUP TABLE data.compact INTO LATEST IN $1 AS OF SYSTEM TIME %s%s`,
+					ts[i], opts), collection)
+			}
+			expected := sqlDB.QueryStr(t, `SELECT * FROM data.compact ORDER BY id`)
+			require.Equal(t, 5, numLayers())
+
+			// Compact the middle two incremental backups, then the rest.
+			sqlDB.Exec(t, `COMPACT BACKUPS IN $1 FROM $2 TO $3`, collection, ts[1], ts[3])
+			require.Equal(t, 4, numLayers())
+			sqlDB.Exec(t, `COMPACT BACKUPS IN $1`, collection)
+			require.Equal(t, 2, numLayers())
+			sqlDB.ExpectErr(t, "at least two incremental backups are required to compact",
+				`COMPACT BACKUPS IN $1`, collection)
+
+			restoredDB := fmt.Sprintf("restored_%t", revisionHistory)
+			sqlDB.Exec(t, fmt.Sprintf(`CREATE DATABASE %s`, restoredDB))
+			sqlDB.Exec(t, fmt.Sprintf(`RESTORE TABLE data.compact FROM LATEST IN $1 WITH into_db = '%s'`,
+				restoredDB), collection)
+			sqlDB.CheckQueryResults(t,
+				fmt.Sprintf(`SELECT * FROM %s.compact ORDER BY id`, restoredDB), expected)
+			sqlDB.Exec(t, `DROP TABLE data.compact`)
+		})
+	}
+}
+
+func TestSelectCompactionRun(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ts := func(i int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: i}
+	}
+	chain := []backuppb.BackupManifest{
+		{EndTime: ts(1)},
+		{StartTime: ts(1), EndTime: ts(2)},
+		{StartTime: ts(2), EndTime: ts(3)},
+		{StartTime: ts(3), EndTime: ts(4)},
+	}
+
+	for _, tc := range []struct {
+		start, end  hlc.Timestamp
+		first, last int
+		err         string
+	}{
+		{first: 1, last: 3},
+		{start: ts(2), first: 2, last: 3},
+		{end: ts(3), first: 1, last: 2},
+		{start: ts(1), end: ts(4), first: 1, last: 3},
+		{start: ts(2), end: ts(3), err: "found 1 in the specified interval"},
+		{start: ts(4), err: "found 0 in the specified interval"},
+	} {
+		t.Run(fmt.Sprintf("%s-%s", tc.start, tc.end), func(t *testing.T) {
+			first, last, err := selectCompactionRun(chain, tc.start, tc.end)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.first, first)
+			require.Equal(t, tc.last, last)
+		})
+	}
+}
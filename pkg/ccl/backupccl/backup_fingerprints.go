@@ -0,0 +1,407 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"hash"
+	"hash/fnv"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// checkFingerprintsSupported returns an error if the cluster version does not
+// yet allow ExportRequests to return fingerprints.
+func checkFingerprintsSupported(ctx context.Context, execCfg *sql.ExecutorConfig) error {
+	if !execCfg.Settings.Version.IsActive(ctx, clusterversion.V23_1) {
+		return errors.Newf("fingerprinting backups requires the cluster version to be at least %s",
+			clusterversion.V23_1.String())
+	}
+	return nil
+}
+
+// checkVerifyFingerprintsSupported returns an error if the options of a
+// RESTORE prevent it from verifying the restored data against the fingerprints
+// recorded in the backup.
+func checkVerifyFingerprintsSupported(restoreStmt *tree.Restore) error {
+	opts := restoreStmt.Options
+	switch {
+	case opts.SchemaOnly:
+		return errors.New("cannot verify fingerprints with the schema_only option")
+	case restoreStmt.RowFilter != nil:
+		return errors.New("cannot verify fingerprints of a row filtered restore")
+	}
+	return nil
+}
+
+// checkBackupHasFingerprints returns an error if the fingerprints recorded in
+// the last of the given backups cannot be compared to the data restored as of
+// endTime, either because that backup has none or because endTime is not the
+// time they were computed at. Only full backups without revision history
+// record table fingerprints: the fingerprints of an incremental backup only
+// cover the keys it contains, and a key updated or deleted in an incremental
+// backup cannot be removed from the fingerprint of the layer below, so the
+// fingerprints of the layers of a chain cannot be combined into those of the
+// restored data.
+func checkBackupHasFingerprints(manifests []backuppb.BackupManifest, endTime hlc.Timestamp) error {
+	const fullBackupsOnly = "verify_fingerprints only supports restoring a full backup " +
+		"without revision history"
+	last := manifests[len(manifests)-1]
+	if last.IsIncremental() {
+		return errors.WithHintf(
+			pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s: the restored backup is an incremental backup", fullBackupsOnly),
+			"restore AS OF SYSTEM TIME %s to restore the full backup of the chain",
+			manifests[0].EndTime.AsOfSystemTime())
+	}
+	if last.MVCCFilter == backuppb.MVCCFilter_All {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s: the restored backup is a backup with revision history", fullBackupsOnly)
+	}
+	if len(last.TableFingerprints) == 0 {
+		return errors.WithHint(
+			errors.New("cannot verify fingerprints of a backup that does not contain any"),
+			"take the full backup with the fingerprints option")
+	}
+	if !endTime.IsEmpty() && !endTime.Equal(last.EndTime) {
+		return errors.Newf("cannot verify fingerprints of a restore AS OF SYSTEM TIME %s: "+
+			"the backup only has fingerprints as of its end time %s", endTime, last.EndTime)
+	}
+	return nil
+}
+
+// includeTableFingerprint returns true if the data of the given table is
+// restored verbatim, so that its fingerprint can be recorded in a backup and
+// verified after it is restored.
+func includeTableFingerprint(table catalog.TableDescriptor) bool {
+	// The system tables of a cluster backup are restored through staging tables
+	// whose content is rewritten, so they cannot be verified.
+	return table.Public() && table.IsPhysicalTable() && !table.GetExcludeDataFromBackup() &&
+		table.GetParentID() != keys.SystemDatabaseID
+}
+
+// fingerprintOptions are the options of the fingerprints recorded in a backup.
+// The fingerprints ignore the tenant and index prefixes of the keys, their
+// timestamps and the checksums of their values, like crdb_internal.fingerprint
+// with stripped set, so that they do not change when the keys are rewritten by
+// a restore.
+var fingerprintOptions = storage.MVCCExportFingerprintOptions{
+	StripTenantPrefix:            true,
+	StripValueChecksum:           true,
+	StripIndexPrefixAndTimestamp: true,
+}
+
+// tableFingerprinter accumulates the fingerprints of point keys by the table
+// and index they belong to. The keys of every tenant are attributed to their
+// table and index regardless of their tenant, so that the fingerprints of a
+// backup can be verified by any tenant. The zero value can only accumulate
+// fingerprints that were previously computed, with addTables.
+type tableFingerprinter struct {
+	hasher       hash.Hash64
+	fingerprints map[descpb.ID]map[descpb.IndexID]uint64
+}
+
+func makeTableFingerprinter() tableFingerprinter {
+	return tableFingerprinter{hasher: fnv.New64()}
+}
+
+// add adds the fingerprint of a point key and its value to the fingerprint of
+// its index. Keys that are not in the index keyspace are not fingerprinted.
+func (f *tableFingerprinter) add(key storage.MVCCKey, value []byte) error {
+	stripped, err := keys.StripTenantPrefix(key.Key)
+	if err != nil {
+		return nil //nolint:returnerrcheck
+	}
+	_, tableID, indexID, err := keys.SystemSQLCodec.DecodeIndexPrefix(stripped)
+	if err != nil {
+		return nil //nolint:returnerrcheck
+	}
+	fingerprint, err := storage.FingerprintPointKey(f.hasher, fingerprintOptions, key, value)
+	if err != nil {
+		return err
+	}
+	f.addIndex(descpb.ID(tableID), descpb.IndexID(indexID), fingerprint)
+	return nil
+}
+
+func (f *tableFingerprinter) addIndex(
+	tableID descpb.ID, indexID descpb.IndexID, fingerprint uint64,
+) {
+	if f.fingerprints == nil {
+		f.fingerprints = make(map[descpb.ID]map[descpb.IndexID]uint64)
+	}
+	indexes, ok := f.fingerprints[tableID]
+	if !ok {
+		indexes = make(map[descpb.IndexID]uint64)
+		f.fingerprints[tableID] = indexes
+	}
+	indexes[indexID] ^= fingerprint
+}
+
+// addTables adds fingerprints that were previously accumulated.
+func (f *tableFingerprinter) addTables(fingerprints []backuppb.BackupManifest_TableFingerprint) {
+	for _, table := range fingerprints {
+		for _, idx := range table.Indexes {
+			f.addIndex(table.TableID, idx.IndexID, idx.Fingerprint)
+		}
+	}
+}
+
+// finish returns the accumulated fingerprints, ordered by table and index ID.
+func (f *tableFingerprinter) finish() []backuppb.BackupManifest_TableFingerprint {
+	fingerprints := make([]backuppb.BackupManifest_TableFingerprint, 0, len(f.fingerprints))
+	for tableID, indexes := range f.fingerprints {
+		table := backuppb.BackupManifest_TableFingerprint{TableID: tableID}
+		for indexID, fingerprint := range indexes {
+			table.Fingerprint ^= fingerprint
+			table.Indexes = append(table.Indexes, backuppb.BackupManifest_IndexFingerprint{
+				IndexID:     indexID,
+				Fingerprint: fingerprint,
+			})
+		}
+		sort.Slice(table.Indexes, func(i, j int) bool {
+			return table.Indexes[i].IndexID < table.Indexes[j].IndexID
+		})
+		fingerprints = append(fingerprints, table)
+	}
+	sort.Slice(fingerprints, func(i, j int) bool {
+		return fingerprints[i].TableID < fingerprints[j].TableID
+	})
+	return fingerprints
+}
+
+// fingerprintFile returns the fingerprints of the point keys in span of the
+// SST returned by an ExportRequest.
+func fingerprintFile(
+	sst []byte, span roachpb.Span,
+) ([]backuppb.BackupManifest_TableFingerprint, error) {
+	iter, err := storage.NewMemSSTIterator(sst, false /* verify */, storage.IterOptions{
+		KeyTypes:   storage.IterKeyTypePointsOnly,
+		LowerBound: span.Key,
+		UpperBound: span.EndKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	fingerprinter := makeTableFingerprinter()
+	for iter.SeekGE(storage.MVCCKey{Key: span.Key}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		v, err := iter.UnsafeValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := fingerprinter.add(iter.UnsafeKey(), v); err != nil {
+			return nil, err
+		}
+	}
+	return fingerprinter.finish(), nil
+}
+
+// mergeFileFingerprints returns the fingerprints of a file that extends the
+// span of a file with the fingerprints a by a span with the fingerprints b.
+func mergeFileFingerprints(
+	a, b []backuppb.BackupManifest_TableFingerprint,
+) []backuppb.BackupManifest_TableFingerprint {
+	if len(b) == 0 {
+		return a
+	}
+	var fingerprinter tableFingerprinter
+	fingerprinter.addTables(a)
+	fingerprinter.addTables(b)
+	return fingerprinter.finish()
+}
+
+// tableFingerprintsEqual returns true if a and b hold the same fingerprints,
+// in the same order.
+func tableFingerprintsEqual(a, b []backuppb.BackupManifest_TableFingerprint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].TableID != b[i].TableID || a[i].Fingerprint != b[i].Fingerprint ||
+			len(a[i].Indexes) != len(b[i].Indexes) {
+			return false
+		}
+		for j := range a[i].Indexes {
+			if a[i].Indexes[j] != b[i].Indexes[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// aggregateTableFingerprints returns the fingerprint of every public index of
+// every table in descs whose data is included in a backup, aggregated from the
+// fingerprints of the files of the backup. A resumed backup does not re-export
+// the spans it had already completed, but the files it exported before it was
+// paused are checkpointed along with their fingerprints.
+//
+// The aggregate is the fingerprint of the live data of the tables as of the end
+// time of the backup only if the backup is a full backup without revision
+// history, whose files contain exactly one revision of each live key.
+func aggregateTableFingerprints(
+	descs []descpb.Descriptor, files []backuppb.BackupManifest_File,
+) []backuppb.BackupManifest_TableFingerprint {
+	var fingerprinter tableFingerprinter
+	for i := range files {
+		fingerprinter.addTables(files[i].TableFingerprints)
+	}
+
+	var fingerprints []backuppb.BackupManifest_TableFingerprint
+	for i := range descs {
+		desc, _, _, _, _ := descpb.GetDescriptors(&descs[i])
+		if desc == nil {
+			continue
+		}
+		table := tabledesc.NewBuilder(desc).BuildImmutableTable()
+		if !includeTableFingerprint(table) {
+			continue
+		}
+		fingerprint := backuppb.BackupManifest_TableFingerprint{TableID: table.GetID()}
+		for _, idx := range table.ActiveIndexes() {
+			indexFingerprint := fingerprinter.fingerprints[table.GetID()][idx.GetID()]
+			fingerprint.Fingerprint ^= indexFingerprint
+			fingerprint.Indexes = append(fingerprint.Indexes, backuppb.BackupManifest_IndexFingerprint{
+				IndexID:     idx.GetID(),
+				Fingerprint: indexFingerprint,
+			})
+		}
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	return fingerprints
+}
+
+// fingerprintSpan returns the fingerprint of the latest values of the keys in
+// span as of the given time, computed with the fingerprintOptions.
+func fingerprintSpan(
+	ctx context.Context, execCfg *sql.ExecutorConfig, span roachpb.Span, ts hlc.Timestamp,
+) (uint64, error) {
+	header := kvpb.Header{
+		Timestamp:                   ts,
+		ReturnElasticCPUResumeSpans: true,
+	}
+	admissionHeader := kvpb.AdmissionHeader{
+		Priority:                 int32(admissionpb.BulkNormalPri),
+		CreateTime:               timeutil.Now().UnixNano(),
+		Source:                   kvpb.AdmissionHeader_FROM_SQL,
+		NoMemoryReservedAtSource: true,
+	}
+
+	var fingerprint uint64
+	var ssts [][]byte
+	for {
+		req := &kvpb.ExportRequest{
+			RequestHeader:     kvpb.RequestHeaderFromSpan(span),
+			MVCCFilter:        kvpb.MVCCFilter_Latest,
+			ExportFingerprint: true,
+			FingerprintOptions: kvpb.FingerprintOptions{
+				StripIndexPrefixAndTimestamp: fingerprintOptions.StripIndexPrefixAndTimestamp,
+			},
+		}
+		rawResp, pErr := kv.SendWrappedWithAdmission(
+			ctx, execCfg.DB.NonTransactionalSender(), header, admissionHeader, req,
+		)
+		if pErr != nil {
+			return 0, pErr.GoError()
+		}
+		resp := rawResp.(*kvpb.ExportResponse)
+		for _, file := range resp.Files {
+			fingerprint ^= file.Fingerprint
+			if len(file.SST) != 0 {
+				ssts = append(ssts, file.SST)
+			}
+		}
+		if resp.ResumeSpan == nil {
+			break
+		}
+		if !resp.ResumeSpan.Valid() {
+			return 0, errors.Errorf("invalid resume span: %s", resp.ResumeSpan)
+		}
+		span = *resp.ResumeSpan
+	}
+
+	// Range keys are fingerprinted once all of them have been exported, since
+	// their fragmentation depends on the ExportRequests that returned them.
+	rangeKeyFingerprint, err := storage.FingerprintRangekeys(ctx, execCfg.Settings,
+		fingerprintOptions, ssts)
+	if err != nil {
+		return 0, err
+	}
+	return fingerprint ^ rangeKeyFingerprint, nil
+}
+
+// verifyTableFingerprints checks the restored tables against the fingerprints
+// recorded in the backup they were restored from, as of the given time. The
+// fingerprints are keyed by the IDs of the tables in the backup, which are
+// mapped to the IDs of the restored tables with rewrites.
+func verifyTableFingerprints(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	tables []*descpb.TableDescriptor,
+	rewrites jobspb.DescRewriteMap,
+	expected []backuppb.BackupManifest_TableFingerprint,
+	ts hlc.Timestamp,
+) error {
+	tablesByID := make(map[descpb.ID]catalog.TableDescriptor, len(tables))
+	for _, table := range tables {
+		tablesByID[table.GetID()] = tabledesc.NewBuilder(table).BuildImmutableTable()
+	}
+	for _, tableFingerprint := range expected {
+		rewrite, ok := rewrites[tableFingerprint.TableID]
+		if !ok {
+			continue
+		}
+		table, ok := tablesByID[rewrite.ID]
+		if !ok {
+			continue
+		}
+		for _, indexFingerprint := range tableFingerprint.Indexes {
+			idx, err := catalog.MustFindIndexByID(table, indexFingerprint.IndexID)
+			if err != nil {
+				return err
+			}
+			fingerprint, err := fingerprintSpan(ctx, execCfg, table.IndexSpan(execCfg.Codec, idx.GetID()), ts)
+			if err != nil {
+				return errors.Wrapf(err,
+					"fingerprinting index %s of table %s", idx.GetName(), table.GetName())
+			}
+			if fingerprint != indexFingerprint.Fingerprint {
+				return errors.Errorf(
+					"fingerprint mismatch for index %s of restored table %s: expected %d, found %d",
+					idx.GetName(), table.GetName(), indexFingerprint.Fingerprint, fingerprint)
+			}
+		}
+	}
+	return nil
+}
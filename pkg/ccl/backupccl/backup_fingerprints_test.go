@@ -0,0 +1,98 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRestoreVerifyFingerprints tests that a restore verifies the restored
+// tables against the fingerprints recorded in the full backup it restores, and
+// that VERIFY BACKUP checks the fingerprints of the files of every backup taken
+// with the fingerprints option.
+func TestRestoreVerifyFingerprints(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 20
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	const collection = "nodelocal://1/fingerprints"
+	sqlDB.Exec(t, `CREATE INDEX balance_idx ON data.bank (balance)`)
+	var fullEndTime string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&fullEndTime)
+	sqlDB.Exec(t, fmt.Sprintf(`BACKUP DATABASE data INTO $1 AS OF SYSTEM TIME %s WITH fingerprints`,
+		fullEndTime), collection)
+
+	sqlDB.Exec(t, `RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = 'restored', verify_fingerprints`,
+		collection)
+	sqlDB.CheckQueryResults(t, `SELECT * FROM restored.bank ORDER BY id`,
+		sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`))
+
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id % 3 = 0`)
+	sqlDB.Exec(t, `DELETE FROM data.bank WHERE id > 15`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1 WITH fingerprints`, collection)
+	for _, row := range sqlDB.QueryStr(t, `VERIFY BACKUP FROM LATEST IN $1 WITH deep`, collection) {
+		require.Equal(t, verifyBackupStatusOK, row[4], row[0])
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct{ stmt, err string }{
+			{
+				`RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = 'r', verify_fingerprints, schema_only`,
+				"schema_only",
+			},
+			{
+				`RESTORE TABLE data.bank FROM LATEST IN $1 WHERE id > 1 INTO b WITH verify_fingerprints`,
+				"row filtered",
+			},
+			{
+				`RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = 'r', verify_fingerprints`,
+				"incremental backup",
+			},
+		} {
+			sqlDB.ExpectErr(t, tc.err, tc.stmt, collection)
+		}
+
+		// Only full backups can be verified; the error explains so and hints at
+		// restoring the full backup of the chain, which can be verified.
+		_, err := sqlDB.DB.ExecContext(context.Background(),
+			`RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = 'r', verify_fingerprints`, collection)
+		var pqErr *pq.Error
+		require.True(t, errors.As(err, &pqErr), "%+v", err)
+		require.Equal(t, pgcode.FeatureNotSupported.String(), string(pqErr.Code))
+		require.Contains(t, pqErr.Message, "only supports restoring a full backup without revision history")
+		require.Contains(t, pqErr.Hint, fullEndTime)
+		sqlDB.Exec(t, fmt.Sprintf(`RESTORE DATABASE data FROM LATEST IN $1 AS OF SYSTEM TIME %s `+
+			`WITH new_db_name = 'restored_full', verify_fingerprints`, fullEndTime), collection)
+
+		const revisionHistory = "nodelocal://1/fingerprints-revision-history"
+		sqlDB.Exec(t, `BACKUP DATABASE data INTO $1 WITH fingerprints, revision_history`, revisionHistory)
+		sqlDB.ExpectErr(t, "revision history",
+			`RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = 'r', verify_fingerprints`,
+			revisionHistory)
+
+		// The restored backup has no fingerprints.
+		const noFingerprints = "nodelocal://1/no-fingerprints"
+		sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, noFingerprints)
+		sqlDB.ExpectErr(t, "does not contain any",
+			`RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = 'r', verify_fingerprints`,
+			noFingerprints)
+	})
+}
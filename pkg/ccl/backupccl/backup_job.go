@@ -140,6 +140,7 @@ func backup(
 	encryption *jobspb.BackupEncryptionOptions,
 	statsCache *stats.TableStatisticsCache,
 	execLocality roachpb.Locality,
+	fingerprints bool,
 ) (roachpb.RowCount, error) {
 	resumerSpan := tracing.SpanFromContext(ctx)
 	var lastCheckpoint time.Time
@@ -214,6 +215,7 @@ func backup(
 		kvpb.MVCCFilter(backupManifest.MVCCFilter),
 		backupManifest.StartTime,
 		backupManifest.EndTime,
+		fingerprints,
 	)
 	if err != nil {
 		return roachpb.RowCount{}, err
@@ -301,6 +303,19 @@ func backup(
 		return roachpb.RowCount{}, errors.Wrapf(err, "exporting %d ranges", errors.Safe(numTotalSpans))
 	}
 
+	// The processors fingerprinted the files they exported. For a full backup
+	// without revision history, these files hold the live data of the backed up
+	// tables as of the end time of the backup, so the fingerprints of the tables
+	// are the aggregate of those of the files.
+	if fingerprints {
+		backupManifest.HasFileFingerprints = true
+		if !backupManifest.IsIncremental() && backupManifest.MVCCFilter == backuppb.MVCCFilter_Latest {
+			backupManifest.TableFingerprints = aggregateTableFingerprints(
+				backupManifest.Descriptors, backupManifest.Files,
+			)
+		}
+	}
+
 	backupID := uuid.MakeV4()
 	backupManifest.ID = backupID
 	// Write additional partial descriptors to each node for partitioned backups.
@@ -341,6 +356,26 @@ func backup(
 		}
 	}
 
+	if err := writeBackupMetadata(
+		ctx, settings, defaultStore, encryption, &kmsEnv, statsCache, backupManifest,
+	); err != nil {
+		return roachpb.RowCount{}, err
+	}
+
+	return backupManifest.EntryCounts, nil
+}
+
+// writeBackupMetadata writes the completed backupManifest, along with the
+// table statistics for the descriptors it contains, to defaultStore.
+func writeBackupMetadata(
+	ctx context.Context,
+	settings *cluster.Settings,
+	defaultStore cloud.ExternalStorage,
+	encryption *jobspb.BackupEncryptionOptions,
+	kmsEnv cloud.KMSEnv,
+	statsCache *stats.TableStatisticsCache,
+	backupManifest *backuppb.BackupManifest,
+) error {
 	// Write a `BACKUP_MANIFEST` file to support backups in mixed-version clusters
 	// with 22.2 nodes.
 	//
@@ -348,8 +383,8 @@ func backup(
 	// because a mixed-version cluster with 23.1 nodes will read the
 	// `BACKUP_METADATA` instead.
 	if err := backupinfo.WriteBackupManifest(ctx, defaultStore, backupbase.BackupManifestName,
-		encryption, &kmsEnv, backupManifest); err != nil {
-		return roachpb.RowCount{}, err
+		encryption, kmsEnv, backupManifest); err != nil {
+		return err
 	}
 
 	// Write a `BACKUP_METADATA` file along with SSTs for all the alloc heavy
@@ -360,28 +395,28 @@ func backup(
 	// manifest.
 	if backupinfo.WriteMetadataWithExternalSSTsEnabled.Get(&settings.SV) {
 		if err := backupinfo.WriteMetadataWithExternalSSTs(ctx, defaultStore, encryption,
-			&kmsEnv, backupManifest); err != nil {
-			return roachpb.RowCount{}, err
+			kmsEnv, backupManifest); err != nil {
+			return err
 		}
 	}
 
 	statsTable := getTableStatsForBackup(ctx, statsCache, backupManifest.Descriptors)
-	if err := backupinfo.WriteTableStatistics(ctx, defaultStore, encryption, &kmsEnv, &statsTable); err != nil {
-		return roachpb.RowCount{}, err
+	if err := backupinfo.WriteTableStatistics(ctx, defaultStore, encryption, kmsEnv, &statsTable); err != nil {
+		return err
 	}
 
 	if backupinfo.WriteMetadataSST.Get(&settings.SV) {
-		if err := backupinfo.WriteBackupMetadataSST(ctx, defaultStore, encryption, &kmsEnv, backupManifest,
+		if err := backupinfo.WriteBackupMetadataSST(ctx, defaultStore, encryption, kmsEnv, backupManifest,
 			statsTable.Statistics); err != nil {
 			err = errors.Wrap(err, "writing forward-compat metadata sst")
 			if !build.IsRelease() {
-				return roachpb.RowCount{}, err
+				return err
 			}
 			log.Warningf(ctx, "%+v", err)
 		}
 	}
 
-	return backupManifest.EntryCounts, nil
+	return nil
 }
 
 func releaseProtectedTimestamp(
@@ -475,6 +510,10 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		p.User(),
 	)
 
+	if details.Compact {
+		return b.compactBackups(ctx, p, details, &kmsEnv)
+	}
+
 	// Resolve the backup destination. We can skip this step if we
 	// have already resolved and persisted the destination either
 	// during a previous resumption of this job.
@@ -718,6 +757,7 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 			details.EncryptionOptions,
 			statsCache,
 			details.ExecutionLocality,
+			details.Fingerprints,
 		)
 		if err == nil {
 			break
@@ -807,6 +847,12 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		if err := backupdest.WriteNewLatestFile(ctx, p.ExecCfg().Settings, c, suffix); err != nil {
 			return err
 		}
+
+		if err := b.maybeDeleteExpiredBackups(ctx, p, details, suffix); err != nil {
+			// Failing to delete expired backups should not fail the backup; they
+			// are deleted the next time a full backup of the schedule succeeds.
+			log.Warningf(ctx, "failed to delete expired backups: %v", err)
+		}
 	}
 
 	b.backupStats = res
@@ -939,6 +985,9 @@ func getBackupDetailAndManifest(
 			return jobspb.BackupDetails{}, backuppb.BackupManifest{}, err
 		}
 		defer mem.Shrink(ctx, memSize)
+		// Layers compacted by COMPACT BACKUPS overlap the layer they were
+		// compacted into, so only the latter is kept in the chain.
+		_, prevBackups, _ = backupinfo.ElideSkippedLayers(nil /* uris */, prevBackups, nil /* localityInfo */)
 	}
 
 	if len(prevBackups) > 0 {
@@ -198,6 +198,7 @@ func resolveOptionsForBackupJobDescription(
 		CaptureRevisionHistory: opts.CaptureRevisionHistory,
 		Detached:               opts.Detached,
 		ExecutionLocality:      opts.ExecutionLocality,
+		Fingerprints:           opts.Fingerprints,
 	}
 
 	if opts.EncryptionPassphrase != nil {
@@ -626,6 +627,12 @@ func backupPlanHook(
 		}
 	}
 
+	if backupStmt.Options.Fingerprints {
+		if err := checkFingerprintsSupported(ctx, p.ExecCfg()); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
 	encryptionParams := jobspb.BackupEncryptionOptions{
 		Mode: jobspb.EncryptionMode_None,
 	}
@@ -762,6 +769,7 @@ func backupPlanHook(
 			Detached:                   detached,
 			ApplicationName:            p.SessionData().ApplicationName,
 			ExecutionLocality:          executionLocality,
+			Fingerprints:               backupStmt.Options.Fingerprints,
 		}
 		if backupStmt.CreatedByInfo != nil && backupStmt.CreatedByInfo.Name == jobs.CreatedByScheduledJobs {
 			initialDetails.ScheduleID = backupStmt.CreatedByInfo.ID
@@ -1563,6 +1571,7 @@ func createBackupManifest(
 		ClusterID:           execCfg.NodeInfo.LogicalClusterID(),
 		StatisticsFilenames: statsFiles,
 		DescriptorCoverage:  coverage,
+		ScheduleID:          jobDetails.ScheduleID,
 	}
 	if err := checkCoverage(ctx, backupManifest.Spans, append(prevBackups, backupManifest)); err != nil {
 		return backuppb.BackupManifest{}, errors.Wrap(err, "new backup would not cover expected time")
@@ -530,6 +530,13 @@ func runBackupProcessor(
 							ret.metadata.StartTime = span.start
 							ret.metadata.EndTime = span.end
 						}
+						if spec.Fingerprints {
+							fingerprints, err := fingerprintFile(file.SST, file.Span)
+							if err != nil {
+								return errors.Wrapf(err, "fingerprinting %s", file.Span)
+							}
+							ret.metadata.TableFingerprints = fingerprints
+						}
 						// If multiple files were returned for this span, only one -- the
 						// last -- should count as completing the requested span.
 						if i == len(resp.Files)-1 {
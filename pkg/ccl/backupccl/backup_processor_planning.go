@@ -45,6 +45,7 @@ func distBackupPlanSpecs(
 	kmsEnv cloud.KMSEnv,
 	mvccFilter kvpb.MVCCFilter,
 	startTime, endTime hlc.Timestamp,
+	fingerprints bool,
 ) (map[base.SQLInstanceID]*execinfrapb.BackupDataSpec, error) {
 	var span *tracing.Span
 	ctx, span = tracing.ChildSpan(ctx, "backupccl.distBackupPlanSpecs")
@@ -107,6 +108,7 @@ func distBackupPlanSpecs(
 			BackupStartTime:  startTime,
 			BackupEndTime:    endTime,
 			UserProto:        user.EncodeProto(),
+			Fingerprints:     fingerprints,
 		}
 		sqlInstanceIDToSpec[partition.SQLInstanceID] = spec
 	}
@@ -129,6 +131,7 @@ func distBackupPlanSpecs(
 				BackupStartTime:  startTime,
 				BackupEndTime:    endTime,
 				UserProto:        user.EncodeProto(),
+				Fingerprints:     fingerprints,
 			}
 			sqlInstanceIDToSpec[partition.SQLInstanceID] = spec
 		}
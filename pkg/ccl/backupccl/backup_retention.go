@@ -0,0 +1,466 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+// parseRetention parses the value of the retention option of a backup
+// schedule, which must be a positive interval.
+func parseRetention(v string) (duration.Duration, error) {
+	d, err := duration.ParseInterval(duration.IntervalStyle_POSTGRES, v, types.DefaultIntervalTypeMetadata)
+	if err != nil {
+		return duration.Duration{}, errors.Wrapf(err, "invalid %s %q", optRetention, v)
+	}
+	if d.Compare(duration.Duration{}) <= 0 {
+		return duration.Duration{}, errors.Newf("%s must be a positive interval, got %q", optRetention, v)
+	}
+	return d, nil
+}
+
+// expiredBackupChains returns the subdirs of the full backups in a collection
+// whose chains are no longer needed to restore to any time after cutoff. The
+// chain of a full backup is only needed until the next full backup was taken,
+// so it expires once the next full backup was taken at or before cutoff. The
+// chain of the latest full backup thus never expires.
+func expiredBackupChains(subdirs []string, cutoff time.Time) []string {
+	type chain struct {
+		subdir string
+		start  time.Time
+	}
+	chains := make([]chain, 0, len(subdirs))
+	for _, subdir := range subdirs {
+		start, err := time.Parse(backupbase.DateBasedIntoFolderName, "/"+strings.TrimPrefix(subdir, "/"))
+		if err != nil {
+			// The full backup was taken into a user specified subdir, which does
+			// not tell us when it was taken. Leave it alone.
+			continue
+		}
+		chains = append(chains, chain{subdir: subdir, start: start})
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i].start.Before(chains[j].start) })
+
+	var expired []string
+	for i := 0; i+1 < len(chains); i++ {
+		if chains[i+1].start.After(cutoff) {
+			break
+		}
+		expired = append(expired, chains[i].subdir)
+	}
+	return expired
+}
+
+// maybeDeleteExpiredBackups deletes the chains of backups in the collection of
+// a successful full backup that fell out of the retention window of the
+// schedule that created it, if any. Only the chains whose full backup was taken
+// by the schedule are deleted, so that a collection can be shared with other
+// schedules or manual backups. A chain is never deleted while LATEST points to
+// it or while a backup or restore job that uses it is running.
+func (b *backupResumer) maybeDeleteExpiredBackups(
+	ctx context.Context, p sql.JobExecContext, details jobspb.BackupDetails, latestSubdir string,
+) error {
+	if details.ScheduleID == 0 || details.CollectionURI == "" {
+		return nil
+	}
+	execCfg := p.ExecCfg()
+	env := scheduledjobs.ProdJobSchedulerEnv
+	if knobs, ok := execCfg.DistSQLSrv.TestingKnobs.JobsTestingKnobs.(*jobs.TestingKnobs); ok {
+		if knobs.JobSchedulerEnv != nil {
+			env = knobs.JobSchedulerEnv
+		}
+	}
+
+	var args *backuppb.ScheduledBackupExecutionArgs
+	var encryptionParams jobspb.BackupEncryptionOptions
+	var incrementalStorage []string
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		schedules := jobs.ScheduledJobTxn(txn)
+		sj, scheduleArgs, err := getScheduledBackupExecutionArgsFromSchedule(ctx, env, schedules, details.ScheduleID)
+		if err != nil {
+			return err
+		}
+		args = scheduleArgs
+		if args.Retention == "" {
+			return nil
+		}
+		// The manifests of the chains are read with the encryption options of
+		// the schedule, to check which of them the schedule took.
+		fullStmt, err := extractBackupStatement(sj)
+		if err != nil {
+			return err
+		}
+		if encryptionParams, err = scheduleEncryptionParams(fullStmt.Backup); err != nil {
+			return err
+		}
+		if args.DependentScheduleID == 0 {
+			return nil
+		}
+		// The incremental backups of the chains are stored wherever the
+		// incremental schedule puts them.
+		inc, err := schedules.Load(ctx, env, args.DependentScheduleID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load scheduled job %d", args.DependentScheduleID)
+		}
+		incStmt, err := extractBackupStatement(inc)
+		if err != nil {
+			return err
+		}
+		for _, expr := range incStmt.Options.IncrementalStorage {
+			dest, ok := expr.(*tree.StrVal)
+			if !ok {
+				return errors.Errorf("unexpected %T incremental location in backup statement", expr)
+			}
+			incrementalStorage = append(incrementalStorage, dest.RawString())
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if args.Retention == "" {
+		return nil
+	}
+
+	retention, err := parseRetention(args.Retention)
+	if err != nil {
+		return err
+	}
+	cutoff := duration.Add(details.EndTime.GoTime(), retention.Mul(-1))
+
+	collectionURIs, err := backupCollectionURIs(details, latestSubdir)
+	if err != nil {
+		return err
+	}
+	store, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, details.CollectionURI, p.User())
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	subdirs, err := backupdest.ListFullBackupsInCollection(ctx, store)
+	if err != nil {
+		return err
+	}
+	expired := expiredBackupChains(subdirs, cutoff)
+	latest, err := backupdest.ReadLatestFile(ctx, details.CollectionURI,
+		execCfg.DistSQLSrv.ExternalStorageFromURI, p.User())
+	if err != nil {
+		return err
+	}
+
+	for _, subdir := range expired {
+		if path.Clean("/"+subdir) == path.Clean("/"+latest) ||
+			path.Clean("/"+subdir) == path.Clean("/"+latestSubdir) {
+			continue
+		}
+		owned, err := backupChainOwnedBySchedule(ctx, p, details.CollectionURI, subdir,
+			encryptionParams, details.ScheduleID)
+		if err != nil {
+			// The chain may have been taken with other encryption options, by
+			// another schedule or manually.
+			log.Infof(ctx, "not deleting expired backup %s of schedule %d: cannot read its manifest: %v",
+				subdir, details.ScheduleID, err)
+			continue
+		}
+		if !owned {
+			continue
+		}
+
+		fullURIs, incURIs, err := backupChainURIs(collectionURIs, incrementalStorage, subdir)
+		if err != nil {
+			return err
+		}
+		var inUse bool
+		if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+			inUse, err = backupChainInUse(ctx, txn, append(fullURIs, incURIs...))
+			return err
+		}); err != nil {
+			return err
+		}
+		if inUse {
+			log.Infof(ctx, "not deleting expired backup %s of schedule %d: it is in use by a running job",
+				subdir, details.ScheduleID)
+			continue
+		}
+
+		if !args.RetentionDryRun {
+			if err := deleteBackupChain(ctx, p, fullURIs, incURIs); err != nil {
+				return errors.Wrapf(err, "deleting expired backup %s", subdir)
+			}
+		}
+		if err := b.logDeleteExpiredBackup(ctx, p, details, subdir, args.RetentionDryRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backupCollectionURIs returns the URIs of the collections that the full
+// backup in the given subdir was written to, one per locality.
+func backupCollectionURIs(details jobspb.BackupDetails, subdir string) ([]string, error) {
+	collectionURIs := []string{details.CollectionURI}
+	for _, uri := range details.URIsByLocalityKV {
+		if uri == details.URI {
+			continue
+		}
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, err
+		}
+		u.Path = strings.TrimSuffix(path.Clean(u.Path), path.Clean("/"+subdir))
+		collectionURIs = append(collectionURIs, u.String())
+	}
+	return collectionURIs, nil
+}
+
+// scheduleEncryptionParams returns the encryption options that the backups of
+// a schedule are taken with, given the backup statement of the schedule.
+func scheduleEncryptionParams(backupStmt *tree.Backup) (jobspb.BackupEncryptionOptions, error) {
+	params := jobspb.BackupEncryptionOptions{Mode: jobspb.EncryptionMode_None}
+	if expr := backupStmt.Options.EncryptionPassphrase; expr != nil {
+		pw, ok := expr.(*tree.StrVal)
+		if !ok {
+			return params, errors.Errorf("unexpected %T passphrase in backup statement", expr)
+		}
+		params.Mode = jobspb.EncryptionMode_Passphrase
+		params.RawPassphrase = pw.RawString()
+	}
+	for _, expr := range backupStmt.Options.EncryptionKMSURI {
+		uri, ok := expr.(*tree.StrVal)
+		if !ok {
+			return params, errors.Errorf("unexpected %T KMS URI in backup statement", expr)
+		}
+		params.Mode = jobspb.EncryptionMode_KMS
+		params.RawKmsUris = append(params.RawKmsUris, uri.RawString())
+	}
+	return params, nil
+}
+
+// backupChainOwnedBySchedule returns true if the full backup in subdir of the
+// collection was taken by the given schedule, as recorded in its manifest.
+func backupChainOwnedBySchedule(
+	ctx context.Context,
+	p sql.JobExecContext,
+	collectionURI string,
+	subdir string,
+	encryptionParams jobspb.BackupEncryptionOptions,
+	scheduleID int64,
+) (bool, error) {
+	execCfg := p.ExecCfg()
+	uris, err := backuputils.AppendPaths([]string{collectionURI}, subdir)
+	if err != nil {
+		return false, err
+	}
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		execCfg.Settings, &execCfg.ExternalIODirConfig, execCfg.InternalDB, p.User(),
+	)
+	encryption, err := backupencryption.GetEncryptionFromBase(ctx, p.User(),
+		execCfg.DistSQLSrv.ExternalStorageFromURI, uris[0], encryptionParams, &kmsEnv)
+	if err != nil {
+		return false, err
+	}
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+	manifest, _, err := backupinfo.ReadBackupManifestFromURI(ctx, &mem, uris[0], p.User(),
+		execCfg.DistSQLSrv.ExternalStorageFromURI, encryption, &kmsEnv)
+	if err != nil {
+		return false, err
+	}
+	return manifest.ScheduleID == scheduleID, nil
+}
+
+// backupChainURIs returns the URIs of the full backup in subdir, one per
+// collection it was written to, and those of the directories its incremental
+// backups are written to.
+func backupChainURIs(
+	collectionURIs []string, incrementalStorage []string, subdir string,
+) (fullURIs []string, incURIs []string, err error) {
+	fullURIs, err = backuputils.AppendPaths(collectionURIs, subdir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(incrementalStorage) > 0 {
+		incURIs, err = backuputils.AppendPaths(incrementalStorage, subdir)
+	} else {
+		incURIs, err = backuputils.AppendPaths(collectionURIs, backupbase.DefaultIncrementalsSubdir, subdir)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return fullURIs, incURIs, nil
+}
+
+// backupChainInUse returns true if a backup or restore job that is not yet
+// finished reads or writes a backup stored under one of the given URIs of a
+// chain.
+func backupChainInUse(ctx context.Context, txn isql.Txn, chainURIs []string) (bool, error) {
+	usesChain := func(uris []string) bool {
+		for _, uri := range uris {
+			for _, chainURI := range chainURIs {
+				if uriWithin(uri, chainURI) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return jobs.RunningJobExists(ctx, jobspb.InvalidJobID, txn, func(payload *jobspb.Payload) bool {
+		switch d := payload.Details.(type) {
+		case *jobspb.Payload_Backup:
+			if d.Backup.URI != "" {
+				uris := []string{d.Backup.URI}
+				for _, uri := range d.Backup.URIsByLocalityKV {
+					uris = append(uris, uri)
+				}
+				return usesChain(uris)
+			}
+			// The destination of the backup is not resolved yet. A backup into
+			// LATEST resolves to the latest chain, which is never deleted.
+			dest := d.Backup.Destination
+			if dest.Subdir == "" || dest.Subdir == backupbase.LatestFileName {
+				return false
+			}
+			uris, err := backuputils.AppendPaths(
+				append(append([]string(nil), dest.To...), dest.IncrementalStorage...), dest.Subdir)
+			if err != nil {
+				// Err on the side of keeping the chain.
+				return true
+			}
+			return usesChain(uris)
+		case *jobspb.Payload_Restore:
+			return usesChain(d.Restore.URIs)
+		}
+		return false
+	})
+}
+
+// uriWithin returns true if uri names dir or a path under it. The query
+// parameters of the URIs, which may hold different credentials for the same
+// location, are ignored.
+func uriWithin(uri, dir string) bool {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	d, err := url.Parse(dir)
+	if err != nil {
+		return false
+	}
+	if u.Scheme != d.Scheme || u.Host != d.Host {
+		return false
+	}
+	uriPath, dirPath := path.Clean("/"+u.Path), path.Clean("/"+d.Path)
+	return uriPath == dirPath || strings.HasPrefix(uriPath, dirPath+"/")
+}
+
+// deleteBackupChain deletes the chain of a full backup, given the URIs of the
+// full backup and of its incremental backups (see backupChainURIs). The
+// incremental backups are deleted first and the manifest of the full backup
+// last, so that a deletion that is interrupted leaves a chain that is still
+// listed in the collection, and is retried the next time expired backups are
+// deleted.
+func deleteBackupChain(
+	ctx context.Context, p sql.JobExecContext, fullURIs []string, incURIs []string,
+) error {
+	for _, uri := range incURIs {
+		if err := deleteBackupFiles(ctx, p, uri, false /* keepManifest */); err != nil {
+			return err
+		}
+	}
+	for _, uri := range fullURIs {
+		if err := deleteBackupFiles(ctx, p, uri, true /* keepManifest */); err != nil {
+			return err
+		}
+	}
+	for _, uri := range fullURIs {
+		store, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, uri, p.User())
+		if err != nil {
+			return err
+		}
+		err = store.Delete(ctx, backupbase.BackupManifestName)
+		store.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteBackupFiles deletes every file stored under uri, except for the
+// manifest at its root if keepManifest is set.
+func deleteBackupFiles(ctx context.Context, p sql.JobExecContext, uri string, keepManifest bool) error {
+	store, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, uri, p.User())
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var files []string
+	if err := store.List(ctx, "", "", func(f string) error {
+		f = strings.TrimPrefix(f, "/")
+		if keepManifest && f == backupbase.BackupManifestName {
+			return nil
+		}
+		files = append(files, f)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := store.Delete(ctx, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logDeleteExpiredBackup records an event for the deletion, or the would-be
+// deletion if dryRun is set, of the expired chain of the full backup in
+// subdir.
+func (b *backupResumer) logDeleteExpiredBackup(
+	ctx context.Context, p sql.JobExecContext, details jobspb.BackupDetails, subdir string, dryRun bool,
+) error {
+	if dryRun {
+		log.Infof(ctx, "retention of schedule %d would delete expired backup %s", details.ScheduleID, subdir)
+	} else {
+		log.Infof(ctx, "retention of schedule %d deleted expired backup %s", details.ScheduleID, subdir)
+	}
+	return p.ExecCfg().InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		event := &eventpb.DeleteExpiredBackup{
+			ScheduleID:    details.ScheduleID,
+			CollectionURI: backuputils.RedactURIForErrorMessage(details.CollectionURI),
+			Subdir:        subdir,
+			DryRun:        dryRun,
+		}
+		return sql.LogEventForJobs(ctx, p.ExecCfg(), txn, event, int64(b.job.ID()),
+			b.job.Payload(), p.User(), jobs.StatusRunning)
+	})
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiredBackupChains(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	subdirs := []string{
+		"/2023/01/03-000000.00",
+		"/2023/01/01-000000.00",
+		"/my-backup",
+		"/2023/01/02-000000.00",
+	}
+	day := func(d int) time.Time { return time.Date(2023, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	for _, tc := range []struct {
+		name     string
+		cutoff   time.Time
+		expected []string
+	}{
+		{name: "before-all", cutoff: day(1).Add(-time.Hour)},
+		{name: "within-first-chain", cutoff: day(1).Add(time.Hour)},
+		{name: "at-second-chain", cutoff: day(2), expected: []string{"/2023/01/01-000000.00"}},
+		{
+			name:     "after-all",
+			cutoff:   day(10),
+			expected: []string{"/2023/01/01-000000.00", "/2023/01/02-000000.00"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, expiredBackupChains(subdirs, tc.cutoff))
+		})
+	}
+}
+
+func TestParseRetention(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	d, err := parseRetention("30d")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC),
+		duration.Add(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), d))
+
+	for _, v := range []string{"", "0s", "-1d", "forever"} {
+		_, err := parseRetention(v)
+		require.Error(t, err, v)
+	}
+}
+
+// TestScheduledBackupRetention runs a backup schedule with the retention
+// option and checks that it deletes its expired chains, but neither the chains
+// it did not take nor, in a dry run, any chain.
+func TestScheduledBackupRetention(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	th, cleanup := newTestHelper(t)
+	defer cleanup()
+
+	th.sqlDB.Exec(t, `
+CREATE DATABASE db;
+CREATE TABLE db.t (a INT);
+INSERT INTO db.t VALUES (1), (2);
+`)
+
+	// We'll be manipulating schedule time via th.env, but we can't fool actual
+	// backup when it comes to AsOf time. So, override AsOf backup clause to be
+	// the current time.
+	th.cfg.TestingKnobs.(*jobs.TestingKnobs).OverrideAsOfClause = func(clause *tree.AsOfClause, _ time.Time) {
+		expr, err := tree.MakeDTimestampTZ(th.cfg.DB.KV().Clock().PhysicalTime(), time.Microsecond)
+		require.NoError(t, err)
+		clause.Expr = expr
+	}
+
+	// runSchedule runs the schedule and waits for its n-th backup to succeed.
+	runSchedule := func(t *testing.T, scheduleID int64, n int) {
+		th.env.SetTime(th.loadSchedule(t, scheduleID).NextRun().Add(time.Second))
+		require.NoError(t, th.executeSchedules())
+		query := "SELECT count(*) FROM " + th.env.SystemJobsTableName() +
+			" WHERE status=$1 AND created_by_type=$2 AND created_by_id=$3"
+		testutils.SucceedsSoon(t, func() error {
+			th.server.JobRegistry().(*jobs.Registry).TestingNudgeAdoptionQueue()
+			var succeeded int
+			th.sqlDB.QueryRow(t, query, jobs.StatusSucceeded, jobs.CreatedByScheduledJobs, scheduleID).
+				Scan(&succeeded)
+			if succeeded < n {
+				return errors.Newf("%d of %d backups succeeded", succeeded, n)
+			}
+			return nil
+		})
+	}
+
+	// listFullBackups returns the subdirs of the full backups of the collection.
+	listFullBackups := func(t *testing.T, collectionURI string) []string {
+		var subdirs []string
+		for _, row := range th.sqlDB.QueryStr(t, `SHOW BACKUPS IN $1`, collectionURI) {
+			subdirs = append(subdirs, strings.TrimPrefix(row[0], "/"))
+		}
+		return subdirs
+	}
+
+	// listFiles returns the files of the collection under each of the given
+	// paths, relative to the collection.
+	listFiles := func(t *testing.T, collection string, paths ...string) []string {
+		var files []string
+		for _, p := range paths {
+			root := filepath.Join(th.iodir, collection)
+			err := filepath.Walk(filepath.Join(root, p), func(f string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return err
+				}
+				rel, err := filepath.Rel(root, f)
+				files = append(files, rel)
+				return err
+			})
+			if !os.IsNotExist(err) {
+				require.NoError(t, err)
+			}
+		}
+		return files
+	}
+
+	for _, dryRun := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dry-run=%t", dryRun), func(t *testing.T) {
+			collection := fmt.Sprintf("retention-%t", dryRun)
+			collectionURI := "nodelocal://1/" + collection
+
+			// A chain that was not taken by the schedule, and which must not be
+			// deleted even though it expires.
+			th.sqlDB.Exec(t, `BACKUP DATABASE db INTO $1`, collectionURI)
+
+			schedule := `CREATE SCHEDULE FOR BACKUP DATABASE db INTO $1 RECURRING '@daily' ` +
+				`FULL BACKUP ALWAYS WITH SCHEDULE OPTIONS retention = '1s'`
+			if dryRun {
+				schedule += `, retention_dry_run`
+			}
+			schedules, err := th.createBackupSchedule(t, schedule, collectionURI)
+			require.NoError(t, err)
+			require.Len(t, schedules, 1)
+			scheduleID := schedules[0].ScheduleID()
+			defer th.sqlDB.Exec(t, `DROP SCHEDULE $1`, scheduleID)
+
+			// The first chain of the schedule gets an incremental backup, which
+			// is deleted along with it.
+			runSchedule(t, scheduleID, 1)
+			th.sqlDB.Exec(t, `INSERT INTO db.t VALUES (3)`)
+			th.sqlDB.Exec(t, `BACKUP DATABASE db INTO LATEST IN $1`, collectionURI)
+			runSchedule(t, scheduleID, 2)
+			subdirs := listFullBackups(t, collectionURI)
+			require.Len(t, subdirs, 3)
+			manual, expired, kept := subdirs[0], subdirs[1], subdirs[2]
+
+			expiredFiles := listFiles(t, collection, expired, filepath.Join("incrementals", expired))
+			keptFiles := listFiles(t, collection, manual, kept)
+			require.NotEmpty(t, listFiles(t, collection, filepath.Join("incrementals", expired)))
+
+			// The chain of the first full backup of the schedule expires once the
+			// second full backup is older than the retention of the schedule.
+			time.Sleep(time.Second)
+			runSchedule(t, scheduleID, 3)
+			expectedFullBackups := []string{manual, kept}
+			if dryRun {
+				expectedFullBackups = []string{manual, expired, kept}
+			}
+			subdirs = listFullBackups(t, collectionURI)
+			require.Len(t, subdirs, len(expectedFullBackups)+1)
+			require.Equal(t, expectedFullBackups, subdirs[:len(expectedFullBackups)])
+
+			require.Subset(t, listFiles(t, collection, manual, kept), keptFiles)
+			remaining := listFiles(t, collection, expired, filepath.Join("incrementals", expired))
+			if dryRun {
+				require.ElementsMatch(t, expiredFiles, remaining)
+			} else {
+				require.Empty(t, remaining)
+			}
+		})
+	}
+}
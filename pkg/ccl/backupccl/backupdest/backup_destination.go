@@ -628,6 +628,9 @@ func ResolveBackupManifests(
 	if err != nil {
 		return nil, nil, nil, 0, err
 	}
+	// Skip the layers that were compacted into a later layer.
+	validatedDefaultURIs, validatedMainBackupManifests, validatedLocalityInfo = backupinfo.ElideSkippedLayers(
+		validatedDefaultURIs, validatedMainBackupManifests, validatedLocalityInfo)
 	return validatedDefaultURIs, validatedMainBackupManifests, validatedLocalityInfo, totalMemSize, nil
 }
 
@@ -891,6 +891,68 @@ func GetLocalityInfo(
 	return info, nil
 }
 
+// ElideSkippedLayers removes from a backup chain the incremental layers whose
+// data is also held by a layer produced by COMPACT BACKUPS. A compacted layer
+// shares its end time with the last layer it compacted, so the chain is
+// rebuilt backwards from its last layer by picking, among the layers ending
+// at the start time of the previously picked layer, the one with the earliest
+// start time. Chains in which no two layers share an end time are returned
+// as is. The uris and localityInfo slices, if non-nil, are parallel to
+// manifests and are elided accordingly.
+func ElideSkippedLayers(
+	uris []string,
+	manifests []backuppb.BackupManifest,
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+) ([]string, []backuppb.BackupManifest, []jobspb.RestoreDetails_BackupLocalityInfo) {
+	if len(manifests) < 3 {
+		return uris, manifests, localityInfo
+	}
+	endTimes := make(map[hlc.Timestamp]struct{}, len(manifests))
+	for i := range manifests {
+		endTimes[manifests[i].EndTime] = struct{}{}
+	}
+	if len(endTimes) == len(manifests) {
+		return uris, manifests, localityInfo
+	}
+
+	var keep []int
+	end := manifests[len(manifests)-1].EndTime
+	for {
+		layer := -1
+		for i := range manifests {
+			if !manifests[i].EndTime.Equal(end) {
+				continue
+			}
+			if layer == -1 || manifests[i].StartTime.Less(manifests[layer].StartTime) {
+				layer = i
+			}
+		}
+		if layer == -1 {
+			// The chain has a gap, which callers are left to report.
+			return uris, manifests, localityInfo
+		}
+		keep = append(keep, layer)
+		if layer == 0 || manifests[layer].StartTime.IsEmpty() {
+			break
+		}
+		end = manifests[layer].StartTime
+	}
+
+	elidedManifests := make([]backuppb.BackupManifest, 0, len(keep))
+	var elidedURIs []string
+	var elidedLocalityInfo []jobspb.RestoreDetails_BackupLocalityInfo
+	for i := len(keep) - 1; i >= 0; i-- {
+		elidedManifests = append(elidedManifests, manifests[keep[i]])
+		if uris != nil {
+			elidedURIs = append(elidedURIs, uris[keep[i]])
+		}
+		if localityInfo != nil {
+			elidedLocalityInfo = append(elidedLocalityInfo, localityInfo[keep[i]])
+		}
+	}
+	return elidedURIs, elidedManifests, elidedLocalityInfo
+}
+
 // ValidateEndTimeAndTruncate checks that the requested target time, if
 // specified, is valid for the list of incremental backups resolved, truncating
 // the results to the backup that contains the target time.
@@ -293,3 +293,70 @@ func mustCreateFileIterFactory(
 		return it
 	}
 }
+
+// TestElideSkippedLayers tests that layers compacted into a later layer are
+// removed from a backup chain, along with their URIs and locality info.
+func TestElideSkippedLayers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ts := func(i int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: i}
+	}
+	// makeChain returns a chain of layers with the given [start, end)
+	// intervals, naming each layer's URI after its position in the chain.
+	makeChain := func(intervals ...[2]int64) ([]string, []backuppb.BackupManifest) {
+		uris := make([]string, len(intervals))
+		manifests := make([]backuppb.BackupManifest, len(intervals))
+		for i, interval := range intervals {
+			uris[i] = fmt.Sprint(i)
+			manifests[i] = backuppb.BackupManifest{StartTime: ts(interval[0]), EndTime: ts(interval[1])}
+		}
+		return uris, manifests
+	}
+
+	for _, tc := range []struct {
+		name      string
+		intervals [][2]int64
+		expected  []string
+	}{
+		{
+			name:      "full only",
+			intervals: [][2]int64{{0, 1}},
+			expected:  []string{"0"},
+		},
+		{
+			name:      "no compaction",
+			intervals: [][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 4}},
+			expected:  []string{"0", "1", "2", "3"},
+		},
+		{
+			name:      "all incrementals compacted",
+			intervals: [][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {1, 4}},
+			expected:  []string{"0", "4"},
+		},
+		{
+			name:      "middle incrementals compacted",
+			intervals: [][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {2, 4}, {4, 5}},
+			expected:  []string{"0", "1", "4", "5"},
+		},
+		{
+			name:      "compacted layer compacted again",
+			intervals: [][2]int64{{0, 1}, {1, 2}, {2, 3}, {1, 3}, {3, 4}, {1, 4}},
+			expected:  []string{"0", "5"},
+		},
+		{
+			name:      "gap",
+			intervals: [][2]int64{{0, 1}, {2, 3}, {3, 4}, {2, 4}},
+			expected:  []string{"0", "1", "2", "3"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			uris, manifests := makeChain(tc.intervals...)
+			elidedURIs, elidedManifests, elidedLocalityInfo := backupinfo.ElideSkippedLayers(
+				uris, manifests, nil /* localityInfo */)
+			require.Equal(t, tc.expected, elidedURIs)
+			require.Len(t, elidedManifests, len(tc.expected))
+			require.Nil(t, elidedLocalityInfo)
+		})
+	}
+}
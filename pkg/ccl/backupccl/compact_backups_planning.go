@@ -0,0 +1,322 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudprivilege"
+	"github.com/cockroachdb/cockroach/pkg/featureflag"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/asof"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+func compactBackupsTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	compactStmt, ok := stmt.(*tree.CompactBackups)
+	if !ok {
+		return false, nil, nil
+	}
+	if compactStmt.Options.Detached == tree.DBoolTrue {
+		header = jobs.DetachedJobExecutionResultHeader
+	} else {
+		header = jobs.BulkJobExecutionResultHeader
+	}
+	if err := exprutil.TypeCheck(
+		ctx, "COMPACT BACKUPS", p.SemaCtx(),
+		exprutil.Strings{
+			compactStmt.From,
+			compactStmt.To,
+			compactStmt.Options.EncryptionPassphrase,
+		},
+		exprutil.StringArrays{
+			tree.Exprs(compactStmt.In),
+			tree.Exprs(compactStmt.Options.IncrementalStorage),
+			tree.Exprs(compactStmt.Options.EncryptionKMSURI),
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, header, nil
+}
+
+// compactBackupsPlanHook implements PlanHookFn.
+func compactBackupsPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	compactStmt, ok := stmt.(*tree.CompactBackups)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	if err := featureflag.CheckEnabled(
+		ctx,
+		p.ExecCfg(),
+		featureBackupEnabled,
+		"COMPACT BACKUPS",
+	); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	opts := compactStmt.Options
+	if opts.CaptureRevisionHistory != nil {
+		return nil, nil, nil, false, errors.New(
+			"revision_history is not supported by COMPACT BACKUPS; revisions are kept if every compacted backup has them")
+	}
+	if opts.ExecutionLocality != nil {
+		return nil, nil, nil, false, errors.New("execution locality is not supported by COMPACT BACKUPS")
+	}
+	if opts.IncludeAllSecondaryTenants != nil {
+		return nil, nil, nil, false, errors.New(
+			"include_all_secondary_tenants is not supported by COMPACT BACKUPS")
+	}
+	detached := opts.Detached == tree.DBoolTrue
+
+	exprEval := p.ExprEvaluator("COMPACT BACKUPS")
+	in, err := exprEval.StringArray(ctx, tree.Exprs(compactStmt.In))
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	incrementalStorage, err := exprEval.StringArray(ctx, tree.Exprs(opts.IncrementalStorage))
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	if len(in) > 1 || len(incrementalStorage) > 1 {
+		return nil, nil, nil, false, errors.New("COMPACT BACKUPS does not support locality-aware backups")
+	}
+
+	var from, to string
+	if compactStmt.From != nil {
+		if from, err = exprEval.String(ctx, compactStmt.From); err != nil {
+			return nil, nil, nil, false, err
+		}
+		if to, err = exprEval.String(ctx, compactStmt.To); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
+	encryptionParams := jobspb.BackupEncryptionOptions{
+		Mode: jobspb.EncryptionMode_None,
+	}
+	if opts.EncryptionPassphrase != nil {
+		pw, err := exprEval.String(ctx, opts.EncryptionPassphrase)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		encryptionParams.Mode = jobspb.EncryptionMode_Passphrase
+		encryptionParams.RawPassphrase = pw
+	}
+	if opts.EncryptionKMSURI != nil {
+		if encryptionParams.Mode != jobspb.EncryptionMode_None {
+			return nil, nil, nil, false,
+				errors.New("cannot have both encryption_passphrase and kms option set")
+		}
+		kms, err := exprEval.StringArray(ctx, tree.Exprs(opts.EncryptionKMSURI))
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		if err = logAndSanitizeKmsURIs(ctx, kms...); err != nil {
+			return nil, nil, nil, false, err
+		}
+		encryptionParams.Mode = jobspb.EncryptionMode_KMS
+		encryptionParams.RawKmsUris = kms
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if !(p.ExtendedEvalContext().TxnIsSingleStmt || detached) {
+			return errors.Errorf("COMPACT BACKUPS cannot be used inside a multi-statement transaction without DETACHED option")
+		}
+		if err := utilccl.CheckEnterpriseEnabled(
+			p.ExecCfg().Settings, p.ExecCfg().NodeInfo.LogicalClusterID(), "COMPACT BACKUPS",
+		); err != nil {
+			return err
+		}
+		if err := checkPrivilegesForCompactBackups(ctx, p, in); err != nil {
+			return err
+		}
+
+		var startTime, endTime hlc.Timestamp
+		if from != "" || to != "" {
+			evalCtx := &p.ExtendedEvalContext().Context
+			stmtTimestamp := evalCtx.GetStmtTimestamp()
+			if startTime, err = asof.DatumToHLC(evalCtx, stmtTimestamp, tree.NewDString(from), asof.AsOf); err != nil {
+				return errors.Wrap(err, "evaluating FROM")
+			}
+			if endTime, err = asof.DatumToHLC(evalCtx, stmtTimestamp, tree.NewDString(to), asof.AsOf); err != nil {
+				return errors.Wrap(err, "evaluating TO")
+			}
+			if !startTime.Less(endTime) {
+				return errors.Newf("FROM %s must be before TO %s", startTime, endTime)
+			}
+		}
+
+		// Resolve the chain now rather than in the job, so that a later full
+		// backup in the collection does not change which chain is compacted.
+		collectionURI, _, err := backupdest.GetURIsByLocalityKV(in, "")
+		if err != nil {
+			return err
+		}
+		subdir, err := backupdest.ReadLatestFile(ctx, collectionURI,
+			p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, p.User())
+		if err != nil {
+			return errors.Wrap(err, "reading LATEST file")
+		}
+
+		details := jobspb.BackupDetails{
+			Destination: jobspb.BackupDetails_Destination{
+				To:                 in,
+				Subdir:             "/" + strings.TrimPrefix(subdir, "/"),
+				IncrementalStorage: incrementalStorage,
+				Exists:             true,
+			},
+			StartTime:         startTime,
+			EndTime:           endTime,
+			EncryptionOptions: &encryptionParams,
+			Detached:          detached,
+			ApplicationName:   p.SessionData().ApplicationName,
+			Compact:           true,
+		}
+
+		if err := logAndSanitizeBackupDestinations(ctx, append(in, incrementalStorage...)...); err != nil {
+			return errors.Wrap(err, "logging backup destinations")
+		}
+		description, err := compactBackupsJobDescription(p, compactStmt, in, incrementalStorage, encryptionParams.RawKmsUris)
+		if err != nil {
+			return err
+		}
+
+		jobID := p.ExecCfg().JobRegistry.MakeJobID()
+		jr := jobs.Record{
+			Description: description,
+			Details:     details,
+			Progress:    jobspb.BackupProgress{},
+			Username:    p.User(),
+		}
+		if detached {
+			if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+				ctx, jr, jobID, p.InternalSQLTxn(),
+			); err != nil {
+				return err
+			}
+			resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+			return nil
+		}
+
+		var sj *jobs.StartableJob
+		if err := func() (err error) {
+			defer func() {
+				if err == nil || sj == nil {
+					return
+				}
+				if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
+					log.Errorf(ctx, "failed to cleanup job: %v", cleanupErr)
+				}
+			}()
+			if err := p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(
+				ctx, &sj, jobID, p.InternalSQLTxn(), jr,
+			); err != nil {
+				return err
+			}
+			// We commit the transaction here so that the job can be started. This
+			// is safe because we're in an implicit transaction.
+			return p.Txn().Commit(ctx)
+		}(); err != nil {
+			return err
+		}
+		p.InternalSQLTxn().Descriptors().ReleaseAll(ctx)
+		if err := sj.Start(ctx); err != nil {
+			return err
+		}
+		if err := sj.AwaitCompletion(ctx); err != nil {
+			return err
+		}
+		return sj.ReportExecutionResults(ctx, resultsCh)
+	}
+
+	if detached {
+		return fn, jobs.DetachedJobExecutionResultHeader, nil, false, nil
+	}
+	return fn, jobs.BulkJobExecutionResultHeader, nil, false, nil
+}
+
+// checkPrivilegesForCompactBackups checks that the user may both back up the
+// cluster and access the collection, as compacting a chain reads and writes
+// backups of whatever the chain contains.
+func checkPrivilegesForCompactBackups(ctx context.Context, p sql.PlanHookState, in []string) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if hasAdmin {
+		return nil
+	}
+	if err := p.CheckPrivilegeForUser(
+		ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.BACKUP, p.User(),
+	); err != nil {
+		return pgerror.Wrapf(
+			err,
+			pgcode.InsufficientPrivilege,
+			"only users with the admin role or the BACKUP system privilege are allowed to compact backups")
+	}
+	return cloudprivilege.CheckDestinationPrivileges(ctx, p, in)
+}
+
+// compactBackupsJobDescription returns the statement with all the secret
+// information redacted.
+func compactBackupsJobDescription(
+	p sql.PlanHookState,
+	compactStmt *tree.CompactBackups,
+	in []string,
+	incrementalStorage []string,
+	kmsURIs []string,
+) (string, error) {
+	c := &tree.CompactBackups{
+		From: compactStmt.From,
+		To:   compactStmt.To,
+	}
+	sanitizedIn, err := sanitizeURIList(in)
+	if err != nil {
+		return "", err
+	}
+	c.In = tree.StringOrPlaceholderOptList(sanitizedIn)
+	c.Options, err = resolveOptionsForBackupJobDescription(compactStmt.Options, kmsURIs, incrementalStorage)
+	if err != nil {
+		return "", err
+	}
+	ann := p.ExtendedEvalContext().Annotations
+	return tree.AsStringWithFQNames(c, ann), nil
+}
+
+func init() {
+	sql.AddPlanHook(
+		"backupccl.compactBackupsPlanHook",
+		compactBackupsPlanHook,
+		compactBackupsTypeCheck,
+	)
+}
@@ -11,6 +11,7 @@ package backupccl
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
@@ -38,19 +39,25 @@ import (
 )
 
 const (
-	optFirstRun                = "first_run"
-	optOnExecFailure           = "on_execution_failure"
-	optOnPreviousRunning       = "on_previous_running"
-	optIgnoreExistingBackups   = "ignore_existing_backups"
-	optUpdatesLastBackupMetric = "updates_cluster_last_backup_time_metric"
+	optFirstRun                 = "first_run"
+	optOnExecFailure            = "on_execution_failure"
+	optOnPreviousRunning        = "on_previous_running"
+	optIgnoreExistingBackups    = "ignore_existing_backups"
+	optUpdatesLastBackupMetric  = "updates_cluster_last_backup_time_metric"
+	optCompactAfterIncrementals = "compact_after_incrementals"
+	optRetention                = "retention"
+	optRetentionDryRun          = "retention_dry_run"
 )
 
 var scheduledBackupOptionExpectValues = map[string]exprutil.KVStringOptValidate{
-	optFirstRun:                exprutil.KVStringOptRequireValue,
-	optOnExecFailure:           exprutil.KVStringOptRequireValue,
-	optOnPreviousRunning:       exprutil.KVStringOptRequireValue,
-	optIgnoreExistingBackups:   exprutil.KVStringOptRequireNoValue,
-	optUpdatesLastBackupMetric: exprutil.KVStringOptRequireNoValue,
+	optFirstRun:                 exprutil.KVStringOptRequireValue,
+	optOnExecFailure:            exprutil.KVStringOptRequireValue,
+	optOnPreviousRunning:        exprutil.KVStringOptRequireValue,
+	optIgnoreExistingBackups:    exprutil.KVStringOptRequireNoValue,
+	optUpdatesLastBackupMetric:  exprutil.KVStringOptRequireNoValue,
+	optCompactAfterIncrementals: exprutil.KVStringOptRequireValue,
+	optRetention:                exprutil.KVStringOptRequireValue,
+	optRetentionDryRun:          exprutil.KVStringOptRequireNoValue,
 }
 
 // scheduledBackupGCProtectionEnabled is used to enable and disable the chaining
@@ -86,6 +93,7 @@ type scheduledBackupSpec struct {
 	incrementalStorage         []string
 	includeAllSecondaryTenants *bool
 	execLoc                    *string
+	fingerprints               bool
 }
 
 func makeScheduleDetails(opts map[string]string) (jobspb.ScheduleDetails, error) {
@@ -115,6 +123,39 @@ func scheduleFirstRun(evalCtx *eval.Context, opts map[string]string) (*time.Time
 	return nil, nil
 }
 
+// scheduleCompactAfterIncrementals returns the number of incremental backups
+// after which the incremental schedule compacts the latest chain, or 0 if it
+// never does.
+func scheduleCompactAfterIncrementals(opts map[string]string) (int64, error) {
+	v, ok := opts[optCompactAfterIncrementals]
+	if !ok {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 1 {
+		return 0, errors.Newf("%s must be a positive integer, got %q", optCompactAfterIncrementals, v)
+	}
+	return n, nil
+}
+
+// scheduleRetention returns the interval for which the full schedule keeps
+// the backups it takes, or an empty string if it keeps them forever, and
+// whether expired backups are only reported instead of deleted.
+func scheduleRetention(opts map[string]string) (string, bool, error) {
+	_, dryRun := opts[optRetentionDryRun]
+	v, ok := opts[optRetention]
+	if !ok {
+		if dryRun {
+			return "", false, errors.Newf("%s requires the %s option", optRetentionDryRun, optRetention)
+		}
+		return "", false, nil
+	}
+	if _, err := parseRetention(v); err != nil {
+		return "", false, err
+	}
+	return v, dryRun, nil
+}
+
 func frequencyFromCron(now time.Time, cronStr string) (time.Duration, error) {
 	expr, err := cron.ParseStandard(cronStr)
 	if err != nil {
@@ -239,6 +280,8 @@ func doCreateBackupSchedules(
 		backupNode.Options.ExecutionLocality = tree.NewStrVal(*eval.execLoc)
 	}
 
+	backupNode.Options.Fingerprints = eval.fingerprints
+
 	// Evaluate encryption KMS URIs if set.
 	// Only one of encryption passphrase and KMS URI should be set, but this check
 	// is done during backup planning so we do not need to worry about it here.
@@ -302,6 +345,20 @@ func doCreateBackupSchedules(
 		return err
 	}
 
+	compactAfterIncrementals, err := scheduleCompactAfterIncrementals(scheduleOptions)
+	if err != nil {
+		return err
+	}
+	if compactAfterIncrementals > 0 && incRecurrence == nil {
+		return errors.Newf("%s requires a schedule that takes incremental backups",
+			optCompactAfterIncrementals)
+	}
+
+	retention, retentionDryRun, err := scheduleRetention(scheduleOptions)
+	if err != nil {
+		return err
+	}
+
 	unpauseOnSuccessID := jobs.InvalidScheduleID
 
 	var chainProtectedTimestampRecords bool
@@ -366,6 +423,15 @@ func doCreateBackupSchedules(
 	if err != nil {
 		return err
 	}
+	if retention != "" {
+		fullScheduledBackupArgs.Retention = retention
+		fullScheduledBackupArgs.RetentionDryRun = retentionDryRun
+		any, err := pbtypes.MarshalAny(fullScheduledBackupArgs)
+		if err != nil {
+			return errors.Wrap(err, "marshaling args")
+		}
+		full.SetExecutionDetails(full.ExecutorType(), jobspb.ExecutionArguments{Args: any})
+	}
 
 	if firstRun != nil {
 		full.SetNextRun(*firstRun)
@@ -386,6 +452,7 @@ func doCreateBackupSchedules(
 	// If schedule creation has resulted in a full and incremental schedule then
 	// we update both the schedules with the ID of the other "dependent" schedule.
 	if incRecurrence != nil {
+		incScheduledBackupArgs.CompactAfterIncrementals = compactAfterIncrementals
 		if err := setDependentSchedule(
 			ctx, scheduledJobs, fullScheduledBackupArgs, full, inc.ScheduleID(),
 		); err != nil {
@@ -712,6 +779,7 @@ func makeScheduledBackupSpec(
 		}
 		spec.includeAllSecondaryTenants = &includeSecondary
 	}
+	spec.fingerprints = schedule.BackupOptions.Fingerprints
 
 	return spec, nil
 }
@@ -673,6 +673,16 @@ func TestSerializesScheduledBackupExecutionArgs(t *testing.T) {
 			query:  `CREATE SCHEDULE FOR BACKUP INTO 'foo' WITH encryption_passphrase=$1 RECURRING '@hourly'`,
 			errMsg: "failed to evaluate backup encryption_passphrase",
 		},
+		{
+			name:   "non-positive-retention",
+			query:  `CREATE SCHEDULE FOR BACKUP INTO 'foo' RECURRING '@hourly' WITH SCHEDULE OPTIONS retention = '-1d'`,
+			errMsg: "retention must be a positive interval",
+		},
+		{
+			name:   "retention-dry-run-without-retention",
+			query:  `CREATE SCHEDULE FOR BACKUP INTO 'foo' RECURRING '@hourly' WITH SCHEDULE OPTIONS retention_dry_run`,
+			errMsg: "retention_dry_run requires the retention option",
+		},
 	}
 
 	for i, tc := range testCases {
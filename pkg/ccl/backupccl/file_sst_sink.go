@@ -46,6 +46,9 @@ type fileSSTSink struct {
 	cancel  func()
 	out     io.WriteCloser
 	outName string
+	// outSize counts the bytes written to the file at outName, after they are
+	// encrypted.
+	outSize *countingWriteCloser
 
 	flushedFiles    []backuppb.BackupManifest_File
 	flushedSize     int64
@@ -96,8 +99,12 @@ func (s *fileSSTSink) flushFile(ctx context.Context) error {
 		log.Warningf(ctx, "failed to close write in fileSSTSink: % #v", pretty.Formatter(err))
 		return errors.Wrap(err, "writing SST")
 	}
+	for i := range s.flushedFiles {
+		s.flushedFiles[i].FileSize = s.outSize.n
+	}
 	s.outName = ""
 	s.out = nil
+	s.outSize = nil
 
 	progDetails := backuppb.BackupManifest_Progress{
 		RevStartTime:   s.flushedRevStart,
@@ -133,6 +140,8 @@ func (s *fileSSTSink) open(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	s.outSize = &countingWriteCloser{WriteCloser: w}
+	w = s.outSize
 	if s.conf.enc != nil {
 		var err error
 		w, err = storageccl.EncryptingWriter(w, s.conf.enc.Key)
@@ -195,6 +204,8 @@ func (s *fileSSTSink) write(ctx context.Context, resp exportedSpan) error {
 		s.flushedFiles[l].StartTime.EqOrdering(resp.metadata.StartTime) {
 		s.flushedFiles[l].Span.EndKey = span.EndKey
 		s.flushedFiles[l].EntryCounts.Add(resp.metadata.EntryCounts)
+		s.flushedFiles[l].TableFingerprints = mergeFileFingerprints(
+			s.flushedFiles[l].TableFingerprints, resp.metadata.TableFingerprints)
 		s.stats.spanGrows++
 	} else {
 		f := resp.metadata
@@ -284,6 +295,18 @@ func (s *fileSSTSink) copyRangeKeys(dataSST []byte) error {
 	return nil
 }
 
+// countingWriteCloser counts the bytes written to the wrapped io.WriteCloser.
+type countingWriteCloser struct {
+	io.WriteCloser
+	n int64
+}
+
+func (w *countingWriteCloser) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
 func generateUniqueSSTName(nodeID base.SQLInstanceID) string {
 	// The data/ prefix, including a /, is intended to group SSTs in most of the
 	// common file/bucket browse UIs.
@@ -480,6 +480,14 @@ func loadBackupSQLDescs(
 		return nil, backuppb.BackupManifest{}, nil, 0, err
 	}
 
+	if f := details.RowFilter; f != nil {
+		for _, desc := range sqlDescs {
+			if table, ok := desc.(*tabledesc.Mutable); ok && table.GetID() == f.TableID {
+				applyRowFilterToTable(table, f)
+			}
+		}
+	}
+
 	return backupManifests, latestBackupManifest, sqlDescs, sz, nil
 }
 
@@ -835,6 +843,10 @@ func createImportingDescriptors(
 	// that is, in the 'old' keyspace, before we reassign the table IDs.
 	preRestoreSpans := spansForAllRestoreTableIndexes(backupCodec, preRestoreTables, nil, details.SchemaOnly)
 	postRestoreSpans := spansForAllRestoreTableIndexes(backupCodec, postRestoreTables, nil, details.SchemaOnly)
+	if details.RowFilter != nil {
+		// Only the rows matching the filter are restored.
+		postRestoreSpans = details.RowFilter.Spans
+	}
 	var verifySpans []roachpb.Span
 	if details.VerifyData {
 		// verifySpans contains the spans that should be read and checksum'd during a
@@ -1565,8 +1577,12 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 	backupStats, err := backupinfo.GetStatisticsFromBackup(ctx, defaultStore, details.Encryption,
 		&kmsEnv, latestBackupManifest)
 	if err == nil {
-		remappedStats = remapAndFilterRelevantStatistics(ctx, backupStats, details.DescriptorRewrites,
-			details.TableDescs)
+		// The statistics of a table whose rows are filtered describe rows that
+		// are not restored, so they are not restored either.
+		if details.RowFilter == nil {
+			remappedStats = remapAndFilterRelevantStatistics(ctx, backupStats, details.DescriptorRewrites,
+				details.TableDescs)
+		}
 	} else {
 		// We don't want to fail the restore if we are unable to resolve statistics
 		// from the backup, since they can be recomputed after the restore has
@@ -1734,6 +1750,20 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 		devalidateIndexes = bad
 	}
 
+	if details.VerifyFingerprints {
+		if err := r.job.NoTxn().RunningStatus(ctx, func(_ context.Context, _ jobspb.Details) (jobs.RunningStatus, error) {
+			return jobs.RunningStatus("verifying fingerprints of restored tables"), nil
+		}); err != nil {
+			return errors.Wrapf(err, "failed to update running status of job %d", errors.Safe(r.job.ID()))
+		}
+		if err := verifyTableFingerprints(
+			ctx, p.ExecCfg(), details.TableDescs, details.DescriptorRewrites,
+			backupManifests[lastBackupIndex].TableFingerprints, p.ExecCfg().Clock.Now(),
+		); err != nil {
+			return err
+		}
+	}
+
 	publishDescriptors := func(ctx context.Context, txn descs.Txn) (err error) {
 		return r.publishDescriptors(
 			ctx, p.ExecCfg().JobRegistry, p.ExecCfg().JobsKnobs(), txn, p.User(),
@@ -921,6 +921,7 @@ func resolveOptionsForRestoreJobDescription(
 		SchemaOnly:                       opts.SchemaOnly,
 		VerifyData:                       opts.VerifyData,
 		UnsafeRestoreIncompatibleVersion: opts.UnsafeRestoreIncompatibleVersion,
+		VerifyFingerprints:               opts.VerifyFingerprints,
 	}
 
 	if opts.EncryptionPassphrase != nil {
@@ -974,6 +975,8 @@ func restoreJobDescription(
 		AsOf:               restore.AsOf,
 		Targets:            restore.Targets,
 		From:               make([]tree.StringOrPlaceholderOptList, len(restore.From)),
+		RowFilter:          restore.RowFilter,
+		IntoTable:          restore.IntoTable,
 	}
 
 	var options tree.RestoreOptions
@@ -1067,6 +1070,21 @@ func restorePlanHook(
 			errors.New("to set the verify_backup_table_data option, the schema_only option must be set")
 	}
 
+	if restoreStmt.RowFilter != nil {
+		if err := checkRowRestoreSupported(restoreStmt); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
+	if restoreStmt.Options.VerifyFingerprints {
+		if err := checkVerifyFingerprintsSupported(restoreStmt); err != nil {
+			return nil, nil, nil, false, err
+		}
+		if err := checkFingerprintsSupported(ctx, p.ExecCfg()); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
 	exprEval := p.ExprEvaluator("RESTORE")
 
 	from := make([][]string, len(restoreStmt.From))
@@ -1665,6 +1683,12 @@ func doRestorePlan(
 		return err
 	}
 
+	if restoreStmt.Options.VerifyFingerprints {
+		if err := checkBackupHasFingerprints(mainBackupManifests, endTime); err != nil {
+			return err
+		}
+	}
+
 	if restoreStmt.DescriptorCoverage == tree.AllDescriptors {
 		// Validate that the backup is a full cluster backup if a full cluster restore was requested.
 		if mainBackupManifests[0].DescriptorCoverage == tree.RequestedDescriptors {
@@ -1784,6 +1808,23 @@ func doRestorePlan(
 		}
 	}
 
+	var rowFilter *jobspb.RestoreDetails_RowFilter
+	if restoreStmt.RowFilter != nil {
+		rowFilter, err = planRestoreRowFilter(ctx, p, restoreStmt, sqlDescs, descsByTablePattern, backupCodec)
+		if err != nil {
+			return err
+		}
+		// The secondary indexes of the filtered table are not restored, so
+		// none of them needs revalidating.
+		filtered := revalidateIndexes[:0]
+		for _, idx := range revalidateIndexes {
+			if idx.TableID != rowFilter.TableID {
+				filtered = append(filtered, idx)
+			}
+		}
+		revalidateIndexes = filtered
+	}
+
 	var oldTenantID *roachpb.TenantID
 	if len(tenants) > 0 {
 		if !p.ExecCfg().Codec.ForSystemTenant() {
@@ -2003,6 +2044,8 @@ func doRestorePlan(
 		PreRewriteTenantId: oldTenantID,
 		SchemaOnly:         restoreStmt.Options.SchemaOnly,
 		VerifyData:         restoreStmt.Options.VerifyData,
+		RowFilter:          rowFilter,
+		VerifyFingerprints: restoreStmt.Options.VerifyFingerprints,
 	}
 
 	jr := jobs.Record{
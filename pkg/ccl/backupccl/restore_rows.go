@@ -0,0 +1,660 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// checkRowRestoreSupported validates a RESTORE with a row filter, which
+// restores the rows of a single table that match a predicate on its primary
+// key into a new table.
+func checkRowRestoreSupported(restoreStmt *tree.Restore) error {
+	targets := restoreStmt.Targets
+	if restoreStmt.DescriptorCoverage != tree.RequestedDescriptors ||
+		len(targets.Databases) > 0 || len(targets.Tables.TablePatterns) != 1 {
+		return errors.New("a row filter can only be used to restore the rows of a single table")
+	}
+	opts := restoreStmt.Options
+	switch {
+	case opts.NewDBName != nil:
+		return errors.New("a row filter cannot be used with the new_db_name option")
+	case opts.SchemaOnly:
+		return errors.New("a row filter cannot be used with the schema_only option")
+	}
+	return nil
+}
+
+// planRestoreRowFilter resolves the table whose rows a row filtered RESTORE
+// restores, computes the spans of its primary index that hold the rows
+// matching the filter, and turns its descriptor into the descriptor of the new
+// table that receives them.
+func planRestoreRowFilter(
+	ctx context.Context,
+	p sql.PlanHookState,
+	restoreStmt *tree.Restore,
+	sqlDescs []catalog.Descriptor,
+	descsByTablePattern map[tree.TablePattern]catalog.Descriptor,
+	backupCodec keys.SQLCodec,
+) (*jobspb.RestoreDetails_RowFilter, error) {
+	pattern := restoreStmt.Targets.Tables.TablePatterns[0]
+	desc, ok := descsByTablePattern[pattern]
+	if !ok {
+		return nil, errors.AssertionFailedf("table pattern %s was not resolved", pattern)
+	}
+	if _, ok := desc.(catalog.TableDescriptor); !ok {
+		return nil, errors.New("a row filter can only be used to restore the rows of a single table")
+	}
+	var table *tabledesc.Mutable
+	for _, d := range sqlDescs {
+		if t, ok := d.(*tabledesc.Mutable); ok && t.GetID() == desc.GetID() {
+			table = t
+			break
+		}
+	}
+	if table == nil {
+		return nil, errors.AssertionFailedf("table %d is not being restored", desc.GetID())
+	}
+	if !table.IsPhysicalTable() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"cannot restore the rows of %q, which is not a table", table.GetName())
+	}
+
+	if err := checkRowFilterTableSupported(table); err != nil {
+		return nil, err
+	}
+
+	spans, err := rowFilterSpans(
+		ctx, p.SemaCtx(), &p.ExtendedEvalContext().Context, backupCodec, table, restoreStmt.RowFilter,
+	)
+	if err != nil {
+		return nil, err
+	}
+	filter := &jobspb.RestoreDetails_RowFilter{
+		TableID:   table.GetID(),
+		Spans:     spans,
+		TableName: string(restoreStmt.IntoTable),
+	}
+	applyRowFilterToTable(table, filter)
+	return filter, nil
+}
+
+// checkRowFilterTableSupported returns an error if the rows of table cannot be
+// restored by a row filtered RESTORE. Only the spans of the primary index that
+// hold the matching rows are restored, so the table cannot have secondary
+// indexes, whose entries for these rows are spread over the whole index. Its
+// foreign keys are restored as for any table, except for those that reference
+// the table itself, which the rows that are not restored could violate.
+func checkRowFilterTableSupported(table catalog.TableDescriptor) error {
+	if len(table.AllIndexes()) > 1 {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot restore the rows of %q with a row filter: it has secondary indexes, "+
+				"which cannot be restored for a subset of its rows", table.GetName())
+	}
+	for _, fk := range table.OutboundForeignKeys() {
+		if fk.GetReferencedTableID() == table.GetID() {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot restore the rows of %q with a row filter: its foreign key %q references "+
+					"the table itself, and could be violated by the restored rows", table.GetName(), fk.GetName())
+		}
+	}
+	return nil
+}
+
+// applyRowFilterToTable turns the descriptor of the table whose rows a row
+// filtered RESTORE restores into the descriptor of the new table that receives
+// them.
+func applyRowFilterToTable(table *tabledesc.Mutable, filter *jobspb.RestoreDetails_RowFilter) {
+	table.SetName(filter.TableName)
+}
+
+// maxRowFilterConjunctions bounds the number of conjunctions of comparisons
+// that a row filter expands to, each of which becomes a span.
+const maxRowFilterConjunctions = 1024
+
+// rowFilterSpans returns the spans of the primary index of table, in the
+// keyspace of codec, that hold the rows matching filter. The filter must only
+// compare primary key columns to constant expressions, using =, <, <=, >, >=,
+// IN and BETWEEN, combined with AND and OR, such that each conjunction of
+// comparisons constrains a prefix of the primary key columns to single values,
+// except possibly for the last column of the prefix which can be constrained
+// to a range of values.
+func rowFilterSpans(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *eval.Context,
+	codec keys.SQLCodec,
+	table catalog.TableDescriptor,
+	filter tree.Expr,
+) ([]roachpb.Span, error) {
+	idx := table.GetPrimaryIndex()
+	b := rowFilterSpanBuilder{
+		semaCtx: semaCtx,
+		evalCtx: evalCtx,
+		prefix:  roachpb.Key(rowenc.MakeIndexKeyPrefix(codec, table.GetID(), idx.GetID())),
+	}
+	for i := 0; i < idx.NumKeyColumns(); i++ {
+		col, err := catalog.MustFindColumnByID(table, idx.GetKeyColumnID(i))
+		if err != nil {
+			return nil, err
+		}
+		dir, err := catalogkeys.IndexColumnEncodingDirection(idx.GetKeyColumnDirection(i))
+		if err != nil {
+			return nil, err
+		}
+		b.cols = append(b.cols, col)
+		b.dirs = append(b.dirs, dir)
+	}
+
+	conjunctions, err := b.conjunctions(filter)
+	if err != nil {
+		return nil, err
+	}
+	var spans []roachpb.Span
+	for _, c := range conjunctions {
+		sp, err := b.conjunctionSpan(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		if sp.Valid() {
+			spans = append(spans, sp)
+		}
+	}
+	spans, _ = roachpb.MergeSpans(&spans)
+	return spans, nil
+}
+
+// rowFilterComparison is a comparison of the primary key column at position
+// col of the primary index with a constant expression.
+type rowFilterComparison struct {
+	col int
+	op  treecmp.ComparisonOperatorSymbol
+	val tree.Expr
+}
+
+type rowFilterSpanBuilder struct {
+	semaCtx *tree.SemaContext
+	evalCtx *eval.Context
+	// cols are the key columns of the primary index and dirs their directions.
+	cols []catalog.Column
+	dirs []encoding.Direction
+	// prefix is the prefix of the keys of the primary index.
+	prefix roachpb.Key
+}
+
+// conjunctions returns the comparisons of expr in disjunctive normal form: the
+// rows matching expr are those that match all the comparisons of any of the
+// returned conjunctions.
+func (b *rowFilterSpanBuilder) conjunctions(expr tree.Expr) ([][]rowFilterComparison, error) {
+	switch e := expr.(type) {
+	case *tree.ParenExpr:
+		return b.conjunctions(e.Expr)
+
+	case *tree.AndExpr:
+		left, err := b.conjunctions(e.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := b.conjunctions(e.Right)
+		if err != nil {
+			return nil, err
+		}
+		if len(left)*len(right) > maxRowFilterConjunctions {
+			return nil, pgerror.Newf(pgcode.ProgramLimitExceeded,
+				"row filter %s expands to more than %d conjunctions", tree.AsString(expr),
+				maxRowFilterConjunctions)
+		}
+		res := make([][]rowFilterComparison, 0, len(left)*len(right))
+		for _, l := range left {
+			for _, r := range right {
+				res = append(res, append(append([]rowFilterComparison(nil), l...), r...))
+			}
+		}
+		return res, nil
+
+	case *tree.OrExpr:
+		left, err := b.conjunctions(e.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := b.conjunctions(e.Right)
+		if err != nil {
+			return nil, err
+		}
+		if len(left)+len(right) > maxRowFilterConjunctions {
+			return nil, pgerror.Newf(pgcode.ProgramLimitExceeded,
+				"row filter %s expands to more than %d conjunctions", tree.AsString(expr),
+				maxRowFilterConjunctions)
+		}
+		return append(left, right...), nil
+
+	case *tree.RangeCond:
+		col, ok := b.column(e.Left)
+		if e.Not || e.Symmetric || !ok {
+			break
+		}
+		return [][]rowFilterComparison{{
+			{col: col, op: treecmp.GE, val: e.From},
+			{col: col, op: treecmp.LE, val: e.To},
+		}}, nil
+
+	case *tree.ComparisonExpr:
+		op, val := e.Operator.Symbol, e.Right
+		col, ok := b.column(e.Left)
+		if !ok && op != treecmp.In {
+			if col, ok = b.column(e.Right); !ok {
+				break
+			}
+			// Put the column on the left of the comparison.
+			op, val = flipComparison(op), e.Left
+		} else if !ok {
+			break
+		}
+		switch op {
+		case treecmp.EQ, treecmp.LT, treecmp.LE, treecmp.GT, treecmp.GE:
+			return [][]rowFilterComparison{{{col: col, op: op, val: val}}}, nil
+		case treecmp.In:
+			tuple, ok := val.(*tree.Tuple)
+			if !ok {
+				break
+			}
+			if len(tuple.Exprs) > maxRowFilterConjunctions {
+				return nil, pgerror.Newf(pgcode.ProgramLimitExceeded,
+					"row filter %s expands to more than %d conjunctions", tree.AsString(expr),
+					maxRowFilterConjunctions)
+			}
+			res := make([][]rowFilterComparison, len(tuple.Exprs))
+			for i, v := range tuple.Exprs {
+				res[i] = []rowFilterComparison{{col: col, op: treecmp.EQ, val: v}}
+			}
+			return res, nil
+		}
+	}
+	return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+		"unsupported row filter %s: expected comparisons of primary key columns "+
+			"with constant values, combined with AND and OR", tree.AsString(expr))
+}
+
+// column returns the position in the primary index of the primary key column
+// that expr names, if any.
+func (b *rowFilterSpanBuilder) column(expr tree.Expr) (int, bool) {
+	n, ok := expr.(*tree.UnresolvedName)
+	if !ok || n.Star || n.NumParts != 1 {
+		return 0, false
+	}
+	for i, col := range b.cols {
+		if n.Parts[0] == col.GetName() {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// conjunctionSpan returns the span of the primary index holding the rows that
+// match all the comparisons of a conjunction, which is empty if no row matches
+// them. The key of a row is the concatenation of the encodings of its primary
+// key columns, so the rows can only be restricted to a span by constraining a
+// prefix of the columns to single values, followed by at most one column
+// constrained to a range of values.
+func (b *rowFilterSpanBuilder) conjunctionSpan(
+	ctx context.Context, conjunction []rowFilterComparison,
+) (roachpb.Span, error) {
+	byCol := make([][]rowFilterComparison, len(b.cols))
+	for _, c := range conjunction {
+		byCol[c.col] = append(byCol[c.col], c)
+	}
+	// unconstrainedAfter returns an error if any column after col is
+	// constrained, since the rows matching the conjunction are then not
+	// contiguous in the primary index.
+	unconstrainedAfter := func(col int) error {
+		for i := col + 1; i < len(b.cols); i++ {
+			if len(byCol[i]) > 0 {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"unsupported row filter: primary key column %q can only be compared to a "+
+						"range of values if the columns before it are compared to single values",
+					b.cols[i].GetName())
+			}
+		}
+		return nil
+	}
+
+	prefix := b.prefix
+	for i := range b.cols {
+		sp := roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+		if len(byCol[i]) == 0 {
+			return sp, unconstrainedAfter(i)
+		}
+		var eqKey roachpb.Key
+		for _, c := range byCol[i] {
+			cmpSpan, key, err := b.comparisonSpan(ctx, prefix, c)
+			if err != nil {
+				return roachpb.Span{}, err
+			}
+			if !cmpSpan.Valid() || !sp.Overlaps(cmpSpan) {
+				// No row matches the conjunction.
+				return roachpb.Span{}, unconstrainedAfter(i)
+			}
+			sp = sp.Intersect(cmpSpan)
+			if c.op == treecmp.EQ {
+				eqKey = key
+			}
+		}
+		if eqKey == nil {
+			return sp, unconstrainedAfter(i)
+		}
+		// The column is equal to a single value, so the next column can be
+		// constrained in turn.
+		prefix = eqKey
+	}
+	return roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}, nil
+}
+
+// comparisonSpan returns the span, within the span of the keys with the given
+// prefix, holding the keys whose next column compares to the constant value
+// of c, and the key made of the prefix followed by the encoding of that value.
+// The returned span is empty if the value is NULL, which primary key columns
+// never are.
+func (b *rowFilterSpanBuilder) comparisonSpan(
+	ctx context.Context, prefix roachpb.Key, c rowFilterComparison,
+) (roachpb.Span, roachpb.Key, error) {
+	col, dir := b.cols[c.col], b.dirs[c.col]
+	typedExpr, err := schemaexpr.SanitizeVarFreeExpr(
+		ctx, c.val, col.GetType(), "RESTORE row filter", b.semaCtx, volatility.Stable,
+		true, /* allowAssignmentCast */
+	)
+	if err != nil {
+		return roachpb.Span{}, nil, err
+	}
+	d, err := eval.Expr(ctx, b.evalCtx, typedExpr)
+	if err != nil {
+		return roachpb.Span{}, nil, err
+	}
+	if d == tree.DNull {
+		return roachpb.Span{}, nil, nil
+	}
+	key, err := keyside.Encode(append(roachpb.Key(nil), prefix...), d, dir)
+	if err != nil {
+		return roachpb.Span{}, nil, err
+	}
+	k := roachpb.Key(key)
+
+	op := c.op
+	if dir == encoding.Descending {
+		// The keys of a descending column sort in the reverse order of its values.
+		op = flipComparison(op)
+	}
+	// All the keys of a row, one per column family, have the encoding of its
+	// primary key as a prefix.
+	switch op {
+	case treecmp.EQ:
+		return roachpb.Span{Key: k, EndKey: k.PrefixEnd()}, k, nil
+	case treecmp.LT:
+		return roachpb.Span{Key: prefix, EndKey: k}, k, nil
+	case treecmp.LE:
+		return roachpb.Span{Key: prefix, EndKey: k.PrefixEnd()}, k, nil
+	case treecmp.GT:
+		return roachpb.Span{Key: k.PrefixEnd(), EndKey: prefix.PrefixEnd()}, k, nil
+	case treecmp.GE:
+		return roachpb.Span{Key: k, EndKey: prefix.PrefixEnd()}, k, nil
+	default:
+		return roachpb.Span{}, nil, errors.AssertionFailedf("unexpected comparison operator %s", op)
+	}
+}
+
+// flipComparison returns the operator op' such that a op b is equivalent to
+// b op' a.
+func flipComparison(op treecmp.ComparisonOperatorSymbol) treecmp.ComparisonOperatorSymbol {
+	switch op {
+	case treecmp.LT:
+		return treecmp.GT
+	case treecmp.LE:
+		return treecmp.GE
+	case treecmp.GT:
+		return treecmp.LT
+	case treecmp.GE:
+		return treecmp.LE
+	default:
+		return op
+	}
+}
+
+// readBackupRows returns the rows of the primary index of table in the given
+// spans of the backup described by info, as of info.endTime, or the end of the
+// backup if it is empty. Each row is returned as a JSON object keyed by column
+// name. The KVs read from the backup and the returned rows are accounted for
+// in mem, so that the read fails rather than exhausting memory if the spans
+// hold too many rows.
+func readBackupRows(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	mem *mon.BoundAccount,
+	info backupInfo,
+	codec keys.SQLCodec,
+	table catalog.TableDescriptor,
+	spans []roachpb.Span,
+) ([]tree.Datums, error) {
+	backupLocalityMap, err := makeBackupLocalityMap(info.localityInfo, user)
+	if err != nil {
+		return nil, err
+	}
+	introducedSpanFrontier, err := createIntroducedSpanFrontier(info.manifests, info.endTime)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := makeSpanCoveringFilter(
+		nil, /* checkpointFrontier */
+		nil, /* highWater */
+		introducedSpanFrontier,
+		targetRestoreSpanSize.Get(&execCfg.Settings.SV),
+		false, /* useFrontierCheckpointing */
+	)
+	if err != nil {
+		return nil, err
+	}
+	cover, err := makeSimpleImportSpans(
+		ctx, spans, info.manifests, info.layerToIterFactory, backupLocalityMap, filter,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileEncryption *kvpb.FileEncryptionOptions
+	if info.enc != nil {
+		key, err := backupencryption.GetEncryptionKey(ctx, info.enc, info.kmsEnv)
+		if err != nil {
+			return nil, err
+		}
+		fileEncryption = &kvpb.FileEncryptionOptions{Key: key}
+	}
+
+	var cols []catalog.Column
+	var colIDs []descpb.ColumnID
+	for _, col := range table.PublicColumns() {
+		if !col.IsVirtual() {
+			cols = append(cols, col)
+			colIDs = append(colIDs, col.GetID())
+		}
+	}
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(&spec, codec, table, table.GetPrimaryIndex(), colIDs); err != nil {
+		return nil, err
+	}
+	var rf row.Fetcher
+	if err := rf.Init(ctx, row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &tree.DatumAlloc{},
+		Spec:              &spec,
+	}); err != nil {
+		return nil, err
+	}
+	defer rf.Close(ctx)
+
+	var rows []tree.Datums
+	// decodeRows decodes the rows made of kvs, which must hold all the KVs of
+	// each of these rows, and appends them to rows.
+	decodeRows := func(kvs []roachpb.KeyValue) error {
+		if err := rf.ConsumeKVProvider(ctx, &row.KVProvider{KVs: kvs}); err != nil {
+			return err
+		}
+		for {
+			datums, err := rf.NextRowDecoded(ctx)
+			if err != nil {
+				return err
+			}
+			if datums == nil {
+				return nil
+			}
+			obj := json.NewObjectBuilder(len(cols))
+			for i, col := range cols {
+				j, err := tree.AsJSON(datums[i], sessiondatapb.DataConversionConfig{}, time.UTC)
+				if err != nil {
+					return err
+				}
+				obj.Add(col.GetName(), j)
+			}
+			d := tree.NewDJSON(obj.Build())
+			if err := mem.Grow(ctx, int64(d.Size())); err != nil {
+				return err
+			}
+			rows = append(rows, tree.Datums{d})
+		}
+	}
+
+	// The cover is ordered by key, so the rows are decoded one entry at a time,
+	// holding only the KVs of one entry in memory. An entry can end in the
+	// middle of a row with several column families, in which case the KVs of
+	// that row are carried over and decoded with the next entry.
+	var carried []roachpb.KeyValue
+	var carriedSize int64
+	for _, entry := range cover {
+		kvs, kvsSize, err := readBackupSpanEntry(ctx, execCfg, mem, entry, fileEncryption, info.endTime)
+		if err != nil {
+			return nil, err
+		}
+		kvs = append(carried, kvs...)
+		kvsSize += carriedSize
+		carried, carriedSize = nil, 0
+		if len(kvs) > 0 {
+			lastRow, err := keys.EnsureSafeSplitKey(kvs[len(kvs)-1].Key)
+			if err != nil {
+				return nil, err
+			}
+			split := len(kvs)
+			for split > 0 && kvs[split-1].Key.HasPrefix(lastRow) {
+				split--
+			}
+			carried = kvs[split:]
+			for _, kv := range carried {
+				carriedSize += int64(kv.Size())
+			}
+			kvs = kvs[:split]
+		}
+		if err := decodeRows(kvs); err != nil {
+			return nil, err
+		}
+		mem.Shrink(ctx, kvsSize-carriedSize)
+	}
+	if err := decodeRows(carried); err != nil {
+		return nil, err
+	}
+	mem.Shrink(ctx, carriedSize)
+	return rows, nil
+}
+
+// readBackupSpanEntry returns the latest revision, as of asOf, of each live key
+// in the span of entry, read from the entry's files, and their total size,
+// which it reserves in mem. The caller releases the reservation, or closes mem
+// on error.
+func readBackupSpanEntry(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	mem *mon.BoundAccount,
+	entry execinfrapb.RestoreSpanEntry,
+	encryption *kvpb.FileEncryptionOptions,
+	asOf hlc.Timestamp,
+) ([]roachpb.KeyValue, int64, error) {
+	storeFiles := make([]storageccl.StoreFile, 0, len(entry.Files))
+	for _, file := range entry.Files {
+		dir, err := execCfg.DistSQLSrv.ExternalStorage(ctx, file.Dir)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer logClose(ctx, dir, "backup rows source")
+		storeFiles = append(storeFiles, storageccl.StoreFile{Store: dir, FilePath: file.Path})
+	}
+	iter, err := storageccl.ExternalSSTReader(ctx, storeFiles, encryption, storage.IterOptions{
+		RangeKeyMaskingBelow: asOf,
+		KeyTypes:             storage.IterKeyTypePointsAndRanges,
+		LowerBound:           entry.Span.Key,
+		UpperBound:           entry.Span.EndKey,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	readAsOfIter := storage.NewReadAsOfIterator(iter, asOf)
+	defer readAsOfIter.Close()
+
+	var kvs []roachpb.KeyValue
+	var size int64
+	for readAsOfIter.SeekGE(storage.MVCCKey{Key: entry.Span.Key}); ; readAsOfIter.NextKey() {
+		if ok, err := readAsOfIter.Valid(); err != nil {
+			return nil, 0, err
+		} else if !ok {
+			return kvs, size, nil
+		}
+		key := readAsOfIter.UnsafeKey()
+		v, err := readAsOfIter.UnsafeValue()
+		if err != nil {
+			return nil, 0, err
+		}
+		kv := roachpb.KeyValue{
+			Key:   key.Key.Clone(),
+			Value: roachpb.Value{RawBytes: append([]byte(nil), v...), Timestamp: key.Timestamp},
+		}
+		if err := mem.Grow(ctx, int64(kv.Size())); err != nil {
+			return nil, 0, err
+		}
+		size += int64(kv.Size())
+		kvs = append(kvs, kv)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRestoreRows tests that the rows in a primary key range of a table can be
+// shown and restored into a new table as of a time before a bad write.
+func TestRestoreRows(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 20
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	const collection = "nodelocal://1/rows"
+	sqlDB.Exec(t, `CREATE TABLE data.orders (
+  region STRING, id INT, amount INT,
+  PRIMARY KEY (region, id DESC), FAMILY (region, id), FAMILY (amount)
+)`)
+	sqlDB.Exec(t, `INSERT INTO data.orders
+SELECT r, i, i * 10 FROM unnest(ARRAY['east', 'north', 'west']) AS r, generate_series(1, 5) AS i`)
+	sqlDB.Exec(t, `CREATE TABLE data.indexed (id INT PRIMARY KEY, v INT, INDEX (v))`)
+	sqlDB.Exec(t, `CREATE TABLE data.tree (id INT PRIMARY KEY, parent INT REFERENCES data.tree (id))`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1 WITH revision_history`, collection)
+
+	var beforeUpdate string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&beforeUpdate)
+	expected := sqlDB.QueryStr(t,
+		`SELECT id, balance, payload FROM data.bank WHERE id BETWEEN 5 AND 9 OR id = 15 ORDER BY id`)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = -1 WHERE id >= 5`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1 WITH revision_history`, collection)
+
+	t.Run("show", func(t *testing.T) {
+		sqlDB.CheckQueryResults(t, `
+SELECT (row->>'id')::INT, (row->>'balance')::INT, row->>'payload'
+FROM [SHOW BACKUP ROWS FOR TABLE data.bank FROM LATEST IN $1
+      AS OF SYSTEM TIME `+beforeUpdate+` WHERE id BETWEEN 5 AND 9 OR id = 15]
+ORDER BY 1`, expected, collection)
+
+		var count int
+		sqlDB.QueryRow(t, `SELECT count(*) FROM [SHOW BACKUP ROWS FOR TABLE data.bank FROM LATEST IN $1]
+WHERE (row->>'balance')::INT = -1`, collection).Scan(&count)
+		require.Equal(t, numAccounts-5, count)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		sqlDB.Exec(t, `RESTORE TABLE data.bank FROM LATEST IN $1 AS OF SYSTEM TIME `+beforeUpdate+`
+WHERE id BETWEEN 5 AND 9 OR 15 = id INTO bank_fixed`, collection)
+		sqlDB.CheckQueryResults(t,
+			`SELECT id, balance, payload FROM data.bank_fixed ORDER BY id`, expected)
+		sqlDB.CheckQueryResults(t,
+			`SELECT index_name FROM [SHOW INDEXES FROM data.bank_fixed]`, [][]string{
+				{"bank_pkey"}, {"bank_pkey"}, {"bank_pkey"},
+			})
+
+		// Repair the bad update from the restored rows.
+		sqlDB.Exec(t, `UPSERT INTO data.bank SELECT * FROM data.bank_fixed`)
+		sqlDB.CheckQueryResults(t,
+			`SELECT id, balance, payload FROM data.bank WHERE id BETWEEN 5 AND 9 OR id = 15 ORDER BY id`,
+			expected)
+	})
+
+	t.Run("composite key", func(t *testing.T) {
+		const filter = `region = 'east' AND id > 2 OR region IN ('west', 'south') AND 1 = id`
+		expectedOrders := sqlDB.QueryStr(t,
+			`SELECT region, id, amount FROM data.orders WHERE `+filter+` ORDER BY region, id`)
+		require.Len(t, expectedOrders, 4)
+		sqlDB.CheckQueryResults(t, `
+SELECT row->>'region', (row->>'id')::INT, (row->>'amount')::INT
+FROM [SHOW BACKUP ROWS FOR TABLE data.orders FROM LATEST IN $1 WHERE `+filter+`]
+ORDER BY 1, 2`, expectedOrders, collection)
+
+		sqlDB.Exec(t, `RESTORE TABLE data.orders FROM LATEST IN $1 WHERE `+filter+`
+INTO orders_subset`, collection)
+		sqlDB.CheckQueryResults(t,
+			`SELECT region, id, amount FROM data.orders_subset ORDER BY region, id`, expectedOrders)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct{ stmt, err string }{
+			{
+				`RESTORE TABLE data.bank FROM LATEST IN $1 WHERE balance > 0 INTO b`,
+				"unsupported row filter",
+			},
+			{
+				`RESTORE TABLE data.bank FROM LATEST IN $1 WHERE id NOT IN (1, 2) INTO b`,
+				"unsupported row filter",
+			},
+			{
+				`RESTORE TABLE data.orders FROM LATEST IN $1 WHERE id > 1 INTO b`,
+				`primary key column "id" can only be compared to a range of values`,
+			},
+			{
+				`RESTORE TABLE data.orders FROM LATEST IN $1 WHERE region > 'a' AND id = 1 INTO b`,
+				`primary key column "id" can only be compared to a range of values`,
+			},
+			{
+				`RESTORE TABLE data.indexed FROM LATEST IN $1 WHERE id > 1 INTO b`,
+				"it has secondary indexes",
+			},
+			{
+				`RESTORE TABLE data.tree FROM LATEST IN $1 WHERE id > 1 INTO b`,
+				"references the table itself",
+			},
+			{
+				`RESTORE TABLE data.bank FROM LATEST IN $1 WHERE id > 1 INTO b WITH schema_only`,
+				"schema_only",
+			},
+			{
+				`RESTORE DATABASE data FROM LATEST IN $1 WHERE id > 1 INTO b`,
+				"single table",
+			},
+		} {
+			sqlDB.ExpectErr(t, tc.err, tc.stmt, collection)
+		}
+	})
+}
@@ -10,6 +10,7 @@ package backupccl
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
@@ -109,6 +110,12 @@ func (e *scheduledBackupExecutor) executeBackup(
 		}
 	}
 
+	if err := e.maybeCompactLatestChain(ctx, hook.(sql.PlanHookState), sj, backupStmt); err != nil {
+		// Failing to compact the chain should not prevent the schedule from
+		// backing up; the compaction is attempted again on the next run.
+		log.Warningf(ctx, "failed to compact backups for schedule %d: %v", sj.ScheduleID(), err)
+	}
+
 	backupFn, err := planBackup(ctx, hook.(sql.PlanHookState), backupStmt)
 	if err != nil {
 		return err
@@ -117,6 +124,51 @@ func (e *scheduledBackupExecutor) executeBackup(
 	return err
 }
 
+// maybeCompactLatestChain starts a detached job compacting the incremental
+// layers of the latest chain if this incremental schedule has run the
+// configured number of incremental backups since it last did so.
+func (e *scheduledBackupExecutor) maybeCompactLatestChain(
+	ctx context.Context, p sql.PlanHookState, sj *jobs.ScheduledJob, backupStmt *annotatedBackupStatement,
+) error {
+	args := &backuppb.ScheduledBackupExecutionArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return errors.Wrap(err, "un-marshaling args")
+	}
+	if args.BackupType != backuppb.ScheduledBackupExecutionArgs_INCREMENTAL ||
+		args.CompactAfterIncrementals <= 0 ||
+		args.IncrementalsSinceCompaction < args.CompactAfterIncrementals {
+		return nil
+	}
+
+	compactStmt := &tree.CompactBackups{
+		In: backupStmt.To,
+		Options: tree.BackupOptions{
+			Detached:             tree.DBoolTrue,
+			EncryptionPassphrase: backupStmt.Options.EncryptionPassphrase,
+			EncryptionKMSURI:     backupStmt.Options.EncryptionKMSURI,
+			IncrementalStorage:   backupStmt.Options.IncrementalStorage,
+		},
+	}
+	fn, _, _, _, err := compactBackupsPlanHook(ctx, compactStmt, p)
+	if err != nil {
+		return err
+	}
+	// A detached job reports its ID, which we have no use for.
+	resultsCh := make(chan tree.Datums, 1)
+	if err := fn(ctx, nil, resultsCh); err != nil {
+		return err
+	}
+	log.Infof(ctx, "started compaction of backups for schedule %d", sj.ScheduleID())
+
+	args.IncrementalsSinceCompaction = 0
+	any, err := pbtypes.MarshalAny(args)
+	if err != nil {
+		return errors.Wrap(err, "marshaling args")
+	}
+	sj.SetExecutionDetails(sj.ExecutorType(), jobspb.ExecutionArguments{Args: any})
+	return nil
+}
+
 func invokeBackup(
 	ctx context.Context, backupFn sql.PlanHookRowFn, registry *jobs.Registry, txn isql.Txn,
 ) (eventpb.RecoveryEvent, error) {
@@ -283,6 +335,36 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 		},
 	}
 
+	// The compaction option is stored on the incremental schedule's arguments,
+	// and the retention options on the full schedule's.
+	incArgs, fullArgs := args, args
+	if dependentSchedule != nil {
+		dependentArgs := &backuppb.ScheduledBackupExecutionArgs{}
+		if err := pbtypes.UnmarshalAny(dependentSchedule.ExecutionArgs().Args, dependentArgs); err != nil {
+			return "", errors.Wrap(err, "un-marshaling args")
+		}
+		if backupNode.AppendToLatest {
+			fullArgs = dependentArgs
+		} else {
+			incArgs = dependentArgs
+		}
+	}
+	if incArgs.CompactAfterIncrementals > 0 {
+		scheduleOptions = append(scheduleOptions, tree.KVOption{
+			Key:   optCompactAfterIncrementals,
+			Value: tree.NewDString(strconv.FormatInt(incArgs.CompactAfterIncrementals, 10)),
+		})
+	}
+	if fullArgs.Retention != "" {
+		scheduleOptions = append(scheduleOptions, tree.KVOption{
+			Key:   optRetention,
+			Value: tree.NewDString(fullArgs.Retention),
+		})
+		if fullArgs.RetentionDryRun {
+			scheduleOptions = append(scheduleOptions, tree.KVOption{Key: optRetentionDryRun})
+		}
+	}
+
 	var destinations []string
 	for i := range backupNode.To {
 		dest, ok := backupNode.To[i].(*tree.StrVal)
@@ -346,6 +428,18 @@ func (e *scheduledBackupExecutor) backupSucceeded(
 		e.metrics.RpoMetric.Update(details.(jobspb.BackupDetails).EndTime.GoTime().Unix())
 	}
 
+	// Count the incremental backups that succeeded since the chain was last
+	// compacted; see maybeCompactLatestChain.
+	if args.BackupType == backuppb.ScheduledBackupExecutionArgs_INCREMENTAL &&
+		args.CompactAfterIncrementals > 0 {
+		args.IncrementalsSinceCompaction++
+		any, err := pbtypes.MarshalAny(args)
+		if err != nil {
+			return errors.Wrap(err, "marshaling args")
+		}
+		schedule.SetExecutionDetails(schedule.ExecutorType(), jobspb.ExecutionArguments{Args: any})
+	}
+
 	if args.UnpauseOnSuccess == jobs.InvalidScheduleID {
 		return nil
 	}
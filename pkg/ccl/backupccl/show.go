@@ -41,6 +41,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/nstree"
 	"github.com/cockroachdb/cockroach/pkg/sql/doctor"
 	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/protoreflect"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
@@ -276,6 +278,20 @@ func showBackupPlanHook(
 		return showBackupsInCollectionPlanHook(ctx, collection, showStmt, p)
 	}
 
+	return planShowBackup(ctx, stmt, p, showStmt, getBackupInfoReader(p, showStmt))
+}
+
+// planShowBackup plans stmt, a SHOW BACKUP or a VERIFY BACKUP, which reads the
+// backup that showStmt describes and prints it with infoReader.
+func planShowBackup(
+	ctx context.Context,
+	stmt tree.Statement,
+	p sql.PlanHookState,
+	showStmt *tree.ShowBackup,
+	infoReader backupInfoReader,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	_, isVerify := stmt.(*tree.VerifyBackup)
+	exprEval := p.ExprEvaluator(stmt.StatementTag())
 	to, err := exprEval.String(ctx, showStmt.Path)
 	if err != nil {
 		return nil, nil, nil, false, err
@@ -289,10 +305,16 @@ func showBackupPlanHook(
 		}
 	}
 
-	infoReader := getBackupInfoReader(p, showStmt)
-
-	if err != nil {
-		return nil, nil, nil, false, err
+	if showStmt.Details == tree.BackupRowDetails && showStmt.InCollection == nil {
+		return nil, nil, nil, false, errors.New("SHOW BACKUP ROWS requires a backup collection")
+	}
+	var endTime hlc.Timestamp
+	if showStmt.AsOf.Expr != nil {
+		asOf, err := p.EvalAsOfTimestamp(ctx, showStmt.AsOf)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		endTime = asOf.Timestamp
 	}
 
 	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
@@ -312,13 +334,15 @@ func showBackupPlanHook(
 			dest = append(dest, to)
 			// Deprecation notice for old `SHOW BACKUP` syntax. Remove this once the syntax is
 			// deleted in 22.2.
-			p.BufferClientNotice(ctx,
-				pgnotice.Newf("The `SHOW BACKUP` syntax without the `IN` keyword will be removed in a"+
-					" future release. Please switch over to using `SHOW BACKUP FROM <backup> IN"+
-					" <collection>` to view metadata on a backup collection: %s."+
-					" Also note that backups created using the `BACKUP TO` syntax may not be showable or"+
-					" restoreable in the next major version release. Use `BACKUP INTO` instead.",
-					"https://www.cockroachlabs.com/docs/stable/show-backup.html"))
+			if !isVerify {
+				p.BufferClientNotice(ctx,
+					pgnotice.Newf("The `SHOW BACKUP` syntax without the `IN` keyword will be removed in a"+
+						" future release. Please switch over to using `SHOW BACKUP FROM <backup> IN"+
+						" <collection>` to view metadata on a backup collection: %s."+
+						" Also note that backups created using the `BACKUP TO` syntax may not be showable or"+
+						" restoreable in the next major version release. Use `BACKUP INTO` instead.",
+						"https://www.cockroachlabs.com/docs/stable/show-backup.html"))
+			}
 		}
 
 		if err := cloudprivilege.CheckDestinationPrivileges(ctx, p, dest); err != nil {
@@ -458,6 +482,7 @@ you must pass the 'encryption_info_dir' parameter that points to the directory o
 		info.subdir = computedSubdir
 		info.kmsEnv = &kmsEnv
 		info.enc = encryption
+		info.endTime = endTime
 
 		mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
 		incStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, p.User(), mkStore,
@@ -474,7 +499,7 @@ you must pass the 'encryption_info_dir' parameter that points to the directory o
 		info.defaultURIs, info.manifests, info.localityInfo, memReserved,
 			err = backupdest.ResolveBackupManifests(
 			ctx, &mem, baseStores, incStores, mkStore, fullyResolvedDest,
-			fullyResolvedIncrementalsDirectory, hlc.Timestamp{}, encryption, &kmsEnv, p.User())
+			fullyResolvedIncrementalsDirectory, endTime, encryption, &kmsEnv, p.User())
 		defer func() {
 			mem.Shrink(ctx, memReserved)
 		}()
@@ -531,7 +556,9 @@ you must pass the 'encryption_info_dir' parameter that points to the directory o
 		if err := infoReader.showBackup(ctx, &mem, mkStore, info, p.User(), &kmsEnv, resultsCh); err != nil {
 			return err
 		}
-		if showStmt.InCollection == nil {
+		if isVerify {
+			telemetry.Count("verify-backup")
+		} else if showStmt.InCollection == nil {
 			telemetry.Count("show-backup.deprecated-subdir-syntax")
 		} else {
 			telemetry.Count("show-backup.collection")
@@ -559,6 +586,8 @@ func getBackupInfoReader(p sql.PlanHookState, showStmt *tree.ShowBackup) backupI
 			shower = backupShowerDefault(p, true, showStmt.Options)
 		case tree.BackupValidateDetails:
 			shower = backupShowerDoctor
+		case tree.BackupRowDetails:
+			shower = backupShowerRowsSetup(p, showStmt)
 
 		default:
 			shower = backupShowerDefault(p, false, showStmt.Options)
@@ -709,6 +738,9 @@ type backupInfo struct {
 	enc                *jobspb.BackupEncryptionOptions
 	kmsEnv             cloud.KMSEnv
 	fileSizes          [][]int64
+	// endTime is the time as of which the backup is shown, or empty to show it
+	// as of its end time.
+	endTime hlc.Timestamp
 }
 
 type backupShower struct {
@@ -1305,6 +1337,52 @@ var backupShowerDoctor = backupShower{
 	},
 }
 
+// backupShowerRowsSetup returns a shower that prints, as JSON, the rows of the
+// table named by showStmt that match its row filter.
+func backupShowerRowsSetup(p sql.PlanHookState, showStmt *tree.ShowBackup) backupShower {
+	return backupShower{header: colinfo.ResultColumns{
+		{Name: "row", Typ: types.Jsonb},
+	},
+
+		fn: func(ctx context.Context, info backupInfo) ([]tree.Datums, error) {
+			pattern := showStmt.Table.ToUnresolvedName()
+			targets := tree.BackupTargetList{
+				Tables: tree.TableAttrs{TablePatterns: tree.TablePatterns{pattern}},
+			}
+			_, _, descsByTablePattern, _, _, err := selectTargets(
+				ctx, p, info.manifests, info.layerToIterFactory, targets, tree.RequestedDescriptors,
+				info.endTime, false, /* restoreAllTenants */
+			)
+			if err != nil {
+				return nil, err
+			}
+			table, ok := descsByTablePattern[pattern].(catalog.TableDescriptor)
+			if !ok || !table.IsPhysicalTable() {
+				return nil, pgerror.Newf(pgcode.WrongObjectType,
+					"cannot show the rows of %s, which is not a table", tree.ErrString(showStmt.Table))
+			}
+			codec, err := backupinfo.MakeBackupCodec(info.manifests[0])
+			if err != nil {
+				return nil, err
+			}
+			spans := []roachpb.Span{table.PrimaryIndexSpan(codec)}
+			if showStmt.RowFilter != nil {
+				spans, err = rowFilterSpans(
+					ctx, p.SemaCtx(), &p.ExtendedEvalContext().Context, codec, table, showStmt.RowFilter,
+				)
+				if err != nil {
+					return nil, err
+				}
+			}
+			// The rows are only accounted for while they are read, which bounds the
+			// memory used to read them from the backup.
+			mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
+			defer mem.Close(ctx)
+			return readBackupRows(ctx, p.ExecCfg(), p.User(), &mem, info, codec, table, spans)
+		},
+	}
+}
+
 func backupShowerFileSetup(
 	p sql.PlanHookState, inCol tree.StringOrPlaceholderOptList,
 ) backupShower {
@@ -0,0 +1,452 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// verifyBackupStatusOK is the status of a backup file that passed
+// verification.
+const verifyBackupStatusOK = "ok"
+
+var verifyBackupHeader = colinfo.ResultColumns{
+	{Name: "path", Typ: types.String},
+	{Name: "backup_type", Typ: types.String},
+	{Name: "locality", Typ: types.String},
+	{Name: "size_bytes", Typ: types.Int},
+	{Name: "status", Typ: types.String},
+}
+
+func verifyBackupTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	verifyStmt, ok := stmt.(*tree.VerifyBackup)
+	if !ok {
+		return false, nil, nil
+	}
+	if err := exprutil.TypeCheck(
+		ctx, "VERIFY BACKUP", p.SemaCtx(),
+		exprutil.Strings{
+			verifyStmt.Path,
+			verifyStmt.Options.EncryptionPassphrase,
+			verifyStmt.Options.EncryptionInfoDir,
+		},
+		exprutil.StringArrays{
+			tree.Exprs(verifyStmt.InCollection),
+			tree.Exprs(verifyStmt.Options.IncrementalStorage),
+			tree.Exprs(verifyStmt.Options.DecryptionKMSURI),
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, verifyBackupHeader, nil
+}
+
+// verifyBackupPlanHook implements sql.PlanHookFn for VERIFY BACKUP, which
+// checks that every SST of a backup exists, has the size it was written with
+// and can be opened and decrypted, and, with the deep option, that every key of
+// every SST can be read with valid checksums and, if the backup was taken with
+// the fingerprints option, that the keys of every file match its recorded
+// fingerprints. It prints one row per file, whose status is "ok" or describes
+// why the file failed verification, so that a scheduled verification can alert
+// on any row that is not "ok".
+//
+// The manifests of the backup are verified against their checksums when they
+// are read.
+func verifyBackupPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	verifyStmt, ok := stmt.(*tree.VerifyBackup)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+	showStmt := &tree.ShowBackup{
+		From:         true,
+		Path:         verifyStmt.Path,
+		InCollection: verifyStmt.InCollection,
+		Options:      verifyStmt.Options,
+	}
+	infoReader := manifestInfoReader{shower: backupShowerVerify(p, verifyStmt.Options.Deep)}
+	return planShowBackup(ctx, stmt, p, showStmt, infoReader)
+}
+
+// backupShowerVerify returns a shower that verifies every SST of a backup,
+// reading all of their keys if deep is set. The files are verified by a
+// verifyBackupProcessor on every instance, each of which verifies a contiguous
+// chunk of the files of the backup.
+func backupShowerVerify(p sql.PlanHookState, deep bool) backupShower {
+	return backupShower{
+		header: verifyBackupHeader,
+
+		fn: func(ctx context.Context, info backupInfo) ([]tree.Datums, error) {
+			var encryption *kvpb.FileEncryptionOptions
+			if info.enc != nil {
+				key, err := backupencryption.GetEncryptionKey(ctx, info.enc, info.kmsEnv)
+				if err != nil {
+					return nil, err
+				}
+				encryption = &kvpb.FileEncryptionOptions{Key: key}
+			}
+
+			var files []execinfrapb.VerifyBackupSpec_File
+			var rows []tree.Datums
+			for layer, manifest := range info.manifests {
+				backupType := "full"
+				if manifest.IsIncremental() {
+					backupType = "incremental"
+				}
+				var err error
+				files, rows, err = collectBackupLayerFiles(ctx, info, layer, backupType, files, rows)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if err := distVerifyBackup(ctx, p, files, encryption, deep, rows); err != nil {
+				return nil, err
+			}
+			return rows, nil
+		},
+	}
+}
+
+// collectBackupLayerFiles appends the files of the given layer of the backup
+// to files, and a row of the verifyBackupHeader for each of them to rows,
+// leaving their size and status to be filled in once they are verified.
+func collectBackupLayerFiles(
+	ctx context.Context,
+	info backupInfo,
+	layer int,
+	backupType string,
+	files []execinfrapb.VerifyBackupSpec_File,
+	rows []tree.Datums,
+) ([]execinfrapb.VerifyBackupSpec_File, []tree.Datums, error) {
+	it, err := info.layerToIterFactory[layer].NewFileIter(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return nil, nil, err
+		} else if !ok {
+			return files, rows, nil
+		}
+		f := it.Value()
+		uri, locality := info.defaultURIs[layer], "default"
+		if localityURI, ok := info.localityInfo[layer].URIsByOriginalLocalityKV[f.LocalityKV]; ok {
+			uri, locality = localityURI, f.LocalityKV
+		}
+		file, err := protoutil.Marshal(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, execinfrapb.VerifyBackupSpec_File{
+			Index:        int64(len(rows)),
+			URI:          uri,
+			File:         file,
+			Fingerprints: info.manifests[layer].HasFileFingerprints,
+		})
+		rows = append(rows, tree.Datums{
+			tree.NewDString(path.Join(strings.Split(uri, "?")[0], f.Path)),
+			tree.NewDString(backupType),
+			tree.NewDString(locality),
+			tree.DNull,
+			tree.DNull,
+		})
+	}
+}
+
+// distVerifyBackup plans and runs a verifyBackupProcessor on every instance,
+// each of which verifies a contiguous chunk of files, and fills in the size and
+// status of the row of each file.
+func distVerifyBackup(
+	ctx context.Context,
+	p sql.PlanHookState,
+	files []execinfrapb.VerifyBackupSpec_File,
+	encryption *kvpb.FileEncryptionOptions,
+	deep bool,
+	rows []tree.Datums,
+) error {
+	ctx, span := tracing.ChildSpan(ctx, "backupccl.distVerifyBackup")
+	defer span.Finish()
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	dsp := p.DistSQLPlanner()
+	evalCtx := p.ExtendedEvalContext()
+	planCtx, sqlInstanceIDs, err := dsp.SetupAllNodesPlanning(ctx, evalCtx, p.ExecCfg())
+	if err != nil {
+		return err
+	}
+
+	corePlacement := make([]physicalplan.ProcessorCorePlacement, 0, len(sqlInstanceIDs))
+	chunkSize := (len(files) + len(sqlInstanceIDs) - 1) / len(sqlInstanceIDs)
+	for i, id := range sqlInstanceIDs {
+		start := i * chunkSize
+		if start >= len(files) {
+			break
+		}
+		end := start + chunkSize
+		if end > len(files) {
+			end = len(files)
+		}
+		corePlacement = append(corePlacement, physicalplan.ProcessorCorePlacement{
+			SQLInstanceID: id,
+			Core: execinfrapb.ProcessorCoreUnion{VerifyBackup: &execinfrapb.VerifyBackupSpec{
+				Files:      files[start:end],
+				Encryption: encryption,
+				Deep:       deep,
+				UserProto:  p.User().EncodeProto(),
+			}},
+		})
+	}
+
+	plan := planCtx.NewPhysicalPlan()
+	plan.AddNoInputStage(corePlacement, execinfrapb.PostProcessSpec{}, verifyBackupOutputTypes, execinfrapb.Ordering{})
+	plan.PlanToStreamColMap = make([]int, len(verifyBackupOutputTypes))
+	for i := range plan.PlanToStreamColMap {
+		plan.PlanToStreamColMap[i] = i
+	}
+	sql.FinalizePlan(ctx, planCtx, plan)
+
+	rowResultWriter := sql.NewCallbackResultWriter(func(ctx context.Context, row tree.Datums) error {
+		idx := int(tree.MustBeDInt(row[0]))
+		if idx < 0 || idx >= len(rows) {
+			return errors.AssertionFailedf("unexpected file index %d", idx)
+		}
+		rows[idx][3], rows[idx][4] = row[1], row[2]
+		return nil
+	})
+	recv := sql.MakeDistSQLReceiver(
+		ctx,
+		rowResultWriter,
+		tree.Rows,
+		nil, /* rangeCache */
+		nil, /* txn - the flow does not read or write the database */
+		nil, /* clockUpdater */
+		evalCtx.Tracing,
+	)
+	defer recv.Release()
+
+	// Copy the evalCtx, as dsp.Run() might change it.
+	evalCtxCopy := *evalCtx
+	dsp.Run(ctx, planCtx, nil /* txn */, plan, recv, &evalCtxCopy, nil /* finishedSetupFn */)
+	return rowResultWriter.Err()
+}
+
+// verifyBackupOutputTypes are the types of the rows output by a
+// verifyBackupProcessor: the index of a file, the size of its SST and its
+// status.
+var verifyBackupOutputTypes = []*types.T{types.Int, types.Int, types.String}
+
+const verifyBackupProcessorName = "verifyBackupProcessor"
+
+// verifyBackupProcessor verifies the files of its spec, one at a time, and
+// outputs a row of the verifyBackupOutputTypes for each of them.
+type verifyBackupProcessor struct {
+	execinfra.ProcessorBase
+
+	spec execinfrapb.VerifyBackupSpec
+	// stores caches the storages opened to read the files, by URI.
+	stores map[string]cloud.ExternalStorage
+	next   int
+}
+
+var (
+	_ execinfra.Processor = &verifyBackupProcessor{}
+	_ execinfra.RowSource = &verifyBackupProcessor{}
+)
+
+func newVerifyBackupProcessor(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.VerifyBackupSpec,
+	post *execinfrapb.PostProcessSpec,
+) (execinfra.Processor, error) {
+	vp := &verifyBackupProcessor{
+		spec:   spec,
+		stores: make(map[string]cloud.ExternalStorage),
+	}
+	if err := vp.Init(ctx, vp, post, verifyBackupOutputTypes, flowCtx, processorID, nil, /* memMonitor */
+		execinfra.ProcStateOpts{
+			// This processor doesn't have any inputs to drain.
+			InputsToDrain: nil,
+			TrailingMetaCallback: func() []execinfrapb.ProducerMetadata {
+				vp.close()
+				return nil
+			},
+		}); err != nil {
+		return nil, err
+	}
+	return vp, nil
+}
+
+// Start is part of the RowSource interface.
+func (vp *verifyBackupProcessor) Start(ctx context.Context) {
+	vp.StartInternal(ctx, verifyBackupProcessorName)
+}
+
+// Next is part of the RowSource interface.
+func (vp *verifyBackupProcessor) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	for vp.State == execinfra.StateRunning {
+		if vp.next >= len(vp.spec.Files) {
+			vp.MoveToDraining(nil /* err */)
+			break
+		}
+		file := &vp.spec.Files[vp.next]
+		vp.next++
+		size, status, err := vp.verify(vp.Ctx(), file)
+		if err != nil {
+			vp.MoveToDraining(err)
+			break
+		}
+		row := rowenc.EncDatumRow{
+			rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(file.Index))),
+			rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(size))),
+			rowenc.DatumToEncDatum(types.String, tree.NewDString(status)),
+		}
+		if outRow := vp.ProcessRowHelper(row); outRow != nil {
+			return outRow, nil
+		}
+	}
+	return nil, vp.DrainHelper()
+}
+
+// verify returns the size of the SST of the given file and its status, which
+// is verifyBackupStatusOK if it passed verification. An error is only returned
+// if the storage of the file cannot be opened.
+func (vp *verifyBackupProcessor) verify(
+	ctx context.Context, file *execinfrapb.VerifyBackupSpec_File,
+) (int64, string, error) {
+	var f backuppb.BackupManifest_File
+	if err := protoutil.Unmarshal(file.File, &f); err != nil {
+		return 0, "", err
+	}
+	store, ok := vp.stores[file.URI]
+	if !ok {
+		var err error
+		store, err = vp.FlowCtx.Cfg.ExternalStorageFromURI(ctx, file.URI, vp.spec.User())
+		if err != nil {
+			return 0, "", err
+		}
+		vp.stores[file.URI] = store
+	}
+
+	size, err := store.Size(ctx, f.Path)
+	switch {
+	case err != nil:
+		return 0, errors.Wrap(err, "missing").Error(), nil
+	case size == 0:
+		return 0, "empty", nil
+	case f.FileSize != 0 && size != f.FileSize:
+		return size, fmt.Sprintf("size mismatch: expected %d bytes, found %d", f.FileSize, size), nil
+	}
+	if err := verifyBackupFile(
+		ctx, store, &f, vp.spec.Encryption, vp.spec.Deep, file.Fingerprints,
+	); err != nil {
+		return size, err.Error(), nil
+	}
+	return size, verifyBackupStatusOK, nil
+}
+
+func (vp *verifyBackupProcessor) close() {
+	if vp.InternalClose() {
+		for _, store := range vp.stores {
+			logClose(vp.Ctx(), store, "verify backup store")
+		}
+	}
+}
+
+// ConsumerClosed is part of the RowSource interface. We have to override the
+// implementation provided by ProcessorBase.
+func (vp *verifyBackupProcessor) ConsumerClosed() {
+	vp.close()
+}
+
+// verifyBackupFile opens the SST f, decrypting it with encryption, and reads
+// its first key, which verifies its footer, its index and its first block. If
+// deep is set, all of its keys are read, which verifies all of its blocks, and,
+// if fingerprints is set, their fingerprints are compared to the ones recorded
+// for f.
+func verifyBackupFile(
+	ctx context.Context,
+	store cloud.ExternalStorage,
+	f *backuppb.BackupManifest_File,
+	encryption *kvpb.FileEncryptionOptions,
+	deep, fingerprints bool,
+) error {
+	iter, err := storageccl.ExternalSSTReader(ctx,
+		[]storageccl.StoreFile{{Store: store, FilePath: f.Path}}, encryption, storage.IterOptions{
+			KeyTypes:   storage.IterKeyTypePointsAndRanges,
+			LowerBound: f.Span.Key,
+			UpperBound: f.Span.EndKey,
+		})
+	if err != nil {
+		return errors.Wrap(err, "opening")
+	}
+	defer iter.Close()
+	fingerprinter := makeTableFingerprinter()
+	for iter.SeekGE(storage.MVCCKey{Key: f.Span.Key}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return errors.Wrap(err, "reading")
+		} else if !ok {
+			break
+		}
+		if hasPoint, _ := iter.HasPointAndRange(); hasPoint {
+			v, err := iter.UnsafeValue()
+			if err != nil {
+				return errors.Wrap(err, "reading")
+			}
+			if fingerprints {
+				if err := fingerprinter.add(iter.UnsafeKey(), v); err != nil {
+					return errors.Wrap(err, "fingerprinting")
+				}
+			}
+		}
+		if !deep {
+			return nil
+		}
+	}
+	if fingerprints && !tableFingerprintsEqual(fingerprinter.finish(), f.TableFingerprints) {
+		return errors.Newf("fingerprint mismatch for span %s", f.Span)
+	}
+	return nil
+}
+
+func init() {
+	sql.AddPlanHook("backupccl.verifyBackupPlanHook", verifyBackupPlanHook, verifyBackupTypeCheck)
+	rowexec.NewVerifyBackupProcessor = newVerifyBackupProcessor
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVerifyBackup tests that VERIFY BACKUP, which verifies the files of a
+// backup on every node, reports the SSTs of an encrypted backup that are
+// missing, truncated or corrupt.
+func TestVerifyBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+	_, sqlDB, dir, cleanupFn := backupRestoreTestSetup(t, multiNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	const collection = "nodelocal://1/verify"
+	sqlDB.Exec(t, `CREATE TABLE data.other AS SELECT * FROM data.bank`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1 WITH encryption_passphrase = 'secret'`, collection)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1 WITH encryption_passphrase = 'secret'`,
+		collection)
+
+	// verify returns the status of each SST of the backup, keyed by its name.
+	verify := func(opts string) map[string]string {
+		rows := sqlDB.QueryStr(t,
+			`VERIFY BACKUP FROM LATEST IN $1 WITH encryption_passphrase = 'secret'`+opts, collection)
+		statuses := make(map[string]string, len(rows))
+		for _, row := range rows {
+			statuses[filepath.Base(row[0])] = row[4]
+		}
+		return statuses
+	}
+	for _, opts := range []string{"", ", deep"} {
+		statuses := verify(opts)
+		require.NotEmpty(t, statuses)
+		for name, status := range statuses {
+			require.Equal(t, verifyBackupStatusOK, status, name)
+		}
+	}
+
+	// Remove one SST of the full backup and truncate another.
+	var ssts []string
+	require.NoError(t, filepath.Walk(filepath.Join(dir, "verify"),
+		func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if strings.HasSuffix(path, ".sst") && filepath.Base(filepath.Dir(path)) == "data" {
+				ssts = append(ssts, path)
+			}
+			return nil
+		}))
+	require.GreaterOrEqual(t, len(ssts), 2)
+	require.NoError(t, os.Remove(ssts[0]))
+	content, err := os.ReadFile(ssts[1])
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(ssts[1], content[:len(content)/2], 0644))
+
+	for _, opts := range []string{"", ", deep"} {
+		statuses := verify(opts)
+		require.Contains(t, statuses[filepath.Base(ssts[0])], "missing")
+		require.Contains(t, statuses[filepath.Base(ssts[1])], "size mismatch")
+		var numOK int
+		for _, status := range statuses {
+			if status == verifyBackupStatusOK {
+				numOK++
+			}
+		}
+		require.Equal(t, len(statuses)-2, numOK)
+	}
+
+	sqlDB.ExpectErr(t, "encryption", `VERIFY BACKUP FROM LATEST IN $1`, collection)
+}
@@ -11,6 +11,7 @@ package changefeedccl
 import (
 	"context"
 	"net/url"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupresolver"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
@@ -60,6 +61,14 @@ func alterChangefeedTypeCheck(
 				KVOptions:  v.Options,
 				Validation: changefeedvalidators.AlterOptionValidations,
 			})
+		case *tree.AlterChangefeedBackfill:
+			toCheck = append(toCheck,
+				exprutil.Strings{v.SinkURI},
+				&exprutil.KVOptions{
+					KVOptions:  v.Options,
+					Validation: changefeedvalidators.CreateOptionValidations,
+				},
+			)
 		}
 	}
 	if err := exprutil.TypeCheck(ctx, "ALTER CHANGEFED", p.SemaCtx(), toCheck...); err != nil {
@@ -106,6 +115,16 @@ func alterChangefeedPlanHook(
 			return errors.Errorf(`job %d is not changefeed job`, jobID)
 		}
 
+		backfillCmd, err := getBackfillCmd(alterChangefeedStmt.Cmds)
+		if err != nil {
+			return err
+		}
+		if backfillCmd != nil {
+			// Backfills are run by the changefeed alongside its own flow, and do
+			// not require the changefeed to be paused.
+			return alterChangefeedBackfill(ctx, p, job, prevDetails, backfillCmd, resultsCh)
+		}
+
 		if job.Status() != jobs.StatusPaused {
 			return errors.Errorf(`job %d is not paused`, jobID)
 		}
@@ -187,6 +206,7 @@ func alterChangefeedPlanHook(
 
 		newDetails := jobRecord.Details.(jobspb.ChangefeedDetails)
 		newDetails.Opts[changefeedbase.OptInitialScan] = ``
+		newDetails.Backfills = prevDetails.Backfills
 
 		// newStatementTime will either be the StatementTime of the job prior to the
 		// alteration, or it will be the high watermark of the job.
@@ -835,3 +855,172 @@ func getPrevOpts(prevDescription string, opts map[string]string) (map[string]str
 
 	return prevOpts, nil
 }
+
+// getBackfillCmd returns the BACKFILL command of an ALTER CHANGEFEED
+// statement, if any. BACKFILL may not be combined with other commands.
+func getBackfillCmd(cmds tree.AlterChangefeedCmds) (*tree.AlterChangefeedBackfill, error) {
+	for _, cmd := range cmds {
+		if backfill, ok := cmd.(*tree.AlterChangefeedBackfill); ok {
+			if len(cmds) > 1 {
+				return nil, pgerror.New(pgcode.InvalidParameterValue,
+					`BACKFILL cannot be combined with other ALTER CHANGEFEED commands`)
+			}
+			return backfill, nil
+		}
+	}
+	return nil, nil
+}
+
+// alterChangefeedBackfill requests the given changefeed to perform a
+// consistent scan of a single table that it watches and to emit it to another
+// sink. The changefeed job performs the scan alongside its own flow, which
+// keeps running, and protects the table at the scan's timestamp until then.
+//
+// The backfill inherits the encoding options of the changefeed so that its
+// messages are indistinguishable from the ones emitted by the changefeed. By
+// default the scan is performed at the changefeed's high-water mark: a
+// downstream consumer can be re-bootstrapped by loading the backfill and then
+// applying the changefeed's messages with later MVCC timestamps. A different
+// timestamp may be chosen with the cursor option.
+func alterChangefeedBackfill(
+	ctx context.Context,
+	p sql.PlanHookState,
+	job *jobs.Job,
+	prevDetails jobspb.ChangefeedDetails,
+	cmd *tree.AlterChangefeedBackfill,
+	resultsCh chan<- tree.Datums,
+) error {
+	if job.Status().Terminal() {
+		return errors.Errorf(`job %d is %s`, job.ID(), job.Status())
+	}
+	if prevDetails.Select != `` {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			`cannot backfill changefeed %d which uses a CDC expression`, job.ID())
+	}
+
+	exprEval := p.ExprEvaluator("ALTER CHANGEFEED")
+	sinkURI, err := exprEval.String(ctx, cmd.SinkURI)
+	if err != nil {
+		return err
+	}
+	if sinkURI == `` {
+		return errors.New(`BACKFILL requires a sink`)
+	}
+	rawOpts, err := exprEval.KVOptions(
+		ctx, cmd.Options, changefeedvalidators.CreateOptionValidations,
+	)
+	if err != nil {
+		return err
+	}
+	for key := range rawOpts {
+		if _, ok := changefeedbase.AlterChangefeedBackfillUnsupportedOptions[key]; ok {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				`cannot specify option %s with BACKFILL`, key)
+		}
+	}
+
+	prevOpts, err := getPrevOpts(job.Payload().Description, prevDetails.Opts)
+	if err != nil {
+		return err
+	}
+	for key, value := range prevOpts {
+		if _, ok := changefeedbase.AlterChangefeedBackfillInheritedOptions[key]; !ok {
+			continue
+		}
+		if _, ok := rawOpts[key]; !ok {
+			rawOpts[key] = value
+		}
+	}
+	rawOpts[changefeedbase.OptInitialScan] = `only`
+	if _, ok := rawOpts[changefeedbase.OptCursor]; !ok {
+		cursor := prevDetails.StatementTime
+		if highWater := job.Progress().GetHighWater(); highWater != nil && !highWater.IsEmpty() {
+			cursor = *highWater
+		}
+		rawOpts[changefeedbase.OptCursor] = cursor.AsOfSystemTime()
+	}
+	opts := changefeedbase.MakeStatementOptions(rawOpts)
+
+	backfillStmt := &tree.CreateChangefeed{
+		Targets: tree.ChangefeedTargets{{TableName: cmd.Target.ToUnresolvedName()}},
+		SinkURI: tree.NewDString(sinkURI),
+	}
+	if cmd.Where != nil {
+		backfillStmt.Select = &tree.SelectClause{
+			Exprs: tree.SelectExprs{tree.StarSelectExpr()},
+			From:  tree.From{Tables: tree.TableExprs{cmd.Target}},
+			Where: tree.NewWhere(tree.AstWhere, cmd.Where),
+		}
+	}
+	for key, value := range opts.AsMap() {
+		opt := tree.KVOption{Key: tree.Name(key)}
+		if len(value) > 0 {
+			opt.Value = tree.NewDString(value)
+		}
+		backfillStmt.Options = append(backfillStmt.Options, opt)
+	}
+	sort.Slice(backfillStmt.Options, func(i, j int) bool {
+		return backfillStmt.Options[i].Key < backfillStmt.Options[j].Key
+	})
+
+	jr, err := createChangefeedJobRecord(
+		ctx,
+		p,
+		&annotatedChangefeedStatement{CreateChangefeed: backfillStmt},
+		sinkURI,
+		opts,
+		jobspb.InvalidJobID,
+		telemetryPath+`.backfill`,
+	)
+	if err != nil {
+		return changefeedbase.MarkTaggedError(err, changefeedbase.UserInput)
+	}
+	details := jr.Details.(jobspb.ChangefeedDetails)
+
+	// The backfilled table must be watched by the changefeed.
+	watched := make(map[descpb.ID]struct{}, len(prevDetails.TargetSpecifications))
+	for _, ts := range prevDetails.TargetSpecifications {
+		watched[ts.TableID] = struct{}{}
+	}
+	for _, ts := range details.TargetSpecifications {
+		if _, ok := watched[ts.TableID]; !ok {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				`table %q is not watched by changefeed %d`, ts.StatementTimeName, job.ID())
+		}
+	}
+
+	backfill := jobspb.ChangefeedBackfill{Details: details, Description: jr.Description}
+	var progress jobspb.ChangefeedProgress
+	ptr := createProtectedTimestampRecord(
+		ctx,
+		p.ExecCfg().Codec,
+		job.ID(),
+		AllTargets(details),
+		details.StatementTime,
+		&progress,
+	)
+	backfill.ProtectedTimestampRecord = progress.ProtectedTimestampRecord
+	if err := p.ExecCfg().ProtectedTimestampProvider.WithTxn(p.InternalSQLTxn()).Protect(ctx, ptr); err != nil {
+		return err
+	}
+	if err := job.WithTxn(p.InternalSQLTxn()).Update(ctx, func(
+		txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		cf := md.Payload.GetChangefeed()
+		cf.Backfills = append(cf.Backfills, backfill)
+		ju.UpdatePayload(md.Payload)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case resultsCh <- tree.Datums{
+		tree.NewDInt(tree.DInt(job.ID())),
+		tree.NewDString(backfill.Description),
+	}:
+		return nil
+	}
+}
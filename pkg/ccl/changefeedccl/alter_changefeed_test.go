@@ -568,6 +568,74 @@ func TestAlterChangefeedErrors(t *testing.T) {
 	cdcTest(t, testFn, feedTestEnterpriseSinks, feedTestNoExternalConnection)
 }
 
+func TestAlterChangefeedBackfill(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b'), (3, 'c')`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo`)
+		defer closeFeed(t, testFeed)
+		assertPayloads(t, testFeed, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "c"}}`,
+		})
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+		jobRegistry := s.Server.JobRegistry().(*jobs.Registry)
+		loadBackfills := func() []jobspb.ChangefeedBackfill {
+			job, err := jobRegistry.LoadJob(context.Background(), feed.JobID())
+			require.NoError(t, err)
+			return job.Details().(jobspb.ChangefeedDetails).Backfills
+		}
+
+		// The backfill is performed by the changefeed job itself.
+		sqlDB.Exec(t, `SET CLUSTER SETTING changefeed.backfill_poll_interval = '10ms'`)
+		var jobID jobspb.JobID
+		var description string
+		sqlDB.QueryRow(t, fmt.Sprintf(
+			`ALTER CHANGEFEED %d BACKFILL TABLE foo WHERE a > 1 INTO 'null://'`, feed.JobID(),
+		)).Scan(&jobID, &description)
+		require.Equal(t, feed.JobID(), jobID)
+		require.Contains(t, description, `initial_scan = 'only'`)
+		require.Contains(t, description, `WHERE a > 1`)
+		testutils.SucceedsSoon(t, func() error {
+			if backfills := loadBackfills(); len(backfills) > 0 {
+				return errors.Newf("waiting for %d backfills", len(backfills))
+			}
+			return nil
+		})
+
+		// The changefeed keeps running while, and after, the backfill runs.
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (4, 'd')`)
+		assertPayloads(t, testFeed, []string{
+			`foo: [4]->{"after": {"a": 4, "b": "d"}}`,
+		})
+
+		sqlDB.ExpectErr(t,
+			fmt.Sprintf(`table "bar" is not watched by changefeed %d`, feed.JobID()),
+			fmt.Sprintf(`ALTER CHANGEFEED %d BACKFILL TABLE bar INTO 'null://'`, feed.JobID()),
+		)
+		sqlDB.ExpectErr(t,
+			`cannot specify option initial_scan with BACKFILL`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d BACKFILL TABLE foo INTO 'null://' WITH initial_scan = 'yes'`, feed.JobID()),
+		)
+		sqlDB.ExpectErr(t,
+			`BACKFILL cannot be combined with other ALTER CHANGEFEED commands`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d ADD bar BACKFILL TABLE foo INTO 'null://'`, feed.JobID()),
+		)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"), feedTestNoExternalConnection)
+}
+
 func TestAlterChangefeedDropAllTargetsError(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -14,19 +14,27 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
 )
 
 // NormalizeExpression normalizes select clause.  Returns normalized (and rewritten)
@@ -143,6 +151,210 @@ func SpansForExpression(
 	return plan.Spans, nil
 }
 
+// ExpressionOmitsDeletes returns true if the select clause filter is known to
+// reject every delete event; that is, if one of the top level conjuncts of the
+// WHERE clause is event_op() != 'delete' (or NOT (event_op() = 'delete')).
+// A false result does not imply that deletes are emitted.
+func ExpressionOmitsDeletes(sc *tree.SelectClause) bool {
+	if sc.Where == nil {
+		return false
+	}
+	var omitsDeletes func(expr tree.Expr) bool
+	omitsDeletes = func(expr tree.Expr) bool {
+		switch t := tree.StripParens(expr).(type) {
+		case *tree.AndExpr:
+			return omitsDeletes(t.Left) || omitsDeletes(t.Right)
+		case *tree.ComparisonExpr:
+			return t.Operator.Symbol == treecmp.NE && isEventOpDeleteComparison(t)
+		case *tree.NotExpr:
+			cmp, ok := tree.StripParens(t.Expr).(*tree.ComparisonExpr)
+			return ok && cmp.Operator.Symbol == treecmp.EQ && isEventOpDeleteComparison(cmp)
+		default:
+			return false
+		}
+	}
+	return omitsDeletes(sc.Where.Expr)
+}
+
+// isEventOpDeleteComparison returns true if the comparison is between
+// event_op() and the 'delete' string constant.
+func isEventOpDeleteComparison(cmp *tree.ComparisonExpr) bool {
+	isEventOp := func(expr tree.Expr) bool {
+		fn, ok := tree.StripParens(expr).(*tree.FuncExpr)
+		if !ok {
+			return false
+		}
+		n, ok := fn.Func.FunctionReference.(*tree.UnresolvedName)
+		return ok && n.Parts[0] == "event_op"
+	}
+	isDelete := func(expr tree.Expr) bool {
+		for {
+			switch t := expr.(type) {
+			case *tree.ParenExpr:
+				expr = t.Expr
+			case *tree.AnnotateTypeExpr:
+				expr = t.Expr
+			case *tree.CastExpr:
+				expr = t.Expr
+			case *tree.StrVal:
+				return t.RawString() == "delete"
+			default:
+				return false
+			}
+		}
+	}
+	return (isEventOp(cmp.Left) && isDelete(cmp.Right)) ||
+		(isEventOp(cmp.Right) && isDelete(cmp.Left))
+}
+
+// ExpressionRangefeedPredicates returns the predicates which can be pushed down
+// into the rangefeed filter of a changefeed over the table with the specified
+// select clause. A predicate is derived from each top level conjunct of the
+// WHERE clause comparing a column of an INT, STRING, BOOL or UUID type to a
+// constant for equality: columns of the primary key become key predicates,
+// and other stored columns become value predicates on their column family.
+// Rangefeeds evaluate predicates on a best-effort basis, so the select clause
+// must still be evaluated against every emitted event.
+func ExpressionRangefeedPredicates(
+	sc *tree.SelectClause, desc catalog.TableDescriptor,
+) ([]kvpb.RangeFeedKeyPredicate, []kvpb.RangeFeedValuePredicate, error) {
+	if sc.Where == nil {
+		return nil, nil, nil
+	}
+	var keyPreds []kvpb.RangeFeedKeyPredicate
+	var valuePreds []kvpb.RangeFeedValuePredicate
+	primaryIndex := desc.GetPrimaryIndex()
+	var addPredicates func(expr tree.Expr) error
+	addPredicates = func(expr tree.Expr) error {
+		switch t := tree.StripParens(expr).(type) {
+		case *tree.AndExpr:
+			if err := addPredicates(t.Left); err != nil {
+				return err
+			}
+			return addPredicates(t.Right)
+		case *tree.ComparisonExpr:
+			if t.Operator.Symbol != treecmp.EQ {
+				return nil
+			}
+			col, d := columnEqualsConstant(desc, t.Left, t.Right)
+			if col == nil {
+				col, d = columnEqualsConstant(desc, t.Right, t.Left)
+			}
+			if col == nil {
+				return nil
+			}
+			for i := 0; i < primaryIndex.NumKeyColumns(); i++ {
+				if primaryIndex.GetKeyColumnID(i) != col.GetID() {
+					continue
+				}
+				dir, err := catalogkeys.IndexColumnEncodingDirection(primaryIndex.GetKeyColumnDirection(i))
+				if err != nil {
+					return err
+				}
+				encoded, err := keyside.Encode(nil, d, dir)
+				if err != nil {
+					return err
+				}
+				keyPreds = append(keyPreds, kvpb.RangeFeedKeyPredicate{
+					TableID:      uint32(desc.GetID()),
+					IndexID:      uint32(primaryIndex.GetID()),
+					ColumnOffset: uint32(i),
+					EncodedValue: encoded,
+				})
+				return nil
+			}
+			for _, family := range desc.GetFamilies() {
+				for _, id := range family.ColumnIDs {
+					if id != col.GetID() {
+						continue
+					}
+					encoded, err := valueside.Encode(nil, valueside.NoColumnID, d, nil /* scratch */)
+					if err != nil {
+						return err
+					}
+					_, dataOffset, _, typ, err := encoding.DecodeValueTag(encoded)
+					if err != nil {
+						return err
+					}
+					valuePreds = append(valuePreds, kvpb.RangeFeedValuePredicate{
+						TableID:      uint32(desc.GetID()),
+						IndexID:      uint32(primaryIndex.GetID()),
+						FamilyID:     uint32(family.ID),
+						ColumnID:     uint32(col.GetID()),
+						ValueType:    int32(typ),
+						EncodedValue: encoded[dataOffset:],
+					})
+					return nil
+				}
+			}
+		}
+		return nil
+	}
+	if err := addPredicates(sc.Where.Expr); err != nil {
+		return nil, nil, err
+	}
+	return keyPreds, valuePreds, nil
+}
+
+// columnEqualsConstant returns the stored column referenced by colExpr and the
+// datum of the column's type for the constant constExpr, or nil if either
+// expression isn't of a kind which can be pushed down into a rangefeed.
+func columnEqualsConstant(
+	desc catalog.TableDescriptor, colExpr, constExpr tree.Expr,
+) (catalog.Column, tree.Datum) {
+	n, ok := tree.StripParens(colExpr).(*tree.UnresolvedName)
+	if !ok || n.NumParts != 1 {
+		return nil, nil
+	}
+	col := catalog.FindColumnByName(desc, n.Parts[0])
+	if col == nil || !col.Public() || col.IsVirtual() {
+		return nil, nil
+	}
+	for {
+		switch t := constExpr.(type) {
+		case *tree.ParenExpr:
+			constExpr = t.Expr
+			continue
+		case *tree.AnnotateTypeExpr:
+			constExpr = t.Expr
+			continue
+		}
+		break
+	}
+	typ := col.GetType()
+	switch c := constExpr.(type) {
+	case *tree.NumVal:
+		if typ.Family() != types.IntFamily {
+			return nil, nil
+		}
+		i, err := c.AsInt64()
+		if err != nil {
+			return nil, nil
+		}
+		if width := typ.Width(); width > 0 && width < 64 &&
+			(i < -(1<<(width-1)) || i >= 1<<(width-1)) {
+			return nil, nil
+		}
+		return col, tree.NewDInt(tree.DInt(i))
+	case *tree.StrVal:
+		switch {
+		case typ.Oid() == oid.T_text:
+			return col, tree.NewDString(c.RawString())
+		case typ.Family() == types.UuidFamily:
+			d, err := tree.ParseDUuidFromString(c.RawString())
+			if err != nil {
+				return nil, nil
+			}
+			return col, d
+		}
+	case *tree.DBool:
+		if typ.Family() == types.BoolFamily {
+			return col, c
+		}
+	}
+	return nil, nil
+}
+
 // withErrorHint wraps error with error hints.
 func withErrorHint(err error, targetFamily string, multiFamily bool) error {
 	// Wrap error with some additional information.
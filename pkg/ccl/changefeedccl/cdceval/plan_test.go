@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql"
@@ -32,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -349,3 +351,108 @@ func normalizeAndPlan(
 	}
 	return norm, withDiff, plan, nil
 }
+
+func TestExpressionOmitsDeletes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		expr         string
+		omitsDeletes bool
+	}{
+		{expr: "SELECT * FROM foo", omitsDeletes: false},
+		{expr: "SELECT * FROM foo WHERE a > 10", omitsDeletes: false},
+		{expr: "SELECT * FROM foo WHERE event_op() = 'delete'", omitsDeletes: false},
+		{expr: "SELECT * FROM foo WHERE event_op() != 'delete'", omitsDeletes: true},
+		{expr: "SELECT * FROM foo WHERE 'delete' != event_op()", omitsDeletes: true},
+		{expr: "SELECT * FROM foo WHERE event_op() != 'delete':::STRING", omitsDeletes: true},
+		{expr: "SELECT * FROM foo WHERE NOT (event_op() = 'delete')", omitsDeletes: true},
+		{expr: "SELECT * FROM foo WHERE a > 10 AND (event_op() != 'delete')", omitsDeletes: true},
+		{expr: "SELECT * FROM foo WHERE a > 10 OR event_op() != 'delete'", omitsDeletes: false},
+		{expr: "SELECT * FROM foo WHERE event_op() != 'update'", omitsDeletes: false},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			sc, err := ParseChangefeedExpression(tc.expr)
+			require.NoError(t, err)
+			require.Equal(t, tc.omitsDeletes, ExpressionOmitsDeletes(sc))
+		})
+	}
+}
+
+func TestExpressionRangefeedPredicates(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.Background())
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE foo (
+a INT, b STRING, c BOOL, d UUID, e INT2, f FLOAT,
+PRIMARY KEY (a, b DESC),
+FAMILY f0 (a, b, c),
+FAMILY f1 (d, e, f)
+)`)
+	fooDesc := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "foo")
+	fooID := uint32(fooDesc.GetID())
+	pkID := uint32(fooDesc.GetPrimaryIndexID())
+	valuePredicate := func(famID, colID uint32, encoded []byte) kvpb.RangeFeedValuePredicate {
+		return kvpb.RangeFeedValuePredicate{
+			TableID:      fooID,
+			IndexID:      pkID,
+			FamilyID:     famID,
+			ColumnID:     colID,
+			ValueType:    int32(encoded[0]),
+			EncodedValue: encoded[1:],
+		}
+	}
+	const uuidStr = "63616665-6630-3064-6465-616462656566"
+
+	for _, tc := range []struct {
+		expr       string
+		keyPreds   []kvpb.RangeFeedKeyPredicate
+		valuePreds []kvpb.RangeFeedValuePredicate
+	}{
+		{expr: "SELECT * FROM foo"},
+		{expr: "SELECT * FROM foo WHERE a > 5"},
+		{expr: "SELECT * FROM foo WHERE a = 5 OR c"},
+		{expr: "SELECT * FROM foo WHERE f = 1.5"},
+		{expr: "SELECT * FROM foo WHERE (cdc_prev).a = 5"},
+		// Out of range of INT2.
+		{expr: "SELECT * FROM foo WHERE e = 100000"},
+		{
+			expr: "SELECT * FROM foo WHERE b = 'x'",
+			keyPreds: []kvpb.RangeFeedKeyPredicate{{
+				TableID: fooID, IndexID: pkID, ColumnOffset: 1,
+				EncodedValue: encoding.EncodeStringDescending(nil, "x"),
+			}},
+		},
+		{
+			expr: "SELECT * FROM foo WHERE 5 = a AND (c = true AND e = 7)",
+			keyPreds: []kvpb.RangeFeedKeyPredicate{{
+				TableID: fooID, IndexID: pkID, ColumnOffset: 0,
+				EncodedValue: encoding.EncodeVarintAscending(nil, 5),
+			}},
+			valuePreds: []kvpb.RangeFeedValuePredicate{
+				valuePredicate(0, 3, encoding.EncodeBoolValue(nil, encoding.NoColumnID, true)),
+				valuePredicate(1, 5, encoding.EncodeIntValue(nil, encoding.NoColumnID, 7)),
+			},
+		},
+		{
+			expr: "SELECT * FROM foo WHERE d = '" + uuidStr + "'",
+			valuePreds: []kvpb.RangeFeedValuePredicate{
+				valuePredicate(1, 4, encoding.EncodeUUIDValue(
+					nil, encoding.NoColumnID, uuid.Must(uuid.FromString(uuidStr)))),
+			},
+		},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			sc, err := ParseChangefeedExpression(tc.expr)
+			require.NoError(t, err)
+			keyPreds, valuePreds, err := ExpressionRangefeedPredicates(sc, fooDesc)
+			require.NoError(t, err)
+			require.Equal(t, tc.keyPreds, keyPreds)
+			require.Equal(t, tc.valuePreds, valuePreds)
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
@@ -250,6 +251,10 @@ type EventDescriptor struct {
 	udtCols    []int          // Columns containing UDTs.
 	allCols    []int          // Contains all the columns
 	colsByName map[string]int // All columns, map[col.GetName()]idx in cols
+
+	// masks is the changefeed masking policy of the table, applied to every
+	// decoded row.
+	masks []columnMask
 }
 
 // NewEventDescriptor returns EventDescriptor for specified table and family descriptors.
@@ -355,6 +360,15 @@ func NewEventDescriptor(
 	}
 	sd.allCols = allCols
 
+	if policy := desc.GetChangefeedMask(); policy != "" {
+		masks, err := catpb.ParseChangefeedMasks(policy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s on table %s",
+				catpb.ChangefeedMaskTableSettingName, desc.GetName())
+		}
+		sd.masks = sd.resolveMasks(masks)
+	}
+
 	return &sd, nil
 }
 
@@ -418,6 +432,9 @@ type eventDecoder struct {
 	// Alloc used when decoding datums.
 	alloc tree.DatumAlloc
 
+	// maskKey keys the hash mask of the table masking policies.
+	maskKey []byte
+
 	// State pertaining for decoding of a single key.
 	fetcher  fetcher                        // Fetcher to decode KV
 	desc     catalog.TableDescriptor        // Current descriptor
@@ -482,6 +499,7 @@ func NewEventDecoder(
 	return &eventDecoder{
 		getEventDescriptor: getEventDescriptor,
 		rfCache:            rfCache,
+		maskKey:            MaskKey(&cfg.Settings.SV),
 	}, nil
 }
 
@@ -517,13 +535,15 @@ func (d *eventDecoder) DecodeKV(
 		return Row{}, err
 	}
 
+	// Apply the table's masking policy before the row is exposed to changefeed
+	// expressions or encoders.
 	return Row{
 		EventDescriptor: ed,
 		MvccTimestamp:   kv.Value.Timestamp,
 		datums:          datums,
 		deleted:         isDeleted,
 		alloc:           &d.alloc,
-	}, nil
+	}.applyMasks(ed.masks, d.maskKey)
 }
 
 // initForKey initializes decoder state to prepare it to decode
@@ -0,0 +1,138 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdcevent
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// columnMask is a masking function bound to a column of an EventDescriptor.
+type columnMask struct {
+	catpb.ChangefeedMask
+	colIdx int // Index into EventDescriptor.cols.
+}
+
+// resolveMasks binds masks to the columns of this descriptor. Masks for
+// columns which are not part of this descriptor (e.g. columns in a different
+// column family) are ignored.
+func (d *EventDescriptor) resolveMasks(masks []catpb.ChangefeedMask) []columnMask {
+	var res []columnMask
+	for _, m := range masks {
+		if idx, ok := d.colsByName[m.Column]; ok {
+			res = append(res, columnMask{ChangefeedMask: m, colIdx: idx})
+		}
+	}
+	return res
+}
+
+// MaskKey returns the key of the hash mask: the cluster secret, which is
+// never exported with the changefeed's output. Changing the cluster secret
+// changes the hashes emitted from then on.
+func MaskKey(sv *settings.Values) []byte {
+	return []byte(sql.ClusterSecret.Get(sv))
+}
+
+// MaskRow returns a copy of the row with the masks applied to the columns they
+// name. Masks naming columns which are not part of the row are ignored. The
+// hash mask is keyed with hashKey, which must not be empty.
+func MaskRow(r Row, masks []catpb.ChangefeedMask, hashKey []byte) (Row, error) {
+	if !r.IsInitialized() || len(masks) == 0 {
+		return r, nil
+	}
+	return r.applyMasks(r.EventDescriptor.resolveMasks(masks), hashKey)
+}
+
+// applyMasks returns a copy of the row with masks applied. The datums of the
+// receiver are not modified since they may be shared with other rows.
+func (r Row) applyMasks(masks []columnMask, hashKey []byte) (Row, error) {
+	if len(masks) == 0 || !r.HasValues() {
+		return r, nil
+	}
+	datums := append(rowenc.EncDatumRow(nil), r.datums...)
+	for _, m := range masks {
+		col := r.cols[m.colIdx]
+		if col.ord == virtualColOrd {
+			// Virtual columns are always emitted as NULL.
+			continue
+		}
+		// The datum row does not contain virtual columns; account for the
+		// virtual columns which precede this one (see forEachDatum).
+		physicalOrd := col.ord
+		for _, other := range r.cols[:m.colIdx] {
+			if other.ord == virtualColOrd {
+				physicalOrd--
+			}
+		}
+		if physicalOrd >= len(datums) {
+			return Row{}, errors.AssertionFailedf("index [%d] out of range for column %q", physicalOrd, col.Name)
+		}
+		if err := datums[physicalOrd].EnsureDecoded(col.Typ, r.alloc); err != nil {
+			return Row{}, errors.Wrapf(err, "error decoding column %q as type %s", col.Name, col.Typ.String())
+		}
+		masked, err := maskDatum(datums[physicalOrd].Datum, m.ChangefeedMask, hashKey)
+		if err != nil {
+			return Row{}, err
+		}
+		datums[physicalOrd] = rowenc.DatumToEncDatum(col.Typ, masked)
+	}
+	r.datums = datums
+	return r, nil
+}
+
+// maskDatum applies the masking function to the datum. The hash mask computes
+// an HMAC-SHA256 keyed with hashKey: a plain digest of a low entropy value
+// (e.g. an email address or a phone number) is trivially reversed by hashing
+// candidate values, which the key prevents for anyone who does not hold it.
+func maskDatum(d tree.Datum, m catpb.ChangefeedMask, hashKey []byte) (tree.Datum, error) {
+	if d == tree.DNull || m.Func == catpb.ChangefeedMaskNull {
+		return tree.DNull, nil
+	}
+	if m.Func == catpb.ChangefeedMaskHash && len(hashKey) == 0 {
+		return nil, errors.Newf("cannot apply mask %s: %s is not set", m, sql.ClusterSecret.Key())
+	}
+	switch t := d.(type) {
+	case *tree.DString:
+		switch m.Func {
+		case catpb.ChangefeedMaskHash:
+			return tree.NewDString(hex.EncodeToString(keyedHash(hashKey, []byte(*t)))), nil
+		case catpb.ChangefeedMaskTruncate:
+			if runes := []rune(string(*t)); len(runes) > m.Length {
+				return tree.NewDString(string(runes[:m.Length])), nil
+			}
+			return d, nil
+		}
+	case *tree.DBytes:
+		switch m.Func {
+		case catpb.ChangefeedMaskHash:
+			return tree.NewDBytes(tree.DBytes(keyedHash(hashKey, []byte(*t)))), nil
+		case catpb.ChangefeedMaskTruncate:
+			if len(*t) > m.Length {
+				return tree.NewDBytes((*t)[:m.Length]), nil
+			}
+			return d, nil
+		}
+	}
+	return nil, errors.Newf("cannot apply mask %s to datum of type %s", m, d.ResolvedType())
+}
+
+// keyedHash returns the HMAC-SHA256 of v keyed with key.
+func keyedHash(key, v []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(v)
+	return h.Sum(nil)
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdcevent
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskRow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	datums := tree.Datums{
+		tree.NewDInt(1),
+		tree.NewDString("alice@example.com"),
+		tree.NewDBytes("secret"),
+		tree.NewDString("555-1234"),
+		tree.DNull,
+	}
+	names := []string{"id", "email", "token", "phone", "ssn"}
+	ed := &EventDescriptor{colsByName: make(map[string]int)}
+	var encRow rowenc.EncDatumRow
+	for i, d := range datums {
+		ed.cols = append(ed.cols, ResultColumn{
+			ResultColumn: colinfo.ResultColumn{Name: names[i], Typ: d.ResolvedType()},
+			ord:          i,
+		})
+		ed.colsByName[names[i]] = i
+		ed.valueCols = append(ed.valueCols, i)
+		encRow = append(encRow, rowenc.DatumToEncDatum(d.ResolvedType(), d))
+	}
+	var alloc tree.DatumAlloc
+	row := Row{EventDescriptor: ed, datums: encRow, alloc: &alloc}
+
+	masks, err := catpb.ParseChangefeedMasks(
+		"email=hash,token=truncate(3),phone=null,ssn=hash,unknown=null")
+	require.NoError(t, err)
+	key := []byte("secret-key")
+	masked, err := MaskRow(row, masks, key)
+	require.NoError(t, err)
+
+	var res tree.Datums
+	require.NoError(t, masked.ForEachColumn().Datum(func(d tree.Datum, col ResultColumn) error {
+		res = append(res, d)
+		return nil
+	}))
+	require.Equal(t, tree.Datums{
+		tree.NewDInt(1),
+		tree.NewDString("e7c2c6e750de17bc7cf066c0fc31f51881983f668d5cdac45ab43de373588b9e"),
+		tree.NewDBytes("sec"),
+		tree.DNull,
+		tree.DNull,
+	}, res)
+
+	// The original row is not modified.
+	d, err := row.DatumAt(1)
+	require.NoError(t, err)
+	require.Equal(t, tree.NewDString("alice@example.com"), d)
+
+	// The hash depends on the key.
+	hashEmail := []catpb.ChangefeedMask{{Column: "email", Func: catpb.ChangefeedMaskHash}}
+	otherKey, err := MaskRow(row, hashEmail, []byte("other-key"))
+	require.NoError(t, err)
+	d, err = otherKey.DatumAt(1)
+	require.NoError(t, err)
+	require.NotEqual(t, res[1], d)
+
+	// Hashing without a key results in an error.
+	_, err = MaskRow(row, hashEmail, nil)
+	require.Error(t, err)
+
+	// Masks which cannot be applied to a datum result in an error.
+	_, err = MaskRow(row, []catpb.ChangefeedMask{{Column: "id", Func: catpb.ChangefeedMaskHash}}, key)
+	require.Error(t, err)
+}
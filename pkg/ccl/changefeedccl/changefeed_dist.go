@@ -20,10 +20,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprofiler"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/flowinfra"
@@ -215,6 +217,71 @@ func fetchSpansForTables(
 		sd, tableDescs[0], initialHighwater, target, sc)
 }
 
+// makeRangefeedFilter returns the filter to push down into the rangefeed
+// registrations of the changefeed, or nil if the changefeed needs every event.
+// Column families are pushed down only when every target is a single column
+// family; deletes are omitted only when the select clause filters them out, and
+// equality predicates on columns are pushed down from the select clause.
+// The filter is best-effort, and aggregators discard non-matching events
+// regardless.
+func makeRangefeedFilter(
+	tableDescs []catalog.TableDescriptor, details jobspb.ChangefeedDetails,
+) (*kvpb.RangeFeedFilter, error) {
+	var filter kvpb.RangeFeedFilter
+	descByID := make(map[descpb.ID]catalog.TableDescriptor, len(tableDescs))
+	for _, d := range tableDescs {
+		descByID[d.GetID()] = d
+	}
+	familyIDs := make(map[descpb.FamilyID]struct{})
+	for _, target := range details.TargetSpecifications {
+		if target.Type != jobspb.ChangefeedTargetSpecification_COLUMN_FAMILY {
+			familyIDs = nil
+			break
+		}
+		desc, ok := descByID[target.TableID]
+		if !ok {
+			return nil, errors.AssertionFailedf("no descriptor for target table %d", target.TableID)
+		}
+		found := false
+		for _, family := range desc.GetFamilies() {
+			if family.Name == target.FamilyName {
+				familyIDs[family.ID] = struct{}{}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue, "no such family %s", target.FamilyName)
+		}
+	}
+	for id := range familyIDs {
+		filter.FamilyIDs = append(filter.FamilyIDs, uint32(id))
+	}
+	sort.Slice(filter.FamilyIDs, func(i, j int) bool { return filter.FamilyIDs[i] < filter.FamilyIDs[j] })
+
+	if details.Select != "" {
+		sc, err := cdceval.ParseChangefeedExpression(details.Select)
+		if err != nil {
+			return nil, pgerror.Wrap(err, pgcode.InvalidParameterValue,
+				"could not parse changefeed expression")
+		}
+		filter.OmitDeletes = cdceval.ExpressionOmitsDeletes(sc)
+		if len(tableDescs) == 1 {
+			filter.KeyPredicates, filter.ValuePredicates, err =
+				cdceval.ExpressionRangefeedPredicates(sc, tableDescs[0])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(filter.FamilyIDs) == 0 && !filter.OmitDeletes &&
+		len(filter.KeyPredicates) == 0 && len(filter.ValuePredicates) == 0 {
+		return nil, nil
+	}
+	return &filter, nil
+}
+
 var replanChangefeedThreshold = settings.RegisterFloatSetting(
 	settings.TenantWritable,
 	"changefeed.replan_flow_threshold",
@@ -254,6 +321,10 @@ func startDistChangefeed(
 	if err != nil {
 		return err
 	}
+	rangefeedFilter, err := makeRangefeedFilter(tableDescs, details)
+	if err != nil {
+		return err
+	}
 	cfKnobs := execCfg.DistSQLSrv.TestingKnobs.Changefeed
 
 	// Changefeed flows handle transactional consistency themselves.
@@ -262,7 +333,7 @@ func startDistChangefeed(
 	dsp := execCtx.DistSQLPlanner()
 	evalCtx := execCtx.ExtendedEvalContext()
 
-	p, planCtx, err := makePlan(execCtx, jobID, details, initialHighWater, checkpoint, trackedSpans)(ctx, dsp)
+	p, planCtx, err := makePlan(execCtx, jobID, details, initialHighWater, checkpoint, trackedSpans, rangefeedFilter)(ctx, dsp)
 	if err != nil {
 		return err
 	}
@@ -278,7 +349,7 @@ func startDistChangefeed(
 
 	replanner, stopReplanner := sql.PhysicalPlanChangeChecker(ctx,
 		p,
-		makePlan(execCtx, jobID, details, initialHighWater, checkpoint, trackedSpans),
+		makePlan(execCtx, jobID, details, initialHighWater, checkpoint, trackedSpans, rangefeedFilter),
 		execCtx,
 		replanOracle,
 		func() time.Duration { return replanChangefeedFrequency.Get(execCtx.ExecCfg().SV()) },
@@ -346,6 +417,7 @@ func makePlan(
 	initialHighWater hlc.Timestamp,
 	checkpoint jobspb.ChangefeedProgress_Checkpoint,
 	trackedSpans []roachpb.Span,
+	rangefeedFilter *kvpb.RangeFeedFilter,
 ) func(context.Context, *sql.DistSQLPlanner) (*sql.PhysicalPlan, *sql.PlanningCtx, error) {
 	return func(ctx context.Context, dsp *sql.DistSQLPlanner) (*sql.PhysicalPlan, *sql.PlanningCtx, error) {
 		var blankTxn *kv.Txn
@@ -423,6 +495,8 @@ func makePlan(
 				UserProto:  execCtx.User().EncodeProto(),
 				JobID:      jobID,
 				Select:     execinfrapb.Expression{Expr: details.Select},
+
+				RangefeedFilter: rangefeedFilter,
 			}
 		}
 
@@ -414,6 +414,7 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		SchemaFeed:              sf,
 		Knobs:                   ca.knobs.FeedKnobs,
 		UseMux:                  changefeedbase.UseMuxRangeFeed.Get(&cfg.Settings.SV),
+		RangefeedFilter:         ca.spec.RangefeedFilter,
 	}, nil
 }
 
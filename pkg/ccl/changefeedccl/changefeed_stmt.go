@@ -49,6 +49,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
@@ -263,7 +264,6 @@ func changefeedPlanHook(
 		// are removed in OnFailOrCancel. See
 		// changeFrontier.manageProtectedTimestamps for more details on the handling
 		// of protected timestamps.
-		var sj *jobs.StartableJob
 		jobID := p.ExecCfg().JobRegistry.MakeJobID()
 		{
 			var ptr *ptpb.Record
@@ -306,29 +306,11 @@ func changefeedPlanHook(
 				}
 			}
 
-			if err := p.ExecCfg().InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
-				if err := p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(ctx, &sj, jobID, txn, *jr); err != nil {
-					return err
-				}
-				if ptr != nil {
-					return p.ExecCfg().ProtectedTimestampProvider.WithTxn(txn).Protect(ctx, ptr)
-				}
-				return nil
-			}); err != nil {
-				if sj != nil {
-					if err := sj.CleanupOnRollback(ctx); err != nil {
-						log.Warningf(ctx, "failed to cleanup aborted job: %v", err)
-					}
-				}
+			if err := createAndStartChangefeedJob(ctx, p, jobID, jr, ptr); err != nil {
 				return err
 			}
 		}
 
-		// Start the job.
-		if err := sj.Start(ctx); err != nil {
-			return err
-		}
-
 		logChangefeedCreateTelemetry(ctx, jr, changefeedStmt.Select != nil)
 
 		select {
@@ -351,6 +333,33 @@ func changefeedPlanHook(
 	return rowFnLogErrors, header, nil, avoidBuffering, nil
 }
 
+// createAndStartChangefeedJob creates the job described by jr along with its
+// protected timestamp record, if any, and starts it.
+func createAndStartChangefeedJob(
+	ctx context.Context, p sql.PlanHookState, jobID jobspb.JobID, jr *jobs.Record, ptr *ptpb.Record,
+) error {
+	var sj *jobs.StartableJob
+	if err := p.ExecCfg().InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		if err := p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(ctx, &sj, jobID, txn, *jr); err != nil {
+			return err
+		}
+		if ptr != nil {
+			return p.ExecCfg().ProtectedTimestampProvider.WithTxn(txn).Protect(ctx, ptr)
+		}
+		return nil
+	}); err != nil {
+		if sj != nil {
+			if err := sj.CleanupOnRollback(ctx); err != nil {
+				log.Warningf(ctx, "failed to cleanup aborted job: %v", err)
+			}
+		}
+		return err
+	}
+
+	// Start the job.
+	return sj.Start(ctx)
+}
+
 func createChangefeedJobRecord(
 	ctx context.Context,
 	p sql.PlanHookState,
@@ -1063,7 +1072,19 @@ func (b *changefeedResumer) Resume(ctx context.Context, execCtx interface{}) err
 	details := b.job.Details().(jobspb.ChangefeedDetails)
 	progress := b.job.Progress()
 
+	// Backfills requested by ALTER CHANGEFEED ... BACKFILL run alongside the
+	// changefeed's own flow, until it stops.
+	backfillCtx, cancelBackfills := context.WithCancel(ctx)
+	defer cancelBackfills()
+	backfills := ctxgroup.WithContext(backfillCtx)
+	backfills.GoCtx(func(ctx context.Context) error {
+		b.runBackfills(ctx, jobExec)
+		return nil
+	})
+
 	err := b.resumeWithRetries(ctx, jobExec, jobID, details, progress, execCfg)
+	cancelBackfills()
+	_ = backfills.Wait()
 	if err != nil {
 		return b.handleChangefeedError(ctx, err, details, jobExec)
 	}
@@ -1237,6 +1258,95 @@ func (b *changefeedResumer) resumeWithRetries(
 	return errors.Wrap(ctx.Err(), `ran out of retries`)
 }
 
+// runBackfills performs the backfills requested by ALTER CHANGEFEED ...
+// BACKFILL, which it polls for in the details of the job, until ctx is
+// canceled. A backfill which fails with a retryable error is retried at the
+// next poll; otherwise, it is removed from the job once it is done.
+func (b *changefeedResumer) runBackfills(ctx context.Context, jobExec sql.JobExecContext) {
+	execCfg := jobExec.ExecCfg()
+	timer := timeutil.NewTimer()
+	defer timer.Stop()
+	for {
+		timer.Reset(changefeedbase.BackfillPollInterval.Get(&execCfg.Settings.SV))
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			timer.Read = true
+		}
+
+		job, err := execCfg.JobRegistry.LoadClaimedJob(ctx, b.job.ID())
+		if err != nil {
+			log.Warningf(ctx, `CHANGEFEED job %d could not load its backfills: %v`, b.job.ID(), err)
+			continue
+		}
+		for _, backfill := range job.Details().(jobspb.ChangefeedDetails).Backfills {
+			err := b.runBackfill(ctx, jobExec, backfill)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil && changefeedbase.AsTerminalError(ctx, execCfg.LeaseManager, err) == nil {
+				log.Warningf(ctx, `CHANGEFEED job %d backfill %q encountered retryable error: %v`,
+					b.job.ID(), backfill.Description, err)
+				continue
+			}
+			b.finishBackfill(ctx, execCfg, backfill, err)
+		}
+	}
+}
+
+// runBackfill performs a backfill requested by ALTER CHANGEFEED ... BACKFILL.
+//
+// The scan runs as a flow without a job, as the flow of a sinkless changefeed
+// does, so that it neither checkpoints into the progress of the changefeed nor
+// manages its protected timestamp record. With a sink, the flow only reports
+// the completion of its setup on the results channel.
+func (b *changefeedResumer) runBackfill(
+	ctx context.Context, jobExec sql.JobExecContext, backfill jobspb.ChangefeedBackfill,
+) error {
+	log.Infof(ctx, `CHANGEFEED job %d running backfill %q`, b.job.ID(), backfill.Description)
+	resultsCh := make(chan tree.Datums, 1)
+	return distChangefeedFlow(
+		ctx, jobExec, jobspb.InvalidJobID, backfill.Details, jobspb.Progress{}, resultsCh,
+	)
+}
+
+// finishBackfill removes a backfill, which failed with backfillErr if it is
+// not nil, from the job, and releases its protected timestamp record.
+func (b *changefeedResumer) finishBackfill(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	backfill jobspb.ChangefeedBackfill,
+	backfillErr error,
+) {
+	if backfillErr != nil {
+		log.Warningf(ctx, `CHANGEFEED job %d backfill %q failed: %v`,
+			b.job.ID(), backfill.Description, backfillErr)
+	} else {
+		log.Infof(ctx, `CHANGEFEED job %d completed backfill %q`, b.job.ID(), backfill.Description)
+	}
+	if err := b.job.NoTxn().Update(ctx, func(
+		txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		details := md.Payload.GetChangefeed()
+		for i := range details.Backfills {
+			if details.Backfills[i].ProtectedTimestampRecord == backfill.ProtectedTimestampRecord {
+				details.Backfills = append(details.Backfills[:i], details.Backfills[i+1:]...)
+				break
+			}
+		}
+		ju.UpdatePayload(md.Payload)
+		err := execCfg.ProtectedTimestampProvider.WithTxn(txn).Release(ctx, backfill.ProtectedTimestampRecord)
+		if errors.Is(err, protectedts.ErrNotExists) {
+			return nil
+		}
+		return err
+	}); err != nil {
+		log.Warningf(ctx, `CHANGEFEED job %d could not remove backfill %q: %v`,
+			b.job.ID(), backfill.Description, err)
+	}
+}
+
 // OnFailOrCancel is part of the jobs.Resumer interface.
 func (b *changefeedResumer) OnFailOrCancel(
 	ctx context.Context, jobExec interface{}, _ error,
@@ -1250,6 +1360,14 @@ func (b *changefeedResumer) OnFailOrCancel(
 		execCfg.ProtectedTimestampProvider,
 		progress.GetChangefeed().ProtectedTimestampRecord,
 	)
+	for _, backfill := range b.job.Details().(jobspb.ChangefeedDetails).Backfills {
+		b.maybeCleanUpProtectedTimestamp(
+			ctx,
+			execCfg.InternalDB,
+			execCfg.ProtectedTimestampProvider,
+			backfill.ProtectedTimestampRecord,
+		)
+	}
 
 	// If this job has failed (not canceled), increment the counter.
 	if jobs.HasErrJobCanceled(
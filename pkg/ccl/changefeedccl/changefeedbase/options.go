@@ -14,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/errors"
@@ -101,6 +102,9 @@ const (
 	OptUnordered                = `unordered`
 	OptVirtualColumns           = `virtual_columns`
 	OptExecutionLocality        = `execution_locality`
+	OptTxnMetadata              = `txn_metadata`
+	OptTxnBoundaries            = `txn_boundaries`
+	OptMask                     = `mask`
 
 	OptVirtualColumnsOmitted VirtualColumnVisibility = `omitted`
 	OptVirtualColumnsNull    VirtualColumnVisibility = `null`
@@ -176,8 +180,12 @@ const (
 	SinkParamClientCert             = `client_cert`
 	SinkParamClientKey              = `client_key`
 	SinkParamFileSize               = `file_size`
+	SinkParamKeyPrefixColumns       = `key_prefix_columns`
+	SinkParamMaxLen                 = `maxlen`
+	SinkParamMaxLenApproximate      = `maxlen_approximate`
 	SinkParamPartitionFormat        = `partition_format`
 	SinkParamSchemaTopic            = `schema_topic`
+	SinkParamShards                 = `shards`
 	SinkParamTLSEnabled             = `tls_enabled`
 	SinkParamSkipTLSVerify          = `insecure_tls_skip_verify`
 	SinkParamTopicPrefix            = `topic_prefix`
@@ -193,6 +201,7 @@ const (
 	SinkSchemeHTTPS                 = `https`
 	SinkSchemeKafka                 = `kafka`
 	SinkSchemeNull                  = `null`
+	SinkSchemeRedis                 = `redis`
 	SinkSchemeWebhookHTTP           = `webhook-http`
 	SinkSchemeWebhookHTTPS          = `webhook-https`
 	SinkSchemeExternalConnection    = `external`
@@ -343,6 +352,9 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptUnordered:                flagOption,
 	OptVirtualColumns:           enum("omitted", "null"),
 	OptExecutionLocality:        stringOption,
+	OptTxnMetadata:              flagOption,
+	OptTxnBoundaries:            flagOption,
+	OptMask:                     stringOption,
 }
 
 // CommonOptions is options common to all sinks
@@ -355,7 +367,7 @@ var CommonOptions = makeStringSet(OptCursor, OptEndTime, OptEnvelope,
 	OptProtectDataFromGCOnPause, OptOnError,
 	OptInitialScan, OptNoInitialScan, OptInitialScanOnly, OptUnordered, OptCustomKeyColumn,
 	OptMinCheckpointFrequency, OptMetricsScope, OptVirtualColumns, Topics, OptExpirePTSAfter,
-	OptExecutionLocality,
+	OptExecutionLocality, OptTxnMetadata, OptTxnBoundaries, OptMask,
 )
 
 // SQLValidOptions is options exclusive to SQL sink
@@ -373,6 +385,9 @@ var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTi
 // PubsubValidOptions is options exclusive to pubsub sink
 var PubsubValidOptions = makeStringSet(OptPubsubSinkConfig)
 
+// RedisValidOptions is options exclusive to the redis sink
+var RedisValidOptions map[string]struct{} = nil
+
 // ExternalConnectionValidOptions is options exclusive to the external
 // connection sink.
 //
@@ -426,7 +441,7 @@ var NoLongerExperimental = map[string]string{
 // InitialScanOnlyUnsupportedOptions is options that are not supported with the
 // initial scan only option
 var InitialScanOnlyUnsupportedOptions = makeStringSet(OptEndTime, OptResolvedTimestamps, OptDiff,
-	OptMVCCTimestamps, OptUpdatedTimestamps)
+	OptMVCCTimestamps, OptUpdatedTimestamps, OptTxnMetadata, OptTxnBoundaries)
 
 // AlterChangefeedUnsupportedOptions are changefeed options that we do not allow
 // users to alter.
@@ -437,6 +452,20 @@ var InitialScanOnlyUnsupportedOptions = makeStringSet(OptEndTime, OptResolvedTim
 var AlterChangefeedUnsupportedOptions = makeStringSet(OptCursor, OptInitialScan,
 	OptNoInitialScan, OptInitialScanOnly, OptEndTime)
 
+// AlterChangefeedBackfillUnsupportedOptions are changefeed options that may
+// not be specified for ALTER CHANGEFEED ... BACKFILL, which always performs
+// only an initial scan.
+var AlterChangefeedBackfillUnsupportedOptions = makeStringSet(OptInitialScan,
+	OptNoInitialScan, OptInitialScanOnly, OptEndTime)
+
+// AlterChangefeedBackfillInheritedOptions are the options of a changefeed
+// which are copied to the changefeeds started by ALTER CHANGEFEED ... BACKFILL,
+// so that the backfill is encoded exactly like the changefeed's own messages.
+// Options explicitly specified for the backfill take precedence.
+var AlterChangefeedBackfillInheritedOptions = makeStringSet(OptEnvelope,
+	OptFormat, OptFullTableName, OptKeyInValue, OptTopicInValue,
+	OptSplitColumnFamilies, OptCustomKeyColumn, OptVirtualColumns, OptMask)
+
 // AlterChangefeedOptionExpectValues is used to parse alter changefeed options
 // using PlanHookState.TypeAsStringOpts().
 var AlterChangefeedOptionExpectValues = func() map[string]OptionPermittedValues {
@@ -490,6 +519,7 @@ var incompatibleOptionsMap = makeInvertedIndex([]incompatibleOptions{
 
 var dependentOptionsMap = makeDirectedInvertedIndex([]dependentOption{
 	{opt1: OptCustomKeyColumn, opt2: OptUnordered, reason: `using a value other than the primary key as the message key means end-to-end ordering cannot be preserved`},
+	{opt1: OptTxnBoundaries, opt2: OptTxnMetadata, reason: `transaction boundary markers refer to the transaction identifiers added to each row`},
 })
 
 // MakeStatementOptions wraps and canonicalizes the options we get
@@ -704,6 +734,9 @@ type CanHandle struct {
 	MultipleColumnFamilies bool
 	VirtualColumns         bool
 	RequiredColumns        []string
+	// Masks are the masks requested with the mask option. They are applied in
+	// addition to the masking policy of each table.
+	Masks []catpb.ChangefeedMask
 }
 
 // GetCanHandle returns a populated CanHandle.
@@ -717,9 +750,20 @@ func (s StatementOptions) GetCanHandle() CanHandle {
 	if s.IsSet(OptCustomKeyColumn) {
 		h.RequiredColumns = append(h.RequiredColumns, s.m[OptCustomKeyColumn])
 	}
+	// The mask option is validated when the changefeed is created.
+	h.Masks, _ = s.GetMasks()
 	return h
 }
 
+// GetMasks returns the masks requested with the mask option.
+func (s StatementOptions) GetMasks() ([]catpb.ChangefeedMask, error) {
+	masks, err := catpb.ParseChangefeedMasks(s.m[OptMask])
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem parsing option %s", OptMask)
+	}
+	return masks, nil
+}
+
 // EncodingOptions describe how events are encoded when
 // sent to the sink.
 type EncodingOptions struct {
@@ -735,6 +779,12 @@ type EncodingOptions struct {
 	SchemaRegistryURI string
 	Compression       string
 	CustomKeyColumn   string
+	// TxnMetadata, if set, tags each row with the identifier of the
+	// transaction that wrote it along with its position in that transaction.
+	TxnMetadata bool
+	// TxnBoundaries, if set, emits a marker message once all rows of a
+	// transaction have been emitted.
+	TxnBoundaries bool
 }
 
 // GetEncodingOptions populates and validates an EncodingOptions.
@@ -776,6 +826,8 @@ func (s StatementOptions) GetEncodingOptions() (EncodingOptions, error) {
 	_, o.UpdatedTimestamps = s.m[OptUpdatedTimestamps]
 	_, o.MVCCTimestamps = s.m[OptMVCCTimestamps]
 	_, o.Diff = s.m[OptDiff]
+	_, o.TxnMetadata = s.m[OptTxnMetadata]
+	_, o.TxnBoundaries = s.m[OptTxnBoundaries]
 
 	o.SchemaRegistryURI = s.m[OptConfluentSchemaRegistry]
 	o.AvroSchemaPrefix = s.m[OptAvroSchemaPrefix]
@@ -811,6 +863,16 @@ func (e EncodingOptions) Validate() error {
 			}
 		}
 	}
+	if e.TxnMetadata {
+		if e.Format != OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`,
+				OptTxnMetadata, OptFormat, OptFormatJSON)
+		}
+		if e.Envelope != OptEnvelopeWrapped && e.Envelope != OptEnvelopeBare {
+			return errors.Errorf(`%s is only usable with %s=%s or %s=%s`,
+				OptTxnMetadata, OptEnvelope, OptEnvelopeWrapped, OptEnvelope, OptEnvelopeBare)
+		}
+	}
 	return nil
 }
 
@@ -1035,6 +1097,9 @@ func (s StatementOptions) ValidateForCreateChangefeed(isPredicateChangefeed bool
 	if err != nil {
 		return err
 	}
+	if _, err := s.GetMasks(); err != nil {
+		return err
+	}
 	scanType, err := s.GetInitialScanType()
 	if err != nil {
 		return err
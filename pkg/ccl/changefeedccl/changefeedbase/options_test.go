@@ -41,6 +41,11 @@ func TestOptionsValidations(t *testing.T) {
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"key_column": "b"}, false, "requires the unordered option"},
 		{map[string]string{"diff": "", "format": "parquet"}, true, ""},
+		{map[string]string{"txn_boundaries": ""}, false, "requires the txn_metadata option"},
+		{map[string]string{"txn_metadata": "", "txn_boundaries": ""}, false, ""},
+		{map[string]string{"txn_metadata": "", "initial_scan": "only"}, false, "cannot specify both initial_scan='only'"},
+		{map[string]string{"mask": "email=hash,phone=truncate(3)"}, false, ""},
+		{map[string]string{"mask": "email=rot13"}, false, "problem parsing option mask"},
 	}
 
 	for _, test := range tests {
@@ -70,6 +70,16 @@ var IdleTimeout = settings.RegisterDurationSetting(
 	settings.NonNegativeDuration,
 )
 
+// BackfillPollInterval controls how often a changefeed checks for backfills
+// requested by ALTER CHANGEFEED ... BACKFILL.
+var BackfillPollInterval = settings.RegisterDurationSetting(
+	settings.TenantWritable,
+	"changefeed.backfill_poll_interval",
+	"the interval at which a changefeed checks for backfills requested by ALTER CHANGEFEED ... BACKFILL",
+	10*time.Second,
+	settings.PositiveDuration,
+)
+
 // FrontierCheckpointFrequency controls the frequency of frontier checkpoints.
 var FrontierCheckpointFrequency = settings.RegisterDurationSetting(
 	settings.TenantWritable,
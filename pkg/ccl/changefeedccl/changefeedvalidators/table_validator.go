@@ -12,6 +12,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/errors"
 )
 
@@ -91,10 +93,41 @@ func validateTable(
 			return errors.Errorf("required column %s not present on table %s", requiredColumn, tableDesc.GetName())
 		}
 	}
+	if err := validateMasks(tableDesc, canHandle.Masks); err != nil {
+		return err
+	}
 
 	return err
 }
 
+// validateMasks checks that the masking policy of the table still applies to
+// the table and that the masks requested by the changefeed can be applied to
+// the columns they name. The masking policy refers to columns by name, so a
+// changefeed must stop rather than emit unmasked values once a masked column
+// is renamed or dropped.
+func validateMasks(tableDesc catalog.TableDescriptor, feedMasks []catpb.ChangefeedMask) error {
+	if policy := tableDesc.GetChangefeedMask(); policy != "" {
+		masks, err := catpb.ParseChangefeedMasks(policy)
+		if err != nil {
+			return err
+		}
+		if err := tabledesc.ValidateChangefeedMasks(tableDesc, masks); err != nil {
+			return errors.Wrapf(err, "CHANGEFEED cannot enforce the %s of table %s",
+				catpb.ChangefeedMaskTableSettingName, tableDesc.GetName())
+		}
+	}
+	// Masks requested by the changefeed apply to any target table which has a
+	// column of that name.
+	for _, m := range feedMasks {
+		if col := catalog.FindColumnByName(tableDesc, m.Column); col != nil {
+			if err := tabledesc.ValidateChangefeedMask(col, m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // WarningsForTable returns any known nonfatal issues with running a changefeed on this kind of table.
 func WarningsForTable(
 	tableDesc catalog.TableDescriptor, canHandle changefeedbase.CanHandle,
@@ -49,7 +49,19 @@ func getEncoder(
 ) (Encoder, error) {
 	switch opts.Format {
 	case changefeedbase.OptFormatJSON:
-		return makeJSONEncoder(opts)
+		e, err := makeJSONEncoder(opts)
+		if err != nil {
+			return nil, err
+		}
+		if opts.SchemaRegistryURI != "" {
+			reg, err := newSchemaRegistry(opts.SchemaRegistryURI, p, sliMetrics)
+			if err != nil {
+				return nil, err
+			}
+			e.schemaRegistry = reg
+			e.targets = targets
+		}
+		return e, nil
 	case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro:
 		return newConfluentAvroEncoder(opts, targets, p, sliMetrics)
 	case changefeedbase.OptFormatCSV:
@@ -37,12 +37,20 @@ import (
 // stored in a sub-object under the `__crdb__` key in the top-level JSON object.
 type jsonEncoder struct {
 	updatedField, mvccTimestampField, beforeField, keyInValue, topicInValue bool
+	txnField                                                                bool
 	envelopeType                                                            changefeedbase.EnvelopeType
 
 	buf             bytes.Buffer
 	versionEncoder  func(ed *cdcevent.EventDescriptor) *versionEncoder
 	envelopeEncoder func(evCtx eventContext, updated, prev cdcevent.Row) (json.JSON, error)
 	customKeyColumn string
+
+	// schemaRegistry, if set, is where the JSON Schemas of the keys and values
+	// of each table version are registered before they are first emitted.
+	// Registration lets the registry check that schema changes are compatible
+	// with the configured policy; messages are not modified.
+	schemaRegistry schemaRegistry
+	targets        changefeedbase.Targets
 }
 
 var _ Encoder = &jsonEncoder{}
@@ -66,6 +74,7 @@ func makeJSONEncoder(opts changefeedbase.EncodingOptions) (*jsonEncoder, error)
 		beforeField:  opts.Diff && opts.Envelope != changefeedbase.OptEnvelopeBare,
 		keyInValue:   opts.KeyInValue,
 		topicInValue: opts.TopicInValue,
+		txnField:     opts.TxnMetadata,
 		versionEncoder: func(ed *cdcevent.EventDescriptor) *versionEncoder {
 			key := cdcevent.CacheKey{
 				ID:       ed.TableID,
@@ -87,6 +96,10 @@ func makeJSONEncoder(opts changefeedbase.EncodingOptions) (*jsonEncoder, error)
 			return nil, errors.Errorf(`%s is only usable with %s=%s`,
 				changefeedbase.OptTopicInValue, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 		}
+		if e.txnField {
+			return nil, errors.Errorf(`%s is only usable with %s=%s`,
+				changefeedbase.OptTxnMetadata, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+		}
 	}
 
 	if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
@@ -105,19 +118,82 @@ func makeJSONEncoder(opts changefeedbase.EncodingOptions) (*jsonEncoder, error)
 // versionEncoder memoizes version specific encoding state.
 type versionEncoder struct {
 	valueBuilder *json.FixedKeysObjectBuilder
+	// registered is set once the schemas of this version have been
+	// registered with the jsonEncoder's schema registry.
+	registered bool
 }
 
-// EncodeKey implements the Encoder interface.
-func (e *jsonEncoder) EncodeKey(_ context.Context, row cdcevent.Row) (enc []byte, err error) {
-	var keys cdcevent.Iterator
+func (e *jsonEncoder) keyColumns(row cdcevent.Row) (cdcevent.Iterator, error) {
 	if e.customKeyColumn == "" {
-		keys = row.ForEachKeyColumn()
-	} else {
-		keys, err = row.DatumNamed(e.customKeyColumn)
+		return row.ForEachKeyColumn(), nil
+	}
+	return row.DatumNamed(e.customKeyColumn)
+}
+
+// maybeRegisterSchemas registers the JSON Schemas of the key and value of
+// the version of row, unless they have already been registered. The subjects
+// follow the naming of the Avro encoder so that they match the Kafka topics.
+func (e *jsonEncoder) maybeRegisterSchemas(ctx context.Context, row cdcevent.Row) error {
+	if e.schemaRegistry == nil {
+		return nil
+	}
+	ve := e.versionEncoder(row.EventDescriptor)
+	if ve.registered {
+		return nil
+	}
+
+	tableName, err := schemaTableName(e.targets, "", row.Metadata)
+	if err != nil {
+		return err
+	}
+	keys, err := e.keyColumns(row)
+	if err != nil {
+		return err
+	}
+	keySchema, err := keyJSONSchema(tableName, keys)
+	if err != nil {
+		return err
+	}
+	subject := SQLNameToKafkaName(tableName)
+	schema, err := marshalJSONSchema(keySchema)
+	if err != nil {
+		return err
+	}
+	if _, err := e.schemaRegistry.RegisterSchemaForSubject(
+		ctx, subject+confluentSubjectSuffixKey, schemaTypeJSON, schema,
+	); err != nil {
+		return err
+	}
+
+	if e.envelopeType != changefeedbase.OptEnvelopeKeyOnly {
+		valueSchema, err := e.valueJSONSchema(tableName, row, keySchema)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		schema, err := marshalJSONSchema(valueSchema)
+		if err != nil {
+			return err
+		}
+		if _, err := e.schemaRegistry.RegisterSchemaForSubject(
+			ctx, subject+confluentSubjectSuffixValue, schemaTypeJSON, schema,
+		); err != nil {
+			return err
 		}
 	}
+
+	ve.registered = true
+	return nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *jsonEncoder) EncodeKey(ctx context.Context, row cdcevent.Row) (enc []byte, err error) {
+	if err := e.maybeRegisterSchemas(ctx, row); err != nil {
+		return nil, err
+	}
+	keys, err := e.keyColumns(row)
+	if err != nil {
+		return nil, err
+	}
 	j, err := e.versionEncoder(row.EventDescriptor).encodeKeyRaw(keys)
 	if err != nil {
 		return nil, err
@@ -221,6 +297,9 @@ func (e *jsonEncoder) initRawEnvelope() error {
 	if e.topicInValue {
 		metaKeys = append(metaKeys, "topic")
 	}
+	if e.txnField {
+		metaKeys = append(metaKeys, "txn")
+	}
 
 	// Setup builder for crdb meta if needed.
 	var metaBuilder *json.FixedKeysObjectBuilder
@@ -265,6 +344,12 @@ func (e *jsonEncoder) initRawEnvelope() error {
 			}
 		}
 
+		if e.txnField {
+			if err := metaBuilder.Set("txn", encodeTxnMetadata(evCtx.txn)); err != nil {
+				return nil, err
+			}
+		}
+
 		meta, err := metaBuilder.Build()
 		if err != nil {
 			return nil, err
@@ -291,6 +376,9 @@ func (e *jsonEncoder) initWrappedEnvelope() error {
 	if e.mvccTimestampField {
 		keys = append(keys, "mvcc_timestamp")
 	}
+	if e.txnField {
+		keys = append(keys, "txn")
+	}
 	b, err := json.NewFixedKeysObjectBuilder(keys)
 	if err != nil {
 		return err
@@ -346,6 +434,12 @@ func (e *jsonEncoder) initWrappedEnvelope() error {
 			}
 		}
 
+		if e.txnField {
+			if err := b.Set("txn", encodeTxnMetadata(evCtx.txn)); err != nil {
+				return nil, err
+			}
+		}
+
 		return b.Build()
 	}
 	return nil
@@ -390,6 +484,49 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 	return gojson.Marshal(jsonEntries)
 }
 
+// encodeTxnMetadata returns the JSON representation of the transaction which
+// wrote a row.
+func encodeTxnMetadata(txn txnMetadata) json.JSON {
+	b := json.NewObjectBuilder(2)
+	b.Add("id", json.FromString(timestampToString(txn.id)))
+	b.Add("row", json.FromInt64(txn.row))
+	return b.Build()
+}
+
+// EncodeTxnBoundary implements the txnBoundaryEncoder interface. The key of
+// the marker is the transaction identifier; the value holds the number of
+// rows of the transaction emitted to the marker's topic.
+func (e *jsonEncoder) EncodeTxnBoundary(
+	_ context.Context, b txnBoundary,
+) (key, value []byte, _ error) {
+	id := timestampToString(b.id)
+	key, err := gojson.Marshal([]interface{}{id})
+	if err != nil {
+		return nil, nil, err
+	}
+	meta := map[string]interface{}{
+		`txn_boundary`: map[string]interface{}{
+			`id`:        id,
+			`row_count`: b.rowCount,
+		},
+	}
+	var jsonEntries interface{}
+	if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
+		jsonEntries = meta
+	} else {
+		jsonEntries = map[string]interface{}{
+			jsonMetaSentinel: meta,
+		}
+	}
+	value, err = gojson.Marshal(jsonEntries)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, value, nil
+}
+
+var _ txnBoundaryEncoder = &jsonEncoder{}
+
 var placeholderCtx = eventContext{topic: "topic"}
 
 // EncodeAsJSONChangefeedWithFlags implements the crdb_internal.to_json_as_changefeed_with_flags
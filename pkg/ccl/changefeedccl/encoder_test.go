@@ -750,6 +750,59 @@ func TestAvroSchemaNamespace(t *testing.T) {
 	cdcTest(t, testFn, feedTestForceSink("kafka"))
 }
 
+func TestJSONSchemaRegistration(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		// Keep a single encoder so that schemas are registered once per version.
+		changefeedbase.EventConsumerWorkers.Override(
+			context.Background(), &s.Server.ClusterSettings().SV, -1)
+
+		sqlDB.Exec(t, `CREATE DATABASE movr`)
+		sqlDB.Exec(t, `CREATE TABLE movr.drivers (id INT PRIMARY KEY, name STRING, rating DECIMAL, tags STRING[])`)
+		sqlDB.Exec(t, `INSERT INTO movr.drivers VALUES (1, 'Alice', 4.5, ARRAY['new'])`)
+
+		reg := cdctest.StartTestSchemaRegistry()
+		defer reg.Close()
+
+		drivers := feed(t, f, fmt.Sprintf(`CREATE CHANGEFEED FOR movr.drivers `+
+			`WITH format=%s, %s='%s', updated`,
+			changefeedbase.OptFormatJSON, changefeedbase.OptConfluentSchemaRegistry, reg.URL()))
+		defer closeFeed(t, drivers)
+
+		// Messages are unchanged by the registration of their schemas.
+		assertPayloadsStripTs(t, drivers, []string{
+			`drivers: [1]->{"after": {"id": 1, "name": "Alice", "rating": 4.5, "tags": ["new"]}}`,
+		})
+		assertRegisteredSubjects(t, reg, []string{
+			`drivers-key`,
+			`drivers-value`,
+		})
+		require.Equal(t,
+			`{"$schema":"http://json-schema.org/draft-07/schema#","additionalItems":false,`+
+				`"items":[{"type":"integer"}],"minItems":1,"title":"drivers","type":"array"}`,
+			reg.SchemaForSubject(`drivers-key`))
+		value := reg.SchemaForSubject(`drivers-value`)
+		require.Contains(t, value, `"after":{"oneOf":[{"type":"null"},{"properties":{`)
+		require.Contains(t, value, `"rating":{"oneOf":[{"type":"null"},{"type":["number","string"]}]}`)
+		require.Contains(t, value, `"tags":{"oneOf":[{"type":"null"},{"items":{"oneOf":[{"type":"null"},{"type":"string"}]},"type":"array"}]}`)
+		require.Contains(t, value, `"updated":{"type":"string"}`)
+		require.NotContains(t, value, `"before"`)
+
+		// A new table version registers new schemas.
+		sqlDB.Exec(t, `ALTER TABLE movr.drivers ADD COLUMN active BOOL DEFAULT true`)
+		assertPayloadsStripTs(t, drivers, []string{
+			`drivers: [1]->{"after": {"active": true, "id": 1, "name": "Alice", "rating": 4.5, "tags": ["new"]}}`,
+		})
+		require.Contains(t, reg.SchemaForSubject(`drivers-value`),
+			`"active":{"oneOf":[{"type":"null"},{"type":"boolean"}]}`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
 func TestTableNameCollision(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -47,6 +48,8 @@ type eventContext struct {
 	updated, mvcc hlc.Timestamp
 	// topic is set to the string to be included if TopicInValue is true
 	topic string
+	// txn is set if TxnMetadata is true.
+	txn txnMetadata
 }
 
 type eventConsumer interface {
@@ -72,6 +75,15 @@ type kvEventToRowConsumer struct {
 	topicDescriptorCache map[TopicIdentifier]TopicDescriptor
 	topicNamer           *TopicNamer
 
+	// masks are the masks requested with the mask option; the masking
+	// policies of the tables are applied by the decoder.
+	masks   []catpb.ChangefeedMask
+	maskKey []byte
+
+	// txnTracker is used when the txn_metadata option is set.
+	txnTracker    *txnTracker
+	txnBoundaries bool
+
 	metrics *sliMetrics
 
 	// This pacer is used to incorporate event consumption to elastic CPU
@@ -161,8 +173,12 @@ func newEventConsumer(
 	// does not work for parquet format.
 	//
 	// TODO (jayshrivastava) enable parallel consumers for sinkless changefeeds.
+	//
+	// Transaction metadata requires a single consumer to see every row emitted
+	// by this aggregator so that it can count the rows of each transaction.
 	isSinkless := spec.JobID == 0
-	if numWorkers <= 1 || isSinkless || encodingOpts.Format == changefeedbase.OptFormatParquet {
+	if numWorkers <= 1 || isSinkless || encodingOpts.Format == changefeedbase.OptFormatParquet ||
+		encodingOpts.TxnMetadata {
 		c, err := makeConsumer(sink, spanFrontier)
 		if err != nil {
 			return nil, nil, err
@@ -245,6 +261,17 @@ func newKVEventToRowConsumer(
 		return nil, err
 	}
 
+	masks, err := details.Opts.GetMasks()
+	if err != nil {
+		return nil, err
+	}
+
+	var tracker *txnTracker
+	if encodingOpts.TxnMetadata {
+		t := makeTxnTracker()
+		tracker = &t
+	}
+
 	return &kvEventToRowConsumer{
 		frontier:             frontier,
 		encoder:              encoder,
@@ -255,6 +282,10 @@ func newKVEventToRowConsumer(
 		knobs:                knobs,
 		topicDescriptorCache: make(map[TopicIdentifier]TopicDescriptor),
 		topicNamer:           topicNamer,
+		masks:                masks,
+		maskKey:              cdcevent.MaskKey(&cfg.Settings.SV),
+		txnTracker:           tracker,
+		txnBoundaries:        encodingOpts.TxnBoundaries,
 		evaluator:            evaluator,
 		encodingFormat:       encodingOpts.Format,
 		metrics:              metrics,
@@ -402,6 +433,15 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 		return err
 	}
 
+	if len(c.masks) > 0 {
+		if updatedRow, err = cdcevent.MaskRow(updatedRow, c.masks, c.maskKey); err != nil {
+			return err
+		}
+		if prevRow, err = cdcevent.MaskRow(prevRow, c.masks, c.maskKey); err != nil {
+			return err
+		}
+	}
+
 	// Ensure that r updates are strictly newer than the least resolved timestamp
 	// being tracked by the local span frontier. The poller should not be forwarding
 	// r updates that have timestamps less than or equal to any resolved timestamp
@@ -427,6 +467,10 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 		evCtx.topic = topic
 	}
 
+	if c.txnTracker != nil {
+		evCtx.txn = c.txnTracker.noteRow(updatedRow.MvccTimestamp, topic)
+	}
+
 	if c.knobs.BeforeEmitRow != nil {
 		if err := c.knobs.BeforeEmitRow(ctx); err != nil {
 			return err
@@ -496,9 +540,33 @@ func (c *kvEventToRowConsumer) encodeForParquet(
 	return nil
 }
 
-// Flush is a noop for the kvEventToRowConsumer because it does not buffer any events.
+// Flush does not need to flush any events since kvEventToRowConsumer does not
+// buffer them. If transaction boundaries were requested, it emits boundary
+// markers for all transactions at or below the local frontier.
 func (c *kvEventToRowConsumer) Flush(ctx context.Context) error {
-	return nil
+	if c.txnTracker == nil {
+		return nil
+	}
+	return c.txnTracker.closeResolved(c.frontier.Frontier(), func(b txnBoundary) error {
+		if !c.txnBoundaries {
+			return nil
+		}
+		return c.emitTxnBoundary(ctx, b)
+	})
+}
+
+// emitTxnBoundary encodes and emits a transaction boundary marker.
+func (c *kvEventToRowConsumer) emitTxnBoundary(ctx context.Context, b txnBoundary) error {
+	enc, ok := c.encoder.(txnBoundaryEncoder)
+	if !ok {
+		return errors.AssertionFailedf("encoder %T does not support %s",
+			c.encoder, changefeedbase.OptTxnBoundaries)
+	}
+	key, value, err := enc.EncodeTxnBoundary(ctx, b)
+	if err != nil {
+		return err
+	}
+	return c.sink.EmitRow(ctx, b.topic, key, value, b.id, b.id, kvevent.Alloc{})
 }
 
 type parallelEventConsumer struct {
@@ -0,0 +1,171 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	gojson "encoding/json"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect of the schemas registered for
+// changefeeds using format=json.
+const jsonSchemaDraft = `http://json-schema.org/draft-07/schema#`
+
+// jsonSchema is a JSON Schema document. It's a plain map so that the schema
+// is marshaled with sorted keys, which keeps the registered schema stable for
+// a given table version.
+type jsonSchema = map[string]interface{}
+
+// jsonSchemaForType returns the schema of the JSON representation of a datum
+// of type typ, as produced by tree.AsJSON.
+func jsonSchemaForType(typ *types.T) jsonSchema {
+	switch typ.Family() {
+	case types.IntFamily:
+		return jsonSchema{`type`: `integer`}
+	case types.FloatFamily, types.DecimalFamily:
+		// NaN and infinities are encoded as strings.
+		return jsonSchema{`type`: []string{`number`, `string`}}
+	case types.BoolFamily:
+		return jsonSchema{`type`: `boolean`}
+	case types.JsonFamily:
+		// Any JSON value.
+		return jsonSchema{}
+	case types.ArrayFamily:
+		return jsonSchema{`type`: `array`, `items`: nullableJSONSchema(jsonSchemaForType(typ.ArrayContents()))}
+	default:
+		return jsonSchema{`type`: `string`}
+	}
+}
+
+// nullableJSONSchema returns a schema accepting either null or whatever s
+// accepts.
+func nullableJSONSchema(s jsonSchema) jsonSchema {
+	if len(s) == 0 {
+		// Already accepts null.
+		return s
+	}
+	return jsonSchema{`oneOf`: []jsonSchema{{`type`: `null`}, s}}
+}
+
+// keyJSONSchema returns the schema of the keys encoded by the jsonEncoder for
+// the columns in it: an array with one item per column.
+func keyJSONSchema(title string, it cdcevent.Iterator) (jsonSchema, error) {
+	var items []jsonSchema
+	if err := it.Col(func(col cdcevent.ResultColumn) error {
+		items = append(items, jsonSchemaForType(col.Typ))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return jsonSchema{
+		`$schema`:         jsonSchemaDraft,
+		`title`:           title,
+		`type`:            `array`,
+		`items`:           items,
+		`minItems`:        len(items),
+		`additionalItems`: false,
+	}, nil
+}
+
+// rowJSONSchema returns the schema of an object holding the columns of row.
+// Every column is optional and nullable so that the schema of a table version
+// also describes rows of previous versions, such as the before field of a
+// wrapped envelope.
+func rowJSONSchema(row cdcevent.Row) (jsonSchema, error) {
+	properties := make(map[string]jsonSchema, len(row.ResultColumns()))
+	if err := row.ForEachColumn().Col(func(col cdcevent.ResultColumn) error {
+		properties[col.Name] = nullableJSONSchema(jsonSchemaForType(col.Typ))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return jsonSchema{`type`: `object`, `properties`: properties}, nil
+}
+
+// metadataJSONSchemaProperties returns the schema of the metadata fields
+// added to each message by the jsonEncoder.
+func (e *jsonEncoder) metadataJSONSchemaProperties(keySchema jsonSchema) map[string]jsonSchema {
+	timestamp := jsonSchema{`type`: `string`}
+	properties := map[string]jsonSchema{
+		`resolved`: timestamp,
+	}
+	if e.updatedField {
+		properties[`updated`] = timestamp
+	}
+	if e.mvccTimestampField {
+		properties[`mvcc_timestamp`] = timestamp
+	}
+	if e.keyInValue {
+		key := make(jsonSchema, len(keySchema))
+		for k, v := range keySchema {
+			if k != `$schema` && k != `title` {
+				key[k] = v
+			}
+		}
+		properties[`key`] = key
+	}
+	if e.topicInValue {
+		properties[`topic`] = jsonSchema{`type`: `string`}
+	}
+	if e.txnField {
+		properties[`txn`] = jsonSchema{
+			`type`: `object`,
+			`properties`: map[string]jsonSchema{
+				`id`:  timestamp,
+				`row`: {`type`: `integer`},
+			},
+		}
+	}
+	return properties
+}
+
+// valueJSONSchema returns the schema of the values encoded by the jsonEncoder
+// for rows of the given table version, which includes resolved timestamp
+// messages.
+func (e *jsonEncoder) valueJSONSchema(
+	title string, row cdcevent.Row, keySchema jsonSchema,
+) (jsonSchema, error) {
+	rowSchema, err := rowJSONSchema(row)
+	if err != nil {
+		return nil, err
+	}
+	meta := e.metadataJSONSchemaProperties(keySchema)
+
+	var s jsonSchema
+	if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
+		properties := meta
+		properties[`after`] = nullableJSONSchema(rowSchema)
+		if e.beforeField {
+			properties[`before`] = nullableJSONSchema(rowSchema)
+		}
+		s = jsonSchema{`type`: `object`, `properties`: properties}
+	} else {
+		// Columns and metadata share the top level object in the bare and row
+		// envelopes, with the metadata nested under jsonMetaSentinel.
+		s = rowSchema
+		s[`properties`].(map[string]jsonSchema)[jsonMetaSentinel] = jsonSchema{
+			`type`:       `object`,
+			`properties`: meta,
+		}
+	}
+	s[`$schema`] = jsonSchemaDraft
+	s[`title`] = title
+	return s, nil
+}
+
+func marshalJSONSchema(s jsonSchema) (string, error) {
+	b, err := gojson.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
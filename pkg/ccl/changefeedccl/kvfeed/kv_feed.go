@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
@@ -75,6 +76,11 @@ type Config struct {
 
 	// UseMux enables MuxRangeFeed rpc
 	UseMux bool
+
+	// RangefeedFilter, if set, is pushed down into the rangefeed registrations
+	// so that the servers can skip events the changefeed would discard anyway.
+	// It is best-effort; events that don't match may still be delivered.
+	RangefeedFilter *kvpb.RangeFeedFilter
 }
 
 // Run will run the kvfeed. The feed runs synchronously and returns an
@@ -110,6 +116,7 @@ func Run(ctx context.Context, cfg Config) error {
 		cfg.SchemaFeed,
 		sc, pff, bf, cfg.UseMux, cfg.Targets, cfg.Knobs)
 	f.onBackfillCallback = cfg.OnBackfillCallback
+	f.rangefeedFilter = cfg.RangefeedFilter
 
 	g := ctxgroup.WithContext(ctx)
 	g.GoCtx(cfg.SchemaFeed.Run)
@@ -181,7 +188,8 @@ type kvFeed struct {
 	schemaChangeEvents changefeedbase.SchemaChangeEventClass
 	schemaChangePolicy changefeedbase.SchemaChangePolicy
 
-	useMux bool
+	useMux          bool
+	rangefeedFilter *kvpb.RangeFeedFilter
 
 	targets changefeedbase.Targets
 
@@ -480,6 +488,7 @@ func (f *kvFeed) runUntilTableEvent(
 		Spans:    stps,
 		Frontier: resumeFrontier.Frontier(),
 		WithDiff: f.withDiff,
+		Filter:   f.rangefeedFilter,
 		Knobs:    f.knobs,
 		UseMux:   f.useMux,
 	}
@@ -30,6 +30,7 @@ type rangeFeedConfig struct {
 	Frontier hlc.Timestamp
 	Spans    []kvcoord.SpanTimePair
 	WithDiff bool
+	Filter   *kvpb.RangeFeedFilter
 	Knobs    TestingKnobs
 	UseMux   bool
 }
@@ -81,6 +82,9 @@ func (p rangefeedFactory) Run(ctx context.Context, sink kvevent.Writer, cfg rang
 	if cfg.WithDiff {
 		rfOpts = append(rfOpts, kvcoord.WithDiff())
 	}
+	if cfg.Filter != nil {
+		rfOpts = append(rfOpts, kvcoord.WithFilter(cfg.Filter))
+	}
 
 	g.GoCtx(func(ctx context.Context) error {
 		return p(ctx, cfg.Spans, feed.eventC, rfOpts...)
@@ -16,6 +16,7 @@ import (
 	"io"
 	"net/url"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
@@ -30,28 +31,41 @@ import (
 
 const confluentSchemaContentType = `application/vnd.schemaregistry.v1+json`
 
+// schemaType is the type of a schema registered with a schema registry.
+type schemaType string
+
+const (
+	schemaTypeAvro schemaType = `AVRO`
+	schemaTypeJSON schemaType = `JSON`
+)
+
 type schemaRegistry interface {
 	// Ping tests the connectivity to the schema registry. A nil
 	// error is returned if the schema registry appears to be
 	// available.
 	Ping(ctx context.Context) error
 
-	// RegisterSchemaForSubject registers the given schema for the
-	// given subject. The returned int32 is a schema ID that can
-	// be used in Avro wire messages or in other calls to the
-	// schema registry.
-	RegisterSchemaForSubject(ctx context.Context, subject string, schema string) (int32, error)
+	// RegisterSchemaForSubject registers the given schema, of the given
+	// type, for the given subject. The returned int32 is a schema ID that
+	// can be used in Avro wire messages or in other calls to the schema
+	// registry.
+	RegisterSchemaForSubject(ctx context.Context, subject string, typ schemaType, schema string) (int32, error)
 }
 
 type confluentSchemaVersionRequest struct {
 	Schema string `json:"schema"`
+	// SchemaType is omitted for Avro schemas, which is the default, for
+	// compatibility with registries which predate other schema types.
+	SchemaType string `json:"schemaType,omitempty"`
 }
 
 type confluentSchemaVersionResponse struct {
 	ID int32 `json:"id"`
 }
 
-type confluentSchemaRegistry struct {
+// httpSchemaRegistry holds the state common to the schema registries
+// accessed over HTTP.
+type httpSchemaRegistry struct {
 	baseURL *url.URL
 	// The current defaults for httputil.Client sets
 	// DisableKeepAlive's true so we don't have persistent
@@ -61,6 +75,12 @@ type confluentSchemaRegistry struct {
 	sliMetrics *sliMetrics
 }
 
+// confluentSchemaRegistry is a client for the Confluent Schema Registry REST
+// API.
+type confluentSchemaRegistry struct {
+	httpSchemaRegistry
+}
+
 var _ schemaRegistry = (*confluentSchemaRegistry)(nil)
 
 type schemaRegistryParams struct {
@@ -118,7 +138,11 @@ func getAndDeleteParams(u *url.URL) (*schemaRegistryParams, error) {
 	return &s, nil
 }
 
-func newConfluentSchemaRegistry(
+// newSchemaRegistry returns a client for the schema registry at the given
+// URL. The flavor of the registry is selected by the scheme of the URL:
+// http(s) for the Confluent Schema Registry and apicurio+http(s) for an
+// Apicurio Registry.
+func newSchemaRegistry(
 	baseURL string, p externalConnectionProvider, sliMetrics *sliMetrics,
 ) (schemaRegistry, error) {
 	u, err := url.Parse(baseURL)
@@ -131,10 +155,28 @@ func newConfluentSchemaRegistry(
 		if err != nil {
 			return nil, err
 		}
-		return newConfluentSchemaRegistry(actual, p, sliMetrics)
+		return newSchemaRegistry(actual, p, sliMetrics)
 	}
 
-	if u.Scheme != "http" && u.Scheme != "https" {
+	var makeRegistry func(httpSchemaRegistry) schemaRegistry
+	switch u.Scheme {
+	case "http", "https":
+		makeRegistry = func(r httpSchemaRegistry) schemaRegistry {
+			return &confluentSchemaRegistry{httpSchemaRegistry: r}
+		}
+	case apicurioSchemeHTTP, apicurioSchemeHTTPS:
+		u.Scheme = strings.TrimPrefix(u.Scheme, apicurioSchemePrefix)
+		query := u.Query()
+		groupID := query.Get(apicurioGroupIDParam)
+		if groupID == "" {
+			groupID = apicurioDefaultGroupID
+		}
+		query.Del(apicurioGroupIDParam)
+		u.RawQuery = query.Encode()
+		makeRegistry = func(r httpSchemaRegistry) schemaRegistry {
+			return &apicurioSchemaRegistry{httpSchemaRegistry: r, groupID: groupID}
+		}
+	default:
 		return nil, errors.Errorf("unsupported scheme: %q", u.Scheme)
 	}
 
@@ -163,18 +205,18 @@ func newConfluentSchemaRegistry(
 	retryOpts := base.DefaultRetryOptions()
 	retryOpts.MaxRetries = 5
 	reg := schemaRegistryWithCache{
-		base: &confluentSchemaRegistry{
+		base: makeRegistry(httpSchemaRegistry{
 			baseURL:    u,
 			client:     httpClient,
 			retryOpts:  retryOpts,
 			sliMetrics: sliMetrics,
-		},
+		}),
 		cache: src,
 	}
 	return &reg, nil
 }
 
-// Setup the httputil.Client to use when dialing Confluent schema registry. If `ca_cert`
+// Setup the httputil.Client to use when dialing the schema registry. If `ca_cert`
 // is set as a query param in the registry URL, client should trust the corresponding
 // cert while dialing. Otherwise, use the DefaultClient.
 func setupHTTPClient(baseURL *url.URL, s *schemaRegistryParams) (*httputil.Client, error) {
@@ -219,18 +261,21 @@ func (r *confluentSchemaRegistry) Ping(ctx context.Context) error {
 }
 
 // RegisterSchemaForSubject registers the given schema for the given
-// subject. The schema type is assumed to be AVRO.
+// subject.
 //
 //	https://docs.confluent.io/platform/current/schema-registry/develop/api.html#post--subjects-(string-%20subject)-versions
 func (r *confluentSchemaRegistry) RegisterSchemaForSubject(
-	ctx context.Context, subject string, schema string,
+	ctx context.Context, subject string, typ schemaType, schema string,
 ) (int32, error) {
 	u := r.urlForPath(fmt.Sprintf("subjects/%s/versions", subject))
 	if log.V(1) {
-		log.Infof(ctx, "registering avro schema %s %s", u, schema)
+		log.Infof(ctx, "registering %s schema %s %s", typ, u, schema)
 	}
 
 	req := confluentSchemaVersionRequest{Schema: schema}
+	if typ != schemaTypeAvro {
+		req.SchemaType = string(typ)
+	}
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(req); err != nil {
 		return 0, err
@@ -263,7 +308,7 @@ func (r *confluentSchemaRegistry) RegisterSchemaForSubject(
 	return id, nil
 }
 
-func (r *confluentSchemaRegistry) doWithRetry(ctx context.Context, fn func() error) error {
+func (r *httpSchemaRegistry) doWithRetry(ctx context.Context, fn func() error) error {
 	// Since network services are often a source of flakes, add a few retries here
 	// before we give up and return an error that will bubble up and tear down the
 	// entire changefeed, though that error is marked as retryable so that the job
@@ -305,7 +350,7 @@ func gracefulClose(ctx context.Context, toClose io.ReadCloser) {
 	}
 }
 
-func (r *confluentSchemaRegistry) urlForPath(relPath string) string {
+func (r *httpSchemaRegistry) urlForPath(relPath string) string {
 	u := *r.baseURL
 	u.Path = path.Join(u.EscapedPath(), relPath)
 	return u.String()
@@ -313,6 +358,7 @@ func (r *confluentSchemaRegistry) urlForPath(relPath string) string {
 
 type schemaRegistryCacheKey struct {
 	subject string
+	typ     schemaType
 	schema  string
 }
 
@@ -349,10 +395,10 @@ func (csr *schemaRegistryWithCache) Ping(ctx context.Context) error {
 
 // RegisterSchemaForSubject implements the schemaRegistry interface.
 func (csr *schemaRegistryWithCache) RegisterSchemaForSubject(
-	ctx context.Context, subject string, schema string,
+	ctx context.Context, subject string, typ schemaType, schema string,
 ) (int32, error) {
 	cacheKey := schemaRegistryCacheKey{
-		subject: subject, schema: schema,
+		subject: subject, typ: typ, schema: schema,
 	}
 	csr.cache.mu.Lock()
 	defer csr.cache.mu.Unlock()
@@ -360,7 +406,7 @@ func (csr *schemaRegistryWithCache) RegisterSchemaForSubject(
 	if ok {
 		return id, nil
 	}
-	id, err := csr.base.RegisterSchemaForSubject(ctx, subject, schema)
+	id, err := csr.base.RegisterSchemaForSubject(ctx, subject, typ, schema)
 	if err == nil {
 		csr.cache.Add(cacheKey, id)
 	}
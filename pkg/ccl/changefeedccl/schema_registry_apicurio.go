@@ -0,0 +1,133 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// apicurioSchemePrefix is prepended to the http(s) scheme of a schema
+	// registry URL to select the Apicurio Registry client.
+	apicurioSchemePrefix = `apicurio+`
+	apicurioSchemeHTTP   = apicurioSchemePrefix + `http`
+	apicurioSchemeHTTPS  = apicurioSchemePrefix + `https`
+
+	// apicurioGroupIDParam is the schema registry URL query parameter
+	// holding the artifact group in which schemas are registered.
+	apicurioGroupIDParam   = `group_id`
+	apicurioDefaultGroupID = `default`
+
+	apicurioAPIPath = `apis/registry/v2`
+)
+
+// apicurioArtifactMetaData is the subset of the reply to an artifact
+// registration used by the changefeed.
+type apicurioArtifactMetaData struct {
+	ContentID int64 `json:"contentId"`
+}
+
+// apicurioSchemaRegistry is a client for the Apicurio Registry (v2) REST API.
+// Subjects are registered as artifacts, whose IDs are the subject names, in a
+// single artifact group. Compatibility checks are performed by the registry
+// according to the rules configured for the artifacts or globally.
+//
+// The IDs returned by RegisterSchemaForSubject are artifact content IDs, which
+// are the IDs returned by the Confluent-compatible API of Apicurio Registry.
+// Avro consumers using the Apicurio serdes must therefore be configured to
+// look up schemas by content ID, with a 4-byte ID handler.
+type apicurioSchemaRegistry struct {
+	httpSchemaRegistry
+	groupID string
+}
+
+var _ schemaRegistry = (*apicurioSchemaRegistry)(nil)
+
+// Ping checks connectivity to the schema registry using the system info
+// endpoint. As for the Confluent registry, only server errors are reported.
+func (r *apicurioSchemaRegistry) Ping(ctx context.Context) error {
+	u := r.urlForPath(path.Join(apicurioAPIPath, `system/info`))
+	return r.doWithRetry(ctx, func() error {
+		resp, err := r.client.Get(ctx, u)
+		if err != nil {
+			return err
+		}
+		defer gracefulClose(ctx, resp.Body)
+		if resp.StatusCode >= 500 {
+			return errors.Errorf("unexpected schema registry response: %s", resp.Status)
+		}
+		return nil
+	})
+}
+
+// RegisterSchemaForSubject registers the given schema as the latest version
+// of the artifact named after the subject, creating the artifact if needed.
+// Registering a schema identical to an existing version of the artifact
+// returns that version.
+//
+//	https://www.apicur.io/registry/docs/apicurio-registry/2.4.x/assets-attachments/registry-rest-api.htm#tag/Artifacts/operation/createArtifact
+func (r *apicurioSchemaRegistry) RegisterSchemaForSubject(
+	ctx context.Context, subject string, typ schemaType, schema string,
+) (int32, error) {
+	u := *r.baseURL
+	u.Path = path.Join(u.EscapedPath(), apicurioAPIPath, `groups`, url.PathEscape(r.groupID), `artifacts`)
+	query := u.Query()
+	query.Set(`ifExists`, `RETURN_OR_UPDATE`)
+	query.Set(`canonical`, `true`)
+	u.RawQuery = query.Encode()
+	if log.V(1) {
+		log.Infof(ctx, "registering %s schema %s %s", typ, u.String(), schema)
+	}
+
+	var id int32
+	err := r.doWithRetry(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(schema))
+		if err != nil {
+			return err
+		}
+		req.Header.Set(`Content-Type`, `application/json`)
+		req.Header.Set(`X-Registry-ArtifactId`, subject)
+		req.Header.Set(`X-Registry-ArtifactType`, string(typ))
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return errors.Wrap(err, "contacting apicurio schema registry")
+		}
+		defer gracefulClose(ctx, resp.Body)
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			body, _ := io.ReadAll(resp.Body)
+			return errors.Errorf("registering schema to %s %s: %s", u.String(), resp.Status, body)
+		}
+		var res apicurioArtifactMetaData
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+			return errors.Wrap(err, "decoding apicurio schema registry reply")
+		}
+		if res.ContentID < 0 || res.ContentID > math.MaxInt32 {
+			return errors.Errorf("schema content id %d does not fit in 4 bytes", res.ContentID)
+		}
+		id = int32(res.ContentID)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if r.sliMetrics != nil {
+		r.sliMetrics.SchemaRegistrations.Inc(1)
+	}
+	return id, nil
+}
@@ -12,7 +12,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -30,19 +34,19 @@ func TestConfluentSchemaRegistry(t *testing.T) {
 	defer log.Scope(t).Close(t)
 
 	t.Run("errors with no scheme", func(t *testing.T) {
-		_, err := newConfluentSchemaRegistry("justsomestring", nil, nil)
+		_, err := newSchemaRegistry("justsomestring", nil, nil)
 		require.Error(t, err)
 	})
 	t.Run("errors with unsupported scheme", func(t *testing.T) {
 		url := "gopher://myhost"
-		_, err := newConfluentSchemaRegistry(url, nil, nil)
+		_, err := newSchemaRegistry(url, nil, nil)
 		require.Error(t, err)
 	})
 
 	t.Run("configure timeout", func(t *testing.T) {
 		regServer := cdctest.StartTestSchemaRegistry()
 		defer regServer.Close()
-		r, err := newConfluentSchemaRegistry(regServer.URL(), nil, nil)
+		r, err := newSchemaRegistry(regServer.URL(), nil, nil)
 		require.NoError(t, err)
 		getTimeout := func(r schemaRegistry) time.Duration {
 			return r.(*schemaRegistryWithCache).base.(*confluentSchemaRegistry).client.Timeout
@@ -55,7 +59,7 @@ func TestConfluentSchemaRegistry(t *testing.T) {
 		values := u.Query()
 		values.Set(timeoutParam, "42ms")
 		u.RawQuery = values.Encode()
-		r, err = newConfluentSchemaRegistry(u.String(), nil, nil)
+		r, err = newSchemaRegistry(u.String(), nil, nil)
 		require.NoError(t, err)
 		require.Equal(t, 42*time.Millisecond, getTimeout(r))
 	})
@@ -83,16 +87,16 @@ func TestConfluentSchemaRegistryExternalConnection(t *testing.T) {
 		"bad_endpoint":  "http://bad",
 	}
 
-	reg, err := newConfluentSchemaRegistry("external://good_endpoint", m, nil)
+	reg, err := newSchemaRegistry("external://good_endpoint", m, nil)
 	require.NoError(t, err)
 	require.NoError(t, reg.Ping(context.Background()))
 
 	// We can load a bad endpoint, but ping should fail.
-	reg, err = newConfluentSchemaRegistry("external://bad_endpoint", m, nil)
+	reg, err = newSchemaRegistry("external://bad_endpoint", m, nil)
 	require.NoError(t, err)
 	require.Error(t, reg.Ping(context.Background()))
 
-	_, err = newConfluentSchemaRegistry("external://no_endpoint", m, nil)
+	_, err = newSchemaRegistry("external://no_endpoint", m, nil)
 	require.Error(t, err)
 
 }
@@ -111,9 +115,9 @@ func TestConfluentSchemaRegistrySharedCache(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		wg.Add(1)
 		go func() {
-			r, err := newConfluentSchemaRegistry(regServer.URL(), nil, nil)
+			r, err := newSchemaRegistry(regServer.URL(), nil, nil)
 			require.NoError(t, err)
-			_, err = r.RegisterSchemaForSubject(context.Background(), "subject1", "schema")
+			_, err = r.RegisterSchemaForSubject(context.Background(), "subject1", schemaTypeAvro, "schema")
 			require.NoError(t, err)
 			wg.Done()
 
@@ -126,9 +130,9 @@ func TestConfluentSchemaRegistrySharedCache(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		wg.Add(1)
 		go func(i int) {
-			r, err := newConfluentSchemaRegistry(regServer.URL(), nil, nil)
+			r, err := newSchemaRegistry(regServer.URL(), nil, nil)
 			require.NoError(t, err)
-			_, err = r.RegisterSchemaForSubject(context.Background(), "subject1", fmt.Sprintf("schema1%d", i))
+			_, err = r.RegisterSchemaForSubject(context.Background(), "subject1", schemaTypeAvro, fmt.Sprintf("schema1%d", i))
 			require.NoError(t, err)
 			wg.Done()
 
@@ -147,17 +151,17 @@ func TestConfluentSchemaRegistryPing(t *testing.T) {
 	defer regServer.Close()
 
 	t.Run("ping works when all is well", func(t *testing.T) {
-		reg, err := newConfluentSchemaRegistry(regServer.URL(), nil, nil)
+		reg, err := newSchemaRegistry(regServer.URL(), nil, nil)
 		require.NoError(t, err)
 		require.NoError(t, reg.Ping(context.Background()))
 	})
 	t.Run("ping does not error from HTTP 404", func(t *testing.T) {
-		reg, err := newConfluentSchemaRegistry(regServer.URL()+"/path-does-not-exist-but-we-do-not-care", nil, nil)
+		reg, err := newSchemaRegistry(regServer.URL()+"/path-does-not-exist-but-we-do-not-care", nil, nil)
 		require.NoError(t, err)
 		require.NoError(t, reg.Ping(context.Background()), "Ping")
 	})
 	t.Run("Ping errors with bad host", func(t *testing.T) {
-		reg, err := newConfluentSchemaRegistry("http://host-does-exist-and-we-care", nil, nil)
+		reg, err := newSchemaRegistry("http://host-does-exist-and-we-care", nil, nil)
 		require.NoError(t, err)
 		require.Error(t, reg.Ping(context.Background()))
 	})
@@ -176,11 +180,11 @@ func TestConfluentSchemaRegistryRetryMetrics(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("ping works when all is well", func(t *testing.T) {
-		reg, err := newConfluentSchemaRegistry(regServer.URL(), nil, sliMetrics)
+		reg, err := newSchemaRegistry(regServer.URL(), nil, sliMetrics)
 		require.NoError(t, err)
 		ctx, cancel := context.WithCancel(context.Background())
 		go func() {
-			_, err = reg.RegisterSchemaForSubject(ctx, "subject1", "schema1")
+			_, err = reg.RegisterSchemaForSubject(ctx, "subject1", schemaTypeAvro, "schema1")
 		}()
 		require.NoError(t, err)
 		testutils.SucceedsSoon(t, func() error {
@@ -194,3 +198,101 @@ func TestConfluentSchemaRegistryRetryMetrics(t *testing.T) {
 	})
 
 }
+
+func TestApicurioSchemaRegistry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	type registration struct {
+		path, query, artifactID, artifactType, schema string
+	}
+	var mu sync.Mutex
+	var registrations []registration
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == `/registry/apis/registry/v2/system/info`:
+			_, _ = w.Write([]byte(`{"name":"Apicurio Registry"}`))
+		case r.Method == http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			registrations = append(registrations, registration{
+				path:         r.URL.Path,
+				query:        r.URL.RawQuery,
+				artifactID:   r.Header.Get(`X-Registry-ArtifactId`),
+				artifactType: r.Header.Get(`X-Registry-ArtifactType`),
+				schema:       string(body),
+			})
+			if strings.Contains(string(body), `incompatible`) {
+				http.Error(w, `{"error_code":409}`, http.StatusConflict)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"groupId":"cdc","id":%q,"version":"1","contentId":%d}`,
+				r.Header.Get(`X-Registry-ArtifactId`), len(registrations)+10)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	u.Scheme = apicurioSchemeHTTP
+	u.Path = `/registry`
+
+	t.Run("default group", func(t *testing.T) {
+		reg, err := newSchemaRegistry(u.String(), nil, nil)
+		require.NoError(t, err)
+		require.NoError(t, reg.Ping(ctx))
+
+		id, err := reg.RegisterSchemaForSubject(ctx, `foo-value`, schemaTypeJSON, `{"type":"object"}`)
+		require.NoError(t, err)
+		require.EqualValues(t, 11, id)
+		// Registrations are cached.
+		id, err = reg.RegisterSchemaForSubject(ctx, `foo-value`, schemaTypeJSON, `{"type":"object"}`)
+		require.NoError(t, err)
+		require.EqualValues(t, 11, id)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, []registration{{
+			path:         `/registry/apis/registry/v2/groups/default/artifacts`,
+			query:        `canonical=true&ifExists=RETURN_OR_UPDATE`,
+			artifactID:   `foo-value`,
+			artifactType: `JSON`,
+			schema:       `{"type":"object"}`,
+		}}, registrations)
+		registrations = nil
+	})
+
+	t.Run("explicit group", func(t *testing.T) {
+		withGroup := *u
+		withGroup.RawQuery = url.Values{apicurioGroupIDParam: {`cdc`}}.Encode()
+		reg, err := newSchemaRegistry(withGroup.String(), nil, nil)
+		require.NoError(t, err)
+
+		_, err = reg.RegisterSchemaForSubject(ctx, `foo-key`, schemaTypeAvro, `"long"`)
+		require.NoError(t, err)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, registrations, 1)
+		require.Equal(t, `/registry/apis/registry/v2/groups/cdc/artifacts`, registrations[0].path)
+		require.Equal(t, `AVRO`, registrations[0].artifactType)
+		registrations = nil
+	})
+
+	t.Run("incompatible schema", func(t *testing.T) {
+		reg, err := newSchemaRegistry(u.String(), nil, nil)
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		_, err = reg.RegisterSchemaForSubject(ctx, `bar-value`, schemaTypeJSON, `{"title":"incompatible"}`)
+		require.Error(t, err)
+	})
+}
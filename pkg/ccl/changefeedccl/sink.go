@@ -59,6 +59,7 @@ const (
 	sinkTypePubsub
 	sinkTypeCloudstorage
 	sinkTypeSQL
+	sinkTypeRedis
 )
 
 // externalResource is the interface common to both EventSink and
@@ -275,6 +276,10 @@ func getSink(
 			return validateOptionsAndMakeSink(changefeedbase.SQLValidOptions, func() (Sink, error) {
 				return makeSQLSink(sinkURL{URL: u}, sqlSinkTableName, AllTargets(feedCfg), metricsBuilder)
 			})
+		case u.Scheme == changefeedbase.SinkSchemeRedis:
+			return validateOptionsAndMakeSink(changefeedbase.RedisValidOptions, func() (Sink, error) {
+				return makeRedisSink(sinkURL{URL: u}, encodingOpts, AllTargets(feedCfg), metricsBuilder)
+			})
 		case u.Scheme == changefeedbase.SinkSchemeExternalConnection:
 			return validateOptionsAndMakeSink(changefeedbase.ExternalConnectionValidOptions, func() (Sink, error) {
 				return makeExternalConnectionSink(
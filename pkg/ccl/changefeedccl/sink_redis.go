@@ -0,0 +1,725 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"hash"
+	"hash/fnv"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// redisSinkDefaultPort is the port used when the sink URI does not
+	// specify one.
+	redisSinkDefaultPort = `6379`
+	// redisSinkMaxBatchSize is the number of buffered XADD commands after
+	// which the sink flushes on its own.
+	redisSinkMaxBatchSize = 1000
+	// redisSinkIOTimeout bounds the time spent writing a batch of commands
+	// and reading their replies.
+	redisSinkIOTimeout = time.Minute
+	// redisSinkScanCount is the number of keys examined by each SCAN command
+	// listing the streams of a topic.
+	redisSinkScanCount = 1000
+
+	redisFieldKey      = `key`
+	redisFieldValue    = `value`
+	redisFieldResolved = `resolved`
+)
+
+// redisSink emits changefeed messages to Redis Streams.
+//
+// Each message is appended with XADD to the stream named after its topic
+// (subject to the topic_prefix and topic_name parameters) as an entry with a
+// `key` and a `value` field. With shards=N, every topic is spread over the N
+// streams `<topic>:0` ... `<topic>:<N-1>` by hashing the message key, so that
+// all messages for a given key land, in order, in the same stream. With
+// key_prefix_columns=N, which requires JSON keys, every topic is instead split
+// into one stream per distinct value of the first N primary key columns, named
+// `<topic>:<value 1>:...:<value N>`. Resolved timestamps are appended, as
+// entries with a single `resolved` field, to every stream of every topic.
+// Since the streams of a topic split by key prefix are not known in advance,
+// they are found by scanning the database for streams named `<topic>:*`, and
+// the resolved timestamp is also appended to the stream named after the topic.
+// With maxlen=N, streams are trimmed to (approximately, with
+// maxlen_approximate=true) the N most recent entries on every append.
+//
+// Commands are buffered and pipelined over a single connection, which
+// preserves their order. Messages are counted as emitted once the server
+// acknowledges them. Error replies are terminal, unless they report a
+// transient condition of the server, such as a failover.
+type redisSink struct {
+	addr       string
+	tlsConfig  *tls.Config
+	username   string
+	password   string
+	db         int
+	topicNamer *TopicNamer
+	shards     int
+	// keyPrefixCols is the number of primary key columns whose values name
+	// the stream of a message, if positive.
+	keyPrefixCols int
+	maxLen        int64
+	approxTrim    bool
+	hasher        hash.Hash32
+
+	conn    *redisConn
+	cmdBuf  [][][]byte
+	scratch bufalloc.ByteAllocator
+
+	// The messages buffered in cmdBuf, which are recorded as emitted once
+	// flushed.
+	batch struct {
+		alloc       kvevent.Alloc
+		start       time.Time
+		numMessages int
+		oldestMVCC  hlc.Timestamp
+		bytes       int
+	}
+
+	metrics metricsRecorder
+}
+
+var _ Sink = (*redisSink)(nil)
+
+func (s *redisSink) getConcreteType() sinkType {
+	return sinkTypeRedis
+}
+
+func makeRedisSink(
+	u sinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	targets changefeedbase.Targets,
+	mb metricsRecorderBuilder,
+) (Sink, error) {
+	s := &redisSink{
+		shards:  1,
+		hasher:  fnv.New32a(),
+		metrics: mb(requiresResourceAccounting),
+	}
+
+	s.addr = u.Host
+	if u.Port() == `` {
+		s.addr = net.JoinHostPort(u.Hostname(), redisSinkDefaultPort)
+	}
+	if u.User != nil {
+		s.username = u.User.Username()
+		s.password, _ = u.User.Password()
+		if s.password == `` {
+			// redis://:password@host is the conventional form for servers
+			// without ACL users.
+			s.password, s.username = s.username, ``
+		}
+	}
+	if db := strings.Trim(u.Path, `/`); db != `` {
+		var err error
+		if s.db, err = strconv.Atoi(db); err != nil || s.db < 0 {
+			return nil, errors.Errorf(`invalid redis database %q`, db)
+		}
+	}
+
+	var tlsEnabled, tlsSkipVerify bool
+	var caCert []byte
+	if _, err := u.consumeBool(changefeedbase.SinkParamTLSEnabled, &tlsEnabled); err != nil {
+		return nil, err
+	}
+	if _, err := u.consumeBool(changefeedbase.SinkParamSkipTLSVerify, &tlsSkipVerify); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamCACert, &caCert); err != nil {
+		return nil, err
+	}
+	if tlsEnabled {
+		s.tlsConfig = &tls.Config{
+			ServerName:         u.Hostname(),
+			InsecureSkipVerify: tlsSkipVerify,
+		}
+		if caCert != nil {
+			caCertPool := x509.NewCertPool()
+			caCertPool.AppendCertsFromPEM(caCert)
+			s.tlsConfig.RootCAs = caCertPool
+		}
+	} else if caCert != nil {
+		return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamCACert, changefeedbase.SinkParamTLSEnabled)
+	}
+
+	if shards := u.consumeParam(changefeedbase.SinkParamShards); shards != `` {
+		var err error
+		if s.shards, err = strconv.Atoi(shards); err != nil || s.shards < 1 {
+			return nil, errors.Errorf(`param %s must be a positive integer`, changefeedbase.SinkParamShards)
+		}
+	}
+	if cols := u.consumeParam(changefeedbase.SinkParamKeyPrefixColumns); cols != `` {
+		var err error
+		if s.keyPrefixCols, err = strconv.Atoi(cols); err != nil || s.keyPrefixCols < 1 {
+			return nil, errors.Errorf(`param %s must be a positive integer`, changefeedbase.SinkParamKeyPrefixColumns)
+		}
+		if s.shards > 1 {
+			return nil, errors.Errorf(`param %s cannot be used with %s`,
+				changefeedbase.SinkParamKeyPrefixColumns, changefeedbase.SinkParamShards)
+		}
+		if encodingOpts.Format != changefeedbase.OptFormatJSON {
+			return nil, errors.Errorf(`param %s requires format=%s`,
+				changefeedbase.SinkParamKeyPrefixColumns, changefeedbase.OptFormatJSON)
+		}
+	}
+	if maxLen := u.consumeParam(changefeedbase.SinkParamMaxLen); maxLen != `` {
+		var err error
+		if s.maxLen, err = strconv.ParseInt(maxLen, 10, 64); err != nil || s.maxLen < 1 {
+			return nil, errors.Errorf(`param %s must be a positive integer`, changefeedbase.SinkParamMaxLen)
+		}
+	}
+	if wasSet, err := u.consumeBool(changefeedbase.SinkParamMaxLenApproximate, &s.approxTrim); err != nil {
+		return nil, err
+	} else if wasSet && s.maxLen == 0 {
+		return nil, errors.Errorf(`%s requires %s`, changefeedbase.SinkParamMaxLenApproximate, changefeedbase.SinkParamMaxLen)
+	}
+
+	var err error
+	s.topicNamer, err = MakeTopicNamer(
+		targets,
+		WithPrefix(u.consumeParam(changefeedbase.SinkParamTopicPrefix)),
+		WithSingleName(u.consumeParam(changefeedbase.SinkParamTopicName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown redis sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+	return s, nil
+}
+
+// Dial implements the Sink interface.
+func (s *redisSink) Dial() error {
+	return s.connect(context.Background())
+}
+
+func (s *redisSink) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: redisSinkIOTimeout}
+	var netConn net.Conn
+	var err error
+	if s.tlsConfig != nil {
+		netConn, err = (&tls.Dialer{NetDialer: &dialer, Config: s.tlsConfig}).DialContext(ctx, `tcp`, s.addr)
+	} else {
+		netConn, err = dialer.DialContext(ctx, `tcp`, s.addr)
+	}
+	if err != nil {
+		return errors.Wrapf(err, `dialing redis at %s`, s.addr)
+	}
+	conn := newRedisConn(netConn)
+
+	var cmds [][][]byte
+	if s.password != `` {
+		if s.username != `` {
+			cmds = append(cmds, redisCommand(`AUTH`, s.username, s.password))
+		} else {
+			cmds = append(cmds, redisCommand(`AUTH`, s.password))
+		}
+	}
+	if s.db != 0 {
+		cmds = append(cmds, redisCommand(`SELECT`, strconv.Itoa(s.db)))
+	}
+	cmds = append(cmds, redisCommand(`PING`))
+	if err := conn.pipeline(ctx, cmds); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *redisSink) EmitRow(
+	ctx context.Context,
+	topicDescr TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	topic, err := s.topicNamer.Name(topicDescr)
+	if err != nil {
+		alloc.Release(ctx)
+		return err
+	}
+	stream, err := s.rowStreamName(topic, key)
+	if err != nil {
+		alloc.Release(ctx)
+		return err
+	}
+
+	s.metrics.recordMessageSize(int64(len(key) + len(value)))
+	if s.batch.numMessages == 0 {
+		s.batch.start = timeutil.Now()
+	}
+	if s.batch.oldestMVCC.IsEmpty() || mvcc.Less(s.batch.oldestMVCC) {
+		s.batch.oldestMVCC = mvcc
+	}
+	s.batch.numMessages++
+	s.batch.bytes += len(key) + len(value)
+	s.batch.alloc.Merge(&alloc)
+
+	s.scratch, key = s.scratch.Copy(key, 0 /* extraCap */)
+	s.scratch, value = s.scratch.Copy(value, 0 /* extraCap */)
+	return s.emit(ctx, stream, []byte(redisFieldKey), key, []byte(redisFieldValue), value)
+}
+
+// rowStreamName returns the name of the stream of topic that a message with
+// the given key is appended to.
+func (s *redisSink) rowStreamName(topic string, key []byte) (string, error) {
+	if s.keyPrefixCols > 0 {
+		return s.keyPrefixStreamName(topic, key)
+	}
+
+	// Hashing logic copied from sarama.HashPartitioner.
+	s.hasher.Reset()
+	if _, err := s.hasher.Write(key); err != nil {
+		return ``, err
+	}
+	shard := int32(s.hasher.Sum32()) % int32(s.shards)
+	if shard < 0 {
+		shard = -shard
+	}
+	return s.streamName(topic, shard), nil
+}
+
+// keyPrefixStreamName returns the name of the stream of topic for the values
+// of the first keyPrefixCols primary key columns in key, a JSON array.
+func (s *redisSink) keyPrefixStreamName(topic string, key []byte) (string, error) {
+	j, err := json.ParseJSON(string(key))
+	if err != nil {
+		return ``, errors.Wrapf(err, `decoding key %q`, key)
+	}
+	if j.Type() != json.ArrayJSONType || j.Len() < s.keyPrefixCols {
+		return ``, errors.Errorf(`%s=%d requires keys with at least %d primary key columns, found %s`,
+			changefeedbase.SinkParamKeyPrefixColumns, s.keyPrefixCols, s.keyPrefixCols, j)
+	}
+	var b strings.Builder
+	b.WriteString(topic)
+	for i := 0; i < s.keyPrefixCols; i++ {
+		col, err := j.FetchValIdx(i)
+		if err != nil {
+			return ``, err
+		}
+		b.WriteByte(':')
+		if text, err := col.AsText(); err != nil {
+			return ``, err
+		} else if text != nil {
+			b.WriteString(*text)
+		} else {
+			b.WriteString(col.String())
+		}
+	}
+	return b.String(), nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *redisSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	defer s.metrics.recordResolvedCallback()()
+
+	if err := s.topicNamer.Each(func(topic string) error {
+		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
+		if err != nil {
+			return err
+		}
+		// The payload is not copied into the scratch buffer since it may be
+		// reset by a flush before the last shard is emitted.
+		payload = append([]byte(nil), payload...)
+		if s.keyPrefixCols > 0 {
+			streams, err := s.keyPrefixStreams(ctx, topic)
+			if err != nil {
+				return err
+			}
+			for _, stream := range append(streams, topic) {
+				if err := s.emit(ctx, stream, []byte(redisFieldResolved), payload); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		for shard := int32(0); shard < int32(s.shards); shard++ {
+			if err := s.emit(ctx, s.streamName(topic, shard), []byte(redisFieldResolved), payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	// Resolved timestamps are emitted synchronously.
+	return s.Flush(ctx)
+}
+
+// keyPrefixStreams returns the names of the streams of topic that are split
+// by key prefix. Every stream holding a message at or below a resolved
+// timestamp exists by the time that resolved timestamp is emitted, since the
+// message was acknowledged before the changefeed's frontier advanced past it.
+func (s *redisSink) keyPrefixStreams(ctx context.Context, topic string) ([]string, error) {
+	if s.conn == nil {
+		if err := s.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+	pattern := redisGlobEscaper.Replace(topic) + `:*`
+	// SCAN may return a key more than once.
+	seen := make(map[string]struct{})
+	var streams []string
+	cursor := `0`
+	for {
+		reply, err := s.conn.do(ctx, redisCommand(`SCAN`, cursor, `MATCH`, pattern,
+			`COUNT`, strconv.Itoa(redisSinkScanCount), `TYPE`, `stream`))
+		if err != nil {
+			return nil, s.handleError(err)
+		}
+		res, ok := reply.([]interface{})
+		if !ok || len(res) != 2 {
+			return nil, errors.Errorf(`redis: unexpected SCAN reply %v`, reply)
+		}
+		keys, _ := res[1].([]interface{})
+		for _, key := range keys {
+			name, ok := key.(string)
+			if _, dup := seen[name]; !ok || dup {
+				continue
+			}
+			seen[name] = struct{}{}
+			streams = append(streams, name)
+		}
+		if cursor, ok = res[0].(string); !ok {
+			return nil, errors.Errorf(`redis: unexpected SCAN cursor %v`, res[0])
+		}
+		if cursor == `0` {
+			break
+		}
+	}
+	sort.Strings(streams)
+	return streams, nil
+}
+
+// redisGlobEscaper escapes the characters of a key that are special in a
+// SCAN pattern.
+var redisGlobEscaper = strings.NewReplacer(
+	`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`,
+)
+
+// Topics gives the names of all topics that have been initialized
+// and will receive resolved timestamps.
+func (s *redisSink) Topics() []string {
+	return s.topicNamer.DisplayNamesSlice()
+}
+
+func (s *redisSink) streamName(topic string, shard int32) string {
+	if s.shards == 1 {
+		return topic
+	}
+	return topic + `:` + strconv.Itoa(int(shard))
+}
+
+// emit buffers an XADD of an entry with the given field/value pairs to the
+// stream.
+func (s *redisSink) emit(ctx context.Context, stream string, fieldsAndValues ...[]byte) error {
+	cmd := [][]byte{[]byte(`XADD`), []byte(stream)}
+	if s.maxLen > 0 {
+		cmd = append(cmd, []byte(`MAXLEN`))
+		if s.approxTrim {
+			cmd = append(cmd, []byte(`~`))
+		}
+		cmd = append(cmd, []byte(strconv.FormatInt(s.maxLen, 10)))
+	}
+	cmd = append(cmd, []byte(`*`))
+	cmd = append(cmd, fieldsAndValues...)
+	s.cmdBuf = append(s.cmdBuf, cmd)
+	if len(s.cmdBuf) >= redisSinkMaxBatchSize {
+		return s.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (s *redisSink) Flush(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+
+	if len(s.cmdBuf) == 0 {
+		return nil
+	}
+	if s.conn == nil {
+		// A previous flush failed; reconnect.
+		if err := s.connect(ctx); err != nil {
+			return err
+		}
+	}
+	if err := s.conn.pipeline(ctx, s.cmdBuf); err != nil {
+		// The buffered commands are kept, and may be retried by a later
+		// flush if the error is not terminal.
+		return s.handleError(err)
+	}
+	s.cmdBuf = s.cmdBuf[:0]
+	s.scratch = s.scratch[:0]
+	if s.batch.numMessages > 0 {
+		s.metrics.recordEmittedBatch(
+			s.batch.start, s.batch.numMessages, s.batch.oldestMVCC, s.batch.bytes, sinkDoesNotCompress)
+	}
+	s.batch.alloc.Release(ctx)
+	s.batch.numMessages, s.batch.oldestMVCC, s.batch.bytes = 0, hlc.Timestamp{}, 0
+	return nil
+}
+
+// handleError drops the connection, whose state is unknown after an error,
+// and marks error replies that will be returned again if the commands are
+// retried as terminal.
+func (s *redisSink) handleError(err error) error {
+	_ = s.conn.Close()
+	s.conn = nil
+	if isTransientRedisError(err) {
+		return err
+	}
+	return changefeedbase.WithTerminalError(err)
+}
+
+// Close implements the Sink interface.
+func (s *redisSink) Close() error {
+	// Messages that were never flushed are dropped.
+	s.batch.alloc.Release(context.Background())
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// redisCommand builds a command from string arguments.
+func redisCommand(args ...string) [][]byte {
+	cmd := make([][]byte, len(args))
+	for i, arg := range args {
+		cmd[i] = []byte(arg)
+	}
+	return cmd
+}
+
+// redisConn is a minimal client for the Redis serialization protocol (RESP),
+// sufficient for the commands issued by redisSink.
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+func newRedisConn(c net.Conn) *redisConn {
+	return &redisConn{Conn: c, r: bufio.NewReader(c), w: bufio.NewWriter(c)}
+}
+
+// pipeline writes all the commands and then reads their replies. The first
+// error reply, if any, is returned once all replies have been read.
+func (c *redisConn) pipeline(ctx context.Context, cmds [][][]byte) error {
+	deadline := time.Now().Add(redisSinkIOTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	for _, cmd := range cmds {
+		if err := writeRESPArray(c.w, cmd); err != nil {
+			return err
+		}
+	}
+	if err := c.w.Flush(); err != nil {
+		return errors.Wrap(err, `writing to redis`)
+	}
+
+	var firstErr error
+	for range cmds {
+		if err := readRESPReply(c.r); err != nil {
+			if !errors.HasType(err, (*redisError)(nil)) {
+				// I/O or protocol errors leave the connection unusable.
+				return err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// do sends a single command and returns its reply.
+func (c *redisConn) do(ctx context.Context, cmd [][]byte) (interface{}, error) {
+	deadline := time.Now().Add(redisSinkIOTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if err := writeRESPArray(c.w, cmd); err != nil {
+		return nil, err
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, errors.Wrap(err, `writing to redis`)
+	}
+	return readRESPValue(c.r)
+}
+
+// redisError is an error reply sent by the server.
+type redisError struct {
+	msg string
+}
+
+func (e *redisError) Error() string {
+	return `redis: ` + e.msg
+}
+
+// redisTransientErrors are the prefixes of the error replies that report a
+// condition of the server that is expected to clear up by itself, such as a
+// failover, a cluster reconfiguration or a dataset being loaded.
+var redisTransientErrors = map[string]struct{}{
+	`BUSY`:        {},
+	`CLUSTERDOWN`: {},
+	`LOADING`:     {},
+	`MASTERDOWN`:  {},
+	`READONLY`:    {},
+	`TRYAGAIN`:    {},
+}
+
+// isTransientRedisError returns whether err may be resolved by retrying the
+// commands that caused it. Errors other than error replies, which are I/O or
+// protocol errors, are transient, and so are the error replies listed in
+// redisTransientErrors. Any other error reply, such as WRONGTYPE or OOM, would
+// be returned again.
+func isTransientRedisError(err error) bool {
+	var redisErr *redisError
+	if !errors.As(err, &redisErr) {
+		return true
+	}
+	prefix := redisErr.msg
+	if i := strings.IndexByte(prefix, ' '); i >= 0 {
+		prefix = prefix[:i]
+	}
+	_, ok := redisTransientErrors[prefix]
+	return ok
+}
+
+func writeRESPArray(w *bufio.Writer, args [][]byte) error {
+	if _, err := w.WriteString(`*` + strconv.Itoa(len(args)) + "\r\n"); err != nil {
+		return err
+	}
+	for _, arg := range args {
+		if _, err := w.WriteString(`$` + strconv.Itoa(len(arg)) + "\r\n"); err != nil {
+			return err
+		}
+		if _, err := w.Write(arg); err != nil {
+			return err
+		}
+		if _, err := w.WriteString("\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readRESPReply reads and discards a single reply, returning a *redisError
+// if the reply, or any element of it, is an error.
+func readRESPReply(r *bufio.Reader) error {
+	_, err := readRESPValue(r)
+	return err
+}
+
+// readRESPValue reads a single reply. Simple and bulk strings are returned as
+// strings, integers as int64s, arrays as []interface{} and null replies as
+// nil. A *redisError is returned if the reply, or any element of it, is an
+// error.
+func readRESPValue(r *bufio.Reader) (interface{}, error) {
+	line, err := readRESPLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New(`redis: empty reply`)
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, `redis: invalid integer %q`, line)
+		}
+		return n, nil
+	case '-':
+		return nil, &redisError{msg: line[1:]}
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, errors.Wrapf(err, `redis: invalid bulk string length %q`, line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, errors.Wrap(err, `reading from redis`)
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, errors.Wrapf(err, `redis: invalid array length %q`, line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		elems := make([]interface{}, 0, n)
+		var firstErr error
+		for i := 0; i < n; i++ {
+			elem, err := readRESPValue(r)
+			if err != nil {
+				if !errors.HasType(err, (*redisError)(nil)) {
+					return nil, err
+				}
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+			elems = append(elems, elem)
+		}
+		return elems, firstErr
+	default:
+		return nil, errors.Errorf(`redis: unexpected reply %q`, line)
+	}
+}
+
+func readRESPLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return ``, errors.Wrap(err, `reading from redis`)
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
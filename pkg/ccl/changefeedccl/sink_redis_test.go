@@ -0,0 +1,451 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedisServer is an in-process stand-in for a Redis server which speaks
+// just enough of the protocol to serve redisSink.
+type fakeRedisServer struct {
+	ln       net.Listener
+	password string
+
+	mu struct {
+		syncutil.Mutex
+		db      string
+		streams map[string][]string
+		// xaddErr, if set, is the error reply to every XADD.
+		xaddErr string
+	}
+}
+
+func startFakeRedisServer(t *testing.T, password string) *fakeRedisServer {
+	ln, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	require.NoError(t, err)
+	s := &fakeRedisServer{ln: ln, password: password}
+	s.mu.streams = make(map[string][]string)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeRedisServer) Close() {
+	_ = s.ln.Close()
+}
+
+func (s *fakeRedisServer) URL(path string, params url.Values) *url.URL {
+	u := &url.URL{Scheme: changefeedbase.SinkSchemeRedis, Host: s.ln.Addr().String(), Path: path}
+	if s.password != `` {
+		u.User = url.UserPassword(``, s.password)
+	}
+	u.RawQuery = params.Encode()
+	return u
+}
+
+// streams returns the entries of every stream, each formatted as its
+// space-separated fields and values.
+func (s *fakeRedisServer) streams() map[string][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make(map[string][]string, len(s.mu.streams))
+	for name, entries := range s.mu.streams {
+		res[name] = append([]string(nil), entries...)
+	}
+	return res
+}
+
+func (s *fakeRedisServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+	authenticated := s.password == ``
+	for {
+		cmd, err := readFakeRedisCommand(r)
+		if err != nil {
+			return
+		}
+		var reply string
+		switch name := strings.ToUpper(cmd[0]); {
+		case name == `AUTH`:
+			if cmd[len(cmd)-1] != s.password {
+				reply = "-WRONGPASS invalid password\r\n"
+				break
+			}
+			authenticated = true
+			reply = "+OK\r\n"
+		case !authenticated:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case name == `PING`:
+			reply = "+PONG\r\n"
+		case name == `SELECT`:
+			s.mu.Lock()
+			s.mu.db = cmd[1]
+			s.mu.Unlock()
+			reply = "+OK\r\n"
+		case name == `XADD`:
+			reply = s.xadd(cmd[1:])
+		case name == `SCAN`:
+			reply = s.scan(cmd[1:])
+		default:
+			reply = fmt.Sprintf("-ERR unknown command '%s'\r\n", cmd[0])
+		}
+		if _, err := w.WriteString(reply); err != nil {
+			return
+		}
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *fakeRedisServer) xadd(args []string) string {
+	stream, args := args[0], args[1:]
+	maxLen := -1
+	if strings.ToUpper(args[0]) == `MAXLEN` {
+		args = args[1:]
+		if args[0] == `~` || args[0] == `=` {
+			args = args[1:]
+		}
+		maxLen, _ = strconv.Atoi(args[0])
+		args = args[1:]
+	}
+	if args[0] != `*` {
+		return "-ERR only auto-generated IDs are supported\r\n"
+	}
+	args = args[1:]
+	if len(args) == 0 || len(args)%2 != 0 {
+		return "-ERR wrong number of arguments for 'xadd' command\r\n"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.xaddErr != `` {
+		return "-" + s.mu.xaddErr + "\r\n"
+	}
+	entries := append(s.mu.streams[stream], strings.Join(args, ` `))
+	if maxLen >= 0 && len(entries) > maxLen {
+		entries = entries[len(entries)-maxLen:]
+	}
+	s.mu.streams[stream] = entries
+	id := fmt.Sprintf("%d-0", len(entries))
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(id), id)
+}
+
+// scan lists the streams matching the MATCH pattern of a SCAN in a single
+// iteration, returning the first one twice as the server is allowed to.
+func (s *fakeRedisServer) scan(args []string) string {
+	pattern := `*`
+	for i := 1; i+1 < len(args); i += 2 {
+		switch strings.ToUpper(args[i]) {
+		case `MATCH`:
+			pattern = args[i+1]
+		case `TYPE`:
+			if args[i+1] != `stream` {
+				return "-ERR only streams are supported\r\n"
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for name := range s.mu.streams {
+		if ok, err := path.Match(pattern, name); err != nil {
+			return fmt.Sprintf("-ERR %s\r\n", err)
+		} else if ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		names = append(names, names[0])
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*2\r\n$1\r\n0\r\n*%d\r\n", len(names))
+	for _, name := range names {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(name), name)
+	}
+	return b.String()
+}
+
+func readFakeRedisCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readRESPLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, `*`) {
+		return nil, fmt.Errorf(`expected array, got %q`, line)
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+	cmd := make([]string, n)
+	for i := range cmd {
+		line, err := readRESPLine(r)
+		if err != nil {
+			return nil, err
+		}
+		l, err := strconv.Atoi(strings.TrimPrefix(line, `$`))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, l+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		cmd[i] = string(buf[:l])
+	}
+	return cmd, nil
+}
+
+func TestRedisSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	makeTopic := func(name string) *tableDescriptorTopic {
+		id, _ := strconv.ParseUint(name, 36, 64)
+		td := tabledesc.NewBuilder(&descpb.TableDescriptor{Name: name, ID: descpb.ID(id)}).BuildImmutableTable()
+		spec := changefeedbase.Target{
+			Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+			TableID:           td.GetID(),
+			StatementTimeName: changefeedbase.StatementTimeName(name),
+		}
+		return &tableDescriptorTopic{Metadata: makeMetadata(td), spec: spec}
+	}
+	fooTopic := makeTopic(`foo`)
+	barTopic := makeTopic(`bar`)
+	targets := changefeedbase.Targets{}
+	targets.Add(fooTopic.GetTargetSpecification())
+	targets.Add(barTopic.GetTargetSpecification())
+	jsonOpts := changefeedbase.EncodingOptions{Format: changefeedbase.OptFormatJSON}
+
+	makeSink := func(t *testing.T, u *url.URL) *redisSink {
+		sink, err := makeRedisSink(sinkURL{URL: u}, jsonOpts, targets, nilMetricsRecorderBuilder)
+		require.NoError(t, err)
+		require.NoError(t, sink.Dial())
+		t.Cleanup(func() { require.NoError(t, sink.Close()) })
+		return sink.(*redisSink)
+	}
+
+	t.Run("stream per table", func(t *testing.T) {
+		srv := startFakeRedisServer(t, `hunter2`)
+		defer srv.Close()
+		sink := makeSink(t, srv.URL(`/3`, url.Values{
+			changefeedbase.SinkParamTopicPrefix: {`cdc:`},
+		}))
+
+		// Nothing is written until Flush is called.
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`k1`), []byte(`v1`), zeroTS, zeroTS, zeroAlloc))
+		require.NoError(t, sink.EmitRow(ctx, barTopic, []byte(`k2`), []byte(`v2`), zeroTS, zeroTS, zeroAlloc))
+		require.Empty(t, srv.streams())
+		require.NoError(t, sink.Flush(ctx))
+		require.Equal(t, map[string][]string{
+			`cdc:foo`: {`key k1 value v1`},
+			`cdc:bar`: {`key k2 value v2`},
+		}, srv.streams())
+		srv.mu.Lock()
+		require.Equal(t, `3`, srv.mu.db)
+		srv.mu.Unlock()
+
+		// Resolved timestamps are written to every stream.
+		require.NoError(t, sink.EmitResolvedTimestamp(ctx, testEncoder{}, hlc.Timestamp{WallTime: 1}))
+		require.Equal(t, map[string][]string{
+			`cdc:foo`: {`key k1 value v1`, `resolved 0.000000001,0`},
+			`cdc:bar`: {`key k2 value v2`, `resolved 0.000000001,0`},
+		}, srv.streams())
+	})
+
+	t.Run("sharded and trimmed", func(t *testing.T) {
+		srv := startFakeRedisServer(t, ``)
+		defer srv.Close()
+		sink := makeSink(t, srv.URL(``, url.Values{
+			changefeedbase.SinkParamShards:            {`3`},
+			changefeedbase.SinkParamMaxLen:            {`2`},
+			changefeedbase.SinkParamMaxLenApproximate: {`true`},
+			changefeedbase.SinkParamTopicName:         {`events`},
+		}))
+
+		// Messages are sharded by key in the same way as the SQL sink
+		// partitions them (see TestSQLSink).
+		for i := 0; i < 4; i++ {
+			require.NoError(t, sink.EmitRow(ctx, fooTopic,
+				[]byte(`v`+strconv.Itoa(i)), []byte(`a`), zeroTS, zeroTS, zeroAlloc))
+		}
+		require.NoError(t, sink.EmitRow(ctx, barTopic, []byte(`v0`), []byte(`b`), zeroTS, zeroTS, zeroAlloc))
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`v0`), []byte(`c`), zeroTS, zeroTS, zeroAlloc))
+		require.NoError(t, sink.Flush(ctx))
+		require.Equal(t, map[string][]string{
+			`events:0`: {`key v3 value a`},
+			`events:1`: {`key v1 value a`, `key v2 value a`},
+			// Trimmed to the two most recent entries.
+			`events:2`: {`key v0 value b`, `key v0 value c`},
+		}, srv.streams())
+	})
+
+	t.Run("stream per key prefix", func(t *testing.T) {
+		srv := startFakeRedisServer(t, ``)
+		defer srv.Close()
+		sink := makeSink(t, srv.URL(``, url.Values{
+			changefeedbase.SinkParamKeyPrefixColumns: {`2`},
+		}))
+
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`["us", 1, "a"]`), []byte(`v1`), zeroTS, zeroTS, zeroAlloc))
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`["us", 1, "b"]`), []byte(`v2`), zeroTS, zeroTS, zeroAlloc))
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`["eu", 2, "a"]`), []byte(`v3`), zeroTS, zeroTS, zeroAlloc))
+		require.NoError(t, sink.EmitRow(ctx, barTopic, []byte(`["us", 1]`), []byte(`v4`), zeroTS, zeroTS, zeroAlloc))
+		require.Regexp(t, `key_prefix_columns=2 requires keys with at least 2 primary key columns`,
+			sink.EmitRow(ctx, barTopic, []byte(`["us"]`), []byte(`v5`), zeroTS, zeroTS, zeroAlloc))
+		require.NoError(t, sink.Flush(ctx))
+
+		// Resolved timestamps are written to every stream of each topic, which
+		// are found by scanning the database, as well as to the stream named
+		// after the topic, even by a sink that did not emit the messages.
+		resolvedSink := makeSink(t, srv.URL(``, url.Values{
+			changefeedbase.SinkParamKeyPrefixColumns: {`2`},
+		}))
+		require.NoError(t, resolvedSink.EmitResolvedTimestamp(ctx, testEncoder{}, hlc.Timestamp{WallTime: 1}))
+		require.Equal(t, map[string][]string{
+			`foo:us:1`: {`key ["us", 1, "a"] value v1`, `key ["us", 1, "b"] value v2`, `resolved 0.000000001,0`},
+			`foo:eu:2`: {`key ["eu", 2, "a"] value v3`, `resolved 0.000000001,0`},
+			`bar:us:1`: {`key ["us", 1] value v4`, `resolved 0.000000001,0`},
+			`foo`:      {`resolved 0.000000001,0`},
+			`bar`:      {`resolved 0.000000001,0`},
+		}, srv.streams())
+	})
+
+	t.Run("error replies", func(t *testing.T) {
+		srv := startFakeRedisServer(t, ``)
+		defer srv.Close()
+		sink := makeSink(t, srv.URL(``, nil))
+		setXAddErr := func(reply string) {
+			srv.mu.Lock()
+			defer srv.mu.Unlock()
+			srv.mu.xaddErr = reply
+		}
+
+		// A transient error reply is retried by the next flush.
+		setXAddErr(`LOADING Redis is loading the dataset in memory`)
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`k1`), []byte(`v1`), zeroTS, zeroTS, zeroAlloc))
+		err := sink.Flush(ctx)
+		require.Regexp(t, `LOADING`, err)
+		require.True(t, isTransientRedisError(err))
+		setXAddErr(``)
+		require.NoError(t, sink.Flush(ctx))
+		require.Equal(t, map[string][]string{`foo`: {`key k1 value v1`}}, srv.streams())
+
+		// Any other error reply would be returned again, and is terminal.
+		setXAddErr(`WRONGTYPE Operation against a key holding the wrong kind of value`)
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`k2`), []byte(`v2`), zeroTS, zeroTS, zeroAlloc))
+		err = sink.Flush(ctx)
+		require.Regexp(t, `WRONGTYPE`, err)
+		require.False(t, isTransientRedisError(err))
+	})
+
+	t.Run("metrics on ack", func(t *testing.T) {
+		srv := startFakeRedisServer(t, ``)
+		defer srv.Close()
+		m, err := MakeMetrics(base.DefaultHistogramWindowInterval()).(*Metrics).AggMetrics.getOrCreateScope(``)
+		require.NoError(t, err)
+		s, err := makeRedisSink(sinkURL{URL: srv.URL(``, nil)}, jsonOpts, targets,
+			func(bool) metricsRecorder { return m })
+		require.NoError(t, err)
+		require.NoError(t, s.Dial())
+		defer func() { require.NoError(t, s.Close()) }()
+
+		// Messages are only counted as emitted once flushed.
+		ts := hlc.Timestamp{WallTime: 1}
+		require.NoError(t, s.EmitRow(ctx, fooTopic, []byte(`k1`), []byte(`v1`), ts, ts, zeroAlloc))
+		require.NoError(t, s.EmitRow(ctx, fooTopic, []byte(`k2`), []byte(`v22`), ts, ts, zeroAlloc))
+		require.Zero(t, m.EmittedMessages.Value())
+		require.NoError(t, s.Flush(ctx))
+		require.Equal(t, int64(2), m.EmittedMessages.Value())
+		require.Equal(t, int64(9), m.EmittedBytes.Value())
+	})
+
+	t.Run("implicit flush", func(t *testing.T) {
+		srv := startFakeRedisServer(t, ``)
+		defer srv.Close()
+		sink := makeSink(t, srv.URL(``, nil))
+
+		for i := 0; i < redisSinkMaxBatchSize+1; i++ {
+			require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`k`), []byte(`v`), zeroTS, zeroTS, zeroAlloc))
+		}
+		require.Len(t, srv.streams()[`foo`], redisSinkMaxBatchSize)
+		require.NoError(t, sink.Flush(ctx))
+		require.Len(t, srv.streams()[`foo`], redisSinkMaxBatchSize+1)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		srv := startFakeRedisServer(t, `hunter2`)
+		defer srv.Close()
+
+		u := srv.URL(``, nil)
+		u.User = url.UserPassword(``, `wrong`)
+		sink, err := makeRedisSink(sinkURL{URL: u}, jsonOpts, targets, nilMetricsRecorderBuilder)
+		require.NoError(t, err)
+		require.Regexp(t, `WRONGPASS`, sink.Dial())
+
+		for _, tc := range []struct {
+			params url.Values
+			err    string
+		}{
+			{url.Values{changefeedbase.SinkParamShards: {`0`}}, `param shards must be a positive integer`},
+			{url.Values{changefeedbase.SinkParamMaxLen: {`x`}}, `param maxlen must be a positive integer`},
+			{url.Values{changefeedbase.SinkParamMaxLenApproximate: {`true`}}, `maxlen_approximate requires maxlen`},
+			{url.Values{changefeedbase.SinkParamCACert: {`Zm9v`}}, `ca_cert requires tls_enabled=true`},
+			{url.Values{changefeedbase.SinkParamKeyPrefixColumns: {`-1`}}, `param key_prefix_columns must be a positive integer`},
+			{url.Values{
+				changefeedbase.SinkParamKeyPrefixColumns: {`1`},
+				changefeedbase.SinkParamShards:           {`2`},
+			}, `param key_prefix_columns cannot be used with shards`},
+			{url.Values{`foo`: {`bar`}}, `unknown redis sink query parameters: foo`},
+		} {
+			_, err := makeRedisSink(sinkURL{URL: srv.URL(``, tc.params)}, jsonOpts, targets, nilMetricsRecorderBuilder)
+			require.EqualError(t, err, tc.err)
+		}
+
+		_, err = makeRedisSink(sinkURL{URL: srv.URL(``, url.Values{
+			changefeedbase.SinkParamKeyPrefixColumns: {`1`},
+		})}, changefeedbase.EncodingOptions{Format: changefeedbase.OptFormatAvro}, targets, nilMetricsRecorderBuilder)
+		require.EqualError(t, err, `param key_prefix_columns requires format=json`)
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// txnMetadata identifies the transaction which wrote a row.
+//
+// Rangefeeds do not carry transaction IDs, so the commit (MVCC) timestamp of a
+// row is used as the transaction identifier: every row written by a
+// transaction shares its commit timestamp. Distinct transactions which happen
+// to commit at exactly the same timestamp are indistinguishable and are
+// grouped together.
+type txnMetadata struct {
+	// id is the transaction identifier.
+	id hlc.Timestamp
+	// row is the 1-based ordinal of this row amongst the rows of the
+	// transaction emitted so far by this aggregator.
+	row int64
+}
+
+// txnBoundary is emitted once all the rows of a transaction which are
+// watched by an aggregator have been emitted.
+type txnBoundary struct {
+	id       hlc.Timestamp
+	topic    TopicDescriptor
+	rowCount int64
+}
+
+// txnBoundaryEncoder is implemented by encoders which support the
+// txn_boundaries option.
+type txnBoundaryEncoder interface {
+	// EncodeTxnBoundary encodes the key and value of a transaction boundary
+	// marker.
+	EncodeTxnBoundary(ctx context.Context, b txnBoundary) (key, value []byte, _ error)
+}
+
+// openTxn tracks the rows of a transaction emitted to a single topic.
+type openTxn struct {
+	topic TopicDescriptor
+	rows  int64
+}
+
+// txnTracker counts the rows emitted for each transaction whose commit
+// timestamp is above the local frontier.
+//
+// Rows of a transaction may be spread over many ranges, and hence may be
+// emitted by many aggregators; each aggregator tracks (and emits boundary
+// markers for) only the rows it emitted itself. The row counts of all
+// boundary markers for a transaction on a topic sum to the number of rows
+// that transaction wrote to the topic.
+type txnTracker struct {
+	open map[hlc.Timestamp]map[TopicIdentifier]*openTxn
+}
+
+func makeTxnTracker() txnTracker {
+	return txnTracker{open: make(map[hlc.Timestamp]map[TopicIdentifier]*openTxn)}
+}
+
+// noteRow records a row written by the transaction that committed at mvcc and
+// returns its metadata.
+func (t *txnTracker) noteRow(mvcc hlc.Timestamp, topic TopicDescriptor) txnMetadata {
+	byTopic, ok := t.open[mvcc]
+	if !ok {
+		byTopic = make(map[TopicIdentifier]*openTxn)
+		t.open[mvcc] = byTopic
+	}
+	txn, ok := byTopic[topic.GetTopicIdentifier()]
+	if !ok {
+		txn = &openTxn{topic: topic}
+		byTopic[topic.GetTopicIdentifier()] = txn
+	}
+	txn.rows++
+
+	var total int64
+	for _, txn := range byTopic {
+		total += txn.rows
+	}
+	return txnMetadata{id: mvcc, row: total}
+}
+
+// closeResolved invokes fn, in timestamp order, for every tracked
+// transaction which committed at or below the frontier and stops tracking
+// them. Once the frontier has passed a timestamp, no more rows for that
+// transaction can be emitted by this aggregator.
+func (t *txnTracker) closeResolved(frontier hlc.Timestamp, fn func(txnBoundary) error) error {
+	var resolved []hlc.Timestamp
+	for ts := range t.open {
+		if ts.LessEq(frontier) {
+			resolved = append(resolved, ts)
+		}
+	}
+	sort.Slice(resolved, func(i, j int) bool { return resolved[i].Less(resolved[j]) })
+
+	for _, ts := range resolved {
+		byTopic := t.open[ts]
+		topics := make([]TopicIdentifier, 0, len(byTopic))
+		for id := range byTopic {
+			topics = append(topics, id)
+		}
+		sort.Slice(topics, func(i, j int) bool {
+			if topics[i].TableID != topics[j].TableID {
+				return topics[i].TableID < topics[j].TableID
+			}
+			return topics[i].FamilyID < topics[j].FamilyID
+		})
+		for _, id := range topics {
+			txn := byTopic[id]
+			if err := fn(txnBoundary{id: ts, topic: txn.topic, rowCount: txn.rows}); err != nil {
+				return err
+			}
+		}
+		delete(t.open, ts)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxnTracker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	t1 := &tableDescriptorTopic{Metadata: cdcevent.Metadata{TableID: 1}}
+	t2 := &tableDescriptorTopic{Metadata: cdcevent.Metadata{TableID: 2}}
+	ts := func(wall int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wall} }
+
+	tracker := makeTxnTracker()
+	require.Equal(t, txnMetadata{id: ts(10), row: 1}, tracker.noteRow(ts(10), t1))
+	require.Equal(t, txnMetadata{id: ts(20), row: 1}, tracker.noteRow(ts(20), t1))
+	require.Equal(t, txnMetadata{id: ts(10), row: 2}, tracker.noteRow(ts(10), t2))
+	require.Equal(t, txnMetadata{id: ts(10), row: 3}, tracker.noteRow(ts(10), t1))
+
+	type boundary struct {
+		id       hlc.Timestamp
+		table    int
+		rowCount int64
+	}
+	closeResolved := func(frontier hlc.Timestamp) (res []boundary) {
+		require.NoError(t, tracker.closeResolved(frontier, func(b txnBoundary) error {
+			res = append(res, boundary{
+				id:       b.id,
+				table:    int(b.topic.GetTopicIdentifier().TableID),
+				rowCount: b.rowCount,
+			})
+			return nil
+		}))
+		return res
+	}
+
+	require.Nil(t, closeResolved(ts(9)))
+	require.Equal(t, []boundary{{ts(10), 1, 2}, {ts(10), 2, 1}}, closeResolved(ts(15)))
+	// Transactions are only closed once.
+	require.Nil(t, closeResolved(ts(15)))
+	require.Equal(t, []boundary{{ts(20), 1, 1}}, closeResolved(ts(20)))
+}
+
+func TestJSONEncoderTxnMetadata(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	id := hlc.Timestamp{WallTime: 17, Logical: 2}
+	for _, tc := range []struct {
+		envelope      changefeedbase.EnvelopeType
+		expectedValue string
+	}{
+		{
+			envelope:      changefeedbase.OptEnvelopeWrapped,
+			expectedValue: `{"txn_boundary":{"id":"17.0000000002","row_count":3}}`,
+		},
+		{
+			envelope:      changefeedbase.OptEnvelopeBare,
+			expectedValue: `{"__crdb__":{"txn_boundary":{"id":"17.0000000002","row_count":3}}}`,
+		},
+	} {
+		t.Run(string(tc.envelope), func(t *testing.T) {
+			e, err := makeJSONEncoder(changefeedbase.EncodingOptions{
+				Format:        changefeedbase.OptFormatJSON,
+				Envelope:      tc.envelope,
+				TxnMetadata:   true,
+				TxnBoundaries: true,
+			})
+			require.NoError(t, err)
+			key, value, err := e.EncodeTxnBoundary(context.Background(), txnBoundary{id: id, rowCount: 3})
+			require.NoError(t, err)
+			require.Equal(t, `["17.0000000002"]`, string(key))
+			require.Equal(t, tc.expectedValue, string(value))
+		})
+	}
+
+	_, err := makeJSONEncoder(changefeedbase.EncodingOptions{
+		Format:      changefeedbase.OptFormatJSON,
+		Envelope:    changefeedbase.OptEnvelopeRow,
+		TxnMetadata: true,
+	})
+	require.Error(t, err)
+}
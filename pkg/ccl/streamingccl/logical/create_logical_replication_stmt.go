@@ -0,0 +1,328 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package logical
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streamclient"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// optFunction names the user-defined function that resolves the conflicts
+	// between the replicated rows and the rows of the destination table.
+	optFunction = "function"
+	// optDeadLetterTable names the table that the rows that cannot be applied
+	// are written to.
+	optDeadLetterTable = "dead_letter_table"
+)
+
+var createLogicalReplicationStreamOptions = exprutil.KVOptionValidationMap{
+	optFunction:        exprutil.KVStringOptRequireValue,
+	optDeadLetterTable: exprutil.KVStringOptRequireValue,
+}
+
+var createLogicalReplicationStreamHeader = colinfo.ResultColumns{
+	{Name: "job_id", Typ: types.Int},
+}
+
+// deadLetterTablePrefix prefixes the name of the destination table to form
+// the name of its default dead letter table.
+const deadLetterTablePrefix = "crdb_replication_dlq_"
+
+func createLogicalReplicationStreamTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, _ colinfo.ResultColumns, _ error) {
+	createStmt, ok := stmt.(*tree.CreateLogicalReplicationStream)
+	if !ok {
+		return false, nil, nil
+	}
+	if err := exprutil.TypeCheck(ctx, "LOGICAL REPLICATION", p.SemaCtx(),
+		exprutil.Strings{createStmt.PGURL},
+		&exprutil.KVOptions{
+			KVOptions:  createStmt.Options,
+			Validation: createLogicalReplicationStreamOptions,
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, createLogicalReplicationStreamHeader, nil
+}
+
+func createLogicalReplicationStreamPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	createStmt, ok := stmt.(*tree.CreateLogicalReplicationStream)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	if !streamingccl.CrossClusterReplicationEnabled.Get(&p.ExecCfg().Settings.SV) {
+		return nil, nil, nil, false, errors.WithTelemetry(
+			pgerror.WithCandidateCode(
+				errors.WithHint(
+					errors.Newf("cross cluster replication is disabled"),
+					"You can enable cross cluster replication by running `SET CLUSTER SETTING cross_cluster_replication.enabled = true`.",
+				),
+				pgcode.ExperimentalFeature,
+			),
+			"cross_cluster_replication.enabled",
+		)
+	}
+
+	exprEval := p.ExprEvaluator("LOGICAL REPLICATION")
+	from, err := exprEval.String(ctx, createStmt.PGURL)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	options, err := exprEval.KVOptions(ctx, createStmt.Options, createLogicalReplicationStreamOptions)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if err := utilccl.CheckEnterpriseEnabled(
+			p.ExecCfg().Settings, p.ExecCfg().NodeInfo.LogicalClusterID(),
+			"CREATE LOGICAL REPLICATION STREAM",
+		); err != nil {
+			return err
+		}
+
+		intoName := createStmt.Into.ToTableName()
+		prefix, dstTable, err := p.ResolveMutableTableDescriptor(ctx, &intoName, true, /* required */
+			tree.ResolveRequireTableDesc)
+		if err != nil {
+			return err
+		}
+		if err := p.CheckPrivilege(ctx, dstTable, privilege.INSERT); err != nil {
+			return err
+		}
+		if err := p.CheckPrivilege(ctx, dstTable, privilege.DELETE); err != nil {
+			return err
+		}
+		if err := validateOriginTimestampColumn(dstTable); err != nil {
+			return err
+		}
+
+		var functionID descpb.ID
+		if name, ok := options[optFunction]; ok {
+			functionID, err = resolveConflictResolutionFunction(ctx, p, name)
+			if err != nil {
+				return err
+			}
+		}
+
+		deadLetterTable := tree.MakeTableNameWithSchema(
+			tree.Name(prefix.Database.GetName()), tree.Name(prefix.Schema.GetName()),
+			tree.Name(deadLetterTablePrefix+dstTable.GetName()))
+		if name, ok := options[optDeadLetterTable]; ok {
+			tn, err := parser.ParseTableName(name)
+			if err != nil {
+				return err
+			}
+			deadLetterTable.ObjectName = tree.Name(tn.Object())
+			if tn.HasExplicitSchema() {
+				deadLetterTable.SchemaName = tree.Name(tn.Schema())
+			}
+			if tn.HasExplicitCatalog() {
+				deadLetterTable.CatalogName = tree.Name(tn.Catalog())
+			}
+		}
+
+		streamAddress := streamingccl.StreamAddress(from)
+		client, err := streamclient.NewStreamClient(ctx, streamAddress, p.ExecCfg().InternalDB)
+		if err != nil {
+			return err
+		}
+		spec, err := client.CreateForTables(ctx, streampb.ReplicationProducerRequest{
+			TableNames: []string{createStmt.From.String()},
+		})
+		if err != nil {
+			return err
+		}
+		if err := client.Close(ctx); err != nil {
+			return err
+		}
+		if len(spec.TableDescriptors) != 1 {
+			return errors.AssertionFailedf("expected the descriptor of 1 table, got %d",
+				len(spec.TableDescriptors))
+		}
+		srcTable := tabledesc.NewBuilder(&spec.TableDescriptors[0]).BuildImmutableTable()
+		if err := validateTablesCompatible(srcTable, dstTable); err != nil {
+			return errors.Wrapf(err, "table %s cannot be replicated into table %s",
+				createStmt.From, createStmt.Into)
+		}
+
+		description, err := logicalReplicationJobDescription(p, from, createStmt)
+		if err != nil {
+			return err
+		}
+		jr := jobs.Record{
+			JobID:       p.ExecCfg().JobRegistry.MakeJobID(),
+			Description: description,
+			Username:    p.User(),
+			Details: jobspb.LogicalReplicationDetails{
+				SourceClusterConnStr: string(streamAddress),
+				StreamID:             uint64(spec.StreamID),
+				SourceTableID:        srcTable.GetID(),
+				DestinationTableID:   dstTable.GetID(),
+				ReplicationStartTime: spec.ReplicationStartTime,
+				FunctionID:           functionID,
+				DeadLetterTable:      deadLetterTable.FQString(),
+			},
+			Progress: jobspb.LogicalReplicationProgress{},
+		}
+		if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+			ctx, jr, jr.JobID, p.InternalSQLTxn(),
+		); err != nil {
+			return err
+		}
+		resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jr.JobID))}
+		return nil
+	}
+	return fn, createLogicalReplicationStreamHeader, nil, false, nil
+}
+
+func logicalReplicationJobDescription(
+	p sql.PlanHookState, from string, createStmt *tree.CreateLogicalReplicationStream,
+) (string, error) {
+	redactedFrom, err := cloud.SanitizeExternalStorageURI(from, streamclient.RedactableURLParameters)
+	if err != nil {
+		return "", err
+	}
+	redactedStmt := *createStmt
+	redactedStmt.PGURL = tree.NewDString(redactedFrom)
+	ann := p.ExtendedEvalContext().Annotations
+	return tree.AsStringWithFQNames(&redactedStmt, ann), nil
+}
+
+// resolveConflictResolutionFunction resolves the name of the user-defined
+// function that resolves the conflicts of a logical replication stream, and
+// checks that it has the expected signature:
+//
+//	(action STRING, proposed JSONB, existing JSONB,
+//	 existing_origin_timestamp DECIMAL, proposed_origin_timestamp DECIMAL)
+//	RETURNS STRING
+func resolveConflictResolutionFunction(
+	ctx context.Context, p sql.PlanHookState, name string,
+) (descpb.ID, error) {
+	un, err := parser.ParseTableName(name)
+	if err != nil {
+		return 0, err
+	}
+	path := p.CurrentSearchPath()
+	def, err := p.ResolveFunction(ctx, un.ToUnresolvedName(), &path)
+	if err != nil {
+		return 0, err
+	}
+	for _, o := range def.Overloads {
+		if !o.IsUDF || !o.Types.MatchIdentical(conflictResolutionFunctionParams) {
+			continue
+		}
+		if !o.ReturnType(nil /* args */).Identical(types.String) {
+			return 0, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"conflict resolution function %s must return STRING", name)
+		}
+		return funcdesc.UserDefinedFunctionOIDToID(o.Oid), nil
+	}
+	return 0, pgerror.Newf(pgcode.UndefinedFunction,
+		"conflict resolution function %s(STRING, JSONB, JSONB, DECIMAL, DECIMAL) does not exist", name)
+}
+
+// conflictResolutionFunctionParams are the types of the parameters of a
+// conflict resolution function.
+var conflictResolutionFunctionParams = []*types.T{
+	types.String, types.Jsonb, types.Jsonb, types.Decimal, types.Decimal,
+}
+
+// validateOriginTimestampColumn checks that the destination table has the
+// column that records the origin timestamp of the rows written by logical
+// replication. The column must be reset whenever the row is written by anyone
+// else, which ON UPDATE NULL takes care of.
+func validateOriginTimestampColumn(desc catalog.TableDescriptor) error {
+	col, err := catalog.MustFindColumnByName(desc, originTimestampColumnName)
+	if err != nil {
+		return errors.WithHintf(err,
+			"tables replicated by logical replication must have a column %s DECIMAL NOT VISIBLE NULL ON UPDATE NULL",
+			originTimestampColumnName)
+	}
+	if col.GetType().Family() != types.DecimalFamily || !col.IsNullable() || col.IsComputed() ||
+		!col.HasOnUpdate() || col.GetOnUpdateExpr() != "NULL" {
+		return pgerror.Newf(pgcode.InvalidTableDefinition,
+			"column %s of table %s must be defined as %s DECIMAL NOT VISIBLE NULL ON UPDATE NULL",
+			originTimestampColumnName, desc.GetName(), originTimestampColumnName)
+	}
+	return nil
+}
+
+// validateTablesCompatible checks that the rows of the source table can be
+// decoded with the descriptor of the destination table, which is the case if
+// they have the same primary index and the same columns, stored in a single
+// column family.
+func validateTablesCompatible(src, dst catalog.TableDescriptor) error {
+	for _, desc := range []catalog.TableDescriptor{src, dst} {
+		if len(desc.GetFamilies()) != 1 {
+			return errors.Newf("table %s has more than one column family", desc.GetName())
+		}
+	}
+	srcIndex, dstIndex := src.GetPrimaryIndex(), dst.GetPrimaryIndex()
+	if srcIndex.GetID() != dstIndex.GetID() || srcIndex.NumKeyColumns() != dstIndex.NumKeyColumns() {
+		return errors.New("the tables have different primary keys")
+	}
+	for i := 0; i < srcIndex.NumKeyColumns(); i++ {
+		if srcIndex.GetKeyColumnID(i) != dstIndex.GetKeyColumnID(i) ||
+			srcIndex.GetKeyColumnDirection(i) != dstIndex.GetKeyColumnDirection(i) {
+			return errors.New("the tables have different primary keys")
+		}
+	}
+	srcCols, dstCols := src.PublicColumns(), dst.PublicColumns()
+	if len(srcCols) != len(dstCols) {
+		return errors.New("the tables have different columns")
+	}
+	for _, dstCol := range dstCols {
+		srcCol := catalog.FindColumnByID(src, dstCol.GetID())
+		if srcCol == nil || !srcCol.Public() || srcCol.GetName() != dstCol.GetName() {
+			return errors.Newf("column %s does not exist in both tables", dstCol.GetName())
+		}
+		if !srcCol.GetType().Identical(dstCol.GetType()) {
+			return errors.Newf("column %s has type %s in the source table and %s in the destination table",
+				dstCol.GetName(), srcCol.GetType().SQLString(), dstCol.GetType().SQLString())
+		}
+	}
+	return nil
+}
+
+func init() {
+	sql.AddPlanHook("create logical replication stream", createLogicalReplicationStreamPlanHook,
+		createLogicalReplicationStreamTypeCheck)
+}
@@ -0,0 +1,308 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package logical
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streamclient"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/errors"
+)
+
+// logicalReplicationResumer implements the logical replication job, which
+// applies the changes of a table of a source cluster, as streamed by a
+// replication stream, to a table of this cluster.
+//
+// The job subscribes to every partition of the stream from a single node and
+// applies the changes of each partition in the order they arrive. The time up
+// to which every change was applied is recorded in the progress of the job
+// and reported to the source cluster, which may then let go of the older
+// revisions of the table.
+type logicalReplicationResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &logicalReplicationResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *logicalReplicationResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	if err := r.ingest(ctx, execCtx.(sql.JobExecContext)); err != nil {
+		return r.handleResumeError(ctx, err)
+	}
+	return nil
+}
+
+// The logical replication job should never fail, only pause, as the
+// replication stream can only be resumed from where it left off.
+func (r *logicalReplicationResumer) handleResumeError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	const errorFmt = "logical replication job failed (%v) but is being paused"
+	errorMessage := fmt.Sprintf(errorFmt, err)
+	log.Warningf(ctx, errorFmt, err)
+	return r.job.NoTxn().PauseRequestedWithFunc(ctx, func(
+		ctx context.Context, planHookState interface{}, txn isql.Txn,
+		progress *jobspb.Progress,
+	) error {
+		progress.RunningStatus = errorMessage
+		return nil
+	}, errorMessage)
+}
+
+func (r *logicalReplicationResumer) ingest(ctx context.Context, execCtx sql.JobExecContext) error {
+	execCfg := execCtx.ExecCfg()
+	details := r.job.Details().(jobspb.LogicalReplicationDetails)
+	progress := r.job.Progress()
+	replicatedTime := progress.GetLogicalReplication().ReplicatedTime
+	streamID := streampb.StreamID(details.StreamID)
+
+	if err := createDeadLetterTable(ctx, execCfg.InternalDB, details.DeadLetterTable); err != nil {
+		return errors.Wrapf(err, "creating dead letter table %s", details.DeadLetterTable)
+	}
+	var table catalog.TableDescriptor
+	var replicatedDeletesTable string
+	if err := sql.DescsTxn(ctx, execCfg, func(
+		ctx context.Context, txn isql.Txn, col *descs.Collection,
+	) (err error) {
+		table, err = col.ByID(txn.KV()).WithoutNonPublic().Get().Table(ctx, details.DestinationTableID)
+		if err != nil {
+			return err
+		}
+		db, err := col.ByID(txn.KV()).WithoutNonPublic().Get().Database(ctx, table.GetParentID())
+		if err != nil {
+			return err
+		}
+		schema, err := col.ByID(txn.KV()).WithoutNonPublic().Get().Schema(ctx, table.GetParentSchemaID())
+		if err != nil {
+			return err
+		}
+		name := tree.MakeTableNameWithSchema(tree.Name(db.GetName()), tree.Name(schema.GetName()),
+			tree.Name(streamingccl.ReplicatedDeletesTablePrefix+table.GetName()))
+		replicatedDeletesTable = name.FQString()
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := createReplicatedDeletesTable(ctx, execCfg.InternalDB, replicatedDeletesTable); err != nil {
+		return errors.Wrapf(err, "creating replicated deletes table %s", replicatedDeletesTable)
+	}
+
+	client, err := streamclient.NewStreamClient(ctx,
+		streamingccl.StreamAddress(details.SourceClusterConnStr), execCfg.InternalDB)
+	if err != nil {
+		return err
+	}
+	defer closeClient(ctx, client)
+	if err := heartbeat(ctx, client, streamID, replicatedTime); err != nil {
+		return err
+	}
+	topology, err := client.Plan(ctx, streamID)
+	if err != nil {
+		return err
+	}
+
+	var spans []roachpb.Span
+	for _, partition := range topology.Partitions {
+		spans = append(spans, partition.Spans...)
+	}
+	frontier, err := span.MakeFrontierAt(replicatedTime, spans...)
+	if err != nil {
+		return err
+	}
+
+	g := ctxgroup.WithContext(ctx)
+	for _, partition := range topology.Partitions {
+		partitionClient, err := streamclient.NewStreamClient(ctx,
+			streamingccl.StreamAddress(partition.SrcAddr), execCfg.InternalDB)
+		if err != nil {
+			return errors.Wrapf(err, "creating client for partition %s", partition.ID)
+		}
+		defer closeClient(ctx, partitionClient)
+		sub, err := partitionClient.Subscribe(ctx, streamID, partition.SubscriptionToken,
+			details.ReplicationStartTime, replicatedTime)
+		if err != nil {
+			return errors.Wrapf(err, "subscribing to partition %s", partition.ID)
+		}
+		// The row processor decodes rows with a fetcher, which cannot be shared
+		// between partitions.
+		rp, err := newRowProcessor(ctx, execCfg.InternalDB, execCfg.Codec, r.job.ID(), details, table,
+			replicatedDeletesTable)
+		if err != nil {
+			return err
+		}
+		g.GoCtx(sub.Subscribe)
+		g.GoCtx(func(ctx context.Context) error {
+			return consumePartition(ctx, sub, rp, frontier)
+		})
+	}
+	g.GoCtx(func(ctx context.Context) error {
+		return r.checkpointLoop(ctx, execCfg, client, streamID, frontier, replicatedTime)
+	})
+	return g.Wait()
+}
+
+// consumePartition applies the changes of a partition of the replication
+// stream, and forwards the frontier of the stream once all the changes up to
+// a checkpoint of the partition were applied.
+func consumePartition(
+	ctx context.Context, sub streamclient.Subscription, rp *rowProcessor, frontier *span.Frontier,
+) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-sub.Events():
+			if !ok {
+				return sub.Err()
+			}
+			switch event.Type() {
+			case streamingccl.KVEvent:
+				if err := rp.processKV(ctx, *event.GetKV()); err != nil {
+					return err
+				}
+			case streamingccl.CheckpointEvent:
+				for _, resolvedSpan := range event.GetResolvedSpans() {
+					if _, err := frontier.Forward(resolvedSpan.Span, resolvedSpan.Timestamp); err != nil {
+						return err
+					}
+				}
+			case streamingccl.SSTableEvent, streamingccl.DeleteRangeEvent:
+				// Bulk writes do not go through SQL, and neither could their
+				// replication.
+				return errors.Newf("logical replication does not support the bulk writes to table %s",
+					rp.table.GetName())
+			default:
+				return errors.Newf("unknown streaming event type %v", event.Type())
+			}
+		}
+	}
+}
+
+// checkpointLoop periodically records the time up to which every change of
+// the stream was applied in the progress of the job, and heartbeats the
+// replication stream with it.
+func (r *logicalReplicationResumer) checkpointLoop(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	client streamclient.Client,
+	streamID streampb.StreamID,
+	frontier *span.Frontier,
+	replicatedTime hlc.Timestamp,
+) error {
+	sv := &execCfg.Settings.SV
+	timer := time.NewTimer(streamingccl.StreamReplicationConsumerHeartbeatFrequency.Get(sv))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			timer.Reset(streamingccl.StreamReplicationConsumerHeartbeatFrequency.Get(sv))
+		}
+		if ts := frontier.Frontier(); replicatedTime.Less(ts) {
+			if err := r.checkpoint(ctx, ts); err != nil {
+				return err
+			}
+			replicatedTime = ts
+		}
+		if err := heartbeat(ctx, client, streamID, replicatedTime); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *logicalReplicationResumer) checkpoint(
+	ctx context.Context, replicatedTime hlc.Timestamp,
+) error {
+	return r.job.NoTxn().Update(ctx, func(
+		txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		md.Progress.GetLogicalReplication().ReplicatedTime = replicatedTime
+		md.Progress.Progress = &jobspb.Progress_HighWater{HighWater: &replicatedTime}
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
+// heartbeat heartbeats the replication stream and returns an error if the
+// stream is no longer active.
+func heartbeat(
+	ctx context.Context, client streamclient.Client, streamID streampb.StreamID, ts hlc.Timestamp,
+) error {
+	status, err := client.Heartbeat(ctx, streamID, ts)
+	if err != nil {
+		return err
+	}
+	switch status.StreamStatus {
+	case streampb.StreamReplicationStatus_STREAM_ACTIVE:
+	case streampb.StreamReplicationStatus_UNKNOWN_STREAM_STATUS_RETRY:
+		log.Warningf(ctx, "replication stream %d has unknown stream status and will be retried", streamID)
+	default:
+		return streamingccl.NewStreamStatusErr(streamID, status.StreamStatus)
+	}
+	return nil
+}
+
+func closeClient(ctx context.Context, client streamclient.Client) {
+	if err := client.Close(ctx); err != nil {
+		log.Warningf(ctx, "encountered error when closing the stream client: %v", err)
+	}
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. The producer job of
+// the replication stream is canceled on best effort, which releases the
+// protected timestamp record of the source table.
+func (r *logicalReplicationResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{}, _ error,
+) error {
+	details := r.job.Details().(jobspb.LogicalReplicationDetails)
+	streamID := streampb.StreamID(details.StreamID)
+	client, err := streamclient.NewStreamClient(ctx,
+		streamingccl.StreamAddress(details.SourceClusterConnStr),
+		execCtx.(sql.JobExecContext).ExecCfg().InternalDB)
+	if err != nil {
+		log.Warningf(ctx, "encountered error when creating the stream client: %v", err)
+		return nil
+	}
+	defer closeClient(ctx, client)
+	log.Infof(ctx, "canceling the producer job %d as logical replication job %d is being canceled",
+		streamID, r.job.ID())
+	if err := client.Complete(ctx, streamID, false /* successfulIngestion */); err != nil {
+		log.Warningf(ctx, "encountered error when canceling the producer job: %v", err)
+	}
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeLogicalReplication,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &logicalReplicationResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+	)
+}
@@ -0,0 +1,200 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package logical_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+const replicatedTableSchema = `(
+	k INT PRIMARY KEY,
+	v STRING,
+	crdb_replication_origin_timestamp DECIMAL NOT VISIBLE NULL ON UPDATE NULL
+)`
+
+// startLogicalReplicationTest starts a server whose databases a and b each
+// have a table tab that can be replicated into the other, and returns a SQL
+// runner and the URL of the server.
+func startLogicalReplicationTest(
+	t *testing.T,
+) (*sqlutils.SQLRunner, url.URL, func()) {
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestTenantDisabled,
+		Knobs: base.TestingKnobs{
+			JobsTestingKnobs: jobs.NewTestingKnobsWithShortIntervals(),
+		},
+	})
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING cross_cluster_replication.enabled = true`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING stream_replication.consumer_heartbeat_frequency = '100ms'`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING stream_replication.min_checkpoint_frequency = '100ms'`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.target_duration = '100ms'`)
+	for _, dbName := range []string{"a", "b"} {
+		sqlDB.Exec(t, `CREATE DATABASE `+dbName)
+		sqlDB.Exec(t, `CREATE TABLE `+dbName+`.tab `+replicatedTableSchema)
+	}
+	pgURL, cleanupURL := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(username.RootUser))
+	return sqlDB, pgURL, func() {
+		cleanupURL()
+		s.Stopper().Stop(ctx)
+	}
+}
+
+func TestLogicalReplicationBidirectional(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sqlDB, pgURL, cleanup := startLogicalReplicationTest(t)
+	defer cleanup()
+
+	sqlDB.Exec(t, `INSERT INTO a.tab VALUES (1, 'a-1')`)
+	var jobAToB, jobBToA int
+	sqlDB.QueryRow(t, `CREATE LOGICAL REPLICATION STREAM FROM TABLE a.tab ON $1 INTO TABLE b.tab`,
+		pgURL.String()).Scan(&jobAToB)
+	sqlDB.QueryRow(t, `CREATE LOGICAL REPLICATION STREAM FROM TABLE b.tab ON $1 INTO TABLE a.tab`,
+		pgURL.String()).Scan(&jobBToA)
+
+	// The existing rows are replicated by the initial scan.
+	sqlDB.CheckQueryResultsRetry(t, `SELECT k, v FROM b.tab`, [][]string{{"1", "a-1"}})
+
+	sqlDB.Exec(t, `INSERT INTO a.tab VALUES (2, 'a-2')`)
+	sqlDB.Exec(t, `INSERT INTO b.tab VALUES (3, 'b-3')`)
+	sqlDB.Exec(t, `UPDATE b.tab SET v = 'b-1' WHERE k = 1`)
+	expected := [][]string{{"1", "b-1"}, {"2", "a-2"}, {"3", "b-3"}}
+	sqlDB.CheckQueryResultsRetry(t, `SELECT k, v FROM a.tab ORDER BY k`, expected)
+	sqlDB.CheckQueryResultsRetry(t, `SELECT k, v FROM b.tab ORDER BY k`, expected)
+
+	// The rows written by logical replication record their origin, and the
+	// rows written locally do not.
+	sqlDB.CheckQueryResults(t,
+		`SELECT k, crdb_replication_origin_timestamp IS NOT NULL FROM a.tab ORDER BY k`,
+		[][]string{{"1", "true"}, {"2", "false"}, {"3", "true"}})
+
+	sqlDB.Exec(t, `DELETE FROM a.tab WHERE k = 3`)
+	expected = [][]string{{"1", "b-1"}, {"2", "a-2"}}
+	sqlDB.CheckQueryResultsRetry(t, `SELECT k, v FROM b.tab ORDER BY k`, expected)
+	sqlDB.CheckQueryResults(t, `SELECT k, v FROM a.tab ORDER BY k`, expected)
+
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM a.crdb_replication_dlq_tab`, [][]string{{"0"}})
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM b.crdb_replication_dlq_tab`, [][]string{{"0"}})
+}
+
+// TestLogicalReplicationDeleteThenReinsert verifies that the deletes applied
+// by logical replication are not replicated back to the cluster they came
+// from, where they would win against the rows written after them.
+func TestLogicalReplicationDeleteThenReinsert(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sqlDB, pgURL, cleanup := startLogicalReplicationTest(t)
+	defer cleanup()
+
+	var jobAToB, jobBToA int
+	sqlDB.QueryRow(t, `CREATE LOGICAL REPLICATION STREAM FROM TABLE a.tab ON $1 INTO TABLE b.tab`,
+		pgURL.String()).Scan(&jobAToB)
+	sqlDB.QueryRow(t, `CREATE LOGICAL REPLICATION STREAM FROM TABLE b.tab ON $1 INTO TABLE a.tab`,
+		pgURL.String()).Scan(&jobBToA)
+
+	sqlDB.Exec(t, `INSERT INTO a.tab VALUES (1, 'a-1')`)
+	sqlDB.CheckQueryResultsRetry(t, `SELECT k, v FROM b.tab`, [][]string{{"1", "a-1"}})
+
+	// The row is reinserted right away, most likely before the delete is
+	// applied to b.tab, and thus at an earlier timestamp than the delete there.
+	sqlDB.Exec(t, `DELETE FROM a.tab WHERE k = 1`)
+	sqlDB.Exec(t, `INSERT INTO a.tab VALUES (1, 'a-2')`)
+	sqlDB.CheckQueryResultsRetry(t, `SELECT k, v FROM b.tab`, [][]string{{"1", "a-2"}})
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM b.crdb_replication_deletes_tab`, [][]string{{"1"}})
+
+	// Once the changes of b.tab up to now are applied to a.tab, the delete
+	// applied to b.tab would have been replicated back.
+	var now string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&now)
+	testutils.SucceedsSoon(t, func() error {
+		var caughtUp bool
+		sqlDB.QueryRow(t, `SELECT COALESCE(high_water_timestamp >= $1::DECIMAL, false)
+FROM crdb_internal.jobs WHERE job_id = $2`, now, jobBToA).Scan(&caughtUp)
+		if !caughtUp {
+			return errors.Newf("logical replication job %d has not caught up to %s", jobBToA, now)
+		}
+		return nil
+	})
+	sqlDB.CheckQueryResults(t, `SELECT k, v FROM a.tab`, [][]string{{"1", "a-2"}})
+	sqlDB.CheckQueryResults(t, `SELECT k, v FROM b.tab`, [][]string{{"1", "a-2"}})
+
+	// A delete of b.tab that was not applied by logical replication is
+	// replicated.
+	sqlDB.Exec(t, `DELETE FROM b.tab WHERE k = 1`)
+	sqlDB.CheckQueryResultsRetry(t, `SELECT count(*) FROM a.tab`, [][]string{{"0"}})
+}
+
+func TestLogicalReplicationConflictResolution(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sqlDB, pgURL, cleanup := startLogicalReplicationTest(t)
+	defer cleanup()
+
+	// The function keeps the existing rows whose value starts with 'keep' and
+	// fails the rows whose value starts with 'fail'.
+	sqlDB.Exec(t, `
+CREATE FUNCTION b.resolve(action STRING, proposed JSONB, existing JSONB,
+                          existing_origin_timestamp DECIMAL, proposed_origin_timestamp DECIMAL)
+RETURNS STRING AS $$
+SELECT CASE
+  WHEN existing->>'v' LIKE 'keep%' THEN 'ignore_proposed'
+  WHEN existing->>'v' LIKE 'fail%' THEN 'give up'
+  ELSE 'accept_proposed'
+END
+$$ LANGUAGE SQL`)
+	sqlDB.Exec(t, `INSERT INTO b.tab VALUES (1, 'keep'), (2, 'fail'), (3, 'replace')`)
+	sqlDB.Exec(t, `INSERT INTO a.tab VALUES (1, 'a-1'), (2, 'a-2'), (3, 'a-3'), (4, 'a-4')`)
+
+	sqlDB.ExpectErr(t, "conflict resolution function b.nonexistent.* does not exist",
+		`CREATE LOGICAL REPLICATION STREAM FROM TABLE a.tab ON $1 INTO TABLE b.tab WITH function = 'b.nonexistent'`,
+		pgURL.String())
+	sqlDB.Exec(t,
+		`CREATE LOGICAL REPLICATION STREAM FROM TABLE a.tab ON $1 INTO TABLE b.tab
+WITH function = 'b.resolve', dead_letter_table = 'b.dlq'`,
+		pgURL.String())
+
+	sqlDB.CheckQueryResultsRetry(t, `SELECT k, v FROM b.tab ORDER BY k`,
+		[][]string{{"1", "keep"}, {"2", "fail"}, {"3", "a-3"}, {"4", "a-4"}})
+	sqlDB.CheckQueryResultsRetry(t, `SELECT error LIKE '%give up%' FROM b.dlq`, [][]string{{"true"}})
+}
+
+func TestLogicalReplicationIncompatibleTables(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sqlDB, pgURL, cleanup := startLogicalReplicationTest(t)
+	defer cleanup()
+
+	sqlDB.Exec(t, `CREATE TABLE b.no_origin (k INT PRIMARY KEY, v STRING)`)
+	sqlDB.ExpectErr(t, "column \"crdb_replication_origin_timestamp\" does not exist",
+		`CREATE LOGICAL REPLICATION STREAM FROM TABLE a.tab ON $1 INTO TABLE b.no_origin`, pgURL.String())
+
+	sqlDB.Exec(t, `CREATE TABLE b.other_types (k INT PRIMARY KEY, v INT,
+crdb_replication_origin_timestamp DECIMAL NOT VISIBLE NULL ON UPDATE NULL)`)
+	sqlDB.ExpectErr(t, "column v has type STRING in the source table and INT8 in the destination table",
+		`CREATE LOGICAL REPLICATION STREAM FROM TABLE a.tab ON $1 INTO TABLE b.other_types`, pgURL.String())
+}
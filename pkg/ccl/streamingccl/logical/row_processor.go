@@ -0,0 +1,411 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package logical
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// originTimestampColumnName is the column of a replicated table that records
+// the timestamp at which a row written by logical replication was written in
+// the source cluster. The column is NULL for the rows written by anyone else,
+// which is how logical replication tells them apart: a row that was itself
+// replicated is never replicated back to the cluster it came from.
+const originTimestampColumnName = "crdb_replication_origin_timestamp"
+
+// The actions that a conflict resolution function is asked to resolve, and
+// its possible resolutions.
+const (
+	actionUpsert = "upsert"
+	actionDelete = "delete"
+
+	resolutionAcceptProposed = "accept_proposed"
+	resolutionIgnoreProposed = "ignore_proposed"
+)
+
+// rowProcessor applies the changes of the rows of the source table of a
+// logical replication stream to the destination table.
+//
+// A change is applied if it is more recent than the row it replaces, as told
+// by the origin timestamp of the row if it was replicated, or else by its MVCC
+// timestamp. If the stream has a conflict resolution function, the function
+// decides instead. Changes that cannot be decoded or applied are written to
+// the dead letter table of the stream, and the deletes that are applied are
+// recorded in the replicated deletes table of the destination table.
+type rowProcessor struct {
+	db      isql.DB
+	codec   keys.SQLCodec
+	jobID   jobspb.JobID
+	details jobspb.LogicalReplicationDetails
+	table   catalog.TableDescriptor
+	fetcher row.Fetcher
+
+	// keyOrds, writeOrds and jsonOrds are the ordinals in the fetched rows of
+	// the columns of the primary key, of the columns that are written when a
+	// row is upserted, and of the columns that are passed to the conflict
+	// resolution function. originOrd is the ordinal of the origin timestamp
+	// column.
+	keyOrds, writeOrds, jsonOrds []int
+	originOrd                    int
+	jsonNames                    []string
+
+	queries struct {
+		selectRow, upsertRow, deleteRow, replicatedDelete, resolve, deadLetter string
+	}
+}
+
+func newRowProcessor(
+	ctx context.Context,
+	db isql.DB,
+	codec keys.SQLCodec,
+	jobID jobspb.JobID,
+	details jobspb.LogicalReplicationDetails,
+	table catalog.TableDescriptor,
+	replicatedDeletesTable string,
+) (*rowProcessor, error) {
+	rp := &rowProcessor{
+		db:        db,
+		codec:     codec,
+		jobID:     jobID,
+		details:   details,
+		table:     table,
+		originOrd: -1,
+	}
+
+	var colIDs []descpb.ColumnID
+	var jsonCols, writeCols []string
+	ords := make(map[descpb.ColumnID]int)
+	for _, col := range table.PublicColumns() {
+		if col.IsVirtual() {
+			continue
+		}
+		ord := len(colIDs)
+		ords[col.GetID()] = ord
+		colIDs = append(colIDs, col.GetID())
+		name := tree.NameString(col.GetName())
+		if col.GetName() == originTimestampColumnName {
+			rp.originOrd = ord
+			continue
+		}
+		rp.jsonOrds = append(rp.jsonOrds, ord)
+		rp.jsonNames = append(rp.jsonNames, col.GetName())
+		jsonCols = append(jsonCols, name)
+		if !col.IsComputed() {
+			rp.writeOrds = append(rp.writeOrds, ord)
+			writeCols = append(writeCols, name)
+		}
+	}
+	if rp.originOrd < 0 {
+		return nil, errors.Newf("table %s has no column %s", table.GetName(), originTimestampColumnName)
+	}
+	primaryIndex := table.GetPrimaryIndex()
+	var keyPreds []string
+	for i := 0; i < primaryIndex.NumKeyColumns(); i++ {
+		rp.keyOrds = append(rp.keyOrds, ords[primaryIndex.GetKeyColumnID(i)])
+		keyPreds = append(keyPreds, fmt.Sprintf("%s = $%d",
+			tree.NameString(primaryIndex.GetKeyColumnName(i)), i+1))
+	}
+
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(&spec, codec, table, primaryIndex, colIDs); err != nil {
+		return nil, err
+	}
+	if err := rp.fetcher.Init(ctx, row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &tree.DatumAlloc{},
+		Spec:              &spec,
+	}); err != nil {
+		return nil, err
+	}
+
+	tableRef := fmt.Sprintf("[%d AS t]", table.GetID())
+	placeholders := make([]string, len(writeCols)+1)
+	for i := range placeholders {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	rp.queries.selectRow = fmt.Sprintf(
+		"SELECT %s, crdb_internal_mvcc_timestamp, %s FROM %s WHERE %s",
+		strings.Join(jsonCols, ", "), originTimestampColumnName, tableRef, strings.Join(keyPreds, " AND "))
+	rp.queries.upsertRow = fmt.Sprintf("UPSERT INTO %s (%s, %s) VALUES (%s)",
+		tableRef, strings.Join(writeCols, ", "), originTimestampColumnName, strings.Join(placeholders, ", "))
+	rp.queries.deleteRow = fmt.Sprintf("DELETE FROM %s WHERE %s", tableRef, strings.Join(keyPreds, " AND "))
+	rp.queries.replicatedDelete = fmt.Sprintf(
+		"INSERT INTO %s (key, deleted_at) VALUES ($1, cluster_logical_timestamp())", replicatedDeletesTable)
+	rp.queries.resolve = fmt.Sprintf("SELECT [FUNCTION %d]($1, $2, $3, $4, $5)",
+		catid.FuncIDToOID(details.FunctionID))
+	rp.queries.deadLetter = fmt.Sprintf(
+		"INSERT INTO %s (job_id, table_id, origin_timestamp, key, value, error) VALUES ($1, $2, $3, $4, $5, $6)",
+		details.DeadLetterTable)
+	return rp, nil
+}
+
+// createDeadLetterTable creates the dead letter table of a logical
+// replication stream if it does not exist yet.
+func createDeadLetterTable(ctx context.Context, db isql.DB, name string) error {
+	_, err := db.Executor().ExecEx(ctx, "logical-replication-create-dead-letter-table", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id INT8 NOT NULL DEFAULT unique_rowid() PRIMARY KEY,
+	job_id INT8 NOT NULL,
+	table_id INT8 NOT NULL,
+	origin_timestamp DECIMAL NOT NULL,
+	key BYTES NOT NULL,
+	value BYTES,
+	error STRING NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`, name))
+	return err
+}
+
+// replicatedDeletesTTL is how long the deletes applied by logical replication
+// are recorded for, by when the replication streams of the destination table
+// are expected to have streamed them.
+const replicatedDeletesTTL = "3 days"
+
+// createReplicatedDeletesTable creates the replicated deletes table of a
+// table written by logical replication if it does not exist yet. The
+// replication streams of the table look up the deletes of its rows there, by
+// the key of the row and the timestamp of the delete, to tell whether logical
+// replication applied them.
+func createReplicatedDeletesTable(ctx context.Context, db isql.DB, name string) error {
+	_, err := db.Executor().ExecEx(ctx, "logical-replication-create-replicated-deletes-table", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	key BYTES NOT NULL,
+	deleted_at DECIMAL NOT NULL,
+	PRIMARY KEY (key, deleted_at)
+) WITH (ttl_expire_after = '%s')`, name, replicatedDeletesTTL))
+	return err
+}
+
+// processKV applies the change of a KV of the source table. KVs that do not
+// belong to the primary index of the table are ignored, and so are the rows
+// that were themselves written by logical replication, lest they bounce back
+// and forth between the clusters.
+func (rp *rowProcessor) processKV(ctx context.Context, kv roachpb.KeyValue) error {
+	rem, err := keys.StripTenantPrefix(kv.Key)
+	if err != nil {
+		return rp.deadLetter(ctx, kv, err)
+	}
+	_, tableID, indexID, err := keys.SystemSQLCodec.DecodeIndexPrefix(rem)
+	if err != nil {
+		return rp.deadLetter(ctx, kv, err)
+	}
+	if descpb.ID(tableID) != rp.details.SourceTableID ||
+		descpb.IndexID(indexID) != rp.table.GetPrimaryIndexID() {
+		return nil
+	}
+
+	key, datums, deleted, err := rp.decodeRow(ctx, kv)
+	if err != nil {
+		return rp.deadLetter(ctx, kv, err)
+	}
+	if datums[rp.originOrd] != tree.DNull {
+		return nil
+	}
+	if err := rp.applyRow(ctx, key, datums, deleted, kv.Value.Timestamp); err != nil {
+		return rp.deadLetter(ctx, kv, err)
+	}
+	return nil
+}
+
+// decodeRow decodes the row of a KV of the source table with the descriptor
+// of the destination table, whose rows are encoded the same way, and returns
+// the key of the KV in the destination table.
+func (rp *rowProcessor) decodeRow(
+	ctx context.Context, kv roachpb.KeyValue,
+) (key roachpb.Key, _ tree.Datums, deleted bool, _ error) {
+	suffix, err := keys.StripTablePrefix(kv.Key)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	key = append(rp.codec.TablePrefix(uint32(rp.table.GetID())), suffix...)
+	value := kv.Value
+	value.InitChecksum(key)
+	if err := rp.fetcher.ConsumeKVProvider(ctx, &row.KVProvider{
+		KVs: []roachpb.KeyValue{{Key: key, Value: value}},
+	}); err != nil {
+		return nil, nil, false, err
+	}
+	datums, err := rp.fetcher.NextRowDecoded(ctx)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if datums == nil {
+		return nil, nil, false, errors.AssertionFailedf("key %s does not hold a row", kv.Key)
+	}
+	return key, datums, rp.fetcher.RowIsDeleted(), nil
+}
+
+// applyRow upserts or deletes a row of the destination table, unless the row
+// it replaces wins the conflict. A delete is recorded in the replicated
+// deletes table in the same transaction, and thus at the same timestamp, so
+// that it is not replicated back to the source cluster, where it would win
+// against the writes that followed the original delete.
+func (rp *rowProcessor) applyRow(
+	ctx context.Context, key roachpb.Key, datums tree.Datums, deleted bool, ts hlc.Timestamp,
+) error {
+	originTS := eval.TimestampToDecimalDatum(ts)
+	keyArgs := make([]interface{}, len(rp.keyOrds))
+	for i, ord := range rp.keyOrds {
+		keyArgs[i] = datums[ord]
+	}
+	return rp.db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		existing, err := txn.QueryRowEx(ctx, "logical-replication-select", txn.KV(),
+			sessiondata.NodeUserSessionDataOverride, rp.queries.selectRow, keyArgs...)
+		if err != nil {
+			return err
+		}
+		if existing == nil && deleted {
+			return nil
+		}
+		if existing != nil {
+			accept, err := rp.acceptProposed(ctx, txn, datums, deleted, existing, originTS)
+			if err != nil || !accept {
+				return err
+			}
+		}
+
+		if deleted {
+			if _, err := txn.ExecEx(ctx, "logical-replication-delete", txn.KV(),
+				sessiondata.NodeUserSessionDataOverride, rp.queries.deleteRow, keyArgs...); err != nil {
+				return err
+			}
+			rowKey, err := keys.EnsureSafeSplitKey(key)
+			if err != nil {
+				return err
+			}
+			_, err = txn.ExecEx(ctx, "logical-replication-record-delete", txn.KV(),
+				sessiondata.NodeUserSessionDataOverride, rp.queries.replicatedDelete,
+				tree.NewDBytes(tree.DBytes(rowKey)))
+			return err
+		}
+		args := make([]interface{}, 0, len(rp.writeOrds)+1)
+		for _, ord := range rp.writeOrds {
+			args = append(args, datums[ord])
+		}
+		args = append(args, originTS)
+		_, err = txn.ExecEx(ctx, "logical-replication-upsert", txn.KV(),
+			sessiondata.NodeUserSessionDataOverride, rp.queries.upsertRow, args...)
+		return err
+	})
+}
+
+// acceptProposed resolves the conflict between a replicated change and the
+// existing row it replaces, and returns true if the change wins.
+func (rp *rowProcessor) acceptProposed(
+	ctx context.Context,
+	txn isql.Txn,
+	datums tree.Datums,
+	deleted bool,
+	existing tree.Datums,
+	originTS *tree.DDecimal,
+) (bool, error) {
+	n := len(rp.jsonOrds)
+	existingTS := existing[n+1]
+	if existingTS == tree.DNull {
+		existingTS = existing[n]
+	}
+	if rp.details.FunctionID == 0 {
+		existingDecimal := tree.MustBeDDecimal(existingTS)
+		return existingDecimal.Cmp(&originTS.Decimal) < 0, nil
+	}
+
+	action, proposedJSON := actionDelete, tree.Datum(tree.DNull)
+	if !deleted {
+		proposed := make(tree.Datums, n)
+		for i, ord := range rp.jsonOrds {
+			proposed[i] = datums[ord]
+		}
+		var err error
+		action = actionUpsert
+		if proposedJSON, err = rp.rowToJSON(proposed); err != nil {
+			return false, err
+		}
+	}
+	existingJSON, err := rp.rowToJSON(existing[:n])
+	if err != nil {
+		return false, err
+	}
+	res, err := txn.QueryRowEx(ctx, "logical-replication-resolve", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride, rp.queries.resolve,
+		tree.NewDString(action), proposedJSON, existingJSON, existingTS, originTS)
+	if err != nil {
+		return false, errors.Wrap(err, "resolving conflict")
+	}
+	switch resolution := tree.MustBeDString(res[0]); resolution {
+	case resolutionAcceptProposed:
+		return true, nil
+	case resolutionIgnoreProposed:
+		return false, nil
+	default:
+		return false, errors.Newf("conflict resolution function returned %q, expected %q or %q",
+			string(resolution), resolutionAcceptProposed, resolutionIgnoreProposed)
+	}
+}
+
+// rowToJSON returns a JSON object mapping the names of the columns passed to
+// the conflict resolution function to their values.
+func (rp *rowProcessor) rowToJSON(datums tree.Datums) (tree.Datum, error) {
+	b := json.NewObjectBuilder(len(datums))
+	for i, d := range datums {
+		j, err := tree.AsJSON(d, sessiondatapb.DataConversionConfig{}, time.UTC)
+		if err != nil {
+			return nil, err
+		}
+		b.Add(rp.jsonNames[i], j)
+	}
+	return tree.NewDJSON(b.Build()), nil
+}
+
+// deadLetter writes a KV whose change could not be applied to the dead letter
+// table, along with the reason why.
+func (rp *rowProcessor) deadLetter(ctx context.Context, kv roachpb.KeyValue, cause error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	log.Warningf(ctx, "logical replication job %d failed to apply the change of key %s: %v",
+		rp.jobID, kv.Key, cause)
+	var value tree.Datum = tree.DNull
+	if kv.Value.IsPresent() {
+		value = tree.NewDBytes(tree.DBytes(kv.Value.RawBytes))
+	}
+	_, err := rp.db.Executor().ExecEx(ctx, "logical-replication-dead-letter", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride, rp.queries.deadLetter,
+		tree.NewDInt(tree.DInt(rp.jobID)),
+		tree.NewDInt(tree.DInt(rp.table.GetID())),
+		eval.TimestampToDecimalDatum(kv.Value.Timestamp),
+		tree.NewDBytes(tree.DBytes(kv.Key)),
+		value,
+		tree.NewDString(cause.Error()),
+	)
+	return errors.Wrap(err, "writing to the dead letter table")
+}
@@ -0,0 +1,17 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingccl
+
+// ReplicatedDeletesTablePrefix prefixes the name of a table written by logical
+// replication to form the name of its replicated deletes table, which lives in
+// the same schema. Logical replication records there the key of every row it
+// deletes, along with the timestamp of the deletion, so that the replication
+// streams of the table can tell these deletes apart from the deletes of its
+// own clients and do not replicate them back to the cluster they came from.
+const ReplicatedDeletesTablePrefix = "crdb_replication_deletes_"
@@ -41,6 +41,19 @@ var StreamReplicationJobLivenessTimeout = settings.RegisterDurationSetting(
 	3*24*time.Hour,
 )
 
+// StreamReplicationFailbackRetention controls how long a producer job retains
+// the protected timestamp of the replicated tenant after the stream was cut
+// over.
+var StreamReplicationFailbackRetention = settings.RegisterDurationSetting(
+	settings.TenantWritable,
+	"stream_replication.failback_retention",
+	"controls how long the producer job of a replication stream that was cut over keeps "+
+		"protecting the replicated tenant, which lets the tenant be replicated back into "+
+		"this cluster without an initial scan",
+	0,
+	settings.NonNegativeDuration,
+)
+
 // StreamReplicationConsumerHeartbeatFrequency controls frequency the stream replication
 // destination cluster sends heartbeat to the source cluster to keep the stream alive.
 var StreamReplicationConsumerHeartbeatFrequency = settings.RegisterDurationSetting(
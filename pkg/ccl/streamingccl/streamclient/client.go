@@ -48,8 +48,19 @@ type CheckpointToken []byte
 type Client interface {
 	// Create initializes a stream with the source, potentially reserving any
 	// required resources, such as protected timestamps, and returns an ID which
-	// can be used to interact with this stream in the future.
-	Create(ctx context.Context, tenant roachpb.TenantName) (streampb.ReplicationProducerSpec, error)
+	// can be used to interact with this stream in the future. The request may
+	// restrict the stream to some of the tables of the tenant.
+	Create(
+		ctx context.Context, tenant roachpb.TenantName, req streampb.ReplicationProducerRequest,
+	) (streampb.ReplicationProducerSpec, error)
+
+	// CreateForTables initializes a stream of the tables of the source cluster
+	// named by the request, which are resolved in the tenant the client is
+	// connected to, for consumption by logical replication. The returned spec
+	// holds the descriptors of the tables.
+	CreateForTables(
+		ctx context.Context, req streampb.ReplicationProducerRequest,
+	) (streampb.ReplicationProducerSpec, error)
 
 	// Dial checks if the source is able to be connected to for queries
 	Dial(ctx context.Context) error
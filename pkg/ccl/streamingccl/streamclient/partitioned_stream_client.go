@@ -73,7 +73,7 @@ var _ Client = &partitionedStreamClient{}
 
 // Create implements Client interface.
 func (p *partitionedStreamClient) Create(
-	ctx context.Context, tenantName roachpb.TenantName,
+	ctx context.Context, tenantName roachpb.TenantName, req streampb.ReplicationProducerRequest,
 ) (streampb.ReplicationProducerSpec, error) {
 	ctx, sp := tracing.ChildSpan(ctx, "streamclient.Client.Create")
 	defer sp.Finish()
@@ -81,7 +81,19 @@ func (p *partitionedStreamClient) Create(
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	var rawReplicationProducerSpec []byte
-	row := p.mu.srcConn.QueryRow(ctx, `SELECT crdb_internal.start_replication_stream($1)`, tenantName)
+	var row pgx.Row
+	// Only send a request to the source if it restricts or resumes the stream,
+	// so that other streams can still be created from older source clusters.
+	if len(req.TableNames) > 0 || len(req.DatabaseNames) > 0 || req.ResumeFromCutover {
+		reqBytes, err := protoutil.Marshal(&req)
+		if err != nil {
+			return streampb.ReplicationProducerSpec{}, err
+		}
+		row = p.mu.srcConn.QueryRow(ctx, `SELECT crdb_internal.start_replication_stream($1, $2)`,
+			tenantName, reqBytes)
+	} else {
+		row = p.mu.srcConn.QueryRow(ctx, `SELECT crdb_internal.start_replication_stream($1)`, tenantName)
+	}
 	err := row.Scan(&rawReplicationProducerSpec)
 	if err != nil {
 		return streampb.ReplicationProducerSpec{}, errors.Wrapf(err, "error creating replication stream for tenant %s", tenantName)
@@ -94,6 +106,33 @@ func (p *partitionedStreamClient) Create(
 	return replicationProducerSpec, err
 }
 
+// CreateForTables implements Client interface.
+func (p *partitionedStreamClient) CreateForTables(
+	ctx context.Context, req streampb.ReplicationProducerRequest,
+) (streampb.ReplicationProducerSpec, error) {
+	ctx, sp := tracing.ChildSpan(ctx, "streamclient.Client.CreateForTables")
+	defer sp.Finish()
+
+	reqBytes, err := protoutil.Marshal(&req)
+	if err != nil {
+		return streampb.ReplicationProducerSpec{}, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var rawReplicationProducerSpec []byte
+	row := p.mu.srcConn.QueryRow(ctx,
+		`SELECT crdb_internal.start_replication_stream_for_tables($1)`, reqBytes)
+	if err := row.Scan(&rawReplicationProducerSpec); err != nil {
+		return streampb.ReplicationProducerSpec{}, errors.Wrapf(err,
+			"error creating replication stream for tables %v", req.TableNames)
+	}
+	var replicationProducerSpec streampb.ReplicationProducerSpec
+	if err := protoutil.Unmarshal(rawReplicationProducerSpec, &replicationProducerSpec); err != nil {
+		return streampb.ReplicationProducerSpec{}, err
+	}
+	return replicationProducerSpec, nil
+}
+
 // Dial implements Client interface.
 func (p *partitionedStreamClient) Dial(ctx context.Context) error {
 	p.mu.Lock()
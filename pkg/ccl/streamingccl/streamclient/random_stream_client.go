@@ -37,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
 )
 
 const (
@@ -384,7 +385,7 @@ func (m *RandomStreamClient) Plan(ctx context.Context, _ streampb.StreamID) (Top
 
 // Create implements the Client interface.
 func (m *RandomStreamClient) Create(
-	ctx context.Context, tenantName roachpb.TenantName,
+	ctx context.Context, tenantName roachpb.TenantName, _ streampb.ReplicationProducerRequest,
 ) (streampb.ReplicationProducerSpec, error) {
 	log.Infof(ctx, "creating random stream for tenant %s", tenantName)
 	return streampb.ReplicationProducerSpec{
@@ -393,6 +394,14 @@ func (m *RandomStreamClient) Create(
 	}, nil
 }
 
+// CreateForTables implements the Client interface.
+func (m *RandomStreamClient) CreateForTables(
+	_ context.Context, _ streampb.ReplicationProducerRequest,
+) (streampb.ReplicationProducerSpec, error) {
+	return streampb.ReplicationProducerSpec{}, errors.New(
+		"the random stream client does not support logical replication")
+}
+
 // Heartbeat implements the Client interface.
 func (m *RandomStreamClient) Heartbeat(
 	ctx context.Context, _ streampb.StreamID, ts hlc.Timestamp,
@@ -14,11 +14,17 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/replicationutils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streamclient"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprotectedts"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/mtinfopb"
+	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
@@ -28,8 +34,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/asof"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
 
@@ -42,7 +51,9 @@ var alterReplicationCutoverHeader = colinfo.ResultColumns{
 // ResolvedTenantReplicationOptions represents options from an
 // evaluated CREATE TENANT FROM REPLICATION command.
 type resolvedTenantReplicationOptions struct {
-	retention *int32
+	retention     *int32
+	tableNames    []string
+	databaseNames []string
 }
 
 func evalTenantReplicationOptions(
@@ -65,9 +76,32 @@ func evalTenantReplicationOptions(
 		retSeconds := int32(retSeconds64)
 		r.retention = &retSeconds
 	}
+	if options.Tables != nil {
+		tableNames, err := eval.StringArray(ctx, tree.Exprs(options.Tables))
+		if err != nil {
+			return nil, err
+		}
+		r.tableNames = tableNames
+	}
+	if options.Databases != nil {
+		databaseNames, err := eval.StringArray(ctx, tree.Exprs(options.Databases))
+		if err != nil {
+			return nil, err
+		}
+		r.databaseNames = databaseNames
+	}
 	return r, nil
 }
 
+// producerRequest returns the request that restricts the replication stream to
+// the tables named by the options, if any.
+func (r *resolvedTenantReplicationOptions) producerRequest() streampb.ReplicationProducerRequest {
+	return streampb.ReplicationProducerRequest{
+		TableNames:    r.tableNames,
+		DatabaseNames: r.databaseNames,
+	}
+}
+
 func (r *resolvedTenantReplicationOptions) GetRetention() (int32, bool) {
 	if r == nil || r.retention == nil {
 		return 0, false
@@ -82,11 +116,17 @@ func alterReplicationJobTypeCheck(
 	if !ok {
 		return false, nil, nil
 	}
-	if err := exprutil.TypeCheck(
-		ctx, alterReplicationJobOp, p.SemaCtx(),
+	toTypeCheck := []exprutil.ToTypeCheck{
 		exprutil.TenantSpec{TenantSpec: alterStmt.TenantSpec},
 		exprutil.Strings{alterStmt.Options.Retention},
-	); err != nil {
+	}
+	if alterStmt.ReplicationSourceAddress != nil {
+		toTypeCheck = append(toTypeCheck,
+			exprutil.TenantSpec{TenantSpec: alterStmt.ReplicationSourceTenantName},
+			exprutil.Strings{alterStmt.ReplicationSourceAddress},
+		)
+	}
+	if err := exprutil.TypeCheck(ctx, alterReplicationJobOp, p.SemaCtx(), toTypeCheck...); err != nil {
 		return false, nil, err
 	}
 
@@ -146,12 +186,34 @@ func alterReplicationJobHook(
 		}
 	}
 
+	if alterTenantStmt.Options.Tables != nil || alterTenantStmt.Options.Databases != nil {
+		return nil, nil, nil, false, errors.New(
+			"the tables replicated by a replication stream cannot be altered")
+	}
+
+	if alterTenantStmt.Options.ResumeFromProtectedTimestamp && alterTenantStmt.ReplicationSourceAddress == nil {
+		return nil, nil, nil, false, errors.New(
+			"the resume_from_protected_ts option can only be used to start the replication of a tenant")
+	}
+
 	exprEval := p.ExprEvaluator(alterReplicationJobOp)
 	options, err := evalTenantReplicationOptions(ctx, alterTenantStmt.Options, exprEval)
 	if err != nil {
 		return nil, nil, nil, false, err
 	}
 
+	var srcAddr, srcTenant string
+	if alterTenantStmt.ReplicationSourceAddress != nil {
+		srcAddr, err = exprEval.String(ctx, alterTenantStmt.ReplicationSourceAddress)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		_, _, srcTenant, err = exprEval.TenantSpec(ctx, alterTenantStmt.ReplicationSourceTenantName)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
 	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
 		if err := utilccl.CheckEnterpriseEnabled(
 			p.ExecCfg().Settings, p.ExecCfg().NodeInfo.LogicalClusterID(),
@@ -168,6 +230,9 @@ func alterReplicationJobHook(
 		if err != nil {
 			return err
 		}
+		if alterTenantStmt.ReplicationSourceAddress != nil {
+			return alterTenantStartReplication(ctx, p, alterTenantStmt, tenInfo, srcAddr, srcTenant, options)
+		}
 		if tenInfo.TenantReplicationJobID == 0 {
 			return errors.Newf("tenant %q (%d) does not have an active replication job",
 				tenInfo.Name, tenInfo.ID)
@@ -271,6 +336,160 @@ func alterTenantJobCutover(
 	return cutoverTime, nil
 }
 
+// alterTenantStartReplication starts replicating the given tenant of the
+// source cluster into an existing tenant, which must be the former source of a
+// replication stream of that tenant that was cut over.
+//
+// Rather than scanning the source tenant, the replication resumes from the
+// time of the cutover: the ingestion job first reverts the existing data of
+// the tenant to that time, which the protected timestamp retained by the
+// former producer job of the stream allows, and then only ingests the changes
+// made on the source tenant since.
+func alterTenantStartReplication(
+	ctx context.Context,
+	p sql.PlanHookState,
+	alterTenantStmt *tree.AlterTenantReplication,
+	tenInfo *mtinfopb.TenantInfo,
+	from string,
+	sourceTenant string,
+	options *resolvedTenantReplicationOptions,
+) error {
+	if !alterTenantStmt.Options.ResumeFromProtectedTimestamp {
+		return errors.WithHint(
+			errors.Newf("cannot replicate into tenant %q (%d) as it already exists", tenInfo.Name, tenInfo.ID),
+			"Use the resume_from_protected_ts option to replicate a tenant back into its former source.")
+	}
+	if tenInfo.TenantReplicationJobID != 0 {
+		return errors.Newf("tenant %q (%d) is already the destination of replication job %d",
+			tenInfo.Name, tenInfo.ID, tenInfo.TenantReplicationJobID)
+	}
+	if tenInfo.ServiceMode != mtinfopb.ServiceModeNone {
+		return errors.WithHint(
+			errors.Newf("cannot replicate into tenant %q (%d) while it is in service", tenInfo.Name, tenInfo.ID),
+			"Stop the service of the tenant with ALTER TENANT ... STOP SERVICE.")
+	}
+	streamAddress, err := validateStreamAddress(from)
+	if err != nil {
+		return err
+	}
+
+	execCfg := p.ExecCfg()
+	txn := p.InternalSQLTxn()
+	tenantID := roachpb.MustMakeTenantID(tenInfo.ID)
+	producerJob, err := formerProducerJob(ctx, txn, execCfg.JobRegistry, tenInfo)
+	if err != nil {
+		return err
+	}
+
+	// The stream starts at the time the source tenant was cut over to.
+	client, err := streamclient.NewStreamClient(ctx, streamAddress, execCfg.InternalDB)
+	if err != nil {
+		return err
+	}
+	req := options.producerRequest()
+	req.ResumeFromCutover = true
+	replicationProducerSpec, err := client.Create(ctx, roachpb.TenantName(sourceTenant), req)
+	if err != nil {
+		return err
+	}
+	if err := client.Close(ctx); err != nil {
+		return err
+	}
+	resumeTime := replicationProducerSpec.ReplicationStartTime
+
+	// The ingestion job takes over the protection of the tenant from the former
+	// producer job, which releases its protected timestamp once it expires.
+	jobID := execCfg.JobRegistry.MakeJobID()
+	ptsID := uuid.MakeV4()
+	pts := jobsprotectedts.MakeRecord(ptsID, int64(jobID), resumeTime,
+		nil /* deprecatedSpans */, jobsprotectedts.Jobs, ptpb.MakeTenantsTarget([]roachpb.TenantID{tenantID}))
+	if err := execCfg.ProtectedTimestampProvider.WithTxn(txn).Protect(ctx, pts); err != nil {
+		return err
+	}
+	if err := producerJob.WithTxn(txn).Update(ctx, func(
+		txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		md.Progress.GetStreamReplication().Expiration = timeutil.Now()
+		ju.UpdateProgress(md.Progress)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	tenInfo.DataState = mtinfopb.DataStateAdd
+	tenInfo.TenantReplicationJobID = jobID
+	if err := sql.UpdateTenantRecord(ctx, execCfg.Settings, txn, tenInfo); err != nil {
+		return err
+	}
+
+	retentionTTLSeconds := defaultRetentionTTLSeconds
+	if ret, ok := options.GetRetention(); ok {
+		retentionTTLSeconds = ret
+	}
+	prefix := keys.MakeTenantPrefix(tenantID)
+	redactedSourceAddr, err := redactSourceURI(from)
+	if err != nil {
+		return err
+	}
+	redactedStmt := *alterTenantStmt
+	redactedStmt.ReplicationSourceAddress = tree.NewDString(redactedSourceAddr)
+	jr := jobs.Record{
+		Description: tree.AsStringWithFQNames(&redactedStmt, p.ExtendedEvalContext().Annotations),
+		Username:    p.User(),
+		Progress: jobspb.StreamIngestionProgress{
+			InitialRevertTo: resumeTime,
+		},
+		Details: jobspb.StreamIngestionDetails{
+			StreamAddress:              string(streamAddress),
+			StreamID:                   uint64(replicationProducerSpec.StreamID),
+			Span:                       roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()},
+			DestinationTenantID:        tenantID,
+			SourceTenantName:           roachpb.TenantName(sourceTenant),
+			DestinationTenantName:      tenInfo.Name,
+			ProtectedTimestampRecordID: &ptsID,
+			ReplicationTTLSeconds:      retentionTTLSeconds,
+			ReplicationStartTime:       resumeTime,
+		},
+	}
+	_, err = execCfg.JobRegistry.CreateAdoptableJobWithTxn(ctx, jr, jobID, txn)
+	return err
+}
+
+// formerProducerJob returns the producer job of a replication stream of the
+// given tenant that was cut over, which retains the protected timestamp of the
+// tenant for the stream_replication.failback_retention.
+func formerProducerJob(
+	ctx context.Context, txn isql.Txn, registry *jobs.Registry, tenInfo *mtinfopb.TenantInfo,
+) (*jobs.Job, error) {
+	rows, err := txn.QueryBufferedEx(ctx, "find-former-producer-job", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT id FROM system.jobs WHERE job_type = $1 AND status = $2`,
+		jobspb.TypeStreamReplication.String(), string(jobs.StatusRunning))
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		job, err := registry.LoadJobWithTxn(ctx, jobspb.JobID(tree.MustBeDInt(row[0])), txn)
+		if err != nil {
+			return nil, err
+		}
+		details, ok := job.Details().(jobspb.StreamReplicationDetails)
+		if !ok || details.TenantID.ToUint64() != tenInfo.ID {
+			continue
+		}
+		progress := job.Progress()
+		if progress.GetStreamReplication().StreamIngestionStatus ==
+			jobspb.StreamReplicationProgress_FINISHED_SUCCESSFULLY {
+			return job, nil
+		}
+	}
+	return nil, errors.WithHint(
+		errors.Newf("tenant %q (%d) has no protected timestamp retained by a replication stream that was cut over",
+			tenInfo.Name, tenInfo.ID),
+		"The stream_replication.failback_retention cluster setting must be set when the stream is cut over "+
+			"for the tenant to be replicated back into this cluster.")
+}
+
 func alterTenantOptions(
 	ctx context.Context,
 	txn isql.Txn,
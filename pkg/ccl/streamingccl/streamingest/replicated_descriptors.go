@@ -0,0 +1,229 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingest
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/rewrite"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+// writeReplicatedDescriptors writes the descriptors of the tables replicated by
+// a stream of some of the tables of the source tenant, and of the databases and
+// schemas that contain them, into the catalog of the destination tenant, which
+// was just initialized. The descriptors are rewritten to new IDs, as by
+// RESTORE, except that the databases and schemas that already exist in the
+// destination tenant, such as defaultdb, are reused. It returns the replicated
+// tables, whose keys the ingestion processors rewrite to their new IDs.
+//
+// The users and roles of the source tenant are not replicated, so the written
+// descriptors are owned by root and only keep the privileges of the roles that
+// every tenant has.
+func writeReplicatedDescriptors(
+	ctx context.Context,
+	p sql.PlanHookState,
+	tenantID roachpb.TenantID,
+	spec streampb.ReplicationProducerSpec,
+) ([]jobspb.StreamIngestionDetails_ReplicatedTable, error) {
+	codec := keys.MakeSQLCodec(tenantID)
+	txn := p.Txn()
+	idGen := descidgen.NewTransactionalGenerator(p.ExecCfg().Settings, codec, txn)
+	lookupName := func(nameInfo descpb.NameInfo) (descpb.ID, error) {
+		res, err := txn.Get(ctx, catalogkeys.EncodeNameKey(codec, nameInfo))
+		if err != nil {
+			return descpb.InvalidID, err
+		}
+		return descpb.ID(res.ValueInt()), nil
+	}
+
+	var databases []*dbdesc.Mutable
+	var schemas []*schemadesc.Mutable
+	for i := range spec.ParentDescriptors {
+		_, db, _, sc, _ := descpb.GetDescriptors(&spec.ParentDescriptors[i])
+		switch {
+		case db != nil:
+			databases = append(databases, dbdesc.NewBuilder(db).BuildCreatedMutableDatabase())
+		case sc != nil:
+			schemas = append(schemas, schemadesc.NewBuilder(sc).BuildCreatedMutableSchema())
+		default:
+			return nil, errors.AssertionFailedf(
+				"descriptor %d is neither a database nor a schema", spec.ParentDescriptors[i].GetID())
+		}
+	}
+	tables := make([]*tabledesc.Mutable, len(spec.TableDescriptors))
+	for i := range spec.TableDescriptors {
+		tables[i] = tabledesc.NewBuilder(&spec.TableDescriptors[i]).BuildCreatedMutableTable()
+	}
+
+	// Map the source descriptors to existing descriptors of the same name, or to
+	// new IDs.
+	descriptorRewrites := make(jobspb.DescRewriteMap)
+	var newDatabases []*dbdesc.Mutable
+	for _, db := range databases {
+		existingID, err := lookupName(descpb.NameInfo{Name: db.Name})
+		if err != nil {
+			return nil, err
+		}
+		if existingID != descpb.InvalidID {
+			descriptorRewrites[db.ID] = &jobspb.DescriptorRewrite{ID: existingID, ToExisting: true}
+			continue
+		}
+		newID, err := idGen.GenerateUniqueDescID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		descriptorRewrites[db.ID] = &jobspb.DescriptorRewrite{ID: newID}
+		newDatabases = append(newDatabases, db)
+	}
+	var newSchemas []*schemadesc.Mutable
+	for _, sc := range schemas {
+		dbRewrite, ok := descriptorRewrites[sc.ParentID]
+		if !ok {
+			return nil, errors.AssertionFailedf("missing database %d of schema %q", sc.ParentID, sc.Name)
+		}
+		if dbRewrite.ToExisting {
+			existingID, err := lookupName(descpb.NameInfo{ParentID: dbRewrite.ID, Name: sc.Name})
+			if err != nil {
+				return nil, err
+			}
+			if existingID == descpb.InvalidID {
+				return nil, errors.Newf(
+					"schema %q cannot be replicated into a database of the destination tenant that exists", sc.Name)
+			}
+			descriptorRewrites[sc.ID] = &jobspb.DescriptorRewrite{
+				ID: existingID, ParentID: dbRewrite.ID, ToExisting: true,
+			}
+			continue
+		}
+		newID, err := idGen.GenerateUniqueDescID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		descriptorRewrites[sc.ID] = &jobspb.DescriptorRewrite{ID: newID, ParentID: dbRewrite.ID}
+		newSchemas = append(newSchemas, sc)
+	}
+	sourceIDs := make([]descpb.ID, len(tables))
+	sourceVersions := make([]descpb.DescriptorVersion, len(tables))
+	for i, table := range tables {
+		dbRewrite, ok := descriptorRewrites[table.ParentID]
+		if !ok {
+			return nil, errors.AssertionFailedf("missing database %d of table %q", table.ParentID, table.Name)
+		}
+		scRewrite, ok := descriptorRewrites[table.GetParentSchemaID()]
+		if !ok {
+			return nil, errors.AssertionFailedf(
+				"missing schema %d of table %q", table.GetParentSchemaID(), table.Name)
+		}
+		newID, err := idGen.GenerateUniqueDescID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		descriptorRewrites[table.ID] = &jobspb.DescriptorRewrite{
+			ID: newID, ParentID: dbRewrite.ID, ParentSchemaID: scRewrite.ID,
+		}
+		sourceIDs[i] = table.ID
+		sourceVersions[i] = table.Version
+	}
+
+	// A new database only lists the schemas that are replicated.
+	for _, db := range newDatabases {
+		for name, info := range db.Schemas {
+			if _, ok := descriptorRewrites[info.ID]; !ok {
+				delete(db.Schemas, name)
+			}
+		}
+	}
+	if err := rewrite.DatabaseDescs(newDatabases, descriptorRewrites, nil /* offlineSchemas */); err != nil {
+		return nil, err
+	}
+	if err := rewrite.SchemaDescs(newSchemas, descriptorRewrites); err != nil {
+		return nil, err
+	}
+	if err := rewrite.TableDescs(tables, descriptorRewrites, "" /* overrideDB */); err != nil {
+		return nil, err
+	}
+
+	b := txn.NewBatch()
+	write := func(desc catalog.MutableDescriptor) {
+		resetReplicatedPrivileges(desc.GetPrivileges())
+		b.CPut(codec.DescMetadataKey(uint32(desc.GetID())), desc.DescriptorProto(), nil)
+		b.CPut(catalogkeys.EncodeNameKey(codec, desc), int64(desc.GetID()), nil)
+	}
+	for _, db := range newDatabases {
+		write(db)
+	}
+	for _, sc := range newSchemas {
+		write(sc)
+	}
+	replicatedTables := make([]jobspb.StreamIngestionDetails_ReplicatedTable, len(tables))
+	for i, table := range tables {
+		write(table)
+		replicatedTables[i] = jobspb.StreamIngestionDetails_ReplicatedTable{
+			SourceID:      sourceIDs[i],
+			Desc:          *table.TableDesc(),
+			SourceVersion: sourceVersions[i],
+		}
+	}
+	if err := txn.Run(ctx, b); err != nil {
+		return nil, err
+	}
+	return replicatedTables, nil
+}
+
+// resetReplicatedPrivileges makes root the owner of a replicated descriptor and
+// drops the privileges of the roles of the source tenant that the destination
+// tenant does not have.
+func resetReplicatedPrivileges(privs *catpb.PrivilegeDescriptor) {
+	privs.SetOwner(username.RootUserName())
+	users := privs.Users[:0]
+	for _, u := range privs.Users {
+		if user := u.User(); user.IsRootUser() || user.IsAdminRole() || user.IsPublicRole() {
+			users = append(users, u)
+		}
+	}
+	privs.Users = users
+}
+
+// makeTableRekeys returns the rekeys of the replicated tables of an ingestion
+// job, which the ingestion processors use to rewrite the keys of these tables,
+// and the versions of their descriptors in the source tenant, which the
+// processors check against the descriptors carried by the stream.
+func makeTableRekeys(
+	replicatedTables []jobspb.StreamIngestionDetails_ReplicatedTable,
+) ([]execinfrapb.TableRekey, []descpb.DescriptorVersion, error) {
+	tableRekeys := make([]execinfrapb.TableRekey, len(replicatedTables))
+	sourceVersions := make([]descpb.DescriptorVersion, len(replicatedTables))
+	for i := range replicatedTables {
+		newDesc, err := protoutil.Marshal(tabledesc.NewBuilder(&replicatedTables[i].Desc).
+			BuildImmutable().DescriptorProto())
+		if err != nil {
+			return nil, nil, errors.NewAssertionErrorWithWrappedErrf(err, "marshaling descriptor")
+		}
+		tableRekeys[i] = execinfrapb.TableRekey{OldID: uint32(replicatedTables[i].SourceID), NewDesc: newDesc}
+		sourceVersions[i] = replicatedTables[i].SourceVersion
+	}
+	return tableRekeys, sourceVersions, nil
+}
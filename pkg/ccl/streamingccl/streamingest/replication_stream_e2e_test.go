@@ -11,6 +11,7 @@ package streamingest
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"testing"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/desctestutils"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
@@ -622,6 +624,72 @@ func TestTenantStreamingDeleteRange(t *testing.T) {
 	checkDelRangeOnTable("t2", false /* embeddedInSST */)
 }
 
+func TestTenantStreamingTableFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	args := replicationtestutils.DefaultTenantStreamingClustersArgs
+	c, cleanup := replicationtestutils.CreateTenantStreamingClusters(ctx, t, args)
+	defer cleanup()
+
+	c.DestSysSQL.Exec(t, c.BuildCreateTenantQuery()+` WITH TABLES = 'd.t1'`)
+	producerJobID, ingestionJobID := replicationtestutils.GetStreamJobIds(
+		t, ctx, c.DestSysSQL, c.Args.DestTenantName)
+	jobutils.WaitForJobToRun(c.T, c.SrcSysSQL, jobspb.JobID(producerJobID))
+	jobutils.WaitForJobToRun(c.T, c.DestSysSQL, jobspb.JobID(ingestionJobID))
+
+	c.SrcTenantSQL.Exec(t, `INSERT INTO d.t1 (i, a) VALUES (43, 'hello')`)
+	c.SrcTenantSQL.Exec(t, `INSERT INTO d.t2 VALUES (3)`)
+	c.SrcTenantSQL.Exec(t, `DELETE FROM d.t1 WHERE i = 42`)
+
+	c.WaitUntilStartTimeReached(jobspb.JobID(ingestionJobID))
+	cutoverTime := c.SrcSysServer.Clock().Now()
+	c.WaitUntilHighWatermark(cutoverTime, jobspb.JobID(ingestionJobID))
+	c.Cutover(producerJobID, ingestionJobID, cutoverTime.GoTime())
+
+	cleanUpTenant := c.CreateDestTenantSQL(ctx)
+	defer func() {
+		require.NoError(t, cleanUpTenant())
+	}()
+
+	// The destination tenant only has the descriptor of the replicated table,
+	// whose rows were rewritten to its ID in the destination tenant.
+	c.CompareResult(`SELECT * FROM d.t1 ORDER BY i`)
+	c.DestTenantSQL.CheckQueryResults(t, `SELECT table_name FROM [SHOW TABLES FROM d]`,
+		[][]string{{"t1"}})
+	c.DestTenantSQL.Exec(t, `INSERT INTO d.t1 (i) VALUES (44)`)
+	c.DestTenantSQL.CheckQueryResults(t, `SELECT count(*) FROM d.t1`, [][]string{{"2"}})
+}
+
+func TestTenantStreamingTableFilterSchemaChange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	args := replicationtestutils.DefaultTenantStreamingClustersArgs
+	c, cleanup := replicationtestutils.CreateTenantStreamingClusters(ctx, t, args)
+	defer cleanup()
+
+	c.DestSysSQL.Exec(t, c.BuildCreateTenantQuery()+` WITH TABLES = 'd.t1'`)
+	producerJobID, ingestionJobID := replicationtestutils.GetStreamJobIds(
+		t, ctx, c.DestSysSQL, c.Args.DestTenantName)
+	jobutils.WaitForJobToRun(c.T, c.SrcSysSQL, jobspb.JobID(producerJobID))
+	jobutils.WaitForJobToRun(c.T, c.DestSysSQL, jobspb.JobID(ingestionJobID))
+
+	c.SrcTenantSQL.Exec(t, `INSERT INTO d.t1 (i, a) VALUES (43, 'hello')`)
+	c.WaitUntilStartTimeReached(jobspb.JobID(ingestionJobID))
+	c.WaitUntilHighWatermark(c.SrcSysServer.Clock().Now(), jobspb.JobID(ingestionJobID))
+
+	// Schema changes of the replicated tables are not replicated, so the
+	// ingestion stops rather than diverging from the source.
+	c.SrcTenantSQL.Exec(t, `ALTER TABLE d.t1 ADD COLUMN b INT NOT NULL DEFAULT 1`)
+	c.SrcTenantSQL.Exec(t, `INSERT INTO d.t1 (i, a, b) VALUES (44, 'world', 2)`)
+	jobutils.WaitForJobToPause(c.T, c.DestSysSQL, jobspb.JobID(ingestionJobID))
+	require.Regexp(t, `replicated table "t1" was changed in the source tenant`,
+		replicationtestutils.RunningStatus(t, c.DestSysSQL, ingestionJobID))
+}
+
 func TestTenantStreamingMultipleNodes(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -910,3 +978,72 @@ func TestTenantStreamingShowTenant(t *testing.T) {
 	cutoverOutput := replicationtestutils.DecimalTimeToHLC(c.T, showCutover)
 	require.Equal(c.T, futureTime, cutoverOutput)
 }
+
+func TestTenantStreamingFailback(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	args := replicationtestutils.DefaultTenantStreamingClustersArgs
+	c, cleanup := replicationtestutils.CreateTenantStreamingClusters(ctx, t, args)
+	defer cleanup()
+
+	// The producer job retains the protected timestamp of the source tenant
+	// after the cutover, which lets the tenant be replicated back.
+	c.SrcSysSQL.Exec(t, `SET CLUSTER SETTING stream_replication.failback_retention = '1h'`)
+	producerJobID, ingestionJobID := c.StartStreamReplication(ctx)
+	jobutils.WaitForJobToRun(c.T, c.SrcSysSQL, jobspb.JobID(producerJobID))
+	jobutils.WaitForJobToRun(c.T, c.DestSysSQL, jobspb.JobID(ingestionJobID))
+
+	cutoverTime := c.SrcSysServer.Clock().Now()
+	c.WaitUntilHighWatermark(cutoverTime, jobspb.JobID(ingestionJobID))
+	c.DestSysSQL.Exec(t, `ALTER TENANT $1 COMPLETE REPLICATION TO SYSTEM TIME $2::string`,
+		args.DestTenantName, cutoverTime.AsOfSystemTime())
+	jobutils.WaitForJobToSucceed(t, c.DestSysSQL, jobspb.JobID(ingestionJobID))
+
+	// The writes to the former source after the cutover are reverted by the
+	// replication back into it.
+	c.SrcTenantSQL.Exec(t, `INSERT INTO d.t2 VALUES (10)`)
+	cleanupDestTenant := c.CreateDestTenantSQL(ctx)
+	defer func() {
+		require.NoError(t, cleanupDestTenant())
+	}()
+	c.DestTenantSQL.Exec(t, `INSERT INTO d.t2 VALUES (20)`)
+
+	c.DestSysSQL.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	c.SrcSysSQL.Exec(t, `SET CLUSTER SETTING cross_cluster_replication.enabled = true`)
+	destURL, cleanupURL := sqlutils.PGUrl(t, c.DestSysServer.ServingSQLAddr(), t.Name(), url.User(username.RootUser))
+	defer cleanupURL()
+
+	c.SrcSysSQL.ExpectErr(t, "while it is in service",
+		`ALTER TENANT $1 START REPLICATION OF $2 ON $3 WITH resume_from_protected_ts`,
+		args.SrcTenantName, args.DestTenantName, destURL.String())
+	c.SrcSysSQL.Exec(t, `ALTER TENANT $1 STOP SERVICE`, args.SrcTenantName)
+	c.SrcSysSQL.ExpectErr(t, "as it already exists",
+		`ALTER TENANT $1 START REPLICATION OF $2 ON $3`,
+		args.SrcTenantName, args.DestTenantName, destURL.String())
+	c.SrcSysSQL.Exec(t, `ALTER TENANT $1 START REPLICATION OF $2 ON $3 WITH resume_from_protected_ts`,
+		args.SrcTenantName, args.DestTenantName, destURL.String())
+
+	// The former producer job lets go of its protected timestamp, which the new
+	// ingestion job took over.
+	jobutils.WaitForJobToSucceed(t, c.SrcSysSQL, jobspb.JobID(producerJobID))
+	_, failbackJobID := replicationtestutils.GetStreamJobIds(t, ctx, c.SrcSysSQL, args.SrcTenantName)
+	progress := jobutils.GetJobProgress(t, c.SrcSysSQL, jobspb.JobID(failbackJobID))
+	require.True(t, progress.GetStreamIngest().InitialRevertTo.IsEmpty() ||
+		progress.GetStreamIngest().InitialRevertTo == cutoverTime)
+
+	replicatedTime := c.DestSysServer.Clock().Now()
+	testutils.SucceedsSoon(t, func() error {
+		progress := jobutils.GetJobProgress(t, c.SrcSysSQL, jobspb.JobID(failbackJobID))
+		if hw := progress.GetHighWater(); hw == nil || hw.Less(replicatedTime) {
+			return errors.Newf("waiting for the high water of job %d to reach %s", failbackJobID, replicatedTime)
+		}
+		return nil
+	})
+	require.Equal(t,
+		replicationtestutils.FingerprintTenantAtTimestampNoHistory(t, c.DestSysSQL,
+			args.DestTenantID.ToUint64(), replicatedTime.AsOfSystemTime()),
+		replicationtestutils.FingerprintTenantAtTimestampNoHistory(t, c.SrcSysSQL,
+			args.SrcTenantID.ToUint64(), replicatedTime.AsOfSystemTime()))
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
@@ -146,6 +147,10 @@ func makePlan(
 			return nil, nil, err
 		}
 
+		tableRekeys, tableSourceVersions, err := makeTableRekeys(details.ReplicatedTables)
+		if err != nil {
+			return nil, nil, err
+		}
 		streamIngestionSpecs, streamIngestionFrontierSpec, err := constructStreamIngestionPlanSpecs(
 			streamingccl.StreamAddress(details.StreamAddress),
 			topology,
@@ -156,7 +161,9 @@ func makePlan(
 			jobID,
 			streamID,
 			topology.SourceTenantID,
-			details.DestinationTenantID)
+			details.DestinationTenantID,
+			tableRekeys,
+			tableSourceVersions)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -207,6 +214,8 @@ func constructStreamIngestionPlanSpecs(
 	streamID streampb.StreamID,
 	sourceTenantID roachpb.TenantID,
 	destinationTenantID roachpb.TenantID,
+	tableRekeys []execinfrapb.TableRekey,
+	tableSourceVersions []descpb.DescriptorVersion,
 ) ([]*execinfrapb.StreamIngestionDataSpec, *execinfrapb.StreamIngestionFrontierSpec, error) {
 	// For each stream partition in the topology, assign it to a node.
 	streamIngestionSpecs := make([]*execinfrapb.StreamIngestionDataSpec, 0, len(sqlInstanceIDs))
@@ -230,6 +239,8 @@ func constructStreamIngestionPlanSpecs(
 					OldID: sourceTenantID,
 					NewID: destinationTenantID,
 				},
+				TableRekeys:         tableRekeys,
+				TableSourceVersions: tableSourceVersions,
 			}
 			streamIngestionSpecs = append(streamIngestionSpecs, spec)
 		}
@@ -202,8 +202,10 @@ func completeIngestion(
 		return err
 	}
 	details := ingestionJob.Details().(jobspb.StreamIngestionDetails)
+	progress := ingestionJob.Progress()
+	cutoverTime := progress.GetStreamIngest().CutoverTime
 	log.Infof(ctx, "activating destination tenant %d", details.DestinationTenantID)
-	if err := activateTenant(ctx, execCtx, details.DestinationTenantID); err != nil {
+	if err := activateTenant(ctx, execCtx, details.DestinationTenantID, cutoverTime); err != nil {
 		return err
 	}
 
@@ -245,6 +247,9 @@ func ingest(ctx context.Context, execCtx sql.JobExecContext, ingestionJob *jobs.
 		log.Infof(ctx, "job completed cutover on resume")
 		return nil
 	}
+	if err := maybeRevertToInitialTimestamp(ctx, execCtx, ingestionJob); err != nil {
+		return err
+	}
 	if knobs := execCtx.ExecCfg().StreamingTestingKnobs; knobs != nil && knobs.BeforeIngestionStart != nil {
 		if err := knobs.BeforeIngestionStart(ctx); err != nil {
 			return err
@@ -526,7 +531,54 @@ func maybeRevertToCutoverTimestamp(
 	return true, nil
 }
 
-func activateTenant(ctx context.Context, execCtx interface{}, newTenantID roachpb.TenantID) error {
+// maybeRevertToInitialTimestamp reverts the destination tenant to the initial
+// revert time of the job, if it has one, so that the ingestion of the changes
+// made on the source since then starts from the same data. The high water of
+// the job is then set to that time, so that the stream only catches up on
+// these changes instead of scanning the whole source tenant.
+func maybeRevertToInitialTimestamp(
+	ctx context.Context, p sql.JobExecContext, ingestionJob *jobs.Job,
+) error {
+	progress := ingestionJob.Progress()
+	revertTo := progress.GetStreamIngest().InitialRevertTo
+	if revertTo.IsEmpty() {
+		return nil
+	}
+	ctx, span := tracing.ChildSpan(ctx, "streamingest.revertToInitialTimestamp")
+	defer span.Finish()
+
+	details := ingestionJob.Details().(jobspb.StreamIngestionDetails)
+	updateRunningStatus(ctx, p, ingestionJob, jobspb.InitializingReplication,
+		fmt.Sprintf("reverting the destination tenant to %s", revertTo))
+	batchSize := int64(sql.RevertTableDefaultBatchSize)
+	if p.ExecCfg().StreamingTestingKnobs != nil && p.ExecCfg().StreamingTestingKnobs.OverrideRevertRangeBatchSize != 0 {
+		batchSize = p.ExecCfg().StreamingTestingKnobs.OverrideRevertRangeBatchSize
+	}
+	if err := sql.RevertSpansFanout(ctx,
+		p.ExecCfg().DB,
+		p,
+		[]roachpb.Span{details.Span},
+		revertTo,
+		false, /* ignoreGCThreshold */
+		batchSize,
+		nil /* onCompletedCallback */); err != nil {
+		return err
+	}
+	return ingestionJob.NoTxn().Update(ctx,
+		func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+			md.Progress.GetStreamIngest().InitialRevertTo = hlc.Timestamp{}
+			md.Progress.Progress = &jobspb.Progress_HighWater{HighWater: &revertTo}
+			ju.UpdateProgress(md.Progress)
+			return nil
+		})
+}
+
+// activateTenant makes the destination tenant available for service, and
+// records the time it was cut over to, from which the tenant may be replicated
+// back into the source cluster.
+func activateTenant(
+	ctx context.Context, execCtx interface{}, newTenantID roachpb.TenantID, cutoverTime hlc.Timestamp,
+) error {
 	p := execCtx.(sql.JobExecContext)
 	execCfg := p.ExecCfg()
 	return execCfg.InternalDB.Txn(ctx, func(
@@ -539,6 +591,7 @@ func activateTenant(ctx context.Context, execCtx interface{}, newTenantID roachp
 
 		info.DataState = mtinfopb.DataStateReady
 		info.TenantReplicationJobID = 0
+		info.ReplicationCutoverTime = cutoverTime
 		return sql.UpdateTenantRecord(ctx, p.ExecCfg().Settings, txn, info)
 	})
 }
@@ -58,6 +58,27 @@ func redactSourceURI(addr string) (string, error) {
 	return cloud.SanitizeExternalStorageURI(addr, streamclient.RedactableURLParameters)
 }
 
+// validateStreamAddress checks that the address of the source cluster of a
+// replication stream authenticates with a certificate if it is a postgres
+// address.
+func validateStreamAddress(from string) (streamingccl.StreamAddress, error) {
+	streamAddress := streamingccl.StreamAddress(from)
+	streamURL, err := streamAddress.URL()
+	if err != nil {
+		return "", err
+	}
+	q := streamURL.Query()
+
+	// Operator should specify a postgres scheme address with cert authentication.
+	if hasPostgresAuthentication := (q.Get("sslmode") == "verify-full") &&
+		q.Has("sslrootcert") && q.Has("sslkey") && q.Has("sslcert"); (streamURL.Scheme == "postgres") &&
+		!hasPostgresAuthentication {
+		return "", errors.Errorf(
+			"stream replication address should have cert authentication if in postgres scheme: %s", streamAddress)
+	}
+	return streamingccl.StreamAddress(streamURL.String()), nil
+}
+
 func ingestionTypeCheck(
 	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
 ) (matched bool, _ colinfo.ResultColumns, _ error) {
@@ -71,6 +92,9 @@ func ingestionTypeCheck(
 		exprutil.Strings{
 			ingestionStmt.ReplicationSourceAddress,
 			ingestionStmt.Options.Retention},
+		exprutil.StringArrays{
+			tree.Exprs(ingestionStmt.Options.Tables),
+			tree.Exprs(ingestionStmt.Options.Databases)},
 	}
 	if ingestionStmt.Like.OtherTenant != nil {
 		toTypeCheck = append(toTypeCheck,
@@ -137,6 +161,10 @@ func ingestionPlanHook(
 		}
 	}
 
+	if ingestionStmt.Options.ResumeFromProtectedTimestamp {
+		return nil, nil, nil, false, errors.New(
+			"the resume_from_protected_ts option can only be used to start the replication of an existing tenant")
+	}
 	options, err := evalTenantReplicationOptions(ctx, ingestionStmt.Options, exprEval)
 	if err != nil {
 		return nil, nil, nil, false, err
@@ -161,22 +189,10 @@ func ingestionPlanHook(
 			return err
 		}
 
-		streamAddress := streamingccl.StreamAddress(from)
-		streamURL, err := streamAddress.URL()
+		streamAddress, err := validateStreamAddress(from)
 		if err != nil {
 			return err
 		}
-		q := streamURL.Query()
-
-		// Operator should specify a postgres scheme address with cert authentication.
-		if hasPostgresAuthentication := (q.Get("sslmode") == "verify-full") &&
-			q.Has("sslrootcert") && q.Has("sslkey") && q.Has("sslcert"); (streamURL.Scheme == "postgres") &&
-			!hasPostgresAuthentication {
-			return errors.Errorf(
-				"stream replication address should have cert authentication if in postgres scheme: %s", streamAddress)
-		}
-
-		streamAddress = streamingccl.StreamAddress(streamURL.String())
 
 		// TODO(adityamaru): Add privileges checks. Probably the same as RESTORE.
 		if roachpb.IsSystemTenantName(roachpb.TenantName(sourceTenant)) ||
@@ -228,7 +244,9 @@ func ingestionPlanHook(
 		// Create the producer job first for the purpose of observability, user is
 		// able to know the producer job id immediately after executing
 		// CREATE TENANT ... FROM REPLICATION.
-		replicationProducerSpec, err := client.Create(ctx, roachpb.TenantName(sourceTenant))
+		producerRequest := options.producerRequest()
+		replicationProducerSpec, err := client.Create(ctx, roachpb.TenantName(sourceTenant),
+			producerRequest)
 		if err != nil {
 			return err
 		}
@@ -236,6 +254,23 @@ func ingestionPlanHook(
 			return err
 		}
 
+		// A stream of some of the tables of the source tenant does not replicate
+		// its catalog, so the destination tenant gets its own, into which the
+		// descriptors of the replicated tables are written.
+		var replicatedTables []jobspb.StreamIngestionDetails_ReplicatedTable
+		if len(producerRequest.TableNames) > 0 || len(producerRequest.DatabaseNames) > 0 {
+			if err := sql.InitializeTenantKeyspace(
+				ctx, p, destinationTenantID, initialTenantZoneConfig,
+			); err != nil {
+				return err
+			}
+			replicatedTables, err = writeReplicatedDescriptors(
+				ctx, p, destinationTenantID, replicationProducerSpec)
+			if err != nil {
+				return err
+			}
+		}
+
 		prefix := keys.MakeTenantPrefix(destinationTenantID)
 		streamIngestionDetails := jobspb.StreamIngestionDetails{
 			StreamAddress:         string(streamAddress),
@@ -246,6 +281,7 @@ func ingestionPlanHook(
 			DestinationTenantName: roachpb.TenantName(dstTenantName),
 			ReplicationTTLSeconds: retentionTTLSeconds,
 			ReplicationStartTime:  replicationProducerSpec.ReplicationStartTime,
+			ReplicatedTables:      replicatedTables,
 		}
 
 		jobDescription, err := streamIngestionJobDescription(p, from, ingestionStmt)
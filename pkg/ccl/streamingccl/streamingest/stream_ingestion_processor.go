@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
@@ -155,6 +156,13 @@ type streamIngestionProcessor struct {
 	rekeyer *backupccl.KeyRewriter
 	// rewriteToDiffKey Indicates whether we are rekeying a key into a different key.
 	rewriteToDiffKey bool
+	// tableSpanRekeys are set when the stream replicates some of the tables of
+	// the source tenant, in which case rekeyer rewrites their keys to the IDs of
+	// the tables in the destination tenant, and keys it cannot rewrite, which
+	// belong to indexes that the tables did not have when the stream was
+	// created, are dropped. The descriptors of the tables in the source tenant
+	// are checked rather than ingested.
+	tableSpanRekeys []tableSpanRekey
 
 	// curKVBatch temporarily batches MVCC Keys so they can be
 	// sorted before ingestion.
@@ -253,11 +261,25 @@ func newStreamIngestionDataProcessor(
 	spec execinfrapb.StreamIngestionDataSpec,
 	post *execinfrapb.PostProcessSpec,
 ) (execinfra.Processor, error) {
-	rekeyer, err := backupccl.MakeKeyRewriterFromRekeys(flowCtx.Codec(),
-		nil /* tableRekeys */, []execinfrapb.TenantRekey{spec.TenantRekey},
-		true /* restoreTenantFromStream */)
-	if err != nil {
-		return nil, err
+	var rekeyer *backupccl.KeyRewriter
+	var tableSpanRekeys []tableSpanRekey
+	var err error
+	if len(spec.TableRekeys) > 0 {
+		rekeyer, err = backupccl.MakeKeyRewriterFromRekeys(keys.MakeSQLCodec(spec.TenantRekey.NewID),
+			spec.TableRekeys, nil /* tenantRekeys */, false /* restoreTenantFromStream */)
+		if err != nil {
+			return nil, err
+		}
+		if tableSpanRekeys, err = makeTableSpanRekeys(spec); err != nil {
+			return nil, err
+		}
+	} else {
+		rekeyer, err = backupccl.MakeKeyRewriterFromRekeys(flowCtx.Codec(),
+			nil /* tableRekeys */, []execinfrapb.TenantRekey{spec.TenantRekey},
+			true /* restoreTenantFromStream */)
+		if err != nil {
+			return nil, err
+		}
 	}
 	trackedSpans := make([]roachpb.Span, 0)
 	for _, partitionSpec := range spec.PartitionSpecs {
@@ -287,7 +309,8 @@ func newStreamIngestionDataProcessor(
 		cutoverCh:        make(chan struct{}),
 		closePoller:      make(chan struct{}),
 		rekeyer:          rekeyer,
-		rewriteToDiffKey: spec.TenantRekey.NewID != spec.TenantRekey.OldID,
+		rewriteToDiffKey: spec.TenantRekey.NewID != spec.TenantRekey.OldID || len(tableSpanRekeys) > 0,
+		tableSpanRekeys:  tableSpanRekeys,
 		logBufferEvery:   log.Every(30 * time.Second),
 	}
 	if err := sip.Init(ctx, sip, post, streamIngestionResultTypes, flowCtx, processorID, nil, /* memMonitor */
@@ -654,8 +677,99 @@ func (sip *streamIngestionProcessor) consumeEvents() (*jobspb.ResolvedSpans, err
 	return nil, nil
 }
 
+// tableSpanRekey maps the span of a replicated table in the source tenant to
+// its span in the destination tenant.
+type tableSpanRekey struct {
+	oldSpan   roachpb.Span
+	newPrefix roachpb.Key
+	// oldDescKey is the key of the descriptor of the table in the source tenant,
+	// which the stream carries, and oldVersion the version it had when the
+	// stream was created.
+	oldDescKey roachpb.Key
+	oldVersion descpb.DescriptorVersion
+	name       string
+}
+
+func makeTableSpanRekeys(spec execinfrapb.StreamIngestionDataSpec) ([]tableSpanRekey, error) {
+	oldCodec := keys.MakeSQLCodec(spec.TenantRekey.OldID)
+	newCodec := keys.MakeSQLCodec(spec.TenantRekey.NewID)
+	if len(spec.TableSourceVersions) != len(spec.TableRekeys) {
+		return nil, errors.AssertionFailedf("expected %d table source versions, found %d",
+			len(spec.TableRekeys), len(spec.TableSourceVersions))
+	}
+	rekeys := make([]tableSpanRekey, len(spec.TableRekeys))
+	for i, rekey := range spec.TableRekeys {
+		var desc descpb.Descriptor
+		if err := protoutil.Unmarshal(rekey.NewDesc, &desc); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling rekey descriptor for old table id %d", rekey.OldID)
+		}
+		table, _, _, _, _ := descpb.GetDescriptors(&desc)
+		if table == nil {
+			return nil, errors.New("expected a table descriptor")
+		}
+		oldPrefix := oldCodec.TablePrefix(rekey.OldID)
+		rekeys[i] = tableSpanRekey{
+			oldSpan:    roachpb.Span{Key: oldPrefix, EndKey: oldPrefix.PrefixEnd()},
+			newPrefix:  newCodec.TablePrefix(uint32(table.ID)),
+			oldDescKey: oldCodec.DescMetadataKey(rekey.OldID),
+			oldVersion: spec.TableSourceVersions[i],
+			name:       table.Name,
+		}
+	}
+	return rekeys, nil
+}
+
+// maybeCheckReplicatedDescriptor checks the KV if it is the descriptor of a
+// replicated table in the source tenant, and returns whether it was. Schema
+// changes in the source tenant are not replicated, and the keys they write,
+// e.g. those of new indexes, can't be rewritten to the destination tenant, so
+// the ingestion fails permanently once the descriptor of a replicated table
+// changes.
+func (sip *streamIngestionProcessor) maybeCheckReplicatedDescriptor(
+	kv *roachpb.KeyValue,
+) (bool, error) {
+	for _, rekey := range sip.tableSpanRekeys {
+		if !kv.Key.Equal(rekey.oldDescKey) {
+			continue
+		}
+		value, err := storage.DecodeMVCCValue(kv.Value.RawBytes)
+		if err != nil {
+			return true, err
+		}
+		if !value.Value.IsPresent() {
+			return true, jobs.MarkAsPermanentJobError(errors.Newf(
+				"replicated table %q was dropped in the source tenant", rekey.name))
+		}
+		var desc descpb.Descriptor
+		if err := value.Value.GetProto(&desc); err != nil {
+			return true, err
+		}
+		table, _, _, _, _ := descpb.GetDescriptors(&desc)
+		if table == nil {
+			return true, errors.AssertionFailedf("descriptor of replicated table %q is not a table", rekey.name)
+		}
+		if table.Version != rekey.oldVersion {
+			return true, jobs.MarkAsPermanentJobError(errors.WithHint(errors.Newf(
+				"replicated table %q was changed in the source tenant at %s: descriptor version %d, expected %d",
+				rekey.name, kv.Value.Timestamp, table.Version, rekey.oldVersion),
+				"schema changes are not replicated by streams of some of the tables of a tenant; "+
+					"create a new stream to replicate the table after its schema change"))
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// rekey rewrites the key to the destination tenant. It returns a nil key if the
+// key is dropped.
 func (sip *streamIngestionProcessor) rekey(key roachpb.Key) ([]byte, error) {
 	rekey, ok, err := sip.rekeyer.RewriteKey(key, 0 /*wallTime*/)
+	if len(sip.tableSpanRekeys) > 0 {
+		if err != nil || !ok {
+			return nil, err
+		}
+		return rekey, nil
+	}
 	if !ok {
 		return nil, errors.New("every key is expected to match tenant prefix")
 	}
@@ -713,6 +827,10 @@ func (sip *streamIngestionProcessor) bufferRangeKeyVal(
 	_, sp := tracing.ChildSpan(sip.Ctx(), "stream-ingestion-buffer-range-key")
 	defer sp.Finish()
 
+	if len(sip.tableSpanRekeys) > 0 {
+		return sip.bufferTableRangeKeyVals(rangeKeyVal)
+	}
+
 	var err error
 	rangeKeyVal.RangeKey.StartKey, err = sip.rekey(rangeKeyVal.RangeKey.StartKey)
 	if err != nil {
@@ -726,6 +844,39 @@ func (sip *streamIngestionProcessor) bufferRangeKeyVal(
 	return nil
 }
 
+// bufferTableRangeKeyVals buffers a range key of a stream of some of the tables
+// of the source tenant. The range key may span several tables, whose IDs in the
+// destination tenant need not be adjacent, so it is split at the boundaries of
+// the tables and each piece is rewritten separately.
+func (sip *streamIngestionProcessor) bufferTableRangeKeyVals(
+	rangeKeyVal storage.MVCCRangeKeyValue,
+) error {
+	rangeKeySpan := roachpb.Span{Key: rangeKeyVal.RangeKey.StartKey, EndKey: rangeKeyVal.RangeKey.EndKey}
+	for _, rekey := range sip.tableSpanRekeys {
+		sp := rekey.oldSpan.Intersect(rangeKeySpan)
+		if !sp.Valid() {
+			continue
+		}
+		piece := rangeKeyVal.Clone()
+		var err error
+		if sp.Key.Equal(rekey.oldSpan.Key) {
+			piece.RangeKey.StartKey = rekey.newPrefix
+		} else if piece.RangeKey.StartKey, err = sip.rekey(sp.Key.Clone()); err != nil {
+			return err
+		}
+		if sp.EndKey.Equal(rekey.oldSpan.EndKey) {
+			piece.RangeKey.EndKey = rekey.newPrefix.PrefixEnd()
+		} else if piece.RangeKey.EndKey, err = sip.rekey(sp.EndKey.Clone()); err != nil {
+			return err
+		}
+		if piece.RangeKey.StartKey == nil || piece.RangeKey.EndKey == nil {
+			continue
+		}
+		sip.rangeBatcher.buffer(piece)
+	}
+	return nil
+}
+
 func (sip *streamIngestionProcessor) maybeSizeFlush() (*jobspb.ResolvedSpans, error) {
 	sv := &sip.FlowCtx.Cfg.Settings.SV
 	kvBufMax := int(maxKVBufferSize.Get(sv))
@@ -748,11 +899,20 @@ func (sip *streamIngestionProcessor) bufferKV(kv *roachpb.KeyValue) error {
 		return errors.New("kv event expected to have kv")
 	}
 
+	if len(sip.tableSpanRekeys) > 0 {
+		if isDesc, err := sip.maybeCheckReplicatedDescriptor(kv); err != nil || isDesc {
+			return err
+		}
+	}
+
 	var err error
 	kv.Key, err = sip.rekey(kv.Key)
 	if err != nil {
 		return err
 	}
+	if kv.Key == nil {
+		return nil
+	}
 
 	if sip.rewriteToDiffKey {
 		kv.Value.ClearChecksum()
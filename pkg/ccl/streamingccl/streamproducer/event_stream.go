@@ -13,8 +13,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/replicationutils"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
@@ -22,8 +24,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
@@ -47,6 +52,11 @@ type eventStream struct {
 
 	data tree.Datums // Data to send to the consumer
 
+	// replicatedDeletesTables maps the IDs of the tables of a stream that
+	// filters replicated deletes to the IDs of their replicated deletes tables,
+	// once they are resolved.
+	replicatedDeletesTables map[descpb.ID]descpb.ID
+
 	// Fields below initialized when Start called.
 	rf          *rangefeed.RangeFeed          // Currently running rangefeed.
 	streamGroup ctxgroup.Group                // Context group controlling stream execution.
@@ -453,6 +463,13 @@ func (s *eventStream) streamLoop(ctx context.Context, frontier *span.Frontier) e
 		case ev := <-s.eventsCh:
 			switch {
 			case ev.Val != nil:
+				replicated, err := s.isReplicatedDelete(ctx, ev.Val)
+				if err != nil {
+					return err
+				}
+				if replicated {
+					break
+				}
 				addValue(ev.Val)
 				if err := maybeFlushBatch(flushIfNeeded); err != nil {
 					return err
@@ -495,6 +512,66 @@ func (s *eventStream) streamLoop(ctx context.Context, frontier *span.Frontier) e
 	}
 }
 
+// isReplicatedDelete returns true if the value is the deletion of a row that
+// was applied by logical replication and the stream filters such deletes, as
+// they would otherwise be replicated back to the cluster they came from, and
+// win against the newer writes of that cluster. Logical replication records
+// the deletes it applies in the replicated deletes table of the table, in the
+// same transaction, so that a delete is replicated if it has no record at its
+// timestamp.
+func (s *eventStream) isReplicatedDelete(
+	ctx context.Context, v *kvpb.RangeFeedValue,
+) (bool, error) {
+	if !s.spec.FilterReplicatedDeletes || v.Value.IsPresent() {
+		return false, nil
+	}
+	_, tableID, err := s.execCfg.Codec.DecodeTablePrefix(v.Key)
+	if err != nil {
+		return false, err
+	}
+	rowKey, err := keys.EnsureSafeSplitKey(v.Key)
+	if err != nil {
+		return false, err
+	}
+
+	ie := s.execCfg.InternalDB.Executor()
+	deletesTableID, ok := s.replicatedDeletesTables[descpb.ID(tableID)]
+	if !ok {
+		row, err := ie.QueryRowEx(ctx, "replication-stream-resolve-replicated-deletes-table", nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride, `
+SELECT d.id
+  FROM system.namespace AS t
+  JOIN system.namespace AS d ON d."parentID" = t."parentID" AND d."parentSchemaID" = t."parentSchemaID"
+ WHERE t.id = $1 AND d.name = $2 || t.name`,
+			tree.NewDInt(tree.DInt(tableID)), tree.NewDString(streamingccl.ReplicatedDeletesTablePrefix))
+		if err != nil {
+			return false, errors.Wrap(err, "resolving replicated deletes table")
+		}
+		// The table has no replicated deletes table until logical replication
+		// deletes its rows, and neither has it any replicated deletes.
+		if row == nil {
+			return false, nil
+		}
+		deletesTableID = descpb.ID(tree.MustBeDInt(row[0]))
+		s.replicatedDeletesTables[descpb.ID(tableID)] = deletesTableID
+	}
+
+	row, err := ie.QueryRowEx(ctx, "replication-stream-lookup-replicated-delete", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride,
+		fmt.Sprintf(`SELECT count(*) FROM [%d AS t] WHERE key = $1 AND deleted_at = $2`, deletesTableID),
+		tree.NewDBytes(tree.DBytes(rowKey)), eval.TimestampToDecimalDatum(v.Value.Timestamp))
+	if sqlerrors.IsUndefinedRelationError(err) {
+		// The replicated deletes table was dropped since it was resolved, and
+		// may have been created again.
+		delete(s.replicatedDeletesTables, descpb.ID(tableID))
+		return s.isReplicatedDelete(ctx, v)
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "looking up replicated delete")
+	}
+	return tree.MustBeDInt(row[0]) > 0, nil
+}
+
 func setConfigDefaults(cfg *streampb.StreamPartitionSpec_ExecutionConfig) {
 	const defaultInitialScanParallelism = 16
 	const defaultMinCheckpointFrequency = 10 * time.Second
@@ -543,5 +620,7 @@ func streamPartition(
 		subscribedSpans: subscribedSpans,
 		execCfg:         execCfg,
 		mon:             evalCtx.Planner.Mon(),
+
+		replicatedDeletesTables: make(map[descpb.ID]descpb.ID),
 	}, nil
 }
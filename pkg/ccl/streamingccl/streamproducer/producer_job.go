@@ -36,6 +36,7 @@ func makeTenantSpan(tenantID uint64) *roachpb.Span {
 func makeProducerJobRecord(
 	registry *jobs.Registry,
 	tenantID uint64,
+	spans []*roachpb.Span,
 	timeout time.Duration,
 	user username.SQLUsername,
 	ptsID uuid.UUID,
@@ -46,7 +47,7 @@ func makeProducerJobRecord(
 		Username:    user,
 		Details: jobspb.StreamReplicationDetails{
 			ProtectedTimestampRecordID: ptsID,
-			Spans:                      []*roachpb.Span{makeTenantSpan(tenantID)},
+			Spans:                      spans,
 			TenantID:                   roachpb.MustMakeTenantID(tenantID),
 		},
 		Progress: jobspb.StreamReplicationProgress{
@@ -100,6 +101,12 @@ func (p *producerJobResumer) Resume(ctx context.Context, execCtx interface{}) er
 			prog := j.Progress()
 			switch prog.GetStreamReplication().StreamIngestionStatus {
 			case jobspb.StreamReplicationProgress_FINISHED_SUCCESSFULLY:
+				// The protected timestamp is retained until the expiration of the job,
+				// which is set when the stream completes, so that the tenant can be
+				// replicated back into this cluster from it.
+				if p.timeSource.Now().Before(prog.GetStreamReplication().Expiration) {
+					continue
+				}
 				return p.releaseProtectedTimestamp(ctx, execCfg)
 			case jobspb.StreamReplicationProgress_FINISHED_UNSUCCESSFULLY:
 				return j.NoTxn().Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
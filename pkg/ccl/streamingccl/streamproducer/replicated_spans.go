@@ -0,0 +1,229 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamproducer
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/errors"
+)
+
+// resolveReplicatedTables returns the spans of the source tenant that a
+// replication stream restricted by req replicates, which are the spans of the
+// tables it names and of the tables in the databases it names. It also returns
+// the descriptors of these tables and of the databases and schemas that contain
+// them, which the destination writes into its own catalog under new IDs: the
+// stream does not replicate the system tables of the source tenant, so the
+// destination tenant only learns of the replicated tables.
+//
+// The names are resolved once, when the stream is created, by reading the
+// namespace table of the tenant: tables created afterwards, even in a
+// replicated database, are not replicated. The spans also include the
+// descriptor keys of the replicated tables, which the destination watches to
+// fail the stream when one of them changes, since schema changes are not
+// replicated.
+func resolveReplicatedTables(
+	ctx context.Context, txn *kv.Txn, tenantID roachpb.TenantID, req streampb.ReplicationProducerRequest,
+) ([]*roachpb.Span, []descpb.TableDescriptor, []descpb.Descriptor, error) {
+	codec := keys.MakeSQLCodec(tenantID)
+	tableIDs, err := resolveReplicatedTableIDs(ctx, txn, codec, req)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "resolving the replicated tables of tenant %s", tenantID)
+	}
+	tables, parents, err := readReplicatedDescriptors(ctx, txn, codec, tableIDs)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "reading the replicated descriptors of tenant %s", tenantID)
+	}
+	replicatedIDs := make([]descpb.ID, len(tables))
+	for i := range tables {
+		replicatedIDs[i] = tables[i].ID
+	}
+	spans := makeTableSpans(codec, replicatedIDs)
+	for _, id := range replicatedIDs {
+		descKey := codec.DescMetadataKey(uint32(id))
+		spans = append(spans, &roachpb.Span{Key: descKey, EndKey: descKey.Next()})
+	}
+	return spans, tables, parents, nil
+}
+
+// readReplicatedDescriptors reads the descriptors of the given objects, which
+// are those of the tables that hold data, and the descriptors of the databases
+// and schemas that contain them. Views and types hold no data and are skipped.
+func readReplicatedDescriptors(
+	ctx context.Context, txn *kv.Txn, codec keys.SQLCodec, ids []descpb.ID,
+) (tables []descpb.TableDescriptor, parents []descpb.Descriptor, _ error) {
+	readDesc := func(id descpb.ID) (*descpb.Descriptor, error) {
+		res, err := txn.Get(ctx, codec.DescMetadataKey(uint32(id)))
+		if err != nil {
+			return nil, err
+		}
+		if !res.Exists() {
+			return nil, errors.Newf("descriptor %d does not exist", id)
+		}
+		var desc descpb.Descriptor
+		if err := res.ValueProto(&desc); err != nil {
+			return nil, err
+		}
+		return &desc, nil
+	}
+	seenParents := make(map[descpb.ID]struct{})
+	addParent := func(id descpb.ID) error {
+		if _, ok := seenParents[id]; ok || id == keys.PublicSchemaID {
+			return nil
+		}
+		seenParents[id] = struct{}{}
+		desc, err := readDesc(id)
+		if err != nil {
+			return err
+		}
+		parents = append(parents, *desc)
+		return nil
+	}
+	seenTables := make(map[descpb.ID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seenTables[id]; ok {
+			continue
+		}
+		seenTables[id] = struct{}{}
+		desc, err := readDesc(id)
+		if err != nil {
+			return nil, nil, err
+		}
+		table, _, _, _, _ := descpb.GetDescriptors(desc)
+		if table == nil || table.IsView() {
+			continue
+		}
+		if table.ParentID == keys.SystemDatabaseID {
+			return nil, nil, errors.Newf("system table %q cannot be replicated", table.Name)
+		}
+		if table.Dropped() || table.Offline() {
+			return nil, nil, errors.Newf("table %q is not public", table.Name)
+		}
+		if len(table.Mutations) > 0 {
+			return nil, nil, errors.Newf("table %q has a schema change in progress", table.Name)
+		}
+		if len(table.DependsOnTypes) > 0 {
+			return nil, nil, errors.Newf(
+				"table %q uses user-defined types, which are not replicated", table.Name)
+		}
+		if err := addParent(table.ParentID); err != nil {
+			return nil, nil, err
+		}
+		if err := addParent(table.UnexposedParentSchemaID); err != nil {
+			return nil, nil, err
+		}
+		tables = append(tables, *table)
+	}
+	return tables, parents, nil
+}
+
+// resolveReplicatedTableIDs returns the IDs of the tables named by req and of
+// the objects in the databases it names. The tables named by req come last, in
+// the order they are named.
+func resolveReplicatedTableIDs(
+	ctx context.Context, txn *kv.Txn, codec keys.SQLCodec, req streampb.ReplicationProducerRequest,
+) ([]descpb.ID, error) {
+	prefix := codec.IndexPrefix(keys.NamespaceTableID, catconstants.NamespaceTablePrimaryIndexID)
+	rows, err := txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0 /* maxRows */)
+	if err != nil {
+		return nil, err
+	}
+	namespace := make(map[descpb.NameInfo]descpb.ID, len(rows))
+	for _, row := range rows {
+		nameInfo, err := catalogkeys.DecodeNameMetadataKey(codec, row.Key)
+		if err != nil {
+			return nil, err
+		}
+		namespace[nameInfo] = descpb.ID(row.ValueInt())
+	}
+
+	lookupDatabase := func(name string) (descpb.ID, error) {
+		id, ok := namespace[descpb.NameInfo{Name: name}]
+		if !ok {
+			return 0, errors.Newf("database %q does not exist", name)
+		}
+		return id, nil
+	}
+
+	replicatedDatabases := make(map[descpb.ID]struct{})
+	for _, name := range req.DatabaseNames {
+		id, err := lookupDatabase(name)
+		if err != nil {
+			return nil, err
+		}
+		replicatedDatabases[id] = struct{}{}
+	}
+	var tableIDs []descpb.ID
+	for nameInfo, id := range namespace {
+		// Only objects have a parent schema.
+		if nameInfo.ParentSchemaID == 0 {
+			continue
+		}
+		if _, ok := replicatedDatabases[nameInfo.ParentID]; ok {
+			tableIDs = append(tableIDs, id)
+		}
+	}
+
+	for _, name := range req.TableNames {
+		tn, err := parser.ParseTableName(name)
+		if err != nil {
+			return nil, err
+		}
+		dbName, scName := tn.Parts[1], catconstants.PublicSchemaName
+		switch tn.NumParts {
+		case 2:
+		case 3:
+			dbName, scName = tn.Parts[2], tn.Parts[1]
+		default:
+			return nil, errors.Newf("table name %q must be qualified by its database", name)
+		}
+		dbID, err := lookupDatabase(dbName)
+		if err != nil {
+			return nil, err
+		}
+		scID, ok := namespace[descpb.NameInfo{ParentID: dbID, Name: scName}]
+		if !ok {
+			if scName != catconstants.PublicSchemaName {
+				return nil, errors.Newf("schema %q does not exist in database %q", scName, dbName)
+			}
+			// Databases created before public schemas had descriptors use the
+			// synthetic public schema.
+			scID = keys.PublicSchemaID
+		}
+		id, ok := namespace[descpb.NameInfo{ParentID: dbID, ParentSchemaID: scID, Name: tn.Parts[0]}]
+		if !ok {
+			return nil, errors.Newf("table %q does not exist", name)
+		}
+		tableIDs = append(tableIDs, id)
+	}
+	return tableIDs, nil
+}
+
+// makeTableSpans returns the merged spans of the given tables.
+func makeTableSpans(codec keys.SQLCodec, tableIDs []descpb.ID) []*roachpb.Span {
+	spans := make([]roachpb.Span, 0, len(tableIDs))
+	for _, id := range tableIDs {
+		prefix := codec.TablePrefix(uint32(id))
+		spans = append(spans, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+	}
+	spans, _ = roachpb.MergeSpans(&spans)
+	res := make([]*roachpb.Span, len(spans))
+	for i := range spans {
+		res[i] = &spans[i]
+	}
+	return res
+}
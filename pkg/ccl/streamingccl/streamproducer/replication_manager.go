@@ -32,9 +32,16 @@ type replicationStreamManagerImpl struct {
 
 // StartReplicationStream implements streaming.ReplicationStreamManager interface.
 func (r *replicationStreamManagerImpl) StartReplicationStream(
-	ctx context.Context, tenantName roachpb.TenantName,
+	ctx context.Context, tenantName roachpb.TenantName, req streampb.ReplicationProducerRequest,
 ) (streampb.ReplicationProducerSpec, error) {
-	return startReplicationProducerJob(ctx, r.evalCtx, r.txn, tenantName)
+	return startReplicationProducerJob(ctx, r.evalCtx, r.txn, tenantName, req)
+}
+
+// StartReplicationStreamForTables implements streaming.ReplicationStreamManager interface.
+func (r *replicationStreamManagerImpl) StartReplicationStreamForTables(
+	ctx context.Context, req streampb.ReplicationProducerRequest,
+) (streampb.ReplicationProducerSpec, error) {
+	return startLogicalReplicationProducerJob(ctx, r.evalCtx, r.txn, req)
 }
 
 // HeartbeatReplicationStream implements streaming.ReplicationStreamManager interface.
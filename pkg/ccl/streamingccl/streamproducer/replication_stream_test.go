@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/desctestutils"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsql"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
@@ -295,6 +296,107 @@ func TestReplicationStreamInitialization(t *testing.T) {
 	})
 }
 
+func TestReplicationStreamTableFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	serverArgs := base.TestServerArgs{
+		DefaultTestTenant: base.TestTenantDisabled,
+		Knobs: base.TestingKnobs{
+			JobsTestingKnobs: jobs.NewTestingKnobsWithShortIntervals(),
+		},
+	}
+
+	h, cleanup := replicationtestutils.NewReplicationHelper(t, serverArgs)
+	defer cleanup()
+	testTenantName := roachpb.TenantName("test-tenant")
+	srcTenant, cleanupTenant := h.CreateTenant(t, serverutils.TestTenantID(), testTenantName)
+	defer cleanupTenant()
+
+	srcTenant.SQL.Exec(t, `
+CREATE DATABASE d;
+CREATE TABLE d.t1(i int primary key);
+CREATE TABLE d.t2(i int primary key);
+CREATE DATABASE hot;
+CREATE TABLE hot.t3(i int primary key);
+`)
+
+	startStream := func(
+		req streampb.ReplicationProducerRequest,
+	) (streampb.ReplicationProducerSpec, error) {
+		rawReq, err := protoutil.Marshal(&req)
+		require.NoError(t, err)
+		var rawReplicationProducerSpec []byte
+		if err := h.SysSQL.DB.QueryRowContext(context.Background(),
+			`SELECT crdb_internal.start_replication_stream($1, $2)`, testTenantName, rawReq,
+		).Scan(&rawReplicationProducerSpec); err != nil {
+			return streampb.ReplicationProducerSpec{}, err
+		}
+		var replicationProducerSpec streampb.ReplicationProducerSpec
+		require.NoError(t, protoutil.Unmarshal(rawReplicationProducerSpec, &replicationProducerSpec))
+		return replicationProducerSpec, nil
+	}
+
+	tableSpan := func(db, table string) roachpb.Span {
+		desc := desctestutils.TestingGetPublicTableDescriptor(h.SysServer.DB(), srcTenant.Codec, db, table)
+		return desc.TableSpan(srcTenant.Codec)
+	}
+
+	t.Run("filtered-spans", func(t *testing.T) {
+		producerSpec, err := startStream(streampb.ReplicationProducerRequest{
+			TableNames:    []string{"d.t1"},
+			DatabaseNames: []string{"hot"},
+		})
+		require.NoError(t, err)
+
+		// The spec holds the descriptors of the replicated tables and of their
+		// databases and schemas.
+		var tableNames, parentNames []string
+		for _, desc := range producerSpec.TableDescriptors {
+			tableNames = append(tableNames, desc.Name)
+		}
+		for i := range producerSpec.ParentDescriptors {
+			_, db, _, sc, _ := descpb.GetDescriptors(&producerSpec.ParentDescriptors[i])
+			if db != nil {
+				parentNames = append(parentNames, db.Name)
+			} else {
+				parentNames = append(parentNames, sc.Name)
+			}
+		}
+		require.ElementsMatch(t, []string{"t1", "t3"}, tableNames)
+		require.ElementsMatch(t, []string{"d", "public", "hot", "public"}, parentNames)
+
+		spec, rawSpec := &streampb.ReplicationStreamSpec{}, make([]byte, 0)
+		row := h.SysSQL.QueryRow(t, "SELECT crdb_internal.replication_stream_spec($1)", producerSpec.StreamID)
+		row.Scan(&rawSpec)
+		require.NoError(t, protoutil.Unmarshal(rawSpec, spec))
+
+		var spans roachpb.SpanGroup
+		for _, partition := range spec.Partitions {
+			spans.Add(partition.PartitionSpec.Spans...)
+		}
+		require.True(t, spans.Encloses(tableSpan("d", "t1"), tableSpan("hot", "t3")))
+		require.False(t, spans.Encloses(tableSpan("d", "t2")))
+		// The system tables of the tenant are not replicated, except for the
+		// descriptors of the replicated tables.
+		descriptorPrefix := srcTenant.Codec.TablePrefix(keys.DescriptorTableID)
+		require.False(t, spans.Encloses(roachpb.Span{Key: descriptorPrefix, EndKey: descriptorPrefix.PrefixEnd()}))
+		for _, desc := range producerSpec.TableDescriptors {
+			descKey := srcTenant.Codec.DescMetadataKey(uint32(desc.ID))
+			require.True(t, spans.Encloses(roachpb.Span{Key: descKey, EndKey: descKey.Next()}))
+		}
+	})
+
+	t.Run("unknown-names", func(t *testing.T) {
+		_, err := startStream(streampb.ReplicationProducerRequest{DatabaseNames: []string{"nope"}})
+		require.ErrorContains(t, err, `database "nope" does not exist`)
+		_, err = startStream(streampb.ReplicationProducerRequest{TableNames: []string{"d.nope"}})
+		require.ErrorContains(t, err, `table "d.nope" does not exist`)
+		_, err = startStream(streampb.ReplicationProducerRequest{TableNames: []string{"t1"}})
+		require.ErrorContains(t, err, "must be qualified by its database")
+	})
+}
+
 func encodeSpec(
 	t *testing.T,
 	h *replicationtestutils.ReplicationHelper,
@@ -16,12 +16,15 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprotectedts"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
 	"github.com/cockroachdb/cockroach/pkg/repstream/streampb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -36,11 +39,17 @@ import (
 //
 // 1. Tracks the liveness of the replication stream consumption.
 // 2. Updates the protected timestamp for spans being replicated.
+//
+// If req names tables or databases, the stream only replicates the spans of
+// their tables, whose descriptors, and those of the databases and schemas that
+// contain them, are returned in the spec.
 func startReplicationProducerJob(
-	ctx context.Context, evalCtx *eval.Context, txn isql.Txn, tenantName roachpb.TenantName,
+	ctx context.Context,
+	evalCtx *eval.Context,
+	txn isql.Txn,
+	tenantName roachpb.TenantName,
+	req streampb.ReplicationProducerRequest,
 ) (streampb.ReplicationProducerSpec, error) {
-	execConfig := evalCtx.Planner.ExecutorConfig().(*sql.ExecutorConfig)
-
 	if !kvserver.RangefeedEnabled.Get(&evalCtx.Settings.SV) {
 		return streampb.ReplicationProducerSpec{}, errors.Errorf("kv.rangefeed.enabled must be true to start a replication job")
 	}
@@ -51,22 +60,129 @@ func startReplicationProducerJob(
 	}
 	tenantID := tenantRecord.ID
 
+	spans := []*roachpb.Span{makeTenantSpan(tenantID)}
+	var tableDescs []descpb.TableDescriptor
+	var parentDescs []descpb.Descriptor
+	if len(req.TableNames) > 0 || len(req.DatabaseNames) > 0 {
+		spans, tableDescs, parentDescs, err = resolveReplicatedTables(
+			ctx, txn.KV(), roachpb.MustMakeTenantID(tenantID), req)
+		if err != nil {
+			return streampb.ReplicationProducerSpec{}, err
+		}
+		if len(spans) == 0 {
+			return streampb.ReplicationProducerSpec{}, errors.New("no tables to replicate")
+		}
+	}
+
+	// A stream that resumes from the cutover of the tenant starts at that time,
+	// which the former source of the tenant already has the data as of.
+	var startTime hlc.Timestamp
+	if req.ResumeFromCutover {
+		startTime = tenantRecord.ReplicationCutoverTime
+		if startTime.IsEmpty() {
+			return streampb.ReplicationProducerSpec{}, errors.Newf(
+				"tenant %q was not cut over from a replication stream", tenantName)
+		}
+	}
+
+	deprecatedSpansToProtect := roachpb.Spans{*makeTenantSpan(tenantID)}
+	targetToProtect := ptpb.MakeTenantsTarget([]roachpb.TenantID{roachpb.MustMakeTenantID(tenantID)})
+	spec, err := createProducerJob(ctx, evalCtx, txn, tenantID, spans, deprecatedSpansToProtect,
+		targetToProtect, startTime, false /* logical */)
+	if err != nil {
+		return streampb.ReplicationProducerSpec{}, err
+	}
+	spec.TableDescriptors = tableDescs
+	spec.ParentDescriptors = parentDescs
+	return spec, nil
+}
+
+// startLogicalReplicationProducerJob initializes a replication stream producer
+// job, like startReplicationProducerJob, for the tables of the tenant of the
+// caller named by req. The stream is consumed by logical replication, which
+// needs the descriptors of the tables to decode their rows, so they are
+// returned in the spec.
+func startLogicalReplicationProducerJob(
+	ctx context.Context, evalCtx *eval.Context, txn isql.Txn, req streampb.ReplicationProducerRequest,
+) (streampb.ReplicationProducerSpec, error) {
+	execConfig := evalCtx.Planner.ExecutorConfig().(*sql.ExecutorConfig)
+
+	if !kvserver.RangefeedEnabled.Get(&evalCtx.Settings.SV) {
+		return streampb.ReplicationProducerSpec{}, errors.Errorf("kv.rangefeed.enabled must be true to start a replication job")
+	}
+	if len(req.TableNames) == 0 || len(req.DatabaseNames) > 0 {
+		return streampb.ReplicationProducerSpec{}, errors.New(
+			"a replication stream for logical replication must name the tables it replicates")
+	}
+
+	_, tenantID, err := keys.DecodeTenantPrefix(execConfig.Codec.TenantPrefix())
+	if err != nil {
+		return streampb.ReplicationProducerSpec{}, err
+	}
+	tableIDs, err := resolveReplicatedTableIDs(ctx, txn.KV(), execConfig.Codec, req)
+	if err != nil {
+		return streampb.ReplicationProducerSpec{}, err
+	}
+	tableDescs := make([]descpb.TableDescriptor, 0, len(tableIDs))
+	for _, id := range tableIDs {
+		desc, err := txn.(descs.Txn).Descriptors().ByID(txn.KV()).WithoutNonPublic().Get().Table(ctx, id)
+		if err != nil {
+			return streampb.ReplicationProducerSpec{}, err
+		}
+		tableDescs = append(tableDescs, *desc.TableDesc())
+	}
+
+	spans := makeTableSpans(execConfig.Codec, tableIDs)
+	deprecatedSpansToProtect := make(roachpb.Spans, 0, len(spans))
+	for _, sp := range spans {
+		deprecatedSpansToProtect = append(deprecatedSpansToProtect, *sp)
+	}
+	targetToProtect := ptpb.MakeSchemaObjectsTarget(tableIDs)
+	spec, err := createProducerJob(ctx, evalCtx, txn, tenantID.ToUint64(), spans,
+		deprecatedSpansToProtect, targetToProtect, hlc.Timestamp{} /* startTime */, true /* logical */)
+	if err != nil {
+		return streampb.ReplicationProducerSpec{}, err
+	}
+	spec.TableDescriptors = tableDescs
+	return spec, nil
+}
+
+// createProducerJob creates the producer job of a replication stream of the
+// given spans of a tenant, along with the protected timestamp record that
+// protects them from garbage collection until they are consumed. The stream
+// starts at startTime, or at the time of the statement if it is empty. A
+// logical stream is consumed by logical replication.
+func createProducerJob(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	txn isql.Txn,
+	tenantID uint64,
+	spans []*roachpb.Span,
+	deprecatedSpansToProtect roachpb.Spans,
+	targetToProtect *ptpb.Target,
+	startTime hlc.Timestamp,
+	logical bool,
+) (streampb.ReplicationProducerSpec, error) {
+	execConfig := evalCtx.Planner.ExecutorConfig().(*sql.ExecutorConfig)
 	registry := execConfig.JobRegistry
 	timeout := streamingccl.StreamReplicationJobLivenessTimeout.Get(&evalCtx.Settings.SV)
 	ptsID := uuid.MakeV4()
 
-	jr := makeProducerJobRecord(registry, tenantID, timeout, evalCtx.SessionData().User(), ptsID)
+	jr := makeProducerJobRecord(registry, tenantID, spans, timeout, evalCtx.SessionData().User(), ptsID)
+	details := jr.Details.(jobspb.StreamReplicationDetails)
+	details.Logical = logical
+	jr.Details = details
 	if _, err := registry.CreateAdoptableJobWithTxn(ctx, jr, jr.JobID, txn); err != nil {
 		return streampb.ReplicationProducerSpec{}, err
 	}
 
 	ptp := execConfig.ProtectedTimestampProvider.WithTxn(txn)
-	statementTime := hlc.Timestamp{
-		WallTime: evalCtx.GetStmtTimestamp().UnixNano(),
+	if startTime.IsEmpty() {
+		startTime = hlc.Timestamp{
+			WallTime: evalCtx.GetStmtTimestamp().UnixNano(),
+		}
 	}
-	deprecatedSpansToProtect := roachpb.Spans{*makeTenantSpan(tenantID)}
-	targetToProtect := ptpb.MakeTenantsTarget([]roachpb.TenantID{roachpb.MustMakeTenantID(tenantID)})
-	pts := jobsprotectedts.MakeRecord(ptsID, int64(jr.JobID), statementTime,
+	pts := jobsprotectedts.MakeRecord(ptsID, int64(jr.JobID), startTime,
 		deprecatedSpansToProtect, jobsprotectedts.Jobs, targetToProtect)
 
 	if err := ptp.Protect(ctx, pts); err != nil {
@@ -74,7 +190,7 @@ func startReplicationProducerJob(
 	}
 	return streampb.ReplicationProducerSpec{
 		StreamID:             streampb.StreamID(jr.JobID),
-		ReplicationStartTime: statementTime,
+		ReplicationStartTime: startTime,
 	}, nil
 }
 
@@ -264,6 +380,7 @@ func getReplicationStreamSpec(
 				Config: streampb.StreamPartitionSpec_ExecutionConfig{
 					MinCheckpointFrequency: streamingccl.StreamReplicationMinCheckpointFrequency.Get(&evalCtx.Settings.SV),
 				},
+				FilterReplicatedDeletes: details.Logical,
 			},
 		})
 	}
@@ -294,6 +411,8 @@ func completeReplicationStream(
 			if successfulIngestion {
 				md.Progress.GetStreamReplication().StreamIngestionStatus =
 					jobspb.StreamReplicationProgress_FINISHED_SUCCESSFULLY
+				md.Progress.GetStreamReplication().Expiration = timeutil.Now().Add(
+					streamingccl.StreamReplicationFailbackRetention.Get(&evalCtx.Settings.SV))
 				md.Progress.RunningStatus = "succeeding this producer job as the corresponding " +
 					"stream ingestion finished successfully"
 			} else {
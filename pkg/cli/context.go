@@ -67,6 +67,7 @@ func initCLIDefaults() {
 	setCertContextDefaults()
 	setDebugRecoverContextDefaults()
 	setDebugSendKVBatchContextDefaults()
+	setDebugAllocatorSimulateContextDefaults()
 
 	initPreFlagsDefaults()
 
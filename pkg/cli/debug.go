@@ -1326,6 +1326,7 @@ var debugCmds = []*cobra.Command{
 	debugResetQuorumCmd,
 	debugSendKVBatchCmd,
 	debugRecoverCmd,
+	debugAllocatorSimulateCmd,
 }
 
 // DebugCmd is the root of all debug commands. Exported to allow modification by CCL code.
@@ -1503,6 +1504,22 @@ func init() {
 	f.Var(&debugTimeSeriesDumpOpts.from, "from", "oldest timestamp to include (inclusive)")
 	f.Var(&debugTimeSeriesDumpOpts.to, "to", "newest timestamp to include (inclusive)")
 
+	f = debugAllocatorSimulateCmd.Flags()
+	f.DurationVar(&debugAllocatorSimulateOpts.duration, "duration", debugAllocatorSimulateOpts.duration,
+		"simulated duration over which the allocator acts on the proposed changes")
+	f.IntVar(&debugAllocatorSimulateOpts.addNodes, "add-nodes", debugAllocatorSimulateOpts.addNodes,
+		"number of nodes to add to the cluster")
+	f.IntVar(&debugAllocatorSimulateOpts.storesPerNode, "stores-per-node", debugAllocatorSimulateOpts.storesPerNode,
+		"number of stores on each added node")
+	f.Var(&debugAllocatorSimulateOpts.locality, "locality",
+		"locality of the added nodes, e.g. region=us-east1,zone=us-east1-b")
+	f.IntSliceVar(&debugAllocatorSimulateOpts.decommissionNodes, "decommission-nodes", nil,
+		"list of IDs of the nodes to decommission")
+	f.StringVar(&debugAllocatorSimulateOpts.zoneConfigFile, "zone-config", debugAllocatorSimulateOpts.zoneConfigFile,
+		"file containing a YAML zone config override; fields which are not set are unchanged")
+	f.IntVar(&debugAllocatorSimulateOpts.tableID, "table-id", debugAllocatorSimulateOpts.tableID,
+		"ID of the table the zone config override applies to; the whole keyspace if unset")
+
 	f = debugSendKVBatchCmd.Flags()
 	f.StringVar(&debugSendKVBatchContext.traceFormat, "trace", debugSendKVBatchContext.traceFormat,
 		"which format to use for the trace output (off, text, jaeger)")
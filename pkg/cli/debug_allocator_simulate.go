@@ -0,0 +1,235 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/clierrorplus"
+	"github.com/cockroachdb/cockroach/pkg/cli/clisqlexec"
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// TODO(knz): this struct belongs elsewhere.
+// See: https://github.com/cockroachdb/cockroach/issues/49509
+var debugAllocatorSimulateOpts = struct {
+	// The simulated duration.
+	duration time.Duration
+	// The number of nodes to add, the number of stores on each of them and
+	// their locality.
+	addNodes      int
+	storesPerNode int
+	locality      roachpb.Locality
+	// The IDs of the nodes to decommission.
+	decommissionNodes []int
+	// The file containing the zone config override, in YAML, and the ID of the
+	// table it applies to. The override applies to the whole keyspace if no
+	// table ID is given.
+	zoneConfigFile string
+	tableID        int
+}{}
+
+func setDebugAllocatorSimulateContextDefaults() {
+	debugAllocatorSimulateOpts.duration = 30 * time.Minute
+	debugAllocatorSimulateOpts.addNodes = 0
+	debugAllocatorSimulateOpts.storesPerNode = 1
+	debugAllocatorSimulateOpts.locality = roachpb.Locality{}
+	debugAllocatorSimulateOpts.decommissionNodes = nil
+	debugAllocatorSimulateOpts.zoneConfigFile = ""
+	debugAllocatorSimulateOpts.tableID = 0
+}
+
+var debugAllocatorSimulateCmd = &cobra.Command{
+	Use:   "allocator-simulate",
+	Short: "simulate the effect of cluster changes on replica placement",
+	Long: `
+Snapshots the stores, ranges, range load and zone configurations of the
+running cluster, and simulates how the allocator would move replicas and
+leases in response to the proposed changes: adding nodes, decommissioning
+nodes, or overriding zone configurations. Nodes which are already
+decommissioning continue to be decommissioned in the simulation.
+
+The command reports, for every store, the predicted number of replicas,
+leases and QPS before and after the simulation, along with the number of
+replica moves and lease transfers, and the simulated time until the
+replica placement stopped changing.
+
+The simulation is an estimate: it replays the load recently recorded on
+each range at a constant rate, and does not simulate non-voting replicas.
+Requires the admin role.
+
+For example, to predict the effect of adding three nodes in a new region:
+
+  cockroach debug allocator-simulate --add-nodes=3 --locality=region=us-west1
+
+To predict the effect of changing the zone config of table 104:
+
+  cockroach debug allocator-simulate --zone-config=zone.yaml --table-id=104
+`,
+	Args: cobra.NoArgs,
+	RunE: clierrorplus.MaybeDecorateError(runDebugAllocatorSimulate),
+}
+
+func runDebugAllocatorSimulate(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := makeAllocatorSimulateRequest()
+	if err != nil {
+		return err
+	}
+
+	c, finish, err := getAdminClient(ctx, serverCfg)
+	if err != nil {
+		return err
+	}
+	defer finish()
+
+	resp, err := c.AllocatorSimulate(ctx, req)
+	if err != nil {
+		return errors.Wrap(err, "while simulating allocator changes")
+	}
+	return printAllocatorSimulateResponse(resp)
+}
+
+// makeAllocatorSimulateRequest returns the request described by the
+// command-line flags.
+func makeAllocatorSimulateRequest() (*serverpb.AllocatorSimulateRequest, error) {
+	opts := &debugAllocatorSimulateOpts
+	req := &serverpb.AllocatorSimulateRequest{Duration: opts.duration}
+	if opts.addNodes < 0 {
+		return nil, errors.Newf("--add-nodes must be non-negative; got %d", opts.addNodes)
+	}
+	if opts.storesPerNode < 1 {
+		return nil, errors.Newf("--stores-per-node must be positive; got %d", opts.storesPerNode)
+	}
+	if opts.addNodes > 0 {
+		req.AddNodes = append(req.AddNodes, serverpb.AllocatorSimulateRequest_AddNodes{
+			Count:         int32(opts.addNodes),
+			StoresPerNode: int32(opts.storesPerNode),
+			Locality:      opts.locality,
+		})
+	}
+	for _, nodeID := range opts.decommissionNodes {
+		req.DecommissionNodeIDs = append(req.DecommissionNodeIDs, roachpb.NodeID(nodeID))
+	}
+
+	if opts.zoneConfigFile == "" {
+		if opts.tableID != 0 {
+			return nil, errors.New("--table-id requires --zone-config")
+		}
+		return req, nil
+	}
+	zoneYAML, err := os.ReadFile(opts.zoneConfigFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read zone config")
+	}
+	// Fields which aren't set in the YAML remain unset, so that they are not
+	// overridden by the simulation.
+	zone := zonepb.NewZoneConfig()
+	if err := yaml.UnmarshalStrict(zoneYAML, zone); err != nil {
+		return nil, errors.Wrap(err, "could not parse zone config")
+	}
+	span := roachpb.Span{Key: keys.MinKey, EndKey: keys.MaxKey}
+	if opts.tableID != 0 {
+		prefix := keys.SystemSQLCodec.TablePrefix(uint32(opts.tableID))
+		span = roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+	}
+	req.ZoneConfigChanges = append(req.ZoneConfigChanges, serverpb.AllocatorSimulateRequest_ZoneConfigChange{
+		Span:       span,
+		ZoneConfig: *zone,
+	})
+	return req, nil
+}
+
+var allocatorSimulateStoreColumnHeaders = []string{
+	"store_id",
+	"node_id",
+	"change",
+	"replicas_before",
+	"replicas_after",
+	"leases_before",
+	"leases_after",
+	"qps_before",
+	"qps_after",
+}
+
+func allocatorSimulateStoreAlignment() string {
+	return "rrlrrrrrr"
+}
+
+func allocatorSimulateStoreValueToRows(
+	stores []serverpb.AllocatorSimulateResponse_Store,
+) [][]string {
+	var rows [][]string
+	for _, s := range stores {
+		change := ""
+		switch {
+		case s.Added:
+			change = "added"
+		case s.Decommissioning:
+			change = "decommissioning"
+		}
+		rows = append(rows, []string{
+			strconv.FormatInt(int64(s.StoreID), 10),
+			strconv.FormatInt(int64(s.NodeID), 10),
+			change,
+			strconv.FormatInt(s.ReplicasBefore, 10),
+			strconv.FormatInt(s.ReplicasAfter, 10),
+			strconv.FormatInt(s.LeasesBefore, 10),
+			strconv.FormatInt(s.LeasesAfter, 10),
+			strconv.FormatFloat(s.QPSBefore, 'f', 2, 64),
+			strconv.FormatFloat(s.QPSAfter, 'f', 2, 64),
+		})
+	}
+	return rows
+}
+
+func printAllocatorSimulateResponse(resp *serverpb.AllocatorSimulateResponse) error {
+	if err := sqlExecCtx.PrintQueryOutput(os.Stdout, stderr, allocatorSimulateStoreColumnHeaders,
+		clisqlexec.NewRowSliceIter(
+			allocatorSimulateStoreValueToRows(resp.Stores),
+			allocatorSimulateStoreAlignment(),
+		)); err != nil {
+		return err
+	}
+
+	balance := func(b serverpb.AllocatorSimulateResponse_Balance) string {
+		return fmt.Sprintf("mean %.2f, min %.2f, max %.2f, stddev %.2f", b.Mean, b.Min, b.Max, b.StdDev)
+	}
+	fmt.Printf("\nsimulated %d ranges\n", resp.RangesSimulated)
+	fmt.Printf("replica balance before: %s\n", balance(resp.ReplicaBalanceBefore))
+	fmt.Printf("replica balance after:  %s\n", balance(resp.ReplicaBalanceAfter))
+	fmt.Printf("lease balance before:   %s\n", balance(resp.LeaseBalanceBefore))
+	fmt.Printf("lease balance after:    %s\n", balance(resp.LeaseBalanceAfter))
+	fmt.Printf("qps balance before:     %s\n", balance(resp.QPSBalanceBefore))
+	fmt.Printf("qps balance after:      %s\n", balance(resp.QPSBalanceAfter))
+	fmt.Printf("replica moves: %d, lease transfers: %d, range splits: %d, rebalanced: %s\n",
+		resp.ReplicaMoves, resp.LeaseTransfers, resp.RangeSplits,
+		humanizeutil.IBytes(resp.RebalancedBytes))
+	if resp.Converged {
+		fmt.Printf("converged after %s\n", resp.TimeToConverge)
+	} else {
+		fmt.Printf("not converged; replicas were still moving after %s\n", resp.TimeToConverge)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAllocatorSimulateRequest verifies that the flags of the `debug
+// allocator-simulate` command are translated into the simulation request.
+func TestAllocatorSimulateRequest(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// Avoid leaking configuration changes after the tests end.
+	defer initCLIDefaults()
+
+	zoneFile := filepath.Join(t.TempDir(), "zone.yaml")
+	require.NoError(t, os.WriteFile(zoneFile, []byte("num_replicas: 5\n"), 0644))
+	badZoneFile := filepath.Join(t.TempDir(), "bad_zone.yaml")
+	require.NoError(t, os.WriteFile(badZoneFile, []byte("num_replicaz: 5\n"), 0644))
+
+	f := debugAllocatorSimulateCmd.Flags()
+
+	t.Run("add and decommission nodes", func(t *testing.T) {
+		initCLIDefaults()
+		require.NoError(t, f.Parse([]string{
+			"--duration=1h", "--add-nodes=2", "--stores-per-node=3",
+			"--locality=region=us-west1", "--decommission-nodes=1,2",
+		}))
+		req, err := makeAllocatorSimulateRequest()
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, req.Duration)
+		require.Equal(t, []serverpb.AllocatorSimulateRequest_AddNodes{{
+			Count:         2,
+			StoresPerNode: 3,
+			Locality:      roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: "us-west1"}}},
+		}}, req.AddNodes)
+		require.Equal(t, []roachpb.NodeID{1, 2}, req.DecommissionNodeIDs)
+		require.Empty(t, req.ZoneConfigChanges)
+	})
+
+	t.Run("zone config override", func(t *testing.T) {
+		initCLIDefaults()
+		require.NoError(t, f.Parse([]string{"--zone-config=" + zoneFile, "--table-id=104"}))
+		req, err := makeAllocatorSimulateRequest()
+		require.NoError(t, err)
+		require.Empty(t, req.AddNodes)
+		require.Len(t, req.ZoneConfigChanges, 1)
+		change := req.ZoneConfigChanges[0]
+		prefix := keys.SystemSQLCodec.TablePrefix(104)
+		require.Equal(t, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}, change.Span)
+		require.Equal(t, int32(5), *change.ZoneConfig.NumReplicas)
+		// Fields which aren't set in the override remain unset.
+		require.Nil(t, change.ZoneConfig.RangeMaxBytes)
+		require.True(t, change.ZoneConfig.InheritedConstraints)
+	})
+
+	for _, args := range [][]string{
+		{"--add-nodes=-1"},
+		{"--stores-per-node=0"},
+		{"--table-id=104"},
+		{"--zone-config=" + badZoneFile},
+		{"--zone-config=" + filepath.Join(t.TempDir(), "missing.yaml")},
+	} {
+		t.Run(args[0], func(t *testing.T) {
+			initCLIDefaults()
+			require.NoError(t, f.Parse(args))
+			_, err := makeAllocatorSimulateRequest()
+			require.Error(t, err)
+		})
+	}
+}
@@ -535,6 +535,15 @@ const (
 	// Step 1b: Add new version for 23.2 development here.
 	// Do not add new versions to a patch release.
 	// *************************************************
+
+	// V23_2_Witnesses is the version at which ranges may have witness replicas,
+	// i.e. voting replicas without the state machine, which older binaries
+	// don't know about.
+	V23_2_Witnesses
+
+	// V23_2_SharedLocks is the version at which locking reads with FOR SHARE
+	// strength acquire Shared locks, which older binaries don't support.
+	V23_2_SharedLocks
 )
 
 func (k Key) String() string {
@@ -930,6 +939,15 @@ var rawVersionsSingleton = keyedVersions{
 	// Step 2b: Add new version gates for 23.2 development here.
 	// Do not add new versions to a patch release.
 	// *************************************************
+
+	{
+		Key:     V23_2_Witnesses,
+		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 4},
+	},
+	{
+		Key:     V23_2_SharedLocks,
+		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 6},
+	},
 }
 
 // developmentBranch must true on the main development branch but
@@ -384,6 +384,12 @@ var specs = []stmtSpec{
 		replace: map[string]string{"a_expr": "job_id", "alter_changefeed_cmds": "( 'ADD' target ( ( ',' target ) )* ( 'WITH' ( initial_scan | no_initial_scan ) )? | 'DROP' target ( ( ',' target ) )* | ( 'SET' | 'UNSET' ) option ( ( ',' option ) )* )+"},
 		unlink:  []string{"job_id", "target", "option", "initial_scan", "no_initial_scan"},
 	},
+	{
+		name:    "alter_changefeed_backfill",
+		stmt:    "alter_changefeed_stmt",
+		replace: map[string]string{"a_expr": "job_id", "alter_changefeed_cmds": "'BACKFILL' 'TABLE' table_name ( 'WHERE' filter_expr )? 'INTO' sink ( 'WITH' option ( ( ',' option ) )* )?"},
+		unlink:  []string{"job_id", "table_name", "filter_expr", "sink", "option"},
+	},
 	{
 		name:   "alter_column",
 		stmt:   "alter_onetable_stmt",
@@ -555,6 +561,11 @@ var specs = []stmtSpec{
 		unlink:  []string{"column_new_name", "constraint_name", "constraint_new_name", "variable", "value"},
 		nosplit: true,
 	},
+	{
+		name:    "alter_tenant_replication_stmt",
+		replace: map[string]string{"'OF' d_expr 'ON' d_expr": "'OF' source_tenant_name 'ON' source_uri"},
+		unlink:  []string{"source_tenant_name", "source_uri"},
+	},
 	{
 		name:    "alter_type",
 		stmt:    "alter_type_stmt",
@@ -679,6 +690,15 @@ var specs = []stmtSpec{
 		inline: []string{"opt_transaction"},
 		match:  []*regexp.Regexp{regexp.MustCompile("'COMMIT'|'END'")},
 	},
+	{
+		name: "compact_backups_stmt",
+		replace: map[string]string{
+			"string_or_placeholder_opt_list": "( collectionURI | '(' localityURI ( ',' localityURI )* ')' )",
+			"'FROM' string_or_placeholder":   "'FROM' timestamp",
+			"'TO' string_or_placeholder":     "'TO' timestamp",
+		},
+		unlink: []string{"collectionURI", "localityURI", "timestamp"},
+	},
 	{
 		name:    "copy_stmt",
 		inline:  []string{"opt_with_copy_options", "copy_options_list", "opt_with", "opt_where_clause", "where_clause"},
@@ -762,6 +782,15 @@ var specs = []stmtSpec{
 		},
 		nosplit: true,
 	},
+	{
+		name: "create_logical_replication_stream_stmt",
+		replace: map[string]string{
+			"'FROM' 'TABLE' db_object_name": "'FROM' 'TABLE' remote_table_name",
+			"'INTO' 'TABLE' db_object_name": "'INTO' 'TABLE' table_name",
+			"string_or_placeholder":         "source_uri",
+		},
+		unlink: []string{"remote_table_name", "table_name", "source_uri"},
+	},
 	{
 		name:   "create_schedule_for_backup_stmt",
 		inline: []string{"string_or_placeholder_opt_list", "string_or_placeholder_list", "opt_with_backup_options", "cron_expr", "opt_full_backup_clause", "opt_with_schedule_options", "opt_backup_targets"},
@@ -947,10 +976,11 @@ var specs = []stmtSpec{
 		inline: []string{"drop_ddl_stmt"},
 	},
 	{
-		name:   "drop_table",
-		stmt:   "drop_table_stmt",
-		inline: []string{"opt_drop_behavior"},
-		match:  []*regexp.Regexp{regexp.MustCompile("'DROP' 'TABLE'")},
+		name:    "drop_table",
+		stmt:    "drop_table_stmt",
+		inline:  []string{"opt_drop_behavior"},
+		match:   []*regexp.Regexp{regexp.MustCompile("'DROP' 'TABLE'")},
+		replace: map[string]string{"opt_with_storage_parameter_list": "( 'WITH' '(' 'purge' '=' ( 'true' | 'false' ) ')' | )"},
 	},
 	{
 		name:    "drop_type",
@@ -1025,6 +1055,16 @@ var specs = []stmtSpec{
 		},
 		unlink: []string{"CSV", "file_location"},
 	},
+	{
+		name: "export_into_iceberg",
+		stmt: "export_stmt",
+		replace: map[string]string{
+			"import_format":         "'ICEBERG'",
+			"string_or_placeholder": "table_location",
+			"select_stmt":           "(| 'select_stmt' | 'TABLE' 'table_name')",
+		},
+		unlink: []string{"table_location"},
+	},
 	{
 		name:   "family_def",
 		inline: []string{"name_list"},
@@ -1208,8 +1248,9 @@ var specs = []stmtSpec{
 			"backup_targets":                         "( 'TABLE' table_pattern ( ( ',' table_pattern ) )* | 'DATABASE' database_name ( ( ',' database_name ) )* )",
 			"string_or_placeholder":                  "( ( subdirectory | 'LATEST' ) )",
 			"list_of_string_or_placeholder_opt_list": "( collectionURI | '(' localityURI ( ',' localityURI )* ')' )",
+			"'WHERE' a_expr 'INTO' name":             "'WHERE' row_filter 'INTO' new_table_name",
 		},
-		unlink: []string{"subdirectory", "timestamp", "collectionURI", "localityURI"},
+		unlink: []string{"subdirectory", "timestamp", "collectionURI", "localityURI", "row_filter", "new_table_name"},
 		exclude: []*regexp.Regexp{
 			regexp.MustCompile("'REPLICATION' 'STREAM' 'FROM'"),
 		},
@@ -1407,6 +1448,18 @@ var specs = []stmtSpec{
 		},
 		unlink: []string{"subdirectory", "location", "location_opt_list"},
 	},
+	{
+		name:   "show_backup_rows",
+		stmt:   "show_backup_stmt",
+		inline: []string{"opt_as_of_clause", "as_of_clause", "opt_where_clause", "where_clause"},
+		match:  []*regexp.Regexp{regexp.MustCompile("'ROWS'")},
+		replace: map[string]string{
+			"'FROM' string_or_placeholder 'IN' string_or_placeholder_opt_list": "'FROM' subdirectory 'IN' location_opt_list",
+			"'TIME' a_expr":  "'TIME' timestamp",
+			"'WHERE' a_expr": "'WHERE' row_filter",
+		},
+		unlink: []string{"subdirectory", "location_opt_list", "timestamp", "row_filter"},
+	},
 	{
 		name:    "show_jobs",
 		stmt:    "show_jobs_stmt",
@@ -1553,7 +1606,7 @@ var specs = []stmtSpec{
 	},
 	{
 		name:    "truncate_stmt",
-		inline:  []string{"opt_table", "relation_expr_list", "opt_drop_behavior"},
+		inline:  []string{"opt_table", "relation_expr_list", "opt_drop_behavior", "opt_purge"},
 		replace: map[string]string{"relation_expr": "table_name"},
 		unlink:  []string{"table_name"},
 	},
@@ -1631,6 +1684,14 @@ var specs = []stmtSpec{
 		replace: map[string]string{"alter_table_cmds": "'VALIDATE' 'CONSTRAINT' constraint_name", "relation_expr": "table_name"},
 		unlink:  []string{"constraint_name", "table_name"},
 	},
+	{
+		name: "verify_backup_stmt",
+		replace: map[string]string{
+			"'FROM' string_or_placeholder 'IN' string_or_placeholder_opt_list": "'FROM' subdirectory 'IN' location_opt_list",
+			"'FROM' string_or_placeholder opt_with_verify_backup_options":      "'FROM' location opt_with_verify_backup_options",
+		},
+		unlink: []string{"subdirectory", "location", "location_opt_list"},
+	},
 	{
 		name:   "window_definition",
 		inline: []string{"window_specification"},
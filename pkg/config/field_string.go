@@ -17,11 +17,14 @@ func _() {
 	_ = x[Constraints-7]
 	_ = x[VoterConstraints-8]
 	_ = x[LeasePreferences-9]
+	_ = x[RangeMaxWriteRequestsPerSecond-10]
+	_ = x[RangeMaxWriteBytesPerSecond-11]
+	_ = x[NumWitnesses-12]
 }
 
-const _Field_name = "range_min_bytesrange_max_bytesglobal_readsnum_replicasnum_votersgc.ttlsecondsconstraintsvoter_constraintslease_preferences"
+const _Field_name = "range_min_bytesrange_max_bytesglobal_readsnum_replicasnum_votersgc.ttlsecondsconstraintsvoter_constraintslease_preferencesrange_max_write_requests_per_secondrange_max_write_bytes_per_secondnum_witnesses"
 
-var _Field_index = [...]uint8{0, 15, 30, 42, 54, 64, 77, 88, 105, 122}
+var _Field_index = [...]uint8{0, 15, 30, 42, 54, 64, 77, 88, 105, 122, 157, 189, 202}
 
 func (i Field) String() string {
 	i -= 1
@@ -387,6 +387,21 @@ func (z *ZoneConfig) Validate() error {
 		}
 	}
 
+	if z.NumWitnesses != nil {
+		if *z.NumWitnesses < 0 {
+			return fmt.Errorf("num_witnesses cannot be negative")
+		}
+		// A quorum of voters must always contain a replica holding the data, so
+		// witnesses must be a minority of the voters.
+		numVoters := z.NumReplicas
+		if numVotersExplicit {
+			numVoters = z.NumVoters
+		}
+		if *z.NumWitnesses > 0 && numVoters != nil && 2**z.NumWitnesses >= *numVoters {
+			return fmt.Errorf("num_witnesses must be less than half of the voting replicas")
+		}
+	}
+
 	if z.RangeMaxBytes != nil && *z.RangeMaxBytes < minRangeMaxBytes {
 		return fmt.Errorf("RangeMaxBytes %d less than minimum allowed %d",
 			*z.RangeMaxBytes, minRangeMaxBytes)
@@ -400,6 +415,15 @@ func (z *ZoneConfig) Validate() error {
 			*z.RangeMinBytes, *z.RangeMaxBytes)
 	}
 
+	if z.RangeMaxWriteRequestsPerSecond != nil && *z.RangeMaxWriteRequestsPerSecond < 0 {
+		return fmt.Errorf("RangeMaxWriteRequestsPerSecond %d less than minimum allowed 0",
+			*z.RangeMaxWriteRequestsPerSecond)
+	}
+	if z.RangeMaxWriteBytesPerSecond != nil && *z.RangeMaxWriteBytesPerSecond < 0 {
+		return fmt.Errorf("RangeMaxWriteBytesPerSecond %d less than minimum allowed 0",
+			*z.RangeMaxWriteBytesPerSecond)
+	}
+
 	// Reserve the value 0 to potentially have some special meaning in the future,
 	// such as to disable GC.
 	if z.GC != nil && z.GC.TTLSeconds < 1 {
@@ -553,6 +577,11 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.NumVoters = proto.Int32(*parent.NumVoters)
 		}
 	}
+	if z.NumWitnesses == nil {
+		if parent.NumWitnesses != nil {
+			z.NumWitnesses = proto.Int32(*parent.NumWitnesses)
+		}
+	}
 	if z.GlobalReads == nil {
 		if parent.GlobalReads != nil {
 			z.GlobalReads = proto.Bool(*parent.GlobalReads)
@@ -568,6 +597,16 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.RangeMaxBytes = proto.Int64(*parent.RangeMaxBytes)
 		}
 	}
+	if z.RangeMaxWriteRequestsPerSecond == nil {
+		if parent.RangeMaxWriteRequestsPerSecond != nil {
+			z.RangeMaxWriteRequestsPerSecond = proto.Int64(*parent.RangeMaxWriteRequestsPerSecond)
+		}
+	}
+	if z.RangeMaxWriteBytesPerSecond == nil {
+		if parent.RangeMaxWriteBytesPerSecond != nil {
+			z.RangeMaxWriteBytesPerSecond = proto.Int64(*parent.RangeMaxWriteBytesPerSecond)
+		}
+	}
 
 	if z.ShouldInheritGC(parent) {
 		tempGC := *parent.GC
@@ -602,6 +641,11 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			if other.NumVoters != nil {
 				z.NumVoters = proto.Int32(*other.NumVoters)
 			}
+		case "num_witnesses":
+			z.NumWitnesses = nil
+			if other.NumWitnesses != nil {
+				z.NumWitnesses = proto.Int32(*other.NumWitnesses)
+			}
 		case "range_min_bytes":
 			z.RangeMinBytes = nil
 			if other.RangeMinBytes != nil {
@@ -617,6 +661,16 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			if other.GlobalReads != nil {
 				z.GlobalReads = proto.Bool(*other.GlobalReads)
 			}
+		case "range_max_write_requests_per_second":
+			z.RangeMaxWriteRequestsPerSecond = nil
+			if other.RangeMaxWriteRequestsPerSecond != nil {
+				z.RangeMaxWriteRequestsPerSecond = proto.Int64(*other.RangeMaxWriteRequestsPerSecond)
+			}
+		case "range_max_write_bytes_per_second":
+			z.RangeMaxWriteBytesPerSecond = nil
+			if other.RangeMaxWriteBytesPerSecond != nil {
+				z.RangeMaxWriteBytesPerSecond = proto.Int64(*other.RangeMaxWriteBytesPerSecond)
+			}
 		case "gc.ttlseconds":
 			z.GC = nil
 			if other.GC != nil {
@@ -691,6 +745,16 @@ func (z *ZoneConfig) DiffWithZone(
 					Field: "num_voters",
 				}, nil
 			}
+		case "num_witnesses":
+			if other.NumWitnesses == nil && z.NumWitnesses == nil {
+				continue
+			}
+			if z.NumWitnesses == nil || other.NumWitnesses == nil ||
+				*z.NumWitnesses != *other.NumWitnesses {
+				return false, DiffWithZoneMismatch{
+					Field: "num_witnesses",
+				}, nil
+			}
 		case "range_min_bytes":
 			if other.RangeMinBytes == nil && z.RangeMinBytes == nil {
 				continue
@@ -721,6 +785,26 @@ func (z *ZoneConfig) DiffWithZone(
 					Field: "global_reads",
 				}, nil
 			}
+		case "range_max_write_requests_per_second":
+			if other.RangeMaxWriteRequestsPerSecond == nil && z.RangeMaxWriteRequestsPerSecond == nil {
+				continue
+			}
+			if z.RangeMaxWriteRequestsPerSecond == nil || other.RangeMaxWriteRequestsPerSecond == nil ||
+				*z.RangeMaxWriteRequestsPerSecond != *other.RangeMaxWriteRequestsPerSecond {
+				return false, DiffWithZoneMismatch{
+					Field: "range_max_write_requests_per_second",
+				}, nil
+			}
+		case "range_max_write_bytes_per_second":
+			if other.RangeMaxWriteBytesPerSecond == nil && z.RangeMaxWriteBytesPerSecond == nil {
+				continue
+			}
+			if z.RangeMaxWriteBytesPerSecond == nil || other.RangeMaxWriteBytesPerSecond == nil ||
+				*z.RangeMaxWriteBytesPerSecond != *other.RangeMaxWriteBytesPerSecond {
+				return false, DiffWithZoneMismatch{
+					Field: "range_max_write_bytes_per_second",
+				}, nil
+			}
 		case "gc.ttlseconds":
 			if other.GC == nil && z.GC == nil {
 				continue
@@ -1132,6 +1216,16 @@ func (z *ZoneConfig) toSpanConfig() (roachpb.SpanConfig, error) {
 	if z.NumVoters != nil {
 		sc.NumVoters = *z.NumVoters
 	}
+	if z.NumWitnesses != nil {
+		sc.NumWitnesses = *z.NumWitnesses
+	}
+	// Writes are not rate limited by default.
+	if z.RangeMaxWriteRequestsPerSecond != nil {
+		sc.RangeMaxWriteRequestsPerSecond = *z.RangeMaxWriteRequestsPerSecond
+	}
+	if z.RangeMaxWriteBytesPerSecond != nil {
+		sc.RangeMaxWriteBytesPerSecond = *z.RangeMaxWriteBytesPerSecond
+	}
 
 	toSpanConfigConstraints := func(src []Constraint) ([]roachpb.Constraint, error) {
 		spanConfigConstraints := make([]roachpb.Constraint, len(src))
@@ -195,22 +195,27 @@ func (c *ConstraintsList) UnmarshalYAML(unmarshal func(interface{}) error) error
 // ConstraintsList for backwards-compatible yaml marshaling and unmarshaling.
 // We also support parsing both lease_preferences (for v2.1+) and
 // experimental_lease_preferences (for v2.0), copying both into the same proto
-// field as needed.
+// field as needed. The number of witnesses and the write rate limits are
+// omitted when unset so that the yaml of zones which don't use them is
+// unchanged.
 //
 // TODO(a-robinson,v2.2): Remove the experimental_lease_preferences field.
 type marshalableZoneConfig struct {
-	RangeMinBytes                *int64            `json:"range_min_bytes" yaml:"range_min_bytes"`
-	RangeMaxBytes                *int64            `json:"range_max_bytes" yaml:"range_max_bytes"`
-	GC                           *GCPolicy         `json:"gc"`
-	GlobalReads                  *bool             `json:"global_reads" yaml:"global_reads"`
-	NumReplicas                  *int32            `json:"num_replicas" yaml:"num_replicas"`
-	NumVoters                    *int32            `json:"num_voters" yaml:"num_voters"`
-	Constraints                  ConstraintsList   `json:"constraints" yaml:"constraints,flow"`
-	VoterConstraints             ConstraintsList   `json:"voter_constraints" yaml:"voter_constraints,flow"`
-	LeasePreferences             []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
-	ExperimentalLeasePreferences []LeasePreference `json:"experimental_lease_preferences" yaml:"experimental_lease_preferences,flow,omitempty"`
-	Subzones                     []Subzone         `json:"subzones" yaml:"-"`
-	SubzoneSpans                 []SubzoneSpan     `json:"subzone_spans" yaml:"-"`
+	RangeMinBytes                  *int64            `json:"range_min_bytes" yaml:"range_min_bytes"`
+	RangeMaxBytes                  *int64            `json:"range_max_bytes" yaml:"range_max_bytes"`
+	GC                             *GCPolicy         `json:"gc"`
+	GlobalReads                    *bool             `json:"global_reads" yaml:"global_reads"`
+	NumReplicas                    *int32            `json:"num_replicas" yaml:"num_replicas"`
+	NumVoters                      *int32            `json:"num_voters" yaml:"num_voters"`
+	NumWitnesses                   *int32            `json:"num_witnesses,omitempty" yaml:"num_witnesses,omitempty"`
+	RangeMaxWriteRequestsPerSecond *int64            `json:"range_max_write_requests_per_second,omitempty" yaml:"range_max_write_requests_per_second,omitempty"`
+	RangeMaxWriteBytesPerSecond    *int64            `json:"range_max_write_bytes_per_second,omitempty" yaml:"range_max_write_bytes_per_second,omitempty"`
+	Constraints                    ConstraintsList   `json:"constraints" yaml:"constraints,flow"`
+	VoterConstraints               ConstraintsList   `json:"voter_constraints" yaml:"voter_constraints,flow"`
+	LeasePreferences               []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
+	ExperimentalLeasePreferences   []LeasePreference `json:"experimental_lease_preferences" yaml:"experimental_lease_preferences,flow,omitempty"`
+	Subzones                       []Subzone         `json:"subzones" yaml:"-"`
+	SubzoneSpans                   []SubzoneSpan     `json:"subzone_spans" yaml:"-"`
 }
 
 func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
@@ -235,6 +240,15 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 	if c.NumVoters != nil && *c.NumVoters != 0 {
 		m.NumVoters = proto.Int32(*c.NumVoters)
 	}
+	if c.NumWitnesses != nil {
+		m.NumWitnesses = proto.Int32(*c.NumWitnesses)
+	}
+	if c.RangeMaxWriteRequestsPerSecond != nil {
+		m.RangeMaxWriteRequestsPerSecond = proto.Int64(*c.RangeMaxWriteRequestsPerSecond)
+	}
+	if c.RangeMaxWriteBytesPerSecond != nil {
+		m.RangeMaxWriteBytesPerSecond = proto.Int64(*c.RangeMaxWriteBytesPerSecond)
+	}
 	// NB: In order to preserve round-trippability, we're directly using
 	// `NullVoterConstraintsIsEmpty` as opposed to calling
 	// `c.InheritedVoterConstraints()`. This is copacetic as long as the value is
@@ -275,6 +289,15 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 	if m.NumVoters != nil {
 		c.NumVoters = proto.Int32(*m.NumVoters)
 	}
+	if m.NumWitnesses != nil {
+		c.NumWitnesses = proto.Int32(*m.NumWitnesses)
+	}
+	if m.RangeMaxWriteRequestsPerSecond != nil {
+		c.RangeMaxWriteRequestsPerSecond = proto.Int64(*m.RangeMaxWriteRequestsPerSecond)
+	}
+	if m.RangeMaxWriteBytesPerSecond != nil {
+		c.RangeMaxWriteBytesPerSecond = proto.Int64(*m.RangeMaxWriteBytesPerSecond)
+	}
 	c.VoterConstraints = m.VoterConstraints.Constraints
 	c.NullVoterConstraintsIsEmpty = !m.VoterConstraints.Inherited
 	if m.LeasePreferences != nil {
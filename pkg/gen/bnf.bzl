@@ -7,6 +7,7 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:alter_backup.bnf",
     "//docs/generated/sql/bnf:alter_backup_schedule.bnf",
     "//docs/generated/sql/bnf:alter_changefeed.bnf",
+    "//docs/generated/sql/bnf:alter_changefeed_backfill.bnf",
     "//docs/generated/sql/bnf:alter_column.bnf",
     "//docs/generated/sql/bnf:alter_database.bnf",
     "//docs/generated/sql/bnf:alter_database_add_region_stmt.bnf",
@@ -56,6 +57,8 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:alter_table_reset_storage_param.bnf",
     "//docs/generated/sql/bnf:alter_table_set_schema_stmt.bnf",
     "//docs/generated/sql/bnf:alter_table_set_storage_param.bnf",
+    "//docs/generated/sql/bnf:alter_tenant_replication_stmt.bnf",
+    "//docs/generated/sql/bnf:alter_tenant_stmt.bnf",
     "//docs/generated/sql/bnf:alter_type.bnf",
     "//docs/generated/sql/bnf:alter_view.bnf",
     "//docs/generated/sql/bnf:alter_view_owner_stmt.bnf",
@@ -81,6 +84,7 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:column_table_def.bnf",
     "//docs/generated/sql/bnf:comment.bnf",
     "//docs/generated/sql/bnf:commit_transaction.bnf",
+    "//docs/generated/sql/bnf:compact_backups_stmt.bnf",
     "//docs/generated/sql/bnf:copy_stmt.bnf",
     "//docs/generated/sql/bnf:copy_to_stmt.bnf",
     "//docs/generated/sql/bnf:create_as_col_qual_list.bnf",
@@ -94,6 +98,7 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:create_index_stmt.bnf",
     "//docs/generated/sql/bnf:create_index_with_storage_param.bnf",
     "//docs/generated/sql/bnf:create_inverted_index_stmt.bnf",
+    "//docs/generated/sql/bnf:create_logical_replication_stream_stmt.bnf",
     "//docs/generated/sql/bnf:create_role_stmt.bnf",
     "//docs/generated/sql/bnf:create_schedule_for_backup_stmt.bnf",
     "//docs/generated/sql/bnf:create_schedule_for_changefeed_stmt.bnf",
@@ -134,6 +139,7 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:explain_analyze_stmt.bnf",
     "//docs/generated/sql/bnf:explain_stmt.bnf",
     "//docs/generated/sql/bnf:explainable_stmt.bnf",
+    "//docs/generated/sql/bnf:export_into_iceberg.bnf",
     "//docs/generated/sql/bnf:export_stmt.bnf",
     "//docs/generated/sql/bnf:family_def.bnf",
     "//docs/generated/sql/bnf:fetch_cursor_stmt.bnf",
@@ -212,6 +218,7 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:set_transaction.bnf",
     "//docs/generated/sql/bnf:set_transaction_stmt.bnf",
     "//docs/generated/sql/bnf:show_backup.bnf",
+    "//docs/generated/sql/bnf:show_backup_rows.bnf",
     "//docs/generated/sql/bnf:show_cluster_setting.bnf",
     "//docs/generated/sql/bnf:show_columns_stmt.bnf",
     "//docs/generated/sql/bnf:show_commit_timestamp_stmt.bnf",
@@ -278,6 +285,7 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:use_stmt.bnf",
     "//docs/generated/sql/bnf:validate_constraint.bnf",
     "//docs/generated/sql/bnf:values_clause.bnf",
+    "//docs/generated/sql/bnf:verify_backup_stmt.bnf",
     "//docs/generated/sql/bnf:window_definition.bnf",
     "//docs/generated/sql/bnf:with_clause.bnf",
 ]
@@ -8,6 +8,7 @@ DIAGRAMS_SRCS = [
     "//docs/generated/sql/bnf:alter_backup.html",
     "//docs/generated/sql/bnf:alter_backup_schedule.html",
     "//docs/generated/sql/bnf:alter_changefeed.html",
+    "//docs/generated/sql/bnf:alter_changefeed_backfill.html",
     "//docs/generated/sql/bnf:alter_column.html",
     "//docs/generated/sql/bnf:alter_database.html",
     "//docs/generated/sql/bnf:alter_database_add_region.html",
@@ -56,6 +57,8 @@ DIAGRAMS_SRCS = [
     "//docs/generated/sql/bnf:alter_table_reset_storage_param.html",
     "//docs/generated/sql/bnf:alter_table_set_schema.html",
     "//docs/generated/sql/bnf:alter_table_set_storage_param.html",
+    "//docs/generated/sql/bnf:alter_tenant.html",
+    "//docs/generated/sql/bnf:alter_tenant_replication.html",
     "//docs/generated/sql/bnf:alter_type.html",
     "//docs/generated/sql/bnf:alter_view.html",
     "//docs/generated/sql/bnf:alter_view_owner.html",
@@ -81,6 +84,7 @@ DIAGRAMS_SRCS = [
     "//docs/generated/sql/bnf:column_table_def.html",
     "//docs/generated/sql/bnf:comment.html",
     "//docs/generated/sql/bnf:commit_transaction.html",
+    "//docs/generated/sql/bnf:compact_backups.html",
     "//docs/generated/sql/bnf:copy.html",
     "//docs/generated/sql/bnf:copy_to.html",
     "//docs/generated/sql/bnf:create.html",
@@ -95,6 +99,7 @@ DIAGRAMS_SRCS = [
     "//docs/generated/sql/bnf:create_index.html",
     "//docs/generated/sql/bnf:create_index_with_storage_param.html",
     "//docs/generated/sql/bnf:create_inverted_index.html",
+    "//docs/generated/sql/bnf:create_logical_replication_stream.html",
     "//docs/generated/sql/bnf:create_role.html",
     "//docs/generated/sql/bnf:create_schedule.html",
     "//docs/generated/sql/bnf:create_schedule_for_backup.html",
@@ -135,6 +140,7 @@ DIAGRAMS_SRCS = [
     "//docs/generated/sql/bnf:explain_analyze.html",
     "//docs/generated/sql/bnf:explainable.html",
     "//docs/generated/sql/bnf:export.html",
+    "//docs/generated/sql/bnf:export_into_iceberg.html",
     "//docs/generated/sql/bnf:family_def.html",
     "//docs/generated/sql/bnf:fetch_cursor.html",
     "//docs/generated/sql/bnf:for_locking.html",
@@ -211,6 +217,7 @@ DIAGRAMS_SRCS = [
     "//docs/generated/sql/bnf:set_session.html",
     "//docs/generated/sql/bnf:set_transaction.html",
     "//docs/generated/sql/bnf:show_backup.html",
+    "//docs/generated/sql/bnf:show_backup_rows.html",
     "//docs/generated/sql/bnf:show_cluster_setting.html",
     "//docs/generated/sql/bnf:show_columns.html",
     "//docs/generated/sql/bnf:show_commit_timestamp.html",
@@ -276,6 +283,7 @@ DIAGRAMS_SRCS = [
     "//docs/generated/sql/bnf:use.html",
     "//docs/generated/sql/bnf:validate_constraint.html",
     "//docs/generated/sql/bnf:values_clause.html",
+    "//docs/generated/sql/bnf:verify_backup.html",
     "//docs/generated/sql/bnf:window_definition.html",
     "//docs/generated/sql/bnf:with_clause.html",
 ]
@@ -51,6 +51,7 @@ var (
 	_ Details = AutoConfigRunnerDetails{}
 	_ Details = AutoConfigEnvRunnerDetails{}
 	_ Details = AutoConfigTaskDetails{}
+	_ Details = LogicalReplicationDetails{}
 )
 
 // ProgressDetails is a marker interface for job progress details proto structs.
@@ -74,6 +75,7 @@ var (
 	_ ProgressDetails = AutoConfigRunnerProgress{}
 	_ ProgressDetails = AutoConfigEnvRunnerProgress{}
 	_ ProgressDetails = AutoConfigTaskProgress{}
+	_ ProgressDetails = LogicalReplicationProgress{}
 )
 
 // Type returns the payload's job type and panics if the type is invalid.
@@ -207,6 +209,8 @@ func DetailsType(d isPayload_Details) (Type, error) {
 		return TypeAutoConfigEnvRunner, nil
 	case *Payload_AutoConfigTask:
 		return TypeAutoConfigTask, nil
+	case *Payload_LogicalReplication:
+		return TypeLogicalReplication, nil
 	default:
 		return TypeUnspecified, errors.Newf("Payload.Type called on a payload with an unknown details type: %T", d)
 	}
@@ -250,6 +254,7 @@ var JobDetailsForEveryJobType = map[Type]Details{
 	TypeAutoConfigRunner:             AutoConfigRunnerDetails{},
 	TypeAutoConfigEnvRunner:          AutoConfigEnvRunnerDetails{},
 	TypeAutoConfigTask:               AutoConfigTaskDetails{},
+	TypeLogicalReplication:           LogicalReplicationDetails{},
 }
 
 // WrapProgressDetails wraps a ProgressDetails object in the protobuf wrapper
@@ -303,6 +308,8 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_AutoConfigEnvRunner{AutoConfigEnvRunner: &d}
 	case AutoConfigTaskProgress:
 		return &Progress_AutoConfigTask{AutoConfigTask: &d}
+	case LogicalReplicationProgress:
+		return &Progress_LogicalReplication{LogicalReplication: &d}
 	default:
 		panic(errors.AssertionFailedf("WrapProgressDetails: unknown progress type %T", d))
 	}
@@ -354,6 +361,8 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.AutoConfigEnvRunner
 	case *Payload_AutoConfigTask:
 		return *d.AutoConfigTask
+	case *Payload_LogicalReplication:
+		return *d.LogicalReplication
 	default:
 		return nil
 	}
@@ -405,6 +414,8 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.AutoConfigEnvRunner
 	case *Progress_AutoConfigTask:
 		return *d.AutoConfigTask
+	case *Progress_LogicalReplication:
+		return *d.LogicalReplication
 	default:
 		return nil
 	}
@@ -480,6 +491,8 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_AutoConfigEnvRunner{AutoConfigEnvRunner: &d}
 	case AutoConfigTaskDetails:
 		return &Payload_AutoConfigTask{AutoConfigTask: &d}
+	case LogicalReplicationDetails:
+		return &Payload_LogicalReplication{LogicalReplication: &d}
 	default:
 		panic(errors.AssertionFailedf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -515,7 +528,7 @@ const (
 func (Type) SafeValue() {}
 
 // NumJobTypes is the number of jobs types.
-const NumJobTypes = 23
+const NumJobTypes = 24
 
 // ChangefeedDetailsMarshaler allows for dependency injection of
 // cloud.SanitizeExternalStorageURI to avoid the dependency from this
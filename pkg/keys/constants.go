@@ -125,6 +125,10 @@ var (
 	// RaftTruncatedState.
 	LocalRaftTruncatedStateSuffix = []byte("rftt")
 
+	// LocalRangeQuarantinedSuffix is the suffix for the timestamp at which the
+	// replica was quarantined by the store scrubber, after keys overlapping the
+	// replica were found in a corrupted SSTable.
+	LocalRangeQuarantinedSuffix = []byte("rlqt")
 	// LocalRangeLastReplicaGCTimestampSuffix is the suffix for a range's last
 	// replica GC timestamp (for GC of old replicas).
 	LocalRangeLastReplicaGCTimestampSuffix = []byte("rlrt")
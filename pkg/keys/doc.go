@@ -197,6 +197,7 @@ var _ = [...]interface{}{
 	RaftLogKey,                     // "rftl"
 	RaftReplicaIDKey,               // "rftr"
 	RaftTruncatedStateKey,          // "rftt"
+	RangeQuarantinedKey,            // "rlqt"
 	RangeLastReplicaGCTimestampKey, // "rlrt"
 
 	//   3. Range local keys: These also store metadata that pertains to a range
@@ -378,6 +378,12 @@ func RaftReplicaIDKey(rangeID roachpb.RangeID) roachpb.Key {
 	return MakeRangeIDPrefixBuf(rangeID).RaftReplicaIDKey()
 }
 
+// RangeQuarantinedKey returns a range-local key for the timestamp at which
+// the replica was quarantined.
+func RangeQuarantinedKey(rangeID roachpb.RangeID) roachpb.Key {
+	return MakeRangeIDPrefixBuf(rangeID).RangeQuarantinedKey()
+}
+
 // RangeLastReplicaGCTimestampKey returns a range-local key for
 // the range's last replica GC timestamp.
 func RangeLastReplicaGCTimestampKey(rangeID roachpb.RangeID) roachpb.Key {
@@ -1101,6 +1107,12 @@ func (b RangeIDPrefixBuf) RaftReplicaIDKey() roachpb.Key {
 	return append(b.unreplicatedPrefix(), LocalRaftReplicaIDSuffix...)
 }
 
+// RangeQuarantinedKey returns a range-local key for the timestamp at which
+// the replica was quarantined.
+func (b RangeIDPrefixBuf) RangeQuarantinedKey() roachpb.Key {
+	return append(b.unreplicatedPrefix(), LocalRangeQuarantinedSuffix...)
+}
+
 // RangeLastReplicaGCTimestampKey returns a range-local key for
 // the range's last replica GC timestamp.
 func (b RangeIDPrefixBuf) RangeLastReplicaGCTimestampKey() roachpb.Key {
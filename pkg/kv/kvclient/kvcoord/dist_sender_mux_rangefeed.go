@@ -219,7 +219,7 @@ func (m *rangefeedMuxer) startSingleRangeFeed(
 		releaseTransport = transport.Release
 
 		for !transport.IsExhausted() {
-			args := makeRangeFeedRequest(span, token.Desc().RangeID, m.cfg.overSystemTable, startAfter, m.cfg.withDiff)
+			args := makeRangeFeedRequest(span, token.Desc().RangeID, m.cfg.overSystemTable, startAfter, m.cfg.withDiff, m.cfg.filter)
 			args.Replica = transport.NextReplica()
 			args.StreamID = streamID
 
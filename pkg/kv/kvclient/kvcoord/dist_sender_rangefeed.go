@@ -91,6 +91,7 @@ type rangeFeedConfig struct {
 	useMuxRangeFeed bool
 	overSystemTable bool
 	withDiff        bool
+	filter          *kvpb.RangeFeedFilter
 }
 
 // RangeFeedOption configures a RangeFeed.
@@ -124,6 +125,15 @@ func WithDiff() RangeFeedOption {
 	})
 }
 
+// WithFilter pushes the given filter down into the server-side rangefeed
+// registrations. Filtering is best-effort: servers that don't support it send
+// every event, so callers must still filter the events they receive.
+func WithFilter(f *kvpb.RangeFeedFilter) RangeFeedOption {
+	return optionFunc(func(c *rangeFeedConfig) {
+		c.filter = f
+	})
+}
+
 // A "kill switch" to disable multiplexing rangefeed if severe issues discovered with new implementation.
 var enableMuxRangeFeed = envutil.EnvOrDefaultBool("COCKROACH_ENABLE_MULTIPLEXING_RANGEFEED", true)
 
@@ -602,7 +612,7 @@ type onRangeEventCb func(nodeID roachpb.NodeID, rangeID roachpb.RangeID, event *
 
 // makeRangeFeedRequest constructs kvpb.RangeFeedRequest for specified span and
 // rangeID. Request is constructed to watch event after specified timestamp, and
-// with optional diff.  If the request corresponds to a system range, request
+// with optional diff and filter.  If the request corresponds to a system range, request
 // receives higher admission priority.
 func makeRangeFeedRequest(
 	span roachpb.Span,
@@ -610,6 +620,7 @@ func makeRangeFeedRequest(
 	isSystemRange bool,
 	startAfter hlc.Timestamp,
 	withDiff bool,
+	filter *kvpb.RangeFeedFilter,
 ) kvpb.RangeFeedRequest {
 	admissionPri := admissionpb.BulkNormalPri
 	if isSystemRange {
@@ -622,6 +633,7 @@ func makeRangeFeedRequest(
 			RangeID:   rangeID,
 		},
 		WithDiff: withDiff,
+		Filter:   filter,
 		AdmissionHeader: kvpb.AdmissionHeader{
 			// NB: AdmissionHeader is used only at the start of the range feed
 			// stream since the initial catch-up scan is expensive.
@@ -675,7 +687,7 @@ func (ds *DistSender) singleRangeFeed(
 		cancelFeed()
 	}()
 
-	args := makeRangeFeedRequest(span, desc.RangeID, cfg.overSystemTable, startAfter, cfg.withDiff)
+	args := makeRangeFeedRequest(span, desc.RangeID, cfg.overSystemTable, startAfter, cfg.withDiff, cfg.filter)
 	transport, err := newTransportForRange(ctx, desc, ds)
 	if err != nil {
 		return args.Timestamp, err
@@ -128,14 +128,30 @@ func IsLocking(args Request) bool {
 }
 
 // LockingDurability returns the durability of the locks acquired by the
-// request. The function assumes that IsLocking(args).
+// request. Locking reads acquire locks with their configured key-level locking
+// durability while all other locking requests write replicated intents. The
+// function assumes that IsLocking(args).
 func LockingDurability(args Request) lock.Durability {
+	if lr, ok := args.(LockingReadRequest); ok {
+		return lr.KeyLockingDurability()
+	}
 	if IsReadOnly(args) {
 		return lock.Unreplicated
 	}
 	return lock.Replicated
 }
 
+// LockingStrength returns the strength of the locks acquired by the request.
+// Locking reads acquire locks with their configured key-level locking
+// strength while all other locking requests write intents. The function
+// assumes that IsLocking(args).
+func LockingStrength(args Request) lock.Strength {
+	if lr, ok := args.(LockingReadRequest); ok {
+		return lr.KeyLockingStrength()
+	}
+	return lock.Intent
+}
+
 // IsIntentWrite returns true if the request produces write intents at
 // the request's sequence number when used within a transaction.
 func IsIntentWrite(args Request) bool {
@@ -210,10 +226,11 @@ type Request interface {
 }
 
 // LockingReadRequest is an interface used to expose the key-level locking
-// strength of a read-only request.
+// strength and durability of a read request.
 type LockingReadRequest interface {
 	Request
 	KeyLockingStrength() lock.Strength
+	KeyLockingDurability() lock.Durability
 }
 
 var _ LockingReadRequest = (*GetRequest)(nil)
@@ -223,6 +240,11 @@ func (gr *GetRequest) KeyLockingStrength() lock.Strength {
 	return gr.KeyLocking
 }
 
+// KeyLockingDurability implements the LockingReadRequest interface.
+func (gr *GetRequest) KeyLockingDurability() lock.Durability {
+	return gr.KeyLockingDurability
+}
+
 var _ LockingReadRequest = (*ScanRequest)(nil)
 
 // KeyLockingStrength implements the LockingReadRequest interface.
@@ -230,6 +252,11 @@ func (sr *ScanRequest) KeyLockingStrength() lock.Strength {
 	return sr.KeyLocking
 }
 
+// KeyLockingDurability implements the LockingReadRequest interface.
+func (sr *ScanRequest) KeyLockingDurability() lock.Durability {
+	return sr.KeyLockingDurability
+}
+
 var _ LockingReadRequest = (*ReverseScanRequest)(nil)
 
 // KeyLockingStrength implements the LockingReadRequest interface.
@@ -237,6 +264,11 @@ func (rsr *ReverseScanRequest) KeyLockingStrength() lock.Strength {
 	return rsr.KeyLocking
 }
 
+// KeyLockingDurability implements the LockingReadRequest interface.
+func (rsr *ReverseScanRequest) KeyLockingDurability() lock.Durability {
+	return rsr.KeyLockingDurability
+}
+
 // SizedWriteRequest is an interface used to expose the number of bytes a
 // request might write.
 type SizedWriteRequest interface {
@@ -1329,8 +1361,19 @@ func flagForLockStrength(l lock.Strength) flag {
 	return 0
 }
 
+// flagForLockDurability returns the flags of a locking read that acquires
+// locks with the given durability. Replicated locks are written to the range's
+// lock table keyspace, so the request must be evaluated as a write.
+func flagForLockDurability(l lock.Strength, d lock.Durability) flag {
+	if l != lock.None && d == lock.Replicated {
+		return isWrite
+	}
+	return 0
+}
+
 func (gr *GetRequest) flags() flag {
-	maybeLocking := flagForLockStrength(gr.KeyLocking)
+	maybeLocking := flagForLockStrength(gr.KeyLocking) |
+		flagForLockDurability(gr.KeyLocking, gr.KeyLockingDurability)
 	return isRead | isTxn | maybeLocking | updatesTSCache | needsRefresh | canSkipLocked
 }
 
@@ -1420,12 +1463,14 @@ func (*RevertRangeRequest) flags() flag {
 }
 
 func (sr *ScanRequest) flags() flag {
-	maybeLocking := flagForLockStrength(sr.KeyLocking)
+	maybeLocking := flagForLockStrength(sr.KeyLocking) |
+		flagForLockDurability(sr.KeyLocking, sr.KeyLockingDurability)
 	return isRead | isRange | isTxn | maybeLocking | updatesTSCache | needsRefresh | canSkipLocked
 }
 
 func (rsr *ReverseScanRequest) flags() flag {
-	maybeLocking := flagForLockStrength(rsr.KeyLocking)
+	maybeLocking := flagForLockStrength(rsr.KeyLocking) |
+		flagForLockDurability(rsr.KeyLocking, rsr.KeyLockingDurability)
 	return isRead | isRange | isReverse | isTxn | maybeLocking | updatesTSCache | needsRefresh | canSkipLocked
 }
 
@@ -1638,6 +1683,18 @@ func ReplicationChangesForDemotion(target roachpb.ReplicationTarget) []Replicati
 	}
 }
 
+// ReplicationChangesForWitnessPromotion returns the replication changes that
+// correspond to replacing a witness with a full voter on another store. The
+// voter is added as a learner first, and then swapped with the witness in a
+// joint configuration.
+func ReplicationChangesForWitnessPromotion(
+	witness, target roachpb.ReplicationTarget,
+) []ReplicationChange {
+	return []ReplicationChange{
+		{ChangeType: roachpb.ADD_VOTER, Target: target}, {ChangeType: roachpb.REMOVE_WITNESS, Target: witness},
+	}
+}
+
 // AddChanges adds a batch of changes to the request in a backwards-compatible
 // way.
 func (acrr *AdminChangeReplicasRequest) AddChanges(chgs ...ReplicationChange) {
@@ -1684,6 +1741,18 @@ func (rc ReplicationChanges) NonVoterRemovals() []roachpb.ReplicationTarget {
 	return rc.byType(roachpb.REMOVE_NON_VOTER)
 }
 
+// WitnessAdditions returns a slice of all contained replication changes
+// that add witnesses.
+func (rc ReplicationChanges) WitnessAdditions() []roachpb.ReplicationTarget {
+	return rc.byType(roachpb.ADD_WITNESS)
+}
+
+// WitnessRemovals returns a slice of all contained replication changes
+// that remove witnesses.
+func (rc ReplicationChanges) WitnessRemovals() []roachpb.ReplicationTarget {
+	return rc.byType(roachpb.REMOVE_WITNESS)
+}
+
 // Changes returns the changes requested by this AdminChangeReplicasRequest, taking
 // the deprecated method of doing so into account.
 func (acrr *AdminChangeReplicasRequest) Changes() []ReplicationChange {
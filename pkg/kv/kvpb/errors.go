@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/caller"
@@ -150,12 +151,12 @@ func ErrPriority(err error) ErrorPriority {
 			return ErrorScoreTxnAbort
 		}
 		return ErrorScoreTxnRestart
-	case *ConditionFailedError, *WriteIntentError:
+	case *ConditionFailedError, *WriteIntentError, *WriteRateLimitExceededError:
 		// We particularly care about returning the low ErrorScoreUnambiguousError
 		// because we don't want to transition a transaction that encounters a
-		// ConditionFailedError or a WriteIntentError to an error state. More
-		// specifically, we want to allow rollbacks to savepoint after one of these
-		// errors.
+		// ConditionFailedError, a WriteIntentError or a
+		// WriteRateLimitExceededError to an error state. More specifically, we
+		// want to allow rollbacks to savepoint after one of these errors.
 		return ErrorScoreUnambiguousError
 	}
 	return ErrorScoreNonRetriable
@@ -295,6 +296,7 @@ const (
 	MinTimestampBoundUnsatisfiableErrType   ErrorDetailType = 42
 	RefreshFailedErrType                    ErrorDetailType = 43
 	MVCCHistoryMutationErrType              ErrorDetailType = 44
+	WriteRateLimitExceededErrType           ErrorDetailType = 45
 	// When adding new error types, don't forget to update NumErrors below.
 
 	// CommunicationErrType indicates a gRPC error; this is not an ErrorDetail.
@@ -304,7 +306,7 @@ const (
 	// detail. The value 25 is chosen because it's reserved in the errors proto.
 	InternalErrType ErrorDetailType = 25
 
-	NumErrors int = 45
+	NumErrors int = 46
 )
 
 // Register the migration of all errors that used to be in the roachpb package
@@ -1478,6 +1480,47 @@ func (e *RefreshFailedError) Type() ErrorDetailType {
 
 var _ ErrorDetailInterface = &RefreshFailedError{}
 
+// NewWriteRateLimitExceededError initializes a new WriteRateLimitExceededError.
+func NewWriteRateLimitExceededError(
+	rangeID roachpb.RangeID, limit string, retryAfter time.Duration,
+) *WriteRateLimitExceededError {
+	return &WriteRateLimitExceededError{
+		RangeID:    rangeID,
+		Limit:      limit,
+		RetryAfter: retryAfter,
+	}
+}
+
+func (e *WriteRateLimitExceededError) Error() string {
+	return redact.Sprint(e).StripMarkers()
+}
+
+func (e *WriteRateLimitExceededError) SafeFormatError(p errors.Printer) (next error) {
+	p.Printf("write rate limit exceeded on range r%d: %s exceeded; retry after %s",
+		e.RangeID, redact.SafeString(e.Limit), e.RetryAfter)
+	return nil
+}
+
+// ErrorHint implements the errors.ErrorHinter interface.
+//
+// Note that WriteRateLimitExceededError deliberately does not implement
+// ClientVisibleRetryError: the SQL layer retries those immediately, which would
+// only add load to a range that is already over its limits.
+func (e *WriteRateLimitExceededError) ErrorHint() string {
+	return fmt.Sprintf(`The range is receiving writes faster than allowed by the `+
+		`range_max_write_requests_per_second or range_max_write_bytes_per_second `+
+		`fields of its zone configuration. The rejected writes did not take effect. `+
+		`Retry the transaction after at least %s, reduce the rate of writes to the `+
+		`table, or raise the limits with ALTER ... CONFIGURE ZONE.`, e.RetryAfter)
+}
+
+// Type is part of the ErrorDetailInterface.
+func (e *WriteRateLimitExceededError) Type() ErrorDetailType {
+	return WriteRateLimitExceededErrType
+}
+
+var _ ErrorDetailInterface = &WriteRateLimitExceededError{}
+
 func (e *InsufficientSpaceError) Error() string {
 	return fmt.Sprintf("store %d has insufficient remaining capacity to %s (remaining: %s / %.1f%%, min required: %.1f%%)",
 		e.StoreID, e.Op, humanizeutil.IBytes(e.Available), float64(e.Available)/float64(e.Capacity)*100, e.Required*100)
@@ -1569,4 +1612,5 @@ var _ errors.SafeFormatter = &OptimisticEvalConflictsError{}
 var _ errors.SafeFormatter = &MinTimestampBoundUnsatisfiableError{}
 var _ errors.SafeFormatter = &RefreshFailedError{}
 var _ errors.SafeFormatter = &MVCCHistoryMutationError{}
+var _ errors.SafeFormatter = &WriteRateLimitExceededError{}
 var _ errors.SafeFormatter = &UnhandledRetryableError{}
@@ -323,6 +323,10 @@ func TestErrorRedaction(t *testing.T) {
 			err:    &MVCCHistoryMutationError{},
 			expect: "unexpected MVCC history mutation in span ‹/Min›",
 		},
+		{
+			err:    &WriteRateLimitExceededError{Limit: "range_max_write_requests_per_second"},
+			expect: "write rate limit exceeded on range r0: range_max_write_requests_per_second exceeded; retry after 0s",
+		},
 		{
 			err:    &UnhandledRetryableError{},
 			expect: "{<nil> 0 {<nil>} ‹<nil>› 0,0}",
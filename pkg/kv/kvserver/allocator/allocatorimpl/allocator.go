@@ -19,6 +19,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/load"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/storepool"
@@ -131,6 +132,9 @@ const (
 	AllocatorConsiderRebalance
 	AllocatorRangeUnavailable
 	AllocatorFinalizeAtomicReplicationChange
+	AllocatorAddWitness
+	AllocatorRemoveWitness
+	AllocatorPromoteWitness
 )
 
 // Add indicates an action adding a replica.
@@ -153,7 +157,8 @@ func (a AllocatorAction) Remove() bool {
 		a == AllocatorRemoveDeadVoter ||
 		a == AllocatorRemoveDeadNonVoter ||
 		a == AllocatorRemoveDecommissioningVoter ||
-		a == AllocatorRemoveDecommissioningNonVoter
+		a == AllocatorRemoveDecommissioningNonVoter ||
+		a == AllocatorRemoveWitness
 }
 
 // TargetReplicaType returns that the action is for a voter or non-voter replica.
@@ -164,7 +169,8 @@ func (a AllocatorAction) TargetReplicaType() TargetReplicaType {
 		a == AllocatorReplaceDeadVoter ||
 		a == AllocatorRemoveDeadVoter ||
 		a == AllocatorReplaceDecommissioningVoter ||
-		a == AllocatorRemoveDecommissioningVoter {
+		a == AllocatorRemoveDecommissioningVoter ||
+		a == AllocatorPromoteWitness {
 		t = VoterTarget
 	} else if a == AllocatorRemoveNonVoter ||
 		a == AllocatorAddNonVoter ||
@@ -187,6 +193,7 @@ func (a AllocatorAction) ReplicaStatus() ReplicaStatus {
 		a == AllocatorAddNonVoter {
 		s = Alive
 	} else if a == AllocatorReplaceDeadVoter ||
+		a == AllocatorPromoteWitness ||
 		a == AllocatorReplaceDeadNonVoter ||
 		a == AllocatorRemoveDeadVoter ||
 		a == AllocatorRemoveDeadNonVoter {
@@ -218,6 +225,9 @@ var allocatorActionNames = map[AllocatorAction]string{
 	AllocatorConsiderRebalance:               "consider rebalance",
 	AllocatorRangeUnavailable:                "range unavailable",
 	AllocatorFinalizeAtomicReplicationChange: "finalize conf change",
+	AllocatorAddWitness:                      "add witness",
+	AllocatorRemoveWitness:                   "remove witness",
+	AllocatorPromoteWitness:                  "promote witness",
 }
 
 func (a AllocatorAction) String() string {
@@ -240,6 +250,8 @@ func (a AllocatorAction) Priority() float64 {
 		return 12001
 	case AllocatorReplaceDeadVoter:
 		return 12000
+	case AllocatorPromoteWitness:
+		return 11000
 	case AllocatorAddVoter:
 		return 10000
 	case AllocatorReplaceDecommissioningVoter:
@@ -248,8 +260,12 @@ func (a AllocatorAction) Priority() float64 {
 		return 1000
 	case AllocatorRemoveDecommissioningVoter:
 		return 900
+	case AllocatorAddWitness:
+		return 850
 	case AllocatorRemoveVoter:
 		return 800
+	case AllocatorRemoveWitness:
+		return 750
 	case AllocatorReplaceDeadNonVoter:
 		return 700
 	case AllocatorAddNonVoter:
@@ -656,6 +672,22 @@ func GetNeededVoters(zoneConfigVoterCount int32, clusterNodes int) int {
 	return need
 }
 
+// GetNeededWitnesses calculates the number of witnesses a range should have
+// given its zone config and the number of voting replicas (including
+// witnesses) it needs, as returned by GetNeededVoters. Witnesses must remain a
+// minority of the voting replicas, so that every quorum includes a replica
+// with the range's data.
+func GetNeededWitnesses(zoneConfigWitnessCount int32, neededVoters int) int {
+	need := int(zoneConfigWitnessCount)
+	if maxWitnesses := (neededVoters - 1) / 2; need > maxWitnesses {
+		need = maxWitnesses
+	}
+	if need < 0 {
+		need = 0 // Must be non-negative.
+	}
+	return need
+}
+
 // GetNeededNonVoters calculates the number of non-voters a range should have
 // given the number of voting replicas the range has and the number of nodes
 // available for up-replication.
@@ -916,7 +948,7 @@ func (a *Allocator) ComputeAction(
 	}
 
 	return a.computeAction(ctx, storePool, conf, desc.Replicas().VoterDescriptors(),
-		desc.Replicas().NonVoterDescriptors())
+		desc.Replicas().NonVoterDescriptors(), desc.Replicas().WitnessDescriptors())
 }
 
 func (a *Allocator) computeAction(
@@ -925,6 +957,7 @@ func (a *Allocator) computeAction(
 	conf roachpb.SpanConfig,
 	voterReplicas []roachpb.ReplicaDescriptor,
 	nonVoterReplicas []roachpb.ReplicaDescriptor,
+	witnessReplicas []roachpb.ReplicaDescriptor,
 ) (action AllocatorAction, adjustedPriority float64) {
 	// NB: The ordering of the checks in this method is intentional. The order in
 	// which these actions are returned by this method determines the relative
@@ -938,15 +971,28 @@ func (a *Allocator) computeAction(
 	// first handle operations that correspond to repairing/recovering the range.
 	// After that we handle rebalancing related actions, followed by removal
 	// actions.
+	//
+	// Witnesses are voting replicas which don't hold the range's data. They
+	// count towards the range's quorum, but are otherwise handled separately
+	// from the (full) voters: voterReplicas and neededVoters don't include
+	// them.
 	haveVoters := len(voterReplicas)
+	haveWitnesses := len(witnessReplicas)
 	decommissioningVoters := storePool.DecommissioningReplicas(voterReplicas)
 	postDecommissionVoters := haveVoters - len(decommissioningVoters)
 	// Node count including dead nodes but excluding
 	// decommissioning/decommissioned nodes.
 	clusterNodes := storePool.ClusterNodeCount()
-	neededVoters := GetNeededVoters(conf.GetNumVoters(), clusterNodes)
-	desiredQuorum := computeQuorum(neededVoters)
-	quorum := computeQuorum(haveVoters)
+	neededVotingReplicas := GetNeededVoters(conf.GetNumVoters(), clusterNodes)
+	neededWitnesses := GetNeededWitnesses(conf.NumWitnesses, neededVotingReplicas)
+	if !a.st.Version.IsActive(ctx, clusterversion.V23_2_Witnesses) {
+		// Nodes running older binaries can't handle witnesses, so none are added
+		// until the cluster is upgraded.
+		neededWitnesses = 0
+	}
+	neededVoters := neededVotingReplicas - neededWitnesses
+	desiredQuorum := computeQuorum(neededVotingReplicas)
+	quorum := computeQuorum(haveVoters + haveWitnesses)
 
 	// TODO(aayush): When haveVoters < neededVoters but we don't have quorum to
 	// actually execute the addition of a new replica, we should be returning a
@@ -970,15 +1016,31 @@ func (a *Allocator) computeAction(
 	// elsewhere (for a regular rebalance or for decommissioning).
 	const includeSuspectAndDrainingStores = true
 	liveVoters, deadVoters := storePool.LiveAndDeadReplicas(voterReplicas, includeSuspectAndDrainingStores)
+	liveWitnesses, deadWitnesses := storePool.LiveAndDeadReplicas(witnessReplicas, includeSuspectAndDrainingStores)
 
-	if len(liveVoters) < quorum {
+	if len(liveVoters)+len(liveWitnesses) < quorum {
 		// Do not take any replacement/removal action if we do not have a quorum of
 		// live voters. If we're correctly assessing the unavailable state of the
 		// range, we also won't be able to add replicas as we try above, but hope
 		// springs eternal.
 		action = AllocatorRangeUnavailable
-		log.KvDistribution.VEventf(ctx, 1, "unable to take action - live voters %v don't meet quorum of %d",
-			liveVoters, quorum)
+		log.KvDistribution.VEventf(ctx, 1,
+			"unable to take action - live voters %v and witnesses %v don't meet quorum of %d",
+			liveVoters, liveWitnesses, quorum)
+		return action, action.Priority()
+	}
+
+	if postDecommissionVoters <= neededVoters && len(deadVoters) > 0 && len(liveWitnesses) > 0 {
+		// Range has lost full voter(s), so fewer replicas hold its data. Before
+		// replacing the dead voters, a live witness is replaced by a full voter,
+		// which is caught up as a learner and then swapped with the witness in a
+		// joint configuration. This restores a copy of the data in the locality
+		// of the witness, which is typically one of the surviving localities,
+		// without reducing the range's fault tolerance in the meantime. The
+		// dead voters are then removed, and the witness re-added elsewhere.
+		action = AllocatorPromoteWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - dead voters=%d, live witnesses=%d, priority=%.2f",
+			action, len(deadVoters), len(liveWitnesses), action.Priority())
 		return action, action.Priority()
 	}
 
@@ -1025,6 +1087,28 @@ func (a *Allocator) computeAction(
 		return action, action.Priority()
 	}
 
+	// Witness actions follow. Dead and decommissioning witnesses are replaced
+	// by first adding a new witness, and then removing the superfluous one,
+	// since witnesses can't be swapped atomically.
+	decommissioningWitnesses := storePool.DecommissioningReplicas(witnessReplicas)
+	if usableWitnesses := len(liveWitnesses) - len(decommissioningWitnesses); usableWitnesses < neededWitnesses {
+		action = AllocatorAddWitness
+		log.KvDistribution.VEventf(ctx, 3,
+			"%s - need=%d, have=%d, dead=%d, num_decommissioning=%d, priority=%.2f",
+			action, neededWitnesses, haveWitnesses, len(deadWitnesses), len(decommissioningWitnesses),
+			action.Priority())
+		return action, action.Priority()
+	}
+
+	if haveWitnesses > neededWitnesses {
+		// Range has too many witnesses, or has dead or decommissioning witnesses
+		// which have been replaced.
+		action = AllocatorRemoveWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - need=%d, have=%d, priority=%.2f", action,
+			neededWitnesses, haveWitnesses, action.Priority())
+		return action, action.Priority()
+	}
+
 	if haveVoters > neededVoters {
 		// Range is over-replicated, and should remove a voter.
 		// Ranges with an even number of voters get extra priority because
@@ -1330,6 +1414,20 @@ func (a *Allocator) AllocateNonVoter(
 	return a.AllocateTarget(ctx, storePool, conf, existingVoters, existingNonVoters, replacing, replicaStatus, NonVoterTarget)
 }
 
+// AllocateWitness returns a suitable store for a new allocation of a witness
+// replica. Witnesses are voting replicas, so they are placed according to the
+// voter constraints and diversified against the existing voters and witnesses,
+// which must all be included in existingVoters.
+func (a *Allocator) AllocateWitness(
+	ctx context.Context,
+	storePool storepool.AllocatorStorePool,
+	conf roachpb.SpanConfig,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+	replicaStatus ReplicaStatus,
+) (roachpb.ReplicationTarget, string, error) {
+	return a.AllocateTarget(ctx, storePool, conf, existingVoters, existingNonVoters, nil /* replacing */, replicaStatus, VoterTarget)
+}
+
 // AllocateTargetFromList returns a suitable store for a new allocation of a
 // replica of the given type from the set of candidate stores, with the given
 // existing set of voters and non-voters..
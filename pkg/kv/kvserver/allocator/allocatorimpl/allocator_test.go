@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -7805,6 +7806,122 @@ func TestAllocatorGetNeededReplicas(t *testing.T) {
 	}
 }
 
+func TestAllocatorGetNeededWitnesses(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testCases := []struct {
+		numWitnesses int32
+		neededVoters int
+		expected     int
+	}{
+		{0, 3, 0},
+		{1, 3, 1},
+		// Witnesses must remain a minority of the voting replicas.
+		{2, 3, 1},
+		{1, 1, 0},
+		{1, 2, 0},
+		{2, 5, 2},
+		{3, 5, 2},
+		{-1, 3, 0},
+	}
+
+	for _, tc := range testCases {
+		if e, a := tc.expected, GetNeededWitnesses(tc.numWitnesses, tc.neededVoters); e != a {
+			t.Errorf(
+				"GetNeededWitnesses(conf.NumWitnesses=%d, neededVoters=%d) got %d; want %d",
+				tc.numWitnesses, tc.neededVoters, a, e)
+		}
+	}
+}
+
+// TestAllocatorComputeActionWitnesses verifies that ComputeAction adds,
+// replaces, removes and promotes witnesses according to num_witnesses, and
+// that witnesses count towards the quorum of the range.
+func TestAllocatorComputeActionWitnesses(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	makeDesc := func(voters, witnesses []roachpb.StoreID) roachpb.RangeDescriptor {
+		var desc roachpb.RangeDescriptor
+		for _, id := range voters {
+			desc.InternalReplicas = append(desc.InternalReplicas, roachpb.ReplicaDescriptor{
+				StoreID: id, NodeID: roachpb.NodeID(id), ReplicaID: roachpb.ReplicaID(id),
+			})
+		}
+		for _, id := range witnesses {
+			desc.InternalReplicas = append(desc.InternalReplicas, roachpb.ReplicaDescriptor{
+				StoreID: id, NodeID: roachpb.NodeID(id), ReplicaID: roachpb.ReplicaID(id),
+				Type: roachpb.WITNESS,
+			})
+		}
+		return desc
+	}
+
+	oneWitness := roachpb.SpanConfig{NumReplicas: 3, NumWitnesses: 1}
+	noWitnesses := roachpb.SpanConfig{NumReplicas: 3}
+	testCases := []struct {
+		conf           roachpb.SpanConfig
+		desc           roachpb.RangeDescriptor
+		expectedAction AllocatorAction
+	}{
+		// Needs two voters and a witness, has only one voter.
+		{oneWitness, makeDesc([]roachpb.StoreID{1}, []roachpb.StoreID{3}), AllocatorAddVoter},
+		// Needs two voters and a witness, has no witness.
+		{oneWitness, makeDesc([]roachpb.StoreID{1, 2}, nil), AllocatorAddWitness},
+		// A witness is added before the extra voter is removed.
+		{oneWitness, makeDesc([]roachpb.StoreID{1, 2, 3}, nil), AllocatorAddWitness},
+		// The witness is dead, and should be replaced.
+		{oneWitness, makeDesc([]roachpb.StoreID{1, 2}, []roachpb.StoreID{6}), AllocatorAddWitness},
+		// The dead witness has been replaced, and should be removed.
+		{oneWitness, makeDesc([]roachpb.StoreID{1, 2}, []roachpb.StoreID{3, 6}), AllocatorRemoveWitness},
+		// The range is fully replicated.
+		{oneWitness, makeDesc([]roachpb.StoreID{1, 2}, []roachpb.StoreID{3}), AllocatorConsiderRebalance},
+		// A full voter is dead: the witness is promoted first, and the dead
+		// voter removed afterwards.
+		{oneWitness, makeDesc([]roachpb.StoreID{1, 6}, []roachpb.StoreID{3}), AllocatorPromoteWitness},
+		{oneWitness, makeDesc([]roachpb.StoreID{1, 4, 6}, nil), AllocatorRemoveDeadVoter},
+		// The voter and the witness on the dead stores make up a majority.
+		{oneWitness, makeDesc([]roachpb.StoreID{1, 7}, []roachpb.StoreID{6}), AllocatorRangeUnavailable},
+		// Witnesses are no longer configured: a voter is added first, and the
+		// witness is removed afterwards.
+		{noWitnesses, makeDesc([]roachpb.StoreID{1, 2}, []roachpb.StoreID{3}), AllocatorAddVoter},
+		{noWitnesses, makeDesc([]roachpb.StoreID{1, 2, 4}, []roachpb.StoreID{3}), AllocatorRemoveWitness},
+	}
+
+	ctx := context.Background()
+	stopper, _, sp, a, _ := CreateTestAllocator(ctx, 10, false /* deterministic */)
+	defer stopper.Stop(ctx)
+
+	mockStorePool(sp,
+		[]roachpb.StoreID{1, 2, 3, 4, 5},
+		nil,
+		[]roachpb.StoreID{6, 7},
+		nil,
+		nil,
+		nil,
+	)
+
+	for i, tcase := range testCases {
+		action, _ := a.ComputeAction(ctx, sp, tcase.conf, &tcase.desc)
+		if tcase.expectedAction != action {
+			t.Errorf("Test case %d expected action %q, got action %q",
+				i, tcase.expectedAction, action)
+		}
+	}
+
+	// Witnesses aren't added until the cluster version allows them, and the
+	// range keeps its full voters instead.
+	a.st = cluster.MakeTestingClusterSettingsWithVersions(
+		clusterversion.TestingBinaryVersion,
+		clusterversion.ByKey(clusterversion.V23_2Start),
+		true, /* initializeVersion */
+	)
+	desc := makeDesc([]roachpb.StoreID{1, 2, 3}, nil)
+	action, _ := a.ComputeAction(ctx, sp, oneWitness, &desc)
+	require.Equal(t, AllocatorConsiderRebalance, action)
+}
+
 func makeDescriptor(storeList []roachpb.StoreID) roachpb.RangeDescriptor {
 	desc := roachpb.RangeDescriptor{
 		EndKey: roachpb.RKey(keys.SystemPrefix),
@@ -82,7 +82,8 @@ func (rq *replicateQueue) MaybeAdd(
 // processed at a time and the duration taken to process a replica depends
 // on the action taken. Replicas in the queue are processed in order of
 // priority, then in FIFO order on ties. The Tick function currently only
-// supports processing ConsiderRebalance actions on replicas.
+// supports processing ConsiderRebalance, ReplaceDecommissioningVoter,
+// AddVoter and RemoveVoter actions on replicas.
 // TODO(kvoli,lidorcarmel): Support taking additional actions, beyond consider
 // rebalance.
 func (rq *replicateQueue) Tick(ctx context.Context, tick time.Time, s state.State) {
@@ -106,6 +107,12 @@ func (rq *replicateQueue) Tick(ctx context.Context, tick time.Time, s state.Stat
 		switch action {
 		case allocatorimpl.AllocatorConsiderRebalance:
 			rq.considerRebalance(ctx, rq.next, rng, s)
+		case allocatorimpl.AllocatorReplaceDecommissioningVoter:
+			rq.replaceDecommissioningVoter(ctx, rq.next, rng, s)
+		case allocatorimpl.AllocatorAddVoter:
+			rq.addVoter(ctx, rq.next, rng, s)
+		case allocatorimpl.AllocatorRemoveVoter:
+			rq.removeVoter(ctx, rq.next, rng, s)
 		case allocatorimpl.AllocatorNoop:
 			return
 		default:
@@ -158,3 +165,124 @@ func (rq *replicateQueue) considerRebalance(
 		rq.next = completeAt
 	}
 }
+
+// replaceDecommissioningVoter simulates the logic of the replicate queue when
+// given a replaceDecommissioningVoter action. It will ask the allocator for a
+// target to replace a voter on a decommissioning node with, then enqueue the
+// replica change into the state changer and update the time to process the
+// next replica, with the completion time returned.
+func (rq *replicateQueue) replaceDecommissioningVoter(
+	ctx context.Context, tick time.Time, rng state.Range, s state.State,
+) {
+	desc := rng.Descriptor()
+	decommissioningVoters := rq.storePool.DecommissioningReplicas(desc.Replicas().VoterDescriptors())
+	if len(decommissioningVoters) == 0 {
+		return
+	}
+	replacing := decommissioningVoters[0]
+
+	add, _, err := rq.allocator.AllocateVoter(
+		ctx,
+		rq.storePool,
+		rng.SpanConfig(),
+		desc.Replicas().VoterDescriptors(),
+		desc.Replicas().NonVoterDescriptors(),
+		&replacing,
+		allocatorimpl.Decommissioning,
+	)
+	if err != nil {
+		log.Infof(ctx, "s%d: unable to allocate a replacement for decommissioning voter %s of range %s: %v",
+			rq.storeID, replacing, rng, err)
+		return
+	}
+
+	change := state.ReplicaChange{
+		RangeID: state.RangeID(desc.RangeID),
+		Add:     state.StoreID(add.StoreID),
+		Remove:  state.StoreID(replacing.StoreID),
+		Wait:    rq.delay(rng.Size(), true),
+		Author:  rq.storeID,
+	}
+	if completeAt, ok := rq.stateChanger.Push(tick, &change); ok {
+		rq.next = completeAt
+	}
+}
+
+// addVoter simulates the logic of the replicate queue when given an addVoter
+// action. It will ask the allocator for a target to add a voter to, then
+// enqueue the replica change into the state changer and update the time to
+// process the next replica, with the completion time returned.
+func (rq *replicateQueue) addVoter(
+	ctx context.Context, tick time.Time, rng state.Range, s state.State,
+) {
+	desc := rng.Descriptor()
+	add, _, err := rq.allocator.AllocateVoter(
+		ctx,
+		rq.storePool,
+		rng.SpanConfig(),
+		desc.Replicas().VoterDescriptors(),
+		desc.Replicas().NonVoterDescriptors(),
+		nil, /* replacing */
+		allocatorimpl.Alive,
+	)
+	if err != nil {
+		log.Infof(ctx, "s%d: unable to allocate a voter for range %s: %v", rq.storeID, rng, err)
+		return
+	}
+
+	change := state.ReplicaChange{
+		RangeID: state.RangeID(desc.RangeID),
+		Add:     state.StoreID(add.StoreID),
+		Wait:    rq.delay(rng.Size(), true),
+		Author:  rq.storeID,
+	}
+	if completeAt, ok := rq.stateChanger.Push(tick, &change); ok {
+		rq.next = completeAt
+	}
+}
+
+// removeVoter simulates the logic of the replicate queue when given a
+// removeVoter action. It will ask the allocator for a voter to remove, then
+// enqueue the replica change into the state changer and update the time to
+// process the next replica, with the completion time returned. The
+// leaseholder is never considered for removal, as removing it would first
+// require a lease transfer.
+func (rq *replicateQueue) removeVoter(
+	ctx context.Context, tick time.Time, rng state.Range, s state.State,
+) {
+	desc := rng.Descriptor()
+	voters := desc.Replicas().VoterDescriptors()
+	candidates := make([]roachpb.ReplicaDescriptor, 0, len(voters))
+	for _, voter := range voters {
+		if state.ReplicaID(voter.ReplicaID) != rng.Leaseholder() {
+			candidates = append(candidates, voter)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	remove, _, err := rq.allocator.RemoveVoter(
+		ctx,
+		rq.storePool,
+		rng.SpanConfig(),
+		candidates,
+		voters,
+		desc.Replicas().NonVoterDescriptors(),
+		rq.allocator.ScorerOptions(ctx),
+	)
+	if err != nil {
+		log.Infof(ctx, "s%d: unable to find a voter to remove from range %s: %v", rq.storeID, rng, err)
+		return
+	}
+
+	change := state.ReplicaChange{
+		RangeID: state.RangeID(desc.RangeID),
+		Remove:  state.StoreID(remove.StoreID),
+		Wait:    rq.delay(rng.Size(), false),
+		Author:  rq.storeID,
+	}
+	if completeAt, ok := rq.stateChanger.Push(tick, &change); ok {
+		rq.next = completeAt
+	}
+}
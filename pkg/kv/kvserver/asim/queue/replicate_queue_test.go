@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/gossip"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/stretchr/testify/require"
 )
@@ -90,10 +91,13 @@ func TestReplicateQueue(t *testing.T) {
 	}
 
 	testCases := []struct {
-		desc          string
-		replicaCounts map[state.StoreID]int
-		ticks         []int64
-		expected      map[int64]map[int]int
+		desc            string
+		replicaCounts   map[state.StoreID]int
+		decommissioning []state.NodeID
+		// replicationFactor defaults to 2 when unset.
+		replicationFactor int32
+		ticks             []int64
+		expected          map[int64]map[int]int
 	}{
 		{
 			// NB: Expect no action, range counts are balanced.
@@ -123,11 +127,66 @@ func TestReplicateQueue(t *testing.T) {
 				15: {1: 10, 2: 8, 3: 2},
 			},
 		},
+		{
+			// NB: Expect replicas to be moved off of the decommissioning n2,
+			// one per interval. The only option is replacing the replicas on
+			// s2 with replicas on s3.
+			desc: "s1:(l=10,r=10), s2:(l=0.r=10), s3:(l=0,r=0) decommissioning n2, replace s2 -> s3",
+			replicaCounts: map[state.StoreID]int{
+				1: 10, 2: 10, 3: 0,
+			},
+			decommissioning: []state.NodeID{2},
+			ticks:           []int64{5, 10, 15},
+			expected: map[int64]map[int]int{
+				5:  {1: 10, 2: 10, 3: 0},
+				10: {1: 10, 2: 9, 3: 1},
+				15: {1: 10, 2: 8, 3: 2},
+			},
+		},
+		{
+			// NB: Expect voters to be added on s3, one per interval, as the
+			// ranges are under-replicated. The only option is adding replicas
+			// on s3.
+			desc: "s1:(l=10,r=10), s2:(l=0.r=10), s3:(l=0,r=0) replication factor 3, add voters on s3",
+			replicaCounts: map[state.StoreID]int{
+				1: 10, 2: 10, 3: 0,
+			},
+			replicationFactor: 3,
+			ticks:             []int64{5, 10, 15},
+			expected: map[int64]map[int]int{
+				5:  {1: 10, 2: 10, 3: 0},
+				10: {1: 10, 2: 10, 3: 1},
+				15: {1: 10, 2: 10, 3: 2},
+			},
+		},
+		{
+			// NB: Expect voters to be removed from s2, one per interval, as the
+			// ranges are over-replicated. The replicas on s1 hold the leases so
+			// can't be removed.
+			desc: "s1:(l=10,r=10), s2:(l=0.r=10) replication factor 1, remove voters on s2",
+			replicaCounts: map[state.StoreID]int{
+				1: 10, 2: 10,
+			},
+			replicationFactor: 1,
+			ticks:             []int64{5, 10, 15},
+			expected: map[int64]map[int]int{
+				5:  {1: 10, 2: 10},
+				10: {1: 10, 2: 9},
+				15: {1: 10, 2: 8},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			s := testingState(tc.replicaCounts, 2 /* replication factor */)
+			replicationFactor := tc.replicationFactor
+			if replicationFactor == 0 {
+				replicationFactor = 2
+			}
+			s := testingState(tc.replicaCounts, replicationFactor)
+			for _, nodeID := range tc.decommissioning {
+				s.SetNodeLiveness(nodeID, livenesspb.NodeLivenessStatus_DECOMMISSIONING)
+			}
 			changer := state.NewReplicaChanger()
 			store, _ := s.Store(testingStore)
 			rq := NewReplicateQueue(
@@ -300,9 +300,10 @@ func (s *state) AddNode() Node {
 	s.nodeSeqGen++
 	nodeID := s.nodeSeqGen
 	node := &node{
-		nodeID: nodeID,
-		desc:   roachpb.NodeDescriptor{NodeID: roachpb.NodeID(nodeID)},
-		stores: []StoreID{},
+		nodeID:   nodeID,
+		desc:     roachpb.NodeDescriptor{NodeID: roachpb.NodeID(nodeID)},
+		stores:   []StoreID{},
+		liveness: livenesspb.NodeLivenessStatus_LIVE,
 	}
 	s.nodes[nodeID] = node
 	return node
@@ -341,6 +342,33 @@ func (s *state) AddStore(nodeID NodeID) (Store, bool) {
 	return store, true
 }
 
+// SetNodeLocality sets the locality of the Node with ID NodeID, which is
+// also reflected in the descriptors of the stores on the Node. This fails if
+// no Node exists with ID NodeID.
+func (s *state) SetNodeLocality(nodeID NodeID, locality roachpb.Locality) bool {
+	node, ok := s.nodes[nodeID]
+	if !ok {
+		return false
+	}
+	node.desc.Locality = locality
+	for _, storeID := range node.stores {
+		s.stores[storeID].desc.Node = node.desc
+	}
+	return true
+}
+
+// SetNodeLiveness sets the liveness status of the Node with ID NodeID, as
+// seen by the store pools of every store. This fails if no Node exists with
+// ID NodeID.
+func (s *state) SetNodeLiveness(nodeID NodeID, status livenesspb.NodeLivenessStatus) bool {
+	node, ok := s.nodes[nodeID]
+	if !ok {
+		return false
+	}
+	node.liveness = status
+	return true
+}
+
 // AddReplica modifies the state to include one additional range for the
 // Range with ID RangeID, placed on the Store with ID StoreID. This fails
 // if a Replica for the Range already exists the Store.
@@ -462,6 +490,16 @@ func (s *state) SetSpanConfig(rangeID RangeID, spanConfig roachpb.SpanConfig) bo
 	return false
 }
 
+// SetRangeSize sets the size in bytes of the Range with ID RangeID. This fails
+// if there is no such Range.
+func (s *state) SetRangeSize(rangeID RangeID, size int64) bool {
+	if rng, ok := s.ranges.rangeMap[rangeID]; ok {
+		rng.size = size
+		return true
+	}
+	return false
+}
+
 // SplitRange splits the Range which contains Key in [StartKey, EndKey).
 // The Range is partitioned into [StartKey, Key), [Key, EndKey) and
 // returned. The right hand side of this split, is the new Range. If any
@@ -768,7 +806,11 @@ func (s *state) NextReplicasFn(storeID StoreID) func() []Replica {
 func (s *state) NodeLivenessFn() storepool.NodeLivenessFunc {
 	nodeLivenessFn := func(nid roachpb.NodeID, now time.Time, timeUntilStoreDead time.Duration) livenesspb.NodeLivenessStatus {
 		// TODO(kvoli): Implement liveness records for nodes, that signal they
-		// are dead when simulating partitions, crashes etc.
+		// are dead when simulating partitions, crashes etc. Currently, only
+		// statuses set explicitly via SetNodeLiveness are reported.
+		if node, ok := s.nodes[NodeID(nid)]; ok {
+			return node.liveness
+		}
 		return livenesspb.NodeLivenessStatus_LIVE
 	}
 	return nodeLivenessFn
@@ -779,7 +821,18 @@ func (s *state) NodeLivenessFn() storepool.NodeLivenessFunc {
 // TODO(kvoli): Find a better home for this method, required by the storepool.
 func (s *state) NodeCountFn() storepool.NodeCountFunc {
 	nodeCountFn := func() int {
-		return len(s.Nodes())
+		// Mirror the real node count function, which doesn't count nodes
+		// which are being or have been decommissioned.
+		count := 0
+		for _, node := range s.nodes {
+			switch node.liveness {
+			case livenesspb.NodeLivenessStatus_DECOMMISSIONING,
+				livenesspb.NodeLivenessStatus_DECOMMISSIONED:
+			default:
+				count++
+			}
+		}
+		return count
 	}
 	return nodeCountFn
 }
@@ -900,8 +953,9 @@ func (s *state) publishNewCapacityEvent(capacity roachpb.StoreCapacity, storeID
 
 // node is an implementation of the Node interface.
 type node struct {
-	nodeID NodeID
-	desc   roachpb.NodeDescriptor
+	nodeID   NodeID
+	desc     roachpb.NodeDescriptor
+	liveness livenesspb.NodeLivenessStatus
 
 	stores []StoreID
 }
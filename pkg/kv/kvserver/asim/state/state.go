@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/allocatorimpl"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/storepool"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/workload"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"go.etcd.io/raft/v3"
@@ -84,6 +85,14 @@ type State interface {
 	// AddStore modifies the state to include one additional store on the Node
 	// with ID NodeID. This fails if no Node exists with ID NodeID.
 	AddStore(NodeID) (Store, bool)
+	// SetNodeLocality sets the locality of the Node with ID NodeID, which is
+	// also reflected in the descriptors of the stores on the Node. This fails
+	// if no Node exists with ID NodeID.
+	SetNodeLocality(NodeID, roachpb.Locality) bool
+	// SetNodeLiveness sets the liveness status of the Node with ID NodeID, as
+	// seen by the store pools of every store. This fails if no Node exists with
+	// ID NodeID.
+	SetNodeLiveness(NodeID, livenesspb.NodeLivenessStatus) bool
 	// CanAddReplica returns whether adding a replica for the Range with ID RangeID
 	// to the Store with ID StoreID is valid.
 	CanAddReplica(RangeID, StoreID) bool
@@ -111,6 +120,9 @@ type State interface {
 	RangeSpan(RangeID) (Key, Key, bool)
 	// SetSpanConfig set the span config for the Range with ID RangeID.
 	SetSpanConfig(RangeID, roachpb.SpanConfig) bool
+	// SetRangeSize sets the size in bytes of the Range with ID RangeID. This
+	// fails if there is no such Range.
+	SetRangeSize(RangeID, int64) bool
 	// ValidTransfer returns whether transferring the lease for the Range with ID
 	// RangeID, to the Store with ID StoreID is valid.
 	ValidTransfer(RangeID, StoreID) bool
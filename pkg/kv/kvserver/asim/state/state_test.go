@@ -13,9 +13,11 @@ package state
 import (
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/workload"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/load"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/stretchr/testify/require"
@@ -29,6 +31,31 @@ func TestStateUpdates(t *testing.T) {
 	require.Equal(t, 1, len(s.Stores()))
 }
 
+// TestNodeLocalityAndLiveness asserts that the locality of a node is reflected
+// in the descriptors of its stores, and that decommissioning nodes are
+// reported by the liveness function and excluded from the node count.
+func TestNodeLocalityAndLiveness(t *testing.T) {
+	s := NewState(config.DefaultSimulationSettings())
+	n1, n2 := s.AddNode(), s.AddNode()
+	s1, _ := s.AddStore(n1.NodeID())
+	s.AddStore(n2.NodeID())
+
+	locality := roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: "us-east1"}}}
+	require.True(t, s.SetNodeLocality(n1.NodeID(), locality))
+	require.False(t, s.SetNodeLocality(NodeID(3), locality))
+	descs := s.StoreDescriptors(false /* cached */, s1.StoreID())
+	require.Equal(t, locality, descs[0].Node.Locality)
+
+	livenessFn, nodeCountFn := s.NodeLivenessFn(), s.NodeCountFn()
+	require.Equal(t, 2, nodeCountFn())
+	require.True(t, s.SetNodeLiveness(n2.NodeID(), livenesspb.NodeLivenessStatus_DECOMMISSIONING))
+	require.Equal(t, livenesspb.NodeLivenessStatus_LIVE,
+		livenessFn(roachpb.NodeID(n1.NodeID()), time.Time{}, 0))
+	require.Equal(t, livenesspb.NodeLivenessStatus_DECOMMISSIONING,
+		livenessFn(roachpb.NodeID(n2.NodeID()), time.Time{}, 0))
+	require.Equal(t, 1, nodeCountFn())
+}
+
 // TestRangeSplit asserts that splitting the first range creates new replicas
 // for any replicas that existed on the pre-split range. It also checks that
 // the post-split keys are correct.
@@ -0,0 +1,102 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package whatif
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/workload"
+)
+
+// rangeLoad is the load replayed against the span of a single range.
+type rangeLoad struct {
+	startKey, endKey int64
+
+	readsPerSecond, writesPerSecond float64
+	readSize, writeSize             float64
+
+	// pendingReads and pendingWrites carry the fractional reads and writes
+	// over to the next tick, so that the load of ranges with a rate lower
+	// than the tick rate is not lost.
+	pendingReads, pendingWrites float64
+}
+
+// rangeLoadGenerator implements the workload.Generator interface. It replays
+// the load recorded for each range of a cluster snapshot at a constant rate,
+// against random keys within the span of the range.
+type rangeLoadGenerator struct {
+	lastRun time.Time
+	rand    *rand.Rand
+	// ranges are ordered by start key.
+	ranges []rangeLoad
+}
+
+var _ workload.Generator = &rangeLoadGenerator{}
+
+func newRangeLoadGenerator(start time.Time, seed int64) *rangeLoadGenerator {
+	return &rangeLoadGenerator{
+		lastRun: start,
+		rand:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// addRange adds the load of the range, replayed against the span [startKey,
+// endKey). Ranges must be added in key order.
+func (g *rangeLoadGenerator) addRange(startKey, endKey int64, r RangeSnapshot) {
+	rl := rangeLoad{
+		startKey:        startKey,
+		endKey:          endKey,
+		readsPerSecond:  r.ReadsPerSecond,
+		writesPerSecond: r.WritesPerSecond,
+	}
+	if r.ReadsPerSecond > 0 {
+		rl.readSize = r.ReadBytesPerSecond / r.ReadsPerSecond
+	}
+	if r.WritesPerSecond > 0 {
+		rl.writeSize = r.WriteBytesPerSecond / r.WritesPerSecond
+	}
+	g.ranges = append(g.ranges, rl)
+}
+
+// Tick returns the load events up till time tick, from the last time the
+// workload generator was called. The load on each range within a tick is
+// aggregated into a single event.
+func (g *rangeLoadGenerator) Tick(maxTime time.Time) workload.LoadBatch {
+	elapsed := maxTime.Sub(g.lastRun).Seconds()
+	if elapsed <= 0 {
+		return workload.LoadBatch{}
+	}
+	g.lastRun = maxTime
+
+	batch := workload.LoadBatch{}
+	for i := range g.ranges {
+		rl := &g.ranges[i]
+		rl.pendingReads += rl.readsPerSecond * elapsed
+		rl.pendingWrites += rl.writesPerSecond * elapsed
+		reads, writes := int64(rl.pendingReads), int64(rl.pendingWrites)
+		if reads == 0 && writes == 0 {
+			continue
+		}
+		rl.pendingReads -= float64(reads)
+		rl.pendingWrites -= float64(writes)
+		// NB: The events are sorted by key, as the ranges are ordered by start
+		// key and the key of each event lies within the span of its range.
+		batch = append(batch, workload.LoadEvent{
+			Key:       rl.startKey + g.rand.Int63n(rl.endKey-rl.startKey),
+			Reads:     reads,
+			ReadSize:  int64(float64(reads) * rl.readSize),
+			Writes:    writes,
+			WriteSize: int64(float64(writes) * rl.writeSize),
+		})
+	}
+	return batch
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package whatif
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
+)
+
+// storeIdent identifies a simulated store by the IDs it has, or would have, in
+// the real cluster.
+type storeIdent struct {
+	storeID roachpb.StoreID
+	nodeID  roachpb.NodeID
+	// added is true if the store was added by the proposal.
+	added bool
+}
+
+// stateBuilder translates a cluster snapshot into simulator state. The
+// simulator assigns node and store IDs sequentially, and uses integer keys, so
+// the builder maintains the mapping between the real and simulated IDs. Real
+// keys are not retained: the ranges of the snapshot are assigned equally
+// sized, consecutive spans of the simulated keyspace in key order.
+type stateBuilder struct {
+	state state.State
+	load  *rangeLoadGenerator
+
+	nodeIDs         map[roachpb.NodeID]state.NodeID
+	storeIDs        map[state.StoreID]storeIdent
+	decommissioning map[roachpb.NodeID]bool
+
+	// maxNodeID and maxStoreID are the largest node and store IDs assigned so
+	// far, used to assign IDs to the stores added by the proposal.
+	maxNodeID  roachpb.NodeID
+	maxStoreID roachpb.StoreID
+}
+
+func newStateBuilder(
+	snapshot ClusterSnapshot, settings *config.SimulationSettings,
+) (*stateBuilder, error) {
+	if len(snapshot.Stores) == 0 {
+		return nil, errors.New("cluster snapshot contains no stores")
+	}
+	if len(snapshot.Ranges) == 0 {
+		return nil, errors.New("cluster snapshot contains no ranges")
+	}
+	b := &stateBuilder{
+		state:           state.NewState(settings),
+		nodeIDs:         make(map[roachpb.NodeID]state.NodeID),
+		storeIDs:        make(map[state.StoreID]storeIdent),
+		decommissioning: make(map[roachpb.NodeID]bool),
+	}
+
+	stores := make([]roachpb.StoreDescriptor, len(snapshot.Stores))
+	copy(stores, snapshot.Stores)
+	sort.Slice(stores, func(i, j int) bool {
+		if stores[i].Node.NodeID != stores[j].Node.NodeID {
+			return stores[i].Node.NodeID < stores[j].Node.NodeID
+		}
+		return stores[i].StoreID < stores[j].StoreID
+	})
+	simStoreIDs := make(map[roachpb.StoreID]state.StoreID, len(stores))
+	for _, desc := range stores {
+		nodeID := desc.Node.NodeID
+		simNodeID, ok := b.nodeIDs[nodeID]
+		if !ok {
+			simNodeID = b.state.AddNode().NodeID()
+			b.state.SetNodeLocality(simNodeID, desc.Node.Locality)
+			b.nodeIDs[nodeID] = simNodeID
+		}
+		store, ok := b.state.AddStore(simNodeID)
+		if !ok {
+			return nil, errors.AssertionFailedf("unable to add s%d to n%d", desc.StoreID, nodeID)
+		}
+		simStoreIDs[desc.StoreID] = store.StoreID()
+		b.storeIDs[store.StoreID()] = storeIdent{storeID: desc.StoreID, nodeID: nodeID}
+		if nodeID > b.maxNodeID {
+			b.maxNodeID = nodeID
+		}
+		if desc.StoreID > b.maxStoreID {
+			b.maxStoreID = desc.StoreID
+		}
+	}
+
+	ranges := make([]RangeSnapshot, len(snapshot.Ranges))
+	copy(ranges, snapshot.Ranges)
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Descriptor.StartKey.Less(ranges[j].Descriptor.StartKey)
+	})
+	span := int64(state.MaxKey-state.MinKey) / int64(len(ranges))
+	if span < 1 {
+		return nil, errors.Newf("cannot simulate %d ranges", len(ranges))
+	}
+
+	rangeInfos := make(state.RangesInfo, 0, len(ranges))
+	b.load = newRangeLoadGenerator(settings.StartTime, settings.Seed)
+	for i, r := range ranges {
+		startKey := state.MinKey + state.Key(int64(i)*span)
+		var replicas []state.StoreID
+		for _, voter := range r.Descriptor.Replicas().VoterDescriptors() {
+			if simStoreID, ok := simStoreIDs[voter.StoreID]; ok {
+				replicas = append(replicas, simStoreID)
+			}
+		}
+		if len(replicas) == 0 {
+			return nil, errors.Newf(
+				"r%d has no voters on the stores in the cluster snapshot", r.Descriptor.RangeID)
+		}
+		lh := replicas[0]
+		if simStoreID, ok := simStoreIDs[leaseholder(r)]; ok {
+			lh = simStoreID
+		}
+		conf := r.Config
+		rangeInfos = append(rangeInfos, state.RangeInfoWithReplicas(startKey, replicas, lh, &conf))
+		b.load.addRange(int64(startKey), int64(startKey)+span, r)
+	}
+	state.LoadRangeInfo(b.state, rangeInfos...)
+	for i, r := range ranges {
+		startKey := state.MinKey + state.Key(int64(i)*span)
+		b.state.SetRangeSize(b.state.RangeFor(startKey).RangeID(), r.LogicalBytes)
+	}
+	return b, nil
+}
+
+// addNodes adds the nodes described by the spec to the simulated state.
+func (b *stateBuilder) addNodes(spec NodeSpec) {
+	storesPerNode := spec.StoresPerNode
+	if storesPerNode < 1 {
+		storesPerNode = 1
+	}
+	for i := 0; i < spec.Count; i++ {
+		b.maxNodeID++
+		simNodeID := b.state.AddNode().NodeID()
+		b.state.SetNodeLocality(simNodeID, spec.Locality)
+		b.nodeIDs[b.maxNodeID] = simNodeID
+		for j := 0; j < storesPerNode; j++ {
+			// Adding a store to an existing node cannot fail.
+			store, _ := b.state.AddStore(simNodeID)
+			b.maxStoreID++
+			b.storeIDs[store.StoreID()] = storeIdent{
+				storeID: b.maxStoreID,
+				nodeID:  b.maxNodeID,
+				added:   true,
+			}
+		}
+	}
+}
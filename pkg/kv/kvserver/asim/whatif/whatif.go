@@ -0,0 +1,434 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package whatif simulates the effect of proposed changes, such as adding or
+// decommissioning nodes and changing zone configurations, on the replica and
+// lease placement of a live cluster. It initializes the allocation simulator
+// from a snapshot of the cluster's stores and ranges rather than from a
+// synthetic configuration.
+package whatif
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/metrics"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/workload"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
+)
+
+// ClusterSnapshot is a point in time view of the stores and ranges of a live
+// cluster, from which a simulation is initialized.
+type ClusterSnapshot struct {
+	// Stores contains the descriptor of every store in the cluster. The node
+	// descriptor of each store determines the locality of the store.
+	Stores []roachpb.StoreDescriptor
+	// Ranges contains every range in the cluster.
+	Ranges []RangeSnapshot
+}
+
+// RangeSnapshot is a point in time view of a range of a live cluster.
+type RangeSnapshot struct {
+	Descriptor roachpb.RangeDescriptor
+	// Config is the span config which applies to the range.
+	Config roachpb.SpanConfig
+	// Leaseholder is the store holding the range lease. If unset or not one of
+	// the range's voters, the first voter is assumed to be the leaseholder.
+	Leaseholder roachpb.StoreID
+	// LogicalBytes is the size of the range.
+	LogicalBytes int64
+	// ReadsPerSecond, WritesPerSecond, ReadBytesPerSecond and
+	// WriteBytesPerSecond describe the load on the range, as tracked by the
+	// leaseholder. The load is replayed against the range for the duration of
+	// the simulation.
+	ReadsPerSecond      float64
+	WritesPerSecond     float64
+	ReadBytesPerSecond  float64
+	WriteBytesPerSecond float64
+}
+
+// queriesPerSecond returns the load on the range in the unit used by the
+// simulator, where every key read or written is a query.
+func (r RangeSnapshot) queriesPerSecond() float64 {
+	return r.ReadsPerSecond + r.WritesPerSecond
+}
+
+// Proposal contains the changes to a cluster whose effect is simulated.
+type Proposal struct {
+	// AddNodes contains the nodes to add to the cluster.
+	AddNodes []NodeSpec
+	// DecommissionNodes contains the IDs of the nodes to decommission.
+	DecommissionNodes []roachpb.NodeID
+	// ZoneConfigChanges contains the zone configuration changes to apply,
+	// in order.
+	ZoneConfigChanges []ZoneConfigChange
+}
+
+// NodeSpec describes nodes to add to the cluster.
+type NodeSpec struct {
+	// Count is the number of nodes to add.
+	Count int
+	// StoresPerNode is the number of stores on each node. When zero, a single
+	// store is added per node.
+	StoresPerNode int
+	// Locality is the locality of the nodes.
+	Locality roachpb.Locality
+}
+
+// ZoneConfigChange describes a change to the zone configuration of the ranges
+// within a span.
+type ZoneConfigChange struct {
+	// Span is the span the change applies to. Ranges whose start key is
+	// contained in the span are changed. An empty span changes every range.
+	Span roachpb.Span
+	// Zone contains the zone configuration fields to change. Only the
+	// replication related fields which are explicitly set are applied:
+	// num_replicas, num_voters, constraints, voter_constraints,
+	// lease_preferences, range_min_bytes and range_max_bytes.
+	Zone zonepb.ZoneConfig
+}
+
+// Options control the simulation of a proposal.
+type Options struct {
+	// Duration is the simulated duration to run for.
+	Duration time.Duration
+	// Settings are the simulation settings. The default settings are used
+	// when nil.
+	Settings *config.SimulationSettings
+}
+
+// Report summarizes the predicted effect of a proposal.
+type Report struct {
+	// Stores contains the predicted change for every store, ordered by store
+	// ID. Stores added by the proposal are assigned IDs following the largest
+	// existing node and store IDs.
+	Stores []StoreReport
+	// ReplicaBalance, LeaseBalance and QPSBalance describe the distribution of
+	// replicas, leases and QPS across stores before and after the simulation.
+	// Before includes every existing store; After excludes decommissioning
+	// stores and includes added stores.
+	ReplicaBalanceBefore, ReplicaBalanceAfter Balance
+	LeaseBalanceBefore, LeaseBalanceAfter     Balance
+	QPSBalanceBefore, QPSBalanceAfter         Balance
+	// ReplicaMoves is the number of replicas moved between stores.
+	ReplicaMoves int64
+	// LeaseTransfers is the number of leases transferred between stores.
+	LeaseTransfers int64
+	// RangeSplits is the number of ranges split due to size or load.
+	RangeSplits int64
+	// RebalancedBytes is the number of bytes sent in snapshots for replica
+	// moves.
+	RebalancedBytes int64
+	// Converged is true if the replica and lease counts of every store stopped
+	// changing before the end of the simulation.
+	Converged bool
+	// TimeToConverge is the simulated time taken until the replica and lease
+	// counts of every store last changed. If the simulation did not converge,
+	// this is a lower bound.
+	TimeToConverge time.Duration
+}
+
+// StoreReport contains the predicted change for a single store.
+type StoreReport struct {
+	StoreID roachpb.StoreID
+	NodeID  roachpb.NodeID
+	// Added is true if the store was added by the proposal.
+	Added bool
+	// Decommissioning is true if the node of the store is decommissioned by
+	// the proposal.
+	Decommissioning bool
+
+	ReplicasBefore, ReplicasAfter int64
+	LeasesBefore, LeasesAfter     int64
+	QPSBefore, QPSAfter           float64
+}
+
+// Balance summarizes the distribution of a quantity across stores.
+type Balance struct {
+	Mean, Min, Max, StdDev float64
+}
+
+func makeBalance(values []float64) Balance {
+	if len(values) == 0 {
+		return Balance{}
+	}
+	b := Balance{Min: math.Inf(1), Max: math.Inf(-1)}
+	for _, v := range values {
+		b.Mean += v
+		b.Min = math.Min(b.Min, v)
+		b.Max = math.Max(b.Max, v)
+	}
+	b.Mean /= float64(len(values))
+	for _, v := range values {
+		b.StdDev += (v - b.Mean) * (v - b.Mean)
+	}
+	b.StdDev = math.Sqrt(b.StdDev / float64(len(values)))
+	return b
+}
+
+// Run simulates the effect of the proposal on the cluster captured by the
+// snapshot, and returns a report summarizing the predicted replica and lease
+// movement.
+//
+// The simulator only models voting replicas, so non-voting replicas in the
+// snapshot are ignored.
+func Run(
+	ctx context.Context, snapshot ClusterSnapshot, proposal Proposal, opts Options,
+) (Report, error) {
+	settings := opts.Settings
+	if settings == nil {
+		settings = config.DefaultSimulationSettings()
+	}
+	if opts.Duration <= 0 {
+		return Report{}, errors.Newf("simulation duration must be positive, got %s", opts.Duration)
+	}
+	ranges, err := applyZoneConfigChanges(snapshot.Ranges, proposal.ZoneConfigChanges)
+	if err != nil {
+		return Report{}, err
+	}
+	snapshot.Ranges = ranges
+
+	b, err := newStateBuilder(snapshot, settings)
+	if err != nil {
+		return Report{}, err
+	}
+	for _, spec := range proposal.AddNodes {
+		b.addNodes(spec)
+	}
+	for _, nodeID := range proposal.DecommissionNodes {
+		if err := b.decommission(nodeID); err != nil {
+			return Report{}, err
+		}
+	}
+
+	before := snapshotStoreLoad(snapshot)
+	sim := asim.NewSimulator(
+		opts.Duration,
+		[]workload.Generator{b.load},
+		b.state,
+		settings,
+		metrics.NewTracker(settings.MetricsInterval),
+	)
+	sim.RunSim(ctx)
+
+	return b.report(before, sim.History(), settings.StartTime), nil
+}
+
+// applyZoneConfigChanges returns a copy of the ranges with the zone config
+// changes applied to their span configs.
+func applyZoneConfigChanges(
+	ranges []RangeSnapshot, changes []ZoneConfigChange,
+) ([]RangeSnapshot, error) {
+	if len(changes) == 0 {
+		return ranges, nil
+	}
+	ret := make([]RangeSnapshot, len(ranges))
+	copy(ret, ranges)
+	for _, change := range changes {
+		// The zone config is hydrated from the default zone config in order to
+		// convert it to a span config. Only the fields set in the change are
+		// then copied over.
+		hydrated := change.Zone
+		hydrated.InheritFromParent(zonepb.DefaultZoneConfigRef())
+		if err := hydrated.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid zone config change for span %s", change.Span)
+		}
+		conf := hydrated.AsSpanConfig()
+		zone := change.Zone
+		for i := range ret {
+			if len(change.Span.Key) != 0 &&
+				!change.Span.ContainsKey(ret[i].Descriptor.StartKey.AsRawKey()) {
+				continue
+			}
+			c := &ret[i].Config
+			if zone.NumReplicas != nil {
+				c.NumReplicas = conf.NumReplicas
+			}
+			if zone.NumVoters != nil {
+				c.NumVoters = conf.NumVoters
+			}
+			if !zone.InheritedConstraints {
+				c.Constraints = conf.Constraints
+			}
+			if !zone.InheritedVoterConstraints() {
+				c.VoterConstraints = conf.VoterConstraints
+			}
+			if !zone.InheritedLeasePreferences {
+				c.LeasePreferences = conf.LeasePreferences
+			}
+			if zone.RangeMinBytes != nil {
+				c.RangeMinBytes = conf.RangeMinBytes
+			}
+			if zone.RangeMaxBytes != nil {
+				c.RangeMaxBytes = conf.RangeMaxBytes
+			}
+		}
+	}
+	return ret, nil
+}
+
+// storeLoad is the replica count, lease count and QPS of a store.
+type storeLoad struct {
+	replicas, leases int64
+	qps              float64
+}
+
+// snapshotStoreLoad returns the load of each store in the snapshot, counting
+// only the voting replicas which are simulated.
+func snapshotStoreLoad(snapshot ClusterSnapshot) map[roachpb.StoreID]storeLoad {
+	load := make(map[roachpb.StoreID]storeLoad, len(snapshot.Stores))
+	for _, store := range snapshot.Stores {
+		load[store.StoreID] = storeLoad{}
+	}
+	for _, r := range snapshot.Ranges {
+		for _, voter := range r.Descriptor.Replicas().VoterDescriptors() {
+			l, ok := load[voter.StoreID]
+			if !ok {
+				continue
+			}
+			l.replicas++
+			load[voter.StoreID] = l
+		}
+		if lh := leaseholder(r); lh != 0 {
+			if l, ok := load[lh]; ok {
+				l.leases++
+				l.qps += r.queriesPerSecond()
+				load[lh] = l
+			}
+		}
+	}
+	return load
+}
+
+// leaseholder returns the leaseholder store of the range, which defaults to
+// the first voter when the leaseholder is unknown.
+func leaseholder(r RangeSnapshot) roachpb.StoreID {
+	voters := r.Descriptor.Replicas().VoterDescriptors()
+	for _, v := range voters {
+		if v.StoreID == r.Leaseholder {
+			return v.StoreID
+		}
+	}
+	if len(voters) > 0 {
+		return voters[0].StoreID
+	}
+	return 0
+}
+
+// report builds the report of a finished simulation.
+func (b *stateBuilder) report(
+	before map[roachpb.StoreID]storeLoad, history asim.History, start time.Time,
+) Report {
+	var r Report
+	simStoreIDs := make([]state.StoreID, 0, len(b.storeIDs))
+	for simID := range b.storeIDs {
+		simStoreIDs = append(simStoreIDs, simID)
+	}
+	sort.Slice(simStoreIDs, func(i, j int) bool { return simStoreIDs[i] < simStoreIDs[j] })
+
+	var replicasBefore, leasesBefore, qpsBefore []float64
+	var replicasAfter, leasesAfter, qpsAfter []float64
+	for _, desc := range b.state.StoreDescriptors(false /* cached */, simStoreIDs...) {
+		simID := state.StoreID(desc.StoreID)
+		id := b.storeIDs[simID]
+		sr := StoreReport{
+			StoreID:         id.storeID,
+			NodeID:          id.nodeID,
+			Added:           id.added,
+			Decommissioning: b.decommissioning[id.nodeID],
+			ReplicasAfter:   int64(desc.Capacity.RangeCount),
+			LeasesAfter:     int64(desc.Capacity.LeaseCount),
+			QPSAfter:        desc.Capacity.QueriesPerSecond,
+		}
+		if l, ok := before[id.storeID]; ok && !id.added {
+			sr.ReplicasBefore, sr.LeasesBefore, sr.QPSBefore = l.replicas, l.leases, l.qps
+			replicasBefore = append(replicasBefore, float64(l.replicas))
+			leasesBefore = append(leasesBefore, float64(l.leases))
+			qpsBefore = append(qpsBefore, l.qps)
+		}
+		if !sr.Decommissioning {
+			replicasAfter = append(replicasAfter, float64(sr.ReplicasAfter))
+			leasesAfter = append(leasesAfter, float64(sr.LeasesAfter))
+			qpsAfter = append(qpsAfter, sr.QPSAfter)
+		}
+		r.Stores = append(r.Stores, sr)
+	}
+	sort.Slice(r.Stores, func(i, j int) bool { return r.Stores[i].StoreID < r.Stores[j].StoreID })
+	r.ReplicaBalanceBefore, r.ReplicaBalanceAfter = makeBalance(replicasBefore), makeBalance(replicasAfter)
+	r.LeaseBalanceBefore, r.LeaseBalanceAfter = makeBalance(leasesBefore), makeBalance(leasesAfter)
+	r.QPSBalanceBefore, r.QPSBalanceAfter = makeBalance(qpsBefore), makeBalance(qpsAfter)
+
+	for _, usage := range b.state.ClusterUsageInfo().StoreUsage {
+		r.ReplicaMoves += usage.Rebalances
+		r.LeaseTransfers += usage.LeaseTransfers
+		r.RangeSplits += usage.RangeSplits
+		r.RebalancedBytes += usage.RebalanceSentBytes
+	}
+
+	r.Converged, r.TimeToConverge = convergence(history, start)
+	return r
+}
+
+// convergence returns whether the replica and lease counts of the stores
+// stopped changing before the end of the recorded history, and the time at
+// which they last changed.
+func convergence(history asim.History, start time.Time) (converged bool, _ time.Duration) {
+	recorded := history.Recorded
+	if len(recorded) == 0 {
+		return false, 0
+	}
+	changed := func(prev, cur []metrics.StoreMetrics) bool {
+		if len(prev) != len(cur) {
+			return true
+		}
+		for i := range cur {
+			if prev[i].StoreID != cur[i].StoreID ||
+				prev[i].Replicas != cur[i].Replicas ||
+				prev[i].Leases != cur[i].Leases {
+				return true
+			}
+		}
+		return false
+	}
+	lastChange := 0
+	for i := 1; i < len(recorded); i++ {
+		if changed(recorded[i-1], recorded[i]) {
+			lastChange = i
+		}
+	}
+	if lastChange == 0 {
+		return true, 0
+	}
+	var timeToConverge time.Duration
+	if len(recorded[lastChange]) > 0 {
+		timeToConverge = recorded[lastChange][0].Tick.Sub(start)
+	}
+	return lastChange < len(recorded)-1, timeToConverge
+}
+
+// decommission marks the node with the given ID as decommissioning in the
+// simulated state.
+func (b *stateBuilder) decommission(nodeID roachpb.NodeID) error {
+	simID, ok := b.nodeIDs[nodeID]
+	if !ok {
+		return errors.Newf("cannot decommission n%d: node not found", nodeID)
+	}
+	b.state.SetNodeLiveness(simID, livenesspb.NodeLivenessStatus_DECOMMISSIONING)
+	b.decommissioning[nodeID] = true
+	return nil
+}
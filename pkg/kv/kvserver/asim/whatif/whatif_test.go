@@ -0,0 +1,248 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package whatif
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/metrics"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+// testingSnapshot returns a snapshot of a cluster with one store per node,
+// where the ranges are replicated to the first replicationFactor stores and
+// their leases are held by the first store.
+func testingSnapshot(nodes, ranges, replicationFactor int) ClusterSnapshot {
+	var snapshot ClusterSnapshot
+	for i := 1; i <= nodes; i++ {
+		snapshot.Stores = append(snapshot.Stores, roachpb.StoreDescriptor{
+			StoreID: roachpb.StoreID(i),
+			Node: roachpb.NodeDescriptor{
+				NodeID: roachpb.NodeID(i),
+				Locality: roachpb.Locality{Tiers: []roachpb.Tier{
+					{Key: "zone", Value: fmt.Sprintf("z%d", i)},
+				}},
+			},
+		})
+	}
+	conf := roachpb.TestingDefaultSpanConfig()
+	conf.NumReplicas = int32(replicationFactor)
+	for i := 0; i < ranges; i++ {
+		desc := roachpb.RangeDescriptor{
+			RangeID:  roachpb.RangeID(i + 1),
+			StartKey: roachpb.RKey(fmt.Sprintf("k%04d", i)),
+			EndKey:   roachpb.RKey(fmt.Sprintf("k%04d", i+1)),
+		}
+		if i == 0 {
+			desc.StartKey = roachpb.RKeyMin
+		}
+		for j := 1; j <= replicationFactor; j++ {
+			desc.AddReplica(roachpb.NodeID(j), roachpb.StoreID(j), roachpb.VOTER_FULL)
+		}
+		snapshot.Ranges = append(snapshot.Ranges, RangeSnapshot{
+			Descriptor:          desc,
+			Config:              conf,
+			Leaseholder:         1,
+			LogicalBytes:        32 << 20,
+			ReadsPerSecond:      10,
+			WritesPerSecond:     1,
+			ReadBytesPerSecond:  10 << 10,
+			WriteBytesPerSecond: 1 << 10,
+		})
+	}
+	return snapshot
+}
+
+func totalReplicas(report Report) (before, after int64) {
+	for _, s := range report.Stores {
+		before += s.ReplicasBefore
+		after += s.ReplicasAfter
+	}
+	return before, after
+}
+
+// TestRunAddNodes verifies that simulating the addition of nodes to a cluster
+// predicts replicas being moved onto the new nodes.
+func TestRunAddNodes(t *testing.T) {
+	ctx := context.Background()
+	snapshot := testingSnapshot(3 /* nodes */, 30 /* ranges */, 3 /* replicationFactor */)
+	proposal := Proposal{AddNodes: []NodeSpec{{
+		Count:    1,
+		Locality: roachpb.Locality{Tiers: []roachpb.Tier{{Key: "zone", Value: "z4"}}},
+	}}}
+
+	report, err := Run(ctx, snapshot, proposal, Options{Duration: 30 * time.Minute})
+	require.NoError(t, err)
+
+	require.Len(t, report.Stores, 4)
+	added := report.Stores[3]
+	require.Equal(t, roachpb.StoreID(4), added.StoreID)
+	require.Equal(t, roachpb.NodeID(4), added.NodeID)
+	require.True(t, added.Added)
+	require.Zero(t, added.ReplicasBefore)
+	require.Positive(t, added.ReplicasAfter)
+	require.Positive(t, report.ReplicaMoves)
+
+	before, after := totalReplicas(report)
+	require.Equal(t, int64(90), before)
+	require.Equal(t, before, after)
+	require.Equal(t, float64(30), report.ReplicaBalanceBefore.Mean)
+	require.Zero(t, report.ReplicaBalanceBefore.StdDev)
+}
+
+// TestRunDecommission verifies that simulating the decommissioning of a node
+// predicts all of its replicas being moved onto the remaining nodes.
+func TestRunDecommission(t *testing.T) {
+	ctx := context.Background()
+	snapshot := testingSnapshot(4 /* nodes */, 20 /* ranges */, 3 /* replicationFactor */)
+	proposal := Proposal{DecommissionNodes: []roachpb.NodeID{2}}
+
+	report, err := Run(ctx, snapshot, proposal, Options{Duration: 30 * time.Minute})
+	require.NoError(t, err)
+
+	require.Len(t, report.Stores, 4)
+	decommissioned := report.Stores[1]
+	require.True(t, decommissioned.Decommissioning)
+	require.Equal(t, int64(20), decommissioned.ReplicasBefore)
+	require.Zero(t, decommissioned.ReplicasAfter)
+	require.Equal(t, int64(20), report.Stores[3].ReplicasAfter)
+	require.Positive(t, report.TimeToConverge)
+
+	_, err = Run(ctx, snapshot, Proposal{DecommissionNodes: []roachpb.NodeID{5}},
+		Options{Duration: time.Minute})
+	require.Error(t, err)
+}
+
+// TestRunChangeReplicationFactor verifies that simulating a change to the
+// number of replicas predicts replicas being added or removed.
+func TestRunChangeReplicationFactor(t *testing.T) {
+	ctx := context.Background()
+	snapshot := testingSnapshot(5 /* nodes */, 10 /* ranges */, 3 /* replicationFactor */)
+
+	for _, tc := range []struct {
+		numReplicas int32
+		expected    int64
+	}{
+		{numReplicas: 5, expected: 50},
+		{numReplicas: 1, expected: 10},
+	} {
+		zone := zonepb.ZoneConfig{NumReplicas: proto.Int32(tc.numReplicas)}
+		proposal := Proposal{ZoneConfigChanges: []ZoneConfigChange{{Zone: zone}}}
+		report, err := Run(ctx, snapshot, proposal, Options{Duration: 30 * time.Minute})
+		require.NoError(t, err)
+
+		before, after := totalReplicas(report)
+		require.Equal(t, int64(30), before)
+		require.Equal(t, tc.expected, after, "num_replicas=%d", tc.numReplicas)
+	}
+}
+
+// TestApplyZoneConfigChanges verifies that only the fields set in a zone config
+// change are applied, to the ranges within its span.
+func TestApplyZoneConfigChanges(t *testing.T) {
+	snapshot := testingSnapshot(3 /* nodes */, 3 /* ranges */, 3 /* replicationFactor */)
+	zone := *zonepb.NewZoneConfig()
+	zone.NumReplicas = proto.Int32(5)
+	zone.Constraints = []zonepb.ConstraintsConjunction{{
+		Constraints: []zonepb.Constraint{{Type: zonepb.Constraint_REQUIRED, Key: "zone", Value: "z1"}},
+		NumReplicas: 1,
+	}}
+	zone.InheritedConstraints = false
+	change := ZoneConfigChange{
+		Span: roachpb.Span{Key: roachpb.Key("k0001"), EndKey: roachpb.Key("k0002")},
+		Zone: zone,
+	}
+
+	ranges, err := applyZoneConfigChanges(snapshot.Ranges, []ZoneConfigChange{change})
+	require.NoError(t, err)
+	require.Equal(t, snapshot.Ranges[0].Config, ranges[0].Config)
+	require.Equal(t, snapshot.Ranges[2].Config, ranges[2].Config)
+	require.Equal(t, int32(5), ranges[1].Config.NumReplicas)
+	require.Equal(t, []roachpb.ConstraintsConjunction{{
+		Constraints: []roachpb.Constraint{{Type: roachpb.Constraint_REQUIRED, Key: "zone", Value: "z1"}},
+		NumReplicas: 1,
+	}}, ranges[1].Config.Constraints)
+	require.Equal(t, snapshot.Ranges[1].Config.LeasePreferences, ranges[1].Config.LeasePreferences)
+	require.Equal(t, snapshot.Ranges[1].Config.RangeMaxBytes, ranges[1].Config.RangeMaxBytes)
+	// The snapshot itself is not modified.
+	require.Equal(t, int32(3), snapshot.Ranges[1].Config.NumReplicas)
+
+	// Invalid zone configs are rejected.
+	zone.NumReplicas = proto.Int32(-1)
+	_, err = applyZoneConfigChanges(snapshot.Ranges, []ZoneConfigChange{{Zone: zone}})
+	require.Error(t, err)
+}
+
+// TestConvergence verifies the time to converge derived from the history of a
+// simulation.
+func TestConvergence(t *testing.T) {
+	start := config.DefaultSimulationSettings().StartTime
+	sample := func(tick time.Duration, replicas ...int64) []metrics.StoreMetrics {
+		var sms []metrics.StoreMetrics
+		for i, r := range replicas {
+			sms = append(sms, metrics.StoreMetrics{
+				Tick: start.Add(tick), StoreID: int64(i + 1), Replicas: r,
+			})
+		}
+		return sms
+	}
+
+	for _, tc := range []struct {
+		desc           string
+		recorded       [][]metrics.StoreMetrics
+		converged      bool
+		timeToConverge time.Duration
+	}{
+		{
+			desc:      "empty",
+			converged: false,
+		},
+		{
+			desc:      "no change",
+			recorded:  [][]metrics.StoreMetrics{sample(10*time.Second, 2, 2), sample(20*time.Second, 2, 2)},
+			converged: true,
+		},
+		{
+			desc: "converged",
+			recorded: [][]metrics.StoreMetrics{
+				sample(10*time.Second, 4, 0),
+				sample(20*time.Second, 3, 1),
+				sample(30*time.Second, 2, 2),
+				sample(40*time.Second, 2, 2),
+			},
+			converged:      true,
+			timeToConverge: 30 * time.Second,
+		},
+		{
+			desc: "not converged",
+			recorded: [][]metrics.StoreMetrics{
+				sample(10*time.Second, 4, 0),
+				sample(20*time.Second, 3, 1),
+			},
+			converged:      false,
+			timeToConverge: 20 * time.Second,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			converged, timeToConverge := convergence(asim.History{Recorded: tc.recorded}, start)
+			require.Equal(t, tc.converged, converged)
+			require.Equal(t, tc.timeToConverge, timeToConverge)
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/lockspanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -43,7 +44,8 @@ func TestRequestsSerializeWithAllKeys(t *testing.T) {
 			continue
 		}
 		t.Run(method.String(), func(t *testing.T) {
-			var otherLatchSpans, otherLockSpans spanset.SpanSet
+			var otherLatchSpans spanset.SpanSet
+			var otherLockSpans lockspanset.LockSpanSet
 
 			startKey := []byte(`a`)
 			endKey := []byte(`b`)
@@ -104,37 +104,63 @@ func readProvisionalVal(
 
 }
 
-// acquireUnreplicatedLocksOnKeys adds an unreplicated lock acquisition by the
-// transaction to the provided result.Result for each key in the scan result.
-func acquireUnreplicatedLocksOnKeys(
+// acquireLockOnKey adds a lock acquisition with the given strength and
+// durability by the transaction on the key to the provided result.Result.
+// Replicated locks are also persisted in the range's lock table keyspace.
+func acquireLockOnKey(
+	ctx context.Context,
+	readWriter storage.ReadWriter,
 	res *result.Result,
 	txn *roachpb.Transaction,
+	str lock.Strength,
+	dur lock.Durability,
+	key roachpb.Key,
+) error {
+	if dur == lock.Replicated {
+		if err := storage.MVCCAcquireLock(ctx, readWriter, txn, str, key); err != nil {
+			return err
+		}
+	}
+	res.Local.AcquiredLocks = append(res.Local.AcquiredLocks, roachpb.MakeLockAcquisition(txn, key, dur, str))
+	return nil
+}
+
+// acquireLocksOnKeys adds a lock acquisition with the given strength and
+// durability by the transaction to the provided result.Result for each key in
+// the scan result. Replicated locks are also persisted in the range's lock
+// table keyspace.
+func acquireLocksOnKeys(
+	ctx context.Context,
+	readWriter storage.ReadWriter,
+	res *result.Result,
+	txn *roachpb.Transaction,
+	str lock.Strength,
+	dur lock.Durability,
 	scanFmt kvpb.ScanFormat,
 	scanRes *storage.MVCCScanResult,
 ) error {
-	res.Local.AcquiredLocks = make([]roachpb.LockAcquisition, scanRes.NumKeys)
+	res.Local.AcquiredLocks = make([]roachpb.LockAcquisition, 0, scanRes.NumKeys)
 	switch scanFmt {
 	case kvpb.BATCH_RESPONSE:
-		var i int
 		return storage.MVCCScanDecodeKeyValues(scanRes.KVData, func(key storage.MVCCKey, _ []byte) error {
-			res.Local.AcquiredLocks[i] = roachpb.MakeLockAcquisition(txn, copyKey(key.Key), lock.Unreplicated)
-			i++
-			return nil
+			return acquireLockOnKey(ctx, readWriter, res, txn, str, dur, copyKey(key.Key))
 		})
 	case kvpb.KEY_VALUES:
-		for i, row := range scanRes.KVs {
-			res.Local.AcquiredLocks[i] = roachpb.MakeLockAcquisition(txn, copyKey(row.Key), lock.Unreplicated)
+		for _, row := range scanRes.KVs {
+			if err := acquireLockOnKey(ctx, readWriter, res, txn, str, dur, copyKey(row.Key)); err != nil {
+				return err
+			}
 		}
 		return nil
 	case kvpb.COL_BATCH_RESPONSE:
-		return errors.AssertionFailedf("unexpectedly acquiring unreplicated locks with COL_BATCH_RESPONSE scan format")
+		return errors.AssertionFailedf("unexpectedly acquiring locks with COL_BATCH_RESPONSE scan format")
 	default:
 		panic("unexpected scanFormat")
 	}
 }
 
 // copyKey copies the provided roachpb.Key into a new byte slice, returning the
-// copy. It is used in acquireUnreplicatedLocksOnKeys for two reasons:
+// copy. It is used in acquireLocksOnKeys for two reasons:
 //  1. the keys in an MVCCScanResult, regardless of the scan format used, point
 //     to a small number of large, contiguous byte slices. These "MVCCScan
 //     batches" contain keys and their associated values in the same backing
@@ -0,0 +1,82 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteRateLimitedInsertIsNotRetried verifies that an implicit INSERT
+// whose writes are rejected by the write rate limits of its table's zone
+// configuration fails with an error that the client can back off on, rather
+// than being retried by the server in a tight loop against the range.
+func TestWriteRateLimitedInsertIsNotRetried(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	tdb := sqlutils.MakeSQLRunner(db)
+
+	tdb.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY)`)
+	tdb.Exec(t, `ALTER TABLE t CONFIGURE ZONE USING range_max_write_requests_per_second = 1`)
+
+	rejectedRequests := func() int64 {
+		var n int64
+		require.NoError(t, s.GetStores().(*kvserver.Stores).VisitStores(func(s *kvserver.Store) error {
+			n += s.Metrics().RangeWriteRateLimitMetrics.RejectedRequests.Count()
+			return nil
+		}))
+		return n
+	}
+
+	// The limits are enforced once the zone configuration is applied to the
+	// range of the table.
+	var k int
+	testutils.SucceedsSoon(t, func() error {
+		for i := 0; i < 100; i++ {
+			k++
+			before := rejectedRequests()
+			_, err := db.ExecContext(ctx, `INSERT INTO t VALUES ($1)`, k)
+			if err == nil {
+				continue
+			}
+			var pqErr *pq.Error
+			require.True(t, errors.As(err, &pqErr), "%+v", err)
+			require.Equal(t, pgcode.ConfigurationLimitExceeded.String(), string(pqErr.Code), "%+v", err)
+			require.Contains(t, pqErr.Message, "write rate limit exceeded")
+			require.NotEmpty(t, pqErr.Hint)
+			// The single write of the INSERT was rejected once, and not retried.
+			require.Equal(t, int64(1), rejectedRequests()-before)
+			return nil
+		}
+		return errors.New("writes were not rate limited")
+	})
+
+	// The rejected write did not take effect.
+	var count int
+	tdb.QueryRow(t, `SELECT count(*) FROM t WHERE k = $1`, k).Scan(&count)
+	require.Zero(t, count)
+}
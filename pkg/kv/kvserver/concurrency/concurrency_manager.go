@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/lockspanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanlatch"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/txnwait"
@@ -513,7 +514,13 @@ func (m *managerImpl) HandleTransactionPushError(
 
 // OnLockAcquired implements the LockManager interface.
 func (m *managerImpl) OnLockAcquired(ctx context.Context, acq *roachpb.LockAcquisition) {
-	if err := m.lt.AcquireLock(&acq.Txn, acq.Key, lock.Exclusive, acq.Durability); err != nil {
+	str := acq.Strength
+	if str == lock.None {
+		// Lock acquisitions proposed by nodes that predate lock strengths do not
+		// carry one. Those nodes only acquired exclusive locks.
+		str = lock.Exclusive
+	}
+	if err := m.lt.AcquireLock(&acq.Txn, acq.Key, str, acq.Durability); err != nil {
 		log.Fatalf(ctx, "%v", err)
 	}
 }
@@ -670,7 +677,7 @@ func (g *Guard) LatchSpans() *spanset.SpanSet {
 // SpanSets to the caller, ensuring that the SpanSets are not destroyed with the
 // Guard. The method is only safe if called immediately before passing the Guard
 // to FinishReq.
-func (g *Guard) TakeSpanSets() (*spanset.SpanSet, *spanset.SpanSet) {
+func (g *Guard) TakeSpanSets() (*spanset.SpanSet, *lockspanset.LockSpanSet) {
 	la, lo := g.Req.LatchSpans, g.Req.LockSpans
 	g.Req.LatchSpans, g.Req.LockSpans = nil, nil
 	return la, lo
@@ -712,14 +719,14 @@ func (g *Guard) IsolatedAtLaterTimestamps() bool {
 		// then it can not trivially bump its timestamp without dropping its
 		// lockTableGuard and re-scanning the lockTable. Doing so could allow the
 		// request to conflict with locks that it previously did not conflict with.
-		len(g.Req.LockSpans.GetSpans(spanset.SpanReadOnly, spanset.SpanGlobal)) == 0 &&
-		len(g.Req.LockSpans.GetSpans(spanset.SpanReadOnly, spanset.SpanLocal)) == 0
+		len(g.Req.LockSpans.GetSpans(spanset.SpanGlobal, lock.None)) == 0 &&
+		len(g.Req.LockSpans.GetSpans(spanset.SpanLocal, lock.None)) == 0
 }
 
 // CheckOptimisticNoConflicts checks that the {latch,lock}SpansRead do not
 // have a conflicting latch, lock.
 func (g *Guard) CheckOptimisticNoConflicts(
-	latchSpansRead *spanset.SpanSet, lockSpansRead *spanset.SpanSet,
+	latchSpansRead *spanset.SpanSet, lockSpansRead *lockspanset.LockSpanSet,
 ) (ok bool) {
 	if g.EvalKind != OptimisticEval {
 		panic(errors.AssertionFailedf("unexpected EvalKind: %d", g.EvalKind))
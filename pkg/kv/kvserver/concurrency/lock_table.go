@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/lockspanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -94,12 +95,28 @@ type waitingState struct {
 	held          bool              // is the conflict a held lock?
 	queuedWriters int               // how many writers are waiting?
 	queuedReaders int               // how many readers are waiting?
+	// The other transactions holding the lock with Shared or Update strength
+	// that the request conflicts with, when txn is one such holder. Pushing
+	// only txn would leave deadlocks involving these transactions undetected
+	// until txn released the lock, so the request pushes all of them.
+	otherHolders []*enginepb.TxnMeta
 
 	// Represents the action that the request was trying to perform when
-	// it hit the conflict. E.g. was it trying to read or write?
+	// it hit the conflict. E.g. was it trying to read or write? Requests
+	// trying to acquire a lock of any strength are considered writers.
 	guardAccess spanset.SpanAccess
 }
 
+// accessForStrength returns the access that a request trying to acquire a lock
+// with the supplied strength is considered to be performing when it conflicts
+// with another transaction.
+func accessForStrength(str lock.Strength) spanset.SpanAccess {
+	if str == lock.None {
+		return spanset.SpanReadOnly
+	}
+	return spanset.SpanReadWrite
+}
+
 // String implements the fmt.Stringer interface.
 func (s waitingState) String() string {
 	switch s.kind {
@@ -363,7 +380,7 @@ type lockTableGuardImpl struct {
 	// Information about this request.
 	txn                *enginepb.TxnMeta
 	ts                 hlc.Timestamp
-	spans              *spanset.SpanSet
+	spans              *lockspanset.LockSpanSet
 	waitPolicy         lock.WaitPolicy
 	maxWaitQueueLength int
 
@@ -407,16 +424,16 @@ type lockTableGuardImpl struct {
 	// release etc.) may cause the request to no longer need to wait at this
 	// key. It then needs to continue iterating through spans to find the next
 	// key to wait at (we don't want to wastefully start at the beginning since
-	// this request probably has a reservation at the contended keys there): sa,
+	// this request probably has a reservation at the contended keys there): str,
 	// ss, index, key collectively track the current position to allow it to
 	// continue iterating.
 
 	// The key for the lockState.
 	key roachpb.Key
 	// The key for the lockState is contained in the Span specified by
-	// spans[sa][ss][index].
+	// spans[ss][str][index].
 	ss    spanset.SpanScope
-	sa    spanset.SpanAccess // Iterates from stronger to weaker strength
+	str   lock.Strength // Iterates from stronger to weaker strength
 	index int
 
 	mu struct {
@@ -536,16 +553,18 @@ func (g *lockTableGuardImpl) updateStateLocked(newState waitingState) {
 	g.mu.state = newState
 }
 
-func (g *lockTableGuardImpl) CheckOptimisticNoConflicts(spanSet *spanset.SpanSet) (ok bool) {
+func (g *lockTableGuardImpl) CheckOptimisticNoConflicts(
+	lockSpanSet *lockspanset.LockSpanSet,
+) (ok bool) {
 	if g.waitPolicy == lock.WaitPolicy_SkipLocked {
 		// If the request is using a SkipLocked wait policy, lock conflicts are
 		// handled during evaluation.
 		return true
 	}
-	// Temporarily replace the SpanSet in the guard.
+	// Temporarily replace the LockSpanSet in the guard.
 	originalSpanSet := g.spans
-	g.spans = spanSet
-	g.sa = spanset.NumSpanAccess - 1
+	g.spans = lockSpanSet
+	g.str = lock.MaxStrength
 	g.ss = spanset.SpanScope(0)
 	g.index = -1
 	defer func() {
@@ -559,7 +578,7 @@ func (g *lockTableGuardImpl) CheckOptimisticNoConflicts(spanSet *spanset.SpanSet
 		ltRange := &lockState{key: startKey, endKey: span.EndKey}
 		for iter.FirstOverlap(ltRange); iter.Valid(); iter.NextOverlap(ltRange) {
 			l := iter.Cur()
-			if !l.isNonConflictingLock(g, g.sa) {
+			if !l.isNonConflictingLock(g, g.str) {
 				return false
 			}
 		}
@@ -589,6 +608,15 @@ func (g *lockTableGuardImpl) IsKeyLockedByConflictingTxn(
 		// The lock is empty but has not yet been deleted.
 		return false, nil
 	}
+	if l.isSharedLocked() {
+		// Key locked with Shared or Update strength.
+		for _, h := range l.sharedHolders {
+			if txn := h.txn(); !g.isSameTxn(txn) && strengthsConflict(h.str, strength) {
+				return true, txn
+			}
+		}
+		return false, nil
+	}
 	if !l.holder.locked {
 		// Key reserved.
 		if strength == lock.None {
@@ -599,6 +627,10 @@ func (g *lockTableGuardImpl) IsKeyLockedByConflictingTxn(
 			// Already reserved by this txn.
 			return false, nil
 		}
+		if !strengthsConflict(l.reservationStr, strength) {
+			// Reserved with a compatible strength.
+			return false, nil
+		}
 		// "If the key is reserved, nil is returned."
 		return true, nil
 	}
@@ -654,8 +686,8 @@ func (g *lockTableGuardImpl) isSameTxnAsReservation(ws waitingState) bool {
 // accumulate intents to resolve.
 // Acquires g.mu.
 func (g *lockTableGuardImpl) findNextLockAfter(notify bool) {
-	spans := g.spans.GetSpans(g.sa, g.ss)
-	var span *spanset.Span
+	spans := g.spans.GetSpans(g.ss, g.str)
+	var span *roachpb.Span
 	resumingInSameSpan := false
 	if g.index == -1 || len(spans[g.index].EndKey) == 0 {
 		span = stepToNextSpan(g)
@@ -700,7 +732,7 @@ func (g *lockTableGuardImpl) findNextLockAfter(notify bool) {
 				// Else, past the lock where it stopped waiting. We may not
 				// encounter that lock since it may have been garbage collected.
 			}
-			wait, transitionedToFree := l.tryActiveWait(g, g.sa, notify, g.lt.clock)
+			wait, transitionedToFree := l.tryActiveWait(g, g.str, notify, g.lt.clock)
 			if transitionedToFree {
 				locksToGC[g.ss] = append(locksToGC[g.ss], l)
 			}
@@ -762,9 +794,30 @@ func (g *lockTableGuardImpl) findNextLockAfter(notify bool) {
 // The first case above (breaking reservations) only occurs for transactional
 // requests, but the other cases can happen for both transactional and
 // non-transactional requests.
+//
+// Despite the name, queued writers include all requests that are trying to
+// acquire a lock on the key, regardless of the lock strength they are trying
+// to acquire it with.
 type queuedGuard struct {
 	guard  *lockTableGuardImpl
-	active bool // protected by lockState.mu
+	str    lock.Strength // the strength the request is trying to lock with
+	active bool          // protected by lockState.mu
+}
+
+// strengthsConflict returns whether a lock held (or reserved) with strength
+// held conflicts with a request trying to access the key with strength str.
+// Shared locks are compatible with Shared and Update locks, Update locks are
+// only compatible with Shared locks, and Exclusive locks and Intents conflict
+// with all other locks. Non-locking reads (None) only conflict with Exclusive
+// locks and Intents, subject to timestamp checks performed by the caller.
+func strengthsConflict(held, str lock.Strength) bool {
+	if held == lock.None || str == lock.None {
+		return held >= lock.Exclusive || str >= lock.Exclusive
+	}
+	if held >= lock.Exclusive || str >= lock.Exclusive {
+		return true
+	}
+	return held == lock.Update && str == lock.Update
 }
 
 // Information about a lock holder.
@@ -792,6 +845,138 @@ func (lh *lockHolderInfo) isEmpty() bool {
 	return lh.txn == nil && lh.seqs == nil && lh.ts.IsEmpty()
 }
 
+// acquire records an acquisition of the lock by txn at timestamp ts, with the
+// durability that lh corresponds to. lh may be empty.
+func (lh *lockHolderInfo) acquire(txn *enginepb.TxnMeta, ts hlc.Timestamp) {
+	seqs := lh.seqs
+	if lh.txn != nil && lh.txn.Epoch < txn.Epoch {
+		// Clear the sequences for the older epoch.
+		seqs = seqs[:0]
+	}
+	if len(seqs) > 0 && seqs[len(seqs)-1] >= txn.Sequence {
+		// Idempotent lock acquisition. In this case, we simply ignore the lock
+		// acquisition as long as it corresponds to an existing sequence number.
+		// If the sequence number is not being tracked yet, insert it into the
+		// sequence history. The validity of such a lock re-acquisition should
+		// have already been determined at the MVCC level.
+		if i := sort.Search(len(seqs), func(i int) bool {
+			return seqs[i] >= txn.Sequence
+		}); i == len(seqs) {
+			panic("lockTable bug - search value <= last element")
+		} else if seqs[i] != txn.Sequence {
+			seqs = append(seqs, 0)
+			copy(seqs[i+1:], seqs[i:])
+			seqs[i] = txn.Sequence
+			lh.seqs = seqs
+		}
+		return
+	}
+	lh.txn = txn
+	// Forward the lock's timestamp instead of assigning to it blindly.
+	// While lock acquisition uses monotonically increasing timestamps
+	// from the perspective of the transaction's coordinator, this does
+	// not guarantee that a lock will never be acquired at a higher
+	// epoch and/or sequence number but with a lower timestamp when in
+	// the presence of transaction pushes. Consider the following
+	// sequence of events:
+	//
+	//  - txn A acquires lock at sequence 1, ts 10
+	//  - txn B pushes txn A to ts 20
+	//  - txn B updates lock to ts 20
+	//  - txn A's coordinator does not immediately learn of the push
+	//  - txn A re-acquires lock at sequence 2, ts 15
+	//
+	// A lock's timestamp at a given durability level is not allowed to
+	// regress, so by forwarding its timestamp during the second acquisition
+	// instead if assigning to it blindly, it remains at 20.
+	//
+	// However, a lock's timestamp as reported by getLockHolder can regress
+	// if it is acquired at a lower timestamp and a different durability
+	// than it was previously held with. This is necessary to support
+	// because the hard constraint which we must uphold here that the
+	// lockHolderInfo for a replicated lock cannot diverge from the
+	// replicated state machine in such a way that its timestamp in the
+	// lockTable exceeds that in the replicated keyspace. If this invariant
+	// were to be violated, we'd risk infinite lock-discovery loops for
+	// requests that conflict with the lock as is written in the replicated
+	// state machine but not as is reflected in the lockTable.
+	//
+	// Lock timestamp regressions are safe from the perspective of other
+	// transactions because the request which re-acquired the lock at the
+	// lower timestamp must have been holding a write latch at or below the
+	// new lock's timestamp. This means that no conflicting requests could
+	// be evaluating concurrently. Instead, all will need to re-scan the
+	// lockTable once they acquire latches and will notice the reduced
+	// timestamp at that point, which may cause them to conflict with the
+	// lock even if they had not conflicted before. In a sense, it is no
+	// different than the first time a lock is added to the lockTable.
+	lh.ts.Forward(ts)
+	lh.seqs = append(seqs, txn.Sequence)
+}
+
+// holderFromInfos returns the TxnMeta and timestamp of the lock holder
+// described by the supplied per-durability lockHolderInfos. If the lock is
+// held as both replicated and unreplicated we want to provide the lower of the
+// two timestamps, since the lower timestamp contends with more transactions.
+// Else we provide whichever one it is held at.
+func holderFromInfos(
+	holder *[lock.MaxDurability + 1]lockHolderInfo,
+) (*enginepb.TxnMeta, hlc.Timestamp) {
+	// Start with the assumption that it is held as replicated.
+	index := lock.Replicated
+	// Condition under which we prefer the unreplicated holder.
+	if holder[index].txn == nil || (holder[lock.Unreplicated].txn != nil &&
+		// If we are evaluating the following clause we are sure that it is held
+		// as both replicated and unreplicated.
+		holder[lock.Unreplicated].ts.Less(holder[lock.Replicated].ts)) {
+		index = lock.Unreplicated
+	}
+	return holder[index].txn, holder[index].ts
+}
+
+// sharedLockHolder is a transaction holding a lock with Shared or Update
+// strength. Unlike Exclusive locks, which can only be held by a single
+// transaction, a Shared lock can be held by any number of transactions at the
+// same time, one of which may instead hold the lock with Update strength.
+//
+// Like lockState.holder, we track information for each durability level
+// separately.
+type sharedLockHolder struct {
+	// The strongest strength with which the lock has been acquired by the
+	// transaction. Rolling back the savepoint that acquired the lock with this
+	// strength does not downgrade the strength; the lock continues to be held
+	// with it until the transaction releases the lock entirely.
+	str    lock.Strength
+	holder [lock.MaxDurability + 1]lockHolderInfo
+
+	// The start time of the transaction being marked as a holder of the lock in
+	// the lock table.
+	startTime time.Time
+}
+
+// txn returns the TxnMeta of the transaction holding the lock.
+func (h *sharedLockHolder) txn() *enginepb.TxnMeta {
+	txn, _ := holderFromInfos(&h.holder)
+	return txn
+}
+
+// durability returns the strongest durability with which the lock is held.
+func (h *sharedLockHolder) durability() lock.Durability {
+	if h.holder[lock.Replicated].txn != nil {
+		return lock.Replicated
+	}
+	return lock.Unreplicated
+}
+
+func (h *sharedLockHolder) isEmpty() bool {
+	for i := range h.holder {
+		if !h.holder[i].isEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
 // Per lock state in lockTableImpl.
 //
 // NOTE: we can't easily pool lockState objects without some form of reference
@@ -815,8 +1000,11 @@ type lockState struct {
 	// - if holder.locked and multiple holderInfos have txn != nil: all the
 	//   txns must have the same txn.ID.
 	// - !holder.locked => waitingReaders.Len() == 0. That is, readers wait
-	//   only if the lock is held. They do not wait for a reservation.
+	//   only if the lock is held (with Exclusive strength). They do not wait
+	//   for a reservation or for Shared and Update locks.
 	// - If reservation != nil, that request is not in queuedWriters.
+	// - len(sharedHolders) > 0 => !holder.locked && reservation == nil. At
+	//   most one of the sharedHolders holds the lock with Update strength.
 
 	// Information about whether the lock is held and the holder. We track
 	// information for each durability level separately since a transaction can
@@ -824,7 +1012,8 @@ type lockState struct {
 	// replicated and unreplicated mode at different stages.
 	holder struct {
 		locked bool
-		// LockStrength is always Exclusive
+		// LockStrength is always Exclusive (or Intent, which the lockTable
+		// does not distinguish from Exclusive).
 		holder [lock.MaxDurability + 1]lockHolderInfo
 
 		// The start time of the lockholder being marked as held in the lock table.
@@ -836,6 +1025,21 @@ type lockState struct {
 		startTime time.Time
 	}
 
+	// The transactions holding the lock with Shared or Update strength, in the
+	// order in which they acquired it. Non-empty only if the lock is not held
+	// with Exclusive strength. A transaction holding a Shared or Update lock
+	// that goes on to acquire an Exclusive lock on the same key is moved to
+	// holder, which is only possible once it is the sole holder of the lock.
+	//
+	// NB: replicated Shared and Update locks are persisted in the replicated
+	// lock table keyspace (see storage.MVCCAcquireLock), where conflicting
+	// requests discover them during evaluation. However, discovered locks are
+	// added to the lock table as Exclusive locks, so the in-memory lock table
+	// still always remembers replicated Shared and Update locks acquired on
+	// this replica, even when uncontended, and does not clear them when under
+	// memory pressure, to preserve their compatibility with other Shared locks.
+	sharedHolders []*sharedLockHolder
+
 	// Information about the requests waiting on the lock.
 	lockWaitQueue
 
@@ -878,8 +1082,7 @@ type lockWaitQueue struct {
 	// seqnums but at another key req2 wants to read and req1 wants to write and
 	// since req2 does not wait in the queue it acquires a read reservation
 	// before req1. See the discussion at the end of this comment section on how
-	// the behavior will extend when we start supporting Shared and Update
-	// locks.
+	// the behavior extends to Shared and Update locks.
 	//
 	// Non-transactional requests can do both reads and writes but cannot be
 	// depended on since they don't have a transaction that can be pushed.
@@ -941,46 +1144,48 @@ type lockWaitQueue struct {
 	//   This is a deadlock caused by the lock table unless req2 partially
 	//   breaks the reservation at A.
 	//
-	// Extension for Shared and Update locks:
+	// Shared and Update locks:
 	// There are 3 aspects to consider: holders; reservers; the dependencies
 	// that need to be captured when waiting.
 	//
 	// - Holders: only shared locks are compatible with themselves, so there can
-	//   be one of (a) no holder (b) multiple shared lock holders, (c) one
-	//   exclusive holder, (d) one upgrade holder. Non-locking reads will
-	//   wait in waitingReaders for only an incompatible exclusive holder.
+	//   be one of (a) no holder (b) multiple shared lock holders, at most one
+	//   of which may instead hold an update lock, (c) one exclusive holder.
+	//   Shared and update lock holders are tracked in lockState.sharedHolders.
+	//   Non-locking reads will wait in waitingReaders for only an incompatible
+	//   exclusive holder, so waitingReaders is empty when the lock is held with
+	//   Shared or Update strength.
 	//
-	// - Reservers: This follows the same pattern as holders. Non-locking reads
-	//   do not wait on reservers.
+	// - Reservers: there is at most one reserver, which records the strength
+	//   it is trying to lock with. A request that is compatible with the
+	//   reserver (e.g. both are trying to acquire shared locks) does not wait
+	//   for it, and is released from the queue when the reservation is made.
+	//   Non-locking reads do not wait on reservers. There is no reservation
+	//   while the lock is held with Shared or Update strength; instead,
+	//   compatible waiters are released from the queue without one. This is
+	//   safe since a released request holds latches until it has acquired the
+	//   lock, and re-scans the lock table after acquiring latches.
 	//
 	// - Queueing and dependencies: All potential lockers and non-transactional
-	//   writers will wait in the same queue. A sequence of consecutive requests
-	//   that have the potential to acquire a shared lock will jointly reserve
-	//   that shared lock. Such requests cannot jump ahead of requests with a
-	//   lower seqnum just because there is currently a shared lock reservation
-	//   (this can cause lockTable induced deadlocks). Such joint reservations
-	//   can be partially broken by a waiter desiring an exclusive or upgrade
-	//   lock. Like the current code, non-transactional writes will wait for
-	//   reservations that have a lower sequence num, but not make their own
-	//   reservation. Additionally, they can partially break joint reservations.
+	//   writers wait in the same queue, in which each queuedGuard records the
+	//   strength it is trying to lock with. A waiter cannot jump ahead of a
+	//   conflicting waiter with a lower seqnum just because it is compatible
+	//   with the lock holder(s) (this could starve exclusive lockers). The
+	//   exception are transactions that already hold the lock and are trying
+	//   to upgrade it, which only wait for conflicting holders, since the
+	//   waiters ahead of them may be waiting for the transaction itself.
 	//
-	//   Reservations that are (partially or fully) broken cause requests to
-	//   reenter the queue as inactive waiters. This is no different than the
-	//   current behavior. Each request can specify the same key in spans for
-	//   ReadOnly, ReadShared, ReadUpgrade, ReadWrite. The spans will be
-	//   iterated over in decreasing order of strength, to only wait at a lock
-	//   at the highest strength (this is similar to the current behavior using
-	//   accessDecreasingStrength).
-	//
-	//   For dependencies, a waiter desiring an exclusive or upgrade lock always
-	//   conflicts with the holder(s) or reserver(s) so that is the dependency
-	//   that will be captured. A waiter desiring a shared lock may encounter a
-	//   situation where it does not conflict with the holder(s) or reserver(s)
-	//   since those are also shared lockers. In that case it will depend on the
-	//   first waiter since that waiter must be desiring a lock that is
-	//   incompatible with a shared lock.
+	//   For dependencies, a waiter desiring an exclusive or update lock always
+	//   conflicts with an exclusive holder or reserver, so that is the
+	//   dependency that will be captured. A waiter on a lock held with Shared
+	//   or Update strength will depend on the first conflicting holder, or if
+	//   it is compatible with all holders, on the first conflicting waiter
+	//   ahead of it in the queue (see lockState.lockConflict).
 
 	reservation *lockTableGuardImpl
+	// The strength the reservation holder is trying to lock with. Requests
+	// that are compatible with this strength do not wait for the reservation.
+	reservationStr lock.Strength
 
 	// TODO(sbhola): There are a number of places where we iterate over these
 	// lists looking for something, as described below. If some of these turn
@@ -1050,17 +1255,29 @@ func (l *lockState) safeFormat(sb *redact.StringBuilder, finalizedTxnCache *txnC
 		sb.SafeString("  empty\n")
 		return
 	}
+	// Only Shared and Update strengths are printed, since Exclusive locks and
+	// Intents are treated identically by the lockTable.
+	writeStr := func(sb *redact.StringBuilder, str lock.Strength) {
+		if str == lock.Shared || str == lock.Update {
+			sb.Printf("str: %s, ", redact.Safe(str))
+		}
+	}
 	writeResInfo := func(sb *redact.StringBuilder, txn *enginepb.TxnMeta, ts hlc.Timestamp) {
 		// TODO(sbhola): strip the leading 0 bytes from the UUID string since tests are assigning
 		// UUIDs using a counter and makes this output more readable.
 		sb.Printf("txn: %v, ts: %v, seq: %v\n",
 			redact.Safe(txn.ID), redact.Safe(ts), redact.Safe(txn.Sequence))
 	}
-	writeHolderInfo := func(sb *redact.StringBuilder, txn *enginepb.TxnMeta, ts hlc.Timestamp) {
-		sb.Printf("  holder: txn: %v, ts: %v, info: ", redact.Safe(txn.ID), redact.Safe(ts))
+	writeHolderInfo := func(
+		sb *redact.StringBuilder, holder *[lock.MaxDurability + 1]lockHolderInfo, str lock.Strength,
+	) {
+		txn, ts := holderFromInfos(holder)
+		sb.Printf("  holder: txn: %v, ts: %v, ", redact.Safe(txn.ID), redact.Safe(ts))
+		writeStr(sb, str)
+		sb.SafeString("info: ")
 		first := true
-		for i := range l.holder.holder {
-			h := &l.holder.holder[i]
+		for i := range holder {
+			h := &holder[i]
 			if h.txn == nil {
 				continue
 			}
@@ -1094,12 +1311,16 @@ func (l *lockState) safeFormat(sb *redact.StringBuilder, finalizedTxnCache *txnC
 		}
 		sb.SafeString("\n")
 	}
-	txn, ts := l.getLockHolder()
-	if txn == nil {
+	if l.isSharedLocked() {
+		for _, h := range l.sharedHolders {
+			writeHolderInfo(sb, &h.holder, h.str)
+		}
+	} else if l.holder.locked {
+		writeHolderInfo(sb, &l.holder.holder, lock.Exclusive)
+	} else {
 		sb.Printf("  res: req: %d, ", l.reservation.seqNum)
+		writeStr(sb, l.reservationStr)
 		writeResInfo(sb, l.reservation.txn, l.reservation.ts)
-	} else {
-		writeHolderInfo(sb, txn, ts)
 	}
 	// TODO(sumeer): Add an optional `description string` field to Request and
 	// lockTableGuardImpl that tests can set to avoid relying on the seqNum to
@@ -1121,7 +1342,9 @@ func (l *lockState) safeFormat(sb *redact.StringBuilder, finalizedTxnCache *txnC
 		for e := l.queuedWriters.Front(); e != nil; e = e.Next() {
 			qg := e.Value.(*queuedGuard)
 			g := qg.guard
-			sb.Printf("    active: %t req: %d, txn: ", redact.Safe(qg.active), redact.Safe(qg.guard.seqNum))
+			sb.Printf("    active: %t req: %d, ", redact.Safe(qg.active), redact.Safe(qg.guard.seqNum))
+			writeStr(sb, qg.str)
+			sb.SafeString("txn: ")
 			if g.txn == nil {
 				sb.SafeString("none\n")
 			} else {
@@ -1162,6 +1385,8 @@ func (l *lockState) collectLockStateInfo(
 // REQUIRES: l.mu is locked.
 func (l *lockState) lockStateInfo(now time.Time) roachpb.LockStateInfo {
 	var txnHolder *enginepb.TxnMeta
+	var holderStr lock.Strength
+	var otherHolders []roachpb.LockHolder
 
 	durability := lock.Unreplicated
 	if l.holder.locked {
@@ -1171,6 +1396,20 @@ func (l *lockState) lockStateInfo(now time.Time) roachpb.LockStateInfo {
 		} else if l.holder.holder[lock.Unreplicated].txn != nil {
 			txnHolder = l.holder.holder[lock.Unreplicated].txn
 		}
+	} else if l.isSharedLocked() {
+		// The first transaction to acquire the lock is reported as the lock
+		// holder and the others are reported separately.
+		first := l.sharedHolders[0]
+		txnHolder = first.txn()
+		holderStr = first.str
+		durability = first.durability()
+		for _, h := range l.sharedHolders[1:] {
+			otherHolders = append(otherHolders, roachpb.LockHolder{
+				Txn:        h.txn(),
+				Strength:   h.str,
+				Durability: h.durability(),
+			})
+		}
 	}
 
 	waiterCount := l.waitingReaders.Len() + l.queuedWriters.Len()
@@ -1186,7 +1425,7 @@ func (l *lockState) lockStateInfo(now time.Time) roachpb.LockStateInfo {
 		lockWaiters = append(lockWaiters, lock.Waiter{
 			WaitingTxn:   l.reservation.txn,
 			ActiveWaiter: true,
-			Strength:     lock.Exclusive,
+			Strength:     waiterStrength(l.reservationStr),
 			WaitDuration: now.Sub(l.reservation.mu.curLockWaitStart),
 		})
 		l.reservation.mu.Unlock()
@@ -1213,7 +1452,7 @@ func (l *lockState) lockStateInfo(now time.Time) roachpb.LockStateInfo {
 		lockWaiters = append(lockWaiters, lock.Waiter{
 			WaitingTxn:   writerGuard.txn,
 			ActiveWaiter: qg.active,
-			Strength:     lock.Exclusive,
+			Strength:     waiterStrength(qg.str),
 			WaitDuration: now.Sub(writerGuard.mu.curLockWaitStart),
 		})
 		writerGuard.mu.Unlock()
@@ -1225,9 +1464,21 @@ func (l *lockState) lockStateInfo(now time.Time) roachpb.LockStateInfo {
 		Durability:   durability,
 		HoldDuration: l.lockHeldDuration(now),
 		Waiters:      lockWaiters,
+		LockStrength: holderStr,
+		OtherHolders: otherHolders,
 	}
 }
 
+// waiterStrength returns the strength to report for a waiter trying to lock
+// with the supplied strength. Intents are reported as Exclusive locks, since
+// the lockTable does not distinguish between the two.
+func waiterStrength(str lock.Strength) lock.Strength {
+	if str == lock.Intent {
+		return lock.Exclusive
+	}
+	return str
+}
+
 // addToMetrics adds the receiver's state to the provided metrics struct.
 func (l *lockState) addToMetrics(m *LockTableMetrics, now time.Time) {
 	l.mu.Lock()
@@ -1238,7 +1489,7 @@ func (l *lockState) addToMetrics(m *LockTableMetrics, now time.Time) {
 	totalWaitDuration, maxWaitDuration := l.totalAndMaxWaitDuration(now)
 	lm := LockMetrics{
 		Key:                  l.key,
-		Held:                 l.holder.locked,
+		Held:                 l.holder.locked || l.isSharedLocked(),
 		HoldDurationNanos:    l.lockHeldDuration(now).Nanoseconds(),
 		WaitingReaders:       int64(l.waitingReaders.Len()),
 		WaitingWriters:       int64(l.queuedWriters.Len()),
@@ -1254,22 +1505,43 @@ func (l *lockState) addToMetrics(m *LockTableMetrics, now time.Time) {
 // REQUIRES: l.mu is locked.
 func (l *lockState) tryBreakReservation(seqNum uint64) bool {
 	if l.reservation.seqNum > seqNum {
-		qg := &queuedGuard{
-			guard:  l.reservation,
-			active: false,
-		}
-		l.queuedWriters.PushFront(qg)
-		l.reservation = nil
+		l.queueReservation()
 		return true
 	}
 	return false
 }
 
+// queueReservation breaks the reservation, placing its holder at the front of
+// the queue as an inactive waiter.
+// REQUIRES: l.mu is locked and l.reservation != nil.
+func (l *lockState) queueReservation() {
+	qg := &queuedGuard{
+		guard:  l.reservation,
+		str:    l.reservationStr,
+		active: false,
+	}
+	l.queuedWriters.PushFront(qg)
+	l.reservation = nil
+	l.reservationStr = lock.None
+}
+
+// setReservation makes g, which is trying to lock with strength str, the
+// reservation holder.
+// REQUIRES: l.mu is locked.
+func (l *lockState) setReservation(g *lockTableGuardImpl, str lock.Strength) {
+	l.reservation = g
+	l.reservationStr = str
+}
+
 // Informs active waiters about reservation or lock holder. The reservation
 // may have changed so this needs to fix any inconsistencies wrt waitSelf and
 // waitForDistinguished states.
 // REQUIRES: l.mu is locked.
 func (l *lockState) informActiveWaiters() {
+	if l.isSharedLocked() {
+		l.informActiveWaitersOnSharedLock()
+		return
+	}
 	waitForState := waitingState{
 		kind:          waitFor,
 		key:           l.key,
@@ -1333,31 +1605,192 @@ func (l *lockState) informActiveWaiters() {
 	}
 }
 
+// Informs active waiters about the transaction that they are waiting for when
+// the lock is held with Shared or Update strength. Unlike when the lock is held
+// with Exclusive strength, different waiters may be waiting for different
+// transactions.
+// REQUIRES: l.mu is locked and l.isSharedLocked().
+func (l *lockState) informActiveWaitersOnSharedLock() {
+	if l.distinguishedWaiter != nil {
+		if qg, ok := l.findQueuedGuard(l.distinguishedWaiter); !ok || !qg.active {
+			l.distinguishedWaiter = nil
+		}
+	}
+	for e := l.queuedWriters.Front(); e != nil; e = e.Next() {
+		qg := e.Value.(*queuedGuard)
+		if !qg.active {
+			continue
+		}
+		g := qg.guard
+		state := waitingState{
+			kind:          waitFor,
+			key:           l.key,
+			queuedWriters: l.queuedWriters.Len(),
+			guardAccess:   accessForStrength(qg.str),
+		}
+		state.txn, state.held = l.lockConflict(g, qg.str)
+		if state.txn == nil {
+			// The waiter should have been released by releaseCompatibleWriters.
+			panic("lockTable bug - active waiter does not conflict with shared lock")
+		}
+		if state.held {
+			state.otherHolders = l.otherConflictingHolders(g, qg.str, state.txn)
+		}
+		if l.distinguishedWaiter == nil {
+			l.distinguishedWaiter = g
+		}
+		if l.distinguishedWaiter == g {
+			state.kind = waitForDistinguished
+		}
+		g.mu.Lock()
+		g.updateStateLocked(state)
+		g.notify()
+		g.mu.Unlock()
+	}
+}
+
+// lockConflict returns the transaction that the request g, trying to lock
+// with strength str, needs to wait for when the lock is not held with Exclusive
+// strength. This is either a conflicting Shared or Update lock holder, the
+// reservation holder (if conflicting or from the same transaction as g), or
+// the transaction of a conflicting request ahead of g in the queue, in that
+// order. held is true iff the returned transaction holds the lock. If g does
+// not need to wait, nil is returned.
+//
+// Requests from transactions that already hold the lock do not wait for the
+// requests ahead of them in the queue, since those requests may themselves be
+// waiting for the transaction. A request ahead of g in the queue with a higher
+// seqNum (i.e. a broken reservation) is also ignored, since g would have
+// broken that reservation itself.
+// REQUIRES: l.mu is locked and !l.holder.locked.
+func (l *lockState) lockConflict(
+	g *lockTableGuardImpl, str lock.Strength,
+) (_ *enginepb.TxnMeta, held bool) {
+	var fallback *enginepb.TxnMeta
+	for _, h := range l.sharedHolders {
+		txn := h.txn()
+		if g.isSameTxn(txn) {
+			continue
+		}
+		if strengthsConflict(h.str, str) {
+			return txn, true
+		}
+		if fallback == nil {
+			fallback = txn
+		}
+	}
+	if l.reservation != nil && l.reservation != g {
+		if g.isSameTxn(l.reservation.txn) || strengthsConflict(l.reservationStr, str) {
+			return l.reservation.txn, false
+		}
+	}
+	if l.heldBySharedTxn(g) {
+		return nil, false
+	}
+	for e := l.queuedWriters.Front(); e != nil; e = e.Next() {
+		qg := e.Value.(*queuedGuard)
+		if qg.guard == g {
+			break
+		}
+		if qg.guard.seqNum > g.seqNum {
+			continue
+		}
+		if qg.guard.txn == nil {
+			// Non-transactional requests cannot be pushed, so wait for whoever
+			// it is waiting for instead.
+			if fallback != nil {
+				return fallback, true
+			}
+			return l.reservation.txn, false
+		}
+		if !g.isSameTxn(qg.guard.txn) && strengthsConflict(qg.str, str) {
+			return qg.guard.txn, false
+		}
+	}
+	return nil, false
+}
+
+// findQueuedGuard returns the queuedGuard for g, if g is in queuedWriters.
+// REQUIRES: l.mu is locked.
+func (l *lockState) findQueuedGuard(g *lockTableGuardImpl) (*queuedGuard, bool) {
+	for e := l.queuedWriters.Front(); e != nil; e = e.Next() {
+		qg := e.Value.(*queuedGuard)
+		if qg.guard == g {
+			return qg, true
+		}
+	}
+	return nil, false
+}
+
 // releaseWritersFromTxn removes all waiting writers for the lockState that are
 // part of the specified transaction.
 // REQUIRES: l.mu is locked.
 func (l *lockState) releaseWritersFromTxn(txn *enginepb.TxnMeta) {
+	l.releaseWritersFromTxnWithStrength(txn, lock.MaxStrength)
+}
+
+// releaseWritersFromTxnWithStrength removes all waiting writers for the
+// lockState that are part of the specified transaction and are trying to lock
+// with a strength no stronger than str.
+// REQUIRES: l.mu is locked.
+func (l *lockState) releaseWritersFromTxnWithStrength(
+	txn *enginepb.TxnMeta, str lock.Strength,
+) {
 	for e := l.queuedWriters.Front(); e != nil; {
 		qg := e.Value.(*queuedGuard)
 		curr := e
 		e = e.Next()
 		g := qg.guard
-		if g.isSameTxn(txn) {
-			if qg.active {
-				if g == l.distinguishedWaiter {
-					l.distinguishedWaiter = nil
-				}
-				g.doneWaitingAtLock(false, l)
-			} else {
-				g.mu.Lock()
-				delete(g.mu.locks, l)
-				g.mu.Unlock()
-			}
-			l.queuedWriters.Remove(curr)
+		if g.isSameTxn(txn) && qg.str <= str {
+			l.removeQueuedWriter(curr)
 		}
 	}
 }
 
+// removeQueuedWriter removes the supplied element of queuedWriters and tells
+// the request that it is done waiting at the lock, if it was actively waiting.
+// REQUIRES: l.mu is locked.
+func (l *lockState) removeQueuedWriter(e *list.Element) {
+	qg := e.Value.(*queuedGuard)
+	g := qg.guard
+	if qg.active {
+		if g == l.distinguishedWaiter {
+			l.distinguishedWaiter = nil
+		}
+		g.doneWaitingAtLock(false, l)
+	} else {
+		g.mu.Lock()
+		delete(g.mu.locks, l)
+		g.mu.Unlock()
+	}
+	l.queuedWriters.Remove(e)
+}
+
+// releaseCompatibleWriters removes from the queue the waiting writers that
+// no longer need to wait because they are compatible with the lock's Shared or
+// Update holders, with its reservation, and with the waiting writers ahead of
+// them in the queue (see lockConflict). Released requests re-scan the lock
+// table after acquiring latches, so two conflicting requests that are released
+// together will be ordered at that point. Returns whether any writers were
+// released.
+// REQUIRES: l.mu is locked and !l.holder.locked.
+func (l *lockState) releaseCompatibleWriters() (released bool) {
+	if l.reservation != nil && l.reservationStr >= lock.Exclusive {
+		// Fast-path: nothing is compatible with an Exclusive reservation.
+		return false
+	}
+	for e := l.queuedWriters.Front(); e != nil; {
+		qg := e.Value.(*queuedGuard)
+		curr := e
+		e = e.Next()
+		if txn, _ := l.lockConflict(qg.guard, qg.str); txn == nil {
+			l.removeQueuedWriter(curr)
+			released = true
+		}
+	}
+	return released
+}
+
 // When the active waiters have shrunk and the distinguished waiter has gone,
 // try to make a new distinguished waiter if there is at least 1 active
 // waiter.
@@ -1389,7 +1822,7 @@ func (l *lockState) tryMakeNewDistinguished() {
 // reservation.
 // REQUIRES: l.mu is locked.
 func (l *lockState) isEmptyLock() bool {
-	if !l.holder.locked && l.reservation == nil {
+	if !l.holder.locked && l.reservation == nil && !l.isSharedLocked() {
 		for i := range l.holder.holder {
 			if !l.holder.holder[i].isEmpty() {
 				panic("lockState with !locked but non-zero lockHolderInfo")
@@ -1403,6 +1836,12 @@ func (l *lockState) isEmptyLock() bool {
 	return false
 }
 
+// Returns true iff the lock is held with Shared or Update strength.
+// REQUIRES: l.mu is locked.
+func (l *lockState) isSharedLocked() bool {
+	return len(l.sharedHolders) > 0
+}
+
 // assertEmptyLock asserts that the lockState is empty. This condition must hold
 // for a lock to be safely removed from the tree. If it does not hold, requests
 // with stale snapshots of the btree will still be able to enter the lock's
@@ -1425,6 +1864,15 @@ func (l *lockState) assertEmptyLockUnlocked() {
 // Returns the duration of time the lock has been tracked as held in the lock table.
 // REQUIRES: l.mu is locked.
 func (l *lockState) lockHeldDuration(now time.Time) time.Duration {
+	if l.isSharedLocked() {
+		startTime := l.sharedHolders[0].startTime
+		for _, h := range l.sharedHolders[1:] {
+			if h.startTime.Before(startTime) {
+				startTime = h.startTime
+			}
+		}
+		return now.Sub(startTime)
+	}
 	if !l.holder.locked {
 		return time.Duration(0)
 	}
@@ -1478,29 +1926,58 @@ func (l *lockState) isLockedBy(id uuid.UUID) bool {
 	return false
 }
 
-// Returns information about the current lock holder if the lock is held, else
-// returns nil.
+// Returns information about the current lock holder if the lock is held with
+// Exclusive strength, else returns nil. Shared and Update lock holders are
+// found in l.sharedHolders.
 // REQUIRES: l.mu is locked.
 func (l *lockState) getLockHolder() (*enginepb.TxnMeta, hlc.Timestamp) {
 	if !l.holder.locked {
 		return nil, hlc.Timestamp{}
 	}
+	return holderFromInfos(&l.holder.holder)
+}
 
-	// If the lock is held as both replicated and unreplicated we want to
-	// provide the lower of the two timestamps, since the lower timestamp
-	// contends with more transactions. Else we provide whichever one it is held
-	// at.
+// Returns the index in l.sharedHolders of the transaction with the given id,
+// or -1 if the transaction does not hold the lock with Shared or Update
+// strength.
+// REQUIRES: l.mu is locked.
+func (l *lockState) findSharedHolder(id uuid.UUID) int {
+	for i, h := range l.sharedHolders {
+		if h.txn().ID == id {
+			return i
+		}
+	}
+	return -1
+}
 
-	// Start with the assumption that it is held as replicated.
-	index := lock.Replicated
-	// Condition under which we prefer the unreplicated holder.
-	if l.holder.holder[index].txn == nil || (l.holder.holder[lock.Unreplicated].txn != nil &&
-		// If we are evaluating the following clause we are sure that it is held
-		// as both replicated and unreplicated.
-		l.holder.holder[lock.Unreplicated].ts.Less(l.holder.holder[lock.Replicated].ts)) {
-		index = lock.Unreplicated
+// Returns true iff the lock is held with Shared or Update strength by the
+// transaction of the supplied request.
+// REQUIRES: l.mu is locked.
+func (l *lockState) heldBySharedTxn(g *lockTableGuardImpl) bool {
+	return g.txn != nil && l.findSharedHolder(g.txn.ID) >= 0
+}
+
+// Removes the Shared or Update lock holder at index i in l.sharedHolders.
+// REQUIRES: l.mu is locked.
+func (l *lockState) removeSharedHolder(i int) {
+	copy(l.sharedHolders[i:], l.sharedHolders[i+1:])
+	l.sharedHolders[len(l.sharedHolders)-1] = nil
+	l.sharedHolders = l.sharedHolders[:len(l.sharedHolders)-1]
+}
+
+// Called when the set of Shared or Update lock holders has changed, or a
+// request has stopped waiting in the queue. Releases waiters that are no
+// longer blocked and, if the lock is still held, informs the active waiters
+// about who they are waiting for. Returns whether the lockState can be garbage
+// collected.
+// REQUIRES: l.mu is locked and !l.holder.locked and l.reservation == nil.
+func (l *lockState) sharedHoldersChanged() (gc bool) {
+	if !l.isSharedLocked() {
+		return l.lockIsFree()
 	}
-	return l.holder.holder[index].txn, l.holder.holder[index].ts
+	l.releaseCompatibleWriters()
+	l.informActiveWaiters()
+	return false
 }
 
 // Removes the current lock holder from the lock.
@@ -1513,9 +1990,10 @@ func (l *lockState) clearLockHolder() {
 	}
 }
 
-// Decides whether the request g with access sa should actively wait at this
-// lock and if yes, adjusts the data-structures appropriately. The notify
-// parameter is true iff the request's new state channel should be notified --
+// Decides whether the request g trying to lock with strength str (lock.None
+// for non-locking reads) should actively wait at this lock and if yes, adjusts
+// the data-structures appropriately. The notify parameter is true iff the
+// request's new state channel should be notified --
 // it is set to false when the call to tryActiveWait is happening due to an
 // event for a different request or transaction (like a lock release) since in
 // that case the channel is notified first and the call to tryActiveWait()
@@ -1573,7 +2051,7 @@ func (l *lockState) clearLockHolder() {
 // The return value is true iff it is actively waiting.
 // Acquires l.mu, g.mu.
 func (l *lockState) tryActiveWait(
-	g *lockTableGuardImpl, sa spanset.SpanAccess, notify bool, clock *hlc.Clock,
+	g *lockTableGuardImpl, str lock.Strength, notify bool, clock *hlc.Clock,
 ) (wait bool, transitionedToFree bool) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -1583,6 +2061,30 @@ func (l *lockState) tryActiveWait(
 		return false, false
 	}
 
+	if l.isSharedLocked() {
+		// Shared and Update locks held by finalized transactions are released
+		// immediately, regardless of their durability, since they are not
+		// written to the replicated keyspace and so need no resolution.
+		removed := false
+		for i := 0; i < len(l.sharedHolders); {
+			if _, ok := g.lt.finalizedTxnCache.get(l.sharedHolders[i].txn().ID); ok {
+				l.removeSharedHolder(i)
+				removed = true
+			} else {
+				i++
+			}
+		}
+		if removed && l.sharedHoldersChanged() {
+			// Empty lock.
+			return false, true
+		}
+		if l.isSharedLocked() {
+			return l.tryActiveWaitOnSharedLock(g, str, notify, clock), false
+		}
+		// There is a reservation holder, which may be the caller itself, so fall
+		// through to the processing below.
+	}
+
 	// Lock is not empty.
 	lockHolderTxn, lockHolderTS := l.getLockHolder()
 	if lockHolderTxn != nil && g.isSameTxn(lockHolderTxn) {
@@ -1610,7 +2112,7 @@ func (l *lockState) tryActiveWait(
 		}
 	}
 
-	if sa == spanset.SpanReadOnly {
+	if str == lock.None {
 		if lockHolderTxn == nil {
 			// Reads only care about locker, not a reservation.
 			return false, false
@@ -1620,14 +2122,15 @@ func (l *lockState) tryActiveWait(
 			return false, false
 		}
 		g.mu.Lock()
-		_, alsoHasStrongerAccess := g.mu.locks[l]
+		_, alsoLocksWithHigherStrength := g.mu.locks[l]
 		g.mu.Unlock()
 
 		// If the request already has this lock in its locks map, it must also be
-		// writing to this key and must be either a reservation holder or inactive
-		// waiter at this lock. The former has already been handled above. For the
-		// latter, it must have had its reservation broken. Since this is a weaker
-		// access we defer to the stronger access and don't wait here.
+		// acquiring this lock at a higher strength and must be either a
+		// reservation holder or inactive waiter at this lock. The former has
+		// already been handled above. For the latter, it must have had its
+		// reservation broken. Since this is a weaker access we defer to the
+		// stronger access and don't wait here.
 		//
 		// For non-transactional requests that have the key specified as both
 		// SpanReadOnly and SpanReadWrite, the request never acquires a
@@ -1635,7 +2138,7 @@ func (l *lockState) tryActiveWait(
 		// key is not possible. In the rare case, the lock is now held at a
 		// timestamp that is not compatible with this request and it will wait
 		// here -- there is no correctness issue with doing that.
-		if alsoHasStrongerAccess {
+		if alsoLocksWithHigherStrength {
 			return false, false
 		}
 	}
@@ -1645,7 +2148,7 @@ func (l *lockState) tryActiveWait(
 		key:           l.key,
 		queuedWriters: l.queuedWriters.Len(),
 		queuedReaders: l.waitingReaders.Len(),
-		guardAccess:   sa,
+		guardAccess:   accessForStrength(str),
 	}
 	if lockHolderTxn != nil {
 		waitForState.txn = lockHolderTxn
@@ -1657,20 +2160,27 @@ func (l *lockState) tryActiveWait(
 		}
 		// A non-transactional write request never makes or breaks reservations,
 		// and only waits for a reservation if the reservation has a lower
-		// seqNum. Note that `sa == spanset.SpanRead && lockHolderTxn == nil`
-		// was already checked above.
+		// seqNum. Note that `str == lock.None && lockHolderTxn == nil` was
+		// already checked above.
 		if g.txn == nil && l.reservation.seqNum > g.seqNum {
 			// Reservation is held by a request with a higher seqNum and g is a
 			// non-transactional request. Ignore the reservation.
 			return false, false
 		}
+		if txn, _ := l.lockConflict(g, str); txn == nil {
+			// The reservation is held with a compatible strength (e.g. both are
+			// trying to acquire Shared locks) and no conflicting request is
+			// ahead of this request in the queue, so there is no need to wait.
+			l.removeInactiveWriter(g, str)
+			return false, false
+		}
 		waitForState.txn = l.reservation.txn
 	}
 
 	// Incompatible with whoever is holding lock or reservation.
 
-	if l.reservation != nil && sa == spanset.SpanReadWrite && l.tryBreakReservation(g.seqNum) {
-		l.reservation = g
+	if l.reservation != nil && str != lock.None && l.tryBreakReservation(g.seqNum) {
+		l.setReservation(g, str)
 		g.mu.Lock()
 		g.mu.locks[l] = struct{}{}
 		g.mu.Unlock()
@@ -1685,65 +2195,12 @@ func (l *lockState) tryActiveWait(
 	wait = true
 	g.mu.Lock()
 	defer g.mu.Unlock()
-	if sa == spanset.SpanReadWrite {
-		var qg *queuedGuard
-		if _, inQueue := g.mu.locks[l]; inQueue {
-			// Already in queue and must be in the right position, so mark as active
-			// waiter there. We expect this to be rare.
-			for e := l.queuedWriters.Front(); e != nil; e = e.Next() {
-				qqg := e.Value.(*queuedGuard)
-				if qqg.guard == g {
-					qg = qqg
-					break
-				}
-			}
-			if qg == nil {
-				panic("lockTable bug")
-			}
-			// Tentative. See below.
-			qg.active = true
-		} else {
-			// Not in queue so insert as active waiter. The active waiter
-			// designation is tentative (see below).
-			qg = &queuedGuard{
-				guard:  g,
-				active: true,
-			}
-			if curLen := l.queuedWriters.Len(); curLen == 0 {
-				l.queuedWriters.PushFront(qg)
-			} else if g.maxWaitQueueLength > 0 && curLen >= g.maxWaitQueueLength {
-				// The wait-queue is longer than the request is willing to wait for.
-				// Instead of entering the queue, immediately reject the request. For
-				// simplicity, we are not finding the position of this writer in the
-				// queue and rejecting the tail of the queue above the max length. That
-				// would be more fair, but more complicated, and we expect that the
-				// common case is that this waiter will be at the end of the queue.
-				g.mu.startWait = true
-				state := waitForState
-				state.kind = waitQueueMaxLengthExceeded
-				g.updateStateLocked(state)
-				if notify {
-					g.notify()
-				}
-				// NOTE: we return wait=true not because the request is waiting, but
-				// because it should not continue scanning for conflicting locks.
-				return true, false
-			} else {
-				var e *list.Element
-				for e = l.queuedWriters.Back(); e != nil; e = e.Prev() {
-					qqg := e.Value.(*queuedGuard)
-					if qqg.guard.seqNum < qg.guard.seqNum {
-						break
-					}
-				}
-				if e == nil {
-					l.queuedWriters.PushFront(qg)
-				} else {
-					l.queuedWriters.InsertAfter(qg, e)
-				}
-			}
-			g.mu.locks[l] = struct{}{}
-			waitForState.queuedWriters = l.queuedWriters.Len() // update field
+	if str != lock.None {
+		qg := l.enqueueLockingRequest(g, str, &waitForState, notify)
+		if qg == nil {
+			// NOTE: we return wait=true not because the request is waiting, but
+			// because it should not continue scanning for conflicting locks.
+			return true, false
 		}
 		if replicatedLockFinalizedTxn != nil && l.queuedWriters.Front().Value.(*queuedGuard) == qg {
 			// First waiter, so should not wait. NB: this inactive waiter can be
@@ -1767,7 +2224,171 @@ func (l *lockState) tryActiveWait(
 			g.toResolve, roachpb.MakeLockUpdate(replicatedLockFinalizedTxn, roachpb.Span{Key: l.key}))
 		return false, false
 	}
-	// Make it an active waiter.
+	l.startActiveWait(g, waitForState, notify, clock)
+	return true, false
+}
+
+// Decides whether the request g trying to lock with strength str should
+// actively wait at this lock, which is held with Shared or Update strength,
+// and if yes, adjusts the data-structures appropriately. See tryActiveWait.
+//
+// Non-locking reads never wait on Shared or Update locks. A locking request
+// waits if it conflicts with one of the lock holders, or with a request ahead
+// of it in the queue (see lockConflict). Otherwise it proceeds to evaluation
+// without a reservation, which is safe because it holds latches until it has
+// acquired the lock.
+//
+// The return value is true iff it is actively waiting.
+// REQUIRES: l.mu is locked and l.isSharedLocked().
+// Acquires g.mu.
+func (l *lockState) tryActiveWaitOnSharedLock(
+	g *lockTableGuardImpl, str lock.Strength, notify bool, clock *hlc.Clock,
+) (wait bool) {
+	if str == lock.None {
+		return false
+	}
+	txn, held := l.lockConflict(g, str)
+	if txn == nil {
+		// Compatible with the lock holders and with the requests ahead of it.
+		l.removeInactiveWriter(g, str)
+		return false
+	}
+	waitForState := waitingState{
+		kind:          waitFor,
+		txn:           txn,
+		key:           l.key,
+		held:          held,
+		queuedWriters: l.queuedWriters.Len(),
+		guardAccess:   accessForStrength(str),
+	}
+	if held {
+		waitForState.otherHolders = l.otherConflictingHolders(g, str, txn)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if qg := l.enqueueLockingRequest(g, str, &waitForState, notify); qg == nil {
+		// NOTE: we return wait=true not because the request is waiting, but
+		// because it should not continue scanning for conflicting locks.
+		return true
+	}
+	l.startActiveWait(g, waitForState, notify, clock)
+	return true
+}
+
+// otherConflictingHolders returns the transactions other than txn holding the
+// lock with Shared or Update strength that the request g, trying to lock with
+// strength str, conflicts with.
+// REQUIRES: l.mu is locked.
+func (l *lockState) otherConflictingHolders(
+	g *lockTableGuardImpl, str lock.Strength, txn *enginepb.TxnMeta,
+) []*enginepb.TxnMeta {
+	var txns []*enginepb.TxnMeta
+	for _, h := range l.sharedHolders {
+		other := h.txn()
+		if other.ID == txn.ID || g.isSameTxn(other) || !strengthsConflict(h.str, str) {
+			continue
+		}
+		txns = append(txns, other)
+	}
+	return txns
+}
+
+// removeInactiveWriter removes the request g from queuedWriters if it is an
+// inactive waiter there that no longer needs to wait to lock with strength
+// str. Waiters trying to lock with a higher strength are left in place; the
+// request is only scanning the lock at a lower strength because it also
+// specified the key at that strength.
+// REQUIRES: l.mu is locked.
+func (l *lockState) removeInactiveWriter(g *lockTableGuardImpl, str lock.Strength) {
+	for e := l.queuedWriters.Front(); e != nil; e = e.Next() {
+		qg := e.Value.(*queuedGuard)
+		if qg.guard == g {
+			if !qg.active && qg.str <= str {
+				l.removeQueuedWriter(e)
+			}
+			return
+		}
+	}
+}
+
+// enqueueLockingRequest inserts the request g, trying to lock with strength
+// str, into queuedWriters as an active waiter, or marks it as an active waiter
+// if it is already in the queue. The active waiter designation is tentative;
+// the caller may revert it. If the request is not already in the queue and
+// the queue's length is equal to or exceeds the request's configured maximum,
+// the request is rejected: nil is returned and the request's state is updated
+// to waitQueueMaxLengthExceeded.
+// REQUIRES: l.mu and g.mu are locked.
+func (l *lockState) enqueueLockingRequest(
+	g *lockTableGuardImpl, str lock.Strength, waitForState *waitingState, notify bool,
+) *queuedGuard {
+	var qg *queuedGuard
+	if _, inQueue := g.mu.locks[l]; inQueue {
+		// Already in queue and must be in the right position, so mark as active
+		// waiter there. We expect this to be rare.
+		for e := l.queuedWriters.Front(); e != nil; e = e.Next() {
+			qqg := e.Value.(*queuedGuard)
+			if qqg.guard == g {
+				qg = qqg
+				break
+			}
+		}
+		if qg == nil {
+			panic("lockTable bug")
+		}
+		// Tentative. See below.
+		qg.active = true
+		return qg
+	}
+	// Not in queue so insert as active waiter. The active waiter
+	// designation is tentative (see below).
+	qg = &queuedGuard{
+		guard:  g,
+		str:    str,
+		active: true,
+	}
+	if curLen := l.queuedWriters.Len(); curLen == 0 {
+		l.queuedWriters.PushFront(qg)
+	} else if g.maxWaitQueueLength > 0 && curLen >= g.maxWaitQueueLength {
+		// The wait-queue is longer than the request is willing to wait for.
+		// Instead of entering the queue, immediately reject the request. For
+		// simplicity, we are not finding the position of this writer in the
+		// queue and rejecting the tail of the queue above the max length. That
+		// would be more fair, but more complicated, and we expect that the
+		// common case is that this waiter will be at the end of the queue.
+		g.mu.startWait = true
+		state := *waitForState
+		state.kind = waitQueueMaxLengthExceeded
+		g.updateStateLocked(state)
+		if notify {
+			g.notify()
+		}
+		return nil
+	} else {
+		var e *list.Element
+		for e = l.queuedWriters.Back(); e != nil; e = e.Prev() {
+			qqg := e.Value.(*queuedGuard)
+			if qqg.guard.seqNum < qg.guard.seqNum {
+				break
+			}
+		}
+		if e == nil {
+			l.queuedWriters.PushFront(qg)
+		} else {
+			l.queuedWriters.InsertAfter(qg, e)
+		}
+	}
+	g.mu.locks[l] = struct{}{}
+	waitForState.queuedWriters = l.queuedWriters.Len() // update field
+	return qg
+}
+
+// startActiveWait makes the request g an active waiter at this lock, waiting
+// in the supplied state.
+// REQUIRES: l.mu and g.mu are locked.
+func (l *lockState) startActiveWait(
+	g *lockTableGuardImpl, waitForState waitingState, notify bool, clock *hlc.Clock,
+) {
 	g.key = l.key
 	g.mu.startWait = true
 	g.mu.curLockWaitStart = clock.PhysicalTime()
@@ -1786,10 +2407,9 @@ func (l *lockState) tryActiveWait(
 	if notify {
 		g.notify()
 	}
-	return true, false
 }
 
-func (l *lockState) isNonConflictingLock(g *lockTableGuardImpl, sa spanset.SpanAccess) bool {
+func (l *lockState) isNonConflictingLock(g *lockTableGuardImpl, str lock.Strength) bool {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -1797,6 +2417,16 @@ func (l *lockState) isNonConflictingLock(g *lockTableGuardImpl, sa spanset.SpanA
 	if l.isEmptyLock() {
 		return true
 	}
+	if l.isSharedLocked() {
+		// Only conflicting holders matter. Like reservation holders (see
+		// below), requests ahead in the queue are non-conflicting.
+		for _, h := range l.sharedHolders {
+			if !g.isSameTxn(h.txn()) && strengthsConflict(h.str, str) {
+				return false
+			}
+		}
+		return true
+	}
 	// Lock is not empty.
 	lockHolderTxn, lockHolderTS := l.getLockHolder()
 	if lockHolderTxn == nil {
@@ -1821,7 +2451,7 @@ func (l *lockState) isNonConflictingLock(g *lockTableGuardImpl, sa spanset.SpanA
 	// path. A conflict with a finalized txn will be noticed when retrying
 	// pessimistically.
 
-	if sa == spanset.SpanReadOnly && g.ts.Less(lockHolderTS) {
+	if str == lock.None && g.ts.Less(lockHolderTS) {
 		return true
 	}
 	// Conflicts.
@@ -1833,7 +2463,7 @@ func (l *lockState) isNonConflictingLock(g *lockTableGuardImpl, sa spanset.SpanA
 // that is acquiring the lock.
 // Acquires l.mu.
 func (l *lockState) acquireLock(
-	_ lock.Strength,
+	str lock.Strength,
 	durability lock.Durability,
 	txn *enginepb.TxnMeta,
 	ts hlc.Timestamp,
@@ -1841,76 +2471,21 @@ func (l *lockState) acquireLock(
 ) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	if l.isSharedLocked() && str != lock.Shared && str != lock.Update {
+		// Upgrading a Shared or Update lock to an Exclusive lock.
+		if err := l.promoteSharedHolder(txn); err != nil {
+			return err
+		}
+	}
 	if l.holder.locked {
 		// Already held.
 		beforeTxn, beforeTs := l.getLockHolder()
 		if txn.ID != beforeTxn.ID {
 			return errors.AssertionFailedf("existing lock cannot be acquired by different transaction")
 		}
-		seqs := l.holder.holder[durability].seqs
-		if l.holder.holder[durability].txn != nil && l.holder.holder[durability].txn.Epoch < txn.Epoch {
-			// Clear the sequences for the older epoch.
-			seqs = seqs[:0]
-		}
-		if len(seqs) > 0 && seqs[len(seqs)-1] >= txn.Sequence {
-			// Idempotent lock acquisition. In this case, we simply ignore the lock
-			// acquisition as long as it corresponds to an existing sequence number.
-			// If the sequence number is not being tracked yet, insert it into the
-			// sequence history. The validity of such a lock re-acquisition should
-			// have already been determined at the MVCC level.
-			if i := sort.Search(len(seqs), func(i int) bool {
-				return seqs[i] >= txn.Sequence
-			}); i == len(seqs) {
-				panic("lockTable bug - search value <= last element")
-			} else if seqs[i] != txn.Sequence {
-				seqs = append(seqs, 0)
-				copy(seqs[i+1:], seqs[i:])
-				seqs[i] = txn.Sequence
-				l.holder.holder[durability].seqs = seqs
-			}
-			return nil
-		}
-		l.holder.holder[durability].txn = txn
-		// Forward the lock's timestamp instead of assigning to it blindly.
-		// While lock acquisition uses monotonically increasing timestamps
-		// from the perspective of the transaction's coordinator, this does
-		// not guarantee that a lock will never be acquired at a higher
-		// epoch and/or sequence number but with a lower timestamp when in
-		// the presence of transaction pushes. Consider the following
-		// sequence of events:
-		//
-		//  - txn A acquires lock at sequence 1, ts 10
-		//  - txn B pushes txn A to ts 20
-		//  - txn B updates lock to ts 20
-		//  - txn A's coordinator does not immediately learn of the push
-		//  - txn A re-acquires lock at sequence 2, ts 15
-		//
-		// A lock's timestamp at a given durability level is not allowed to
-		// regress, so by forwarding its timestamp during the second acquisition
-		// instead if assigning to it blindly, it remains at 20.
-		//
-		// However, a lock's timestamp as reported by getLockHolder can regress
-		// if it is acquired at a lower timestamp and a different durability
-		// than it was previously held with. This is necessary to support
-		// because the hard constraint which we must uphold here that the
-		// lockHolderInfo for a replicated lock cannot diverge from the
-		// replicated state machine in such a way that its timestamp in the
-		// lockTable exceeds that in the replicated keyspace. If this invariant
-		// were to be violated, we'd risk infinite lock-discovery loops for
-		// requests that conflict with the lock as is written in the replicated
-		// state machine but not as is reflected in the lockTable.
-		//
-		// Lock timestamp regressions are safe from the perspective of other
-		// transactions because the request which re-acquired the lock at the
-		// lower timestamp must have been holding a write latch at or below the
-		// new lock's timestamp. This means that no conflicting requests could
-		// be evaluating concurrently. Instead, all will need to re-scan the
-		// lockTable once they acquire latches and will notice the reduced
-		// timestamp at that point, which may cause them to conflict with the
-		// lock even if they had not conflicted before. In a sense, it is no
-		// different than the first time a lock is added to the lockTable.
-		l.holder.holder[durability].ts.Forward(ts)
-		l.holder.holder[durability].seqs = append(seqs, txn.Sequence)
+		// NB: a Shared or Update lock acquired by the transaction holding the
+		// lock with Exclusive strength is subsumed by the Exclusive lock.
+		l.holder.holder[durability].acquire(txn, ts)
 
 		_, afterTs := l.getLockHolder()
 		if beforeTs.Less(afterTs) {
@@ -1918,6 +2493,9 @@ func (l *lockState) acquireLock(
 		}
 		return nil
 	}
+	if str == lock.Shared || str == lock.Update {
+		return l.acquireSharedLock(str, durability, txn, ts, clock)
+	}
 	// Not already held, so may have been reserved by this request. There is also
 	// the possibility that some other request has broken this reservation because
 	// of a concurrent release but that is harmless since this request is
@@ -1925,11 +2503,7 @@ func (l *lockState) acquireLock(
 	if l.reservation != nil {
 		if l.reservation.txn.ID != txn.ID {
 			// Reservation is broken.
-			qg := &queuedGuard{
-				guard:  l.reservation,
-				active: false,
-			}
-			l.queuedWriters.PushFront(qg)
+			l.queueReservation()
 		} else {
 			// Else, reservation is not broken, or broken by a different request
 			// from the same transaction. In the latter case, both requests are not
@@ -1940,6 +2514,8 @@ func (l *lockState) acquireLock(
 			l.reservation.mu.Lock()
 			delete(l.reservation.mu.locks, l)
 			l.reservation.mu.Unlock()
+			l.reservation = nil
+			l.reservationStr = lock.None
 		}
 		if l.waitingReaders.Len() > 0 {
 			panic("lockTable bug")
@@ -1949,7 +2525,6 @@ func (l *lockState) acquireLock(
 			panic("lockTable bug")
 		}
 	}
-	l.reservation = nil
 	l.holder.locked = true
 	l.holder.holder[durability].txn = txn
 	l.holder.holder[durability].ts = ts
@@ -1964,14 +2539,97 @@ func (l *lockState) acquireLock(
 	return nil
 }
 
+// Acquires this lock with Shared or Update strength on behalf of txn. The lock
+// may already be held by txn, or by other transactions with a compatible
+// strength.
+// REQUIRES: l.mu is locked and !l.holder.locked.
+func (l *lockState) acquireSharedLock(
+	str lock.Strength,
+	durability lock.Durability,
+	txn *enginepb.TxnMeta,
+	ts hlc.Timestamp,
+	clock *hlc.Clock,
+) error {
+	// There is no reservation while the lock is held. Like in acquireLock, a
+	// reservation may have been broken by a concurrent release, which is
+	// harmless.
+	if l.reservation != nil {
+		if l.reservation.txn.ID != txn.ID {
+			// Reservation is broken. The reservation holder may no longer need to
+			// wait if it is compatible with the lock; see releaseCompatibleWriters
+			// below.
+			l.queueReservation()
+		} else {
+			l.reservation.mu.Lock()
+			delete(l.reservation.mu.locks, l)
+			l.reservation.mu.Unlock()
+			l.reservation = nil
+			l.reservationStr = lock.None
+		}
+	}
+	if l.waitingReaders.Len() > 0 {
+		panic("lockTable bug")
+	}
+	var h *sharedLockHolder
+	if i := l.findSharedHolder(txn.ID); i >= 0 {
+		h = l.sharedHolders[i]
+		if h.str > str {
+			str = h.str
+		}
+	}
+	for _, other := range l.sharedHolders {
+		if other != h && strengthsConflict(other.str, str) {
+			return errors.AssertionFailedf(
+				"existing %s lock cannot be acquired with %s strength by a different transaction",
+				other.str, str)
+		}
+	}
+	if h == nil {
+		h = &sharedLockHolder{startTime: clock.PhysicalTime()}
+		l.sharedHolders = append(l.sharedHolders, h)
+	}
+	h.str = str
+	h.holder[durability].acquire(txn, ts)
+
+	// If there are waiting requests from the same txn that are not trying to
+	// lock with a higher strength, they no longer need to wait.
+	l.releaseWritersFromTxnWithStrength(txn, h.str)
+	// Other waiters may be compatible with the lock holders. The rest need to
+	// be told about who they are waiting for.
+	_ = l.sharedHoldersChanged()
+	return nil
+}
+
+// Upgrades the Shared or Update lock held by txn to an Exclusive lock by
+// moving its lock holder information to l.holder. This is only possible if
+// txn is the sole holder of the lock.
+// REQUIRES: l.mu is locked and l.isSharedLocked().
+func (l *lockState) promoteSharedHolder(txn *enginepb.TxnMeta) error {
+	i := l.findSharedHolder(txn.ID)
+	if i < 0 || len(l.sharedHolders) > 1 {
+		return errors.AssertionFailedf(
+			"existing lock cannot be acquired with Exclusive strength while held by a different transaction")
+	}
+	h := l.sharedHolders[i]
+	l.removeSharedHolder(i)
+	l.holder.locked = true
+	l.holder.holder = h.holder
+	l.holder.startTime = h.startTime
+	// If there are waiting requests from the same txn, they no longer need to
+	// wait. The remaining waiters are now waiting for the Exclusive lock holder.
+	l.releaseWritersFromTxn(txn)
+	l.informActiveWaiters()
+	return nil
+}
+
 // A replicated lock held by txn with timestamp ts was discovered by guard g
-// where g is trying to access this key with access sa.
+// where g is trying to access this key with strength str.
 // Acquires l.mu.
 func (l *lockState) discoveredLock(
 	txn *enginepb.TxnMeta,
 	ts hlc.Timestamp,
 	g *lockTableGuardImpl,
-	sa spanset.SpanAccess,
+	str lock.Strength,
 	notRemovable bool,
 	clock *hlc.Clock,
 ) error {
@@ -1981,6 +2639,18 @@ func (l *lockState) discoveredLock(
 	if notRemovable {
 		l.notRemovable++
 	}
+	if l.isSharedLocked() {
+		// The discovered lock is an intent, so the lock must be held with
+		// Exclusive strength by txn.
+		if l.findSharedHolder(txn.ID) < 0 || len(l.sharedHolders) > 1 {
+			return errors.AssertionFailedf(
+				"discovered lock by different transaction (%s) than existing shared lock: %s",
+				txn, l)
+		}
+		if err := l.promoteSharedHolder(txn); err != nil {
+			return err
+		}
+	}
 	if l.holder.locked {
 		if !l.isLockedBy(txn.ID) {
 			return errors.AssertionFailedf(
@@ -2004,16 +2674,10 @@ func (l *lockState) discoveredLock(
 	// the comment in acquireLock()), (b) g may be a non-transactional
 	// request (read or write) that can ignore the reservation.
 	if l.reservation != nil {
-		qg := &queuedGuard{
-			guard:  l.reservation,
-			active: false,
-		}
-		l.queuedWriters.PushFront(qg)
-		l.reservation = nil
+		l.queueReservation()
 	}
 
-	switch sa {
-	case spanset.SpanReadOnly:
+	if str == lock.None {
 		// Don't enter the lock's queuedReaders list, because all queued readers
 		// are expected to be active. Instead, wait until the next scan.
 
@@ -2024,8 +2688,7 @@ func (l *lockState) discoveredLock(
 		if g.ts.Less(ts) {
 			return errors.AssertionFailedf("discovered non-conflicting lock")
 		}
-
-	case spanset.SpanReadWrite:
+	} else {
 		// Immediately enter the lock's queuedWriters list.
 		// NB: this inactive waiter can be non-transactional.
 		g.mu.Lock()
@@ -2040,6 +2703,7 @@ func (l *lockState) discoveredLock(
 			// Put self in queue as inactive waiter.
 			qg := &queuedGuard{
 				guard:  g,
+				str:    str,
 				active: false,
 			}
 			// g is not necessarily first in the queue in the (rare) case (a) above.
@@ -2082,6 +2746,15 @@ func (l *lockState) tryClearLock(force bool) bool {
 	if l.notRemovable > 0 && !force {
 		return false
 	}
+	if !force {
+		// Replicated Shared and Update locks cannot be recovered from
+		// persistent storage, so they are not cleared.
+		for _, h := range l.sharedHolders {
+			if h.holder[lock.Replicated].txn != nil {
+				return false
+			}
+		}
+	}
 
 	// Clear lock holder. While doing so, determine which waitingState to
 	// transition waiters to.
@@ -2104,6 +2777,7 @@ func (l *lockState) tryClearLock(force bool) bool {
 		waitState = waitingState{kind: doneWaiting}
 	}
 	l.clearLockHolder()
+	l.sharedHolders = nil
 
 	// Clear reservation.
 	if l.reservation != nil {
@@ -2112,6 +2786,7 @@ func (l *lockState) tryClearLock(force bool) bool {
 		delete(g.mu.locks, l)
 		g.mu.Unlock()
 		l.reservation = nil
+		l.reservationStr = lock.None
 	}
 
 	// Clear waitingReaders.
@@ -2129,7 +2804,6 @@ func (l *lockState) tryClearLock(force bool) bool {
 	}
 
 	// Clear queuedWriters.
-	waitState.guardAccess = spanset.SpanReadWrite
 	for e := l.queuedWriters.Front(); e != nil; {
 		qg := e.Value.(*queuedGuard)
 		curr := e
@@ -2139,6 +2813,7 @@ func (l *lockState) tryClearLock(force bool) bool {
 		g := qg.guard
 		g.mu.Lock()
 		if qg.active {
+			waitState.guardAccess = accessForStrength(qg.str)
 			g.updateStateLocked(waitState)
 			g.notify()
 		}
@@ -2185,6 +2860,9 @@ func (l *lockState) tryUpdateLock(up *roachpb.LockUpdate) (heldByTxn, gc bool) {
 		// tryActiveWait due to the txn being in the finalizedTxnCache.
 		return false, true
 	}
+	if l.isSharedLocked() {
+		return l.tryUpdateSharedLock(up)
+	}
 	if !l.isLockedBy(up.Txn.ID) {
 		return false, false
 	}
@@ -2194,13 +2872,62 @@ func (l *lockState) tryUpdateLock(up *roachpb.LockUpdate) (heldByTxn, gc bool) {
 		return true, gc
 	}
 
-	txn := &up.Txn
 	ts := up.Txn.WriteTimestamp
 	_, beforeTs := l.getLockHolder()
 	advancedTs := beforeTs.Less(ts)
-	isLocked := false
-	for i := range l.holder.holder {
-		holder := &l.holder.holder[i]
+	isLocked := updateHolderInfos(&l.holder.holder, up, advancedTs)
+
+	if !isLocked {
+		l.clearLockHolder()
+		gc = l.lockIsFree()
+		return true, gc
+	}
+
+	if advancedTs {
+		l.increasedLockTs(ts)
+	}
+	// Else no change for waiters. This can happen due to a race between different
+	// callers of UpdateLocks().
+
+	return true, false
+}
+
+// Like tryUpdateLock, but for a lock held with Shared or Update strength.
+// Shared and Update locks do not block non-locking reads, so an increase in
+// the timestamp of the lock does not affect waiters.
+// REQUIRES: l.mu is locked and l.isSharedLocked().
+func (l *lockState) tryUpdateSharedLock(up *roachpb.LockUpdate) (heldByTxn, gc bool) {
+	i := l.findSharedHolder(up.Txn.ID)
+	if i < 0 {
+		return false, false
+	}
+	h := l.sharedHolders[i]
+	if up.Status.IsFinalized() {
+		l.removeSharedHolder(i)
+	} else {
+		_, beforeTs := holderFromInfos(&h.holder)
+		if !updateHolderInfos(&h.holder, up, beforeTs.Less(up.Txn.WriteTimestamp)) {
+			l.removeSharedHolder(i)
+		} else {
+			// The set of holders has not changed.
+			return true, false
+		}
+	}
+	return true, l.sharedHoldersChanged()
+}
+
+// updateHolderInfos updates the supplied per-durability lockHolderInfos of a
+// transaction holding a lock in accordance with the LockUpdate for that
+// transaction, which is not finalized. advancedTs is true iff the update
+// advances the timestamp of the lock. Returns whether the lock is still held
+// by the transaction.
+func updateHolderInfos(
+	holders *[lock.MaxDurability + 1]lockHolderInfo, up *roachpb.LockUpdate, advancedTs bool,
+) (isLocked bool) {
+	txn := &up.Txn
+	ts := up.Txn.WriteTimestamp
+	for i := range holders {
+		holder := &holders[i]
 		if holder.txn == nil {
 			continue
 		}
@@ -2245,20 +2972,7 @@ func (l *lockState) tryUpdateLock(up *roachpb.LockUpdate) (heldByTxn, gc bool) {
 		// potentially updated.
 		isLocked = true
 	}
-
-	if !isLocked {
-		l.clearLockHolder()
-		gc = l.lockIsFree()
-		return true, gc
-	}
-
-	if advancedTs {
-		l.increasedLockTs(ts)
-	}
-	// Else no change for waiters. This can happen due to a race between different
-	// callers of UpdateLocks().
-
-	return true, false
+	return isLocked
 }
 
 // The lock holder timestamp has increased. Some of the waiters may no longer
@@ -2307,6 +3021,7 @@ func (l *lockState) requestDone(g *lockTableGuardImpl) (gc bool) {
 
 	if l.reservation == g {
 		l.reservation = nil
+		l.reservationStr = lock.None
 		return l.lockIsFree()
 	}
 	// May be in queuedWriters or waitingReaders.
@@ -2341,6 +3056,17 @@ func (l *lockState) requestDone(g *lockTableGuardImpl) (gc bool) {
 	if !doneRemoval {
 		panic("lockTable bug")
 	}
+	if l.isSharedLocked() {
+		// Requests that were waiting behind g may no longer need to wait, and
+		// the others may be waiting for a different transaction now.
+		return l.sharedHoldersChanged()
+	}
+	if !l.holder.locked && l.releaseCompatibleWriters() {
+		// Requests that were waiting behind g for a compatible reservation no
+		// longer need to wait.
+		l.informActiveWaiters()
+		return false
+	}
 	if distinguishedRemoved {
 		l.tryMakeNewDistinguished()
 	}
@@ -2391,7 +3117,7 @@ func (l *lockState) tryFreeLockOnReplicatedAcquire() bool {
 // waiters, but there cannot be a reservation.
 // REQUIRES: l.mu is locked.
 func (l *lockState) lockIsFree() (gc bool) {
-	if l.holder.locked {
+	if l.holder.locked || l.isSharedLocked() {
 		panic("called lockIsFree on lock with holder")
 	}
 	if l.reservation != nil {
@@ -2444,7 +3170,7 @@ func (l *lockState) lockIsFree() (gc bool) {
 	e := l.queuedWriters.Front()
 	qg := e.Value.(*queuedGuard)
 	g := qg.guard
-	l.reservation = g
+	l.setReservation(g, qg.str)
 	l.queuedWriters.Remove(e)
 	if qg.active {
 		if g == l.distinguishedWaiter {
@@ -2454,6 +3180,10 @@ func (l *lockState) lockIsFree() (gc bool) {
 	}
 	// Else inactive waiter and is waiting elsewhere.
 
+	// Waiting writers that are compatible with the reservation, e.g. because
+	// both are trying to acquire Shared locks, don't need to wait either.
+	l.releaseCompatibleWriters()
+
 	// Tell the active waiters who they are waiting for.
 	l.informActiveWaiters()
 	return false
@@ -2506,7 +3236,7 @@ func (t *lockTableImpl) ScanAndEnqueue(req Request, guard lockTableGuard) lockTa
 	} else {
 		g = guard.(*lockTableGuardImpl)
 		g.key = nil
-		g.sa = spanset.NumSpanAccess - 1
+		g.str = lock.MaxStrength
 		g.ss = spanset.SpanScope(0)
 		g.index = -1
 		g.mu.Lock()
@@ -2544,15 +3274,15 @@ func (t *lockTableImpl) newGuardForReq(req Request) *lockTableGuardImpl {
 	g.spans = req.LockSpans
 	g.waitPolicy = req.WaitPolicy
 	g.maxWaitQueueLength = req.MaxLockWaitQueueLength
-	g.sa = spanset.NumSpanAccess - 1
+	g.str = lock.MaxStrength
 	g.index = -1
 	return g
 }
 
 func (t *lockTableImpl) doSnapshotForGuard(g *lockTableGuardImpl) {
 	for ss := spanset.SpanScope(0); ss < spanset.NumSpanScope; ss++ {
-		for sa := spanset.SpanAccess(0); sa < spanset.NumSpanAccess; sa++ {
-			if len(g.spans.GetSpans(sa, ss)) > 0 {
+		for str := lock.None; str <= lock.MaxStrength; str++ {
+			if len(g.spans.GetSpans(ss, str)) > 0 {
 				// Since the spans are constant for a request, every call to
 				// ScanAndEnqueue for that request will execute the following code
 				// for the same SpanScope(s). Any SpanScope for which this code does
@@ -2646,7 +3376,7 @@ func (t *lockTableImpl) AddDiscoveredLock(
 	}
 	g := guard.(*lockTableGuardImpl)
 	key := intent.Key
-	sa, ss, err := findAccessInSpans(key, g.spans)
+	str, ss, err := findStrengthInSpans(key, g.spans)
 	if err != nil {
 		return false, err
 	}
@@ -2684,7 +3414,7 @@ func (t *lockTableImpl) AddDiscoveredLock(
 		g.notRemovableLock = l
 		notRemovableLock = true
 	}
-	err = l.discoveredLock(&intent.Txn, intent.Txn.WriteTimestamp, g, sa, notRemovableLock, g.lt.clock)
+	err = l.discoveredLock(&intent.Txn, intent.Txn.WriteTimestamp, g, str, notRemovableLock, g.lt.clock)
 	// Can't release tree.mu until call l.discoveredLock() since someone may
 	// find an empty lock and remove it from the tree.
 	tree.mu.Unlock()
@@ -2704,9 +3434,13 @@ func (t *lockTableImpl) AcquireLock(
 		// If not enabled, don't track any locks.
 		return nil
 	}
-	if strength != lock.Exclusive {
-		return errors.AssertionFailedf("lock strength not Exclusive")
+	if strength == lock.None {
+		return errors.AssertionFailedf("lock strength not specified")
 	}
+	// Replicated Shared and Update locks would be rediscovered as Exclusive
+	// locks from the replicated keyspace, so unlike replicated Exclusive locks
+	// (which are stored as intents) they are always remembered.
+	sharedStr := strength == lock.Shared || strength == lock.Update
 	ss := spanset.SpanGlobal
 	if keys.IsLocal(key) {
 		ss = spanset.SpanLocal
@@ -2722,7 +3456,7 @@ func (t *lockTableImpl) AcquireLock(
 	iter.FirstOverlap(&lockState{key: key})
 	checkMaxLocks := false
 	if !iter.Valid() {
-		if durability == lock.Replicated {
+		if durability == lock.Replicated && !sharedStr {
 			// Don't remember uncontended replicated locks. The downside is that
 			// sometimes contention won't be noticed until when the request
 			// evaluates. Remembering here would be better, but our behavior when
@@ -2741,7 +3475,7 @@ func (t *lockTableImpl) AcquireLock(
 		atomic.AddInt64(&tree.numLocks, 1)
 	} else {
 		l = iter.Cur()
-		if durability == lock.Replicated && l.tryFreeLockOnReplicatedAcquire() {
+		if durability == lock.Replicated && !sharedStr && l.tryFreeLockOnReplicatedAcquire() {
 			// Don't remember uncontended replicated locks. Just like in the
 			// case where the lock is initially added as replicated, we drop
 			// replicated locks from the lockTable when being upgraded from
@@ -2822,27 +3556,27 @@ func (t *lockTableImpl) tryClearLocks(force bool, numToClear int) {
 	}
 }
 
-// Given the key must be in spans, returns the strongest access
+// Given the key must be in spans, returns the strongest lock strength
 // specified in the spans, along with the scope of the key.
-func findAccessInSpans(
-	key roachpb.Key, spans *spanset.SpanSet,
-) (spanset.SpanAccess, spanset.SpanScope, error) {
+func findStrengthInSpans(
+	key roachpb.Key, spans *lockspanset.LockSpanSet,
+) (lock.Strength, spanset.SpanScope, error) {
 	ss := spanset.SpanGlobal
 	if keys.IsLocal(key) {
 		ss = spanset.SpanLocal
 	}
-	for sa := spanset.NumSpanAccess - 1; sa >= 0; sa-- {
-		s := spans.GetSpans(sa, ss)
+	for str := lock.MaxStrength; str >= lock.None; str-- {
+		s := spans.GetSpans(ss, str)
 		// First span that starts after key
 		i := sort.Search(len(s), func(i int) bool {
 			return key.Compare(s[i].Key) < 0
 		})
 		if i > 0 &&
 			((len(s[i-1].EndKey) > 0 && key.Compare(s[i-1].EndKey) < 0) || key.Equal(s[i-1].Key)) {
-			return sa, ss, nil
+			return str, ss, nil
 		}
 	}
-	return 0, 0, errors.AssertionFailedf("could not find access in spans")
+	return 0, 0, errors.AssertionFailedf("could not find strength in spans")
 }
 
 // Tries to GC locks that were previously known to have become empty.
@@ -2920,11 +3654,11 @@ func (t *lockTableImpl) updateLockInternal(up *roachpb.LockUpdate) (heldByTxn bo
 // Iteration helper for findNextLockAfter. Returns the next span to search
 // over, or nil if the iteration is done.
 // REQUIRES: g.mu is locked.
-func stepToNextSpan(g *lockTableGuardImpl) *spanset.Span {
+func stepToNextSpan(g *lockTableGuardImpl) *roachpb.Span {
 	g.index++
 	for ; g.ss < spanset.NumSpanScope; g.ss++ {
-		for ; g.sa >= 0; g.sa-- {
-			spans := g.spans.GetSpans(g.sa, g.ss)
+		for ; g.str >= lock.None; g.str-- {
+			spans := g.spans.GetSpans(g.ss, g.str)
 			if g.index < len(spans) {
 				span := &spans[g.index]
 				g.key = span.Key
@@ -2932,7 +3666,7 @@ func stepToNextSpan(g *lockTableGuardImpl) *spanset.Span {
 			}
 			g.index = 0
 		}
-		g.sa = spanset.NumSpanAccess - 1
+		g.str = lock.MaxStrength
 	}
 	return nil
 }
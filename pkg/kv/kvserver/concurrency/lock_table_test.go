@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/poison"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/lockspanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanlatch"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -63,10 +64,11 @@ new-txn txn=<name> ts=<int>[,<int>] epoch=<int> [seq=<int>]
 
  Creates a TxnMeta.
 
-new-request r=<name> txn=<name>|none ts=<int>[,<int>] spans=r|w@<start>[,<end>]+... [skip-locked] [max-lock-wait-queue-length=<int>]
+new-request r=<name> txn=<name>|none ts=<int>[,<int>] spans=r|s|u|x|w@<start>[,<end>]+... [skip-locked] [max-lock-wait-queue-length=<int>]
 ----
 
- Creates a Request.
+ Creates a Request. Each span is declared as a read (r), or as acquiring a
+ shared (s), update (u), exclusive (x) or intent (w) lock.
 
 scan r=<name>
 ----
@@ -82,11 +84,12 @@ start-waiting: <bool>
  Calls lockTable.ScanOptimistic. The request must not have an existing guard.
  If a guard is returned, stores it for later use.
 
-acquire r=<name> k=<key> durability=r|u
+acquire r=<name> k=<key> durability=r|u [strength=shared|update|exclusive]
 ----
 <error string>
 
- Acquires lock for the request, using the existing guard for that request.
+ Acquires lock for the request, using the existing guard for that request. The
+ strength defaults to exclusive.
 
 release txn=<name> span=<start>[,<end>]
 ----
@@ -111,7 +114,7 @@ add-discovered r=<name> k=<key> txn=<name> [lease-seq=<seq>] [consult-finalized-
 
  Adds a discovered lock that is discovered by the named request.
 
-check-opt-no-conflicts r=<name> spans=r|w@<start>[,<end>]+...
+check-opt-no-conflicts r=<name> spans=r|s|u|x|w@<start>[,<end>]+...
 ----
 no-conflicts: <bool>
 
@@ -305,13 +308,13 @@ func TestLockTableBasic(t *testing.T) {
 				if d.HasArg("max-lock-wait-queue-length") {
 					d.ScanArgs(t, "max-lock-wait-queue-length", &maxLockWaitQueueLength)
 				}
-				spans := scanSpans(t, d, ts)
+				latchSpans, lockSpans := scanSpans(t, d, ts)
 				req := Request{
 					Timestamp:              ts,
 					WaitPolicy:             waitPolicy,
 					MaxLockWaitQueueLength: maxLockWaitQueueLength,
-					LatchSpans:             spans,
-					LockSpans:              spans,
+					LatchSpans:             latchSpans,
+					LockSpans:              lockSpans,
 				}
 				if txnMeta != nil {
 					// Update the transaction's timestamp, if necessary. The transaction
@@ -370,7 +373,11 @@ func TestLockTableBasic(t *testing.T) {
 				if s[0] == 'r' {
 					durability = lock.Replicated
 				}
-				if err := lt.AcquireLock(&req.Txn.TxnMeta, roachpb.Key(key), lock.Exclusive, durability); err != nil {
+				str := lock.Exclusive
+				if d.HasArg("strength") {
+					str = ScanLockStrength(t, d)
+				}
+				if err := lt.AcquireLock(&req.Txn.TxnMeta, roachpb.Key(key), str, durability); err != nil {
 					return err.Error()
 				}
 				return lt.String()
@@ -485,8 +492,8 @@ func TestLockTableBasic(t *testing.T) {
 				if g == nil {
 					d.Fatalf(t, "unknown guard: %s", reqName)
 				}
-				spans := scanSpans(t, d, req.Timestamp)
-				return fmt.Sprintf("no-conflicts: %t", g.CheckOptimisticNoConflicts(spans))
+				_, lockSpans := scanSpans(t, d, req.Timestamp)
+				return fmt.Sprintf("no-conflicts: %t", g.CheckOptimisticNoConflicts(lockSpans))
 
 			case "is-key-locked-by-conflicting-txn":
 				var reqName string
@@ -689,8 +696,11 @@ func getSpan(t *testing.T, d *datadriven.TestData, str string) roachpb.Span {
 	return span
 }
 
-func scanSpans(t *testing.T, d *datadriven.TestData, ts hlc.Timestamp) *spanset.SpanSet {
-	spans := &spanset.SpanSet{}
+func scanSpans(
+	t *testing.T, d *datadriven.TestData, ts hlc.Timestamp,
+) (*spanset.SpanSet, *lockspanset.LockSpanSet) {
+	latchSpans := &spanset.SpanSet{}
+	lockSpans := &lockspanset.LockSpanSet{}
 	var spansStr string
 	d.ScanArgs(t, "spans", &spansStr)
 	parts := strings.Split(spansStr, "+")
@@ -701,17 +711,40 @@ func scanSpans(t *testing.T, d *datadriven.TestData, ts hlc.Timestamp) *spanset.
 		c := p[0]
 		p = p[2:]
 		var sa spanset.SpanAccess
+		var str lock.Strength
 		switch c {
 		case 'r':
 			sa = spanset.SpanReadOnly
+			str = lock.None
+		case 's':
+			sa = spanset.SpanReadWrite
+			str = lock.Shared
+		case 'u':
+			sa = spanset.SpanReadWrite
+			str = lock.Update
+		case 'x':
+			sa = spanset.SpanReadWrite
+			str = lock.Exclusive
 		case 'w':
 			sa = spanset.SpanReadWrite
+			str = lock.Intent
 		default:
 			d.Fatalf(t, "incorrect span access: %c", c)
 		}
-		spans.AddMVCC(sa, getSpan(t, d, p), ts)
+		span := getSpan(t, d, p)
+		latchSpans.AddMVCC(sa, span, ts)
+		lockSpans.Add(str, span)
+	}
+	return latchSpans, lockSpans
+}
+
+// lockStrengthForAccess returns the lock strength that a randomly generated
+// request declares alongside a latch of the given access.
+func lockStrengthForAccess(sa spanset.SpanAccess) lock.Strength {
+	if sa == spanset.SpanReadOnly {
+		return lock.None
 	}
-	return spans
+	return lock.Intent
 }
 
 func ScanLockStrength(t *testing.T, d *datadriven.TestData) lock.Strength {
@@ -758,16 +791,18 @@ func TestLockTableMaxLocks(t *testing.T) {
 	// 10 requests, each with 10 discovered locks. Only 1 will be considered
 	// notRemovable per request.
 	for i := 0; i < 10; i++ {
-		spans := &spanset.SpanSet{}
+		latchSpans := &spanset.SpanSet{}
+		lockSpans := &lockspanset.LockSpanSet{}
 		for j := 0; j < 20; j++ {
 			k := roachpb.Key(fmt.Sprintf("%08d", i*20+j))
 			keys = append(keys, k)
-			spans.AddMVCC(spanset.SpanReadWrite, roachpb.Span{Key: k}, hlc.Timestamp{WallTime: 1})
+			latchSpans.AddMVCC(spanset.SpanReadWrite, roachpb.Span{Key: k}, hlc.Timestamp{WallTime: 1})
+			lockSpans.Add(lock.Intent, roachpb.Span{Key: k})
 		}
 		req := Request{
 			Timestamp:  hlc.Timestamp{WallTime: 1},
-			LatchSpans: spans,
-			LockSpans:  spans,
+			LatchSpans: latchSpans,
+			LockSpans:  lockSpans,
 		}
 		reqs = append(reqs, req)
 		ltg := lt.ScanAndEnqueue(req, nil)
@@ -883,16 +918,18 @@ func TestLockTableMaxLocksWithMultipleNotRemovableRefs(t *testing.T) {
 	var guards []lockTableGuard
 	// 10 requests. Every pair of requests have the same span.
 	for i := 0; i < 10; i++ {
-		spans := &spanset.SpanSet{}
+		latchSpans := &spanset.SpanSet{}
+		lockSpans := &lockspanset.LockSpanSet{}
 		key := roachpb.Key(fmt.Sprintf("%08d", i/2))
 		if i%2 == 0 {
 			keys = append(keys, key)
 		}
-		spans.AddMVCC(spanset.SpanReadWrite, roachpb.Span{Key: key}, hlc.Timestamp{WallTime: 1})
+		latchSpans.AddMVCC(spanset.SpanReadWrite, roachpb.Span{Key: key}, hlc.Timestamp{WallTime: 1})
+		lockSpans.Add(lock.Intent, roachpb.Span{Key: key})
 		req := Request{
 			Timestamp:  hlc.Timestamp{WallTime: 1},
-			LatchSpans: spans,
-			LockSpans:  spans,
+			LatchSpans: latchSpans,
+			LockSpans:  lockSpans,
 		}
 		ltg := lt.ScanAndEnqueue(req, nil)
 		require.Nil(t, ltg.ResolveBeforeScanning())
@@ -1346,11 +1383,13 @@ func TestLockTableConcurrentSingleRequests(t *testing.T) {
 	for i := 0; i < numRequests; i++ {
 		ts := timestamps[rng.Intn(len(timestamps))]
 		keysPerm := rng.Perm(len(keys))
-		spans := &spanset.SpanSet{}
+		latchSpans := &spanset.SpanSet{}
+		lockSpans := &lockspanset.LockSpanSet{}
 		for i := 0; i < numKeys; i++ {
 			span := roachpb.Span{Key: keys[keysPerm[i]]}
 			acc := spanset.SpanAccess(rng.Intn(int(spanset.NumSpanAccess)))
-			spans.AddMVCC(acc, span, ts)
+			latchSpans.AddMVCC(acc, span, ts)
+			lockSpans.Add(lockStrengthForAccess(acc), span)
 		}
 		var txn *roachpb.Transaction
 		if rng.Intn(2) == 0 {
@@ -1365,8 +1404,8 @@ func TestLockTableConcurrentSingleRequests(t *testing.T) {
 		request := &Request{
 			Txn:        txn,
 			Timestamp:  ts,
-			LatchSpans: spans,
-			LockSpans:  spans,
+			LatchSpans: latchSpans,
+			LockSpans:  lockSpans,
 		}
 		items = append(items, workloadItem{request: request})
 		if txn != nil {
@@ -1439,13 +1478,14 @@ func TestLockTableConcurrentRequests(t *testing.T) {
 			ts = timestamps[rng.Intn(len(timestamps))]
 		}
 		keysPerm := rng.Perm(len(keys))
-		spans := &spanset.SpanSet{}
+		latchSpans := &spanset.SpanSet{}
+		lockSpans := &lockspanset.LockSpanSet{}
 		onlyReads := txnMeta == nil && rng.Intn(2) != 0
 		numKeys := rng.Intn(len(keys)-1) + 1
 		request := &Request{
 			Timestamp:  ts,
-			LatchSpans: spans,
-			LockSpans:  spans,
+			LatchSpans: latchSpans,
+			LockSpans:  lockSpans,
 		}
 		if txnMeta != nil {
 			request.Txn = &roachpb.Transaction{
@@ -1469,9 +1509,11 @@ func TestLockTableConcurrentRequests(t *testing.T) {
 					dupRead = true
 				}
 			}
-			spans.AddMVCC(acc, span, ts)
+			latchSpans.AddMVCC(acc, span, ts)
+			lockSpans.Add(lockStrengthForAccess(acc), span)
 			if dupRead {
-				spans.AddMVCC(spanset.SpanReadOnly, span, ts)
+				latchSpans.AddMVCC(spanset.SpanReadOnly, span, ts)
+				lockSpans.Add(lock.None, span)
 			}
 		}
 		items = append(items, wi)
@@ -1579,20 +1621,23 @@ func doBenchWork(item *benchWorkItem, env benchEnv, doneCh chan<- error) {
 // keys will be locked.
 func createRequests(index int, numOutstanding int, numKeys int, numReadKeys int) []benchWorkItem {
 	ts := hlc.Timestamp{WallTime: 10}
-	spans := &spanset.SpanSet{}
+	latchSpans := &spanset.SpanSet{}
+	lockSpans := &lockspanset.LockSpanSet{}
 	wi := benchWorkItem{
 		Request: Request{
 			Timestamp:  ts,
-			LatchSpans: spans,
-			LockSpans:  spans,
+			LatchSpans: latchSpans,
+			LockSpans:  lockSpans,
 		},
 	}
 	for i := 0; i < numKeys; i++ {
 		key := roachpb.Key(fmt.Sprintf("k%d.%d", index, i))
 		if i <= numReadKeys {
-			spans.AddMVCC(spanset.SpanReadOnly, roachpb.Span{Key: key}, ts)
+			latchSpans.AddMVCC(spanset.SpanReadOnly, roachpb.Span{Key: key}, ts)
+			lockSpans.Add(lock.None, roachpb.Span{Key: key})
 		} else {
-			spans.AddMVCC(spanset.SpanReadWrite, roachpb.Span{Key: key}, ts)
+			latchSpans.AddMVCC(spanset.SpanReadWrite, roachpb.Span{Key: key}, ts)
+			lockSpans.Add(lock.Intent, roachpb.Span{Key: key})
 			wi.locksToAcquire = append(wi.locksToAcquire, key)
 		}
 	}
@@ -376,6 +376,9 @@ func (w *lockTableWaiterImpl) WaitOn(
 				// conflicting request but not necessarily the entire conflicting
 				// transaction.
 				if timerWaitingState.held {
+					if len(timerWaitingState.otherHolders) > 0 && req.WaitPolicy == lock.WaitPolicy_Block {
+						return w.pushLockTxns(ctx, req, timerWaitingState)
+					}
 					return w.pushLockTxn(ctx, req, timerWaitingState)
 				}
 
@@ -656,6 +659,28 @@ func (w *lockTableWaiterImpl) pushLockTxn(
 	return w.ir.ResolveIntent(ctx, resolve, opts)
 }
 
+// pushLockTxns pushes all holders of the provided Shared or Update lock that
+// the request conflicts with, concurrently, using pushLockTxn. Each push waits
+// in the txnWaitQueue of its pushee, so a dependency cycle through any of the
+// holders is detected. The method returns as soon as one of the pushes
+// completes, at which point the lock has been updated to reflect the state
+// transition of its holder, and cancels the others.
+func (w *lockTableWaiterImpl) pushLockTxns(
+	ctx context.Context, req Request, ws waitingState,
+) *Error {
+	pushCtx, pushCancel := context.WithCancel(ctx)
+	defer pushCancel()
+	holders := append([]*enginepb.TxnMeta{ws.txn}, ws.otherHolders...)
+	errC := make(chan *Error, len(holders))
+	for _, txn := range holders {
+		holderWS := ws
+		holderWS.txn = txn
+		holderWS.otherHolders = nil
+		go func() { errC <- w.pushLockTxn(pushCtx, req, holderWS) }()
+	}
+	return <-errC
+}
+
 // pushLockTxnAfterTimeout is like pushLockTxn, but it sets the Error wait
 // policy on its request so that the request will not block on the lock holder
 // if it is still active. It is meant to be used after a lock timeout has been
@@ -15,12 +15,14 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/intentresolver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/lockspanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -84,7 +86,7 @@ func (g *mockLockTableGuard) CurState() waitingState {
 func (g *mockLockTableGuard) ResolveBeforeScanning() []roachpb.LockUpdate {
 	return g.toResolve
 }
-func (g *mockLockTableGuard) CheckOptimisticNoConflicts(*spanset.SpanSet) (ok bool) {
+func (g *mockLockTableGuard) CheckOptimisticNoConflicts(*lockspanset.LockSpanSet) (ok bool) {
 	return true
 }
 func (g *mockLockTableGuard) IsKeyLockedByConflictingTxn(
@@ -831,6 +833,69 @@ func TestLockTableWaiterIntentResolverError(t *testing.T) {
 	})
 }
 
+// TestLockTableWaiterPushesAllSharedLockHolders tests that a request waiting
+// on a lock held with Shared strength by multiple transactions pushes all of
+// them concurrently, so that a deadlock involving any of them is detected.
+func TestLockTableWaiterPushesAllSharedLockHolders(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	w, ir, g, _ := setupLockTableWaiterTest()
+	defer w.stopper.Stop(ctx)
+
+	txn := makeTxnProto("request")
+	req := Request{
+		Txn:       &txn,
+		Timestamp: txn.ReadTimestamp,
+	}
+	keyA := roachpb.Key("keyA")
+	holder1 := makeTxnProto("holder1")
+	holder2 := makeTxnProto("holder2")
+	g.state = waitingState{
+		kind:         waitForDistinguished,
+		txn:          &holder1.TxnMeta,
+		otherHolders: []*enginepb.TxnMeta{&holder2.TxnMeta},
+		key:          keyA,
+		held:         true,
+		guardAccess:  spanset.SpanReadWrite,
+	}
+	g.notify()
+
+	// Neither push completes until both have started. The push of holder1
+	// blocks until it is canceled, while holder2 is found to be committed.
+	var pushes sync.WaitGroup
+	pushes.Add(2)
+	ir.pushTxn = func(
+		ctx context.Context, pusheeArg *enginepb.TxnMeta, _ kvpb.Header, pushType kvpb.PushTxnType,
+	) (*roachpb.Transaction, *Error) {
+		require.Equal(t, kvpb.PUSH_ABORT, pushType)
+		pushes.Done()
+		pushes.Wait()
+		if pusheeArg.ID == holder1.ID {
+			<-ctx.Done()
+			return nil, kvpb.NewError(ctx.Err())
+		}
+		require.Equal(t, holder2.ID, pusheeArg.ID)
+		resp := holder2.Clone()
+		resp.Status = roachpb.COMMITTED
+		return resp, nil
+	}
+	ir.resolveIntent = func(_ context.Context, intent roachpb.LockUpdate) *Error {
+		require.Equal(t, keyA, intent.Key)
+		require.Equal(t, holder2.ID, intent.Txn.ID)
+		require.Equal(t, roachpb.COMMITTED, intent.Status)
+		g.state = waitingState{kind: doneWaiting}
+		g.notify()
+		return nil
+	}
+	w.lt = &mockLockTable{txnFinalizedFn: func(txn *roachpb.Transaction) {
+		require.Equal(t, holder2.ID, txn.ID)
+	}}
+
+	err := w.WaitOn(ctx, req, g)
+	require.Nil(t, err)
+}
+
 // TestLockTableWaiterDeferredIntentResolverError tests that the lockTableWaiter
 // propagates errors from its intent resolver when it resolves intent batches.
 func TestLockTableWaiterDeferredIntentResolverError(t *testing.T) {
@@ -424,7 +424,8 @@ func tryRangeIDKey(kv storage.MVCCKeyValue) (string, error) {
 	case bytes.Equal(suffix, keys.LocalRaftHardStateSuffix):
 		msg = &raftpb.HardState{}
 
-	case bytes.Equal(suffix, keys.LocalRangeLastReplicaGCTimestampSuffix):
+	case bytes.Equal(suffix, keys.LocalRangeLastReplicaGCTimestampSuffix),
+		bytes.Equal(suffix, keys.LocalRangeQuarantinedSuffix):
 		msg = &hlc.Timestamp{}
 
 	default:
@@ -0,0 +1,185 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package hotkeycache provides an in-memory cache of the latest MVCC versions
+// of a replica's hot keys.
+package hotkeycache
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// Cache caches the latest MVCC version of each of a set of hot keys, which
+// allows reads of those keys at or above that version to be served without
+// reading from the storage engine.
+//
+// The cache is only correct if every key is invalidated after any change to it
+// becomes visible in the storage engine, and before any reader which must
+// observe the change can read the key; on a replica, this means before the
+// latches of the request which wrote the key are released. Populating the cache
+// is a two step process to avoid racing with invalidations: a Token is obtained
+// with Begin before reading the key from the storage engine, and Put discards
+// the value if the key was invalidated in the meantime.
+//
+// A value without a timestamp denotes a key without any versions. A value with
+// a timestamp but without a payload denotes a deletion tombstone.
+type Cache struct {
+	mu struct {
+		syncutil.Mutex
+		entries map[string]*entry
+	}
+}
+
+type entry struct {
+	key roachpb.Key
+	// gen is incremented every time the key is invalidated.
+	gen uint64
+	// value is the latest version of the key. It is only valid if cached is
+	// set.
+	value  roachpb.Value
+	cached bool
+}
+
+// Token is obtained before reading a hot key from the storage engine in order
+// to populate the cache, and passed to Put along with the value that was read.
+type Token struct {
+	e   *entry
+	gen uint64
+}
+
+// New returns a new, empty, Cache.
+func New() *Cache {
+	c := &Cache{}
+	c.mu.entries = make(map[string]*entry)
+	return c
+}
+
+// SetHotKeys replaces the set of keys which may be cached. The cached values
+// of keys that remain hot are retained.
+func (c *Cache) SetHotKeys(keys []roachpb.Key) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make(map[string]*entry, len(keys))
+	for _, key := range keys {
+		if e, ok := c.mu.entries[string(key)]; ok {
+			entries[string(key)] = e
+			continue
+		}
+		key = key.Clone()
+		entries[string(key)] = &entry{key: key}
+	}
+	c.mu.entries = entries
+}
+
+// HotKeys returns the set of keys which may be cached.
+func (c *Cache) HotKeys() []roachpb.Key {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]roachpb.Key, 0, len(c.mu.entries))
+	for _, e := range c.mu.entries {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+// IsEmpty returns whether there are no hot keys, i.e. no keys which may be
+// cached.
+func (c *Cache) IsEmpty() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.mu.entries) == 0
+}
+
+// IsHot returns whether the given key may be cached.
+func (c *Cache) IsHot(key roachpb.Key) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.mu.entries[string(key)]
+	return ok
+}
+
+// Get returns the value of the given key as of the given timestamp, if it can
+// be determined from the cache; that is, if the latest version of the key is
+// cached and is not above the timestamp. The returned value is nil if the key
+// doesn't exist, or is deleted, as of the timestamp.
+func (c *Cache) Get(key roachpb.Key, ts hlc.Timestamp) (_ *roachpb.Value, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.mu.entries[string(key)]
+	if !ok || !e.cached || ts.Less(e.value.Timestamp) {
+		return nil, false
+	}
+	if !e.value.IsPresent() {
+		return nil, true
+	}
+	value := e.value
+	return &value, true
+}
+
+// Begin returns a Token with which the given key can be populated, if the key
+// is hot and not already cached.
+func (c *Cache) Begin(key roachpb.Key) (_ Token, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.mu.entries[string(key)]
+	if !ok || e.cached {
+		return Token{}, false
+	}
+	return Token{e: e, gen: e.gen}, true
+}
+
+// Put caches the latest version of the key for which the Token was obtained,
+// unless the key was invalidated, or is no longer hot, since then.
+func (c *Cache) Put(tok Token, value roachpb.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.mu.entries[string(tok.e.key)]
+	if !ok || e != tok.e || e.gen != tok.gen {
+		return
+	}
+	e.value = roachpb.Value{
+		RawBytes:  append([]byte(nil), value.RawBytes...),
+		Timestamp: value.Timestamp,
+	}
+	e.cached = true
+}
+
+// Invalidate evicts the cached value of the given key, if any.
+func (c *Cache) Invalidate(key roachpb.Key) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.mu.entries[string(key)]; ok {
+		e.invalidate()
+	}
+}
+
+// InvalidateAll evicts all cached values. The set of hot keys is retained.
+func (c *Cache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range c.mu.entries {
+		e.invalidate()
+	}
+}
+
+func (e *entry) invalidate() {
+	e.gen++
+	e.value = roachpb.Value{}
+	e.cached = false
+}
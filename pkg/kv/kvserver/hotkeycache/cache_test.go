@@ -0,0 +1,103 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package hotkeycache
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+	val := roachpb.MakeValueFromString("val")
+	val.Timestamp = ts(10)
+
+	c := New()
+	hot, cold := roachpb.Key("hot"), roachpb.Key("cold")
+	c.SetHotKeys([]roachpb.Key{hot})
+	require.True(t, c.IsHot(hot))
+	require.False(t, c.IsHot(cold))
+
+	// Keys that aren't hot can't be populated.
+	_, ok := c.Begin(cold)
+	require.False(t, ok)
+
+	// Nothing is cached until the key is populated.
+	_, ok = c.Get(hot, ts(20))
+	require.False(t, ok)
+	tok, ok := c.Begin(hot)
+	require.True(t, ok)
+	c.Put(tok, val)
+
+	// Reads at or above the cached version are served from the cache, reads
+	// below it aren't.
+	v, ok := c.Get(hot, ts(20))
+	require.True(t, ok)
+	require.Equal(t, val.RawBytes, v.RawBytes)
+	require.Equal(t, ts(10), v.Timestamp)
+	v, ok = c.Get(hot, ts(10))
+	require.True(t, ok)
+	require.NotNil(t, v)
+	_, ok = c.Get(hot, ts(9))
+	require.False(t, ok)
+
+	// A key that was invalidated after the token was obtained isn't populated.
+	c.Invalidate(hot)
+	_, ok = c.Get(hot, ts(20))
+	require.False(t, ok)
+	tok, ok = c.Begin(hot)
+	require.True(t, ok)
+	c.Invalidate(hot)
+	c.Put(tok, val)
+	_, ok = c.Get(hot, ts(20))
+	require.False(t, ok)
+
+	// Nor is a key that stopped, and then started again, being hot.
+	tok, ok = c.Begin(hot)
+	require.True(t, ok)
+	c.SetHotKeys(nil)
+	c.SetHotKeys([]roachpb.Key{hot})
+	c.Put(tok, val)
+	_, ok = c.Get(hot, ts(20))
+	require.False(t, ok)
+
+	// A tombstone is served as a missing value, and so is a key without any
+	// versions.
+	tok, ok = c.Begin(hot)
+	require.True(t, ok)
+	c.Put(tok, roachpb.Value{Timestamp: ts(10)})
+	v, ok = c.Get(hot, ts(20))
+	require.True(t, ok)
+	require.Nil(t, v)
+	_, ok = c.Get(hot, ts(9))
+	require.False(t, ok)
+	c.InvalidateAll()
+	tok, ok = c.Begin(hot)
+	require.True(t, ok)
+	c.Put(tok, roachpb.Value{})
+	v, ok = c.Get(hot, ts(1))
+	require.True(t, ok)
+	require.Nil(t, v)
+
+	// Cached values of keys that remain hot are retained.
+	c.SetHotKeys([]roachpb.Key{hot, cold})
+	_, ok = c.Get(hot, ts(1))
+	require.True(t, ok)
+	require.ElementsMatch(t, []roachpb.Key{hot, cold}, c.HotKeys())
+	c.SetHotKeys(nil)
+	require.True(t, c.IsEmpty())
+}
@@ -84,6 +84,36 @@ var rangefeedCatchupScanElasticControlEnabled = settings.RegisterBoolSetting(
 	true,
 )
 
+// elasticCPUDurationPerStoreScrubUnit controls how many CPU tokens are
+// allotted for each unit of work during background store scrubbing. Only takes
+// effect if kvadmission.store_scrub_elastic_control.enabled is set.
+var elasticCPUDurationPerStoreScrubUnit = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kvadmission.elastic_cpu.duration_per_store_scrub_unit",
+	"controls how many CPU tokens are allotted for each unit of work during store scrubbing",
+	admission.MaxElasticCPUDuration,
+	func(duration time.Duration) error {
+		if duration < admission.MinElasticCPUDuration {
+			return fmt.Errorf("minimum CPU duration allowed is %s, got %s",
+				admission.MinElasticCPUDuration, duration)
+		}
+		if duration > admission.MaxElasticCPUDuration {
+			return fmt.Errorf("maximum CPU duration allowed is %s, got %s",
+				admission.MaxElasticCPUDuration, duration)
+		}
+		return nil
+	},
+)
+
+// storeScrubElasticControlEnabled determines whether background store
+// scrubbing integrates with elastic CPU control.
+var storeScrubElasticControlEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kvadmission.store_scrub_elastic_control.enabled",
+	"determines whether store scrubbing integrates with the elastic CPU control",
+	true,
+)
+
 // ProvisionedBandwidth set a value of the provisioned
 // bandwidth for each store in the cluster.
 var ProvisionedBandwidth = settings.RegisterByteSizeSetting(
@@ -108,6 +138,10 @@ type Controller interface {
 	// catchup scans (typically CPU-intensive and affecting scheduling
 	// latencies).
 	AdmitRangefeedRequest(roachpb.TenantID, *kvpb.RangeFeedRequest) *admission.Pacer
+	// AdmitStoreScrub must be called before scrubbing a store in the
+	// background. If enabled, it returns a non-nil Pacer that's to be used
+	// while verifying the checksums of the store's SSTables.
+	AdmitStoreScrub() *admission.Pacer
 	// SetTenantWeightProvider is used to set the provider that will be
 	// periodically polled for weights. The stopper should be used to terminate
 	// the periodic polling.
@@ -337,6 +371,22 @@ func (n *controllerImpl) AdmitRangefeedRequest(
 		})
 }
 
+// AdmitStoreScrub implements the Controller interface.
+func (n *controllerImpl) AdmitStoreScrub() *admission.Pacer {
+	if !storeScrubElasticControlEnabled.Get(&n.settings.SV) {
+		return nil
+	}
+
+	return n.elasticCPUGrantCoordinator.NewPacer(
+		elasticCPUDurationPerStoreScrubUnit.Get(&n.settings.SV),
+		admission.WorkInfo{
+			TenantID:        roachpb.SystemTenantID,
+			Priority:        admissionpb.BulkNormalPri,
+			CreateTime:      timeutil.Now().UnixNano(),
+			BypassAdmission: false,
+		})
+}
+
 // SetTenantWeightProvider implements the Controller interface.
 func (n *controllerImpl) SetTenantWeightProvider(
 	provider TenantWeightProvider, stopper *stop.Stopper,
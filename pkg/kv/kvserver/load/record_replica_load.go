@@ -10,7 +10,11 @@
 
 package load
 
-import "github.com/cockroachdb/cockroach/pkg/roachpb"
+import (
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/replicastats"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
 
 // RecordBatchRequests records the value for number of batch requests at the
 // current time against the gateway nodeID.
@@ -52,3 +56,32 @@ func (rl *ReplicaLoad) RecordRaftCPUNanos(val float64) {
 func (rl *ReplicaLoad) RecordReqCPUNanos(val float64) {
 	rl.record(ReqCPUNanos, val, 0 /* nodeID */)
 }
+
+// RecordKeyRead records a read of the given key, for the purpose of detecting
+// hot keys.
+func (rl *ReplicaLoad) RecordKeyRead(key roachpb.Key) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := timeutil.Unix(0, rl.clock.PhysicalNow())
+	if rl.mu.hotKeys == nil {
+		rl.mu.hotKeys = replicastats.NewHotKeyStats(now, replicastats.DefaultHotKeyStatsCapacity)
+	}
+	rl.mu.hotKeys.RecordRead(now, key)
+}
+
+// RecordKeyWrites records a write of each of the given keys, for the purpose
+// of detecting hot keys. Only writes of keys whose reads are tracked are
+// counted.
+func (rl *ReplicaLoad) RecordKeyWrites(keys []roachpb.Key) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.mu.hotKeys == nil {
+		return
+	}
+	now := timeutil.Unix(0, rl.clock.PhysicalNow())
+	for _, key := range keys {
+		rl.mu.hotKeys.RecordWrite(now, key)
+	}
+}
@@ -90,6 +90,9 @@ type ReplicaLoad struct {
 	mu struct {
 		syncutil.Mutex
 		stats [numLoadStats]*replicastats.ReplicaStats
+		// hotKeys tracks the reads and writes of individual keys. It is
+		// created lazily, when the first key read is recorded.
+		hotKeys *replicastats.HotKeyStats
 	}
 }
 
@@ -144,6 +147,10 @@ func (rl *ReplicaLoad) Split(other *ReplicaLoad) {
 	for i := range rl.mu.stats {
 		rl.mu.stats[i].SplitRequestCounts(other.mu.stats[i])
 	}
+	// The tracked keys may now belong to either side of the split; start over
+	// on both sides.
+	rl.resetHotKeysLocked()
+	other.resetHotKeysLocked()
 }
 
 // Merge will combine the tracked load from other, into the calling struct.
@@ -162,6 +169,8 @@ func (rl *ReplicaLoad) Merge(other *ReplicaLoad) {
 	for i := range rl.mu.stats {
 		rl.mu.stats[i].MergeRequestCounts(other.mu.stats[i])
 	}
+	rl.resetHotKeysLocked()
+	other.resetHotKeysLocked()
 }
 
 // Reset will clear all recorded history.
@@ -172,6 +181,13 @@ func (rl *ReplicaLoad) Reset() {
 	for i := range rl.mu.stats {
 		rl.mu.stats[i].ResetRequestCounts(timeutil.Unix(0, rl.clock.PhysicalNow()))
 	}
+	rl.resetHotKeysLocked()
+}
+
+func (rl *ReplicaLoad) resetHotKeysLocked() {
+	if rl.mu.hotKeys != nil {
+		rl.mu.hotKeys.Reset(timeutil.Unix(0, rl.clock.PhysicalNow()))
+	}
 }
 
 // getLocked returns the current value for the LoadStat with ordinal stat. It
@@ -212,6 +228,22 @@ func (rl *ReplicaLoad) RequestLocalityInfo() *replicastats.RatedSummary {
 	return rl.mu.stats[Queries].SnapshotRatedSummary(timeutil.Unix(0, rl.clock.PhysicalNow()))
 }
 
+// HotKeys returns the keys of the replica which are read at least
+// minReadsPerSecond times per second, and for which writes make up at most
+// maxWriteFraction of all accesses. See replicastats.HotKeyStats.
+func (rl *ReplicaLoad) HotKeys(
+	minReadsPerSecond float64, maxWriteFraction float64,
+) []replicastats.HotKey {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.mu.hotKeys == nil {
+		return nil
+	}
+	return rl.mu.hotKeys.HotKeys(
+		timeutil.Unix(0, rl.clock.PhysicalNow()), minReadsPerSecond, maxWriteFraction)
+}
+
 // TestingGetSum returns the sum of recorded values for the LoadStat with
 // ordinal stat. The sum is used in testing in place of any averaging in order
 // to assert precisely on the values that have been recorded.
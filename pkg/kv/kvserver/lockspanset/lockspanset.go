@@ -0,0 +1,156 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package lockspanset provides a set of key spans, each associated with the
+// lock strength with which a request intends to access the span.
+package lockspanset
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
+)
+
+// LockSpanSet tracks the set of key spans over which a request expects
+// isolation from conflicting transactions, broken down by the strength of the
+// lock the request needs on each span. Non-locking reads are tracked with
+// lock.None. The set is further divided by key scope (local or global), which
+// mirrors the division of the lock table.
+// The span slice for a particular strength and scope contains non-overlapping
+// spans in increasing key order after calls to SortAndDedup.
+type LockSpanSet struct {
+	spans [spanset.NumSpanScope][lock.NumLockStrength][]roachpb.Span
+}
+
+var lockSpanSetPool = sync.Pool{
+	New: func() interface{} { return new(LockSpanSet) },
+}
+
+// New creates a new empty LockSpanSet.
+func New() *LockSpanSet {
+	return lockSpanSetPool.Get().(*LockSpanSet)
+}
+
+// Release releases the LockSpanSet and its underlying slices. The receiver
+// should not be used after being released.
+func (l *LockSpanSet) Release() {
+	for ss := spanset.SpanScope(0); ss < spanset.NumSpanScope; ss++ {
+		for str := lock.Strength(0); str < lock.NumLockStrength; str++ {
+			// Recycle slice if capacity below threshold.
+			const maxRecycleCap = 8
+			var recycle []roachpb.Span
+			if sl := l.spans[ss][str]; cap(sl) <= maxRecycleCap {
+				for i := range sl {
+					sl[i] = roachpb.Span{}
+				}
+				recycle = sl[:0]
+			}
+			l.spans[ss][str] = recycle
+		}
+	}
+	lockSpanSetPool.Put(l)
+}
+
+// String prints a string representation of the LockSpanSet.
+func (l *LockSpanSet) String() string {
+	var buf strings.Builder
+	for ss := spanset.SpanScope(0); ss < spanset.NumSpanScope; ss++ {
+		for str := lock.Strength(0); str < lock.NumLockStrength; str++ {
+			for _, span := range l.GetSpans(ss, str) {
+				fmt.Fprintf(&buf, "%s %s: %s\n", str, ss, span)
+			}
+		}
+	}
+	return buf.String()
+}
+
+// Len returns the total number of spans tracked across all strengths and
+// scopes.
+func (l *LockSpanSet) Len() int {
+	var count int
+	for ss := spanset.SpanScope(0); ss < spanset.NumSpanScope; ss++ {
+		for str := lock.Strength(0); str < lock.NumLockStrength; str++ {
+			count += len(l.GetSpans(ss, str))
+		}
+	}
+	return count
+}
+
+// Empty returns whether the set contains any spans across all strengths and
+// scopes.
+func (l *LockSpanSet) Empty() bool {
+	return l.Len() == 0
+}
+
+// Copy copies the LockSpanSet.
+func (l *LockSpanSet) Copy() *LockSpanSet {
+	n := New()
+	for ss := spanset.SpanScope(0); ss < spanset.NumSpanScope; ss++ {
+		for str := lock.Strength(0); str < lock.NumLockStrength; str++ {
+			n.spans[ss][str] = append(n.spans[ss][str], l.spans[ss][str]...)
+		}
+	}
+	return n
+}
+
+// Reserve space for N additional spans.
+func (l *LockSpanSet) Reserve(ss spanset.SpanScope, str lock.Strength, n int) {
+	existing := l.spans[ss][str]
+	if n <= cap(existing)-len(existing) {
+		return
+	}
+	l.spans[ss][str] = make([]roachpb.Span, len(existing), n+len(existing))
+	copy(l.spans[ss][str], existing)
+}
+
+// Add adds the supplied span to the LockSpanSet to be accessed with the given
+// lock strength.
+func (l *LockSpanSet) Add(str lock.Strength, span roachpb.Span) {
+	ss := spanset.SpanGlobal
+	if keys.IsLocal(span.Key) {
+		ss = spanset.SpanLocal
+	}
+	l.spans[ss][str] = append(l.spans[ss][str], span)
+}
+
+// SortAndDedup sorts the spans in the LockSpanSet and removes any duplicates.
+func (l *LockSpanSet) SortAndDedup() {
+	for ss := spanset.SpanScope(0); ss < spanset.NumSpanScope; ss++ {
+		for str := lock.Strength(0); str < lock.NumLockStrength; str++ {
+			l.spans[ss][str], _ /* distinct */ = roachpb.MergeSpans(&l.spans[ss][str])
+		}
+	}
+}
+
+// GetSpans returns a slice of spans with the given parameters.
+func (l *LockSpanSet) GetSpans(ss spanset.SpanScope, str lock.Strength) []roachpb.Span {
+	return l.spans[ss][str]
+}
+
+// Validate returns an error if any spans that have been added to the set are
+// invalid.
+func (l *LockSpanSet) Validate() error {
+	for ss := spanset.SpanScope(0); ss < spanset.NumSpanScope; ss++ {
+		for str := lock.Strength(0); str < lock.NumLockStrength; str++ {
+			for _, span := range l.GetSpans(ss, str) {
+				if len(span.EndKey) > 0 && span.Key.Compare(span.EndKey) >= 0 {
+					return errors.Errorf("inverted span %s %s", span.Key, span.EndKey)
+				}
+			}
+		}
+	}
+	return nil
+}
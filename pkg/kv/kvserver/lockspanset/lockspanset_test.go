@@ -0,0 +1,70 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package lockspanset
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that spans are properly classified as global or local and that
+// GetSpans respects the scope and strength arguments.
+func TestGetSpansScopeAndStrength(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	lss := New()
+	defer lss.Release()
+	spA := roachpb.Span{Key: roachpb.Key("a")}
+	spBC := roachpb.Span{Key: roachpb.Key("b"), EndKey: roachpb.Key("c")}
+	spLocal := roachpb.Span{Key: keys.RangeGCThresholdKey(1)}
+	lss.Add(lock.None, spA)
+	lss.Add(lock.Shared, spBC)
+	lss.Add(lock.Shared, spLocal)
+	lss.Add(lock.Intent, spA)
+
+	require.Equal(t, []roachpb.Span{spA}, lss.GetSpans(spanset.SpanGlobal, lock.None))
+	require.Equal(t, []roachpb.Span{spBC}, lss.GetSpans(spanset.SpanGlobal, lock.Shared))
+	require.Equal(t, []roachpb.Span{spLocal}, lss.GetSpans(spanset.SpanLocal, lock.Shared))
+	require.Equal(t, []roachpb.Span{spA}, lss.GetSpans(spanset.SpanGlobal, lock.Intent))
+	require.Empty(t, lss.GetSpans(spanset.SpanGlobal, lock.Update))
+	require.Empty(t, lss.GetSpans(spanset.SpanGlobal, lock.Exclusive))
+	require.Equal(t, 4, lss.Len())
+	require.False(t, lss.Empty())
+	require.NoError(t, lss.Validate())
+}
+
+func TestCopyAndSortAndDedup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	lss := New()
+	lss.Add(lock.Update, roachpb.Span{Key: roachpb.Key("c"), EndKey: roachpb.Key("e")})
+	lss.Add(lock.Update, roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("d")})
+
+	c := lss.Copy()
+	require.Equal(t, lss, c)
+
+	lss.SortAndDedup()
+	require.Equal(t,
+		[]roachpb.Span{{Key: roachpb.Key("a"), EndKey: roachpb.Key("e")}},
+		lss.GetSpans(spanset.SpanGlobal, lock.Update),
+	)
+	// Modifying the original should not modify the copy.
+	require.Len(t, c.GetSpans(spanset.SpanGlobal, lock.Update), 2)
+
+	lss.Add(lock.Exclusive, roachpb.Span{Key: roachpb.Key("z"), EndKey: roachpb.Key("a")})
+	require.Error(t, lss.Validate())
+}
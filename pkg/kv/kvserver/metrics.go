@@ -637,6 +637,35 @@ Raft applied index at which this checkpoint was taken.`,
 		Unit:        metric.Unit_COUNT,
 	}
 
+	// Store scrubber metrics.
+	metaScrubberSSTablesVerified = metric.Metadata{
+		Name:        "storage.scrubber.sstables_verified",
+		Help:        "Number of SSTables whose block checksums were verified by the store scrubber",
+		Measurement: "SSTables",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaScrubberBytesVerified = metric.Metadata{
+		Name:        "storage.scrubber.bytes_verified",
+		Help:        "Number of bytes of SSTables whose block checksums were verified by the store scrubber",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaScrubberCorruptSSTables = metric.Metadata{
+		Name: "storage.scrubber.corrupt_sstables",
+		Help: `Number of corrupted SSTables found by the store scrubber.
+
+The replicas overlapping a corrupted SSTable are quarantined, and replaced by
+new replicas initialized from snapshots of healthy replicas.`,
+		Measurement: "SSTables",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaScrubberQuarantinedReplicas = metric.Metadata{
+		Name:        "storage.scrubber.quarantined_replicas",
+		Help:        "Number of replicas quarantined by the store scrubber because of corrupted data",
+		Measurement: "Replicas",
+		Unit:        metric.Unit_COUNT,
+	}
+
 	metaBlockBytes = metric.Metadata{
 		Name:        "storage.iterator.block-load.bytes",
 		Help:        "Bytes loaded by storage engine iterators (possibly cached). See storage.AggregatedIteratorStats for details.",
@@ -1680,6 +1709,30 @@ not occurring.
 		Unit:        metric.Unit_COUNT,
 	}
 
+	// Range write rate limit metrics.
+	metaRangeWriteRateLimitRejectedRequests = metric.Metadata{
+		Name: "kv.range_write_rate_limit.rejected_requests",
+		Help: `Number of write requests rejected because a range exceeded its write rate limits.
+
+Ranges reject writes in excess of the range_max_write_requests_per_second and
+range_max_write_bytes_per_second fields of their zone configurations. The
+metric is broken down by tenant and zone.
+`,
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRangeWriteRateLimitRejectedBytes = metric.Metadata{
+		Name: "kv.range_write_rate_limit.rejected_bytes",
+		Help: `Number of bytes of writes rejected because a range exceeded its write rate limits.
+
+Ranges reject writes in excess of the range_max_write_requests_per_second and
+range_max_write_bytes_per_second fields of their zone configurations. The
+metric is broken down by tenant and zone.
+`,
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+
 	// AddSSTable metrics.
 	metaAddSSTableProposals = metric.Metadata{
 		Name:        "addsstable.proposals",
@@ -1878,6 +1931,12 @@ Note that the measurement does not include the duration for replicating the eval
 		Measurement: "Batches",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaReplicaReadBatchHotKeyCacheHits = metric.Metadata{
+		Name:        "kv.replica_read_batch_evaluate.hot_key_cache_hits",
+		Help:        `Number of read-only batches served from the hot key cache.`,
+		Measurement: "Batches",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // StoreMetrics is the set of metrics for a given store.
@@ -1891,6 +1950,10 @@ type StoreMetrics struct {
 	// LoadSplitterMetrics stores metrics for load-based splitter split key.
 	*split.LoadSplitterMetrics
 
+	// RangeWriteRateLimitMetrics stores metrics for writes rejected by the
+	// per-range write rate limits.
+	*RangeWriteRateLimitMetrics
+
 	// Replica metrics.
 	ReplicaCount                  *metric.Gauge // Does not include uninitialized or reserved replicas.
 	ReservedReplicaCount          *metric.Gauge
@@ -2020,6 +2083,12 @@ type StoreMetrics struct {
 
 	RdbCheckpoints *metric.Gauge
 
+	// Store scrubber metrics.
+	ScrubberSSTablesVerified    *metric.Counter
+	ScrubberBytesVerified       *metric.Counter
+	ScrubberCorruptSSTables     *metric.Counter
+	ScrubberQuarantinedReplicas *metric.Counter
+
 	// Disk health metrics.
 	DiskSlow    *metric.Gauge
 	DiskStalled *metric.Gauge
@@ -2214,6 +2283,7 @@ type StoreMetrics struct {
 
 	ReplicaReadBatchDroppedLatchesBeforeEval *metric.Counter
 	ReplicaReadBatchWithoutInterleavingIter  *metric.Counter
+	ReplicaReadBatchHotKeyCacheHits          *metric.Counter
 
 	FlushUtilization *metric.GaugeFloat64
 	FsyncLatency     *metric.ManualWindowHistogram
@@ -2506,6 +2576,7 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 			PopularKeyCount: metric.NewCounter(metaPopularKeyCount),
 			NoSplitKeyCount: metric.NewCounter(metaNoSplitKeyCount),
 		},
+		RangeWriteRateLimitMetrics: newRangeWriteRateLimitMetrics(),
 
 		// Replica metrics.
 		ReplicaCount:                  metric.NewGauge(metaReplicaCount),
@@ -2632,6 +2703,12 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 
 		RdbCheckpoints: metric.NewGauge(metaRdbCheckpoints),
 
+		// Store scrubber metrics.
+		ScrubberSSTablesVerified:    metric.NewCounter(metaScrubberSSTablesVerified),
+		ScrubberBytesVerified:       metric.NewCounter(metaScrubberBytesVerified),
+		ScrubberCorruptSSTables:     metric.NewCounter(metaScrubberCorruptSSTables),
+		ScrubberQuarantinedReplicas: metric.NewCounter(metaScrubberQuarantinedReplicas),
+
 		// Disk health metrics.
 		DiskSlow:    metric.NewGauge(metaDiskSlow),
 		DiskStalled: metric.NewGauge(metaDiskStalled),
@@ -2871,6 +2948,7 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 
 		ReplicaReadBatchDroppedLatchesBeforeEval: metric.NewCounter(metaReplicaReadBatchDroppedLatchesBeforeEval),
 		ReplicaReadBatchWithoutInterleavingIter:  metric.NewCounter(metaReplicaReadBatchWithoutInterleavingIter),
+		ReplicaReadBatchHotKeyCacheHits:          metric.NewCounter(metaReplicaReadBatchHotKeyCacheHits),
 	}
 
 	{
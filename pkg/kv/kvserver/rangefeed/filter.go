@@ -11,7 +11,12 @@
 package rangefeed
 
 import (
+	"bytes"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
 )
 
@@ -53,3 +58,155 @@ func (r *Filter) NeedPrevVal(s roachpb.Span) bool {
 func (r *Filter) NeedVal(s roachpb.Span) bool {
 	return r.needVals.Overlaps(s.AsRange())
 }
+
+// eventFilter is the filter pushed down into a registration by its consumer,
+// which decides which of the events over the registration's span are published
+// to it. The zero value matches every event. See kvpb.RangeFeedFilter.
+type eventFilter struct {
+	familyIDs       []uint32
+	omitDeletes     bool
+	keyPredicates   []kvpb.RangeFeedKeyPredicate
+	valuePredicates []kvpb.RangeFeedValuePredicate
+}
+
+func makeEventFilter(f *kvpb.RangeFeedFilter) eventFilter {
+	if f == nil {
+		return eventFilter{}
+	}
+	return eventFilter{
+		familyIDs:       f.FamilyIDs,
+		omitDeletes:     f.OmitDeletes,
+		keyPredicates:   f.KeyPredicates,
+		valuePredicates: f.ValuePredicates,
+	}
+}
+
+// isEmpty returns whether the filter matches every event.
+func (f *eventFilter) isEmpty() bool {
+	return len(f.familyIDs) == 0 && !f.omitDeletes &&
+		len(f.keyPredicates) == 0 && len(f.valuePredicates) == 0
+}
+
+// matches returns whether the event should be published to the registration.
+// Only RangeFeedValue and RangeFeedDeleteRange events are ever filtered out.
+func (f *eventFilter) matches(event *kvpb.RangeFeedEvent) bool {
+	switch t := event.GetValue().(type) {
+	case *kvpb.RangeFeedValue:
+		if f.omitDeletes && !t.Value.IsPresent() {
+			return false
+		}
+		if len(f.familyIDs) > 0 {
+			famID, err := keys.DecodeFamilyKey(t.Key)
+			if err != nil {
+				// Not a SQL row key, so it can't be filtered by column family.
+				return true
+			}
+			if !f.matchesFamily(famID) {
+				return false
+			}
+		}
+		if len(f.keyPredicates) > 0 || len(f.valuePredicates) > 0 {
+			return f.matchesPredicates(t)
+		}
+	case *kvpb.RangeFeedDeleteRange:
+		return !f.omitDeletes
+	}
+	return true
+}
+
+func (f *eventFilter) matchesFamily(famID uint32) bool {
+	for _, id := range f.familyIDs {
+		if id == famID {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPredicates returns whether the value satisfies the key and value
+// predicates of the filter. Keys and values which can't be decoded always
+// match, so that predicates never drop an event they can't evaluate.
+func (f *eventFilter) matchesPredicates(v *kvpb.RangeFeedValue) bool {
+	key, err := keys.StripTenantPrefix(v.Key)
+	if err != nil {
+		return true
+	}
+	cols, tableID, indexID, err := keys.DecodeTableIDIndexID(key)
+	if err != nil {
+		return true
+	}
+	for i := range f.keyPredicates {
+		p := &f.keyPredicates[i]
+		if p.TableID != tableID || p.IndexID != indexID {
+			continue
+		}
+		if !matchesKeyPredicate(cols, p) {
+			return false
+		}
+	}
+	if len(f.valuePredicates) == 0 || !v.Value.IsPresent() {
+		return true
+	}
+	famID, err := keys.DecodeFamilyKey(v.Key)
+	if err != nil {
+		return true
+	}
+	for i := range f.valuePredicates {
+		p := &f.valuePredicates[i]
+		if p.TableID != tableID || p.IndexID != indexID || p.FamilyID != famID {
+			continue
+		}
+		if !matchesValuePredicate(v.Value, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesKeyPredicate returns whether the key column at the predicate's offset
+// in the encoded key columns is equal to the predicate's constant.
+func matchesKeyPredicate(cols []byte, p *kvpb.RangeFeedKeyPredicate) bool {
+	for i := uint32(0); i < p.ColumnOffset; i++ {
+		n, err := encoding.PeekLength(cols)
+		if err != nil {
+			return true
+		}
+		cols = cols[n:]
+	}
+	n, err := encoding.PeekLength(cols)
+	if err != nil {
+		return true
+	}
+	return bytes.Equal(cols[:n], p.EncodedValue)
+}
+
+// matchesValuePredicate returns whether the predicate's column in the tuple
+// encoded value is equal to the predicate's constant. A value which isn't a
+// tuple, or in which the column is absent (i.e. NULL), matches.
+func matchesValuePredicate(v roachpb.Value, p *kvpb.RangeFeedValuePredicate) bool {
+	b, err := v.GetTuple()
+	if err != nil {
+		return true
+	}
+	var colID uint32
+	for len(b) > 0 {
+		_, dataOffset, delta, typ, err := encoding.DecodeValueTag(b)
+		if err != nil {
+			return true
+		}
+		n, err := encoding.PeekValueLengthWithOffsetsAndType(b, dataOffset, typ)
+		if err != nil {
+			return true
+		}
+		colID += delta
+		if colID == p.ColumnID {
+			return typ == encoding.Type(p.ValueType) &&
+				bytes.Equal(b[dataOffset:n], p.EncodedValue)
+		}
+		if colID > p.ColumnID {
+			return true
+		}
+		b = b[n:]
+	}
+	return true
+}
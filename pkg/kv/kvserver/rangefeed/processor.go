@@ -464,6 +464,10 @@ func (p *Processor) sendStop(pErr *kvpb.Error) {
 // The optionally provided "catch-up" iterator is used to read changes from the
 // engine which occurred after the provided start timestamp (exclusive).
 //
+// The optionally provided filter is evaluated against each event, including
+// those emitted by the catch-up scan, and events that do not match it are not
+// sent to the stream.
+//
 // If the method returns false, the processor will have been stopped, so calling
 // Stop is not necessary. If the method returns true, it will also return an
 // updated operation filter that includes the operations required by the new
@@ -477,6 +481,7 @@ func (p *Processor) Register(
 	startTS hlc.Timestamp,
 	catchUpIterConstructor CatchUpIteratorConstructor,
 	withDiff bool,
+	filter *kvpb.RangeFeedFilter,
 	stream Stream,
 	disconnectFn func(),
 	done *future.ErrorFuture,
@@ -487,7 +492,7 @@ func (p *Processor) Register(
 	p.syncEventC()
 
 	r := newRegistration(
-		span.AsRawSpanWithNoLocals(), startTS, catchUpIterConstructor, withDiff,
+		span.AsRawSpanWithNoLocals(), startTS, catchUpIterConstructor, withDiff, filter,
 		p.Config.EventChanCap, p.Metrics, stream, disconnectFn, done,
 	)
 	select {
@@ -221,6 +221,7 @@ func TestProcessorBasic(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* filter */
 		r1Stream,
 		func() {},
 		&r1Done,
@@ -343,6 +344,7 @@ func TestProcessorBasic(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,  /* catchUpIter */
 		true, /* withDiff */
+		nil,  /* filter */
 		r2Stream,
 		func() {},
 		&r2Done,
@@ -427,6 +429,7 @@ func TestProcessorBasic(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* filter */
 		r3Stream,
 		func() {},
 		&r3Done,
@@ -455,7 +458,7 @@ func TestNilProcessor(t *testing.T) {
 	require.Panics(t, func() { _ = p.Start(stopper, nil) })
 	require.Panics(t, func() {
 		var done future.ErrorFuture
-		p.Register(roachpb.RSpan{}, hlc.Timestamp{}, nil, false, nil,
+		p.Register(roachpb.RSpan{}, hlc.Timestamp{}, nil, false, nil, nil,
 			func() {}, &done,
 		)
 	})
@@ -475,6 +478,7 @@ func TestProcessorSlowConsumer(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* filter */
 		r1Stream,
 		func() {},
 		&r1Done,
@@ -486,6 +490,7 @@ func TestProcessorSlowConsumer(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* filter */
 		r2Stream,
 		func() {},
 		&r2Done,
@@ -589,6 +594,7 @@ func TestProcessorMemoryBudgetExceeded(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* filter */
 		r1Stream,
 		func() {},
 		&r1Done,
@@ -659,6 +665,7 @@ func TestProcessorMemoryBudgetReleased(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* filter */
 		r1Stream,
 		func() {},
 		&r1Done,
@@ -734,6 +741,7 @@ func TestProcessorInitializeResolvedTimestamp(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* filter */
 		r1Stream,
 		func() {},
 		&r1Done,
@@ -978,7 +986,7 @@ func TestProcessorConcurrentStop(t *testing.T) {
 			runtime.Gosched()
 			s := newTestStream()
 			var done future.ErrorFuture
-			p.Register(p.Span, hlc.Timestamp{}, nil, false, s,
+			p.Register(p.Span, hlc.Timestamp{}, nil, false, nil, s,
 				func() {}, &done)
 		}()
 		go func() {
@@ -1048,7 +1056,7 @@ func TestProcessorRegistrationObservesOnlyNewEvents(t *testing.T) {
 			s := newTestStream()
 			regs[s] = firstIdx
 			var done future.ErrorFuture
-			p.Register(p.Span, hlc.Timestamp{}, nil, false,
+			p.Register(p.Span, hlc.Timestamp{}, nil, false, nil,
 				s, func() {}, &done)
 			regDone <- struct{}{}
 		}
@@ -1148,6 +1156,7 @@ func TestBudgetReleaseOnProcessorStop(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* filter */
 		rStream,
 		func() {},
 		&done,
@@ -1239,6 +1248,7 @@ func TestBudgetReleaseOnLastStreamError(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* filter */
 		rStream,
 		func() {},
 		&done,
@@ -1319,6 +1329,7 @@ func TestBudgetReleaseOnOneStreamError(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* filter */
 		r1Stream,
 		func() {},
 		&r1Done,
@@ -1333,6 +1344,7 @@ func TestBudgetReleaseOnOneStreamError(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* filter */
 		r2Stream,
 		func() {},
 		&r2Done,
@@ -1492,6 +1504,7 @@ func BenchmarkProcessorWithBudget(b *testing.B) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* filter */
 		r1Stream,
 		func() {},
 		&r1Done,
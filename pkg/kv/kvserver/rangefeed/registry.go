@@ -79,6 +79,7 @@ type registration struct {
 	span             roachpb.Span
 	catchUpTimestamp hlc.Timestamp // exclusive
 	withDiff         bool
+	filter           eventFilter
 	metrics          *Metrics
 
 	// catchUpIterConstructor is used to construct the catchUpIter if necessary.
@@ -124,6 +125,7 @@ func newRegistration(
 	startTS hlc.Timestamp,
 	catchUpIterConstructor CatchUpIteratorConstructor,
 	withDiff bool,
+	filter *kvpb.RangeFeedFilter,
 	bufferSz int,
 	metrics *Metrics,
 	stream Stream,
@@ -135,6 +137,7 @@ func newRegistration(
 		catchUpTimestamp:       startTS,
 		catchUpIterConstructor: catchUpIterConstructor,
 		withDiff:               withDiff,
+		filter:                 makeEventFilter(filter),
 		metrics:                metrics,
 		stream:                 stream,
 		done:                   done,
@@ -387,7 +390,17 @@ func (r *registration) maybeRunCatchUpScan(ctx context.Context) error {
 		r.metrics.RangeFeedCatchUpScanNanos.Inc(timeutil.Since(start).Nanoseconds())
 	}()
 
-	return catchUpIter.CatchUpScan(ctx, r.stream.Send, r.withDiff)
+	outputFn := r.stream.Send
+	if !r.filter.isEmpty() {
+		outputFn = func(event *kvpb.RangeFeedEvent) error {
+			if !r.filter.matches(event) {
+				r.metrics.RangeFeedFilteredEvents.Inc(1)
+				return nil
+			}
+			return r.stream.Send(event)
+		}
+	}
+	return catchUpIter.CatchUpScan(ctx, outputFn, r.withDiff)
 }
 
 // ID implements interval.Interface.
@@ -474,6 +487,11 @@ func (reg *registry) PublishToOverlapping(
 		// Don't publish events if they are equal to or less
 		// than the registration's starting timestamp.
 		if r.catchUpTimestamp.Less(minTS) {
+			// Don't publish events that the registration filters out.
+			if !r.filter.matches(event) {
+				r.metrics.RangeFeedFilteredEvents.Inc(1)
+				return false, nil
+			}
 			r.publish(ctx, event, alloc)
 		}
 		return false, nil
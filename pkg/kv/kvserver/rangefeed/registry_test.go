@@ -15,10 +15,11 @@ import (
 	"fmt"
 	"testing"
 
-	_ "github.com/cockroachdb/cockroach/pkg/keys" // hook up pretty printer
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/future"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
@@ -118,6 +119,7 @@ func newTestRegistration(
 		ts,
 		makeCatchUpIteratorConstructor(catchup),
 		withDiff,
+		nil, /* filter */
 		5,
 		NewMetrics(),
 		s,
@@ -548,6 +550,133 @@ func TestRegistryPublishBeneathStartTimestamp(t *testing.T) {
 	r.disconnect(nil)
 }
 
+func TestRegistryPublishFiltered(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	reg := makeRegistry(NewMetrics())
+
+	tablePrefix := keys.SystemSQLCodec.TablePrefix(100)
+	tableSpan := roachpb.Span{Key: tablePrefix, EndKey: tablePrefix.PrefixEnd()}
+	rowKey := roachpb.Key(encoding.EncodeUvarintAscending(keys.SystemSQLCodec.IndexPrefix(100, 1), 1))
+	fam0Key := keys.MakeFamilyKey(rowKey.Clone(), 0)
+	fam2Key := keys.MakeFamilyKey(rowKey.Clone(), 2)
+	indexKey := keys.SystemSQLCodec.IndexPrefix(100, 1)
+
+	r := newTestRegistration(tableSpan, hlc.Timestamp{}, nil, false /* withDiff */)
+	r.filter = makeEventFilter(&kvpb.RangeFeedFilter{FamilyIDs: []uint32{2}, OmitDeletes: true})
+	go r.runOutputLoop(context.Background(), 0)
+	reg.Register(&r.registration)
+
+	val := roachpb.Value{RawBytes: []byte("val"), Timestamp: hlc.Timestamp{WallTime: 1}}
+	tombstone := roachpb.Value{RawBytes: []byte{}, Timestamp: hlc.Timestamp{WallTime: 1}}
+	valueEvent := func(key roachpb.Key, value roachpb.Value) *kvpb.RangeFeedEvent {
+		ev := new(kvpb.RangeFeedEvent)
+		ev.MustSetValue(&kvpb.RangeFeedValue{Key: key, Value: value})
+		return ev
+	}
+	fam0Ev := valueEvent(fam0Key, val)
+	fam2Ev := valueEvent(fam2Key, val)
+	fam2DelEv := valueEvent(fam2Key, tombstone)
+	// Keys that are not SQL row keys are not filtered by column family.
+	indexEv := valueEvent(indexKey, val)
+	delRangeEv := new(kvpb.RangeFeedEvent)
+	delRangeEv.MustSetValue(&kvpb.RangeFeedDeleteRange{
+		Span: tableSpan, Timestamp: hlc.Timestamp{WallTime: 1},
+	})
+	checkpointEv := new(kvpb.RangeFeedEvent)
+	checkpointEv.MustSetValue(&kvpb.RangeFeedCheckpoint{
+		Span: tableSpan, ResolvedTS: hlc.Timestamp{WallTime: 1},
+	})
+
+	for _, ev := range []*kvpb.RangeFeedEvent{
+		fam0Ev, fam2Ev, fam2DelEv, indexEv, delRangeEv, checkpointEv,
+	} {
+		reg.PublishToOverlapping(ctx, tableSpan, ev, nil /* alloc */)
+	}
+	require.NoError(t, reg.waitForCaughtUp(all))
+	require.Equal(t, []*kvpb.RangeFeedEvent{fam2Ev, indexEv, checkpointEv}, r.Events())
+	require.Equal(t, int64(3), r.metrics.RangeFeedFilteredEvents.Count())
+
+	r.disconnect(nil)
+}
+
+func TestRegistryPublishFilteredPredicates(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	reg := makeRegistry(NewMetrics())
+
+	tablePrefix := keys.SystemSQLCodec.TablePrefix(100)
+	tableSpan := roachpb.Span{Key: tablePrefix, EndKey: tablePrefix.PrefixEnd()}
+	// Rows of a table with a primary key on (a, b), filtered on b = 2 and on
+	// a value column 3 = 'x' in family 1.
+	rowKey := func(a, b int64, famID uint32) roachpb.Key {
+		k := keys.SystemSQLCodec.IndexPrefix(100, 1)
+		k = encoding.EncodeVarintAscending(k, a)
+		k = encoding.EncodeVarintAscending(k, b)
+		return keys.MakeFamilyKey(k, famID)
+	}
+	tupleValue := func(c3 string) roachpb.Value {
+		var tuple []byte
+		tuple = encoding.EncodeIntValue(tuple, 2, 1)
+		if c3 != "" {
+			tuple = encoding.EncodeBytesValue(tuple, 1, []byte(c3))
+		}
+		v := roachpb.Value{Timestamp: hlc.Timestamp{WallTime: 1}}
+		v.SetTuple(tuple)
+		return v
+	}
+	xValue := encoding.EncodeBytesValue(nil, encoding.NoColumnID, []byte("x"))
+	r := newTestRegistration(tableSpan, hlc.Timestamp{}, nil, false /* withDiff */)
+	r.filter = makeEventFilter(&kvpb.RangeFeedFilter{
+		KeyPredicates: []kvpb.RangeFeedKeyPredicate{{
+			TableID:      100,
+			IndexID:      1,
+			ColumnOffset: 1,
+			EncodedValue: encoding.EncodeVarintAscending(nil, 2),
+		}},
+		ValuePredicates: []kvpb.RangeFeedValuePredicate{{
+			TableID:      100,
+			IndexID:      1,
+			FamilyID:     1,
+			ColumnID:     3,
+			ValueType:    int32(encoding.Bytes),
+			EncodedValue: xValue[1:],
+		}},
+	})
+	go r.runOutputLoop(context.Background(), 0)
+	reg.Register(&r.registration)
+
+	tombstone := roachpb.Value{RawBytes: []byte{}, Timestamp: hlc.Timestamp{WallTime: 1}}
+	valueEvent := func(key roachpb.Key, value roachpb.Value) *kvpb.RangeFeedEvent {
+		ev := new(kvpb.RangeFeedEvent)
+		ev.MustSetValue(&kvpb.RangeFeedValue{Key: key, Value: value})
+		return ev
+	}
+	matchEv := valueEvent(rowKey(1, 2, 1), tupleValue("x"))
+	// The key doesn't satisfy b = 2.
+	wrongKeyEv := valueEvent(rowKey(2, 1, 1), tupleValue("x"))
+	// The value doesn't satisfy column 3 = 'x'.
+	wrongValueEv := valueEvent(rowKey(1, 2, 1), tupleValue("y"))
+	// A NULL column can't be evaluated, so the value isn't filtered.
+	nullValueEv := valueEvent(rowKey(1, 2, 1), tupleValue(""))
+	// Value predicates don't apply to other families.
+	otherFamilyEv := valueEvent(rowKey(1, 2, 0), tupleValue("y"))
+	// Deletes are filtered by key predicates only.
+	deleteEv := valueEvent(rowKey(1, 2, 1), tombstone)
+	wrongKeyDeleteEv := valueEvent(rowKey(1, 3, 1), tombstone)
+
+	for _, ev := range []*kvpb.RangeFeedEvent{
+		matchEv, wrongKeyEv, wrongValueEv, nullValueEv, otherFamilyEv, deleteEv, wrongKeyDeleteEv,
+	} {
+		reg.PublishToOverlapping(ctx, tableSpan, ev, nil /* alloc */)
+	}
+	require.NoError(t, reg.waitForCaughtUp(all))
+	require.Equal(t, []*kvpb.RangeFeedEvent{matchEv, nullValueEv, otherFamilyEv, deleteEv}, r.Events())
+	require.Equal(t, int64(3), r.metrics.RangeFeedFilteredEvents.Count())
+
+	r.disconnect(nil)
+}
+
 func TestRegistrationString(t *testing.T) {
 	testCases := []struct {
 		r   registration
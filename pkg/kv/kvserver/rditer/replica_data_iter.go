@@ -78,6 +78,30 @@ func MakeReplicatedKeySpans(d *roachpb.RangeDescriptor) []roachpb.Span {
 	})
 }
 
+// MakeWitnessReplicatedKeySpans returns the key spans that are fully Raft
+// replicated for the given Range and retained by witness replicas, which
+// hold the Range's local keys but none of its user data. These are returned
+// in lexicographically sorted order:
+//
+// 1. Replicated range-id local key span.
+// 2. "Local" key span (range descriptor, etc)
+// 3. Range-local lock-table key span.
+func MakeWitnessReplicatedKeySpans(d *roachpb.RangeDescriptor) []roachpb.Span {
+	spans := MakeReplicatedKeySpans(d)
+	return spans[:len(spans)-2]
+}
+
+// MakeUserDataKeySpans returns the key spans that are fully Raft replicated
+// for the given Range but not retained by witness replicas, in
+// lexicographically sorted order:
+//
+// 1. Global lock-table key span.
+// 2. User key span.
+func MakeUserDataKeySpans(d *roachpb.RangeDescriptor) []roachpb.Span {
+	spans := MakeReplicatedKeySpans(d)
+	return spans[len(spans)-2:]
+}
+
 // makeReplicatedKeySpansExceptLockTable returns all key spans that are fully Raft
 // replicated for the given Range, except for the lock table spans. These are
 // returned in the following sorted order:
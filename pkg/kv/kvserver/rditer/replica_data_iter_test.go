@@ -484,6 +484,14 @@ func TestReplicaKeyRanges(t *testing.T) {
 	checkOrdering(t, makeAllKeySpans(&desc))
 	checkOrdering(t, MakeReplicatedKeySpans(&desc))
 	checkOrdering(t, makeReplicatedKeySpansExceptLockTable(&desc))
+	checkOrdering(t, MakeWitnessReplicatedKeySpans(&desc))
+	checkOrdering(t, MakeUserDataKeySpans(&desc))
+
+	// The spans retained by witnesses and the user data spans partition the
+	// replicated key spans.
+	require.Equal(t, MakeReplicatedKeySpans(&desc),
+		append(MakeWitnessReplicatedKeySpans(&desc), MakeUserDataKeySpans(&desc)...))
+	require.Equal(t, desc.KeySpan().AsRawSpanWithNoLocals(), MakeUserDataKeySpans(&desc)[1])
 }
 
 func BenchmarkReplicaEngineDataIterator(b *testing.B) {
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/gc"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/hotkeycache"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/load"
@@ -207,6 +208,22 @@ type Replica struct {
 	// inform load based lease and replica rebalancing decisions.
 	loadStats *load.ReplicaLoad
 
+	// hotKeyCache caches the latest versions of the replica's hot keys, which
+	// allows the leaseholder to serve reads of those keys without reading from
+	// the storage engine. See replica_hot_keys.go.
+	hotKeyCache *hotkeycache.Cache
+
+	// writeRateLimiter enforces the write rate limits configured in the span
+	// config of the range. See replica_write_rate_limit.go.
+	writeRateLimiter writeRateLimiter
+
+	// quarantined is set when the store scrubber finds the replica's data to be
+	// corrupted. Quarantined replicas don't acquire leases, serve follower
+	// reads, or send snapshots, and are replaced by the store scrubber. The
+	// quarantine is persisted under keys.RangeQuarantinedKey, and is lifted when
+	// a snapshot replaces the replica's data. See store_scrubber.go.
+	quarantined atomic.Bool
+
 	// Held in read mode during read-only commands. Held in exclusive mode to
 	// prevent read-only commands from executing. Acquired before the embedded
 	// RWMutex.
@@ -818,6 +835,10 @@ type Replica struct {
 	// semaphores.
 	splitQueueThrottle, mergeQueueThrottle util.EveryN
 
+	// Throttle how often the set of hot keys in the hotKeyCache is refreshed
+	// from the load stats.
+	hotKeysRefreshThrottle util.EveryN
+
 	// loadBasedSplitter keeps information about load-based splitting.
 	loadBasedSplitter split.Decider
 
@@ -923,6 +944,7 @@ func (r *Replica) SetSpanConfig(conf roachpb.SpanConfig) {
 		conf = knobs.SetSpanConfigInterceptor(r.descRLocked(), conf)
 	}
 	r.mu.conf, r.mu.spanConfigExplicitlySet = conf, true
+	r.writeRateLimiter.update(conf)
 }
 
 // IsFirstRange returns true if this is the first range.
@@ -60,10 +60,21 @@ type replicaAppBatch struct {
 	// changeRemovesReplica tracks whether the command in the batch (there must
 	// be only one) removes this replica from the range.
 	changeRemovesReplica bool
+	// witness is set if this replica is a witness, in which case only the
+	// writes to the range's local keys are applied. See addWitnessWriteBatch.
+	witness bool
 
 	start                   time.Time // time at NewBatch()
 	followerStoreWriteBytes kvadmission.FollowerStoreWriteBytes
 
+	// hotKeyWrites accumulates the keys written by the commands in the batch,
+	// if hot key writes are tracked (see Replica.hotKeyWritesTracked). If
+	// hotKeyWritesAll is set, the batch contains writes which can't be
+	// attributed to individual keys, and the entire hot key cache must be
+	// invalidated.
+	hotKeyWrites    []roachpb.Key
+	hotKeyWritesAll bool
+
 	// Reused by addAppliedStateKeyToBatch to avoid heap allocations.
 	asAlloc enginepb.RangeAppliedState
 }
@@ -137,7 +148,11 @@ func (b *replicaAppBatch) Stage(
 	}
 
 	// Stage the command's write batch in the application batch.
-	if err := b.ab.addWriteBatch(ctx, b.batch, cmd); err != nil {
+	if b.witness {
+		if err := b.ab.addWitnessWriteBatch(ctx, b.batch, cmd); err != nil {
+			return nil, err
+		}
+	} else if err := b.ab.addWriteBatch(ctx, b.batch, cmd); err != nil {
 		return nil, err
 	}
 
@@ -150,6 +165,7 @@ func (b *replicaAppBatch) Stage(
 		eng:         b.r.store.TODOEngine(),
 		sideloaded:  b.r.raftMu.sideloaded,
 		bulkLimiter: b.r.store.limiters.BulkIOWriteRate,
+		witness:     b.witness,
 	}); err != nil {
 		return nil, err
 	}
@@ -204,6 +220,18 @@ func (b *replicaAppBatch) runPreAddTriggersReplicaOnly(
 			}
 		}
 	}
+	if b.r.hotKeyWritesTracked() && !b.hotKeyWritesAll {
+		if cmd.ReplicatedResult().AddSSTable != nil {
+			b.hotKeyWritesAll = true
+		} else if wb := cmd.Cmd.WriteBatch; wb != nil {
+			var err error
+			b.hotKeyWrites, b.hotKeyWritesAll, err = collectHotKeyWrites(wb.Data, b.hotKeyWrites)
+			if err != nil {
+				log.Errorf(ctx, "unable to collect keys of committed WriteBatch: %+v", err)
+				b.hotKeyWritesAll = true
+			}
+		}
+	}
 	return nil
 }
 
@@ -485,6 +513,16 @@ func (b *replicaAppBatch) runPostAddTriggersReplicaOnly(
 		}
 	}
 
+	// Detect if this command turns us into a witness. If so, we stage the
+	// removal of the range's user data, which witnesses don't retain, into
+	// this batch.
+	if change := res.ChangeReplicas; change != nil && !b.changeRemovesReplica &&
+		changeMakesStoreWitness(b.state.Desc, change, b.r.store.StoreID()) {
+		if err := clearWitnessUserData(b.state.Desc, b.batch); err != nil {
+			return errors.Wrapf(err, "unable to clear user data of witness")
+		}
+	}
+
 	// Provide the command's corresponding logical operations to the Replica's
 	// rangefeed. Only do so if the WriteBatch is non-nil, in which case the
 	// rangefeed requires there to be a corresponding logical operation log or
@@ -609,6 +647,13 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	// Record the number of keys written to the replica.
 	b.r.loadStats.RecordWriteKeys(float64(b.ab.numMutations))
 
+	// Invalidate the written keys in the hot key cache now that the writes are
+	// visible in the storage engine. The latches of the commands in the batch
+	// are only released after this.
+	if len(b.hotKeyWrites) > 0 || b.hotKeyWritesAll {
+		r.handleHotKeyWritesRaftMuLocked(b.hotKeyWrites, b.hotKeyWritesAll)
+	}
+
 	now := timeutil.Now()
 	if needsSplitBySize && r.splitQueueThrottle.ShouldProcess(now) {
 		r.store.splitQueue.MaybeAddAsync(ctx, r, r.store.Clock().NowAsClockTimestamp())
@@ -145,6 +145,9 @@ func (sm *replicaStateMachine) NewBatch() apply.Batch {
 	b.state.Stats = &sm.stats
 	*b.state.Stats = *r.mu.state.Stats
 	b.closedTimestampSetter = r.mu.closedTimestampSetter
+	if repl, ok := r.mu.state.Desc.GetReplicaDescriptor(r.store.StoreID()); ok {
+		b.witness = repl.IsWitness()
+	}
 	r.mu.RUnlock()
 	b.start = timeutil.Now()
 	return b
@@ -648,6 +648,12 @@ func (r *Replica) AdminMerge(
 		if !replicasCollocated(lReplicas.Descriptors(), rReplicas.Descriptors()) {
 			return errors.Errorf("ranges not collocated; %s != %s", lReplicas, rReplicas)
 		}
+		// The RHS's data is subsumed by the LHS's replica on every store, so a
+		// store must not hold a witness of one side and a full replica of the
+		// other.
+		if !replicasCollocated(lReplicas.WitnessDescriptors(), rReplicas.WitnessDescriptors()) {
+			return errors.Errorf("ranges' witnesses not collocated; %s != %s", lReplicas, rReplicas)
+		}
 
 		disableWaitForReplicasInTesting := r.store.TestingKnobs() != nil &&
 			r.store.TestingKnobs().DisableMergeWaitForReplicasInit
@@ -1006,6 +1012,12 @@ func (r *Replica) changeReplicasImpl(
 	if err := validateReplicationChanges(desc, chgs); err != nil {
 		return nil, errors.Mark(err, errMarkInvalidReplicationChange)
 	}
+	// Nodes running older binaries can't handle witness replicas.
+	if len(chgs.WitnessAdditions()) > 0 &&
+		!r.store.ClusterSettings().Version.IsActive(ctx, clusterversion.V23_2_Witnesses) {
+		return nil, errors.Errorf("witnesses are not supported until upgrade to version %v is finalized",
+			clusterversion.ByKey(clusterversion.V23_2_Witnesses))
+	}
 	targets := synthesizeTargetsByChangeType(chgs)
 
 	// NB: As of the time of this writing,`AdminRelocateRange` will only execute
@@ -1015,10 +1027,12 @@ func (r *Replica) changeReplicasImpl(
 	//
 	// We choose to execute changes in the following order:
 	// 1. Promotions / demotions / swaps between voters and non-voters
-	// 2. Voter additions
+	// 2. Voter additions, along with the removal of the witnesses they replace
 	// 3. Voter removals
-	// 4. Non-voter additions
-	// 5. Non-voter removals
+	// 4. Witness additions
+	// 5. Witness removals
+	// 6. Non-voter additions
+	// 7. Non-voter removals
 	//
 	// This order is meant to be symmetric with how the allocator prioritizes
 	// these actions. Broadly speaking, we first want to add a missing voter (and
@@ -1070,7 +1084,7 @@ func (r *Replica) changeReplicasImpl(
 	if len(targets.voterAdditions)+len(targets.voterRemovals) > 0 {
 		desc, err = r.execReplicationChangesForVoters(
 			ctx, desc, reason, details,
-			targets.voterAdditions, targets.voterRemovals,
+			targets.voterAdditions, targets.voterRemovals, targets.witnessPromotions,
 		)
 		if err != nil {
 			// If the error occurred while transitioning out of an atomic replication
@@ -1093,6 +1107,40 @@ func (r *Replica) changeReplicasImpl(
 		}
 	}
 
+	if adds := targets.witnessAdditions; len(adds) > 0 {
+		// Witnesses are added as learners first, and promoted to witnesses once
+		// they have caught up, just like voters.
+		//
+		// TODO(kvserver): the initial snapshot sent to the learner contains the
+		// range's user data, which the replica clears once it becomes a witness.
+		// It would be cheaper to send a snapshot of the range's local keys only.
+		desc, err = r.initializeRaftLearners(
+			ctx, desc, priority, senderName, senderQueuePriority, reason, details, adds, roachpb.LEARNER,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range adds {
+			desc, err = r.execWitnessChange(
+				ctx, desc, reason, details, target, internalChangeTypePromoteLearnerToWitness,
+			)
+			if err != nil {
+				// Don't leave a learner replica lying around if we didn't succeed in
+				// promoting it to a witness.
+				log.Infof(ctx, "could not promote %v to witness, rolling back: %v", target, err)
+				r.tryRollbackRaftLearner(ctx, r.Desc(), target, reason, details)
+				return nil, err
+			}
+		}
+	}
+
+	for _, target := range targets.witnessRemovals {
+		desc, err = r.execWitnessChange(ctx, desc, reason, details, target, internalChangeTypeRemoveWitness)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if adds := targets.nonVoterAdditions; len(adds) > 0 {
 		// Add all non-voters and send them initial snapshots since some callers of
 		// `AdminChangeReplicas` (notably the mergeQueue, via `AdminRelocateRange`)
@@ -1140,10 +1188,36 @@ func (r *Replica) changeReplicasImpl(
 	return desc, nil
 }
 
+// execWitnessChange carries out the promotion of a learner to a witness, or
+// the removal of a witness. Since witnesses are Raft voters, these changes
+// modify the quorum, and are carried out one at a time as simple changes.
+func (r *Replica) execWitnessChange(
+	ctx context.Context,
+	desc *roachpb.RangeDescriptor,
+	reason kvserverpb.RangeLogEventReason,
+	details string,
+	target roachpb.ReplicationTarget,
+	typ internalChangeType,
+) (*roachpb.RangeDescriptor, error) {
+	return execChangeReplicasTxn(ctx, r.store.cfg.Tracer(), desc, reason, details,
+		[]internalReplicationChange{{target: target, typ: typ}},
+		changeReplicasTxnArgs{
+			db:                                   r.store.DB(),
+			liveAndDeadReplicas:                  r.store.cfg.StorePool.LiveAndDeadReplicas,
+			logChange:                            r.store.logChange,
+			testForceJointConfig:                 r.store.TestingKnobs().ReplicationAlwaysUseJointConfig,
+			testAllowDangerousReplicationChanges: r.store.TestingKnobs().AllowDangerousReplicationChanges,
+		})
+}
+
 type targetsForReplicationChanges struct {
-	voterDemotions, nonVoterPromotions  []roachpb.ReplicationTarget
+	voterDemotions, nonVoterPromotions []roachpb.ReplicationTarget
+	// witnessPromotions are the witnesses removed atomically with the addition
+	// of the voters replacing them.
+	witnessPromotions                   []roachpb.ReplicationTarget
 	voterAdditions, voterRemovals       []roachpb.ReplicationTarget
 	nonVoterAdditions, nonVoterRemovals []roachpb.ReplicationTarget
+	witnessAdditions, witnessRemovals   []roachpb.ReplicationTarget
 }
 
 // synthesizeTargetsByChangeType groups replication changes in the
@@ -1152,8 +1226,11 @@ type targetsForReplicationChanges struct {
 // In particular, it coalesces ReplicationChanges of types ADD_VOTER and
 // REMOVE_NON_VOTER on a given target as promotions of non-voters into voters
 // and likewise, ADD_NON_VOTER and REMOVE_VOTER changes for a given target as
-// demotions of voters into non-voters. The rest of the changes are handled
-// distinctly and are thus segregated in the return result.
+// demotions of voters into non-voters. REMOVE_WITNESS changes accompanied by
+// ADD_VOTER changes are coalesced as promotions of witnesses, i.e. replacements
+// of witnesses by full voters on other stores, which are executed atomically
+// with the voter additions. The rest of the changes are handled distinctly and
+// are thus segregated in the return result.
 func synthesizeTargetsByChangeType(
 	chgs kvpb.ReplicationChanges,
 ) (result targetsForReplicationChanges) {
@@ -1170,6 +1247,17 @@ func synthesizeTargetsByChangeType(
 	result.nonVoterAdditions = subtractTargets(chgs.NonVoterAdditions(), chgs.VoterRemovals())
 	result.nonVoterRemovals = subtractTargets(chgs.NonVoterRemovals(), chgs.VoterAdditions())
 
+	// Witnesses can't be promoted in place, since they don't have the range's
+	// data. Instead, the witnesses are removed in the same joint configuration
+	// which adds the voters replacing them, so that the range's number of
+	// voting replicas never drops.
+	result.witnessAdditions = chgs.WitnessAdditions()
+	if len(chgs.VoterAdditions()) > 0 {
+		result.witnessPromotions = chgs.WitnessRemovals()
+	} else {
+		result.witnessRemovals = chgs.WitnessRemovals()
+	}
+
 	return result
 }
 
@@ -1428,6 +1516,15 @@ func validateAdditionsPerStore(
 					return errors.AssertionFailedf(
 						"trying to add a non-voter to a store that already has a %s", t)
 				}
+			case roachpb.WITNESS:
+				// Witnesses don't have the range's data, so they can't be promoted in
+				// place. They're replaced by voters on other stores instead.
+				if chg.ChangeType == roachpb.ADD_WITNESS {
+					return errors.AssertionFailedf(
+						"trying to add a witness to a store that already has a %s", t)
+				}
+				return errors.AssertionFailedf(
+					"trying to add(%+v) to a store that already has a %s", chg, t)
 			default:
 				return errors.AssertionFailedf("store(%d) being added to already contains a"+
 					" replica of an unexpected type: %s", storeID, t)
@@ -1462,6 +1559,11 @@ func validateRemovals(desc *roachpb.RangeDescriptor, chgsByStoreID changesByStor
 					return errors.AssertionFailedf("type of replica being removed (%s) does not match"+
 						" expectation for change: %+v", t, chg)
 				}
+			case roachpb.WITNESS:
+				if chg.ChangeType != roachpb.REMOVE_WITNESS {
+					return errors.AssertionFailedf("type of replica being removed (%s) does not match"+
+						" expectation for change: %+v", t, chg)
+				}
 			default:
 				return errors.AssertionFailedf("unexpected replica type for removal %+v: %s", chg, t)
 			}
@@ -1876,13 +1978,14 @@ func (r *Replica) execReplicationChangesForVoters(
 	desc *roachpb.RangeDescriptor,
 	reason kvserverpb.RangeLogEventReason,
 	details string,
-	voterAdditions, voterRemovals []roachpb.ReplicationTarget,
+	voterAdditions, voterRemovals, witnessRemovals []roachpb.ReplicationTarget,
 ) (rangeDesc *roachpb.RangeDescriptor, err error) {
 	// TODO(dan): We allow ranges with learner replicas to split, so in theory
 	// this may want to detect that and retry, sending a snapshot and promoting
 	// both sides.
 
-	iChgs := make([]internalReplicationChange, 0, len(voterAdditions)+len(voterRemovals))
+	iChgs := make([]internalReplicationChange, 0,
+		len(voterAdditions)+len(voterRemovals)+len(witnessRemovals))
 	for _, target := range voterAdditions {
 		iChgs = append(iChgs, internalReplicationChange{target: target, typ: internalChangeTypePromoteLearner})
 	}
@@ -1895,6 +1998,12 @@ func (r *Replica) execReplicationChangesForVoters(
 		iChgs = append(iChgs, internalReplicationChange{target: target, typ: typ})
 	}
 
+	// Witnesses being replaced by the added voters become VOTER_OUTGOING in the
+	// joint configuration, and are removed when leaving it.
+	for _, target := range witnessRemovals {
+		iChgs = append(iChgs, internalReplicationChange{target: target, typ: internalChangeTypeRemoveWitness})
+	}
+
 	desc, err = execChangeReplicasTxn(ctx, r.store.cfg.Tracer(), desc, reason, details, iChgs, changeReplicasTxnArgs{
 		db:                                   r.store.DB(),
 		liveAndDeadReplicas:                  r.store.cfg.StorePool.LiveAndDeadReplicas,
@@ -2009,6 +2118,14 @@ const (
 	// https://github.com/cockroachdb/cockroach/pull/40268
 	internalChangeTypeRemoveLearner
 	internalChangeTypeRemoveNonVoter
+	// internalChangeTypePromoteLearnerToWitness promotes a learner to a witness.
+	// Since the witness is a Raft voter, this changes the quorum and must be a
+	// simple change.
+	internalChangeTypePromoteLearnerToWitness
+	// internalChangeTypeRemoveWitness removes a witness. On its own, this must
+	// be a simple change. Along with the promotion of learners replacing the
+	// witness, the witness becomes a VOTER_OUTGOING in a joint config instead.
+	internalChangeTypeRemoveWitness
 )
 
 // internalReplicationChange is a replication target together with an internal
@@ -2034,6 +2151,23 @@ func (c internalReplicationChanges) isSingleLearnerRemoval() bool {
 	return len(c) == 1 && c[0].typ == internalChangeTypeRemoveLearner
 }
 
+// promotesLearner returns whether the changes promote a learner to a voter.
+func (c internalReplicationChanges) promotesLearner() bool {
+	for _, chg := range c {
+		if chg.typ == internalChangeTypePromoteLearner {
+			return true
+		}
+	}
+	return false
+}
+
+// isWitnessChange returns whether the changes add or remove a witness, which
+// is only possible in a simple (i.e. non-joint) change.
+func (c internalReplicationChanges) isWitnessChange() bool {
+	return len(c) == 1 && (c[0].typ == internalChangeTypePromoteLearnerToWitness ||
+		c[0].typ == internalChangeTypeRemoveWitness)
+}
+
 func prepareChangeReplicasTrigger(
 	ctx context.Context,
 	desc *roachpb.RangeDescriptor,
@@ -2051,7 +2185,7 @@ func prepareChangeReplicasTrigger(
 		}
 
 		useJoint := chgs.useJoint()
-		if fn := testingForceJointConfig; fn != nil && fn() {
+		if fn := testingForceJointConfig; fn != nil && fn() && !chgs.isWitnessChange() {
 			useJoint = true
 		}
 		for _, chg := range chgs {
@@ -2084,6 +2218,32 @@ func prepareChangeReplicasTrigger(
 						chg.target)
 				}
 				added = append(added, rDesc)
+			case internalChangeTypePromoteLearnerToWitness:
+				if useJoint {
+					return nil, errors.Errorf("cannot promote target %v to WITNESS in a joint config", chg.target)
+				}
+				rDesc, prevTyp, ok := updatedDesc.SetReplicaType(chg.target.NodeID, chg.target.StoreID, roachpb.WITNESS)
+				if !ok || prevTyp != roachpb.LEARNER {
+					return nil, errors.Errorf("cannot promote target %v which is missing as LEARNER",
+						chg.target)
+				}
+				added = append(added, rDesc)
+			case internalChangeTypeRemoveWitness:
+				rDesc, ok := updatedDesc.GetReplicaDescriptor(chg.target.StoreID)
+				if !ok || rDesc.Type != roachpb.WITNESS {
+					return nil, errors.Errorf("cannot remove target %v which is missing as WITNESS",
+						chg.target)
+				}
+				if useJoint {
+					if !chgs.promotesLearner() {
+						return nil, errors.Errorf(
+							"cannot remove WITNESS %v in a joint config without promoting a LEARNER", chg.target)
+					}
+					rDesc, _, _ = updatedDesc.SetReplicaType(chg.target.NodeID, chg.target.StoreID, roachpb.VOTER_OUTGOING)
+				} else {
+					rDesc, _ = updatedDesc.RemoveReplica(chg.target.NodeID, chg.target.StoreID)
+				}
+				removed = append(removed, rDesc)
 			case internalChangeTypeRemoveLearner, internalChangeTypeRemoveNonVoter:
 				rDesc, ok := updatedDesc.GetReplicaDescriptor(chg.target.StoreID)
 				if !ok {
@@ -2488,16 +2648,21 @@ func recordRangeEventsInLog(
 ) error {
 	for _, repDesc := range repDescs {
 		isNonVoter := repDesc.Type == roachpb.NON_VOTER
+		isWitness := repDesc.Type == roachpb.WITNESS
 		var typ roachpb.ReplicaChangeType
 		if added {
 			typ = roachpb.ADD_VOTER
 			if isNonVoter {
 				typ = roachpb.ADD_NON_VOTER
+			} else if isWitness {
+				typ = roachpb.ADD_WITNESS
 			}
 		} else {
 			typ = roachpb.REMOVE_VOTER
 			if isNonVoter {
 				typ = roachpb.REMOVE_NON_VOTER
+			} else if isWitness {
+				typ = roachpb.REMOVE_WITNESS
 			}
 		}
 		if err := logChange(
@@ -2911,7 +3076,8 @@ func (r *Replica) validateSnapshotDelegationRequest(
 	// the leaseholder, and we haven't yet applied the configuration change that's
 	// adding the recipient to the range, or we are the leaseholder but have
 	// removed the recipient between starting to send the snapshot and this point.
-	if _, ok := desc.GetReplicaDescriptorByID(req.RecipientReplica.ReplicaID); !ok {
+	recipient, ok := desc.GetReplicaDescriptorByID(req.RecipientReplica.ReplicaID)
+	if !ok {
 		// Recipient replica not found in the current range descriptor.
 		// The sender replica's descriptor may be lagging behind the coordinator's.
 		log.VEventf(ctx, 2,
@@ -2924,6 +3090,19 @@ func (r *Replica) validateSnapshotDelegationRequest(
 		)
 	}
 
+	// Witnesses don't retain the range's user data, so they can only send
+	// snapshots to other witnesses.
+	if sender, ok := desc.GetReplicaDescriptor(r.StoreID()); ok && sender.IsWitness() && !recipient.IsWitness() {
+		return errors.Errorf(
+			"%s: witness cannot send snapshot to %s replica %s", r, recipient.Type, recipient,
+		)
+	}
+
+	// Quarantined replicas have corrupted data, which mustn't be propagated.
+	if r.quarantined.Load() {
+		return errors.Errorf("%s: quarantined replica cannot send snapshot", r)
+	}
+
 	// Check the raft applied state index and term to determine if this replica
 	// is not too far behind the leaseholder. If the delegate is too far behind
 	// that is also needs a snapshot, then any snapshot it sends will be useless.
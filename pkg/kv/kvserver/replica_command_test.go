@@ -129,6 +129,13 @@ func TestValidateReplicationChanges(t *testing.T) {
 			{NodeID: 1, StoreID: 1},
 		},
 	}
+	twoVotersAndAWitness := &roachpb.RangeDescriptor{
+		InternalReplicas: []roachpb.ReplicaDescriptor{
+			{NodeID: 1, StoreID: 1},
+			{NodeID: 2, StoreID: 2},
+			{NodeID: 3, StoreID: 3, Type: roachpb.WITNESS},
+		},
+	}
 
 	type testCase struct {
 		name          string
@@ -411,6 +418,65 @@ func TestValidateReplicationChanges(t *testing.T) {
 			shouldFail:    true,
 			expErrorRegex: "trying to remove a replica that doesn't exist",
 		},
+		{
+			name:      "add a witness",
+			rangeDesc: twoVotersAndALearner,
+			changes: kvpb.ReplicationChanges{
+				{ChangeType: roachpb.ADD_WITNESS, Target: roachpb.ReplicationTarget{NodeID: 2, StoreID: 2}},
+			},
+		},
+		{
+			name:      "add a witness to a store that already has one",
+			rangeDesc: twoVotersAndAWitness,
+			changes: kvpb.ReplicationChanges{
+				{ChangeType: roachpb.ADD_WITNESS, Target: roachpb.ReplicationTarget{NodeID: 3, StoreID: 3}},
+			},
+			shouldFail:    true,
+			expErrorRegex: "trying to add a witness to a store that already has a WITNESS",
+		},
+		{
+			name:      "remove a witness",
+			rangeDesc: twoVotersAndAWitness,
+			changes: kvpb.ReplicationChanges{
+				{ChangeType: roachpb.REMOVE_WITNESS, Target: roachpb.ReplicationTarget{NodeID: 3, StoreID: 3}},
+			},
+		},
+		{
+			name:      "remove a witness as a voter",
+			rangeDesc: twoVotersAndAWitness,
+			changes: kvpb.ReplicationChanges{
+				{ChangeType: roachpb.REMOVE_VOTER, Target: roachpb.ReplicationTarget{NodeID: 3, StoreID: 3}},
+			},
+			shouldFail:    true,
+			expErrorRegex: "type of replica being removed.*does not match expectation",
+		},
+		{
+			name:      "remove a voter as a witness",
+			rangeDesc: twoVotersAndAWitness,
+			changes: kvpb.ReplicationChanges{
+				{ChangeType: roachpb.REMOVE_WITNESS, Target: roachpb.ReplicationTarget{NodeID: 1, StoreID: 1}},
+			},
+			shouldFail:    true,
+			expErrorRegex: "type of replica being removed.*does not match expectation",
+		},
+		{
+			name:      "witness promotion",
+			rangeDesc: twoVotersAndAWitness,
+			changes: kvpb.ReplicationChanges{
+				{ChangeType: roachpb.ADD_VOTER, Target: roachpb.ReplicationTarget{NodeID: 4, StoreID: 4}},
+				{ChangeType: roachpb.REMOVE_WITNESS, Target: roachpb.ReplicationTarget{NodeID: 3, StoreID: 3}},
+			},
+		},
+		{
+			name:      "witness promotion in place",
+			rangeDesc: twoVotersAndAWitness,
+			changes: kvpb.ReplicationChanges{
+				{ChangeType: roachpb.ADD_VOTER, Target: roachpb.ReplicationTarget{NodeID: 3, StoreID: 3}},
+				{ChangeType: roachpb.REMOVE_WITNESS, Target: roachpb.ReplicationTarget{NodeID: 3, StoreID: 3}},
+			},
+			shouldFail:    true,
+			expErrorRegex: "trying to add",
+		},
 	}
 
 	for _, test := range tests {
@@ -433,6 +499,8 @@ func TestSynthesizeTargetsByChangeType(t *testing.T) {
 		expPromotions, expDemotions               []int32
 		expVoterAdditions, expVoterRemovals       []int32
 		expNonVoterAdditions, expNonVoterRemovals []int32
+		expWitnessPromotions                      []int32
+		expWitnessAdditions, expWitnessRemovals   []int32
 	}
 
 	mkTarget := func(t int32) roachpb.ReplicationTarget {
@@ -594,6 +662,29 @@ func TestSynthesizeTargetsByChangeType(t *testing.T) {
 			expNonVoterAdditions: []int32{5},
 			expNonVoterRemovals:  []int32{6},
 		},
+		{
+			name: "simple witness addition",
+			changes: []kvpb.ReplicationChange{
+				{ChangeType: roachpb.ADD_WITNESS, Target: mkTarget(2)},
+			},
+			expWitnessAdditions: []int32{2},
+		},
+		{
+			name: "simple witness removal",
+			changes: []kvpb.ReplicationChange{
+				{ChangeType: roachpb.REMOVE_WITNESS, Target: mkTarget(2)},
+			},
+			expWitnessRemovals: []int32{2},
+		},
+		{
+			name: "promote witness to voter",
+			changes: []kvpb.ReplicationChange{
+				{ChangeType: roachpb.ADD_VOTER, Target: mkTarget(2)},
+				{ChangeType: roachpb.REMOVE_WITNESS, Target: mkTarget(3)},
+			},
+			expVoterAdditions:    []int32{2},
+			expWitnessPromotions: []int32{3},
+		},
 	}
 
 	for _, test := range tests {
@@ -605,6 +696,9 @@ func TestSynthesizeTargetsByChangeType(t *testing.T) {
 			require.Equal(t, result.voterRemovals, mkTargetList(test.expVoterRemovals))
 			require.Equal(t, result.nonVoterAdditions, mkTargetList(test.expNonVoterAdditions))
 			require.Equal(t, result.nonVoterRemovals, mkTargetList(test.expNonVoterRemovals))
+			require.Equal(t, result.witnessPromotions, mkTargetList(test.expWitnessPromotions))
+			require.Equal(t, result.witnessAdditions, mkTargetList(test.expWitnessAdditions))
+			require.Equal(t, result.witnessRemovals, mkTargetList(test.expWitnessRemovals))
 		})
 	}
 }
@@ -326,7 +326,12 @@ func (r *Replica) runConsistencyCheck(
 	}
 	ccRes := res.(*kvpb.ComputeChecksumResponse)
 
-	replicas := r.Desc().Replicas().Descriptors()
+	// Witnesses don't retain the range's user data, so they don't compute
+	// checksums, and neither do outgoing voters, which may be witnesses being
+	// replaced. See computeChecksumPostApply.
+	replicas := r.Desc().Replicas().Filter(func(rDesc roachpb.ReplicaDescriptor) bool {
+		return !rDesc.IsWitness() && rDesc.Type != roachpb.VOTER_OUTGOING
+	}).Descriptors()
 	resultCh := make(chan ConsistencyCheckResult, len(replicas))
 	results := make([]ConsistencyCheckResult, 0, len(replicas))
 
@@ -641,6 +646,15 @@ func CalcReplicaDigest(
 func (r *Replica) computeChecksumPostApply(
 	ctx context.Context, cc kvserverpb.ComputeChecksum,
 ) (err error) {
+	// Witnesses don't retain the range's user data, so their checksum would
+	// not match that of the other replicas. Nobody collects it either. The
+	// same goes for outgoing voters, which may be witnesses being replaced by
+	// full voters, and which are about to be removed from the range anyway.
+	if repl, err := r.GetReplicaDescriptor(); err == nil &&
+		(repl.IsWitness() || repl.Type == roachpb.VOTER_OUTGOING) {
+		log.VEventf(ctx, 2, "not computing checksum %s on %s replica", cc.ChecksumID, repl.Type)
+		return nil
+	}
 	c, cleanup := r.trackReplicaChecksum(cc.ChecksumID)
 	defer func() {
 		if err != nil {
@@ -96,6 +96,11 @@ func (r *Replica) canServeFollowerReadRLocked(ctx context.Context, ba *kvpb.Batc
 		return false
 	}
 
+	if r.quarantined.Load() {
+		log.Event(ctx, "quarantined replicas cannot serve follower reads")
+		return false
+	}
+
 	switch repDesc.Type {
 	case roachpb.VOTER_FULL, roachpb.VOTER_INCOMING, roachpb.NON_VOTER:
 	default:
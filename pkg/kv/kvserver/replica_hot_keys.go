@@ -0,0 +1,317 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/hotkeycache"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Load-based splitting can't spread the load of a single hot key. Instead, the
+// leaseholder detects keys which are read often and written rarely, and serves
+// reads of them from an in-memory cache of their latest versions (the
+// hotKeyCache). Reads served from the cache still acquire latches, go through
+// the concurrency manager and update the timestamp cache; they only skip the
+// storage engine.
+//
+// The cache is kept coherent with the storage engine by the raft application
+// path, which invalidates every key written by a command after the command's
+// batch is committed to the engine, and before the command's latches are
+// released. Commands whose writes can't be cheaply enumerated (e.g. range
+// deletions and AddSSTable ingestions), snapshots, splits and merges invalidate
+// the whole cache.
+
+// hotKeyCacheEnabled controls whether leaseholders detect hot keys and serve
+// reads of them from the hot key cache.
+var hotKeyCacheEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.hot_key_cache.enabled",
+	"if set, leaseholders detect keys which are read often and written rarely, "+
+		"and serve reads of them from an in-memory cache",
+	false,
+)
+
+// hotKeyCacheMinReadsPerSecond is the rate at which a key needs to be read on
+// a replica to be considered hot.
+var hotKeyCacheMinReadsPerSecond = settings.RegisterFloatSetting(
+	settings.SystemOnly,
+	"kv.hot_key_cache.min_reads_per_second",
+	"the minimum rate at which a key must be read on a replica for it to be considered hot",
+	1000,
+	settings.PositiveFloat,
+)
+
+// hotKeyCacheMaxWriteFraction is the maximum fraction of the accesses to a key
+// which may be writes for the key to be considered hot. Every write evicts the
+// key from the cache, so caching keys which are written often is pointless.
+var hotKeyCacheMaxWriteFraction = settings.RegisterFloatSetting(
+	settings.SystemOnly,
+	"kv.hot_key_cache.max_write_fraction",
+	"the maximum fraction of the accesses to a key which may be writes for it to be considered hot",
+	0.01,
+	func(v float64) error {
+		if v < 0 || v > 1 {
+			return errors.Errorf("cannot set to a value outside of [0, 1]: %f", v)
+		}
+		return nil
+	},
+)
+
+const (
+	// maxHotKeysPerReplica is the maximum number of hot keys cached by a
+	// replica.
+	maxHotKeysPerReplica = 8
+	// maxHotKeyCacheValueBytes is the maximum size of a value which is cached.
+	maxHotKeyCacheValueBytes = 64 << 10
+)
+
+// HotKeys returns the keys currently considered hot on the replica, that is,
+// the keys for which reads may be served from the hot key cache.
+func (r *Replica) HotKeys() []roachpb.Key {
+	keys := r.hotKeyCache.HotKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Compare(keys[j]) < 0
+	})
+	return keys
+}
+
+// hotKeyCacheGetKey returns the key read by the batch, if the batch can be
+// served from the hot key cache. Only consistent, non-locking, single-key reads
+// evaluated on the leaseholder under latches are eligible.
+func (r *Replica) hotKeyCacheGetKey(
+	ba *kvpb.BatchRequest, g *concurrency.Guard, st kvserverpb.LeaseStatus,
+) (roachpb.Key, bool) {
+	if !hotKeyCacheEnabled.Get(&r.store.cfg.Settings.SV) {
+		return nil, false
+	}
+	if len(ba.Requests) != 1 || ba.ReadConsistency != kvpb.CONSISTENT {
+		return nil, false
+	}
+	get, ok := ba.Requests[0].GetInner().(*kvpb.GetRequest)
+	if !ok || get.KeyLocking != lock.None {
+		return nil, false
+	}
+	// Limited reads may have to return a resume span rather than the value.
+	if ba.MaxSpanRequestKeys < 0 || ba.TargetBytes < 0 || ba.AllowEmpty {
+		return nil, false
+	}
+	// Transactions which have acquired locks need to check the abort span on
+	// every read.
+	if ba.Txn != nil && ba.Txn.IsLocking() {
+		return nil, false
+	}
+	if g == nil || g.EvalKind != concurrency.PessimisticEval {
+		return nil, false
+	}
+	if !st.Lease.OwnedBy(r.store.StoreID()) {
+		return nil, false
+	}
+	return get.Key, true
+}
+
+// maybeServeReadFromHotKeyCache attempts to serve the read-only batch from the
+// hot key cache. If it succeeds, the timestamp cache is updated and the latches
+// are released, and the response is returned. The batch must have passed
+// checkExecutionCanProceedBeforeStorageSnapshot.
+func (r *Replica) maybeServeReadFromHotKeyCache(
+	ctx context.Context, ba *kvpb.BatchRequest, g *concurrency.Guard, st kvserverpb.LeaseStatus,
+) (br *kvpb.BatchResponse, ok bool, pErr *kvpb.Error) {
+	if r.hotKeyCache.IsEmpty() {
+		return nil, false, nil
+	}
+	key, ok := r.hotKeyCacheGetKey(ba, g, st)
+	if !ok {
+		return nil, false, nil
+	}
+	// The latest version of the key is not above the read timestamp, so there
+	// is no version of the key in the read's uncertainty interval.
+	value, ok := r.hotKeyCache.Get(key, ba.Timestamp)
+	if !ok {
+		return nil, false, nil
+	}
+	if err := r.checkExecutionCanProceedAfterStorageSnapshot(ctx, ba, st); err != nil {
+		return nil, true, kvpb.NewError(err)
+	}
+
+	br = ba.CreateReply()
+	resp := br.Responses[0].GetGet()
+	resp.Value = value
+	if value != nil {
+		resp.NumKeys = 1
+		resp.NumBytes = int64(len(value.RawBytes))
+	}
+	if ba.Txn != nil {
+		br.Txn = ba.Txn.Clone()
+		br.Timestamp = br.Txn.ReadTimestamp
+	} else {
+		br.Timestamp = ba.Timestamp
+	}
+	log.VEventf(ctx, 3, "served read of hot key %s from the hot key cache", key)
+	r.store.metrics.ReplicaReadBatchHotKeyCacheHits.Inc(1)
+	r.updateTimestampCacheAndDropLatches(ctx, g, ba, br, nil /* pErr */, st)
+	return br, true, nil
+}
+
+// maybeBeginHotKeyCacheFill returns the key read by the batch and a token with
+// which the key's latest version can be added to the hot key cache, if the key
+// is hot but not cached. It must be called before the storage engine state
+// used to evaluate the batch is pinned.
+func (r *Replica) maybeBeginHotKeyCacheFill(
+	ba *kvpb.BatchRequest, g *concurrency.Guard, st kvserverpb.LeaseStatus,
+) (roachpb.Key, hotkeycache.Token, bool) {
+	if r.hotKeyCache.IsEmpty() {
+		return nil, hotkeycache.Token{}, false
+	}
+	key, ok := r.hotKeyCacheGetKey(ba, g, st)
+	if !ok {
+		return nil, hotkeycache.Token{}, false
+	}
+	tok, ok := r.hotKeyCache.Begin(key)
+	return key, tok, ok
+}
+
+// fillHotKeyCache reads the latest version of the given hot key from the
+// reader and adds it to the hot key cache. The key is not cached if it has an
+// intent, or if its value is too large.
+func (r *Replica) fillHotKeyCache(
+	ctx context.Context, reader storage.Reader, key roachpb.Key, tok hotkeycache.Token,
+) {
+	// The read is above the timestamps declared by the batch's latches; that's
+	// fine since its result is only used if no write to the key is applied in
+	// the meantime.
+	res, err := storage.MVCCGet(
+		ctx, spanset.DisableReaderAssertions(reader), key, hlc.MaxTimestamp,
+		storage.MVCCGetOptions{Tombstones: true},
+	)
+	if err != nil {
+		// Most likely, the key has an intent.
+		log.VEventf(ctx, 3, "not caching hot key %s: %v", key, err)
+		return
+	}
+	var value roachpb.Value
+	if res.Value != nil {
+		if len(res.Value.RawBytes) > maxHotKeyCacheValueBytes {
+			return
+		}
+		value = *res.Value
+	}
+	r.hotKeyCache.Put(tok, value)
+}
+
+// recordHotKeyReads records the keys read by the batch in the load stats, and
+// periodically refreshes the set of hot keys which may be cached.
+func (r *Replica) recordHotKeyReads(ctx context.Context, ba *kvpb.BatchRequest) {
+	if r.loadStats == nil {
+		return
+	}
+	if !hotKeyCacheEnabled.Get(&r.store.cfg.Settings.SV) {
+		if !r.hotKeyCache.IsEmpty() {
+			r.hotKeyCache.SetHotKeys(nil)
+		}
+		return
+	}
+	for _, ru := range ba.Requests {
+		if get, ok := ru.GetInner().(*kvpb.GetRequest); ok {
+			r.loadStats.RecordKeyRead(get.Key)
+		}
+	}
+	if r.hotKeysRefreshThrottle.ShouldProcess(timeutil.Now()) {
+		r.refreshHotKeys(ctx)
+	}
+}
+
+// refreshHotKeys replaces the set of keys which may be cached with the hot keys
+// detected by the load stats.
+func (r *Replica) refreshHotKeys(ctx context.Context) {
+	sv := &r.store.cfg.Settings.SV
+	hotKeys := r.loadStats.HotKeys(
+		hotKeyCacheMinReadsPerSecond.Get(sv), hotKeyCacheMaxWriteFraction.Get(sv))
+	if len(hotKeys) > maxHotKeysPerReplica {
+		hotKeys = hotKeys[:maxHotKeysPerReplica]
+	}
+	keys := make([]roachpb.Key, len(hotKeys))
+	for i := range hotKeys {
+		keys[i] = hotKeys[i].Key
+		log.VEventf(ctx, 2, "hot key %s: %.1f reads/s, %.1f writes/s",
+			hotKeys[i].Key, hotKeys[i].ReadsPerSecond, hotKeys[i].WritesPerSecond)
+	}
+	r.hotKeyCache.SetHotKeys(keys)
+}
+
+// hotKeyWritesTracked returns whether the keys written by applied commands need
+// to be collected, either to record them in the load stats or to invalidate
+// them in the hot key cache.
+func (r *Replica) hotKeyWritesTracked() bool {
+	return !r.hotKeyCache.IsEmpty() || hotKeyCacheEnabled.Get(&r.store.cfg.Settings.SV)
+}
+
+// collectHotKeyWrites appends the keys written by the given write batch to
+// keys. If the batch contains writes which don't pertain to individual keys,
+// such as range deletions, all is set. The returned keys alias the batch.
+func collectHotKeyWrites(wb []byte, keys []roachpb.Key) (_ []roachpb.Key, all bool, _ error) {
+	r, err := storage.NewPebbleBatchReader(wb)
+	if err != nil {
+		return keys, false, err
+	}
+	for r.Next() {
+		switch r.BatchType() {
+		case storage.BatchTypeValue, storage.BatchTypeDeletion,
+			storage.BatchTypeSingleDeletion, storage.BatchTypeMerge:
+		default:
+			return keys, true, nil
+		}
+		engineKey, err := r.EngineKey()
+		if err != nil {
+			return keys, false, err
+		}
+		switch {
+		case engineKey.IsMVCCKey():
+			keys = append(keys, engineKey.Key)
+		case engineKey.IsLockTableKey():
+			ltKey, err := engineKey.ToLockTableKey()
+			if err != nil {
+				return keys, false, err
+			}
+			keys = append(keys, ltKey.Key)
+		}
+	}
+	return keys, false, r.Error()
+}
+
+// handleHotKeyWritesRaftMuLocked invalidates the keys written by an applied
+// batch of commands in the hot key cache, and records the writes in the load
+// stats. It must be called after the batch is committed to the storage engine.
+func (r *Replica) handleHotKeyWritesRaftMuLocked(keys []roachpb.Key, all bool) {
+	if all {
+		r.hotKeyCache.InvalidateAll()
+	} else {
+		for _, key := range keys {
+			r.hotKeyCache.Invalidate(key)
+		}
+	}
+	if r.loadStats != nil && len(keys) > 0 {
+		r.loadStats.RecordKeyWrites(keys)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplicaHotKeyCache verifies that reads of hot keys are served from the
+// hot key cache, and that writes applied to the replica invalidate the cache.
+func TestReplicaHotKeyCache(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	tc := testContext{}
+	tc.Start(ctx, t, stopper)
+	hotKeyCacheEnabled.Override(ctx, &tc.store.cfg.Settings.SV, true)
+
+	key := roachpb.Key("a")
+	hits := tc.store.metrics.ReplicaReadBatchHotKeyCacheHits
+	get := func(ts hlc.Timestamp) []byte {
+		t.Helper()
+		gArgs := getArgs(key)
+		resp, pErr := tc.SendWrappedWith(kvpb.Header{Timestamp: ts}, &gArgs)
+		require.NoError(t, pErr.GoError())
+		val := resp.(*kvpb.GetResponse).Value
+		if val == nil {
+			return nil
+		}
+		b, err := val.GetBytes()
+		require.NoError(t, err)
+		return b
+	}
+	put := func(value string) hlc.Timestamp {
+		t.Helper()
+		pArgs := putArgs(key, []byte(value))
+		ts := tc.Clock().Now()
+		_, pErr := tc.SendWrappedWith(kvpb.Header{Timestamp: ts}, &pArgs)
+		require.NoError(t, pErr.GoError())
+		return ts
+	}
+
+	ts1 := put("one")
+	tc.repl.hotKeyCache.SetHotKeys([]roachpb.Key{key})
+	require.Equal(t, []roachpb.Key{key}, tc.repl.HotKeys())
+
+	// The first read populates the cache, the second one is served from it.
+	require.Equal(t, []byte("one"), get(tc.Clock().Now()))
+	require.Equal(t, int64(0), hits.Count())
+	require.Equal(t, []byte("one"), get(tc.Clock().Now()))
+	require.Equal(t, int64(1), hits.Count())
+
+	// A write invalidates the cached value.
+	ts2 := put("two")
+	require.Equal(t, []byte("two"), get(tc.Clock().Now()))
+	require.Equal(t, int64(1), hits.Count())
+	require.Equal(t, []byte("two"), get(tc.Clock().Now()))
+	require.Equal(t, int64(2), hits.Count())
+
+	// Reads below the cached version aren't served from the cache.
+	require.Equal(t, []byte("one"), get(ts1.Next()))
+	require.Nil(t, get(ts1.Prev()))
+	require.Equal(t, int64(2), hits.Count())
+	require.Equal(t, []byte("two"), get(ts2))
+	require.Equal(t, int64(3), hits.Count())
+
+	// Disabling the cache clears the hot keys.
+	hotKeyCacheEnabled.Override(ctx, &tc.store.cfg.Settings.SV, false)
+	require.Equal(t, []byte("two"), get(tc.Clock().Now()))
+	require.Empty(t, tc.repl.HotKeys())
+	require.Equal(t, []byte("two"), get(tc.Clock().Now()))
+	require.Equal(t, int64(3), hits.Count())
+}
+
+func TestCollectHotKeyWrites(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMemForTesting()
+	defer eng.Close()
+
+	ts := hlc.Timestamp{WallTime: 10}
+	txn := roachpb.MakeTransaction(
+		"test", roachpb.Key("b"), isolation.Serializable, roachpb.NormalUserPriority, ts, 0, 1)
+	value := roachpb.MakeValueFromString("val")
+
+	b := eng.NewBatch()
+	defer b.Close()
+	require.NoError(t, storage.MVCCPut(
+		ctx, b, nil, roachpb.Key("a"), ts, hlc.ClockTimestamp{}, value, nil))
+	require.NoError(t, storage.MVCCPut(
+		ctx, b, nil, roachpb.Key("b"), ts, hlc.ClockTimestamp{}, value, &txn))
+
+	keys, all, err := collectHotKeyWrites(b.Repr(), nil)
+	require.NoError(t, err)
+	require.False(t, all)
+	// The intent on b is written both to the lock table and as a provisional
+	// value.
+	require.ElementsMatch(t, []roachpb.Key{
+		roachpb.Key("a"), roachpb.Key("b"), roachpb.Key("b"),
+	}, keys)
+
+	require.NoError(t, b.ClearRawRange(
+		roachpb.Key("c"), roachpb.Key("d"), true /* pointKeys */, false /* rangeKeys */))
+	_, all, err = collectHotKeyWrites(b.Repr(), nil)
+	require.NoError(t, err)
+	require.True(t, all)
+}
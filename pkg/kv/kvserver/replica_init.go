@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/abortspan"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/tracker"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/hotkeycache"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvstorage"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/load"
@@ -40,6 +41,7 @@ import (
 const (
 	splitQueueThrottleDuration = 5 * time.Second
 	mergeQueueThrottleDuration = 5 * time.Second
+	hotKeysRefreshDuration     = 10 * time.Second
 )
 
 // loadInitializedReplicaForTesting loads and constructs an initialized Replica,
@@ -67,6 +69,12 @@ func newInitializedReplica(store *Store, loaded kvstorage.LoadedReplicaState) (*
 	if err := r.initRaftMuLockedReplicaMuLocked(loaded); err != nil {
 		return nil, err
 	}
+	// Restore the quarantine of the replica, see storeScrubber.
+	if quarantined, err := r.loadQuarantinedRaftMuLocked(r.AnnotateCtx(context.TODO())); err != nil {
+		return nil, err
+	} else if quarantined {
+		r.quarantined.Store(true)
+	}
 	return r, nil
 }
 
@@ -150,6 +158,8 @@ func newUninitializedReplica(
 
 	r.splitQueueThrottle = util.Every(splitQueueThrottleDuration)
 	r.mergeQueueThrottle = util.Every(mergeQueueThrottleDuration)
+	r.hotKeysRefreshThrottle = util.Every(hotKeysRefreshDuration)
+	r.hotKeyCache = hotkeycache.New()
 
 	onTrip := func() {
 		telemetry.Inc(telemetryTripAsync)
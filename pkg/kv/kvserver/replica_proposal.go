@@ -424,6 +424,8 @@ func (r *Replica) leasePostApplyLocked(
 		if r.loadStats != nil {
 			r.loadStats.Reset()
 		}
+		// Hot keys are only detected and cached on the leaseholder.
+		r.hotKeyCache.SetHotKeys(nil)
 	}
 
 	// Potentially re-gossip if the range contains system data (e.g. system
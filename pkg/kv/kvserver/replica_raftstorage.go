@@ -451,7 +451,8 @@ func (r *Replica) applySnapshot(
 				// "applied by voters" here, since the LEARNER will soon be promoted to
 				// a voting replica.
 				case roachpb.VOTER_FULL, roachpb.VOTER_INCOMING, roachpb.VOTER_DEMOTING_LEARNER,
-					roachpb.VOTER_OUTGOING, roachpb.LEARNER, roachpb.VOTER_DEMOTING_NON_VOTER:
+					roachpb.VOTER_OUTGOING, roachpb.LEARNER, roachpb.VOTER_DEMOTING_NON_VOTER,
+					roachpb.WITNESS:
 					r.store.metrics.RangeSnapshotsAppliedByVoters.Inc(1)
 				case roachpb.NON_VOTER:
 					r.store.metrics.RangeSnapshotsAppliedByNonVoters.Inc(1)
@@ -637,6 +638,10 @@ func (r *Replica) applySnapshot(
 	// feelings about this ever change, we can add a LastIndex field to
 	// raftpb.SnapshotMetadata.
 	r.mu.lastIndexNotDurable = state.RaftAppliedIndex
+	// The snapshot replaced all the data of the replica, and cleared its
+	// persisted quarantine along with the other unreplicated range-ID local
+	// keys, so the replica is healthy again.
+	r.quarantined.Store(false)
 
 	// TODO(sumeer): We should be able to set this to
 	// nonemptySnap.Metadata.Term. See
@@ -674,6 +679,10 @@ func (r *Replica) applySnapshot(
 	// Inform the concurrency manager that this replica just applied a snapshot.
 	r.concMgr.OnReplicaSnapshotApplied()
 
+	// The snapshot replaced the replica's data without going through the raft
+	// application path, so any cached hot key may be stale.
+	r.hotKeyCache.InvalidateAll()
+
 	r.mu.Unlock()
 
 	// Assert that the in-memory and on-disk states of the Replica are congruent
@@ -816,6 +816,13 @@ func (r *Replica) requestLeaseLocked(
 	if pErr := r.store.TestingKnobs().PinnedLeases.rejectLeaseIfPinnedElsewhere(r); pErr != nil {
 		return r.mu.pendingLeaseRequest.newResolvedHandle(pErr)
 	}
+	// A quarantined replica's data is corrupted, so it must not serve requests
+	// as the leaseholder.
+	if r.quarantined.Load() {
+		return r.mu.pendingLeaseRequest.newResolvedHandle(kvpb.NewError(
+			kvpb.NewNotLeaseHolderError(roachpb.Lease{}, r.store.StoreID(), r.mu.state.Desc,
+				"replica is quarantined")))
+	}
 
 	// Propose a Raft command to get a lease for this replica.
 	repDesc, err := r.getReplicaDescriptorRLocked()
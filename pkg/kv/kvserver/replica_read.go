@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvadmission"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/lockspanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/uncertainty"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -62,6 +63,21 @@ func (r *Replica) executeReadOnlyBatch(
 		fn(r)
 	}
 
+	// Serve reads of hot keys from the hot key cache, if possible. Otherwise,
+	// prepare to populate the cache with the key's latest version, which must
+	// be done before the storage engine state is pinned below.
+	if cachedBr, ok, cachedPErr := r.maybeServeReadFromHotKeyCache(ctx, ba, g, st); ok {
+		if cachedPErr != nil {
+			return nil, g, nil, cachedPErr
+		}
+		r.recordHotKeyReads(ctx, ba)
+		keysRead, bytesRead := getBatchResponseReadStats(cachedBr)
+		r.loadStats.RecordReadKeys(keysRead)
+		r.loadStats.RecordReadBytes(bytesRead)
+		return cachedBr, nil, nil, nil
+	}
+	hotKey, hotKeyTok, fillHotKeyCache := r.maybeBeginHotKeyCacheFill(ba, g, st)
+
 	// Compute the transaction's local uncertainty limit using observed
 	// timestamps, which can help avoid uncertainty restarts.
 	ui := uncertainty.ComputeInterval(&ba.Header, st, r.Clock().MaxOffset())
@@ -215,6 +231,10 @@ func (r *Replica) executeReadOnlyBatch(
 	if pErr != nil {
 		log.VErrEventf(ctx, 3, "%v", pErr.String())
 	} else {
+		if fillHotKeyCache {
+			r.fillHotKeyCache(ctx, rw, hotKey, hotKeyTok)
+		}
+		r.recordHotKeyReads(ctx, ba)
 		keysRead, bytesRead := getBatchResponseReadStats(br)
 		r.loadStats.RecordReadKeys(keysRead)
 		r.loadStats.RecordReadBytes(bytesRead)
@@ -522,7 +542,7 @@ func (r *Replica) handleReadOnlyLocalEvalResult(
 // and uses that to compute the latch and lock spans.
 func (r *Replica) collectSpansRead(
 	ba *kvpb.BatchRequest, br *kvpb.BatchResponse,
-) (latchSpans, lockSpans *spanset.SpanSet, _ error) {
+) (latchSpans *spanset.SpanSet, lockSpans *lockspanset.LockSpanSet, _ error) {
 	baCopy := *ba
 	baCopy.Requests = make([]kvpb.RequestUnion, 0, len(ba.Requests))
 	for i := 0; i < len(ba.Requests); i++ {
@@ -42,6 +42,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/lockspanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
@@ -101,7 +102,7 @@ func allSpansGuard() *concurrency.Guard {
 	return &concurrency.Guard{
 		Req: concurrency.Request{
 			LatchSpans: allSpans(),
-			LockSpans:  spanset.New(),
+			LockSpans:  lockspanset.New(),
 		},
 	}
 }
@@ -13865,6 +13866,14 @@ func TestPrepareChangeReplicasTrigger(t *testing.T) {
 			typOp{roachpb.LEARNER, internalChangeTypePromoteLearner},
 		),
 
+		// Replacement of a witness by a promoted learner.
+		mk(
+			"ENTER_JOINT(r3 v2) [(n200,s200):2VOTER_INCOMING], [(n300,s300):3VOTER_OUTGOING]: after=[(n100,s100):1 (n200,s200):2VOTER_INCOMING (n300,s300):3VOTER_OUTGOING] next=4",
+			typOp{roachpb.VOTER_FULL, noop},
+			typOp{roachpb.LEARNER, internalChangeTypePromoteLearner},
+			typOp{roachpb.WITNESS, internalChangeTypeRemoveWitness},
+		),
+
 		// Removal of two voters.
 		mk(
 			"ENTER_JOINT(r2 r3) [(n200,s200):2VOTER_OUTGOING (n300,s300):3VOTER_OUTGOING]: after=[(n100,s100):1 (n200,s200):2VOTER_OUTGOING (n300,s300):3VOTER_OUTGOING] next=4",
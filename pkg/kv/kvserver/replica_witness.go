@@ -0,0 +1,142 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/errors"
+)
+
+// Witness replicas (see roachpb.WITNESS) participate in Raft voting and hold
+// the Raft log, but they don't retain the range's user data. They apply the
+// writes of committed commands to the range's local keys only, which keeps
+// the replicated range state (the range descriptor, the applied state, the
+// lease, etc.) available to them, but drop the writes to user keys and to
+// the locks on user keys. Similarly, snapshots sent to witnesses only contain
+// the range's local keys (see kvBatchSnapshotStrategy.Send).
+//
+// Note that the MVCCStats of a witness continue to describe the data of the
+// entire range, as these are part of the replicated range state. This also
+// means that witnesses can't take part in consistency checks.
+
+// witnessRetainsKey returns whether witnesses retain the given engine key,
+// i.e. whether it is a local key which isn't a lock on a user key.
+func witnessRetainsKey(key roachpb.Key) bool {
+	if !keys.IsLocal(key) {
+		return false
+	}
+	if bytes.HasPrefix(key, keys.LocalRangeLockTablePrefix) {
+		lockedKey, err := keys.DecodeLockTableSingleKey(key)
+		return err == nil && keys.IsLocal(lockedKey)
+	}
+	return true
+}
+
+// addWitnessWriteBatch is like addWriteBatch, except that it only stages the
+// writes to keys retained by witnesses (see witnessRetainsKey). Range
+// deletions are staged if they start at a retained key; if they extend into
+// the user keys, they are no-ops there.
+func (b *appBatch) addWitnessWriteBatch(
+	ctx context.Context, batch storage.Batch, cmd *replicatedCmd,
+) error {
+	wb := cmd.Cmd.WriteBatch
+	if wb == nil {
+		return nil
+	}
+	r, err := storage.NewPebbleBatchReader(wb.Data)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read WriteBatch")
+	}
+	for r.Next() {
+		switch r.BatchType() {
+		case storage.BatchTypeValue, storage.BatchTypeDeletion, storage.BatchTypeSingleDeletion,
+			storage.BatchTypeRangeDeletion:
+			key, err := r.EngineKey()
+			if err != nil {
+				return err
+			}
+			if !witnessRetainsKey(key.Key) {
+				continue
+			}
+			b.numMutations++
+			switch r.BatchType() {
+			case storage.BatchTypeValue:
+				err = batch.PutEngineKey(key, r.Value())
+			case storage.BatchTypeDeletion:
+				err = batch.ClearEngineKey(key)
+			case storage.BatchTypeSingleDeletion:
+				err = batch.SingleClearEngineKey(key)
+			case storage.BatchTypeRangeDeletion:
+				var endKey storage.EngineKey
+				if endKey, err = r.EngineEndKey(); err == nil {
+					err = batch.ClearRawRange(key.Key, endKey.Key, true /* pointKeys */, false /* rangeKeys */)
+				}
+			}
+			if err != nil {
+				return errors.Wrapf(err, "unable to apply WriteBatch")
+			}
+
+		case storage.BatchTypeMerge:
+			key, err := r.MVCCKey()
+			if err != nil {
+				return err
+			}
+			if !witnessRetainsKey(key.Key) {
+				continue
+			}
+			b.numMutations++
+			if err := batch.Merge(key, r.Value()); err != nil {
+				return errors.Wrapf(err, "unable to apply WriteBatch")
+			}
+
+		case storage.BatchTypeRangeKeySet, storage.BatchTypeRangeKeyUnset,
+			storage.BatchTypeRangeKeyDelete, storage.BatchTypeLogData:
+			// Range keys are MVCC range tombstones, which only exist in the user
+			// key span, and log data doesn't affect the state machine.
+
+		default:
+			return errors.AssertionFailedf("unexpected batch entry type %d", r.BatchType())
+		}
+	}
+	return r.Error()
+}
+
+// changeMakesStoreWitness returns true if the given change turns the replica
+// on the given store into a witness.
+func changeMakesStoreWitness(
+	desc *roachpb.RangeDescriptor, change *kvserverpb.ChangeReplicas, storeID roachpb.StoreID,
+) bool {
+	if repl, ok := desc.GetReplicaDescriptor(storeID); !ok || repl.IsWitness() {
+		return false
+	}
+	repl, ok := change.Desc.GetReplicaDescriptor(storeID)
+	return ok && repl.IsWitness()
+}
+
+// clearWitnessUserData stages the removal of the range's user data, which
+// witnesses don't retain, into the given batch.
+func clearWitnessUserData(desc *roachpb.RangeDescriptor, writer storage.Writer) error {
+	for _, span := range rditer.MakeUserDataKeySpans(desc) {
+		if err := writer.ClearRawRange(
+			span.Key, span.EndKey, true /* pointKeys */, true, /* rangeKeys */
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWitnessRetainsKey verifies that witnesses retain the local keys of the
+// range, but neither its user keys nor the locks on them.
+func TestWitnessRetainsKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	rangeDescKey := keys.RangeDescriptorKey(roachpb.RKey("a"))
+	lockOnLocalKey, _ := keys.LockTableSingleKey(rangeDescKey, nil)
+	lockOnUserKey, _ := keys.LockTableSingleKey(roachpb.Key("a"), nil)
+
+	for _, tc := range []struct {
+		name string
+		key  roachpb.Key
+		exp  bool
+	}{
+		{"range-id key", keys.RangeAppliedStateKey(1), true},
+		{"range descriptor", rangeDescKey, true},
+		{"lock on local key", lockOnLocalKey, true},
+		{"lock on user key", lockOnUserKey, false},
+		{"user key", roachpb.Key("a"), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.exp, witnessRetainsKey(tc.key))
+		})
+	}
+}
+
+// TestChangeMakesStoreWitness verifies that only changes which turn an
+// existing replica into a witness are detected.
+func TestChangeMakesStoreWitness(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	mkDesc := func(typ roachpb.ReplicaType) *roachpb.RangeDescriptor {
+		return &roachpb.RangeDescriptor{
+			InternalReplicas: []roachpb.ReplicaDescriptor{
+				{NodeID: 1, StoreID: 1, ReplicaID: 1},
+				{NodeID: 2, StoreID: 2, ReplicaID: 2, Type: typ},
+			},
+		}
+	}
+	change := &kvserverpb.ChangeReplicas{}
+	change.Desc = mkDesc(roachpb.WITNESS)
+
+	require.True(t, changeMakesStoreWitness(mkDesc(roachpb.LEARNER), change, 2))
+	require.False(t, changeMakesStoreWitness(mkDesc(roachpb.LEARNER), change, 1))
+	require.False(t, changeMakesStoreWitness(mkDesc(roachpb.WITNESS), change, 2))
+	require.False(t, changeMakesStoreWitness(mkDesc(roachpb.LEARNER), change, 3))
+}
+
+// TestClearWitnessUserData verifies that the user data of a range is cleared
+// when its replica becomes a witness, while its local keys are retained.
+func TestClearWitnessUserData(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMemForTesting()
+	defer eng.Close()
+
+	desc := &roachpb.RangeDescriptor{
+		RangeID:  1,
+		StartKey: roachpb.RKey("a"),
+		EndKey:   roachpb.RKey("c"),
+	}
+	localKey := keys.RangeDescriptorKey(desc.StartKey)
+	userKey := roachpb.Key("b")
+	for _, key := range []roachpb.Key{localKey, userKey} {
+		require.NoError(t, eng.PutMVCC(
+			storage.MVCCKey{Key: key, Timestamp: hlc.Timestamp{WallTime: 1}},
+			storage.MVCCValue{Value: roachpb.MakeValueFromString("v")},
+		))
+	}
+
+	batch := eng.NewBatch()
+	defer batch.Close()
+	require.NoError(t, clearWitnessUserData(desc, batch))
+	require.NoError(t, batch.Commit(true /* sync */))
+
+	for _, tc := range []struct {
+		key roachpb.Key
+		exp bool
+	}{
+		{localKey, true},
+		{userKey, false},
+	} {
+		res, err := storage.MVCCGet(ctx, eng, tc.key, hlc.MaxTimestamp, storage.MVCCGetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, tc.exp, res.Value != nil, "key %s", tc.key)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/metric/aggmetric"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+var writeRateLimitLogLimiter = log.Every(10 * time.Second)
+
+// IsWriteRateLimitExceededError returns true iff the error indicates that a
+// write was rejected because the range exceeded the write rate limits
+// configured through the range_max_write_requests_per_second and
+// range_max_write_bytes_per_second zone config fields.
+func IsWriteRateLimitExceededError(err error) bool {
+	return errors.HasType(err, (*kvpb.WriteRateLimitExceededError)(nil))
+}
+
+// writeRateLimiter enforces the per-range write rate limits configured in the
+// replica's span config. Writes in excess of the limits are rejected rather
+// than queued, so that a single abusive table cannot tie up the resources of
+// the stores holding its ranges.
+type writeRateLimiter struct {
+	// enabled is set if any limit is configured. It avoids acquiring the mutex
+	// for the vast majority of ranges, which are not rate limited.
+	enabled syncutil.AtomicBool
+	mu      struct {
+		syncutil.Mutex
+		// requestsLimit and bytesLimit are the configured limits, where zero
+		// means unlimited. The corresponding token bucket is only used while
+		// the limit is set.
+		requestsLimit, bytesLimit int64
+		requests, bytes           quotapool.TokenBucket
+		// zoneID and subzoneID identify the zone the limits were configured
+		// in.
+		zoneID, subzoneID uint32
+	}
+}
+
+// update configures the limits of the limiter from the span config. A limit of
+// zero disables the corresponding limiter. The burst of each limiter is one
+// second's worth of its rate.
+func (l *writeRateLimiter) update(conf roachpb.SpanConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	updateBucket := func(tb *quotapool.TokenBucket, limit *int64, rate int64) {
+		switch {
+		case rate <= 0:
+			rate = 0
+		case *limit == 0:
+			tb.Init(quotapool.TokensPerSecond(rate), quotapool.Tokens(rate), timeutil.DefaultTimeSource{})
+		case *limit != rate:
+			tb.UpdateConfig(quotapool.TokensPerSecond(rate), quotapool.Tokens(rate))
+		}
+		*limit = rate
+	}
+	updateBucket(&l.mu.requests, &l.mu.requestsLimit, conf.RangeMaxWriteRequestsPerSecond)
+	updateBucket(&l.mu.bytes, &l.mu.bytesLimit, conf.RangeMaxWriteBytesPerSecond)
+	l.mu.zoneID, l.mu.subzoneID = conf.WriteRateLimitZoneID, conf.WriteRateLimitSubzoneID
+	l.enabled.Set(l.mu.requestsLimit != 0 || l.mu.bytesLimit != 0)
+}
+
+// admit attempts to acquire quota for the given number of write requests and
+// bytes without blocking. Quota is only acquired if both limits admit the
+// writes. If the quota cannot be acquired, it returns the name of the zone
+// config field whose limit was exceeded and the time after which the writes
+// are expected to be admitted.
+func (l *writeRateLimiter) admit(
+	requests, bytes int64,
+) (ok bool, exceeded string, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.mu.requestsLimit != 0 {
+		if ok, retryAfter := l.mu.requests.TryToFulfill(quotapool.Tokens(requests)); !ok {
+			return false, "range_max_write_requests_per_second", retryAfter
+		}
+	}
+	if l.mu.bytesLimit != 0 && bytes > 0 {
+		if ok, retryAfter := l.mu.bytes.TryToFulfill(quotapool.Tokens(bytes)); !ok {
+			// Refund the requests admitted above, as the writes are rejected.
+			if l.mu.requestsLimit != 0 {
+				l.mu.requests.Adjust(quotapool.Tokens(requests))
+			}
+			return false, "range_max_write_bytes_per_second", retryAfter
+		}
+	}
+	return true, "", 0
+}
+
+// zone returns the IDs of the zone the limits were configured in.
+func (l *writeRateLimiter) zone() (zoneID, subzoneID uint32) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.mu.zoneID, l.mu.subzoneID
+}
+
+// maybeWriteRateLimitBatch rejects the batch if it contains writes and the
+// range has exceeded the write rate limits configured in its span config. The
+// limits are only enforced on the leaseholder; other replicas will redirect
+// the batch to the leaseholder anyway.
+func (r *Replica) maybeWriteRateLimitBatch(ctx context.Context, ba *kvpb.BatchRequest) error {
+	if !ba.IsWrite() || !r.writeRateLimiter.enabled.Get() {
+		return nil
+	}
+	// Only requests which write user data are rate limited. In particular,
+	// batches which only commit, heartbeat or clean up after transactions are
+	// let through so that transactions which were admitted can complete.
+	info := tenantcostmodel.MakeRequestInfo(ba, 1, 1)
+	if info.WriteCount() == 0 {
+		return nil
+	}
+	if !r.OwnsValidLease(ctx, r.Clock().NowAsClockTimestamp()) {
+		return nil
+	}
+	ok, exceeded, retryAfter := r.writeRateLimiter.admit(info.WriteCount(), info.WriteBytes())
+	if ok {
+		return nil
+	}
+
+	tenantID, ok := r.TenantID()
+	if !ok {
+		tenantID = roachpb.SystemTenantID
+	}
+	zoneID, subzoneID := r.writeRateLimiter.zone()
+	r.store.metrics.RangeWriteRateLimitMetrics.recordRejected(
+		tenantID, zoneID, subzoneID, info.WriteCount(), info.WriteBytes())
+	if writeRateLimitLogLimiter.ShouldLog() {
+		log.Warningf(ctx, "rejecting writes to range exceeding %s", exceeded)
+	}
+	return kvpb.NewWriteRateLimitExceededError(r.RangeID, exceeded, retryAfter)
+}
+
+// RangeWriteRateLimitMetrics tracks the writes rejected because ranges
+// exceeded the write rate limits of their zone configurations. The metrics
+// are broken down by tenant and zone, so that the zones whose limits are hit
+// can be identified.
+type RangeWriteRateLimitMetrics struct {
+	RejectedRequests *aggmetric.AggCounter
+	RejectedBytes    *aggmetric.AggCounter
+
+	// children are only added the first time a zone has writes rejected, and
+	// are never removed. The number of children is thus bounded by the number
+	// of zones with rate limits configured.
+	mu struct {
+		syncutil.Mutex
+		children map[rangeWriteRateLimitZone]rangeWriteRateLimitChildMetrics
+	}
+}
+
+// rangeWriteRateLimitZone identifies the zone of a tenant whose write rate
+// limits rejected writes.
+type rangeWriteRateLimitZone struct {
+	tenantID          roachpb.TenantID
+	zoneID, subzoneID uint32
+}
+
+type rangeWriteRateLimitChildMetrics struct {
+	rejectedRequests *aggmetric.Counter
+	rejectedBytes    *aggmetric.Counter
+}
+
+var _ metric.Struct = (*RangeWriteRateLimitMetrics)(nil)
+
+// MetricStruct makes RangeWriteRateLimitMetrics a metric.Struct.
+func (*RangeWriteRateLimitMetrics) MetricStruct() {}
+
+func newRangeWriteRateLimitMetrics() *RangeWriteRateLimitMetrics {
+	b := aggmetric.MakeBuilder(multitenant.TenantIDLabel, "zone_id", "subzone_id")
+	m := &RangeWriteRateLimitMetrics{
+		RejectedRequests: b.Counter(metaRangeWriteRateLimitRejectedRequests),
+		RejectedBytes:    b.Counter(metaRangeWriteRateLimitRejectedBytes),
+	}
+	m.mu.children = make(map[rangeWriteRateLimitZone]rangeWriteRateLimitChildMetrics)
+	return m
+}
+
+func (m *RangeWriteRateLimitMetrics) recordRejected(
+	tenantID roachpb.TenantID, zoneID, subzoneID uint32, requests, bytes int64,
+) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := rangeWriteRateLimitZone{tenantID: tenantID, zoneID: zoneID, subzoneID: subzoneID}
+	c, ok := m.mu.children[key]
+	if !ok {
+		labels := []string{
+			tenantID.String(),
+			strconv.FormatUint(uint64(zoneID), 10),
+			strconv.FormatUint(uint64(subzoneID), 10),
+		}
+		c = rangeWriteRateLimitChildMetrics{
+			rejectedRequests: m.RejectedRequests.AddChild(labels...),
+			rejectedBytes:    m.RejectedBytes.AddChild(labels...),
+		}
+		m.mu.children[key] = c
+	}
+	c.rejectedRequests.Inc(requests)
+	c.rejectedBytes.Inc(bytes)
+}
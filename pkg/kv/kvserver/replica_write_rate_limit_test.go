@@ -0,0 +1,163 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplicaWriteRateLimit verifies that the leaseholder rejects writes in
+// excess of the write rate limits configured in the range's span config, while
+// letting reads through.
+func TestReplicaWriteRateLimit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	tc := testContext{}
+	tc.Start(ctx, t, stopper)
+
+	key := roachpb.Key("a")
+	put := func() error {
+		pArgs := putArgs(key, []byte("value"))
+		_, pErr := tc.SendWrapped(&pArgs)
+		return pErr.GoError()
+	}
+	get := func() error {
+		gArgs := getArgs(key)
+		_, pErr := tc.SendWrapped(&gArgs)
+		return pErr.GoError()
+	}
+	setLimits := func(requestsPerSecond, bytesPerSecond int64) {
+		conf := tc.repl.SpanConfig()
+		conf.RangeMaxWriteRequestsPerSecond = requestsPerSecond
+		conf.RangeMaxWriteBytesPerSecond = bytesPerSecond
+		tc.repl.SetSpanConfig(conf)
+	}
+	// putUntilRejected writes until a write is rejected, which must happen
+	// quickly given the tiny limits configured below.
+	putUntilRejected := func() error {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			if err := put(); err != nil {
+				return err
+			}
+		}
+		t.Fatal("writes were not rate limited")
+		return nil
+	}
+
+	// Without limits, writes are not rejected.
+	for i := 0; i < 10; i++ {
+		require.NoError(t, put())
+	}
+
+	for _, tc2 := range []struct {
+		name              string
+		requestsPerSecond int64
+		bytesPerSecond    int64
+		exceeded          string
+	}{
+		{"requests", 1, 0, "range_max_write_requests_per_second"},
+		{"bytes", 0, 1, "range_max_write_bytes_per_second"},
+	} {
+		t.Run(tc2.name, func(t *testing.T) {
+			metrics := tc.store.metrics.RangeWriteRateLimitMetrics
+			rejectedBefore := metrics.RejectedRequests.Count()
+
+			setLimits(tc2.requestsPerSecond, tc2.bytesPerSecond)
+			err := putUntilRejected()
+			require.True(t, IsWriteRateLimitExceededError(err), "%+v", err)
+			var rlErr *kvpb.WriteRateLimitExceededError
+			require.True(t, errors.As(err, &rlErr))
+			require.Equal(t, tc.repl.RangeID, rlErr.RangeID)
+			require.Equal(t, tc2.exceeded, rlErr.Limit)
+			require.Positive(t, rlErr.RetryAfter)
+			// The error is not retried automatically, which would only add load
+			// to the range.
+			require.False(t, errors.HasInterface(err, (*kvpb.ClientVisibleRetryError)(nil)))
+			require.NotEqual(t, pgcode.SerializationFailure, pgerror.GetPGCode(err))
+			require.NotEmpty(t, errors.GetAllHints(err))
+			require.Less(t, rejectedBefore, metrics.RejectedRequests.Count())
+
+			// Reads are not subject to the write rate limits.
+			require.NoError(t, get())
+
+			// Removing the limits lets writes through again.
+			setLimits(0, 0)
+			for i := 0; i < 10; i++ {
+				require.NoError(t, put())
+			}
+		})
+	}
+}
+
+// TestWriteRateLimiter verifies that a writeRateLimiter only enforces the
+// configured limits, and that writes rejected by one limit don't consume the
+// quota of the other.
+func TestWriteRateLimiter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var l writeRateLimiter
+	require.False(t, l.enabled.Get())
+	ok, _, _ := l.admit(1000, 1000)
+	require.True(t, ok)
+
+	l.update(roachpb.SpanConfig{RangeMaxWriteRequestsPerSecond: 10})
+	require.True(t, l.enabled.Get())
+	ok, _, _ = l.admit(10, 1<<20)
+	require.True(t, ok)
+	ok, exceeded, retryAfter := l.admit(10, 0)
+	require.False(t, ok)
+	require.Equal(t, "range_max_write_requests_per_second", exceeded)
+	require.Positive(t, retryAfter)
+
+	// Writes rejected by the bytes limit are refunded their requests quota.
+	l.update(roachpb.SpanConfig{
+		RangeMaxWriteRequestsPerSecond: 1000,
+		RangeMaxWriteBytesPerSecond:    100,
+	})
+	ok, _, _ = l.admit(1, 100)
+	require.True(t, ok)
+	_, _, available := l.mu.requests.TestingInternalParameters()
+	ok, exceeded, _ = l.admit(1, 100)
+	require.False(t, ok)
+	require.Equal(t, "range_max_write_bytes_per_second", exceeded)
+	_, _, availableAfter := l.mu.requests.TestingInternalParameters()
+	require.GreaterOrEqual(t, availableAfter, available)
+
+	l.update(roachpb.SpanConfig{
+		RangeMaxWriteBytesPerSecond: 100,
+		WriteRateLimitZoneID:        104,
+		WriteRateLimitSubzoneID:     2,
+	})
+	require.True(t, l.enabled.Get())
+	zoneID, subzoneID := l.zone()
+	require.Equal(t, uint32(104), zoneID)
+	require.Equal(t, uint32(2), subzoneID)
+
+	l.update(roachpb.SpanConfig{})
+	require.False(t, l.enabled.Get())
+}
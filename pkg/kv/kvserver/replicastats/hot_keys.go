@@ -0,0 +1,167 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package replicastats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+const (
+	hotKeyStatsRotateInterval = 10 * time.Second
+
+	// DefaultHotKeyStatsCapacity is the default number of keys for which a
+	// HotKeyStats keeps counts.
+	DefaultHotKeyStatsCapacity = 16
+)
+
+// HotKey is a key which is read often, and written rarely, on a replica.
+type HotKey struct {
+	Key             roachpb.Key
+	ReadsPerSecond  float64
+	WritesPerSecond float64
+}
+
+// HotKeyStats tracks the number of reads and writes of the most read keys of a
+// replica. Counts are kept for at most capacity keys: when a key that isn't
+// tracked is read and there's no room for it, the least read key is evicted
+// to make room. A key that's read much more often than the others is thus never
+// evicted, and its counts are exact, while keys that are read about as often as
+// each other keep evicting each other and never accumulate large counts.
+//
+// Like ReplicaStats, counts are aged out by rotating through windows: the rates
+// are computed from the counts of the current window and of the previous one.
+//
+// HotKeyStats is not safe for concurrent use.
+type HotKeyStats struct {
+	capacity   int
+	keys       map[string]*hotKeyCounts
+	lastRotate time.Time
+	// hasPrev is set if the previous window was active for its entire
+	// duration, i.e. if its counts should be used when computing rates.
+	hasPrev bool
+}
+
+type hotKeyCounts struct {
+	key                   roachpb.Key
+	reads, writes         float64
+	prevReads, prevWrites float64
+}
+
+func (c *hotKeyCounts) totalReads() float64 {
+	return c.reads + c.prevReads
+}
+
+// NewHotKeyStats constructs a new HotKeyStats tracker, which keeps counts for
+// at most capacity keys.
+func NewHotKeyStats(now time.Time, capacity int) *HotKeyStats {
+	return &HotKeyStats{
+		capacity:   capacity,
+		keys:       make(map[string]*hotKeyCounts, capacity),
+		lastRotate: now,
+	}
+}
+
+// RecordRead records a read of the given key.
+func (hs *HotKeyStats) RecordRead(now time.Time, key roachpb.Key) {
+	hs.maybeRotate(now)
+
+	if c, ok := hs.keys[string(key)]; ok {
+		c.reads++
+		return
+	}
+	if len(hs.keys) >= hs.capacity {
+		var minKey string
+		var min *hotKeyCounts
+		for k, c := range hs.keys {
+			if min == nil || c.totalReads() < min.totalReads() {
+				minKey, min = k, c
+			}
+		}
+		delete(hs.keys, minKey)
+	}
+	key = key.Clone()
+	hs.keys[string(key)] = &hotKeyCounts{key: key, reads: 1}
+}
+
+// RecordWrite records a write of the given key. Writes are only counted for
+// keys whose reads are already being tracked.
+func (hs *HotKeyStats) RecordWrite(now time.Time, key roachpb.Key) {
+	hs.maybeRotate(now)
+
+	if c, ok := hs.keys[string(key)]; ok {
+		c.writes++
+	}
+}
+
+func (hs *HotKeyStats) maybeRotate(now time.Time) {
+	sinceRotate := now.Sub(hs.lastRotate)
+	if sinceRotate < hotKeyStatsRotateInterval {
+		return
+	}
+	// If more than one window elapsed since the last rotation, the current
+	// window becomes stale as well and nothing is carried over.
+	hs.hasPrev = sinceRotate < 2*hotKeyStatsRotateInterval
+	for k, c := range hs.keys {
+		if hs.hasPrev && c.reads > 0 {
+			c.prevReads, c.prevWrites = c.reads, c.writes
+			c.reads, c.writes = 0, 0
+			continue
+		}
+		delete(hs.keys, k)
+	}
+	hs.lastRotate = now
+}
+
+// HotKeys returns the tracked keys which are read at least minReadsPerSecond
+// times per second, and for which writes make up at most maxWriteFraction of
+// all accesses, ordered by decreasing read rate. No keys are returned until
+// MinStatsDuration has passed since the stats were created or reset.
+func (hs *HotKeyStats) HotKeys(
+	now time.Time, minReadsPerSecond float64, maxWriteFraction float64,
+) []HotKey {
+	hs.maybeRotate(now)
+
+	duration := now.Sub(hs.lastRotate)
+	if hs.hasPrev {
+		duration += hotKeyStatsRotateInterval
+	}
+	if duration < MinStatsDuration {
+		return nil
+	}
+
+	var hotKeys []HotKey
+	for _, c := range hs.keys {
+		reads, writes := c.totalReads(), c.writes+c.prevWrites
+		readsPerSecond := reads / duration.Seconds()
+		if readsPerSecond < minReadsPerSecond || writes > maxWriteFraction*(reads+writes) {
+			continue
+		}
+		hotKeys = append(hotKeys, HotKey{
+			Key:             c.key,
+			ReadsPerSecond:  readsPerSecond,
+			WritesPerSecond: writes / duration.Seconds(),
+		})
+	}
+	sort.Slice(hotKeys, func(i, j int) bool {
+		return hotKeys[i].ReadsPerSecond > hotKeys[j].ReadsPerSecond
+	})
+	return hotKeys
+}
+
+// Reset clears all tracked keys.
+func (hs *HotKeyStats) Reset(now time.Time) {
+	hs.keys = make(map[string]*hotKeyCounts, hs.capacity)
+	hs.lastRotate = now
+	hs.hasPrev = false
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package replicastats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotKeyStats(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	now := testingStartTime()
+	hs := NewHotKeyStats(now, 4 /* capacity */)
+
+	hot, writtenHot, cold := roachpb.Key("hot"), roachpb.Key("written-hot"), roachpb.Key("cold")
+	record := func(seconds int) {
+		for i := 0; i < seconds; i++ {
+			now = now.Add(time.Second)
+			for j := 0; j < 100; j++ {
+				hs.RecordRead(now, hot)
+				if j%5 != 0 {
+					hs.RecordRead(now, writtenHot)
+				}
+				// Many keys that are each read once keep evicting each other, but
+				// don't evict the keys that are read often.
+				hs.RecordRead(now, roachpb.Key(fmt.Sprintf("key-%d-%d", i, j)))
+			}
+			hs.RecordRead(now, cold)
+			for j := 0; j < 40; j++ {
+				hs.RecordWrite(now, writtenHot)
+			}
+		}
+	}
+
+	// No hot keys are returned before MinStatsDuration has passed.
+	record(1)
+	require.Empty(t, hs.HotKeys(now, 10 /* minReadsPerSecond */, 0.1 /* maxWriteFraction */))
+
+	record(9)
+	hotKeys := hs.HotKeys(now, 10 /* minReadsPerSecond */, 0.1 /* maxWriteFraction */)
+	require.Len(t, hotKeys, 1)
+	require.Equal(t, hot, hotKeys[0].Key)
+	require.InDelta(t, 100, hotKeys[0].ReadsPerSecond, 0.01)
+	require.Zero(t, hotKeys[0].WritesPerSecond)
+
+	// Allowing more writes also returns the written key.
+	hotKeys = hs.HotKeys(now, 10 /* minReadsPerSecond */, 0.5 /* maxWriteFraction */)
+	require.Len(t, hotKeys, 2)
+	require.Equal(t, writtenHot, hotKeys[1].Key)
+	require.InDelta(t, 80, hotKeys[1].ReadsPerSecond, 0.01)
+	require.InDelta(t, 40, hotKeys[1].WritesPerSecond, 0.01)
+
+	// Counts are carried over to the next window, and aged out after that.
+	record(5)
+	require.Len(t, hs.HotKeys(now, 10 /* minReadsPerSecond */, 0.1 /* maxWriteFraction */), 1)
+	now = now.Add(2 * hotKeyStatsRotateInterval)
+	require.Empty(t, hs.HotKeys(now, 0 /* minReadsPerSecond */, 1 /* maxWriteFraction */))
+
+	// Reset clears all keys.
+	record(10)
+	require.NotEmpty(t, hs.HotKeys(now, 10 /* minReadsPerSecond */, 0.1 /* maxWriteFraction */))
+	hs.Reset(now)
+	require.Empty(t, hs.HotKeys(now.Add(MinStatsDuration), 0 /* minReadsPerSecond */, 1 /* maxWriteFraction */))
+}
@@ -463,11 +463,14 @@ func (metrics *ReplicateQueueMetrics) trackSuccessByAllocatorAction(
 	ctx context.Context, action allocatorimpl.AllocatorAction,
 ) {
 	switch action {
-	case allocatorimpl.AllocatorRemoveVoter, allocatorimpl.AllocatorRemoveNonVoter:
+	case allocatorimpl.AllocatorRemoveVoter, allocatorimpl.AllocatorRemoveNonVoter,
+		allocatorimpl.AllocatorRemoveWitness:
 		metrics.RemoveReplicaSuccessCount.Inc(1)
-	case allocatorimpl.AllocatorAddVoter, allocatorimpl.AllocatorAddNonVoter:
+	case allocatorimpl.AllocatorAddVoter, allocatorimpl.AllocatorAddNonVoter,
+		allocatorimpl.AllocatorAddWitness:
 		metrics.AddReplicaSuccessCount.Inc(1)
-	case allocatorimpl.AllocatorReplaceDeadVoter, allocatorimpl.AllocatorReplaceDeadNonVoter:
+	case allocatorimpl.AllocatorReplaceDeadVoter, allocatorimpl.AllocatorReplaceDeadNonVoter,
+		allocatorimpl.AllocatorPromoteWitness:
 		metrics.ReplaceDeadReplicaSuccessCount.Inc(1)
 	case allocatorimpl.AllocatorRemoveDeadVoter, allocatorimpl.AllocatorRemoveDeadNonVoter:
 		metrics.RemoveDeadReplicaSuccessCount.Inc(1)
@@ -491,11 +494,14 @@ func (metrics *ReplicateQueueMetrics) trackErrorByAllocatorAction(
 	ctx context.Context, action allocatorimpl.AllocatorAction,
 ) {
 	switch action {
-	case allocatorimpl.AllocatorRemoveVoter, allocatorimpl.AllocatorRemoveNonVoter:
+	case allocatorimpl.AllocatorRemoveVoter, allocatorimpl.AllocatorRemoveNonVoter,
+		allocatorimpl.AllocatorRemoveWitness:
 		metrics.RemoveReplicaErrorCount.Inc(1)
-	case allocatorimpl.AllocatorAddVoter, allocatorimpl.AllocatorAddNonVoter:
+	case allocatorimpl.AllocatorAddVoter, allocatorimpl.AllocatorAddNonVoter,
+		allocatorimpl.AllocatorAddWitness:
 		metrics.AddReplicaErrorCount.Inc(1)
-	case allocatorimpl.AllocatorReplaceDeadVoter, allocatorimpl.AllocatorReplaceDeadNonVoter:
+	case allocatorimpl.AllocatorReplaceDeadVoter, allocatorimpl.AllocatorReplaceDeadNonVoter,
+		allocatorimpl.AllocatorPromoteWitness:
 		metrics.ReplaceDeadReplicaErrorCount.Inc(1)
 	case allocatorimpl.AllocatorRemoveDeadVoter, allocatorimpl.AllocatorRemoveDeadNonVoter:
 		metrics.RemoveDeadReplicaErrorCount.Inc(1)
@@ -1057,6 +1063,15 @@ func (rq *replicateQueue) PlanOneChange(
 	var op AllocationOp
 	removeIdx := -1
 	nothingToDo := false
+	if action == allocatorimpl.AllocatorPromoteWitness {
+		op, err = rq.promoteWitness(ctx, repl, liveVoterReplicas, nonVoterReplicas, allocatorPrio)
+		if err != nil {
+			// If no store can take over from the witness, fall back to replacing
+			// the dead voters in place.
+			log.KvDistribution.VEventf(ctx, 1, "unable to promote witness, replacing dead voters: %v", err)
+			action, op, err = allocatorimpl.AllocatorReplaceDeadVoter, nil, nil
+		}
+	}
 	switch action {
 	case allocatorimpl.AllocatorNoop, allocatorimpl.AllocatorRangeUnavailable:
 		// We're either missing liveness information or the range is known to have
@@ -1103,6 +1118,15 @@ func (rq *replicateQueue) PlanOneChange(
 	case allocatorimpl.AllocatorRemoveNonVoter:
 		op, err = rq.removeNonVoter(ctx, repl, voterReplicas, nonVoterReplicas)
 
+	// Add, remove or promote witnesses. Dead and decommissioning witnesses are
+	// replaced by an addition followed by a removal.
+	case allocatorimpl.AllocatorPromoteWitness:
+		// Planned above.
+	case allocatorimpl.AllocatorAddWitness:
+		op, err = rq.addWitness(ctx, repl, voterReplicas, nonVoterReplicas, allocatorPrio)
+	case allocatorimpl.AllocatorRemoveWitness:
+		op, err = rq.removeWitness(ctx, repl, voterReplicas, nonVoterReplicas)
+
 	// Remove decommissioning replicas.
 	//
 	// NB: these two paths will only be hit when the range is over-replicated and
@@ -1202,7 +1226,15 @@ func (rq *replicateQueue) addOrReplaceVoters(
 	// we're removing it (i.e. dead or decommissioning). If we left the replica in
 	// the slice, the allocator would not be guaranteed to pick a replica that
 	// fills the gap removeRepl leaves once it's gone.
-	newVoter, details, err := rq.allocator.AllocateVoter(ctx, rq.storePool, conf, remainingLiveVoters, remainingLiveNonVoters, replacing, replicaStatus)
+	//
+	// The stores holding witnesses are ruled out as targets by passing the
+	// witnesses along with the non-voters, since a store can't hold a witness
+	// and a voter of the same range. Witnesses are replaced by full voters
+	// through promoteWitness instead.
+	newVoter, details, err := rq.allocator.AllocateVoter(ctx, rq.storePool, conf, remainingLiveVoters,
+		append(remainingLiveNonVoters[:len(remainingLiveNonVoters):len(remainingLiveNonVoters)],
+			desc.Replicas().WitnessDescriptors()...),
+		replacing, replicaStatus)
 	if err != nil {
 		return nil, err
 	}
@@ -1297,13 +1329,18 @@ func (rq *replicateQueue) addOrReplaceNonVoters(
 	allocatorPrio float64,
 ) (op AllocationOp, _ error) {
 	effects := effectBuilder{}
-	conf := repl.SpanConfig()
+	desc, conf := repl.DescAndSpanConfig()
 	var replacing *roachpb.ReplicaDescriptor
 	if removeIdx >= 0 {
 		replacing = &existingNonVoters[removeIdx]
 	}
 
-	newNonVoter, details, err := rq.allocator.AllocateNonVoter(ctx, rq.storePool, conf, liveVoterReplicas, liveNonVoterReplicas, replacing, replicaStatus)
+	// The stores holding witnesses are ruled out as targets by passing the
+	// witnesses along with the voters.
+	newNonVoter, details, err := rq.allocator.AllocateNonVoter(ctx, rq.storePool, conf,
+		append(liveVoterReplicas[:len(liveVoterReplicas):len(liveVoterReplicas)],
+			desc.Replicas().WitnessDescriptors()...),
+		liveNonVoterReplicas, replacing, replicaStatus)
 	if err != nil {
 		return nil, err
 	}
@@ -1583,6 +1620,166 @@ func (rq *replicateQueue) removeNonVoter(
 	return op, nil
 }
 
+// addWitness adds a witness replica to `repl`s range.
+func (rq *replicateQueue) addWitness(
+	ctx context.Context,
+	repl *Replica,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+	allocatorPriority float64,
+) (op AllocationOp, _ error) {
+	effects := effectBuilder{}
+	desc, conf := repl.DescAndSpanConfig()
+	existingWitnesses := desc.Replicas().WitnessDescriptors()
+	existingVotingReplicas := append(append([]roachpb.ReplicaDescriptor(nil), existingVoters...), existingWitnesses...)
+
+	newWitness, details, err := rq.allocator.AllocateWitness(
+		ctx, rq.storePool, conf, existingVotingReplicas, existingNonVoters, allocatorimpl.Alive,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if _, found := desc.GetReplicaDescriptor(newWitness.StoreID); found {
+		return nil, errors.AssertionFailedf("allocation target %s for a witness"+
+			" already has a replica", newWitness)
+	}
+	effects = effects.add(func() {
+		rq.metrics.trackAddReplicaCount(allocatorimpl.VoterTarget)
+	})
+
+	log.KvDistribution.Infof(ctx, "adding witness %+v: %s",
+		newWitness, rangeRaftProgress(repl.RaftStatus(), existingVotingReplicas))
+	op = AllocationChangeReplicasOp{
+		lhStore:           repl.StoreID(),
+		sideEffects:       effects.f(),
+		usage:             RangeUsageInfoForRepl(repl),
+		chgs:              kvpb.MakeReplicationChanges(roachpb.ADD_WITNESS, newWitness),
+		priority:          kvserverpb.SnapshotRequest_RECOVERY,
+		allocatorPriority: allocatorPriority,
+		reason:            kvserverpb.ReasonRangeUnderReplicated,
+		details:           details,
+	}
+	return op, nil
+}
+
+// promoteWitness replaces a live witness of `repl`s range by a full voter on
+// another store, preferably in the same locality. The new voter is added as a
+// learner and caught up through a snapshot, and then swapped with the witness
+// in a joint configuration, in which the learner is a VOTER_INCOMING and the
+// witness a VOTER_OUTGOING. The range's number of voting replicas thus never
+// drops, and neither does its fault tolerance.
+func (rq *replicateQueue) promoteWitness(
+	ctx context.Context,
+	repl *Replica,
+	liveVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+	allocatorPriority float64,
+) (op AllocationOp, _ error) {
+	effects := effectBuilder{}
+	desc, conf := repl.DescAndSpanConfig()
+	existingWitnesses := desc.Replicas().WitnessDescriptors()
+	liveWitnesses, _ := rq.storePool.LiveAndDeadReplicas(existingWitnesses, false /* includeSuspectAndDrainingStores */)
+	if len(liveWitnesses) == 0 {
+		return nil, errors.Errorf("no live witness to promote")
+	}
+	witness := liveWitnesses[0]
+
+	// The witnesses are passed along with the non-voters, which rules out their
+	// stores as targets.
+	newVoter, details, err := rq.allocator.AllocateVoter(ctx, rq.storePool, conf, liveVoters,
+		append(existingNonVoters[:len(existingNonVoters):len(existingNonVoters)], existingWitnesses...),
+		&witness, allocatorimpl.Alive)
+	if err != nil {
+		return nil, err
+	}
+	if _, found := desc.GetReplicaDescriptor(newVoter.StoreID); found {
+		return nil, errors.AssertionFailedf("allocation target %s for a voter"+
+			" already has a replica", newVoter)
+	}
+	effects = effects.add(func() {
+		rq.metrics.trackAddReplicaCount(allocatorimpl.VoterTarget)
+		rq.metrics.trackRemoveMetric(allocatorimpl.VoterTarget, allocatorimpl.Alive)
+	})
+
+	witnessTarget := roachpb.ReplicationTarget{NodeID: witness.NodeID, StoreID: witness.StoreID}
+	log.KvDistribution.Infof(ctx, "promoting witness %+v to a voter on %+v: %s",
+		witnessTarget, newVoter, rangeRaftProgress(repl.RaftStatus(), desc.Replicas().Descriptors()))
+	op = AllocationChangeReplicasOp{
+		lhStore:           repl.StoreID(),
+		sideEffects:       effects.f(),
+		usage:             RangeUsageInfoForRepl(repl),
+		chgs:              kvpb.ReplicationChangesForWitnessPromotion(witnessTarget, newVoter),
+		priority:          kvserverpb.SnapshotRequest_RECOVERY,
+		allocatorPriority: allocatorPriority,
+		reason:            kvserverpb.ReasonStoreDead,
+		details:           details,
+	}
+	return op, nil
+}
+
+// removeWitness removes a witness replica from `repl`s range. Dead and
+// decommissioning witnesses are removed first, which completes their
+// replacement; otherwise, the allocator picks the witness to remove.
+func (rq *replicateQueue) removeWitness(
+	ctx context.Context, repl *Replica, existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+) (op AllocationOp, _ error) {
+	effects := effectBuilder{}
+	desc, conf := repl.DescAndSpanConfig()
+	existingWitnesses := desc.Replicas().WitnessDescriptors()
+	existingVotingReplicas := append(append([]roachpb.ReplicaDescriptor(nil), existingVoters...), existingWitnesses...)
+
+	var removeWitness roachpb.ReplicationTarget
+	var details string
+	replicaStatus := allocatorimpl.Alive
+	reason := kvserverpb.ReasonRangeOverReplicated
+	_, deadWitnesses := rq.storePool.LiveAndDeadReplicas(existingWitnesses, false /* includeSuspectAndDrainingStores */)
+	decommissioningWitnesses := rq.storePool.DecommissioningReplicas(existingWitnesses)
+	if len(deadWitnesses) > 0 {
+		removeWitness = roachpb.ReplicationTarget{
+			NodeID:  deadWitnesses[0].NodeID,
+			StoreID: deadWitnesses[0].StoreID,
+		}
+		replicaStatus = allocatorimpl.Dead
+		reason = kvserverpb.ReasonStoreDead
+	} else if len(decommissioningWitnesses) > 0 {
+		removeWitness = roachpb.ReplicationTarget{
+			NodeID:  decommissioningWitnesses[0].NodeID,
+			StoreID: decommissioningWitnesses[0].StoreID,
+		}
+		replicaStatus = allocatorimpl.Decommissioning
+		reason = kvserverpb.ReasonStoreDecommissioning
+	} else {
+		var err error
+		removeWitness, details, err = rq.allocator.RemoveVoter(
+			ctx,
+			rq.storePool,
+			conf,
+			existingWitnesses,
+			existingVotingReplicas,
+			existingNonVoters,
+			rq.allocator.ScorerOptions(ctx),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	effects = effects.add(func() {
+		rq.metrics.trackRemoveMetric(allocatorimpl.VoterTarget, replicaStatus)
+	})
+
+	log.KvDistribution.Infof(ctx, "removing witness %+v: %s",
+		removeWitness, rangeRaftProgress(repl.RaftStatus(), existingVotingReplicas))
+	op = AllocationChangeReplicasOp{
+		lhStore:           repl.StoreID(),
+		sideEffects:       effects.f(),
+		usage:             RangeUsageInfoForRepl(repl),
+		chgs:              kvpb.MakeReplicationChanges(roachpb.REMOVE_WITNESS, removeWitness),
+		priority:          kvserverpb.SnapshotRequest_UNKNOWN, // unused
+		allocatorPriority: 0.0,                                // unused
+		reason:            reason,
+		details:           details,
+	}
+	return op, nil
+}
+
 func (rq *replicateQueue) removeDecommissioning(
 	ctx context.Context, repl *Replica, targetType allocatorimpl.TargetReplicaType,
 ) (op AllocationOp, _ error) {
@@ -620,7 +620,7 @@ func isVoter(r roachpb.ReplicaDescriptor) bool {
 
 func isInIncomingQuorum(r roachpb.ReplicaDescriptor) bool {
 	switch r.Type {
-	case roachpb.VOTER_FULL, roachpb.VOTER_INCOMING:
+	case roachpb.VOTER_FULL, roachpb.VOTER_INCOMING, roachpb.WITNESS:
 		return true
 	default:
 		return false
@@ -629,7 +629,8 @@ func isInIncomingQuorum(r roachpb.ReplicaDescriptor) bool {
 
 func isInOutgoingQuorum(r roachpb.ReplicaDescriptor) bool {
 	switch r.Type {
-	case roachpb.VOTER_FULL, roachpb.VOTER_OUTGOING, roachpb.VOTER_DEMOTING_NON_VOTER, roachpb.VOTER_DEMOTING_LEARNER:
+	case roachpb.VOTER_FULL, roachpb.VOTER_OUTGOING, roachpb.VOTER_DEMOTING_NON_VOTER, roachpb.VOTER_DEMOTING_LEARNER,
+		roachpb.WITNESS:
 		return true
 	default:
 		return false
@@ -16,6 +16,7 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -545,6 +546,13 @@ func (s spanSetWriter) ClearIntent(
 	return s.w.ClearIntent(key, txnDidNotUpdateMeta, txnUUID)
 }
 
+func (s spanSetWriter) ClearLock(key roachpb.Key, str lock.Strength, txnUUID uuid.UUID) error {
+	if err := s.checkAllowed(key); err != nil {
+		return err
+	}
+	return s.w.ClearLock(key, str, txnUUID)
+}
+
 func (s spanSetWriter) ClearEngineKey(key storage.EngineKey) error {
 	if err := s.spans.CheckAllowed(SpanReadWrite, roachpb.Span{Key: key.Key}); err != nil {
 		return err
@@ -687,6 +695,15 @@ func (s spanSetWriter) PutIntent(
 	return s.w.PutIntent(ctx, key, value, txnUUID)
 }
 
+func (s spanSetWriter) PutLock(
+	key roachpb.Key, str lock.Strength, value []byte, txnUUID uuid.UUID,
+) error {
+	if err := s.checkAllowed(key); err != nil {
+		return err
+	}
+	return s.w.PutLock(key, str, value, txnUUID)
+}
+
 func (s spanSetWriter) PutEngineKey(key storage.EngineKey, value []byte) error {
 	if !s.spansOnly {
 		panic("cannot do timestamp checking for putting EngineKey")
@@ -2068,6 +2068,9 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 		s.storeRebalancer.Start(ctx, s.stopper)
 	}
 
+	// Verify the checksums of the store's SSTables in the background.
+	newStoreScrubber(s).start(ctx)
+
 	// Set the started flag (for unittests).
 	atomic.StoreInt32(&s.started, 1)
 
@@ -3310,6 +3313,9 @@ type HotReplicaInfo struct {
 	WriteBytesPerSecond float64
 	ReadBytesPerSecond  float64
 	CPUTimePerSecond    float64
+	// HotKeys are the keys of the range which are read often and written
+	// rarely, and whose reads are served from the hot key cache.
+	HotKeys []roachpb.Key
 }
 
 // HottestReplicas returns the hottest replicas on a store, sorted by their
@@ -3342,6 +3348,7 @@ func mapToHotReplicasInfo(repls []CandidateReplica) []HotReplicaInfo {
 		hotRepls[i].WriteBytesPerSecond = loadStats.WriteBytesPerSecond
 		hotRepls[i].ReadBytesPerSecond = loadStats.ReadBytesPerSecond
 		hotRepls[i].CPUTimePerSecond = loadStats.RaftCPUNanosPerSecond + loadStats.RequestCPUNanosPerSecond
+		hotRepls[i].HotKeys = repls[i].Repl().HotKeys()
 	}
 	return hotRepls
 }
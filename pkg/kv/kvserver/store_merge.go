@@ -146,6 +146,9 @@ func (s *Store) MergeRange(
 	}
 
 	leftRepl.loadStats.Merge(rightRepl.loadStats)
+	// The hot keys of the LHS were detected before the merge, and the RHS's
+	// keys were never tracked by the LHS; start over.
+	leftRepl.hotKeyCache.SetHotKeys(nil)
 
 	// Clear the concurrency manager's lock and txn wait-queues to redirect the
 	// queued transactions to the left-hand replica, if necessary.
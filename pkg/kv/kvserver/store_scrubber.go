@@ -0,0 +1,354 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/allocatorimpl"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// The store scrubber periodically verifies the block checksums of all the
+// SSTables of a store, which detects bit rot on a single replica long before
+// the consistency queue would notice a divergence between replicas (or even
+// if it never does, e.g. because the corrupted blocks are in the Raft log).
+//
+// SSTables aren't aligned with ranges, so when an SSTable is found to be
+// corrupted, all the replicas whose keys overlap the bounds of the SSTable are
+// quarantined. Quarantined replicas don't acquire leases, serve follower
+// reads, or send snapshots, and the scrubber replaces them by new replicas on
+// other stores, which are initialized from snapshots of the healthy replicas.
+// The quarantine is persisted, so that it survives restarts, and is lifted
+// when the replica receives a snapshot. Replacements which fail (e.g. because
+// there's no suitable target store) are retried every
+// storeScrubberReplaceRetryInterval, independently of the scrubbing passes.
+
+var storeScrubberInterval = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kv.store_scrubber.interval",
+	"the time between the starts of successive passes verifying the checksums of all SSTables"+
+		" of each store; set to 0 to disable store scrubbing",
+	24*time.Hour,
+	settings.NonNegativeDuration,
+)
+
+var storeScrubberRate = settings.RegisterByteSizeSetting(
+	settings.SystemOnly,
+	"kv.store_scrubber.max_rate",
+	"the rate limit (bytes/sec) at which each store's SSTables are read to verify their checksums",
+	8<<20, // 8MB
+	settings.PositiveInt,
+)
+
+// storeScrubberRateBurstFactor scales the burst of the store scrubber's rate
+// limiter based on the rate defined above, see consistencyCheckRateBurstFactor.
+const storeScrubberRateBurstFactor = 8
+
+// storeScrubberRateMinWait is the minimum time to wait once the rate limit is
+// reached.
+const storeScrubberRateMinWait = 100 * time.Millisecond
+
+// storeScrubberReplaceRetryInterval is the time between the attempts to
+// replace the quarantined replicas of a store.
+const storeScrubberReplaceRetryInterval = time.Minute
+
+// storeScrubber verifies the checksums of the SSTables of a store in the
+// background. See the comment at the top of this file.
+type storeScrubber struct {
+	store   *Store
+	limiter *quotapool.RateLimiter
+}
+
+func newStoreScrubber(s *Store) *storeScrubber {
+	sv := &s.ClusterSettings().SV
+	limiter := quotapool.NewRateLimiter(
+		"StoreScrubber",
+		quotapool.Limit(storeScrubberRate.Get(sv)),
+		storeScrubberRate.Get(sv)*storeScrubberRateBurstFactor,
+		quotapool.WithMinimumWait(storeScrubberRateMinWait))
+	storeScrubberRate.SetOnChange(sv, func(ctx context.Context) {
+		rate := storeScrubberRate.Get(sv)
+		limiter.UpdateLimit(quotapool.Limit(rate), rate*storeScrubberRateBurstFactor)
+	})
+	return &storeScrubber{store: s, limiter: limiter}
+}
+
+// start runs the scrubbing passes of the store until the stopper quiesces.
+func (ss *storeScrubber) start(ctx context.Context) {
+	s := ss.store
+	_ = s.stopper.RunAsyncTask(ctx, "store-scrubber", func(ctx context.Context) {
+		ctx, cancel := s.stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+
+		timer := timeutil.NewTimer()
+		defer timer.Stop()
+		retryTimer := timeutil.NewTimer()
+		defer retryTimer.Stop()
+		retryTimer.Reset(storeScrubberReplaceRetryInterval)
+		settingChangeCh := make(chan struct{}, 1)
+		storeScrubberInterval.SetOnChange(&s.ClusterSettings().SV, func(ctx context.Context) {
+			select {
+			case settingChangeCh <- struct{}{}:
+			default:
+			}
+		})
+
+		lastPass := timeutil.Now()
+		for {
+			// Scrubbing is disabled while the interval is 0, in which case the
+			// timer channel stays nil until the setting changes.
+			var timerC <-chan time.Time
+			if interval := storeScrubberInterval.Get(&s.ClusterSettings().SV); interval > 0 {
+				timer.Reset(timeutil.Until(lastPass.Add(interval)))
+				timerC = timer.C
+			}
+			select {
+			case <-timerC:
+				timer.Read = true
+				lastPass = timeutil.Now()
+				if err := ss.scrub(ctx); err != nil && ctx.Err() == nil {
+					log.Warningf(ctx, "failed to scrub store: %v", err)
+				}
+			case <-retryTimer.C:
+				retryTimer.Read = true
+				retryTimer.Reset(storeScrubberReplaceRetryInterval)
+				ss.replaceQuarantined(ctx)
+			case <-settingChangeCh:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// scrub runs a single scrubbing pass: it verifies the checksums of all the
+// SSTables of the store, quarantines the replicas overlapping corrupted
+// SSTables, and attempts to replace all the quarantined replicas.
+func (ss *storeScrubber) scrub(ctx context.Context) error {
+	s := ss.store
+	var pacer *admission.Pacer
+	if s.cfg.KVAdmissionController != nil {
+		pacer = s.cfg.KVAdmissionController.AdmitStoreScrub()
+	}
+	defer pacer.Close()
+
+	engines := []storage.Engine{s.StateEngine()}
+	if s.LogEngine() != s.StateEngine() {
+		engines = append(engines, s.LogEngine())
+	}
+	for _, eng := range engines {
+		ssts, err := eng.ListSSTables()
+		if err != nil {
+			return err
+		}
+		for _, sst := range ssts {
+			if err := pacer.Pace(ctx); err != nil {
+				return err
+			}
+			if err := ss.limiter.WaitN(ctx, int64(sst.Size)); err != nil {
+				return err
+			}
+			if err := eng.VerifySSTableChecksums(sst); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				log.Errorf(ctx, "corrupted SSTable %d in [%s, %s]: %v",
+					sst.FileNum, sst.Smallest, sst.Largest, err)
+				s.metrics.ScrubberCorruptSSTables.Inc(1)
+				ss.quarantineOverlapping(ctx, sst)
+			}
+			s.metrics.ScrubberSSTablesVerified.Inc(1)
+			s.metrics.ScrubberBytesVerified.Inc(int64(sst.Size))
+		}
+	}
+
+	ss.replaceQuarantined(ctx)
+	return ctx.Err()
+}
+
+// replaceQuarantined attempts to replace all the quarantined replicas of the
+// store.
+func (ss *storeScrubber) replaceQuarantined(ctx context.Context) {
+	ss.store.VisitReplicas(func(repl *Replica) (wantMore bool) {
+		if repl.quarantined.Load() {
+			if err := ss.replace(ctx, repl); err != nil {
+				log.Warningf(ctx, "failed to replace quarantined replica %s: %v", repl, err)
+			}
+		}
+		return ctx.Err() == nil
+	})
+}
+
+// quarantineOverlapping quarantines all the replicas of the store which have
+// keys overlapping the bounds of the given SSTable.
+func (ss *storeScrubber) quarantineOverlapping(ctx context.Context, sst storage.SSTable) {
+	s := ss.store
+	s.VisitReplicas(func(repl *Replica) (wantMore bool) {
+		if !replicaOverlapsSSTable(repl.Desc(), sst) {
+			return true
+		}
+		if quarantined, err := repl.quarantine(ctx); err != nil {
+			// The replica is still quarantined in memory.
+			log.Errorf(ctx, "failed to persist quarantine of replica %s: %v", repl, err)
+		} else if quarantined {
+			log.Errorf(ctx, "quarantining replica %s overlapping corrupted SSTable %d", repl, sst.FileNum)
+			s.metrics.ScrubberQuarantinedReplicas.Inc(1)
+		}
+		return true
+	})
+}
+
+// quarantine quarantines the replica, and persists the quarantine so that it
+// survives restarts. It returns false if the replica was already quarantined.
+// The replica is quarantined in memory even if the quarantine can't be
+// persisted.
+func (r *Replica) quarantine(ctx context.Context) (bool, error) {
+	// Holding raftMu serializes the quarantine with snapshot application, which
+	// lifts it.
+	r.raftMu.Lock()
+	defer r.raftMu.Unlock()
+	if r.quarantined.Swap(true) {
+		return false, nil
+	}
+	now := r.store.Clock().Now()
+	batch := r.store.TODOEngine().NewUnindexedBatch()
+	defer batch.Close()
+	if err := storage.MVCCPutProto(ctx, batch, nil /* ms */, keys.RangeQuarantinedKey(r.RangeID),
+		hlc.Timestamp{}, hlc.ClockTimestamp{}, nil /* txn */, &now); err != nil {
+		return true, err
+	}
+	return true, batch.Commit(true /* sync */)
+}
+
+// loadQuarantinedRaftMuLocked returns whether the quarantine of the replica
+// was persisted.
+func (r *Replica) loadQuarantinedRaftMuLocked(ctx context.Context) (bool, error) {
+	var ts hlc.Timestamp
+	return storage.MVCCGetProto(ctx, r.store.TODOEngine(), keys.RangeQuarantinedKey(r.RangeID),
+		hlc.Timestamp{}, &ts, storage.MVCCGetOptions{})
+}
+
+// replicaOverlapsSSTable returns whether any of the keys of the replica with
+// the given descriptor, including its unreplicated range-ID local keys, may
+// be stored in the given SSTable.
+func replicaOverlapsSSTable(desc *roachpb.RangeDescriptor, sst storage.SSTable) bool {
+	spans := rditer.Select(desc.RangeID, rditer.SelectOpts{
+		ReplicatedBySpan:      desc.RSpan(),
+		ReplicatedByRangeID:   true,
+		UnreplicatedByRangeID: true,
+	})
+	for _, span := range spans {
+		// The bounds of the SSTable are inclusive, those of the span aren't.
+		if span.Key.Compare(sst.Largest) <= 0 && sst.Smallest.Compare(span.EndKey) < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// replace attempts to replace the given quarantined replica by a new replica
+// on another store. If the replica holds the lease, it's transferred away
+// first.
+func (ss *storeScrubber) replace(ctx context.Context, repl *Replica) error {
+	s := ss.store
+	ctx = repl.AnnotateCtx(ctx)
+	desc, conf := repl.DescAndSpanConfig()
+	self, ok := desc.GetReplicaDescriptor(s.StoreID())
+	if !ok {
+		// The replica was already removed from the range, and will be garbage
+		// collected.
+		return nil
+	}
+
+	if repl.OwnsValidLease(ctx, s.Clock().NowAsClockTimestamp()) {
+		for _, target := range desc.Replicas().VoterDescriptors() {
+			if target.StoreID == s.StoreID() || target.Type != roachpb.VOTER_FULL {
+				continue
+			}
+			err := repl.AdminTransferLease(ctx, target.StoreID, false /* bypassSafetyChecks */)
+			if err == nil {
+				log.Infof(ctx, "transferred lease of quarantined replica to %s", target)
+				break
+			}
+			log.VEventf(ctx, 1, "failed to transfer lease to %s: %v", target, err)
+		}
+		if repl.OwnsValidLease(ctx, s.Clock().NowAsClockTimestamp()) {
+			return errors.Errorf("no voter to transfer the lease to")
+		}
+		// The replication changes below are routed to the new leaseholder.
+	}
+
+	selfTarget := roachpb.ReplicationTarget{NodeID: self.NodeID, StoreID: self.StoreID}
+	var addType, removeType roachpb.ReplicaChangeType
+	var allocate func() (roachpb.ReplicationTarget, string, error)
+	// Witnesses are passed along with the replicas of the other type, so that
+	// the replacement isn't placed on the store of a witness.
+	existingVoters := desc.Replicas().VoterDescriptors()
+	existingNonVoters := desc.Replicas().NonVoterDescriptors()
+	existingWitnesses := desc.Replicas().WitnessDescriptors()
+	switch self.Type {
+	case roachpb.VOTER_FULL:
+		addType, removeType = roachpb.ADD_VOTER, roachpb.REMOVE_VOTER
+		allocate = func() (roachpb.ReplicationTarget, string, error) {
+			return s.allocator.AllocateVoter(ctx, s.cfg.StorePool, conf, existingVoters,
+				append(existingNonVoters, existingWitnesses...), &self, allocatorimpl.Alive)
+		}
+	case roachpb.NON_VOTER:
+		addType, removeType = roachpb.ADD_NON_VOTER, roachpb.REMOVE_NON_VOTER
+		allocate = func() (roachpb.ReplicationTarget, string, error) {
+			return s.allocator.AllocateNonVoter(ctx, s.cfg.StorePool, conf,
+				append(existingVoters, existingWitnesses...), existingNonVoters, &self, allocatorimpl.Alive)
+		}
+	case roachpb.LEARNER:
+		removeType = roachpb.REMOVE_VOTER
+	case roachpb.WITNESS:
+		// Witnesses are only added through simple configuration changes, so they
+		// can't be swapped atomically. The witness is removed, and the replicate
+		// queue adds a new one.
+		removeType = roachpb.REMOVE_WITNESS
+	default:
+		// The range is in a joint configuration, which the replicate queue will
+		// leave shortly.
+		return errors.Errorf("replica is of type %s", self.Type)
+	}
+
+	chgs := kvpb.MakeReplicationChanges(removeType, selfTarget)
+	if allocate != nil {
+		// Voters and non-voters are only removed along with the addition of
+		// their replacement, which avoids reducing the range's fault tolerance.
+		if s.cfg.StorePool == nil {
+			return errors.Errorf("no store pool to allocate a replacement")
+		}
+		target, details, err := allocate()
+		if err != nil {
+			return err
+		}
+		log.Infof(ctx, "replacing quarantined replica by %s: %s", target, details)
+		chgs = append(kvpb.MakeReplicationChanges(addType, target), chgs...)
+	} else {
+		log.Infof(ctx, "removing quarantined replica")
+	}
+	_, err := s.DB().AdminChangeReplicas(ctx, desc.StartKey.AsRawKey(), *desc, chgs)
+	return err
+}
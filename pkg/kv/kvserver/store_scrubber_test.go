@@ -0,0 +1,150 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvstorage"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplicaOverlapsSSTable verifies that a replica overlaps the SSTables
+// which may contain any of its keys, including its range-ID local keys.
+func TestReplicaOverlapsSSTable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	desc := &roachpb.RangeDescriptor{
+		RangeID:  2,
+		StartKey: roachpb.RKey("b"),
+		EndKey:   roachpb.RKey("d"),
+	}
+	for _, tc := range []struct {
+		name              string
+		smallest, largest roachpb.Key
+		exp               bool
+	}{
+		{"before", roachpb.Key("a"), roachpb.Key("a\xff"), false},
+		{"ending at start key", roachpb.Key("a"), roachpb.Key("b"), true},
+		{"inside", roachpb.Key("c"), roachpb.Key("c"), true},
+		{"covering", roachpb.Key("a"), roachpb.Key("e"), true},
+		{"starting at end key", roachpb.Key("d"), roachpb.Key("e"), false},
+		{"range descriptor", keys.RangeDescriptorKey(desc.StartKey), keys.RangeDescriptorKey(desc.StartKey), true},
+		{"raft state", keys.RaftHardStateKey(2), keys.RaftHardStateKey(2), true},
+		{"applied state", keys.RangeAppliedStateKey(2), keys.RangeAppliedStateKey(2), true},
+		{"other range's raft state", keys.RaftHardStateKey(3), keys.RaftHardStateKey(3), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sst := storage.SSTable{Smallest: tc.smallest, Largest: tc.largest}
+			require.Equal(t, tc.exp, replicaOverlapsSSTable(desc, sst))
+		})
+	}
+}
+
+// TestStoreScrubberQuarantinesOverlappingReplicas verifies that the replicas
+// overlapping a corrupted SSTable are quarantined, and that quarantined
+// replicas refuse to send snapshots.
+func TestStoreScrubberQuarantinesOverlappingReplicas(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	tc := testContext{}
+	tc.Start(ctx, t, stopper)
+
+	splitKey := roachpb.RKey("m")
+	leftRepl := tc.store.LookupReplica(roachpb.RKey("a"))
+	rightRepl := splitTestRange(tc.store, splitKey, t)
+
+	ss := newStoreScrubber(tc.store)
+	sst := storage.SSTable{FileNum: 1, Smallest: roachpb.Key("n"), Largest: roachpb.Key("o")}
+	for i := 0; i < 2; i++ {
+		// Quarantining a replica again doesn't count it twice.
+		ss.quarantineOverlapping(ctx, sst)
+		require.False(t, leftRepl.quarantined.Load())
+		require.True(t, rightRepl.quarantined.Load())
+		require.Equal(t, int64(1), tc.store.metrics.ScrubberQuarantinedReplicas.Count())
+	}
+
+	err := rightRepl.validateSnapshotDelegationRequest(ctx, &kvserverpb.DelegateSendSnapshotRequest{
+		DescriptorGeneration: rightRepl.Desc().Generation,
+		RecipientReplica:     rightRepl.Desc().Replicas().Descriptors()[0],
+	})
+	require.ErrorContains(t, err, "quarantined replica cannot send snapshot")
+}
+
+// TestStoreScrubberPersistsQuarantine verifies that the quarantine of a replica
+// survives restarts.
+func TestStoreScrubberPersistsQuarantine(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	tc := testContext{}
+	tc.Start(ctx, t, stopper)
+
+	repl := tc.repl
+	load := func() bool {
+		loaded, err := kvstorage.LoadReplicaState(
+			ctx, tc.store.TODOEngine(), tc.store.StoreID(), repl.Desc(), repl.ReplicaID())
+		require.NoError(t, err)
+		reloaded, err := newInitializedReplica(tc.store, loaded)
+		require.NoError(t, err)
+		return reloaded.quarantined.Load()
+	}
+	require.False(t, load())
+
+	quarantined, err := repl.quarantine(ctx)
+	require.NoError(t, err)
+	require.True(t, quarantined)
+	quarantined, err = repl.quarantine(ctx)
+	require.NoError(t, err)
+	require.False(t, quarantined)
+	require.True(t, load())
+}
+
+// TestStoreScrubberReplace verifies that the scrubber doesn't remove the sole
+// replica of a range, which holds the lease and can't transfer it.
+func TestStoreScrubberReplace(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	tc := testContext{}
+	tc.Start(ctx, t, stopper)
+
+	ss := newStoreScrubber(tc.store)
+	repl := tc.repl
+	// Make sure the replica holds the lease, which quarantined replicas can't
+	// acquire.
+	_, pErr := repl.redirectOnOrAcquireLease(ctx)
+	require.NoError(t, pErr.GoError())
+	_, err := repl.quarantine(ctx)
+	require.NoError(t, err)
+
+	require.ErrorContains(t, ss.replace(ctx, repl), "no voter to transfer the lease to")
+	require.Len(t, repl.Desc().Replicas().Descriptors(), 1)
+}
@@ -570,8 +570,21 @@ func (kvSS *kvBatchSnapshotStrategy) Send(
 		return nil
 	}
 
+	// Witnesses don't retain the range's user data, so snapshots sent to them
+	// only contain the range's local keys. The recipient clears the user data
+	// spans when ingesting the snapshot.
+	var skipSpans []roachpb.Span
+	if header.RaftMessageRequest.ToReplica.IsWitness() {
+		skipSpans = rditer.MakeUserDataKeySpans(snap.State.Desc)
+	}
+
 	err := rditer.IterateReplicaKeySpans(snap.State.Desc, snap.EngineSnap, true, /* replicatedOnly */
-		func(iter storage.EngineIterator, _ roachpb.Span, keyType storage.IterKeyType) error {
+		func(iter storage.EngineIterator, span roachpb.Span, keyType storage.IterKeyType) error {
+			for _, skipSpan := range skipSpans {
+				if span.Equal(skipSpan) {
+					return nil
+				}
+			}
 			timingTag.start("iter")
 			defer timingTag.stop("iter")
 
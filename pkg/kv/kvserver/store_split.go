@@ -320,6 +320,11 @@ func (s *Store) SplitRange(
 	// clear them.
 	leftRepl.concMgr.OnRangeSplit()
 
+	// Writes to the keys which now belong to the RHS are no longer applied by
+	// the LHS, so its hot key cache could become stale if the ranges were to
+	// merge again. Start over.
+	leftRepl.hotKeyCache.SetHotKeys(nil)
+
 	if rightReplOrNil == nil {
 		// There is no RHS replica, so (heuristically) halve the load stats for the
 		// LHS, instead of splitting it between LHS and RHS.
@@ -1554,7 +1554,7 @@ func confChangeImpl(
 			if err := checkNotExists(rDesc); err != nil {
 				return nil, err
 			}
-		case VOTER_FULL:
+		case VOTER_FULL, WITNESS:
 			// A voter can't be in the descriptor if it's being removed.
 			if err := checkNotExists(rDesc); err != nil {
 				return nil, err
@@ -1581,6 +1581,11 @@ func confChangeImpl(
 			// We're adding a voter, but will transition into a joint config
 			// first.
 			changeType = raftpb.ConfChangeAddNode
+		case WITNESS:
+			// We're promoting a learner to a witness, which votes like any
+			// other voter. Witnesses are only ever added through simple
+			// configuration changes.
+			changeType = raftpb.ConfChangeAddNode
 		case LEARNER, NON_VOTER:
 			// We're adding a learner or non-voter.
 			// Note that we're guaranteed by virtue of the upstream ChangeReplicas txn
@@ -1957,9 +1962,11 @@ func AsIntents(txn *enginepb.TxnMeta, keys []Key) []Intent {
 }
 
 // MakeLockAcquisition makes a lock acquisition message from the given
-// txn, key, and durability level.
-func MakeLockAcquisition(txn *Transaction, key Key, dur lock.Durability) LockAcquisition {
-	return LockAcquisition{Span: Span{Key: key}, Txn: txn.TxnMeta, Durability: dur}
+// txn, key, durability level, and lock strength.
+func MakeLockAcquisition(
+	txn *Transaction, key Key, dur lock.Durability, str lock.Strength,
+) LockAcquisition {
+	return LockAcquisition{Span: Span{Key: key}, Txn: txn.TxnMeta, Durability: dur, Strength: str}
 }
 
 // MakeLockUpdate makes a lock update from the given txn and span.
@@ -1991,8 +1998,25 @@ func (ls LockStateInfo) SafeFormat(w redact.SafePrinter, r rune) {
 		}
 	}
 	w.Printf("holder=%s ", redactableLockHolder)
+	if ls.LockStrength != lock.None {
+		w.Printf("strength=%s ", ls.LockStrength)
+	}
 	w.Printf("durability=%s ", ls.Durability)
 	w.Printf("duration=%s", ls.HoldDuration)
+	if len(ls.OtherHolders) > 0 {
+		w.Printf("\n other holders:")
+		for _, h := range ls.OtherHolders {
+			redactableHolder := redact.Sprint(nil)
+			if h.Txn != nil {
+				if expand {
+					redactableHolder = redact.Sprint(h.Txn.ID)
+				} else {
+					redactableHolder = redact.Sprint(h.Txn.Short())
+				}
+			}
+			w.Printf("\n  holder=%s strength=%s durability=%s", redactableHolder, h.Strength, h.Durability)
+		}
+	}
 	if len(ls.Waiters) > 0 {
 		w.Printf("\n waiters:")
 
@@ -89,12 +89,16 @@ func predNonVoter(rDesc ReplicaDescriptor) bool {
 	return rDesc.Type == NON_VOTER
 }
 
+func predWitness(rDesc ReplicaDescriptor) bool {
+	return rDesc.Type == WITNESS
+}
+
 func predVoterOrNonVoter(rDesc ReplicaDescriptor) bool {
 	return predVoterFullOrIncoming(rDesc) || predNonVoter(rDesc)
 }
 
-func predVoterFullOrNonVoter(rDesc ReplicaDescriptor) bool {
-	return predVoterFull(rDesc) || predNonVoter(rDesc)
+func predVoterFullOrNonVoterOrWitness(rDesc ReplicaDescriptor) bool {
+	return predVoterFull(rDesc) || predNonVoter(rDesc) || predWitness(rDesc)
 }
 
 // Voters returns a ReplicaSet of current and future voter replicas in `d`. This
@@ -227,12 +231,25 @@ func (d ReplicaSet) NonVoterDescriptors() []ReplicaDescriptor {
 }
 
 // VoterFullAndNonVoterDescriptors returns the descriptors of
-// VOTER_FULL/NON_VOTER replicas in the set. This set will not contain learners
-// or, during an atomic replication change, incoming or outgoing voters.
-// Notably, this set must encapsulate all replicas of a range for a range merge
-// to proceed.
+// VOTER_FULL/NON_VOTER/WITNESS replicas in the set. This set will not contain
+// learners or, during an atomic replication change, incoming or outgoing
+// voters. Notably, this set must encapsulate all replicas of a range for a
+// range merge to proceed.
 func (d ReplicaSet) VoterFullAndNonVoterDescriptors() []ReplicaDescriptor {
-	return d.FilterToDescriptors(predVoterFullOrNonVoter)
+	return d.FilterToDescriptors(predVoterFullOrNonVoterOrWitness)
+}
+
+// Witnesses returns a ReplicaSet containing only the witnesses in `d`.
+// Witnesses are voters, and so count towards the quorum(s), but they are not
+// returned by Voters() since they hold none of the data of the range: they
+// can't hold the lease, serve reads, or send snapshots.
+func (d ReplicaSet) Witnesses() ReplicaSet {
+	return d.Filter(predWitness)
+}
+
+// WitnessDescriptors returns the witness replica descriptors in the set.
+func (d ReplicaSet) WitnessDescriptors() []ReplicaDescriptor {
+	return d.FilterToDescriptors(predWitness)
 }
 
 // VoterAndNonVoterDescriptors returns the descriptors of VOTER_FULL,
@@ -322,7 +339,7 @@ func (d ReplicaSet) InAtomicReplicationChange() bool {
 		case VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING_LEARNER,
 			VOTER_DEMOTING_NON_VOTER:
 			return true
-		case VOTER_FULL, LEARNER, NON_VOTER:
+		case VOTER_FULL, LEARNER, NON_VOTER, WITNESS:
 		default:
 			panic(fmt.Sprintf("unknown replica type %d", rDesc.Type))
 		}
@@ -340,7 +357,7 @@ func (d ReplicaSet) ConfState() raftpb.ConfState {
 	for _, rep := range d.wrapped {
 		id := uint64(rep.ReplicaID)
 		switch rep.Type {
-		case VOTER_FULL:
+		case VOTER_FULL, WITNESS:
 			cs.Voters = append(cs.Voters, id)
 			if joint {
 				cs.VotersOutgoing = append(cs.VotersOutgoing, id)
@@ -486,9 +503,9 @@ func (d ReplicaSet) ReplicationTargets() (out []ReplicationTarget) {
 // IsAddition returns true if `c` refers to a replica addition operation.
 func (c ReplicaChangeType) IsAddition() bool {
 	switch c {
-	case ADD_NON_VOTER, ADD_VOTER:
+	case ADD_NON_VOTER, ADD_VOTER, ADD_WITNESS:
 		return true
-	case REMOVE_NON_VOTER, REMOVE_VOTER:
+	case REMOVE_NON_VOTER, REMOVE_VOTER, REMOVE_WITNESS:
 		return false
 	default:
 		panic(fmt.Sprintf("unexpected ReplicaChangeType %s", c))
@@ -498,9 +515,9 @@ func (c ReplicaChangeType) IsAddition() bool {
 // IsRemoval returns true if `c` refers a replica removal operation.
 func (c ReplicaChangeType) IsRemoval() bool {
 	switch c {
-	case ADD_NON_VOTER, ADD_VOTER:
+	case ADD_NON_VOTER, ADD_VOTER, ADD_WITNESS:
 		return false
-	case REMOVE_NON_VOTER, REMOVE_VOTER:
+	case REMOVE_NON_VOTER, REMOVE_VOTER, REMOVE_WITNESS:
 		return true
 	default:
 		panic(fmt.Sprintf("unexpected ReplicaChangeType %s", c))
@@ -548,6 +565,10 @@ func CheckCanReceiveLease(
 	if !ok {
 		return ErrReplicaNotFound
 	}
+	if repDesc.IsWitness() {
+		// Witnesses hold none of the data of the range.
+		return ErrReplicaCannotHoldLease
+	}
 	if !(repDesc.IsVoterNewConfig() ||
 		(repDesc.IsVoterOldConfig() && replDescs.containsVoterIncoming() && wasLastLeaseholder)) {
 		// We allow a demoting / incoming voter to receive the lease if there's an incoming voter.
@@ -182,6 +182,21 @@ func TestReplicaDescriptorsConfState(t *testing.T) {
 			[]ReplicaDescriptor{rd(VOTER_OUTGOING, 1), rd(VOTER_DEMOTING_LEARNER, 2), rd(VOTER_INCOMING, 3), rd(VOTER_INCOMING, 4), rd(LEARNER, 5)},
 			"Voters:[3 4] VotersOutgoing:[1 2] Learners:[5] LearnersNext:[2] AutoLeave:false",
 		},
+		// Witnesses are voters.
+		{
+			[]ReplicaDescriptor{rd(VOTER_FULL, 1), rd(VOTER_FULL, 2), rd(WITNESS, 3)},
+			"Voters:[1 2 3] VotersOutgoing:[] Learners:[] LearnersNext:[] AutoLeave:false",
+		},
+		// Swapping out witness n3 for n4, which is promoted from a learner.
+		{
+			[]ReplicaDescriptor{rd(VOTER_FULL, 1), rd(VOTER_FULL, 2), rd(VOTER_OUTGOING, 3), rd(VOTER_INCOMING, 4)},
+			"Voters:[1 2 4] VotersOutgoing:[1 2 3] Learners:[] LearnersNext:[] AutoLeave:false",
+		},
+		// A witness which isn't changing is part of both configs.
+		{
+			[]ReplicaDescriptor{rd(VOTER_FULL, 1), rd(VOTER_OUTGOING, 2), rd(WITNESS, 3), rd(VOTER_INCOMING, 4)},
+			"Voters:[1 3 4] VotersOutgoing:[1 2 3] Learners:[] LearnersNext:[] AutoLeave:false",
+		},
 	}
 
 	for _, test := range tests {
@@ -277,6 +292,18 @@ func TestReplicaDescriptorsCanMakeProgress(t *testing.T) {
 			{false, rd(LEARNER, 6)},
 			{false, rd(LEARNER, 7)},
 		}, true},
+		// Two out of three voters alive, one of which is a witness.
+		{[]descWithLiveness{
+			{false, rd(VOTER_FULL, 1)},
+			{true, rd(VOTER_FULL, 2)},
+			{true, rd(WITNESS, 3)},
+		}, true},
+		// Two out of three voters dead, one of which is a witness.
+		{[]descWithLiveness{
+			{true, rd(VOTER_FULL, 1)},
+			{false, rd(VOTER_FULL, 2)},
+			{false, rd(WITNESS, 3)},
+		}, false},
 		// Non-joint case that should be live unless the learner is somehow taken
 		// into account.
 		{[]descWithLiveness{
@@ -305,6 +332,35 @@ func TestReplicaDescriptorsCanMakeProgress(t *testing.T) {
 	}
 }
 
+// TestReplicaDescriptorsWitnesses verifies that witnesses are treated as voters
+// by the quorum logic, but are excluded from the voters holding data.
+func TestReplicaDescriptorsWitnesses(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	witness := rd(WITNESS, 3)
+	r := MakeReplicaSet([]ReplicaDescriptor{rd(VOTER_FULL, 1), rd(VOTER_FULL, 2), witness, rd(NON_VOTER, 4)})
+	require.Equal(t, []ReplicaDescriptor{witness}, r.WitnessDescriptors())
+	require.Equal(t, []ReplicaDescriptor{rd(VOTER_FULL, 1), rd(VOTER_FULL, 2)}, r.VoterDescriptors())
+	require.Len(t, r.VoterFullAndNonVoterDescriptors(), 4)
+	require.False(t, r.InAtomicReplicationChange())
+	require.True(t, witness.IsVoterOldConfig())
+	require.True(t, witness.IsVoterNewConfig())
+	require.True(t, witness.IsAnyVoter())
+	require.False(t, witness.IsNonVoter())
+
+	// Witnesses count towards the number of voters when determining
+	// over and under-replication.
+	allLive := func(ReplicaDescriptor) bool { return true }
+	status := r.ReplicationStatus(allLive, 3 /* neededVoters */, 1 /* neededNonVoters */)
+	require.Equal(t, RangeStatusReport{Available: true}, status)
+	status = r.ReplicationStatus(allLive, 5 /* neededVoters */, 1 /* neededNonVoters */)
+	require.True(t, status.UnderReplicated)
+
+	// Witnesses can never receive the lease.
+	require.ErrorIs(t, CheckCanReceiveLease(witness, r, false /* wasLastLeaseholder */), ErrReplicaCannotHoldLease)
+	require.NoError(t, CheckCanReceiveLease(rd(VOTER_FULL, 1), r, false /* wasLastLeaseholder */))
+}
+
 // Test that ReplicaDescriptors.CanMakeProgress() agrees with the equivalent
 // etcd/raft's code. We generate random configs and then see whether out
 // determination for unavailability matches etcd/raft.
@@ -92,9 +92,30 @@ func (s *SpanConfig) ValidateSystemTargetSpanConfig() error {
 	if s.ExcludeDataFromBackup {
 		return errors.AssertionFailedf("ExcludeDataFromBackup set on system span config")
 	}
+	if s.RangeMaxWriteRequestsPerSecond != 0 {
+		return errors.AssertionFailedf("RangeMaxWriteRequestsPerSecond set on system span config")
+	}
+	if s.RangeMaxWriteBytesPerSecond != 0 {
+		return errors.AssertionFailedf("RangeMaxWriteBytesPerSecond set on system span config")
+	}
+	if s.WriteRateLimitZoneID != 0 {
+		return errors.AssertionFailedf("WriteRateLimitZoneID set on system span config")
+	}
+	if s.WriteRateLimitSubzoneID != 0 {
+		return errors.AssertionFailedf("WriteRateLimitSubzoneID set on system span config")
+	}
+	if s.NumWitnesses != 0 {
+		return errors.AssertionFailedf("NumWitnesses set on system span config")
+	}
 	return nil
 }
 
+// HasWriteRateLimits returns whether the span config limits the rate of
+// writes to its ranges.
+func (s *SpanConfig) HasWriteRateLimits() bool {
+	return s.RangeMaxWriteRequestsPerSecond != 0 || s.RangeMaxWriteBytesPerSecond != 0
+}
+
 // GetNumVoters returns the number of voting replicas as defined in the
 // span config.
 func (s *SpanConfig) GetNumVoters() int32 {
@@ -104,6 +125,12 @@ func (s *SpanConfig) GetNumVoters() int32 {
 	return s.NumReplicas
 }
 
+// GetNumFullVoters returns the number of voting replicas which are not
+// witnesses, as defined in the span config.
+func (s *SpanConfig) GetNumFullVoters() int32 {
+	return s.GetNumVoters() - s.NumWitnesses
+}
+
 // GetNumNonVoters returns the number of non-voting replicas as defined in the
 // span config.
 func (s *SpanConfig) GetNumNonVoters() int32 {
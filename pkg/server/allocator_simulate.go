@@ -0,0 +1,250 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/whatif"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/rangedesc"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// maxAllocatorSimulateDuration is the longest simulated duration accepted by
+// AllocatorSimulate. The cost of a simulation grows with both its duration and
+// the number of ranges in the cluster, and it runs on the node serving the
+// request.
+const maxAllocatorSimulateDuration = 24 * time.Hour
+
+// AllocatorSimulate snapshots the stores, ranges, range load and span configs
+// of the cluster into the allocation simulator, and simulates the effect of
+// the changes proposed in the request on the replica and lease placement.
+func (s *systemAdminServer) AllocatorSimulate(
+	ctx context.Context, req *serverpb.AllocatorSimulateRequest,
+) (*serverpb.AllocatorSimulateResponse, error) {
+	ctx = forwardSQLIdentityThroughRPCCalls(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	if _, err := s.requireAdminUser(ctx); err != nil {
+		// NB: not using serverError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+	if req.Duration <= 0 {
+		return nil, grpcstatus.Errorf(codes.InvalidArgument, "duration must be positive; got %s", req.Duration)
+	}
+	if req.Duration > maxAllocatorSimulateDuration {
+		return nil, grpcstatus.Errorf(codes.InvalidArgument,
+			"duration must be at most %s; got %s", maxAllocatorSimulateDuration, req.Duration)
+	}
+	for _, add := range req.AddNodes {
+		if add.Count < 0 || add.StoresPerNode < 0 {
+			return nil, grpcstatus.Errorf(codes.InvalidArgument,
+				"node and store counts must be non-negative; got %d nodes with %d stores",
+				add.Count, add.StoresPerNode)
+		}
+	}
+
+	snapshot, decommissioning, err := s.allocatorSimulationSnapshot(ctx)
+	if err != nil {
+		return nil, serverError(ctx, err)
+	}
+
+	proposal := whatif.Proposal{DecommissionNodes: decommissioning}
+	for _, add := range req.AddNodes {
+		proposal.AddNodes = append(proposal.AddNodes, whatif.NodeSpec{
+			Count:         int(add.Count),
+			StoresPerNode: int(add.StoresPerNode),
+			Locality:      add.Locality,
+		})
+	}
+	for _, nodeID := range req.DecommissionNodeIDs {
+		proposal.DecommissionNodes = append(proposal.DecommissionNodes, nodeID)
+	}
+	for _, change := range req.ZoneConfigChanges {
+		proposal.ZoneConfigChanges = append(proposal.ZoneConfigChanges, whatif.ZoneConfigChange{
+			Span: change.Span,
+			Zone: change.ZoneConfig,
+		})
+	}
+
+	log.Infof(ctx, "simulating allocator changes for %s over %d stores and %d ranges",
+		req.Duration, len(snapshot.Stores), len(snapshot.Ranges))
+	report, err := whatif.Run(ctx, snapshot, proposal, whatif.Options{Duration: req.Duration})
+	if err != nil {
+		return nil, grpcstatus.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return allocatorSimulateResponse(report, len(snapshot.Ranges)), nil
+}
+
+// allocatorSimulationSnapshot returns a snapshot of the stores, ranges, range
+// load and span configs of the cluster. Stores on decommissioned nodes are
+// excluded. The IDs of nodes which are decommissioning are also returned, so
+// that the simulation continues to decommission them.
+//
+// The load of each range is collected from its leaseholder. Ranges whose
+// leaseholder couldn't be reached are simulated without load.
+func (s *systemAdminServer) allocatorSimulationSnapshot(
+	ctx context.Context,
+) (_ whatif.ClusterSnapshot, decommissioning []roachpb.NodeID, _ error) {
+	var snapshot whatif.ClusterSnapshot
+
+	// The store pool and span configs are identical on every store of the
+	// node, so use the first one.
+	var evalStore *kvserver.Store
+	if err := s.server.node.stores.VisitStores(func(s *kvserver.Store) error {
+		if evalStore == nil {
+			evalStore = s
+		}
+		return nil
+	}); err != nil {
+		return whatif.ClusterSnapshot{}, nil, err
+	}
+	if evalStore == nil {
+		return whatif.ClusterSnapshot{}, nil, errors.Errorf("n%d has no initialized store", s.server.NodeID())
+	}
+
+	livenessStatusByNodeID, err := getLivenessStatusMap(ctx, s.nodeLiveness, s.clock.Now().GoTime(), s.st)
+	if err != nil {
+		return whatif.ClusterSnapshot{}, nil, err
+	}
+	seenNodes := make(map[roachpb.NodeID]bool)
+	for _, desc := range evalStore.GetStoreConfig().StorePool.GetStores() {
+		nodeID := desc.Node.NodeID
+		switch livenessStatusByNodeID[nodeID] {
+		case livenesspb.NodeLivenessStatus_DECOMMISSIONED:
+			continue
+		case livenesspb.NodeLivenessStatus_DECOMMISSIONING:
+			if !seenNodes[nodeID] {
+				decommissioning = append(decommissioning, nodeID)
+			}
+		}
+		seenNodes[nodeID] = true
+		snapshot.Stores = append(snapshot.Stores, desc)
+	}
+
+	confReader, err := evalStore.GetConfReader(ctx)
+	if err != nil {
+		return whatif.ClusterSnapshot{}, nil, err
+	}
+	const pageSize = 10000
+	rangeDescScanner := rangedesc.NewScanner(s.db)
+	if err := rangeDescScanner.Scan(ctx, pageSize, func() {
+		snapshot.Ranges = snapshot.Ranges[:0]
+	}, keys.EverythingSpan, func(descriptors ...roachpb.RangeDescriptor) error {
+		for _, desc := range descriptors {
+			conf, err := confReader.GetSpanConfigForKey(ctx, desc.StartKey)
+			if err != nil {
+				return err
+			}
+			snapshot.Ranges = append(snapshot.Ranges, whatif.RangeSnapshot{
+				Descriptor: desc,
+				Config:     conf,
+			})
+		}
+		return nil
+	}); err != nil {
+		return whatif.ClusterSnapshot{}, nil, err
+	}
+
+	// Collect the load of each range from its leaseholder.
+	loadByRangeID := make(map[roachpb.RangeID]serverpb.RangeInfo)
+	dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+		return s.server.status.dialNode(ctx, nodeID)
+	}
+	nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+		status := client.(serverpb.StatusClient)
+		return status.Ranges(ctx, &serverpb.RangesRequest{NodeId: "local"})
+	}
+	responseFn := func(_ roachpb.NodeID, resp interface{}) {
+		for _, info := range resp.(*serverpb.RangesResponse).Ranges {
+			if info.IsLeaseholder {
+				loadByRangeID[info.State.Desc.RangeID] = info
+			}
+		}
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		log.Warningf(ctx, "unable to collect range load from n%d: %v", nodeID, err)
+	}
+	if err := s.server.status.iterateNodes(
+		ctx, "range load", dialFn, nodeFn, responseFn, errorFn,
+	); err != nil {
+		return whatif.ClusterSnapshot{}, nil, err
+	}
+	for i := range snapshot.Ranges {
+		r := &snapshot.Ranges[i]
+		info, ok := loadByRangeID[r.Descriptor.RangeID]
+		if !ok {
+			continue
+		}
+		r.Leaseholder = info.SourceStoreID
+		if info.State.Stats != nil {
+			r.LogicalBytes = info.State.Stats.Total()
+		}
+		r.ReadsPerSecond = info.Stats.ReadsPerSecond
+		r.WritesPerSecond = info.Stats.WritesPerSecond
+		r.ReadBytesPerSecond = info.Stats.ReadBytesPerSecond
+		r.WriteBytesPerSecond = info.Stats.WriteBytesPerSecond
+	}
+	return snapshot, decommissioning, nil
+}
+
+func allocatorSimulateResponse(
+	report whatif.Report, rangesSimulated int,
+) *serverpb.AllocatorSimulateResponse {
+	balance := func(b whatif.Balance) serverpb.AllocatorSimulateResponse_Balance {
+		return serverpb.AllocatorSimulateResponse_Balance{
+			Mean:   b.Mean,
+			Min:    b.Min,
+			Max:    b.Max,
+			StdDev: b.StdDev,
+		}
+	}
+	resp := &serverpb.AllocatorSimulateResponse{
+		ReplicaBalanceBefore: balance(report.ReplicaBalanceBefore),
+		ReplicaBalanceAfter:  balance(report.ReplicaBalanceAfter),
+		LeaseBalanceBefore:   balance(report.LeaseBalanceBefore),
+		LeaseBalanceAfter:    balance(report.LeaseBalanceAfter),
+		QPSBalanceBefore:     balance(report.QPSBalanceBefore),
+		QPSBalanceAfter:      balance(report.QPSBalanceAfter),
+		ReplicaMoves:         report.ReplicaMoves,
+		LeaseTransfers:       report.LeaseTransfers,
+		RangeSplits:          report.RangeSplits,
+		RebalancedBytes:      report.RebalancedBytes,
+		Converged:            report.Converged,
+		TimeToConverge:       report.TimeToConverge,
+		RangesSimulated:      int64(rangesSimulated),
+	}
+	for _, s := range report.Stores {
+		resp.Stores = append(resp.Stores, serverpb.AllocatorSimulateResponse_Store{
+			StoreID:         s.StoreID,
+			NodeID:          s.NodeID,
+			Added:           s.Added,
+			Decommissioning: s.Decommissioning,
+			ReplicasBefore:  s.ReplicasBefore,
+			ReplicasAfter:   s.ReplicasAfter,
+			LeasesBefore:    s.LeasesBefore,
+			LeasesAfter:     s.LeasesAfter,
+			QPSBefore:       s.QPSBefore,
+			QPSAfter:        s.QPSAfter,
+		})
+	}
+	return resp
+}
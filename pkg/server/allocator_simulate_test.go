@@ -0,0 +1,86 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// TestAllocatorSimulate tests that the AllocatorSimulate endpoint simulates
+// changes starting from the current state of the cluster.
+func TestAllocatorSimulate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := serverutils.StartNewTestCluster(t, 3, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+
+	adminSrv := tc.Server(0)
+	conn, err := adminSrv.RPCContext().GRPCDialNode(
+		adminSrv.RPCAddr(), adminSrv.NodeID(), rpc.DefaultClass).Connect(ctx)
+	require.NoError(t, err)
+	adminClient := serverpb.NewAdminClient(conn)
+
+	req := &serverpb.AllocatorSimulateRequest{
+		AddNodes: []serverpb.AllocatorSimulateRequest_AddNodes{{Count: 1}},
+		Duration: 10 * time.Minute,
+	}
+	var resp *serverpb.AllocatorSimulateResponse
+	// Wait until the stores of every node have been gossiped.
+	testutils.SucceedsSoon(t, func() error {
+		resp, err = adminClient.AllocatorSimulate(ctx, req)
+		if err != nil {
+			return err
+		}
+		if len(resp.Stores) != 4 {
+			return errors.Newf("expected 4 stores, found %d", len(resp.Stores))
+		}
+		return nil
+	})
+	require.Positive(t, resp.RangesSimulated)
+	for i, s := range resp.Stores[:3] {
+		require.False(t, s.Added)
+		require.Equal(t, tc.Server(i).GetFirstStoreID(), s.StoreID)
+		require.Positive(t, s.ReplicasBefore)
+	}
+	added := resp.Stores[3]
+	require.True(t, added.Added)
+	require.Equal(t, roachpb.NodeID(4), added.NodeID)
+	require.Equal(t, roachpb.StoreID(4), added.StoreID)
+	require.Zero(t, added.ReplicasBefore)
+
+	for _, req := range []*serverpb.AllocatorSimulateRequest{
+		{},
+		{Duration: 25 * time.Hour},
+		{DecommissionNodeIDs: []roachpb.NodeID{34}, Duration: time.Minute},
+		{AddNodes: []serverpb.AllocatorSimulateRequest_AddNodes{{Count: -1}}, Duration: time.Minute},
+	} {
+		_, err := adminClient.AllocatorSimulate(ctx, req)
+		require.Error(t, err)
+		require.Equal(t, codes.InvalidArgument, grpcstatus.Code(err), "%+v", err)
+	}
+}
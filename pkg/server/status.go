@@ -2603,6 +2603,14 @@ func (s *systemStatusServer) HotRangesV2(
 						})
 					}
 
+					var hotKeys []serverpb.HotRangesResponseV2_HotRange_HotKey
+					for _, key := range r.HotKeys {
+						hotKeys = append(hotKeys, serverpb.HotRangesResponseV2_HotRange_HotKey{
+							Key:       key,
+							PrettyKey: key.String(),
+						})
+					}
+
 					ranges = append(ranges, &serverpb.HotRangesResponseV2_HotRange{
 						RangeID:             r.Desc.RangeID,
 						NodeID:              requestedNodeID,
@@ -2619,6 +2627,7 @@ func (s *systemStatusServer) HotRangesV2(
 						ReplicaNodeIds:      replicaNodeIDs,
 						LeaseholderNodeID:   r.LeaseholderNodeID,
 						StoreID:             store.StoreID,
+						HotKeys:             hotKeys,
 					})
 				}
 			}
@@ -2713,6 +2722,7 @@ func (s *systemStatusServer) localHotRanges(
 			storeResp.HotRanges[i].WriteBytesPerSecond = r.WriteBytesPerSecond
 			storeResp.HotRanges[i].ReadBytesPerSecond = r.ReadBytesPerSecond
 			storeResp.HotRanges[i].CPUTimePerSecond = r.CPUTimePerSecond
+			storeResp.HotRanges[i].HotKeys = r.HotKeys
 		}
 		resp.Stores = append(resp.Stores, storeResp)
 		return nil
@@ -53,16 +53,22 @@ var fields = [config.NumFields]Field{
 	constraints,
 	voterConstraints,
 	leasePreferences,
+	rangeMaxWriteRequestsPerSecond,
+	rangeMaxWriteBytesPerSecond,
+	numWitnesses,
 }
 
 const (
-	rangeMaxBytes    = int64Field(config.RangeMaxBytes)
-	rangeMinBytes    = int64Field(config.RangeMinBytes)
-	globalReads      = boolField(config.GlobalReads)
-	numReplicas      = int32Field(config.NumReplicas)
-	numVoters        = int32Field(config.NumVoters)
-	gcTTLSeconds     = int32Field(config.GCTTL)
-	constraints      = constraintsConjunctionField(config.Constraints)
-	voterConstraints = constraintsConjunctionField(config.VoterConstraints)
-	leasePreferences = leasePreferencesField(config.LeasePreferences)
+	rangeMaxBytes                  = int64Field(config.RangeMaxBytes)
+	rangeMinBytes                  = int64Field(config.RangeMinBytes)
+	globalReads                    = boolField(config.GlobalReads)
+	numReplicas                    = int32Field(config.NumReplicas)
+	numVoters                      = int32Field(config.NumVoters)
+	gcTTLSeconds                   = int32Field(config.GCTTL)
+	constraints                    = constraintsConjunctionField(config.Constraints)
+	voterConstraints               = constraintsConjunctionField(config.VoterConstraints)
+	leasePreferences               = leasePreferencesField(config.LeasePreferences)
+	rangeMaxWriteRequestsPerSecond = int64Field(config.RangeMaxWriteRequestsPerSecond)
+	rangeMaxWriteBytesPerSecond    = int64Field(config.RangeMaxWriteBytesPerSecond)
+	numWitnesses                   = int32Field(config.NumWitnesses)
 )
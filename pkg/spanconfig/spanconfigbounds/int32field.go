@@ -37,6 +37,9 @@ func (f int32Field) FieldBound(b *Bounds) ValueBounds {
 			return b.NumVoters
 		case gcTTLSeconds:
 			return b.GCTTLSeconds
+		case numWitnesses:
+			// Witnesses are a subset of the voters, which are already bounded.
+			return nil
 		default:
 			// This is safe because we test that all the fields in the proto have
 			// a corresponding field, and we call this for each of them, and the user
@@ -62,6 +65,8 @@ func (f int32Field) fieldValue(c *roachpb.SpanConfig) *int32 {
 		return &c.NumVoters
 	case gcTTLSeconds:
 		return &c.GCPolicy.TTLSeconds
+	case numWitnesses:
+		return &c.NumWitnesses
 	default:
 		// This is safe because we test that all the fields in the proto have
 		// a corresponding field, and we call this for each of them, and the user
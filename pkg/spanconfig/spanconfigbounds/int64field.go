@@ -37,6 +37,10 @@ func (f int64Field) FieldBound(b *Bounds) ValueBounds {
 			return b.RangeMaxBytes
 		case rangeMinBytes:
 			return b.RangeMinBytes
+		case rangeMaxWriteRequestsPerSecond, rangeMaxWriteBytesPerSecond:
+			// The write rate limits only throttle the tenant's own traffic, so
+			// the tenant is free to configure them.
+			return nil
 		default:
 			// This is safe because we test that all the fields in the proto have
 			// a corresponding field, and we call this for each of them, and the user
@@ -60,6 +64,10 @@ func (f int64Field) fieldValue(c *roachpb.SpanConfig) *int64 {
 		return &c.RangeMaxBytes
 	case rangeMinBytes:
 		return &c.RangeMinBytes
+	case rangeMaxWriteRequestsPerSecond:
+		return &c.RangeMaxWriteRequestsPerSecond
+	case rangeMaxWriteBytesPerSecond:
+		return &c.RangeMaxWriteBytesPerSecond
 	default:
 		// This is safe because we test that all the fields in the proto have
 		// a corresponding field, and we call this for each of them, and the user
@@ -290,6 +290,9 @@ func (s *SQLTranslator) generateSpanConfigurationsForNamedZone(
 		return nil, err
 	}
 	spanConfig := zoneConfig.AsSpanConfig()
+	if spanConfig.HasWriteRateLimits() {
+		spanConfig.WriteRateLimitZoneID = uint32(id)
+	}
 	var records []spanconfig.Record
 	for _, span := range spans {
 		record, err := spanconfig.MakeRecord(
@@ -349,6 +352,16 @@ func (s *SQLTranslator) generateSpanConfigurationsForTable(
 	// backups.
 	tableSpanConfig.ExcludeDataFromBackup = table.GetExcludeDataFromBackup()
 
+	// Identify the zone the write rate limits of the table came from, so that
+	// KV can attribute the writes it rejects to it.
+	if tableSpanConfig.HasWriteRateLimits() {
+		zoneID, err := sql.GetZoneIDForTable(ctx, txn, s.txn.Descriptors(), table.GetID())
+		if err != nil {
+			return nil, err
+		}
+		tableSpanConfig.WriteRateLimitZoneID = uint32(zoneID)
+	}
+
 	records := make([]spanconfig.Record, 0)
 	if table.GetID() == keys.DescriptorTableID {
 		// We have named ranges preceding `system.descriptor`.
@@ -449,6 +462,10 @@ func (s *SQLTranslator) generateSpanConfigurationsForTable(
 			subzoneSpanConfig.RangefeedEnabled = true
 			subzoneSpanConfig.GCPolicy.IgnoreStrictEnforcement = true
 		}
+		if subzoneSpanConfig.HasWriteRateLimits() {
+			subzoneSpanConfig.WriteRateLimitZoneID = uint32(table.GetID())
+			subzoneSpanConfig.WriteRateLimitSubzoneID = uint32(zone.SubzoneSpans[i].SubzoneIndex) + 1
+		}
 		record, err := spanconfig.MakeRecord(
 			spanconfig.MakeTargetFromSpan(roachpb.Span{Key: span.Key, EndKey: span.EndKey}), subzoneSpanConfig)
 		if err != nil {
@@ -662,6 +662,21 @@ func PrintSpanConfigDiffedAgainstDefaults(conf roachpb.SpanConfig) string {
 	if conf.ExcludeDataFromBackup != defaultConf.ExcludeDataFromBackup {
 		diffs = append(diffs, fmt.Sprintf("exclude_data_from_backup=%v", conf.ExcludeDataFromBackup))
 	}
+	if conf.RangeMaxWriteRequestsPerSecond != defaultConf.RangeMaxWriteRequestsPerSecond {
+		diffs = append(diffs, fmt.Sprintf("range_max_write_requests_per_second=%d", conf.RangeMaxWriteRequestsPerSecond))
+	}
+	if conf.RangeMaxWriteBytesPerSecond != defaultConf.RangeMaxWriteBytesPerSecond {
+		diffs = append(diffs, fmt.Sprintf("range_max_write_bytes_per_second=%d", conf.RangeMaxWriteBytesPerSecond))
+	}
+	if conf.WriteRateLimitZoneID != defaultConf.WriteRateLimitZoneID {
+		diffs = append(diffs, fmt.Sprintf("write_rate_limit_zone_id=%d", conf.WriteRateLimitZoneID))
+	}
+	if conf.WriteRateLimitSubzoneID != defaultConf.WriteRateLimitSubzoneID {
+		diffs = append(diffs, fmt.Sprintf("write_rate_limit_subzone_id=%d", conf.WriteRateLimitSubzoneID))
+	}
+	if conf.NumWitnesses != defaultConf.NumWitnesses {
+		diffs = append(diffs, fmt.Sprintf("num_witnesses=%d", conf.NumWitnesses))
+	}
 
 	return strings.Join(diffs, " ")
 }
@@ -0,0 +1,110 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package catpb
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ChangefeedMaskTableSettingName is the name of the table storage parameter
+// holding the masking policy applied to every changefeed on the table.
+const ChangefeedMaskTableSettingName = "changefeed_mask"
+
+// ChangefeedMaskFunc is a function used to mask the value of a column before
+// it is emitted by a changefeed.
+type ChangefeedMaskFunc int
+
+// The values for ChangefeedMaskFunc.
+const (
+	// ChangefeedMaskHash replaces the value with its HMAC-SHA256 digest, keyed
+	// with the cluster secret.
+	ChangefeedMaskHash ChangefeedMaskFunc = iota + 1
+	// ChangefeedMaskTruncate keeps only a prefix of the value.
+	ChangefeedMaskTruncate
+	// ChangefeedMaskNull replaces the value with NULL.
+	ChangefeedMaskNull
+)
+
+// ChangefeedMask is the masking function applied to a single column.
+type ChangefeedMask struct {
+	Column string
+	Func   ChangefeedMaskFunc
+	// Length is the length of the prefix kept by ChangefeedMaskTruncate.
+	Length int
+}
+
+// String implements fmt.Stringer.
+func (m ChangefeedMask) String() string {
+	switch m.Func {
+	case ChangefeedMaskHash:
+		return m.Column + "=hash"
+	case ChangefeedMaskTruncate:
+		return m.Column + "=truncate(" + strconv.Itoa(m.Length) + ")"
+	case ChangefeedMaskNull:
+		return m.Column + "=null"
+	default:
+		return m.Column + "=unknown"
+	}
+}
+
+// ParseChangefeedMasks parses a masking policy of the form
+// `col=fn[,col=fn...]` where fn is one of `hash`, `null` or `truncate(n)`.
+func ParseChangefeedMasks(policy string) ([]ChangefeedMask, error) {
+	if strings.TrimSpace(policy) == "" {
+		return nil, nil
+	}
+	var masks []ChangefeedMask
+	seen := make(map[string]struct{})
+	for _, entry := range strings.Split(policy, ",") {
+		col, fn, ok := strings.Cut(entry, "=")
+		col, fn = strings.TrimSpace(col), strings.ToLower(strings.TrimSpace(fn))
+		if !ok || col == "" || fn == "" {
+			return nil, errors.Newf("invalid mask %q: expected <column>=<function>", entry)
+		}
+		if _, dup := seen[col]; dup {
+			return nil, errors.Newf("column %q is masked more than once", col)
+		}
+		seen[col] = struct{}{}
+
+		m := ChangefeedMask{Column: col}
+		switch {
+		case fn == "hash":
+			m.Func = ChangefeedMaskHash
+		case fn == "null":
+			m.Func = ChangefeedMaskNull
+		case strings.HasPrefix(fn, "truncate(") && strings.HasSuffix(fn, ")"):
+			n, err := strconv.Atoi(strings.TrimSpace(fn[len("truncate(") : len(fn)-1]))
+			if err != nil || n < 0 {
+				return nil, errors.Newf("invalid mask %q: truncate requires a non-negative length", entry)
+			}
+			m.Func = ChangefeedMaskTruncate
+			m.Length = n
+		default:
+			return nil, errors.Newf(
+				"invalid mask %q: unknown function %q, valid functions are hash, null and truncate(n)",
+				entry, fn)
+		}
+		masks = append(masks, m)
+	}
+	return masks, nil
+}
+
+// FormatChangefeedMasks is the inverse of ParseChangefeedMasks.
+func FormatChangefeedMasks(masks []ChangefeedMask) string {
+	strs := make([]string, len(masks))
+	for i, m := range masks {
+		strs[i] = m.String()
+	}
+	return strings.Join(strs, ",")
+}
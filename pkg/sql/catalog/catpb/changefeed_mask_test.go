@@ -0,0 +1,61 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package catpb_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChangefeedMasks(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		policy    string
+		expected  []catpb.ChangefeedMask
+		expectErr string
+	}{
+		{policy: "", expected: nil},
+		{
+			policy: "email=hash, ssn = NULL,phone=truncate(4)",
+			expected: []catpb.ChangefeedMask{
+				{Column: "email", Func: catpb.ChangefeedMaskHash},
+				{Column: "ssn", Func: catpb.ChangefeedMaskNull},
+				{Column: "phone", Func: catpb.ChangefeedMaskTruncate, Length: 4},
+			},
+		},
+		{policy: "email", expectErr: "expected <column>=<function>"},
+		{policy: "=hash", expectErr: "expected <column>=<function>"},
+		{policy: "email=hash,email=null", expectErr: "masked more than once"},
+		{policy: "email=rot13", expectErr: "unknown function"},
+		{policy: "email=truncate(-1)", expectErr: "non-negative length"},
+		{policy: "email=truncate(x)", expectErr: "non-negative length"},
+	} {
+		t.Run(tc.policy, func(t *testing.T) {
+			masks, err := catpb.ParseChangefeedMasks(tc.policy)
+			if tc.expectErr != "" {
+				require.True(t, testutils.IsError(err, tc.expectErr), "%v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, masks)
+
+			// Formatting the masks produces an equivalent policy.
+			roundTrip, err := catpb.ParseChangefeedMasks(catpb.FormatChangefeedMasks(masks))
+			require.NoError(t, err)
+			require.Equal(t, masks, roundTrip)
+		})
+	}
+}
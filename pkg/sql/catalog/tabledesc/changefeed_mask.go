@@ -0,0 +1,56 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tabledesc
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// ValidateChangefeedMasks validates that every masked column exists in the
+// table and that its type is compatible with the masking function.
+func ValidateChangefeedMasks(desc catalog.TableDescriptor, masks []catpb.ChangefeedMask) error {
+	for _, m := range masks {
+		col := catalog.FindColumnByName(desc, m.Column)
+		if col == nil {
+			return pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q referenced by %s does not exist", m.Column, catpb.ChangefeedMaskTableSettingName)
+		}
+		if err := ValidateChangefeedMask(col, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateChangefeedMask validates that the masking function can be applied to
+// the column.
+func ValidateChangefeedMask(col catalog.Column, m catpb.ChangefeedMask) error {
+	switch m.Func {
+	case catpb.ChangefeedMaskHash, catpb.ChangefeedMaskTruncate:
+		switch col.GetType().Family() {
+		case types.StringFamily, types.BytesFamily:
+		default:
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot apply mask %s to column %q of type %s: only STRING and BYTES columns can be hashed or truncated",
+				m, col.GetName(), col.GetType().SQLString())
+		}
+	case catpb.ChangefeedMaskNull:
+		if !col.IsNullable() {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot apply mask %s to non-nullable column %q", m, col.GetName())
+		}
+	}
+	return nil
+}
@@ -2360,6 +2360,11 @@ func (desc *wrapper) GetExcludeDataFromBackup() bool {
 	return desc.ExcludeDataFromBackup
 }
 
+// GetChangefeedMask implements the TableDescriptor interface.
+func (desc *wrapper) GetChangefeedMask() string {
+	return desc.ChangefeedMask
+}
+
 // GetStorageParams implements the TableDescriptor interface.
 func (desc *wrapper) GetStorageParams(spaceBetweenEqual bool) []string {
 	var storageParams []string
@@ -2403,6 +2408,9 @@ func (desc *wrapper) GetStorageParams(spaceBetweenEqual bool) []string {
 	if exclude := desc.GetExcludeDataFromBackup(); exclude {
 		appendStorageParam(`exclude_data_from_backup`, `true`)
 	}
+	if mask := desc.GetChangefeedMask(); mask != "" {
+		appendStorageParam(catpb.ChangefeedMaskTableSettingName, lexbase.EscapeSQLString(mask))
+	}
 	if settings := desc.AutoStatsSettings; settings != nil {
 		if settings.Enabled != nil {
 			value := *settings.Enabled
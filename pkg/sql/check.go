@@ -19,9 +19,11 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/flowinfra"
@@ -599,6 +601,59 @@ func RevalidateUniqueConstraintsInTable(
 	return nil
 }
 
+// RevalidateForeignKeysAndChecksInTable verifies that all rows in the given
+// table satisfy its validated outbound foreign key and CHECK constraints. It is
+// used after bulk ingestion (e.g. IMPORT INTO), which writes rows without
+// evaluating these constraints.
+//
+// Hash-sharded column check constraints are skipped since the values of the
+// shard columns are computed by the ingestion itself.
+func RevalidateForeignKeysAndChecksInTable(
+	ctx context.Context, txn descs.Txn, sv *settings.Values, tableDesc *tabledesc.Mutable,
+) error {
+	for _, fk := range tableDesc.OutboundForeignKeys() {
+		if !fk.IsConstraintValidated() {
+			continue
+		}
+		targetTable, err := txn.Descriptors().ByID(txn.KV()).Get().Table(ctx, fk.GetReferencedTableID())
+		if err != nil {
+			return err
+		}
+		if err := validateForeignKey(
+			ctx, txn, tableDesc, targetTable, fk.ForeignKeyDesc(), 0, /* indexIDForValidation */
+		); err != nil {
+			log.Errorf(ctx, "validation of foreign keys failed for table %s: %s", tableDesc.GetName(), err)
+			return errors.Wrapf(err, "for table %s", tableDesc.GetName())
+		}
+	}
+
+	// The semaCtx is used to pretty print the check expression back to the user
+	// on failure, which requires resolving types by ID.
+	resolver := descs.NewDistSQLTypeResolver(txn.Descriptors(), txn.KV())
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.TypeResolver = &resolver
+	sessionData := NewFakeSessionData(sv, "validate constraint")
+	for _, ck := range tableDesc.CheckConstraints() {
+		if !ck.IsConstraintValidated() || ck.IsHashShardingConstraint() {
+			continue
+		}
+		violatingRow, formattedCkExpr, err := validateCheckExpr(
+			ctx, &semaCtx, txn, sessionData, ck.GetExpr(), tableDesc, 0, /* indexIDForValidation */
+		)
+		if err != nil {
+			return err
+		}
+		if len(violatingRow) > 0 {
+			err := newCheckViolationErr(formattedCkExpr, tableDesc.AccessibleColumns(), violatingRow)
+			log.Errorf(ctx, "validation of check constraints failed for table %s: %s", tableDesc.GetName(), err)
+			return errors.Wrapf(err, "for table %s", tableDesc.GetName())
+		}
+	}
+
+	log.Infof(ctx, "validated all foreign key and check constraints in table %s", tableDesc.GetName())
+	return nil
+}
+
 // validateUniqueConstraint verifies that all the rows in the srcTable
 // have unique values for the given columns.
 //
@@ -253,8 +253,11 @@ var (
 	errChangeFrontierWrap             = errors.New("core.ChangeFrontier is not supported")
 	errReadImportWrap                 = errors.New("core.ReadImport is not supported")
 	errBackupDataWrap                 = errors.New("core.BackupData is not supported")
+	errCompactBackupsWrap             = errors.New("core.CompactBackups is not supported")
+	errVerifyBackupWrap               = errors.New("core.VerifyBackup is not supported")
 	errBackfillerWrap                 = errors.New("core.Backfiller is not supported (not an execinfra.RowSource)")
 	errExporterWrap                   = errors.New("core.Exporter is not supported (not an execinfra.RowSource)")
+	errExportIcebergWrap              = errors.New("core.ExportIceberg is not supported (not an execinfra.RowSource)")
 	errSamplerWrap                    = errors.New("core.Sampler is not supported (not an execinfra.RowSource)")
 	errSampleAggregatorWrap           = errors.New("core.SampleAggregator is not supported (not an execinfra.RowSource)")
 	errExperimentalWrappingProhibited = errors.New("wrapping for non-JoinReader and non-LocalPlanNode cores is prohibited in vectorize=experimental_always")
@@ -287,6 +290,8 @@ func canWrap(mode sessiondatapb.VectorizeExecMode, core *execinfrapb.ProcessorCo
 		return errReadImportWrap
 	case core.Exporter != nil:
 		return errExporterWrap
+	case core.ExportIceberg != nil:
+		return errExportIcebergWrap
 	case core.Sampler != nil:
 		return errSamplerWrap
 	case core.SampleAggregator != nil:
@@ -309,6 +314,10 @@ func canWrap(mode sessiondatapb.VectorizeExecMode, core *execinfrapb.ProcessorCo
 	case core.InvertedJoiner != nil:
 	case core.BackupData != nil:
 		return errBackupDataWrap
+	case core.CompactBackups != nil:
+		return errCompactBackupsWrap
+	case core.VerifyBackup != nil:
+		return errVerifyBackupWrap
 	case core.SplitAndScatter != nil:
 	case core.RestoreData != nil:
 	case core.Filterer != nil:
@@ -204,6 +204,7 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalTenantUsageDetailsViewID:           crdbInternalTenantUsageDetailsView,
 		catconstants.CrdbInternalPgCatalogTableIsImplementedTableID: crdbInternalPgCatalogTableIsImplementedTable,
 		catconstants.CrdbInternalShowTenantCapabilitiesCacheTableID: crdbInternalShowTenantCapabilitiesCache,
+		catconstants.CrdbInternalKVHotKeysTableID:                   crdbInternalKVHotKeysTable,
 	},
 	validWithNoDatabaseContext: true,
 }
@@ -5279,6 +5280,53 @@ CREATE TABLE crdb_internal.kv_store_status (
 	},
 }
 
+// crdbInternalKVHotKeysTable exposes the keys which are detected as hot on the
+// leaseholders of the cluster's hot ranges, and whose reads are served from
+// the leaseholders' hot key caches.
+var crdbInternalKVHotKeysTable = virtualSchemaTable{
+	comment: "keys read often and written rarely, whose reads are served from the leaseholder's hot key cache (cluster RPC; expensive!)",
+	schema: `
+CREATE TABLE crdb_internal.kv_hot_keys (
+  range_id      INT NOT NULL,
+  node_id       INT NOT NULL,
+  store_id      INT NOT NULL,
+  database_name STRING NOT NULL,
+  schema_name   STRING NOT NULL,
+  table_name    STRING NOT NULL,
+  index_name    STRING NOT NULL,
+  key           BYTES NOT NULL,
+  pretty_key    STRING NOT NULL
+)
+	`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.kv_hot_keys"); err != nil {
+			return err
+		}
+		response, err := p.ExecCfg().TenantStatusServer.HotRangesV2(ctx, &serverpb.HotRangesRequest{})
+		if err != nil {
+			return err
+		}
+		for _, r := range response.Ranges {
+			for _, k := range r.HotKeys {
+				if err := addRow(
+					tree.NewDInt(tree.DInt(r.RangeID)),
+					tree.NewDInt(tree.DInt(r.NodeID)),
+					tree.NewDInt(tree.DInt(r.StoreID)),
+					tree.NewDString(r.DatabaseName),
+					tree.NewDString(r.SchemaName),
+					tree.NewDString(r.TableName),
+					tree.NewDString(r.IndexName),
+					tree.NewDBytes(tree.DBytes(k.Key)),
+					tree.NewDString(k.PrettyKey),
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
+
 var crdbInternalCatalogDescriptorTable = virtualSchemaTable{
 	comment: `like system.descriptor but overlaid with in-txn in-memory changes and including virtual objects`,
 	schema: `
@@ -7298,16 +7346,20 @@ func genClusterLocksGenerator(
 
 		var curLock *roachpb.LockStateInfo
 		var fErr error
-		waiterIdx := -1
-		// Flatten response such that both lock holders and lock waiters are each
+		rowIdx := 0
+		// Flatten response such that lock holders and lock waiters are each
 		// individual rows in the final output. As such, we iterate through the
 		// locks received in the response and first output the lock holder, then
-		// each waiter, prior to moving onto the next lock (or fetching additional
-		// results as necessary).
+		// any other transactions holding the lock with a Shared or Update strength,
+		// then each waiter, prior to moving onto the next lock (or fetching
+		// additional results as necessary).
+		numRows := func(l *roachpb.LockStateInfo) int {
+			return 1 + len(l.OtherHolders) + len(l.Waiters)
+		}
 		return func() (tree.Datums, error) {
-			if curLock == nil || waiterIdx >= len(curLock.Waiters) {
+			if curLock == nil || rowIdx >= numRows(curLock) {
 				curLock, fErr = getNextLock()
-				waiterIdx = -1
+				rowIdx = 0
 			}
 
 			// If we couldn't get any more locks from getNextLock(), we have finished
@@ -7320,21 +7372,39 @@ func genClusterLocksGenerator(
 			txnIDDatum := tree.DNull
 			tsDatum := tree.DNull
 			durationDatum := tree.DNull
+			durability := curLock.Durability
 			granted := false
-			// Utilize -1 to indicate that the row represents the lock holder.
-			if waiterIdx < 0 {
+			holdDurationDatum := func() tree.Datum {
+				return tree.NewDInterval(
+					duration.MakeDuration(curLock.HoldDuration.Nanoseconds(), 0 /* days */, 0 /* months */),
+					types.DefaultIntervalTypeMetadata,
+				)
+			}
+			// The first row represents the lock holder.
+			if rowIdx == 0 {
 				if curLock.LockHolder != nil {
 					txnIDDatum = tree.NewDUuid(tree.DUuid{UUID: curLock.LockHolder.ID})
 					tsDatum = eval.TimestampToInexactDTimestamp(curLock.LockHolder.WriteTimestamp)
-					strengthDatum = tree.NewDString(lock.Exclusive.String())
-					durationDatum = tree.NewDInterval(
-						duration.MakeDuration(curLock.HoldDuration.Nanoseconds(), 0 /* days */, 0 /* months */),
-						types.DefaultIntervalTypeMetadata,
-					)
+					strength := curLock.LockStrength
+					if strength == lock.None {
+						strength = lock.Exclusive
+					}
+					strengthDatum = tree.NewDString(strength.String())
+					durationDatum = holdDurationDatum()
 					granted = true
 				}
+			} else if holderIdx := rowIdx - 1; holderIdx < len(curLock.OtherHolders) {
+				holder := curLock.OtherHolders[holderIdx]
+				if holder.Txn != nil {
+					txnIDDatum = tree.NewDUuid(tree.DUuid{UUID: holder.Txn.ID})
+					tsDatum = eval.TimestampToInexactDTimestamp(holder.Txn.WriteTimestamp)
+				}
+				strengthDatum = tree.NewDString(holder.Strength.String())
+				durationDatum = holdDurationDatum()
+				durability = holder.Durability
+				granted = true
 			} else {
-				waiter := curLock.Waiters[waiterIdx]
+				waiter := curLock.Waiters[holderIdx-len(curLock.OtherHolders)]
 				if waiter.WaitingTxn != nil {
 					txnIDDatum = tree.NewDUuid(tree.DUuid{UUID: waiter.WaitingTxn.ID})
 					tsDatum = eval.TimestampToInexactDTimestamp(waiter.WaitingTxn.WriteTimestamp)
@@ -7346,7 +7416,7 @@ func genClusterLocksGenerator(
 				)
 			}
 
-			waiterIdx++
+			rowIdx++
 
 			tableID, dbName, schemaName, tableName, indexName := lookupNamesByKey(
 				p, curLock.Key, dbNames, tableNames, schemaNames,
@@ -7361,21 +7431,21 @@ func genClusterLocksGenerator(
 			}
 
 			return tree.Datums{
-				tree.NewDInt(tree.DInt(curLock.RangeID)),     /* range_id */
-				tree.NewDInt(tree.DInt(tableID)),             /* table_id */
-				tree.NewDString(dbName),                      /* database_name */
-				tree.NewDString(schemaName),                  /* schema_name */
-				tree.NewDString(tableName),                   /* table_name */
-				tree.NewDString(indexName),                   /* index_name */
-				tree.NewDBytes(tree.DBytes(keyOrRedacted)),   /* lock_key */
-				tree.NewDString(prettyKeyOrRedacted),         /* lock_key_pretty */
-				txnIDDatum,                                   /* txn_id */
-				tsDatum,                                      /* ts */
-				strengthDatum,                                /* lock_strength */
-				tree.NewDString(curLock.Durability.String()), /* durability */
-				tree.MakeDBool(tree.DBool(granted)),          /* granted */
-				tree.MakeDBool(len(curLock.Waiters) > 0),     /* contended */
-				durationDatum,                                /* duration */
+				tree.NewDInt(tree.DInt(curLock.RangeID)),   /* range_id */
+				tree.NewDInt(tree.DInt(tableID)),           /* table_id */
+				tree.NewDString(dbName),                    /* database_name */
+				tree.NewDString(schemaName),                /* schema_name */
+				tree.NewDString(tableName),                 /* table_name */
+				tree.NewDString(indexName),                 /* index_name */
+				tree.NewDBytes(tree.DBytes(keyOrRedacted)), /* lock_key */
+				tree.NewDString(prettyKeyOrRedacted),       /* lock_key_pretty */
+				txnIDDatum,                                 /* txn_id */
+				tsDatum,                                    /* ts */
+				strengthDatum,                              /* lock_strength */
+				tree.NewDString(durability.String()),       /* durability */
+				tree.MakeDBool(tree.DBool(granted)),        /* granted */
+				tree.MakeDBool(len(curLock.Waiters) > 0),   /* contended */
+				durationDatum,                              /* duration */
 			}, nil
 
 		}, nil, nil
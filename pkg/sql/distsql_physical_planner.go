@@ -48,6 +48,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/span"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
@@ -4393,7 +4394,9 @@ func (dsp *DistSQLPlanner) createPlanForWindow(
 }
 
 // createPlanForExport creates a physical plan for EXPORT.
-// We add a new stage of CSV/Parquet Writer processors to the input plan.
+// We add a new stage of CSV/Parquet Writer processors to the input plan. An
+// export into an Iceberg table adds a final stage on the gateway that commits
+// the files written by these processors to the table.
 func (dsp *DistSQLPlanner) createPlanForExport(
 	ctx context.Context, planCtx *PlanningCtx, n *exportNode,
 ) (*PhysicalPlan, error) {
@@ -4406,6 +4409,13 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 		return nil, err
 	}
 
+	var startTime hlc.Timestamp
+	if n.incremental {
+		if startTime, err = dsp.planIncrementalIcebergExport(ctx, planCtx, plan, n); err != nil {
+			return nil, err
+		}
+	}
+
 	var core execinfrapb.ProcessorCoreUnion
 	core.Exporter = &execinfrapb.ExportSpec{
 		Destination: n.destination,
@@ -4425,11 +4435,77 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 		core, execinfrapb.PostProcessSpec{}, resTypes, execinfrapb.Ordering{},
 	)
 
+	if n.iceberg {
+		var commitCore execinfrapb.ProcessorCoreUnion
+		commitCore.ExportIceberg = &execinfrapb.ExportIcebergSpec{
+			Destination:    n.destination,
+			UserProto:      planCtx.planner.User().EncodeProto(),
+			ColNames:       n.colNames,
+			ColTypes:       n.colTypes,
+			ColNullability: n.format.Parquet.ColNullability,
+			ReadTime:       planCtx.planner.Txn().ReadTimestamp(),
+			Incremental:    n.incremental,
+			StartTime:      startTime,
+		}
+		plan.AddSingleGroupStage(
+			ctx, dsp.gatewaySQLInstanceID, commitCore, execinfrapb.PostProcessSpec{}, resTypes,
+		)
+	}
+
 	// The CSVWriter produces the same columns as the EXPORT statement.
 	plan.PlanToStreamColMap = identityMap(plan.PlanToStreamColMap, len(colinfo.ExportColumns))
 	return plan, nil
 }
 
+// planIncrementalIcebergExport restricts the rows of the source of an
+// incremental export into an Iceberg table to those written since the read
+// time of the current snapshot of the table, which it returns, and projects
+// away the MVCC timestamp column that the source outputs for this purpose. The
+// export reads the rows as of its own read time, so that the consecutive
+// snapshots of the table hold the rows written in consecutive time ranges.
+func (dsp *DistSQLPlanner) planIncrementalIcebergExport(
+	ctx context.Context, planCtx *PlanningCtx, plan *PhysicalPlan, n *exportNode,
+) (hlc.Timestamp, error) {
+	if IcebergSnapshotReadTime == nil {
+		return hlc.Timestamp{}, errors.New("incremental Iceberg exports unimplemented")
+	}
+	es, err := planCtx.ExtendedEvalCtx.ExecCfg.DistSQLSrv.ExternalStorageFromURI(
+		ctx, n.destination, planCtx.planner.User(),
+	)
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	defer es.Close()
+	startTime, err := IcebergSnapshotReadTime(ctx, es)
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	if readTime := planCtx.planner.Txn().ReadTimestamp(); readTime.LessEq(startTime) {
+		return hlc.Timestamp{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			"the Iceberg table already holds the rows as of %s, which is not before the time of the export %s",
+			startTime.AsOfSystemTime(), readTime.AsOfSystemTime())
+	}
+
+	mvccCol := len(n.colNames)
+	if !startTime.IsEmpty() {
+		filter := tree.NewTypedComparisonExpr(
+			treecmp.MakeComparisonOperator(treecmp.GT),
+			tree.NewTypedOrdinalReference(mvccCol, types.Decimal),
+			eval.TimestampToDecimalDatum(startTime),
+		)
+		if err := plan.AddFilter(ctx, filter, planCtx, plan.PlanToStreamColMap); err != nil {
+			return hlc.Timestamp{}, err
+		}
+	}
+	projection := make([]uint32, mvccCol)
+	for i := range projection {
+		projection[i] = uint32(plan.PlanToStreamColMap[i])
+	}
+	plan.AddProjection(projection, execinfrapb.Ordering{})
+	plan.PlanToStreamColMap = identityMap(plan.PlanToStreamColMap, mvccCol)
+	return startTime, nil
+}
+
 func logAndSanitizeExportDestination(ctx context.Context, dest string) error {
 	clean, err := cloud.SanitizeExternalStorageURI(dest, nil)
 	if err != nil {
@@ -23,6 +23,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -59,6 +61,10 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 	); err != nil {
 		return nil, err
 	}
+	if len(n.StorageParams) > 0 {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"DROP TABLE ... WITH (...) is only supported by the declarative schema changer")
+	}
 
 	td := make(map[descpb.ID]toDelete, len(n.Names))
 	for i := range n.Names {
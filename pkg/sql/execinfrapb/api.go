@@ -67,11 +67,26 @@ func (m *BackupDataSpec) User() username.SQLUsername {
 	return m.UserProto.Decode()
 }
 
+// User accesses the user field.
+func (m *CompactBackupsSpec) User() username.SQLUsername {
+	return m.UserProto.Decode()
+}
+
+// User accesses the user field.
+func (m *VerifyBackupSpec) User() username.SQLUsername {
+	return m.UserProto.Decode()
+}
+
 // User accesses the user field.
 func (m *ExportSpec) User() username.SQLUsername {
 	return m.UserProto.Decode()
 }
 
+// User accesses the user field.
+func (m *ExportIcebergSpec) User() username.SQLUsername {
+	return m.UserProto.Decode()
+}
+
 // User accesses the user field.
 func (m *ReadImportDataSpec) User() username.SQLUsername {
 	return m.UserProto.Decode()
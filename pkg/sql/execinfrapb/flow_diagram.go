@@ -365,6 +365,24 @@ func (m *BackupDataSpec) summary() (string, []string) {
 	return "BACKUP", details
 }
 
+// summary implements the diagramCellType interface.
+func (m *CompactBackupsSpec) summary() (string, []string) {
+	details := []string{
+		fmt.Sprintf("Entries: %d", len(m.Entries)),
+		fmt.Sprintf("Interval: (%s, %s]", m.StartTime, m.EndTime),
+	}
+	return "COMPACT BACKUPS", details
+}
+
+// summary implements the diagramCellType interface.
+func (m *VerifyBackupSpec) summary() (string, []string) {
+	details := []string{
+		fmt.Sprintf("Files: %d", len(m.Files)),
+		fmt.Sprintf("Deep: %t", m.Deep),
+	}
+	return "VERIFY BACKUP", details
+}
+
 // summary implements the diagramCellType interface.
 func (d *DistinctSpec) summary() (string, []string) {
 	details := []string{
@@ -540,6 +558,11 @@ func (s *ExportSpec) summary() (string, []string) {
 	return "Exporter", []string{s.Destination}
 }
 
+// summary implements the diagramCellType interface.
+func (s *ExportIcebergSpec) summary() (string, []string) {
+	return "ExportIceberg", []string{s.Destination}
+}
+
 // summary implements the diagramCellType interface.
 func (s *BulkRowWriterSpec) summary() (string, []string) {
 	return "BulkRowWriterSpec", []string{}
@@ -28,7 +28,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/errors"
 )
@@ -48,6 +50,15 @@ type exportNode struct {
 	chunkRows       int
 	chunkSize       int64
 	colNames        []string
+	// iceberg is set if the exported Parquet files are committed to an Iceberg
+	// table at the destination, in which case colTypes describes the exported
+	// columns.
+	iceberg  bool
+	colTypes []*types.T
+	// incremental is set if only the rows written since the previous export
+	// into the Iceberg table are exported. The source then outputs the MVCC
+	// timestamp of each row after the exported columns.
+	incremental bool
 }
 
 func (e *exportNode) startExec(params runParams) error {
@@ -73,6 +84,7 @@ const (
 	exportOptionChunkSize   = "chunk_size"
 	exportOptionFileName    = "filename"
 	exportOptionCompression = "compression"
+	exportOptionIncremental = "incremental"
 
 	exportChunkSizeDefault = int64(32 << 20) // 32 MB
 	exportChunkRowsDefault = 100000
@@ -82,6 +94,11 @@ const (
 	exportSnappyCodec     = "snappy"
 	csvSuffix             = "csv"
 	parquetSuffix         = "parquet"
+	icebergSuffix         = "iceberg"
+
+	// icebergDataDir is the directory of the Parquet data files of an Iceberg
+	// table, relative to the location of the table.
+	icebergDataDir = "data/"
 )
 
 var exportOptionExpectValues = map[string]exprutil.KVStringOptValidate{
@@ -91,8 +108,17 @@ var exportOptionExpectValues = map[string]exprutil.KVStringOptValidate{
 	exportOptionNullAs:      exprutil.KVStringOptRequireValue,
 	exportOptionCompression: exprutil.KVStringOptRequireValue,
 	exportOptionChunkSize:   exprutil.KVStringOptRequireValue,
+	exportOptionIncremental: exprutil.KVStringOptRequireNoValue,
 }
 
+// IcebergSnapshotReadTime returns the read time recorded in the summary of the
+// current snapshot of the Iceberg table in the given storage, or an empty
+// timestamp if the table has no snapshot yet.
+//
+// It is implemented in the importer package and injected here via runtime
+// initialization.
+var IcebergSnapshotReadTime func(ctx context.Context, es cloud.ExternalStorage) (hlc.Timestamp, error)
+
 // featureExportEnabled is used to enable and disable the EXPORT feature.
 var featureExportEnabled = settings.RegisterBoolSetting(
 	settings.TenantWritable,
@@ -131,7 +157,7 @@ func (ef *execFactory) ConstructExport(
 		return nil, errors.Errorf("EXPORT cannot be used inside a multi-statement transaction")
 	}
 
-	if fileSuffix != csvSuffix && fileSuffix != parquetSuffix {
+	if fileSuffix != csvSuffix && fileSuffix != parquetSuffix && fileSuffix != icebergSuffix {
 		return nil, errors.Errorf("unsupported export format: %q", fileSuffix)
 	}
 
@@ -179,11 +205,24 @@ func (ef *execFactory) ConstructExport(
 		return nil, err
 	}
 
+	_, incremental := optVals[exportOptionIncremental]
+	if incremental && fileSuffix != icebergSuffix {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"%s is only supported for exports into Iceberg tables", exportOptionIncremental)
+	}
+
 	cols := planColumns(input.(planNode))
+	if incremental {
+		// The last column is the MVCC timestamp of the rows, which the optimizer
+		// added to select the rows to export. It is not exported.
+		cols = cols[:len(cols)-1]
+	}
 	colNames := make([]string, len(cols))
+	colTypes := make([]*types.T, len(cols))
 	colNullability := make([]bool, len(cols))
 	for i, col := range cols {
 		colNames[i] = col.Name
+		colTypes[i] = col.Typ
 		colNullability[i] = !notNullCols.Contains(i)
 	}
 
@@ -202,7 +241,8 @@ func (ef *execFactory) ConstructExport(
 		}
 		format.Format = roachpb.IOFileFormat_CSV
 		format.Csv = csvOpts
-	case parquetSuffix:
+	case parquetSuffix, icebergSuffix:
+		// The data files of an Iceberg table are Parquet files.
 		parquetOpts := roachpb.ParquetOptions{
 			ColNullability: colNullability,
 		}
@@ -239,7 +279,7 @@ func (ef *execFactory) ConstructExport(
 		switch {
 		case strings.EqualFold(name, exportGzipCodec):
 			codec = roachpb.IOFileFormat_Gzip
-		case strings.EqualFold(name, exportSnappyCodec) && fileSuffix == parquetSuffix:
+		case strings.EqualFold(name, exportSnappyCodec) && format.Format == roachpb.IOFileFormat_Parquet:
 			codec = roachpb.IOFileFormat_Snappy
 		default:
 			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
@@ -251,6 +291,9 @@ func (ef *execFactory) ConstructExport(
 	exportID := ef.planner.stmt.QueryID.String()
 	exportFilePattern := exportFilePatternPart + "." + fileSuffix
 	namePattern := fmt.Sprintf("export%s-%s", exportID, exportFilePattern)
+	if fileSuffix == icebergSuffix {
+		namePattern = fmt.Sprintf("%sexport%s-%s.%s", icebergDataDir, exportID, exportFilePatternPart, parquetSuffix)
+	}
 	return &exportNode{
 		source:          input.(planNode),
 		destination:     string(*destination),
@@ -259,5 +302,8 @@ func (ef *execFactory) ConstructExport(
 		chunkRows:       chunkRows,
 		chunkSize:       chunkSize,
 		colNames:        colNames,
+		iceberg:         fileSuffix == icebergSuffix,
+		colTypes:        colTypes,
+		incremental:     incremental,
 	}, nil
 }
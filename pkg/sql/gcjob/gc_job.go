@@ -308,6 +308,12 @@ func (r schemaChangeGCResumer) Resume(ctx context.Context, execCtx interface{})
 		return err
 	}
 
+	if purged, err := maybePurgeData(ctx, &execCfg, r.job.ID(), details, progress); err != nil {
+		return err
+	} else if purged {
+		return nil
+	}
+
 	if !shouldUseDelRange(ctx, details, execCfg.Settings, execCfg.GCJobTestingKnobs) {
 		return r.legacyWaitAndClearTableData(ctx, execCfg, details, progress)
 	}
@@ -0,0 +1,168 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gcjob
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// maybePurgeData clears the data of the dropped tables or indexes without
+// waiting for their GC TTL to expire, if the job was asked to purge it and it
+// is safe to do so. It returns whether all the elements of the job have been
+// GC'd; if not, the caller should fall back to waiting for the GC TTL.
+//
+// Purging is only safe if nothing may still need to read the dropped data: no
+// protected timestamp may cover it, regardless of its timestamp, and no
+// changefeed or backup may target the dropped descriptors.
+func maybePurgeData(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	jobID jobspb.JobID,
+	details *jobspb.SchemaChangeGCDetails,
+	progress *jobspb.SchemaChangeGCProgress,
+) (purged bool, _ error) {
+	if !details.Purge || details.Tenant != nil {
+		return false, nil
+	}
+	reason, err := purgeBlockedReason(ctx, execCfg, jobID, details)
+	if err != nil {
+		return false, err
+	}
+	if reason != "" {
+		log.Infof(ctx, "not purging dropped data, waiting for the GC TTL instead: %s", reason)
+		return false, nil
+	}
+
+	log.Infof(ctx, "purging dropped data without waiting for the GC TTL")
+	for i := range progress.Tables {
+		if progress.Tables[i].Status != jobspb.SchemaChangeGCProgress_CLEARED {
+			progress.Tables[i].Status = jobspb.SchemaChangeGCProgress_CLEARING
+		}
+	}
+	for i := range progress.Indexes {
+		if progress.Indexes[i].Status != jobspb.SchemaChangeGCProgress_CLEARED {
+			progress.Indexes[i].Status = jobspb.SchemaChangeGCProgress_CLEARING
+		}
+	}
+	persistProgress(ctx, execCfg, jobID, progress, runningStatusGC(progress))
+	if fn := execCfg.GCJobTestingKnobs.RunBeforePerformGC; fn != nil {
+		if err := fn(jobID); err != nil {
+			return false, err
+		}
+	}
+	if err := performGC(ctx, execCfg, details, progress); err != nil {
+		return false, err
+	}
+	persistProgress(ctx, execCfg, jobID, progress, sql.RunningStatusWaitingGC)
+	return isDoneGC(progress), nil
+}
+
+// purgeBlockedReason returns a description of why the data of the job cannot
+// be purged, or the empty string if it can.
+func purgeBlockedReason(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	jobID jobspb.JobID,
+	details *jobspb.SchemaChangeGCDetails,
+) (string, error) {
+	var spans []roachpb.Span
+	var ids catalog.DescriptorIDSet
+	for _, t := range details.Tables {
+		prefix := execCfg.Codec.TablePrefix(uint32(t.ID))
+		spans = append(spans, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+		ids.Add(t.ID)
+	}
+	for _, idx := range details.Indexes {
+		prefix := execCfg.Codec.IndexPrefix(uint32(details.ParentID), uint32(idx.IndexID))
+		spans = append(spans, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+		ids.Add(details.ParentID)
+	}
+
+	// Any protected timestamp covering the data prevents purging it, as the data
+	// is cleared at all timestamps.
+	for _, sp := range spans {
+		protected, err := isProtected(
+			ctx,
+			jobID,
+			math.MaxInt64,
+			execCfg,
+			execCfg.SpanConfigKVAccessor,
+			execCfg.ProtectedTimestampProvider,
+			sp,
+		)
+		if err != nil {
+			return "", err
+		}
+		if protected {
+			return fmt.Sprintf("span %s is protected by a protected timestamp", sp), nil
+		}
+	}
+
+	var blockingJob string
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		blockingJob = ""
+		_, err := jobs.RunningJobExists(ctx, jobspb.InvalidJobID, txn, func(payload *jobspb.Payload) bool {
+			if jobTargetsDescriptors(payload, ids) {
+				blockingJob = payload.Description
+				return true
+			}
+			return false
+		})
+		return err
+	}); err != nil {
+		return "", err
+	}
+	if blockingJob != "" {
+		return fmt.Sprintf("the dropped data is targeted by job %q", blockingJob), nil
+	}
+	return "", nil
+}
+
+// jobTargetsDescriptors returns whether the job is a changefeed or a backup
+// which may need to read the data of any of the given descriptors.
+func jobTargetsDescriptors(payload *jobspb.Payload, ids catalog.DescriptorIDSet) bool {
+	if cf := payload.GetChangefeed(); cf != nil {
+		for _, ts := range cf.TargetSpecifications {
+			if ids.Contains(ts.TableID) {
+				return true
+			}
+		}
+		for id := range cf.Tables {
+			if ids.Contains(id) {
+				return true
+			}
+		}
+	}
+	if b := payload.GetBackup(); b != nil {
+		if b.FullCluster {
+			return true
+		}
+		for i := range b.ResolvedTargets {
+			id, _, _, _, err := descpb.GetDescriptorMetadata(&b.ResolvedTargets[i])
+			// Be conservative if the descriptor cannot be decoded.
+			if err != nil || ids.Contains(id) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gcjob
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobTargetsDescriptors(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ids := catalog.MakeDescriptorIDSet(104)
+	for _, tc := range []struct {
+		name    string
+		details jobspb.Details
+		exp     bool
+	}{
+		{
+			name: "changefeed on dropped table",
+			details: jobspb.ChangefeedDetails{
+				TargetSpecifications: []jobspb.ChangefeedTargetSpecification{{TableID: 104}},
+			},
+			exp: true,
+		},
+		{
+			name: "changefeed on other table",
+			details: jobspb.ChangefeedDetails{
+				TargetSpecifications: []jobspb.ChangefeedTargetSpecification{{TableID: 105}},
+			},
+			exp: false,
+		},
+		{
+			name:    "full cluster backup",
+			details: jobspb.BackupDetails{FullCluster: true},
+			exp:     true,
+		},
+		{
+			name: "backup of dropped table",
+			details: jobspb.BackupDetails{
+				ResolvedTargets: []descpb.Descriptor{
+					{Union: &descpb.Descriptor_Table{Table: &descpb.TableDescriptor{ID: 104}}},
+				},
+			},
+			exp: true,
+		},
+		{
+			name: "backup of other table",
+			details: jobspb.BackupDetails{
+				ResolvedTargets: []descpb.Descriptor{
+					{Union: &descpb.Descriptor_Table{Table: &descpb.TableDescriptor{ID: 105}}},
+				},
+			},
+			exp: false,
+		},
+		{
+			name:    "other job",
+			details: jobspb.ImportDetails{},
+			exp:     false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			payload := jobspb.Payload{Details: jobspb.WrapPayloadDetails(tc.details)}
+			require.Equal(t, tc.exp, jobTargetsDescriptors(&payload, ids))
+		})
+	}
+}
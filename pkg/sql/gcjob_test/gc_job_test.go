@@ -312,6 +312,49 @@ SELECT job_id
 	)
 }
 
+// TestGCJobPurge verifies that DROP TABLE ... WITH (purge = true) and
+// TRUNCATE ... PURGE clear the dropped data without waiting for the GC TTL.
+func TestGCJobPurge(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	cs := cluster.MakeTestingClusterSettings()
+	storage.MVCCRangeTombstonesEnabledInMixedClusters.Override(ctx, &cs.SV, true)
+	params := base.TestServerArgs{Settings: cs}
+	params.Knobs.JobsTestingKnobs = jobs.NewTestingKnobsWithShortIntervals()
+	s, db, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+	tdb := sqlutils.MakeSQLRunner(db)
+
+	tdb.Exec(t, "CREATE TABLE foo (i INT PRIMARY KEY)")
+	tdb.Exec(t, "CREATE TABLE bar (i INT PRIMARY KEY, j INT, INDEX (j))")
+	tdb.Exec(t, "CREATE TABLE baz (i INT PRIMARY KEY)")
+	tdb.Exec(t, "INSERT INTO foo SELECT generate_series(1, 10)")
+	tdb.Exec(t, "INSERT INTO bar SELECT i, i FROM generate_series(1, 10) AS g(i)")
+	tdb.Exec(t, "INSERT INTO baz SELECT generate_series(1, 10)")
+
+	tdb.Exec(t, "DROP TABLE foo WITH (purge = true)")
+	tdb.Exec(t, "TRUNCATE bar PURGE")
+	tdb.Exec(t, "DROP TABLE baz")
+
+	gcJobQuery := func(column, table string) string {
+		return fmt.Sprintf(`
+SELECT %s
+  FROM [SHOW JOBS]
+ WHERE job_type = 'SCHEMA CHANGE GC' AND description LIKE '%%%s%%'`, column, table)
+	}
+	// The purged data is cleared right away.
+	tdb.CheckQueryResultsRetry(t, gcJobQuery("status", "foo"), [][]string{{"succeeded"}})
+	tdb.CheckQueryResultsRetry(t, gcJobQuery("status", "bar"), [][]string{{"succeeded"}})
+	tdb.CheckQueryResults(t, "SELECT count(*) FROM bar", [][]string{{"0"}})
+	// Without purge, the GC job waits for the GC TTL to expire.
+	tdb.CheckQueryResultsRetry(t, gcJobQuery("running_status", "baz"),
+		[][]string{{string(sql.RunningStatusWaitingForMVCCGC)}})
+
+	tdb.ExpectErr(t, `invalid storage parameter "bogus"`, "DROP TABLE bar WITH (bogus = true)")
+	tdb.ExpectErr(t, `parameter "purge" requires a Boolean value`, "DROP TABLE bar WITH (purge = 'yes')")
+}
+
 // TestGCTenant is lightweight test that tests the branching logic in Resume
 // depending on if the job is GC for tenant or tables/indexes.
 func TestGCResumer(t *testing.T) {
@@ -0,0 +1,755 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+	"github.com/linkedin/goavro/v2"
+)
+
+// The tables written by EXPORT INTO ICEBERG follow version 1 of the Iceberg
+// table format, laid out as by the Hadoop catalog: the metadata of the table
+// is kept in versioned metadata files, the latest of which is named by the
+// version hint file. Every export adds a snapshot that appends its data files
+// to those of the previous snapshot. As the external storage cannot atomically
+// swap the version hint, concurrent exports into the same table are not
+// supported.
+const (
+	icebergFormatVersion   = 1
+	icebergMetadataDir     = "metadata/"
+	icebergVersionHintFile = icebergMetadataDir + "version-hint.text"
+	icebergFileFormat      = "PARQUET"
+	// icebergBlockSize is the block size recorded for the data files, which
+	// version 1 of the format requires but readers ignore.
+	icebergBlockSize = 64 << 20
+
+	// icebergReadTimeProperty is the property of the summary of a snapshot that
+	// records the time as of which its rows were exported.
+	icebergReadTimeProperty = "crdb.read-time"
+	// icebergStartTimeProperty is the property of the summary of a snapshot
+	// appended by an incremental export that records the time since which its
+	// rows were written, which is the read time of its parent snapshot.
+	icebergStartTimeProperty = "crdb.start-time"
+)
+
+type icebergField struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Type     string `json:"type"`
+}
+
+type icebergSchema struct {
+	Type     string         `json:"type"`
+	SchemaID int            `json:"schema-id"`
+	Fields   []icebergField `json:"fields"`
+}
+
+// equivalent returns whether rows of the given schema can be appended to a
+// table of this schema.
+func (s icebergSchema) equivalent(o icebergSchema) bool {
+	if len(s.Fields) != len(o.Fields) {
+		return false
+	}
+	for i := range s.Fields {
+		if s.Fields[i].Name != o.Fields[i].Name || s.Fields[i].Type != o.Fields[i].Type ||
+			(s.Fields[i].Required && !o.Fields[i].Required) {
+			return false
+		}
+	}
+	return true
+}
+
+type icebergPartitionSpec struct {
+	SpecID int           `json:"spec-id"`
+	Fields []interface{} `json:"fields"`
+}
+
+type icebergSortOrder struct {
+	OrderID int           `json:"order-id"`
+	Fields  []interface{} `json:"fields"`
+}
+
+type icebergSnapshot struct {
+	SnapshotID       int64             `json:"snapshot-id"`
+	ParentSnapshotID *int64            `json:"parent-snapshot-id,omitempty"`
+	TimestampMs      int64             `json:"timestamp-ms"`
+	Summary          map[string]string `json:"summary"`
+	ManifestList     string            `json:"manifest-list"`
+	SchemaID         int               `json:"schema-id"`
+}
+
+type icebergSnapshotLogEntry struct {
+	TimestampMs int64 `json:"timestamp-ms"`
+	SnapshotID  int64 `json:"snapshot-id"`
+}
+
+type icebergMetadataLogEntry struct {
+	TimestampMs  int64  `json:"timestamp-ms"`
+	MetadataFile string `json:"metadata-file"`
+}
+
+// icebergTableMetadata is the content of a metadata file of an Iceberg table.
+type icebergTableMetadata struct {
+	FormatVersion      int                       `json:"format-version"`
+	TableUUID          string                    `json:"table-uuid"`
+	Location           string                    `json:"location"`
+	LastUpdatedMs      int64                     `json:"last-updated-ms"`
+	LastColumnID       int                       `json:"last-column-id"`
+	Schema             icebergSchema             `json:"schema"`
+	Schemas            []icebergSchema           `json:"schemas"`
+	CurrentSchemaID    int                       `json:"current-schema-id"`
+	PartitionSpec      []interface{}             `json:"partition-spec"`
+	PartitionSpecs     []icebergPartitionSpec    `json:"partition-specs"`
+	DefaultSpecID      int                       `json:"default-spec-id"`
+	LastPartitionID    int                       `json:"last-partition-id"`
+	Properties         map[string]string         `json:"properties"`
+	CurrentSnapshotID  int64                     `json:"current-snapshot-id"`
+	Snapshots          []icebergSnapshot         `json:"snapshots"`
+	SnapshotLog        []icebergSnapshotLogEntry `json:"snapshot-log"`
+	MetadataLog        []icebergMetadataLogEntry `json:"metadata-log"`
+	SortOrders         []icebergSortOrder        `json:"sort-orders"`
+	DefaultSortOrderID int                       `json:"default-sort-order-id"`
+}
+
+// currentSnapshot returns the current snapshot of the table, if any.
+func (m *icebergTableMetadata) currentSnapshot() *icebergSnapshot {
+	for i := range m.Snapshots {
+		if m.Snapshots[i].SnapshotID == m.CurrentSnapshotID {
+			return &m.Snapshots[i]
+		}
+	}
+	return nil
+}
+
+// snapshotReadTime returns the read time of the current snapshot of the table,
+// or an empty timestamp if the table has no snapshot.
+func (m *icebergTableMetadata) snapshotReadTime() (hlc.Timestamp, error) {
+	snapshot := m.currentSnapshot()
+	if snapshot == nil {
+		return hlc.Timestamp{}, nil
+	}
+	readTime, ok := snapshot.Summary[icebergReadTimeProperty]
+	if !ok {
+		return hlc.Timestamp{}, errors.Newf(
+			"snapshot %d of the Iceberg table was not exported by CockroachDB", snapshot.SnapshotID)
+	}
+	ts, err := hlc.ParseHLC(readTime)
+	if err != nil {
+		return hlc.Timestamp{}, errors.Wrapf(err, "parsing the read time of snapshot %d", snapshot.SnapshotID)
+	}
+	return ts, nil
+}
+
+// icebergSnapshotReadTime implements sql.IcebergSnapshotReadTime.
+func icebergSnapshotReadTime(ctx context.Context, es cloud.ExternalStorage) (hlc.Timestamp, error) {
+	_, metadata, err := readIcebergTableMetadata(ctx, es)
+	if err != nil || metadata == nil {
+		return hlc.Timestamp{}, err
+	}
+	return metadata.snapshotReadTime()
+}
+
+// icebergManifestEntrySchema is the Avro schema of the entries of a manifest,
+// each of which describes a data file added by a snapshot.
+const icebergManifestEntrySchema = `{
+  "type": "record",
+  "name": "manifest_entry",
+  "fields": [
+    {"name": "status", "type": "int", "field-id": 0},
+    {"name": "snapshot_id", "type": "long", "field-id": 1},
+    {"name": "data_file", "field-id": 2, "type": {
+      "type": "record",
+      "name": "r2",
+      "fields": [
+        {"name": "file_path", "type": "string", "field-id": 100},
+        {"name": "file_format", "type": "string", "field-id": 101},
+        {"name": "partition", "field-id": 102, "type": {"type": "record", "name": "r102", "fields": []}},
+        {"name": "record_count", "type": "long", "field-id": 103},
+        {"name": "file_size_in_bytes", "type": "long", "field-id": 104},
+        {"name": "block_size_in_bytes", "type": "long", "field-id": 105}
+      ]
+    }}
+  ]
+}`
+
+// icebergManifestFileSchema is the Avro schema of the entries of a manifest
+// list, each of which describes a manifest of a snapshot.
+const icebergManifestFileSchema = `{
+  "type": "record",
+  "name": "manifest_file",
+  "fields": [
+    {"name": "manifest_path", "type": "string", "field-id": 500},
+    {"name": "manifest_length", "type": "long", "field-id": 501},
+    {"name": "partition_spec_id", "type": "int", "field-id": 502},
+    {"name": "added_snapshot_id", "type": ["null", "long"], "default": null, "field-id": 503},
+    {"name": "added_data_files_count", "type": ["null", "int"], "default": null, "field-id": 504},
+    {"name": "existing_data_files_count", "type": ["null", "int"], "default": null, "field-id": 505},
+    {"name": "deleted_data_files_count", "type": ["null", "int"], "default": null, "field-id": 506},
+    {"name": "added_rows_count", "type": ["null", "long"], "default": null, "field-id": 512},
+    {"name": "existing_rows_count", "type": ["null", "long"], "default": null, "field-id": 513},
+    {"name": "deleted_rows_count", "type": ["null", "long"], "default": null, "field-id": 514}
+  ]
+}`
+
+// icebergManifestFileFields are the fields of icebergManifestFileSchema.
+var icebergManifestFileFields = []string{
+	"manifest_path", "manifest_length", "partition_spec_id", "added_snapshot_id",
+	"added_data_files_count", "existing_data_files_count", "deleted_data_files_count",
+	"added_rows_count", "existing_rows_count", "deleted_rows_count",
+}
+
+// icebergType returns the Iceberg type of a column of the given type, as it is
+// written in the Parquet data files by the parquetWriterProcessor. The types
+// that the Parquet exporter writes as strings are strings in Iceberg too.
+func icebergType(typ *types.T) (string, error) {
+	switch typ.Family() {
+	case types.BoolFamily:
+		return "boolean", nil
+	case types.IntFamily:
+		if typ.Oid() == oid.T_int8 {
+			return "long", nil
+		}
+		return "int", nil
+	case types.FloatFamily:
+		if typ.Oid() == oid.T_float4 {
+			return "float", nil
+		}
+		return "double", nil
+	case types.UuidFamily:
+		return "uuid", nil
+	case types.TimeFamily:
+		return "time", nil
+	case types.BytesFamily, types.BitFamily, types.GeographyFamily, types.GeometryFamily:
+		return "binary", nil
+	case types.StringFamily, types.CollatedStringFamily, types.INetFamily, types.JsonFamily,
+		types.EnumFamily, types.Box2DFamily, types.DateFamily, types.TimeTZFamily,
+		types.IntervalFamily, types.TimestampFamily, types.TimestampTZFamily:
+		return "string", nil
+	}
+	return "", errors.WithHint(
+		pgerror.Newf(pgcode.FeatureNotSupported, "type %s cannot be exported into an Iceberg table", typ.SQLString()),
+		"Cast the column to STRING.")
+}
+
+// newIcebergSchema returns the schema of a table of the exported columns.
+func newIcebergSchema(spec execinfrapb.ExportIcebergSpec) (icebergSchema, error) {
+	schema := icebergSchema{Type: "struct", Fields: make([]icebergField, len(spec.ColNames))}
+	for i, name := range spec.ColNames {
+		typ, err := icebergType(spec.ColTypes[i])
+		if err != nil {
+			return icebergSchema{}, errors.Wrapf(err, "column %s", tree.NameString(name))
+		}
+		schema.Fields[i] = icebergField{
+			ID:       i + 1,
+			Name:     name,
+			Required: !spec.ColNullability[i],
+			Type:     typ,
+		}
+	}
+	return schema, nil
+}
+
+// icebergTableLocation returns the location of the table at the given
+// destination, which is recorded in its metadata. The query parameters of the
+// destination, which may hold credentials, are left out.
+func icebergTableLocation(destination string) (string, error) {
+	uri, err := url.Parse(destination)
+	if err != nil {
+		return "", err
+	}
+	uri.RawQuery = ""
+	return strings.TrimSuffix(uri.String(), "/"), nil
+}
+
+// icebergDataFile describes a data file written by an exporter.
+type icebergDataFile struct {
+	path        string
+	recordCount int64
+	size        int64
+}
+
+type exportIcebergProcessor struct {
+	flowCtx     *execinfra.FlowCtx
+	processorID int32
+	spec        execinfrapb.ExportIcebergSpec
+	input       execinfra.RowSource
+	out         execinfra.ProcOutputHelper
+	schema      icebergSchema
+}
+
+var _ execinfra.Processor = &exportIcebergProcessor{}
+
+func newExportIcebergProcessor(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.ExportIcebergSpec,
+	input execinfra.RowSource,
+) (execinfra.Processor, error) {
+	// The schema is derived before the flow starts, so that an export of
+	// columns that cannot be represented in Iceberg fails before any data file
+	// is written.
+	schema, err := newIcebergSchema(spec)
+	if err != nil {
+		return nil, err
+	}
+	c := &exportIcebergProcessor{
+		flowCtx:     flowCtx,
+		processorID: processorID,
+		spec:        spec,
+		input:       input,
+		schema:      schema,
+	}
+	semaCtx := tree.MakeSemaContext()
+	if err := c.out.Init(ctx, &execinfrapb.PostProcessSpec{}, c.OutputTypes(), &semaCtx, flowCtx.NewEvalCtx()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (sp *exportIcebergProcessor) OutputTypes() []*types.T {
+	res := make([]*types.T, len(colinfo.ExportColumns))
+	for i := range res {
+		res[i] = colinfo.ExportColumns[i].Typ
+	}
+	return res
+}
+
+// MustBeStreaming is part of the execinfra.Processor interface.
+func (sp *exportIcebergProcessor) MustBeStreaming() bool {
+	return false
+}
+
+func (sp *exportIcebergProcessor) Run(ctx context.Context, output execinfra.RowReceiver) {
+	ctx, span := tracing.ChildSpan(ctx, "exportIceberg")
+	defer span.Finish()
+
+	err := func() error {
+		sp.input.Start(ctx)
+		input := execinfra.MakeNoMetadataRowSource(sp.input, output)
+
+		conf, err := cloud.ExternalStorageConfFromURI(sp.spec.Destination, sp.spec.User())
+		if err != nil {
+			return err
+		}
+		es, err := sp.flowCtx.Cfg.ExternalStorage(ctx, conf)
+		if err != nil {
+			return err
+		}
+		defer es.Close()
+		location, err := icebergTableLocation(sp.spec.Destination)
+		if err != nil {
+			return err
+		}
+
+		// Check that the rows can be appended to the existing table, if any,
+		// before the exporters write the data files.
+		version, metadata, err := readIcebergTableMetadata(ctx, es)
+		if err != nil {
+			return err
+		}
+		if metadata != nil && !metadata.Schema.equivalent(sp.schema) {
+			return pgerror.Newf(pgcode.DatatypeMismatch,
+				"the exported columns do not match the schema of the Iceberg table at %s", location)
+		}
+
+		// The rows of the exporters are only output once the snapshot that
+		// holds their files is committed.
+		var rows []rowenc.EncDatumRow
+		var files []icebergDataFile
+		alloc := &tree.DatumAlloc{}
+		for {
+			row, err := input.NextRow()
+			if err != nil {
+				return err
+			}
+			if row == nil {
+				break
+			}
+			for i := range row {
+				if err := row[i].EnsureDecoded(colinfo.ExportColumns[i].Typ, alloc); err != nil {
+					return err
+				}
+			}
+			files = append(files, icebergDataFile{
+				path:        location + "/" + string(tree.MustBeDString(row[0].Datum)),
+				recordCount: int64(tree.MustBeDInt(row[1].Datum)),
+				size:        int64(tree.MustBeDInt(row[2].Datum)),
+			})
+			rows = append(rows, row.Copy())
+		}
+
+		instanceID := sp.flowCtx.EvalCtx.NodeID.SQLInstanceID()
+		snapshotID := int64(builtins.GenerateUniqueInt(builtins.ProcessUniqueID(instanceID)))
+		if err := commitIcebergSnapshot(
+			ctx, es, location, version, metadata, sp.schema, snapshotID, files, sp.spec,
+		); err != nil {
+			return errors.Wrapf(err, "committing snapshot of Iceberg table at %s", location)
+		}
+
+		for _, row := range rows {
+			cs, err := sp.out.EmitRow(ctx, row, output)
+			if err != nil {
+				return err
+			}
+			if cs != execinfra.NeedMoreRows {
+				return nil
+			}
+		}
+		return nil
+	}()
+
+	execinfra.DrainAndClose(
+		ctx, output, err, func(context.Context, execinfra.RowReceiver) {} /* pushTrailingMeta */, sp.input)
+}
+
+// Resume is part of the execinfra.Processor interface.
+func (sp *exportIcebergProcessor) Resume(output execinfra.RowReceiver) {
+	panic("not implemented")
+}
+
+func icebergMetadataFile(version int) string {
+	return fmt.Sprintf("%sv%d.metadata.json", icebergMetadataDir, version)
+}
+
+// readIcebergTableMetadata returns the current version and metadata of the
+// table in the given storage, or a nil metadata if there is no table yet.
+func readIcebergTableMetadata(
+	ctx context.Context, es cloud.ExternalStorage,
+) (int, *icebergTableMetadata, error) {
+	hint, err := readIcebergFile(ctx, es, icebergVersionHintFile)
+	if err != nil {
+		if errors.Is(err, cloud.ErrFileDoesNotExist) {
+			return 0, nil, nil
+		}
+		return 0, nil, err
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(string(hint)))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "parsing the version hint of the Iceberg table")
+	}
+	raw, err := readIcebergFile(ctx, es, icebergMetadataFile(version))
+	if err != nil {
+		return 0, nil, err
+	}
+	var metadata icebergTableMetadata
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		return 0, nil, errors.Wrap(err, "parsing the metadata of the Iceberg table")
+	}
+	if metadata.FormatVersion != icebergFormatVersion {
+		return 0, nil, errors.Newf("unsupported Iceberg table format version %d", metadata.FormatVersion)
+	}
+	return version, &metadata, nil
+}
+
+func readIcebergFile(ctx context.Context, es cloud.ExternalStorage, name string) ([]byte, error) {
+	r, err := es.ReadFile(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close(ctx)
+	return ioctx.ReadAll(ctx, r)
+}
+
+// commitIcebergSnapshot writes the manifest of the given data files, a
+// manifest list of the manifests of the current snapshot of the table and of
+// that manifest, and the next version of the metadata of the table with a
+// snapshot of that list. The table is created if metadata is nil.
+func commitIcebergSnapshot(
+	ctx context.Context,
+	es cloud.ExternalStorage,
+	location string,
+	version int,
+	metadata *icebergTableMetadata,
+	schema icebergSchema,
+	snapshotID int64,
+	files []icebergDataFile,
+	spec execinfrapb.ExportIcebergSpec,
+) error {
+	now := timeutil.Now().UnixMilli()
+	if metadata == nil {
+		metadata = newIcebergTableMetadata(location, schema, now)
+	}
+	if spec.Incremental {
+		// The rows were selected by their MVCC timestamps being after the start
+		// time, so the snapshot only holds all the rows of the table if its
+		// parent holds those as of the start time.
+		parentReadTime, err := metadata.snapshotReadTime()
+		if err != nil {
+			return err
+		}
+		if parentReadTime != spec.StartTime {
+			return errors.Newf(
+				"the Iceberg table was exported into as of %s by a concurrent export since this incremental export started after %s",
+				parentReadTime.AsOfSystemTime(), spec.StartTime.AsOfSystemTime())
+		}
+	}
+	schemaJSON, err := json.Marshal(metadata.Schema)
+	if err != nil {
+		return err
+	}
+
+	// Write the manifest of the added data files.
+	var addedRecords, addedSize int64
+	entries := make([]interface{}, len(files))
+	for i, f := range files {
+		addedRecords += f.recordCount
+		addedSize += f.size
+		entries[i] = map[string]interface{}{
+			"status":      1, // ADDED
+			"snapshot_id": snapshotID,
+			"data_file": map[string]interface{}{
+				"file_path":           f.path,
+				"file_format":         icebergFileFormat,
+				"partition":           map[string]interface{}{},
+				"record_count":        f.recordCount,
+				"file_size_in_bytes":  f.size,
+				"block_size_in_bytes": int64(icebergBlockSize),
+			},
+		}
+	}
+	manifestName := fmt.Sprintf("%s%s-m0.avro", icebergMetadataDir, uuid.MakeV4())
+	manifestLength, err := writeIcebergAvroFile(ctx, es, manifestName, icebergManifestEntrySchema,
+		map[string][]byte{
+			"schema":            schemaJSON,
+			"schema-id":         []byte(strconv.Itoa(metadata.Schema.SchemaID)),
+			"partition-spec":    []byte("[]"),
+			"partition-spec-id": []byte("0"),
+			"format-version":    []byte(strconv.Itoa(icebergFormatVersion)),
+		}, entries)
+	if err != nil {
+		return err
+	}
+
+	// Write the manifest list of the snapshot, which appends the manifest to
+	// those of the current snapshot.
+	var manifests []interface{}
+	summary := map[string]string{
+		"operation":             "append",
+		"added-data-files":      strconv.Itoa(len(files)),
+		"added-records":         strconv.FormatInt(addedRecords, 10),
+		"added-files-size":      strconv.FormatInt(addedSize, 10),
+		"total-data-files":      strconv.Itoa(len(files)),
+		"total-records":         strconv.FormatInt(addedRecords, 10),
+		"total-files-size":      strconv.FormatInt(addedSize, 10),
+		icebergReadTimeProperty: spec.ReadTime.AsOfSystemTime(),
+	}
+	if spec.Incremental && !spec.StartTime.IsEmpty() {
+		summary[icebergStartTimeProperty] = spec.StartTime.AsOfSystemTime()
+	}
+	var parentID *int64
+	if parent := metadata.currentSnapshot(); parent != nil {
+		parentID = &parent.SnapshotID
+		manifests, err = readIcebergManifestList(ctx, es, location, parent.ManifestList)
+		if err != nil {
+			return err
+		}
+		for _, total := range []string{"total-data-files", "total-records", "total-files-size"} {
+			prev, err := strconv.ParseInt(parent.Summary[total], 10, 64)
+			if err != nil {
+				continue
+			}
+			added, _ := strconv.ParseInt(summary[total], 10, 64)
+			summary[total] = strconv.FormatInt(prev+added, 10)
+		}
+	}
+	manifests = append(manifests, map[string]interface{}{
+		"manifest_path":             location + "/" + manifestName,
+		"manifest_length":           manifestLength,
+		"partition_spec_id":         0,
+		"added_snapshot_id":         goavro.Union("long", snapshotID),
+		"added_data_files_count":    goavro.Union("int", len(files)),
+		"existing_data_files_count": goavro.Union("int", 0),
+		"deleted_data_files_count":  goavro.Union("int", 0),
+		"added_rows_count":          goavro.Union("long", addedRecords),
+		"existing_rows_count":       goavro.Union("long", 0),
+		"deleted_rows_count":        goavro.Union("long", 0),
+	})
+	manifestListName := fmt.Sprintf("%ssnap-%d-1-%s.avro", icebergMetadataDir, snapshotID, uuid.MakeV4())
+	manifestListMeta := map[string][]byte{
+		"snapshot-id":    []byte(strconv.FormatInt(snapshotID, 10)),
+		"format-version": []byte(strconv.Itoa(icebergFormatVersion)),
+	}
+	if parentID != nil {
+		manifestListMeta["parent-snapshot-id"] = []byte(strconv.FormatInt(*parentID, 10))
+	}
+	if _, err := writeIcebergAvroFile(
+		ctx, es, manifestListName, icebergManifestFileSchema, manifestListMeta, manifests,
+	); err != nil {
+		return err
+	}
+
+	// Write the next version of the metadata, and point the version hint to it.
+	if version > 0 {
+		metadata.MetadataLog = append(metadata.MetadataLog, icebergMetadataLogEntry{
+			TimestampMs:  metadata.LastUpdatedMs,
+			MetadataFile: location + "/" + icebergMetadataFile(version),
+		})
+	}
+	metadata.Snapshots = append(metadata.Snapshots, icebergSnapshot{
+		SnapshotID:       snapshotID,
+		ParentSnapshotID: parentID,
+		TimestampMs:      now,
+		Summary:          summary,
+		ManifestList:     location + "/" + manifestListName,
+		SchemaID:         metadata.Schema.SchemaID,
+	})
+	metadata.SnapshotLog = append(metadata.SnapshotLog, icebergSnapshotLogEntry{
+		TimestampMs: now,
+		SnapshotID:  snapshotID,
+	})
+	metadata.CurrentSnapshotID = snapshotID
+	metadata.LastUpdatedMs = now
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	nextFile := icebergMetadataFile(version + 1)
+	if _, err := readIcebergFile(ctx, es, nextFile); err == nil {
+		return errors.Newf("%s was written by a concurrent export", nextFile)
+	} else if !errors.Is(err, cloud.ErrFileDoesNotExist) {
+		return err
+	}
+	if err := cloud.WriteFile(ctx, es, nextFile, bytes.NewReader(raw)); err != nil {
+		return err
+	}
+	return cloud.WriteFile(ctx, es, icebergVersionHintFile,
+		strings.NewReader(strconv.Itoa(version+1)))
+}
+
+// newIcebergTableMetadata returns the metadata of a new table of the given
+// schema, which has no snapshot yet.
+func newIcebergTableMetadata(location string, schema icebergSchema, now int64) *icebergTableMetadata {
+	// The data files do not record the IDs of their columns, so the table maps
+	// them by name.
+	type nameMapping struct {
+		FieldID int      `json:"field-id"`
+		Names   []string `json:"names"`
+	}
+	mapping := make([]nameMapping, len(schema.Fields))
+	for i, f := range schema.Fields {
+		mapping[i] = nameMapping{FieldID: f.ID, Names: []string{f.Name}}
+	}
+	// The mapping is only made of names and integers, which always marshal.
+	rawMapping, _ := json.Marshal(mapping)
+	return &icebergTableMetadata{
+		FormatVersion:     icebergFormatVersion,
+		TableUUID:         uuid.MakeV4().String(),
+		Location:          location,
+		LastUpdatedMs:     now,
+		LastColumnID:      len(schema.Fields),
+		Schema:            schema,
+		Schemas:           []icebergSchema{schema},
+		PartitionSpec:     []interface{}{},
+		PartitionSpecs:    []icebergPartitionSpec{{SpecID: 0, Fields: []interface{}{}}},
+		LastPartitionID:   999,
+		CurrentSnapshotID: -1,
+		Properties: map[string]string{
+			"schema.name-mapping.default": string(rawMapping),
+		},
+		SortOrders: []icebergSortOrder{{OrderID: 0, Fields: []interface{}{}}},
+	}
+}
+
+// readIcebergManifestList returns the entries of the given manifest list of
+// the table, restricted to the fields of icebergManifestFileSchema.
+func readIcebergManifestList(
+	ctx context.Context, es cloud.ExternalStorage, location string, manifestList string,
+) ([]interface{}, error) {
+	name := strings.TrimPrefix(manifestList, location+"/")
+	if name == manifestList {
+		return nil, errors.Newf("manifest list %s is not in the location of the table", manifestList)
+	}
+	raw, err := readIcebergFile(ctx, es, name)
+	if err != nil {
+		return nil, err
+	}
+	ocf, err := goavro.NewOCFReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	var manifests []interface{}
+	for ocf.Scan() {
+		datum, err := ocf.Read()
+		if err != nil {
+			return nil, err
+		}
+		record, ok := datum.(map[string]interface{})
+		if !ok {
+			return nil, errors.Newf("unexpected entry in manifest list %s", manifestList)
+		}
+		manifest := make(map[string]interface{}, len(icebergManifestFileFields))
+		for _, field := range icebergManifestFileFields {
+			if v, ok := record[field]; ok {
+				manifest[field] = v
+			}
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, ocf.Err()
+}
+
+// writeIcebergAvroFile writes the given records to an Avro file of the given
+// schema and returns the size of the file.
+func writeIcebergAvroFile(
+	ctx context.Context,
+	es cloud.ExternalStorage,
+	name string,
+	schema string,
+	meta map[string][]byte,
+	records []interface{},
+) (int64, error) {
+	var buf bytes.Buffer
+	ocf, err := goavro.NewOCFWriter(goavro.OCFConfig{W: &buf, Schema: schema, MetaData: meta})
+	if err != nil {
+		return 0, err
+	}
+	if err := ocf.Append(records); err != nil {
+		return 0, err
+	}
+	size := int64(buf.Len())
+	if err := cloud.WriteFile(ctx, es, name, &buf); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+func init() {
+	rowexec.NewExportIcebergProcessor = newExportIcebergProcessor
+	sql.IcebergSnapshotReadTime = icebergSnapshotReadTime
+}
@@ -0,0 +1,167 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// icebergMetadata holds the parts of the metadata of an Iceberg table that the
+// tests check.
+type icebergMetadata struct {
+	Location          string `json:"location"`
+	CurrentSnapshotID int64  `json:"current-snapshot-id"`
+	Schema            struct {
+		Fields []struct {
+			Name     string `json:"name"`
+			Type     string `json:"type"`
+			Required bool   `json:"required"`
+		} `json:"fields"`
+	} `json:"schema"`
+	Snapshots []struct {
+		SnapshotID   int64             `json:"snapshot-id"`
+		Summary      map[string]string `json:"summary"`
+		ManifestList string            `json:"manifest-list"`
+	} `json:"snapshots"`
+}
+
+// readIcebergMetadata reads the current metadata of the Iceberg table in dir.
+func readIcebergMetadata(t *testing.T, dir string) icebergMetadata {
+	hint, err := os.ReadFile(filepath.Join(dir, "metadata", "version-hint.text"))
+	require.NoError(t, err)
+	raw, err := os.ReadFile(filepath.Join(dir, "metadata", "v"+string(hint)+".metadata.json"))
+	require.NoError(t, err)
+	var metadata icebergMetadata
+	require.NoError(t, json.Unmarshal(raw, &metadata))
+	return metadata
+}
+
+// readAvroRecords reads the records of the Avro file of the table in dir at
+// the given location.
+func readAvroRecords(
+	t *testing.T, dir string, metadata icebergMetadata, location string,
+) []map[string]interface{} {
+	f, err := os.Open(filepath.Join(dir, strings.TrimPrefix(location, metadata.Location+"/")))
+	require.NoError(t, err)
+	defer f.Close()
+	ocf, err := goavro.NewOCFReader(f)
+	require.NoError(t, err)
+	var records []map[string]interface{}
+	for ocf.Scan() {
+		datum, err := ocf.Read()
+		require.NoError(t, err)
+		records = append(records, datum.(map[string]interface{}))
+	}
+	require.NoError(t, ocf.Err())
+	return records
+}
+
+func TestExportIceberg(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v STRING, ts TIMESTAMPTZ)`)
+	sqlDB.Exec(t, `INSERT INTO t VALUES (1, 'a', now()), (2, NULL, now())`)
+	tableDir := filepath.Join(dir, "ice")
+
+	var readTime string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&readTime)
+	rows := sqlDB.QueryStr(t,
+		`EXPORT INTO ICEBERG 'nodelocal://1/ice' FROM SELECT * FROM t AS OF SYSTEM TIME `+readTime)
+	require.Len(t, rows, 1)
+	require.True(t, strings.HasPrefix(rows[0][0], "data/export"), rows[0][0])
+	require.Equal(t, "2", rows[0][1])
+
+	metadata := readIcebergMetadata(t, tableDir)
+	require.Equal(t, "nodelocal://1/ice", metadata.Location)
+	require.Len(t, metadata.Schema.Fields, 3)
+	require.Equal(t, "k", metadata.Schema.Fields[0].Name)
+	require.Equal(t, "long", metadata.Schema.Fields[0].Type)
+	require.True(t, metadata.Schema.Fields[0].Required)
+	require.Equal(t, "string", metadata.Schema.Fields[1].Type)
+	require.False(t, metadata.Schema.Fields[1].Required)
+	require.Equal(t, "string", metadata.Schema.Fields[2].Type)
+	require.Len(t, metadata.Snapshots, 1)
+	snapshot := metadata.Snapshots[0]
+	require.Equal(t, metadata.CurrentSnapshotID, snapshot.SnapshotID)
+	require.Equal(t, "append", snapshot.Summary["operation"])
+	require.Equal(t, "2", snapshot.Summary["added-records"])
+	require.Equal(t, readTime, snapshot.Summary["crdb.read-time"])
+
+	// The manifest of the snapshot lists the data file.
+	manifests := readAvroRecords(t, tableDir, metadata, snapshot.ManifestList)
+	require.Len(t, manifests, 1)
+	entries := readAvroRecords(t, tableDir, metadata, manifests[0]["manifest_path"].(string))
+	require.Len(t, entries, 1)
+	dataFile := entries[0]["data_file"].(map[string]interface{})
+	require.Equal(t, "nodelocal://1/ice/"+rows[0][0], dataFile["file_path"])
+	require.Equal(t, int64(2), dataFile["record_count"])
+	_, err := os.Stat(filepath.Join(tableDir, rows[0][0]))
+	require.NoError(t, err)
+
+	// An incremental export of the rows written since the previous one appends
+	// a snapshot.
+	sqlDB.Exec(t, `INSERT INTO t VALUES (3, 'c', now())`)
+	sqlDB.Exec(t, `EXPORT INTO ICEBERG 'nodelocal://1/ice' FROM SELECT * FROM t
+WHERE crdb_internal_mvcc_timestamp > $1`, readTime)
+	metadata = readIcebergMetadata(t, tableDir)
+	require.Len(t, metadata.Snapshots, 2)
+	snapshot = metadata.Snapshots[1]
+	require.Equal(t, metadata.CurrentSnapshotID, snapshot.SnapshotID)
+	require.Equal(t, "1", snapshot.Summary["added-records"])
+	require.Equal(t, "3", snapshot.Summary["total-records"])
+	require.Len(t, readAvroRecords(t, tableDir, metadata, snapshot.ManifestList), 2)
+
+	// The incremental option selects the rows written since the read time of
+	// the current snapshot itself.
+	startTime := snapshot.Summary["crdb.read-time"]
+	sqlDB.Exec(t, `UPDATE t SET v = 'b' WHERE k = 2`)
+	sqlDB.Exec(t, `EXPORT INTO ICEBERG 'nodelocal://1/ice' WITH incremental FROM SELECT k, v, ts FROM t`)
+	metadata = readIcebergMetadata(t, tableDir)
+	require.Len(t, metadata.Snapshots, 3)
+	snapshot = metadata.Snapshots[2]
+	require.Equal(t, "1", snapshot.Summary["added-records"])
+	require.Equal(t, "4", snapshot.Summary["total-records"])
+	require.Equal(t, startTime, snapshot.Summary["crdb.start-time"])
+	sqlDB.ExpectErr(t, "incremental exports are only supported for queries over a single table",
+		`EXPORT INTO ICEBERG 'nodelocal://1/ice' WITH incremental FROM SELECT count(*) FROM t`)
+	sqlDB.ExpectErr(t, "incremental is only supported for exports into Iceberg tables",
+		`EXPORT INTO CSV 'nodelocal://1/csv' WITH incremental FROM SELECT * FROM t`)
+
+	// The exported columns must match the schema of the existing table.
+	sqlDB.ExpectErr(t, "the exported columns do not match the schema",
+		`EXPORT INTO ICEBERG 'nodelocal://1/ice' FROM SELECT k, v FROM t`)
+	sqlDB.ExpectErr(t, "type DECIMAL cannot be exported into an Iceberg table",
+		`EXPORT INTO ICEBERG 'nodelocal://1/dec' FROM SELECT 1.5::DECIMAL AS d`)
+	require.Len(t, readIcebergMetadata(t, tableDir).Snapshots, 3)
+}
@@ -316,7 +316,7 @@ func (r *importResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		return err
 	}
 
-	if err := r.checkVirtualConstraints(ctx, p.ExecCfg(), r.job, p.User()); err != nil {
+	if err := r.validateConstraints(ctx, p.ExecCfg(), r.job, p.User()); err != nil {
 		return err
 	}
 
@@ -957,33 +957,9 @@ func (r *importResumer) publishTables(
 			}
 			newTableDesc.SetPublic()
 
-			if !tbl.IsNew {
-				// NB: This is not using AllNonDropIndexes or directly mutating the
-				// constraints returned by the other usual helpers because we need to
-				// replace the `OutboundFKs` and `Checks` slices of newTableDesc with copies
-				// that we can mutate. We need to do that because newTableDesc is a shallow
-				// copy of tbl.Desc that we'll be asserting is the current version when we
-				// CPut below.
-				//
-				// Set FK constraints to unvalidated before publishing the table imported
-				// into.
-				newTableDesc.OutboundFKs = make([]descpb.ForeignKeyConstraint, len(newTableDesc.OutboundFKs))
-				copy(newTableDesc.OutboundFKs, tbl.Desc.OutboundFKs)
-				for i := range newTableDesc.OutboundFKs {
-					newTableDesc.OutboundFKs[i].Validity = descpb.ConstraintValidity_Unvalidated
-				}
-
-				// Set CHECK constraints to unvalidated before publishing the table imported into.
-				for _, c := range newTableDesc.CheckConstraints() {
-					// We only "unvalidate" constraints that are not hash-sharded column
-					// check constraints.
-					if !c.IsHashShardingConstraint() {
-						c.CheckDesc().Validity = descpb.ConstraintValidity_Unvalidated
-					}
-				}
-			}
+			// The FK and CHECK constraints of tables imported into were re-validated
+			// by validateConstraints, so they remain validated once published.
 			newTableDesc.FinalizeImport()
-			// TODO(dt): re-validate any FKs?
 			if err := descsCol.WriteDescToBatch(
 				ctx, false /* kvTrace */, newTableDesc, b,
 			); err != nil {
@@ -1107,16 +1083,22 @@ func (r *importResumer) publishSchemas(ctx context.Context, execCfg *sql.Executo
 	})
 }
 
-// checkVirtualConstraints checks constraints that are enforced via runtime
-// checks, such as uniqueness checks that are not directly backed by an index.
-func (r *importResumer) checkVirtualConstraints(
+// validateConstraints checks the constraints of the tables imported into that
+// are not enforced while ingesting: uniqueness checks that are not directly
+// backed by an index, as well as the foreign key and CHECK constraints of
+// pre-existing tables. The validation queries are planned by DistSQL and so
+// scan the imported data where it lives. A violation fails the job, which then
+// rolls back the imported data.
+func (r *importResumer) validateConstraints(
 	ctx context.Context, execCfg *sql.ExecutorConfig, job *jobs.Job, user username.SQLUsername,
 ) error {
 	for _, tbl := range job.Details().(jobspb.ImportDetails).Tables {
 		desc := tabledesc.NewBuilder(tbl.Desc).BuildExistingMutableTable()
 		desc.SetPublic()
 
-		if sql.HasVirtualUniqueConstraints(desc) {
+		validateFKsAndChecks := !tbl.IsNew &&
+			(len(desc.OutboundForeignKeys()) > 0 || len(desc.CheckConstraints()) > 0)
+		if validateFKsAndChecks || sql.HasVirtualUniqueConstraints(desc) {
 			if err := job.NoTxn().RunningStatus(ctx, func(_ context.Context, _ jobspb.Details) (jobs.RunningStatus, error) {
 				return jobs.RunningStatus(fmt.Sprintf("re-validating %s", desc.GetName())), nil
 			}); err != nil {
@@ -1126,7 +1108,13 @@ func (r *importResumer) checkVirtualConstraints(
 
 		if err := execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
 			txn.Descriptors().AddSyntheticDescriptor(desc)
-			return sql.RevalidateUniqueConstraintsInTable(ctx, txn, user, desc)
+			if err := sql.RevalidateUniqueConstraintsInTable(ctx, txn, user, desc); err != nil {
+				return err
+			}
+			if !validateFKsAndChecks {
+				return nil
+			}
+			return sql.RevalidateForeignKeysAndChecksInTable(ctx, txn, &execCfg.Settings.SV, desc)
 		}); err != nil {
 			return err
 		}
@@ -86,7 +86,7 @@ const (
 
 	optMaxRowSize = "max_row_size"
 
-	// Turn on strict validation when importing avro records.
+	// Turn on strict validation when importing avro, parquet or ndjson records.
 	avroStrict = "strict_validation"
 	// Default input format is assumed to be OCF (object container file).
 	// This default can be changed by specified either of these options.
@@ -174,6 +174,10 @@ var avroAllowedOptions = makeStringSet(
 	avroRecordsSeparatedBy, avroSchema, avroSchemaURI, optMaxRowSize, csvRowLimit,
 )
 
+var parquetAllowedOptions = makeStringSet(avroStrict, csvRowLimit)
+
+var ndjsonAllowedOptions = makeStringSet(avroStrict, optMaxRowSize, csvRowLimit)
+
 var csvAllowedOptions = makeStringSet(
 	csvDelimiter, csvComment, csvNullIf, csvSkip, csvStrictQuotes, csvRowLimit, csvAllowQuotedNulls,
 )
@@ -200,6 +204,8 @@ var allowedIntoFormats = map[string]struct{}{
 	"AVRO":      {},
 	"DELIMITED": {},
 	"PGCOPY":    {},
+	"PARQUET":   {},
+	"NDJSON":    {},
 }
 
 // featureImportEnabled is used to enable and disable the IMPORT feature.
@@ -750,6 +756,20 @@ func importPlanHook(
 			if err != nil {
 				return err
 			}
+		case "PARQUET":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, parquetAllowedOptions); err != nil {
+				return err
+			}
+			if err := parseParquetOptions(opts, &format); err != nil {
+				return err
+			}
+		case "NDJSON":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, ndjsonAllowedOptions); err != nil {
+				return err
+			}
+			if err := parseNDJSONOptions(opts, &format); err != nil {
+				return err
+			}
 		default:
 			return unimplemented.Newf("import.format", "unsupported import format: %q", importStmt.FileFormat)
 		}
@@ -914,6 +934,19 @@ func importPlanHook(
 
 		telemetry.CountBucketed("import.files", int64(len(files)))
 
+		// Split large parquet files into several inputs, so that their row groups
+		// are imported by different processors. The row limit applies to entire
+		// files, so files aren't split when it's set.
+		var parquetRowGroups []roachpb.ParquetRowGroupRange
+		if format.Format == roachpb.IOFileFormat_Parquet && format.Parquet.RowLimit == 0 {
+			inputs, rowGroups, err := splitParquetFiles(
+				ctx, files, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, p.User())
+			if err != nil {
+				return err
+			}
+			files, parquetRowGroups = inputs, rowGroups
+		}
+
 		// Record telemetry for userfile being used as the import target.
 		for _, file := range files {
 			uri, err := url.Parse(file)
@@ -951,6 +984,7 @@ func importPlanHook(
 			ParseBundleSchema:     importStmt.Bundle,
 			DefaultIntSize:        p.SessionData().DefaultIntSize,
 			DatabasePrimaryRegion: databasePrimaryRegion,
+			ParquetRowGroups:      parquetRowGroups,
 		}
 
 		jr := jobs.Record{
@@ -1121,6 +1155,57 @@ func parseAvroOptions(
 	return nil
 }
 
+// parseRowLimitOption returns the value of the row_limit option, or 0 if it is
+// not set.
+func parseRowLimitOption(opts map[string]string) (int64, error) {
+	override, ok := opts[csvRowLimit]
+	if !ok {
+		return 0, nil
+	}
+	rowLimit, err := strconv.Atoi(override)
+	if err != nil {
+		return 0, pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+	}
+	if rowLimit <= 0 {
+		return 0, pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+	}
+	return int64(rowLimit), nil
+}
+
+func parseParquetOptions(opts map[string]string, format *roachpb.IOFileFormat) error {
+	format.Format = roachpb.IOFileFormat_Parquet
+	_, format.Parquet.StrictMode = opts[avroStrict]
+	rowLimit, err := parseRowLimitOption(opts)
+	if err != nil {
+		return err
+	}
+	format.Parquet.RowLimit = rowLimit
+	return nil
+}
+
+func parseNDJSONOptions(opts map[string]string, format *roachpb.IOFileFormat) error {
+	format.Format = roachpb.IOFileFormat_NDJSON
+	_, format.NDJSON.StrictMode = opts[avroStrict]
+	rowLimit, err := parseRowLimitOption(opts)
+	if err != nil {
+		return err
+	}
+	format.NDJSON.RowLimit = rowLimit
+
+	format.NDJSON.MaxRowSize = int32(defaultScanBuffer)
+	if override, ok := opts[optMaxRowSize]; ok {
+		sz, err := humanizeutil.ParseBytes(override)
+		if err != nil {
+			return err
+		}
+		if sz < 1 || sz > math.MaxInt32 {
+			return errors.Errorf("%s out of range: %d", override, sz)
+		}
+		format.NDJSON.MaxRowSize = int32(sz)
+	}
+	return nil
+}
+
 type loggerKind int
 
 const (
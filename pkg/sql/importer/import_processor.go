@@ -36,8 +36,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -262,6 +262,7 @@ func makeInputConverter(
 	kvCh chan row.KVBatch,
 	seqChunkProvider *row.SeqChunkProvider,
 	db *kv.DB,
+	memMon *mon.BytesMonitor,
 ) (inputConverter, error) {
 	injectTimeIntoEvalCtx(evalCtx, spec.WalltimeNanos)
 	var singleTable catalog.TableDescriptor
@@ -282,19 +283,22 @@ func makeInputConverter(
 
 	if singleTable != nil {
 		// If we're using a format like CSV where data columns are not "named", and
-		// therefore cannot be mapped to schema columns, then require the user to
-		// use IMPORT INTO.
-		//
-		// We could potentially do something smarter here and check that only a
-		// suffix of the columns are computed, and then expect the data file to have
-		// #(visible columns) - #(computed columns).
+		// no target columns were specified, then the data files are expected to
+		// have a value for every visible column that is not computed. Computed
+		// column values are then evaluated by the row converter.
 		if len(singleTableTargetCols) == 0 && !formatHasNamedColumns(spec.Format.Format) {
+			var hasComputed bool
+			var nonComputed tree.NameList
 			for _, col := range singleTable.VisibleColumns() {
 				if col.IsComputed() {
-					return nil, unimplemented.NewWithIssueDetail(56002, "import.computed",
-						"to use computed columns, use IMPORT INTO")
+					hasComputed = true
+				} else {
+					nonComputed = append(nonComputed, col.ColName())
 				}
 			}
+			if hasComputed {
+				singleTableTargetCols = nonComputed
+			}
 		}
 	}
 
@@ -338,6 +342,14 @@ func makeInputConverter(
 		return newAvroInputReader(
 			semaCtx, kvCh, singleTable, spec.Format.Avro, spec.WalltimeNanos,
 			readerParallelism, evalCtx, db)
+	case roachpb.IOFileFormat_Parquet:
+		return newParquetInputReader(
+			semaCtx, kvCh, singleTable, spec.Format.Parquet, spec.ParquetRowGroups, spec.WalltimeNanos,
+			readerParallelism, evalCtx, db, memMon)
+	case roachpb.IOFileFormat_NDJSON:
+		return newNDJSONInputReader(
+			semaCtx, kvCh, singleTable, spec.Format.NDJSON, spec.WalltimeNanos,
+			readerParallelism, evalCtx, db)
 	default:
 		return nil, errors.Errorf(
 			"Requested IMPORT format (%d) not supported by this node", spec.Format.Format)
@@ -325,6 +325,7 @@ func makeImportReaderSpecs(
 				WalltimeNanos:         walltime,
 				Uri:                   make(map[int32]string),
 				ResumePos:             make(map[int32]int64),
+				ParquetRowGroups:      make(map[int32]roachpb.ParquetRowGroupRange),
 				UserProto:             user.EncodeProto(),
 				DatabasePrimaryRegion: details.DatabasePrimaryRegion,
 				InitialSplits:         int32(len(sqlInstanceIDs)),
@@ -336,6 +337,9 @@ func makeImportReaderSpecs(
 		if importProgress.ResumePos != nil {
 			inputSpecs[n].ResumePos[int32(i)] = importProgress.ResumePos[int32(i)]
 		}
+		if len(details.ParquetRowGroups) == len(from) {
+			inputSpecs[n].ParquetRowGroups[int32(i)] = details.ParquetRowGroups[i]
+		}
 	}
 
 	for i := range inputSpecs {
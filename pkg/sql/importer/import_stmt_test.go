@@ -81,6 +81,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquetschema"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/linkedin/goavro/v2"
@@ -1191,9 +1193,12 @@ END;
 			typ:  "PGDUMP",
 			data: `
 CREATE TABLE t (a INT8, b INT8);
+INSERT INTO t (a, b) VALUES (1, 5), (2, 15), (3, 25);
 CREATE INDEX i ON t USING btree (a) WHERE (b > 10);
 			`,
-			err: "cannot import a table with partial indexes",
+			query: map[string][][]string{
+				`SELECT a FROM t@i WHERE b > 10 ORDER BY a`: {{"2"}, {"3"}},
+			},
 		},
 		{
 			name: "user defined type",
@@ -3845,31 +3850,38 @@ func TestImportIntoCSV(t *testing.T) {
 		sqlDB.CheckQueryResults(t, `SELECT * FROM t`, preCollisionData)
 	})
 
-	// Tests that IMPORT INTO invalidates FK and CHECK constraints.
-	t.Run("import-into-invalidate-constraints", func(t *testing.T) {
+	// Tests that IMPORT INTO validates FK and CHECK constraints after ingesting.
+	t.Run("import-into-validate-constraints", func(t *testing.T) {
 		sqlDB.Exec(t, `CREATE TABLE ref (b STRING PRIMARY KEY)`)
 		defer sqlDB.Exec(t, `DROP TABLE ref`)
 		sqlDB.Exec(t, `CREATE TABLE t (a INT CHECK (a >= 0), b STRING, CONSTRAINT fk_ref FOREIGN KEY (b) REFERENCES ref)`)
 		defer dropTableAfterJobComplete(t, "t")
 
-		var checkValidated, fkValidated bool
-		sqlDB.QueryRow(t, `SELECT validated from [SHOW CONSTRAINT FROM t] WHERE constraint_name = 'check_a'`).Scan(&checkValidated)
-		sqlDB.QueryRow(t, `SELECT validated from [SHOW CONSTRAINT FROM t] WHERE constraint_name = 'fk_ref'`).Scan(&fkValidated)
-
-		// Prior to import all constraints should be validated.
-		if !checkValidated || !fkValidated {
-			t.Fatal("Constraints not validated on creation.\n")
+		checkConstraintsValidated := func() {
+			t.Helper()
+			sqlDB.CheckQueryResults(t,
+				`SELECT constraint_name, validated FROM [SHOW CONSTRAINTS FROM t] WHERE constraint_name IN ('check_a', 'fk_ref') ORDER BY 1`,
+				[][]string{{"check_a", "true"}, {"fk_ref", "true"}},
+			)
 		}
+		checkConstraintsValidated()
 
-		sqlDB.Exec(t, fmt.Sprintf(`IMPORT INTO t (a, b) CSV DATA (%s)`, testFiles.files[0]))
-
-		sqlDB.QueryRow(t, `SELECT validated from [SHOW CONSTRAINT FROM t] WHERE constraint_name = 'check_a'`).Scan(&checkValidated)
-		sqlDB.QueryRow(t, `SELECT validated from [SHOW CONSTRAINT FROM t] WHERE constraint_name = 'fk_ref'`).Scan(&fkValidated)
+		// The referenced rows do not exist yet, so the import is rolled back.
+		sqlDB.ExpectErr(t, `foreign key violation`,
+			fmt.Sprintf(`IMPORT INTO t (a, b) CSV DATA (%s)`, testFiles.files[0]))
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM t`, [][]string{{"0"}})
+		checkConstraintsValidated()
 
-		// Following an import the constraints should be unvalidated.
-		if checkValidated || fkValidated {
-			t.Fatal("FK and CHECK constraints not unvalidated after IMPORT INTO\n")
-		}
+		sqlDB.Exec(t, `INSERT INTO ref SELECT chr(i) FROM generate_series(65, 90) AS g(i)`)
+		sqlDB.Exec(t, fmt.Sprintf(`IMPORT INTO t (a, b) CSV DATA (%s)`, testFiles.files[0]))
+		checkConstraintsValidated()
+
+		// A row violating the CHECK constraint rolls back the import as well.
+		sqlDB.Exec(t, `DELETE FROM t WHERE a >= 10`)
+		sqlDB.Exec(t, `ALTER TABLE t ADD CONSTRAINT check_small CHECK (a < 10)`)
+		sqlDB.ExpectErr(t, `validation of CHECK "a < 10:::INT8" failed`,
+			fmt.Sprintf(`IMPORT INTO t (a, b) CSV DATA (%s)`, testFiles.files[1]))
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM t WHERE a >= 10`, [][]string{{"0"}})
 	})
 
 	// Test userfile IMPORT INTO CSV.
@@ -4927,6 +4939,14 @@ INSERT INTO users (a, b) VALUES (1, 2), (3, 4);
 			format:          "CSV",
 			expectedResults: [][]string{{"35", "23", "58"}, {"67", "10", "77"}},
 		},
+		{
+			into:            true,
+			name:            "no-target-cols",
+			data:            "35,23\n67,10",
+			create:          "a INT, b INT, c INT AS (a + b) STORED",
+			format:          "CSV",
+			expectedResults: [][]string{{"35", "23", "58"}, {"67", "10", "77"}},
+		},
 		{
 			into:          true,
 			name:          "cannot-be-targeted",
@@ -4996,8 +5016,12 @@ INSERT INTO users (a, b) VALUES (1, 2), (3, 4);
 			var importStmt string
 			if test.into {
 				sqlDB.Exec(t, fmt.Sprintf(`CREATE TABLE users (%s)`, test.create))
-				importStmt = fmt.Sprintf(`IMPORT INTO users (%s) %s DATA (%q)`,
-					test.targetCols, test.format, srv.URL)
+				var targetCols string
+				if test.targetCols != "" {
+					targetCols = fmt.Sprintf("(%s) ", test.targetCols)
+				}
+				importStmt = fmt.Sprintf(`IMPORT INTO users %s%s DATA (%q)`,
+					targetCols, test.format, srv.URL)
 			} else {
 				importStmt = fmt.Sprintf(`IMPORT %s (%q)`, test.format, srv.URL)
 			}
@@ -6782,6 +6806,146 @@ func TestImportAvro(t *testing.T) {
 	})
 }
 
+func TestImportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	t.Run("export-roundtrip", func(t *testing.T) {
+		const schema = `(
+	k INT PRIMARY KEY, s STRING, f FLOAT, b BOOL, d DECIMAL, dt DATE, ts TIMESTAMPTZ,
+	iv INTERVAL, u UUID, by BYTES, j JSONB, ia INT[], sa STRING[]
+)`
+		sqlDB.Exec(t, `CREATE TABLE src `+schema)
+		sqlDB.Exec(t, `CREATE TABLE dst `+schema)
+		sqlDB.Exec(t, `INSERT INTO src VALUES
+	(1, 'a', 1.5, true, 1.25, '2023-01-02', '2023-01-02 03:04:05.678+00', '1 day 2 hours',
+	 'a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11', b'\x01\x02', '{"a": [1, 2]}', ARRAY[1, 2], ARRAY['x', 'y']),
+	(2, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL),
+	(3, 'c', -2.5, false, -10.001, '1999-12-31', '1999-12-31 23:59:59+00', '-3 minutes',
+	 'b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12', b'', '"s"', ARRAY[]::INT[], ARRAY['z'])`)
+		sqlDB.Exec(t, `EXPORT INTO PARQUET 'nodelocal://1/roundtrip' WITH chunk_rows = '2' FROM SELECT * FROM src`)
+
+		sqlDB.Exec(t, `IMPORT INTO dst PARQUET DATA ('nodelocal://1/roundtrip/*')`)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM dst ORDER BY k`,
+			sqlDB.QueryStr(t, `SELECT * FROM src ORDER BY k`))
+	})
+
+	t.Run("row-groups", func(t *testing.T) {
+		// The file has columns whose names differ from the columns of the table
+		// in case, nested columns, and several row groups.
+		sd, err := parquetschema.ParseSchemaDefinition(`message test {
+	required int64 Id;
+	optional binary name (STRING);
+	optional group tags (LIST) {
+		repeated group list {
+			optional binary element (STRING);
+		}
+	}
+	optional group attrs {
+		optional int32 a;
+		optional binary b (STRING);
+	}
+	optional int32 day (DATE);
+	optional binary extra (STRING);
+}`)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		w := goparquet.NewFileWriter(&buf, goparquet.WithSchemaDefinition(sd))
+		const numRows = 100
+		for i := 0; i < numRows; i++ {
+			r := map[string]interface{}{"Id": int64(i)}
+			if i%2 == 0 {
+				r["name"] = []byte(fmt.Sprintf("name-%d", i))
+				r["tags"] = map[string]interface{}{"list": []map[string]interface{}{
+					{"element": []byte("x")}, {"element": []byte(fmt.Sprint(i))},
+				}}
+				r["attrs"] = map[string]interface{}{"a": int32(i), "b": []byte("y")}
+				r["day"] = int32(19000 + i)
+			}
+			require.NoError(t, w.AddData(r))
+			if i%7 == 6 {
+				require.NoError(t, w.FlushRowGroup())
+			}
+		}
+		require.NoError(t, w.Close())
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "groups.parquet"), buf.Bytes(), 0644))
+
+		sqlDB.Exec(t, `CREATE TABLE nested (id INT PRIMARY KEY, name STRING, tags STRING[], attrs JSONB, day DATE)`)
+		sqlDB.ExpectErr(t, "could not find column for parquet column extra",
+			`IMPORT INTO nested PARQUET DATA ('nodelocal://1/groups.parquet') WITH strict_validation`)
+		sqlDB.Exec(t, `IMPORT INTO nested PARQUET DATA ('nodelocal://1/groups.parquet')`)
+		sqlDB.CheckQueryResults(t, `SELECT count(*), count(name), count(tags) FROM nested`,
+			[][]string{{"100", "50", "50"}})
+		sqlDB.CheckQueryResults(t,
+			`SELECT id, name, tags, attrs, day::STRING FROM nested WHERE id IN (42, 43) ORDER BY id`, [][]string{
+				{"42", "name-42", "{x,42}", `{"a": 42, "b": "y"}`, "2022-02-19"},
+				{"43", "NULL", "NULL", "NULL", "NULL"},
+			})
+
+		// When the row groups of the file are split across inputs, each of them
+		// is imported exactly once.
+		defer func(old int64) { parquetInputTargetSize = old }(parquetInputTargetSize)
+		parquetInputTargetSize = 1
+		inputs, rowGroups, err := splitParquetFiles(ctx, []string{"nodelocal://1/groups.parquet"},
+			srv.ExecutorConfig().(sql.ExecutorConfig).DistSQLSrv.ExternalStorageFromURI, username.RootUserName())
+		require.NoError(t, err)
+		require.Len(t, inputs, 15)
+		for i, rg := range rowGroups {
+			require.Equal(t, roachpb.ParquetRowGroupRange{Start: int32(i), End: int32(i + 1)}, rg)
+		}
+		sqlDB.Exec(t, `CREATE TABLE split (id INT PRIMARY KEY, name STRING)`)
+		sqlDB.Exec(t, `IMPORT INTO split (id, name) PARQUET DATA ('nodelocal://1/groups.parquet')`)
+		sqlDB.CheckQueryResults(t, `SELECT count(*), count(name), sum(id) FROM split`,
+			[][]string{{"100", "50", "4950"}})
+
+		sqlDB.Exec(t, `CREATE TABLE limited (id INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `IMPORT INTO limited PARQUET DATA ('nodelocal://1/groups.parquet') WITH row_limit = '10'`)
+		sqlDB.CheckQueryResults(t, `SELECT count(*), max(id) FROM limited`, [][]string{{"10", "9"}})
+	})
+}
+
+func TestImportNDJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	data := `{"ID": 1, "s": "a", "d": 1.50, "ts": "2023-01-02 03:04:05+00", "tags": ["x", "y"], "j": {"k": [1, null]}}
+
+{"id": 2, "s": null, "j": 3, "unknown": true}
+{"id": 3, "s": "c", "d": "-7", "tags": [], "j": "str"}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.ndjson"), []byte(data), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.ndjson"), []byte(`[1, 2]`+"\n"), 0644))
+
+	sqlDB.Exec(t, `CREATE TABLE t (id INT PRIMARY KEY, s STRING, d DECIMAL, ts TIMESTAMPTZ, tags STRING[], j JSONB)`)
+	sqlDB.ExpectErr(t, "could not find column for record field unknown",
+		`IMPORT INTO t NDJSON DATA ('nodelocal://1/data.ndjson') WITH strict_validation`)
+	sqlDB.ExpectErr(t, "expected a JSON object",
+		`IMPORT INTO t NDJSON DATA ('nodelocal://1/bad.ndjson')`)
+	sqlDB.ExpectErr(t, "line too long",
+		`IMPORT INTO t NDJSON DATA ('nodelocal://1/data.ndjson') WITH max_row_size = '10B'`)
+
+	sqlDB.Exec(t, `IMPORT INTO t NDJSON DATA ('nodelocal://1/data.ndjson')`)
+	sqlDB.CheckQueryResults(t, `SELECT id, s, d, ts = '2023-01-02 03:04:05+00', tags, j FROM t ORDER BY id`, [][]string{
+		{"1", "a", "1.50", "true", "{x,y}", `{"k": [1, null]}`},
+		{"2", "NULL", "NULL", "NULL", "NULL", "3"},
+		{"3", "c", "-7", "NULL", "{}", `"str"`},
+	})
+}
+
 // TestImportClientDisconnect ensures that an import job can complete even if
 // the client connection which started it closes. This test uses a helper
 // subprocess to force a closed client connection without needing to rely
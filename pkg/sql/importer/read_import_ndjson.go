@@ -0,0 +1,262 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	gojson "encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// ndjsonToDatum converts a value decoded from a JSON document into a datum of
+// type targetT.
+//
+// JSONB columns accept any value. Otherwise, strings, numbers and booleans are
+// parsed as targetT, and arrays are converted element by element into array
+// columns.
+func ndjsonToDatum(
+	ctx context.Context, x interface{}, targetT *types.T, evalCtx *eval.Context,
+) (tree.Datum, error) {
+	if x == nil {
+		return tree.DNull, nil
+	}
+	if targetT.Family() == types.JsonFamily {
+		j, err := json.MakeJSON(x)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDJSON(j), nil
+	}
+	switch v := x.(type) {
+	case string:
+		return rowenc.ParseDatumStringAs(ctx, targetT, v, evalCtx)
+	case gojson.Number:
+		return rowenc.ParseDatumStringAs(ctx, targetT, v.String(), evalCtx)
+	case bool:
+		return rowenc.ParseDatumStringAs(ctx, targetT, strconv.FormatBool(v), evalCtx)
+	case []interface{}:
+		if targetT.Family() != types.ArrayFamily {
+			return nil, errors.Newf("cannot convert JSON array to %s", targetT)
+		}
+		arr := tree.NewDArray(targetT.ArrayContents())
+		for _, elt := range v {
+			d, err := ndjsonToDatum(ctx, elt, targetT.ArrayContents(), evalCtx)
+			if err == nil {
+				err = arr.Append(d)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	}
+	return nil, errors.Newf("cannot convert JSON value of type %T to %s", x, targetT)
+}
+
+// ndjsonConsumer implements importRowConsumer interface.
+type ndjsonConsumer struct {
+	fieldNameToIdx map[string]int
+	strict         bool
+}
+
+var _ importRowConsumer = &ndjsonConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (n *ndjsonConsumer) FillDatums(
+	ctx context.Context, native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	line := native.(string)
+	decoder := gojson.NewDecoder(bytes.NewReader([]byte(line)))
+	decoder.UseNumber()
+	var record map[string]interface{}
+	if err := decoder.Decode(&record); err != nil {
+		return newImportRowError(errors.Wrap(err, "expected a JSON object"), line, rowIndex)
+	}
+	if decoder.More() {
+		return newImportRowError(errors.New("expected a single JSON object per line"), line, rowIndex)
+	}
+
+	// Any column which isn't set in the record is null. The datums are reused
+	// across rows, so they have to be reset before filling in the columns of
+	// this row.
+	for i := range conv.Datums {
+		if conv.TargetColOrds.Contains(i) {
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	var setCols intsets.Fast
+	for f, v := range record {
+		field := lexbase.NormalizeName(f)
+		idx, ok := n.fieldNameToIdx[field]
+		if !ok {
+			if n.strict {
+				return newImportRowError(
+					fmt.Errorf("could not find column for record field %s", field), line, rowIndex)
+			}
+			continue
+		}
+		datum, err := ndjsonToDatum(ctx, v, conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			return newImportRowError(errors.Wrapf(err, "field %s", field), line, rowIndex)
+		}
+		conv.Datums[idx] = datum
+		setCols.Add(idx)
+	}
+
+	if n.strict {
+		for i := range conv.Datums {
+			if conv.TargetColOrds.Contains(i) && !setCols.Contains(i) {
+				return newImportRowError(
+					fmt.Errorf("field %s was not set in the record", conv.VisibleCols[i].GetName()), line, rowIndex)
+			}
+		}
+	}
+	return nil
+}
+
+// ndjsonStream is an importRowProducer over the lines of a newline-delimited
+// JSON file. Blank lines are ignored.
+type ndjsonStream struct {
+	input *fileReader
+	s     *bufio.Scanner
+	err   error
+}
+
+var _ importRowProducer = &ndjsonStream{}
+
+// Scan implements importRowProducer interface.
+func (n *ndjsonStream) Scan() bool {
+	for n.s.Scan() {
+		if len(bytes.TrimSpace(n.s.Bytes())) > 0 {
+			return true
+		}
+	}
+	if err := n.s.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			err = wrapWithLineTooLongHint(errors.New("line too long"))
+		}
+		n.err = err
+	}
+	return false
+}
+
+// Err implements importRowProducer interface.
+func (n *ndjsonStream) Err() error {
+	return n.err
+}
+
+// Skip implements importRowProducer interface.
+func (n *ndjsonStream) Skip() error {
+	return nil
+}
+
+// Row implements importRowProducer interface.
+func (n *ndjsonStream) Row() (interface{}, error) {
+	// The line is decoded by the consumer, since the scanner reuses its buffer.
+	return n.s.Text(), nil
+}
+
+// Progress implements importRowProducer interface.
+func (n *ndjsonStream) Progress() float32 {
+	return n.input.ReadFraction()
+}
+
+type ndjsonInputReader struct {
+	importCtx *parallelImportContext
+	opts      roachpb.NDJSONOptions
+}
+
+var _ inputConverter = &ndjsonInputReader{}
+
+func newNDJSONInputReader(
+	semaCtx *tree.SemaContext,
+	kvCh chan row.KVBatch,
+	tableDesc catalog.TableDescriptor,
+	opts roachpb.NDJSONOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *eval.Context,
+	db *kv.DB,
+) (*ndjsonInputReader, error) {
+	return &ndjsonInputReader{
+		importCtx: &parallelImportContext{
+			semaCtx:    semaCtx,
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			kvCh:       kvCh,
+			db:         db,
+		},
+		opts: opts,
+	}, nil
+}
+
+func (n *ndjsonInputReader) start(group ctxgroup.Group) {}
+
+func (n *ndjsonInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user username.SQLUsername,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, n.readFile, makeExternalStorage, user)
+}
+
+func (n *ndjsonInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	fieldIdxByName := make(map[string]int)
+	for idx, col := range n.importCtx.tableDesc.VisibleColumns() {
+		fieldIdxByName[col.GetName()] = idx
+	}
+	consumer := &ndjsonConsumer{
+		fieldNameToIdx: fieldIdxByName,
+		strict:         n.opts.StrictMode,
+	}
+
+	maxRowSize := int(n.opts.MaxRowSize)
+	if maxRowSize == 0 {
+		maxRowSize = defaultScanBuffer
+	}
+	s := bufio.NewScanner(input)
+	s.Buffer(nil, maxRowSize)
+	producer := &ndjsonStream{input: input, s: s}
+
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+		rowLimit: n.opts.RowLimit,
+	}
+	return runParallelImport(ctx, n.importCtx, fileCtx, producer, consumer)
+}
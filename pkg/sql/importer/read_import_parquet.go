@@ -0,0 +1,877 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/errors"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+)
+
+// parquetAnnotations returns the logical and converted types of the parquet
+// column described by el. The logical type is empty and the converted type is
+// -1 if they are not set.
+func parquetAnnotations(el *parquet.SchemaElement) (*parquet.LogicalType, parquet.ConvertedType) {
+	logical, converted := el.GetLogicalType(), parquet.ConvertedType(-1)
+	if logical == nil {
+		logical = parquet.NewLogicalType()
+	}
+	if el.IsSetConvertedType() {
+		converted = el.GetConvertedType()
+	}
+	return logical, converted
+}
+
+// parquetColumnType returns the type of the datums that the values of the
+// primitive parquet column described by el naturally map to.
+func parquetColumnType(el *parquet.SchemaElement) *types.T {
+	logical, converted := parquetAnnotations(el)
+	switch el.GetType() {
+	case parquet.Type_BOOLEAN:
+		return types.Bool
+	case parquet.Type_INT32, parquet.Type_INT64:
+		switch {
+		case logical.IsSetDATE() || converted == parquet.ConvertedType_DATE:
+			return types.Date
+		case logical.IsSetTIME() || converted == parquet.ConvertedType_TIME_MILLIS ||
+			converted == parquet.ConvertedType_TIME_MICROS:
+			return types.Time
+		case logical.IsSetTIMESTAMP() && !logical.GetTIMESTAMP().GetIsAdjustedToUTC():
+			return types.Timestamp
+		case logical.IsSetTIMESTAMP() || converted == parquet.ConvertedType_TIMESTAMP_MILLIS ||
+			converted == parquet.ConvertedType_TIMESTAMP_MICROS:
+			return types.TimestampTZ
+		case logical.IsSetDECIMAL() || converted == parquet.ConvertedType_DECIMAL:
+			return types.Decimal
+		}
+		return types.Int
+	case parquet.Type_INT96:
+		return types.TimestampTZ
+	case parquet.Type_FLOAT, parquet.Type_DOUBLE:
+		return types.Float
+	}
+	switch {
+	case logical.IsSetSTRING() || logical.IsSetENUM() ||
+		converted == parquet.ConvertedType_UTF8 || converted == parquet.ConvertedType_ENUM:
+		return types.String
+	case logical.IsSetJSON() || converted == parquet.ConvertedType_JSON:
+		return types.Jsonb
+	case logical.IsSetUUID():
+		return types.Uuid
+	case logical.IsSetDECIMAL() || converted == parquet.ConvertedType_DECIMAL:
+		return types.Decimal
+	}
+	return types.Bytes
+}
+
+// parquetTimeUnit returns the duration of the unit of the time or timestamp
+// parquet column described by el.
+func parquetTimeUnit(el *parquet.SchemaElement) time.Duration {
+	logical, converted := parquetAnnotations(el)
+	var unit *parquet.TimeUnit
+	switch {
+	case logical.IsSetTIME():
+		unit = logical.GetTIME().GetUnit()
+	case logical.IsSetTIMESTAMP():
+		unit = logical.GetTIMESTAMP().GetUnit()
+	}
+	switch {
+	case unit != nil && unit.IsSetNANOS():
+		return time.Nanosecond
+	case unit != nil && unit.IsSetMILLIS():
+		return time.Millisecond
+	case unit == nil && (converted == parquet.ConvertedType_TIME_MILLIS ||
+		converted == parquet.ConvertedType_TIMESTAMP_MILLIS):
+		return time.Millisecond
+	}
+	return time.Microsecond
+}
+
+// parquetDecimalScale returns the scale of the decimal parquet column
+// described by el.
+func parquetDecimalScale(el *parquet.SchemaElement) int32 {
+	if logical, _ := parquetAnnotations(el); logical.IsSetDECIMAL() {
+		return logical.GetDECIMAL().GetScale()
+	}
+	return el.GetScale()
+}
+
+// parquetPrimitiveToDatum converts a value of the primitive parquet column
+// described by el into a datum of the type returned by parquetColumnType.
+func parquetPrimitiveToDatum(x interface{}, el *parquet.SchemaElement) (tree.Datum, error) {
+	typ := parquetColumnType(el)
+	switch v := x.(type) {
+	case bool:
+		return tree.MakeDBool(tree.DBool(v)), nil
+	case float32:
+		return tree.NewDFloat(tree.DFloat(v)), nil
+	case float64:
+		return tree.NewDFloat(tree.DFloat(v)), nil
+	case [12]byte:
+		return tree.MakeDTimestampTZ(goparquet.Int96ToTime(v).UTC(), time.Microsecond)
+	case int32:
+		return parquetIntToDatum(int64(v), el, typ)
+	case int64:
+		return parquetIntToDatum(v, el, typ)
+	case []byte:
+		switch typ.Family() {
+		case types.StringFamily:
+			return tree.NewDString(string(v)), nil
+		case types.JsonFamily:
+			return tree.ParseDJSON(string(v))
+		case types.UuidFamily:
+			return tree.ParseDUuidFromBytes(v)
+		case types.DecimalFamily:
+			// EXPORT PARQUET writes decimals as their textual representation, while
+			// the parquet specification stores the unscaled value as a big-endian
+			// two's complement integer.
+			if d, err := tree.ParseDDecimal(string(v)); err == nil {
+				return d, nil
+			}
+			unscaled := new(big.Int).SetBytes(v)
+			if len(v) > 0 && v[0]&0x80 != 0 {
+				unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(v)*8)))
+			}
+			coeff := new(apd.BigInt).SetMathBigInt(unscaled)
+			return &tree.DDecimal{Decimal: *apd.NewWithBigInt(coeff, -parquetDecimalScale(el))}, nil
+		}
+		return tree.NewDBytes(tree.DBytes(v)), nil
+	}
+	return nil, errors.Newf("cannot handle type %T of parquet column %s", x, el.GetName())
+}
+
+// parquetIntToDatum converts an integer value of the parquet column described
+// by el into a datum of type typ, as returned by parquetColumnType.
+func parquetIntToDatum(v int64, el *parquet.SchemaElement, typ *types.T) (tree.Datum, error) {
+	switch typ.Family() {
+	case types.DateFamily:
+		d, err := pgdate.MakeDateFromUnixEpoch(v)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDDate(d), nil
+	case types.TimeFamily:
+		return tree.MakeDTime(timeofday.TimeOfDay(time.Duration(v) * parquetTimeUnit(el) / time.Microsecond)), nil
+	case types.TimestampFamily:
+		return tree.MakeDTimestamp(timeutil.Unix(0, v*int64(parquetTimeUnit(el))), time.Microsecond)
+	case types.TimestampTZFamily:
+		return tree.MakeDTimestampTZ(timeutil.Unix(0, v*int64(parquetTimeUnit(el))), time.Microsecond)
+	case types.DecimalFamily:
+		return &tree.DDecimal{Decimal: *apd.New(v, -parquetDecimalScale(el))}, nil
+	}
+	return tree.NewDInt(tree.DInt(v)), nil
+}
+
+// isParquetList returns true if col is a list annotated with the LIST logical
+// type.
+func isParquetList(col *parquetschema.ColumnDefinition) bool {
+	logical, converted := parquetAnnotations(col.SchemaElement)
+	return len(col.Children) == 1 &&
+		(logical.IsSetLIST() || converted == parquet.ConvertedType_LIST)
+}
+
+// isParquetMap returns true if col is a map annotated with the MAP logical
+// type.
+func isParquetMap(col *parquetschema.ColumnDefinition) bool {
+	logical, converted := parquetAnnotations(col.SchemaElement)
+	return len(col.Children) == 1 && len(col.Children[0].Children) == 2 &&
+		(logical.IsSetMAP() || converted == parquet.ConvertedType_MAP ||
+			converted == parquet.ConvertedType_MAP_KEY_VALUE)
+}
+
+// parquetListElements returns the elements of the value x of the list column
+// col and the column that describes the elements.
+//
+// The parquet library decodes a list into a map whose only key is the name of
+// the repeated group of the list. Its value is a slice of maps whose only key
+// is the name of the element column, unless the element is null. An empty list
+// is decoded as a slice that holds a single empty map.
+func parquetListElements(
+	x interface{}, col *parquetschema.ColumnDefinition,
+) ([]interface{}, *parquetschema.ColumnDefinition, error) {
+	repeated := col.Children[0]
+	if len(repeated.Children) != 1 {
+		return nil, nil, errors.Newf("unsupported layout of parquet list column %s", col.SchemaElement.GetName())
+	}
+	elCol := repeated.Children[0]
+	group, ok := x.(map[string]interface{})
+	if !ok {
+		return nil, nil, errors.Newf("unexpected value of type %T in parquet list column %s", x, col.SchemaElement.GetName())
+	}
+	entries, _ := group[repeated.SchemaElement.GetName()].([]map[string]interface{})
+	if len(entries) == 1 && len(entries[0]) == 0 {
+		return nil, elCol, nil
+	}
+	elts := make([]interface{}, len(entries))
+	for i, entry := range entries {
+		elts[i] = entry[elCol.SchemaElement.GetName()]
+	}
+	return elts, elCol, nil
+}
+
+// parquetToJSON converts the value x of the parquet column col into JSON.
+// Groups become objects, and lists and repeated fields become arrays.
+func parquetToJSON(x interface{}, col *parquetschema.ColumnDefinition) (json.JSON, error) {
+	if x == nil {
+		return json.NullJSONValue, nil
+	}
+	switch {
+	case isParquetList(col):
+		elts, elCol, err := parquetListElements(x, col)
+		if err != nil {
+			return nil, err
+		}
+		b := json.NewArrayBuilder(len(elts))
+		for _, elt := range elts {
+			j, err := parquetToJSON(elt, elCol)
+			if err != nil {
+				return nil, err
+			}
+			b.Add(j)
+		}
+		return b.Build(), nil
+	case isParquetMap(col):
+		group, _ := x.(map[string]interface{})
+		keyValue := col.Children[0]
+		entries, _ := group[keyValue.SchemaElement.GetName()].([]map[string]interface{})
+		b := json.NewObjectBuilder(len(entries))
+		for _, entry := range entries {
+			if len(entry) == 0 {
+				continue
+			}
+			key, err := parquetToJSON(entry[keyValue.Children[0].SchemaElement.GetName()], keyValue.Children[0])
+			if err != nil {
+				return nil, err
+			}
+			value, err := parquetToJSON(entry[keyValue.Children[1].SchemaElement.GetName()], keyValue.Children[1])
+			if err != nil {
+				return nil, err
+			}
+			keyStr, err := key.AsText()
+			if err != nil || keyStr == nil {
+				return nil, errors.Newf("invalid key in parquet map column %s", col.SchemaElement.GetName())
+			}
+			b.Add(*keyStr, value)
+		}
+		return b.Build(), nil
+	}
+
+	switch v := x.(type) {
+	case []interface{}:
+		// A repeated primitive field.
+		b := json.NewArrayBuilder(len(v))
+		for _, elt := range v {
+			j, err := parquetToJSON(elt, &parquetschema.ColumnDefinition{SchemaElement: col.SchemaElement})
+			if err != nil {
+				return nil, err
+			}
+			b.Add(j)
+		}
+		return b.Build(), nil
+	case []map[string]interface{}:
+		// A repeated group.
+		b := json.NewArrayBuilder(len(v))
+		for _, elt := range v {
+			j, err := parquetToJSON(elt, &parquetschema.ColumnDefinition{Children: col.Children})
+			if err != nil {
+				return nil, err
+			}
+			b.Add(j)
+		}
+		return b.Build(), nil
+	case map[string]interface{}:
+		b := json.NewObjectBuilder(len(col.Children))
+		for _, child := range col.Children {
+			j, err := parquetToJSON(v[child.SchemaElement.GetName()], child)
+			if err != nil {
+				return nil, err
+			}
+			b.Add(child.SchemaElement.GetName(), j)
+		}
+		return b.Build(), nil
+	}
+
+	d, err := parquetPrimitiveToDatum(x, col.SchemaElement)
+	if err != nil {
+		return nil, err
+	}
+	if j, ok := d.(*tree.DJSON); ok {
+		return j.JSON, nil
+	}
+	return tree.AsJSON(d, sessiondatapb.DataConversionConfig{}, time.UTC)
+}
+
+// parquetToDatum converts the value x of the parquet column col into a datum
+// of type targetT.
+//
+// Primitive values are first decoded into the datum type that their physical
+// and logical parquet types map to, which is then converted into targetT.
+// Strings can be converted into any type that they can be parsed as, so that
+// files written by EXPORT PARQUET, which stores many types as strings, can be
+// imported. Lists can be imported into array columns, and lists, maps and
+// groups can be imported into JSONB columns.
+func parquetToDatum(
+	ctx context.Context,
+	x interface{},
+	col *parquetschema.ColumnDefinition,
+	targetT *types.T,
+	evalCtx *eval.Context,
+) (tree.Datum, error) {
+	if x == nil {
+		return tree.DNull, nil
+	}
+	name := col.SchemaElement.GetName()
+	if targetT.Family() == types.JsonFamily {
+		j, err := parquetToJSON(x, col)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDJSON(j), nil
+	}
+	if isParquetList(col) {
+		if targetT.Family() != types.ArrayFamily {
+			return nil, errors.Newf("cannot convert parquet list column %s to %s", name, targetT)
+		}
+		elts, elCol, err := parquetListElements(x, col)
+		if err != nil {
+			return nil, err
+		}
+		arr := tree.NewDArray(targetT.ArrayContents())
+		for _, elt := range elts {
+			d, err := parquetToDatum(ctx, elt, elCol, targetT.ArrayContents(), evalCtx)
+			if err == nil {
+				err = arr.Append(d)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	}
+	if len(col.Children) > 0 {
+		return nil, errors.Newf("cannot convert parquet group column %s to %s", name, targetT)
+	}
+	if _, ok := x.([]interface{}); ok {
+		return nil, errors.Newf("cannot convert repeated parquet column %s to %s", name, targetT)
+	}
+
+	d, err := parquetPrimitiveToDatum(x, col.SchemaElement)
+	if err != nil {
+		return nil, err
+	}
+	switch t := d.(type) {
+	case *tree.DString:
+		return rowenc.ParseDatumStringAs(ctx, targetT, string(*t), evalCtx)
+	case *tree.DBytes:
+		if targetT.Family() != types.BytesFamily {
+			return rowenc.ParseDatumStringAs(ctx, targetT, string(*t), evalCtx)
+		}
+	}
+	if targetT.Equivalent(d.ResolvedType()) && targetT.Family() != types.DecimalFamily {
+		return d, nil
+	}
+	res, err := eval.PerformCast(ctx, evalCtx, d, targetT)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot convert parquet column %s to %s", name, targetT)
+	}
+	return res, nil
+}
+
+// parquetConsumer implements importRowConsumer interface.
+type parquetConsumer struct {
+	fieldNameToIdx map[string]int
+	columns        map[string]*parquetschema.ColumnDefinition
+}
+
+var _ importRowConsumer = &parquetConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (p *parquetConsumer) FillDatums(
+	ctx context.Context, native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	record, ok := native.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected native type; expected map[string]interface{} found %T instead", native)
+	}
+	// The parquet library omits the null values of a row, so any column that is
+	// not set is null. The datums are reused across rows, so they have to be
+	// reset before filling in the columns of this row.
+	for i := range conv.Datums {
+		if conv.TargetColOrds.Contains(i) {
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	for f, v := range record {
+		idx, ok := p.fieldNameToIdx[lexbase.NormalizeName(f)]
+		if !ok {
+			continue
+		}
+		datum, err := parquetToDatum(ctx, v, p.columns[f], conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			return newImportRowError(err, fmt.Sprintf("%v", record), rowIndex)
+		}
+		conv.Datums[idx] = datum
+	}
+	return nil
+}
+
+// parquetDecodedValueOverhead is an estimate of the memory used by a decoded
+// parquet value in addition to its data, i.e. its entry in the map of its row
+// and its boxing into an interface.
+const parquetDecodedValueOverhead = 64
+
+// parquetStream is an importRowProducer over the rows of the row groups of a
+// parquet file which are listed in meta.
+//
+// The row groups are decoded one at a time, and the estimated memory usage of
+// the decoded row group is accounted for in acc until all of its rows have
+// been produced. The row groups that only contain rows that are skipped when
+// resuming an import are not decoded at all.
+type parquetStream struct {
+	ctx   context.Context
+	meta  *parquet.FileMetaData
+	input io.ReadSeeker
+	skip  int64
+	acc   *mon.BoundAccount
+
+	nextRowGroup int
+	nextRowPos   int64 // Position of the first row of the next row group.
+	rows         []map[string]interface{}
+	numRows      int64 // Number of rows of the current row group.
+	pos          int64 // Position of the next row within the current row group.
+	numRead      int64
+	err          error
+}
+
+var _ importRowProducer = &parquetStream{}
+
+func newParquetStream(
+	ctx context.Context,
+	meta *parquet.FileMetaData,
+	input io.ReadSeeker,
+	skip int64,
+	acc *mon.BoundAccount,
+) *parquetStream {
+	return &parquetStream{
+		ctx:   ctx,
+		meta:  meta,
+		input: input,
+		skip:  skip,
+		acc:   acc,
+	}
+}
+
+// close releases the memory of the current row group.
+func (p *parquetStream) close() {
+	p.rows = nil
+	p.acc.Clear(p.ctx)
+}
+
+// load decodes the next row group, unless all of its rows are skipped.
+func (p *parquetStream) load() error {
+	idx := p.nextRowGroup
+	rowGroup := p.meta.RowGroups[idx]
+	p.nextRowGroup++
+	p.numRows, p.pos = rowGroup.GetNumRows(), 0
+	p.nextRowPos += p.numRows
+	if p.nextRowPos <= p.skip {
+		// All the rows of the row group are skipped.
+		return nil
+	}
+
+	size := rowGroup.GetTotalByteSize() +
+		p.numRows*int64(len(rowGroup.GetColumns()))*parquetDecodedValueOverhead
+	if err := p.acc.Grow(p.ctx, size); err != nil {
+		return errors.Wrapf(err, "decoding row group %d", idx)
+	}
+
+	// The reader reads the row groups listed in the metadata in order, so it is
+	// given metadata that only lists the idx-th row group.
+	meta := *p.meta
+	meta.RowGroups = p.meta.RowGroups[idx : idx+1]
+	meta.NumRows = p.numRows
+	r, err := goparquet.NewFileReaderWithOptions(p.input,
+		goparquet.WithFileMetaData(&meta), goparquet.WithReaderContext(p.ctx))
+	if err != nil {
+		return err
+	}
+	p.rows = make([]map[string]interface{}, 0, p.numRows)
+	for int64(len(p.rows)) < p.numRows {
+		row, err := r.NextRowWithContext(p.ctx)
+		if err != nil {
+			return errors.Wrapf(err, "reading row group %d", idx)
+		}
+		p.rows = append(p.rows, row)
+	}
+	return nil
+}
+
+// Scan implements importRowProducer interface.
+func (p *parquetStream) Scan() bool {
+	for p.pos >= p.numRows {
+		p.close()
+		if p.nextRowGroup >= len(p.meta.RowGroups) {
+			return false
+		}
+		if err := p.load(); err != nil {
+			p.err = err
+			return false
+		}
+	}
+	return true
+}
+
+// Err implements importRowProducer interface.
+func (p *parquetStream) Err() error {
+	return p.err
+}
+
+// Skip implements importRowProducer interface.
+func (p *parquetStream) Skip() error {
+	p.pos++
+	p.numRead++
+	return nil
+}
+
+// Row implements importRowProducer interface.
+func (p *parquetStream) Row() (interface{}, error) {
+	if p.rows == nil {
+		return nil, errors.AssertionFailedf("row %d of a skipped row group was not skipped", p.numRead)
+	}
+	row := p.rows[p.pos]
+	// Release the row once it has been handed off.
+	p.rows[p.pos] = nil
+	p.pos++
+	p.numRead++
+	return row, nil
+}
+
+// Progress implements importRowProducer interface.
+func (p *parquetStream) Progress() float32 {
+	if p.meta.NumRows == 0 {
+		return 0
+	}
+	return float32(p.numRead) / float32(p.meta.NumRows)
+}
+
+// storageReadSeeker is an io.ReadSeeker over a file in external storage. It
+// opens a reader at the current offset on the first read after a seek.
+type storageReadSeeker struct {
+	ctx  context.Context
+	es   cloud.ExternalStorage
+	size int64
+	pos  int64
+	r    ioctx.ReadCloserCtx
+}
+
+var _ io.ReadSeeker = &storageReadSeeker{}
+
+// Read implements io.Reader interface.
+func (s *storageReadSeeker) Read(p []byte) (int, error) {
+	if s.pos >= s.size {
+		return 0, io.EOF
+	}
+	if s.r == nil {
+		r, _, err := s.es.ReadFileAt(s.ctx, "", s.pos)
+		if err != nil {
+			return 0, err
+		}
+		s.r = r
+	}
+	n, err := s.r.Read(s.ctx, p)
+	s.pos += int64(n)
+	return n, err
+}
+
+// Seek implements io.Seeker interface.
+func (s *storageReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	pos := offset
+	switch whence {
+	case io.SeekCurrent:
+		pos += s.pos
+	case io.SeekEnd:
+		pos += s.size
+	}
+	if pos < 0 {
+		return 0, errors.Newf("invalid seek to offset %d", pos)
+	}
+	if pos != s.pos {
+		s.close()
+		s.pos = pos
+	}
+	return pos, nil
+}
+
+func (s *storageReadSeeker) close() {
+	if s.r != nil {
+		_ = s.r.Close(s.ctx)
+		s.r = nil
+	}
+}
+
+type parquetInputReader struct {
+	importCtx *parallelImportContext
+	opts      roachpb.ParquetOptions
+	rowGroups map[int32]roachpb.ParquetRowGroupRange
+	memMon    *mon.BytesMonitor
+}
+
+var _ inputConverter = &parquetInputReader{}
+
+func newParquetInputReader(
+	semaCtx *tree.SemaContext,
+	kvCh chan row.KVBatch,
+	tableDesc catalog.TableDescriptor,
+	opts roachpb.ParquetOptions,
+	rowGroups map[int32]roachpb.ParquetRowGroupRange,
+	walltime int64,
+	parallelism int,
+	evalCtx *eval.Context,
+	db *kv.DB,
+	memMon *mon.BytesMonitor,
+) (*parquetInputReader, error) {
+	return &parquetInputReader{
+		importCtx: &parallelImportContext{
+			semaCtx:    semaCtx,
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			kvCh:       kvCh,
+			db:         db,
+		},
+		opts:      opts,
+		rowGroups: rowGroups,
+		memMon:    memMon,
+	}, nil
+}
+
+func (p *parquetInputReader) start(group ctxgroup.Group) {}
+
+// readFiles implements inputConverter interface.
+//
+// Unlike the other formats, parquet files cannot be read as a stream: the
+// metadata of a file is in its footer, and the metadata locates the row groups
+// of the file. So rather than using readInputFiles, the files are read
+// through storageReadSeekers.
+func (p *parquetInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user username.SQLUsername,
+) error {
+	for dataFileIndex, dataFile := range dataFiles {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if err := func() error {
+			conf, err := cloud.ExternalStorageConfFromURI(dataFile, user)
+			if err != nil {
+				return err
+			}
+			es, err := makeExternalStorage(ctx, conf)
+			if err != nil {
+				return err
+			}
+			defer es.Close()
+			return p.readFile(ctx, es, dataFileIndex, resumePos[dataFileIndex])
+		}(); err != nil {
+			return errors.Wrapf(err, "%s", dataFile)
+		}
+	}
+	return nil
+}
+
+func (p *parquetInputReader) readFile(
+	ctx context.Context, es cloud.ExternalStorage, inputIdx int32, resumePos int64,
+) error {
+	input, meta, err := openParquetFile(ctx, es)
+	if err != nil {
+		return err
+	}
+	defer input.close()
+	r, err := goparquet.NewFileReaderWithOptions(input,
+		goparquet.WithFileMetaData(meta), goparquet.WithReaderContext(ctx))
+	if err != nil {
+		return err
+	}
+	consumer, err := p.newConsumer(r.GetSchemaDefinition())
+	if err != nil {
+		return err
+	}
+
+	// Only read the row groups that the input covers.
+	if rowGroups, ok := p.rowGroups[inputIdx]; ok {
+		if rowGroups.Start < 0 || rowGroups.Start > rowGroups.End || int(rowGroups.End) > len(meta.RowGroups) {
+			return errors.Newf("row groups [%d, %d) are out of range, the file has %d row groups",
+				rowGroups.Start, rowGroups.End, len(meta.RowGroups))
+		}
+		meta.RowGroups = meta.RowGroups[rowGroups.Start:rowGroups.End]
+		meta.NumRows = 0
+		for _, rowGroup := range meta.RowGroups {
+			meta.NumRows += rowGroup.GetNumRows()
+		}
+	}
+
+	var acc *mon.BoundAccount
+	if p.memMon != nil {
+		a := p.memMon.MakeBoundAccount()
+		acc = &a
+	}
+	producer := newParquetStream(ctx, meta, input, resumePos, acc)
+	defer producer.close()
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rowLimit: p.opts.RowLimit,
+	}
+	return runParallelImport(ctx, p.importCtx, fileCtx, producer, consumer)
+}
+
+// openParquetFile returns a reader over the parquet file of the given storage,
+// along with the metadata of the file.
+func openParquetFile(
+	ctx context.Context, es cloud.ExternalStorage,
+) (*storageReadSeeker, *parquet.FileMetaData, error) {
+	size, err := es.Size(ctx, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	input := &storageReadSeeker{ctx: ctx, es: es, size: size}
+	meta, err := goparquet.ReadFileMetaDataWithContext(ctx, input, true /* extraValidation */)
+	if err != nil {
+		input.close()
+		return nil, nil, err
+	}
+	return input, meta, nil
+}
+
+// parquetInputTargetSize is the target uncompressed size of the row groups
+// covered by each of the inputs that parquet files are split into.
+var parquetInputTargetSize = int64(util.ConstantWithMetamorphicTestValue(
+	"parquet-input-target-size",
+	64<<20, /* defaultValue */
+	1,      /* metamorphicValue */
+))
+
+// splitParquetFiles splits the given parquet files into inputs that each cover
+// a range of consecutive row groups of a file, so that the row groups of large
+// files are imported by different processors. It returns the URI of the file of
+// each input along with the range of its row groups.
+func splitParquetFiles(
+	ctx context.Context,
+	files []string,
+	makeExternalStorage cloud.ExternalStorageFromURIFactory,
+	user username.SQLUsername,
+) ([]string, []roachpb.ParquetRowGroupRange, error) {
+	var inputs []string
+	var rowGroups []roachpb.ParquetRowGroupRange
+	for _, file := range files {
+		if err := func() error {
+			es, err := makeExternalStorage(ctx, file, user)
+			if err != nil {
+				return err
+			}
+			defer es.Close()
+			input, meta, err := openParquetFile(ctx, es)
+			if err != nil {
+				return err
+			}
+			input.close()
+
+			cur := roachpb.ParquetRowGroupRange{}
+			var curSize int64
+			for i, rowGroup := range meta.RowGroups {
+				if curSize >= parquetInputTargetSize {
+					inputs = append(inputs, file)
+					rowGroups = append(rowGroups, cur)
+					cur, curSize = roachpb.ParquetRowGroupRange{Start: int32(i)}, 0
+				}
+				cur.End = int32(i + 1)
+				curSize += rowGroup.GetTotalByteSize()
+			}
+			// Files without row groups still get an input, which produces no rows.
+			inputs = append(inputs, file)
+			rowGroups = append(rowGroups, cur)
+			return nil
+		}(); err != nil {
+			return nil, nil, errors.Wrapf(err, "%s", file)
+		}
+	}
+	return inputs, rowGroups, nil
+}
+
+// newConsumer returns a consumer that maps the columns of the parquet schema
+// to the columns of the table with the same name.
+func (p *parquetInputReader) newConsumer(
+	schema *parquetschema.SchemaDefinition,
+) (*parquetConsumer, error) {
+	consumer := &parquetConsumer{
+		fieldNameToIdx: make(map[string]int),
+		columns:        make(map[string]*parquetschema.ColumnDefinition),
+	}
+	for idx, col := range p.importCtx.tableDesc.VisibleColumns() {
+		consumer.fieldNameToIdx[col.GetName()] = idx
+	}
+	found := make(map[string]bool)
+	for _, col := range schema.RootColumn.Children {
+		name := col.SchemaElement.GetName()
+		consumer.columns[name] = col
+		field := lexbase.NormalizeName(name)
+		if _, ok := consumer.fieldNameToIdx[field]; !ok {
+			if p.opts.StrictMode {
+				return nil, errors.Newf("could not find column for parquet column %s", field)
+			}
+			continue
+		}
+		found[field] = true
+	}
+	if p.opts.StrictMode {
+		for _, col := range p.importCtx.tableDesc.VisibleColumns() {
+			if !found[col.GetName()] {
+				return nil, errors.Newf("column %s is not in the parquet file", col.GetName())
+			}
+		}
+	}
+	return consumer, nil
+}
@@ -631,9 +631,6 @@ func readPostgresStmt(
 			schemaObjects.createTbl[schemaQualifiedName] = nil
 		}
 	case *tree.CreateIndex:
-		if stmt.Predicate != nil {
-			return unimplemented.NewWithIssue(50225, "cannot import a table with partial indexes")
-		}
 		schemaQualifiedTableName, err := getSchemaAndTableName(&stmt.Table)
 		if err != nil {
 			return err
@@ -649,6 +646,7 @@ func readPostgresStmt(
 			Inverted:         stmt.Inverted,
 			PartitionByIndex: stmt.PartitionByIndex,
 			StorageParams:    stmt.StorageParams,
+			Predicate:        stmt.Predicate,
 			// Postgres doesn't support NotVisible Index, so NotVisible is not populated here.
 		}
 		if stmt.Unique {
@@ -396,13 +396,16 @@ func init() {
 		&tree.AlterBackupSchedule{},
 		&tree.AlterTenantReplication{},
 		&tree.Backup{},
+		&tree.CompactBackups{},
 		&tree.ShowBackup{},
 		&tree.Restore{},
+		&tree.VerifyBackup{},
 		&tree.CreateChangefeed{},
 		&tree.ScheduledChangefeed{},
 		&tree.Import{},
 		&tree.ScheduledBackup{},
 		&tree.CreateTenantFromReplication{},
+		&tree.CreateLogicalReplicationStream{},
 	} {
 		typ := optbuilder.OpaqueReadOnly
 		if tree.CanModifySchema(stmt) {
@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/mutations"
@@ -976,6 +977,22 @@ func (b *Builder) canAutoCommit(rel memo.RelExpr) bool {
 // equivalent that used by a SELECT ... FOR UPDATE statement.
 var forUpdateLocking = opt.Locking{Strength: tree.ForUpdate}
 
+// buildLocking returns the row-level locking mode to use for an expression
+// with the given locking mode. FOR SHARE and FOR KEY SHARE locking acquire
+// Shared locks, which nodes running older binaries don't support, so until the
+// cluster version supporting them is active these strengths perform
+// non-locking reads, as they always used to.
+func (b *Builder) buildLocking(locking opt.Locking) opt.Locking {
+	if b.forceForUpdateLocking {
+		return forUpdateLocking
+	}
+	if (locking.Strength == tree.ForShare || locking.Strength == tree.ForKeyShare) &&
+		!b.evalCtx.Settings.Version.IsActive(b.ctx, clusterversion.V23_2_SharedLocks) {
+		locking.Strength = tree.ForNone
+	}
+	return locking
+}
+
 // shouldApplyImplicitLockingToMutationInput determines whether or not the
 // builder should apply a FOR UPDATE row-level locking mode to the initial row
 // scan of a mutation expression.
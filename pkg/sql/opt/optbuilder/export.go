@@ -11,8 +11,12 @@
 package optbuilder
 
 import (
+	"strings"
+
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
@@ -22,7 +26,11 @@ func (b *Builder) buildExport(export *tree.Export, inScope *scope) (outScope *sc
 	// We don't allow the input statement to reference outer columns, so we
 	// pass a "blank" scope rather than inScope.
 	emptyScope := b.allocScope()
-	inputScope := b.buildStmt(export.Query, nil /* desiredTypes */, emptyScope)
+	query := export.Query
+	if isIncrementalIcebergExport(export) {
+		query = addMVCCTimestampColumn(query)
+	}
+	inputScope := b.buildStmt(query, nil /* desiredTypes */, emptyScope)
 
 	texpr := emptyScope.resolveType(export.File, types.String)
 	fileName := b.buildScalar(
@@ -61,3 +69,46 @@ func (b *Builder) buildKVOptions(opts tree.KVOptions, inScope *scope) memo.KVOpt
 	}
 	return res
 }
+
+// isIncrementalIcebergExport returns whether the EXPORT statement appends only
+// the rows written since the previous snapshot of an Iceberg table.
+func isIncrementalIcebergExport(export *tree.Export) bool {
+	if !strings.EqualFold(export.FileFormat, "iceberg") {
+		return false
+	}
+	for _, opt := range export.Options {
+		if opt.Key == "incremental" {
+			return true
+		}
+	}
+	return false
+}
+
+// addMVCCTimestampColumn returns a copy of the query of an incremental export
+// that additionally outputs the MVCC timestamp of each row as its last column,
+// which the export uses to skip the rows written before its start time. Only
+// queries that read the rows of a single table have such a timestamp.
+func addMVCCTimestampColumn(query *tree.Select) *tree.Select {
+	sel, ok := query.Select.(*tree.SelectClause)
+	if !ok || query.With != nil || len(sel.From.Tables) != 1 || sel.GroupBy != nil ||
+		sel.Having != nil || sel.Window != nil || sel.Distinct || sel.DistinctOn != nil {
+		ok = false
+	} else if tab, isAliased := sel.From.Tables[0].(*tree.AliasedTableExpr); !isAliased {
+		ok = false
+	} else {
+		_, ok = tab.Expr.(*tree.TableName)
+	}
+	if !ok {
+		panic(pgerror.New(pgcode.FeatureNotSupported,
+			"incremental exports are only supported for queries over a single table"))
+	}
+	selCopy := *sel
+	selCopy.Exprs = append(append(tree.SelectExprs(nil), sel.Exprs...), tree.SelectExpr{
+		Expr: &tree.UnresolvedName{
+			NumParts: 1, Parts: tree.NameParts{colinfo.MVCCTimestampColumnName},
+		},
+	})
+	queryCopy := *query
+	queryCopy.Select = &selCopy
+	return &queryCopy
+}
@@ -513,6 +513,12 @@ func TestContextualHelp(t *testing.T) {
 		{`BACKUP DATABASE ??`, `BACKUP`},
 		{`BACKUP foo TO 'bar' AS OF ??`, `BACKUP`},
 
+		{`COMPACT BACKUPS ??`, `COMPACT BACKUPS`},
+		{`COMPACT BACKUPS IN 'foo' FROM 'a' TO ??`, `COMPACT BACKUPS`},
+
+		{`VERIFY BACKUP ??`, `VERIFY BACKUP`},
+		{`VERIFY BACKUP FROM 'foo' IN 'bar' WITH ??`, `VERIFY BACKUP`},
+
 		{`RESTORE foo FROM 'bar' ??`, `RESTORE`},
 		{`RESTORE DATABASE ??`, `RESTORE`},
 
@@ -529,6 +535,9 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE CHANGEFEED FOR foo ??`, `CREATE CHANGEFEED`},
 		{`CREATE CHANGEFEED FOR foo INTO 'sink' ??`, `CREATE CHANGEFEED`},
 
+		{`CREATE LOGICAL REPLICATION STREAM ??`, `CREATE LOGICAL REPLICATION STREAM`},
+		{`CREATE LOGICAL REPLICATION STREAM FROM TABLE foo ON 'uri' INTO TABLE bar WITH ??`, `CREATE LOGICAL REPLICATION STREAM`},
+
 		{`CREATE FUNCTION ??`, `CREATE FUNCTION`},
 		{`ALTER FUNCTION ??`, `ALTER FUNCTION`},
 		{`DROP FUNCTION ??`, `DROP FUNCTION`},
@@ -890,7 +890,7 @@ func (u *sqlSymUnion) showCreateFormatOption() tree.ShowCreateFormatOption {
 %token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC AS_JSON AT_AT
 %token <str> ASENSITIVE ASYMMETRIC AT ATOMIC ATTRIBUTE AUTHORIZATION AUTOMATIC AVAILABILITY
 
-%token <str> BACKUP BACKUPS BACKWARD BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
+%token <str> BACKFILL BACKUP BACKUPS BACKWARD BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
 %token <str> BUCKET_COUNT
 %token <str> BOOLEAN BOTH BOX2D BUNDLE BY
 
@@ -904,7 +904,7 @@ func (u *sqlSymUnion) showCreateFormatOption() tree.ShowCreateFormatOption {
 %token <str> CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
 %token <str> CURRENT_USER CURSOR CYCLE
 
-%token <str> DATA DATABASE DATABASES DATE DAY DEBUG_IDS DEBUG_PAUSE_ON DEC DEBUG_DUMP_METADATA_SST DECIMAL DEFAULT DEFAULTS DEFINER
+%token <str> DATA DATABASE DATABASES DATE DAY DEBUG_IDS DEBUG_PAUSE_ON DEC DEBUG_DUMP_METADATA_SST DECIMAL DEEP DEFAULT DEFAULTS DEFINER
 %token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DEPENDS DESC DESTINATION DETACHED DETAILS
 %token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
@@ -915,7 +915,7 @@ func (u *sqlSymUnion) showCreateFormatOption() tree.ShowCreateFormatOption {
 %token <str> EXPIRATION EXPLAIN EXPORT EXTENSION EXTERNAL EXTRACT EXTRACT_DURATION EXTREMES
 
 %token <str> FAILURE FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH
-%token <str> FILES FILTER
+%token <str> FILES FILTER FINGERPRINTS
 %token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE FORCE_INDEX
 %token <str> FORCE_NOT_NULL FORCE_NULL FORCE_QUOTE FORCE_ZIGZAG
 %token <str> FOREIGN FORMAT FORWARD FREEZE FROM FULL FUNCTION FUNCTIONS
@@ -942,7 +942,7 @@ func (u *sqlSymUnion) showCreateFormatOption() tree.ShowCreateFormatOption {
 %token <str> LABEL LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
 %token <str> LEADING LEASE LEAST LEAKPROOF LEFT LESS LEVEL LIKE LIMIT
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
-%token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
+%token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGICAL LOGIN LOOKUP LOW LSHIFT
 
 %token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MODIFYSQLCLUSTERSETTING MONTH MOVE
 %token <str> MULTILINESTRING MULTILINESTRINGM MULTILINESTRINGZ MULTILINESTRINGZM
@@ -962,14 +962,14 @@ func (u *sqlSymUnion) showCreateFormatOption() tree.ShowCreateFormatOption {
 %token <str> PARALLEL PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PLACEMENT PLACING
 %token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
 %token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
-%token <str> PROCEDURAL PUBLIC PUBLICATION
+%token <str> PROCEDURAL PUBLIC PUBLICATION PURGE
 
 %token <str> QUERIES QUERY QUOTE
 
 %token <str> RANGE RANGES READ REAL REASON REASSIGN RECURSIVE RECURRING REDACT REF REFERENCES REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> RELATIVE RELOCATE REMOVE_PATH RENAME REPEATABLE REPLACE REPLICATION
-%token <str> RELEASE RESET RESTART RESTORE RESTRICT RESTRICTED RESUME RETENTION RETURNING RETURN RETURNS RETRY REVISION_HISTORY
+%token <str> RELEASE RESET RESTART RESTORE RESTRICT RESTRICTED RESUME RESUME_FROM_PROTECTED_TS RETENTION RETURNING RETURN RETURNS RETRY REVISION_HISTORY
 %token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS RSHIFT RULE RUNNING
 
 %token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCROLL SCHEMA SCHEMA_ONLY SCHEMAS SCRUB
@@ -990,7 +990,7 @@ func (u *sqlSymUnion) showCreateFormatOption() tree.ShowCreateFormatOption {
 %token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLISTEN UNLOGGED UNSAFE_RESTORE_INCOMPATIBLE_VERSION UNSPLIT
 %token <str> UPDATE UPSERT UNSET UNTIL USE USER USERS USING UUID
 
-%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VERIFY_BACKUP_TABLE_DATA VIEW VARYING VIEWACTIVITY VIEWACTIVITYREDACTED VIEWDEBUG
+%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VERIFY VERIFY_BACKUP_TABLE_DATA VERIFY_FINGERPRINTS VIEW VARYING VIEWACTIVITY VIEWACTIVITYREDACTED VIEWDEBUG
 %token <str> VIEWCLUSTERMETADATA VIEWCLUSTERSETTING VIRTUAL VISIBLE INVISIBLE VOLATILE VOTERS
 
 %token <str> WHEN WHERE WINDOW WITH WITHIN WITHOUT WORK WRITE
@@ -1136,6 +1136,8 @@ func (u *sqlSymUnion) showCreateFormatOption() tree.ShowCreateFormatOption {
 %type <tree.Statement> alter_func_dep_extension_stmt
 
 %type <tree.Statement> backup_stmt
+%type <tree.Statement> compact_backups_stmt
+%type <tree.Statement> verify_backup_stmt
 %type <tree.Statement> begin_stmt
 
 %type <tree.Statement> cancel_stmt
@@ -1159,6 +1161,7 @@ func (u *sqlSymUnion) showCreateFormatOption() tree.ShowCreateFormatOption {
 %type <tree.Statement> create_stmt
 %type <tree.Statement> create_schedule_stmt
 %type <tree.Statement> create_changefeed_stmt create_schedule_for_changefeed_stmt
+%type <tree.Statement> create_logical_replication_stream_stmt
 %type <tree.Statement> create_ddl_stmt
 %type <tree.Statement> create_database_stmt
 %type <tree.Statement> create_extension_stmt
@@ -1324,6 +1327,7 @@ func (u *sqlSymUnion) showCreateFormatOption() tree.ShowCreateFormatOption {
 %type <*tree.TenantReplicationOptions> opt_with_tenant_replication_options tenant_replication_options tenant_replication_options_list
 %type <tree.ShowBackupDetails> show_backup_details
 %type <*tree.ShowBackupOptions> opt_with_show_backup_options show_backup_options show_backup_options_list show_backup_connection_options show_backup_connection_options_list
+%type <*tree.ShowBackupOptions> opt_with_verify_backup_options verify_backup_options verify_backup_options_list
 %type <*tree.CopyOptions> opt_with_copy_options copy_options copy_options_list copy_generic_options copy_generic_options_list
 %type <str> import_format
 %type <str> storage_parameter_key
@@ -1471,7 +1475,7 @@ func (u *sqlSymUnion) showCreateFormatOption() tree.ShowCreateFormatOption {
 %type <tree.Expr> overlay_placing
 %type <*tree.TenantSpec> tenant_spec
 
-%type <bool> opt_unique opt_concurrently opt_cluster opt_without_index
+%type <bool> opt_unique opt_concurrently opt_cluster opt_without_index opt_purge
 %type <bool> opt_index_access_method opt_index_visible alter_index_visible
 
 %type <*tree.Limit> limit_clause offset_clause opt_limit_clause
@@ -3137,6 +3141,7 @@ opt_clear_data:
 //    detached: execute backup job asynchronously, without waiting for its completion
 //    incremental_location: specify a different path to store the incremental backup
 //    include_all_secondary_tenants: enable backups of all secondary tenants during a cluster backup in the system tenant
+//    fingerprints: record a fingerprint of each backed up file, and of each backed up table and index in full backups without revision_history
 //
 // %SeeAlso: RESTORE, WEBDOCS/backup.html
 backup_stmt:
@@ -3270,6 +3275,10 @@ backup_options:
   {
     $$.val = &tree.BackupOptions{IncludeAllSecondaryTenants: $3.expr()}
   }
+| FINGERPRINTS
+  {
+    $$.val = &tree.BackupOptions{Fingerprints: true}
+  }
 
 // %Help: CREATE SCHEDULE FOR BACKUP - backup data periodically
 // %Category: CCL
@@ -3561,6 +3570,11 @@ drop_external_connection_stmt:
 // RESTORE SYSTEM USERS FROM <location...>
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
+// or
+// RESTORE TABLE <table> FROM <subdir> IN <location...>
+//         [ AS OF SYSTEM TIME <expr> ]
+//         WHERE <primary key predicate> INTO <new table name>
+//         [ WITH <option> [= <value>] [, ...] ]
 //
 // Targets:
 //    TABLE <pattern> [, ...]
@@ -3583,6 +3597,7 @@ drop_external_connection_stmt:
 //    debug_pause_on: describes the events that the job should pause itself on for debugging purposes.
 //    new_db_name: renames the restored database. only applies to database restores
 //    include_all_secondary_tenants: enable backups of all secondary tenants during a cluster backup in the system tenant
+//    verify_fingerprints: check the restored tables and indexes against the fingerprints recorded in the backup; only supported when restoring a full backup taken without revision_history
 // %SeeAlso: BACKUP, WEBDOCS/restore.html
 restore_stmt:
   RESTORE FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
@@ -3623,6 +3638,18 @@ restore_stmt:
       Options: *($8.restoreOptions()),
     }
   }
+| RESTORE backup_targets FROM string_or_placeholder IN list_of_string_or_placeholder_opt_list opt_as_of_clause WHERE a_expr INTO name opt_with_restore_options
+  {
+    $$.val = &tree.Restore{
+      Targets: $2.backupTargetList(),
+      Subdir: $4.expr(),
+      From: $6.listOfStringOrPlaceholderOptList(),
+      AsOf: $7.asOfClause(),
+      RowFilter: $9.expr(),
+      IntoTable: tree.Name($11),
+      Options: *($12.restoreOptions()),
+    }
+  }
 | RESTORE SYSTEM USERS FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
   {
     $$.val = &tree.Restore{
@@ -3774,6 +3801,10 @@ restore_options:
   {
     $$.val = &tree.RestoreOptions{UnsafeRestoreIncompatibleVersion: true}
   }
+| VERIFY_FINGERPRINTS
+  {
+    $$.val = &tree.RestoreOptions{VerifyFingerprints: true}
+  }
 
 import_format:
   name
@@ -3853,9 +3884,14 @@ import_stmt:
 // Formats:
 //    CSV
 //    Parquet
+//    Iceberg             [Parquet data files appended as a snapshot of the
+//                         Iceberg table at <datafile>, which is created if
+//                         needed]
 //
 // Options:
 //    delimiter = '...'   [CSV-specific]
+//    incremental         [Iceberg-specific; exports the rows of a single table
+//                         written since the previous snapshot]
 //
 // %SeeAlso: SELECT
 export_stmt:
@@ -4356,6 +4392,7 @@ create_stmt:
 | create_extension_stmt  // EXTEND WITH HELP: CREATE EXTENSION
 | create_external_connection_stmt // EXTEND WITH HELP: CREATE EXTERNAL CONNECTION
 | create_tenant_stmt     // EXTEND WITH HELP: CREATE TENANT
+| create_logical_replication_stream_stmt // EXTEND WITH HELP: CREATE LOGICAL REPLICATION STREAM
 | create_schedule_stmt   // help texts in sub-rule
 | create_unsupported     {}
 | CREATE error           // SHOW HELP: CREATE
@@ -4365,6 +4402,11 @@ create_stmt:
 // %Text:
 // CREATE TENANT [ IF NOT EXISTS ] name [ LIKE <tenant_spec> ]
 // CREATE TENANT [ IF NOT EXISTS ] name [ LIKE <tenant_spec> ] FROM REPLICATION OF <tenant_spec> ON <location> [ WITH OPTIONS ... ]
+//
+// Options:
+// RETENTION = '<interval>'
+// TABLES = ('<database>.[<schema>.]<table>', ...)
+// DATABASES = ('<database>', ...)
 create_tenant_stmt:
   CREATE TENANT d_expr opt_like_tenant
   {
@@ -4455,6 +4497,18 @@ tenant_replication_options:
   {
     $$.val = &tree.TenantReplicationOptions{Retention: $3.expr()}
   }
+| TABLES '=' string_or_placeholder_opt_list
+  {
+    $$.val = &tree.TenantReplicationOptions{Tables: $3.stringOrPlaceholderOptList()}
+  }
+| DATABASES '=' string_or_placeholder_opt_list
+  {
+    $$.val = &tree.TenantReplicationOptions{Databases: $3.stringOrPlaceholderOptList()}
+  }
+| RESUME_FROM_PROTECTED_TS
+  {
+    $$.val = &tree.TenantReplicationOptions{ResumeFromProtectedTimestamp: true}
+  }
 
 // %Help: CREATE SCHEDULE
 // %Category: Group
@@ -5057,6 +5111,31 @@ create_stats_option:
     }
   }
 
+// %Help: CREATE LOGICAL REPLICATION STREAM - replicate the rows of a table of another cluster
+// %Category: CCL
+// %Text:
+// CREATE LOGICAL REPLICATION STREAM FROM TABLE <remote table> ON <source uri> INTO TABLE <table> [WITH <options>]
+//
+// Both tables must have the same schema, including a column
+// crdb_replication_origin_timestamp DECIMAL NOT VISIBLE NULL ON UPDATE NULL.
+//
+// Options:
+//    function = '<function>': resolve conflicts with the given function instead of last write wins
+//    dead_letter_table = '<table>': table that rows which fail to apply are written to
+//
+// %SeeAlso: CREATE TENANT
+create_logical_replication_stream_stmt:
+  CREATE LOGICAL REPLICATION STREAM FROM TABLE db_object_name ON string_or_placeholder INTO TABLE db_object_name opt_with_options
+  {
+    $$.val = &tree.CreateLogicalReplicationStream{
+      From: $7.unresolvedObjectName(),
+      PGURL: $9.expr(),
+      Into: $12.unresolvedObjectName(),
+      Options: $13.kvOptions(),
+    }
+  }
+| CREATE LOGICAL REPLICATION STREAM error // SHOW HELP: CREATE LOGICAL REPLICATION STREAM
+
 // %Help: CREATE CHANGEFEED  - create change data capture
 // %Category: CCL
 // %Text:
@@ -5355,16 +5434,16 @@ drop_sequence_stmt:
 
 // %Help: DROP TABLE - remove a table
 // %Category: DDL
-// %Text: DROP TABLE [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
+// %Text: DROP TABLE [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT] [WITH (purge = <bool>)]
 // %SeeAlso: WEBDOCS/drop-table.html
 drop_table_stmt:
-  DROP TABLE table_name_list opt_drop_behavior
+  DROP TABLE table_name_list opt_drop_behavior opt_with_storage_parameter_list
   {
-    $$.val = &tree.DropTable{Names: $3.tableNames(), IfExists: false, DropBehavior: $4.dropBehavior()}
+    $$.val = &tree.DropTable{Names: $3.tableNames(), IfExists: false, DropBehavior: $4.dropBehavior(), StorageParams: $5.storageParams()}
   }
-| DROP TABLE IF EXISTS table_name_list opt_drop_behavior
+| DROP TABLE IF EXISTS table_name_list opt_drop_behavior opt_with_storage_parameter_list
   {
-    $$.val = &tree.DropTable{Names: $5.tableNames(), IfExists: true, DropBehavior: $6.dropBehavior()}
+    $$.val = &tree.DropTable{Names: $5.tableNames(), IfExists: true, DropBehavior: $6.dropBehavior(), StorageParams: $7.storageParams()}
   }
 | DROP TABLE error // SHOW HELP: DROP TABLE
 
@@ -5650,6 +5729,7 @@ preparable_stmt:
   alter_stmt     // help texts in sub-rule
 | backup_stmt    // EXTEND WITH HELP: BACKUP
 | cancel_stmt    // help texts in sub-rule
+| compact_backups_stmt // EXTEND WITH HELP: COMPACT BACKUPS
 | create_stmt    // help texts in sub-rule
 | delete_stmt    // EXTEND WITH HELP: DELETE
 | drop_stmt      // help texts in sub-rule
@@ -5671,6 +5751,7 @@ preparable_stmt:
 | truncate_stmt     // EXTEND WITH HELP: TRUNCATE
 | update_stmt       // EXTEND WITH HELP: UPDATE
 | upsert_stmt       // EXTEND WITH HELP: UPSERT
+| verify_backup_stmt // EXTEND WITH HELP: VERIFY BACKUP
 
 // These are statements that can be used as a data source using the special
 // syntax with brackets. These are a subset of preparable_stmt.
@@ -5710,6 +5791,7 @@ explain_option_list:
 // %Category: CCL
 // %Text:
 // ALTER CHANGEFEED <job_id> {{ADD|DROP <targets...>} | SET <options...>}...
+// ALTER CHANGEFEED <job_id> BACKFILL TABLE <table> [WHERE <expr>] INTO <sink> [WITH <options...>]
 alter_changefeed_stmt:
   ALTER CHANGEFEED a_expr alter_changefeed_cmds
   {
@@ -5758,6 +5840,16 @@ alter_changefeed_cmd:
       Options: $2.nameList(),
     }
   }
+  // ALTER CHANGEFEED <job_id> BACKFILL TABLE ... INTO ...
+| BACKFILL TABLE table_name opt_where_clause INTO string_or_placeholder opt_with_options
+  {
+    $$.val = &tree.AlterChangefeedBackfill{
+      Target:  $3.unresolvedObjectName(),
+      Where:   $4.expr(),
+      SinkURI: $6.expr(),
+      Options: $7.kvOptions(),
+    }
+  }
 
 // %Help: ALTER BACKUP - alter an existing backup's encryption keys
 // %Category: CCL
@@ -5815,6 +5907,131 @@ backup_kms:
     }
 	}
 
+// %Help: COMPACT BACKUPS - merge incremental backups into a single layer
+// %Category: CCL
+// %Text:
+// COMPACT BACKUPS IN <collection...>
+//        [ FROM <timestamp> TO <timestamp> ]
+//        [ WITH <option> [= <value>] [, ...] ]
+//
+// Compacts the incremental backups of the latest backup in the collection
+// whose times lie between FROM and TO, or all of them if omitted.
+//
+// Collection:
+//    "[scheme]://[host]/[path to collection]?[parameters]"
+//
+// Options:
+//    encryption_passphrase="secret": decrypt and encrypt backups
+//    kms="[kms_provider]://[kms_host]/[master_key_identifier]?[parameters]" : decrypt and encrypt backups using KMS
+//    detached: execute compaction job asynchronously, without waiting for its completion
+//    incremental_location: specify the path holding the incremental backups
+//
+// %SeeAlso: BACKUP, WEBDOCS/backup.html
+compact_backups_stmt:
+  COMPACT BACKUPS IN string_or_placeholder_opt_list opt_with_backup_options
+  {
+    $$.val = &tree.CompactBackups{
+      In: $4.stringOrPlaceholderOptList(),
+      Options: *$5.backupOptions(),
+    }
+  }
+| COMPACT BACKUPS IN string_or_placeholder_opt_list FROM string_or_placeholder TO string_or_placeholder opt_with_backup_options
+  {
+    $$.val = &tree.CompactBackups{
+      In: $4.stringOrPlaceholderOptList(),
+      From: $6.expr(),
+      To: $8.expr(),
+      Options: *$9.backupOptions(),
+    }
+  }
+| COMPACT BACKUPS error // SHOW HELP: COMPACT BACKUPS
+
+// %Help: VERIFY BACKUP - verify the integrity of a backup
+// %Category: CCL
+// %Text:
+// VERIFY BACKUP FROM <location>
+// VERIFY BACKUP FROM <subdir> IN <collection...>
+//        [ WITH <option> [= <value>] [, ...] ]
+//
+// Checks that every file of the backup exists, has the size it was written
+// with, is readable and can be decrypted, without restoring it. The files are
+// verified by every node of the cluster.
+//
+// Options:
+//    deep: read every key of every file, verifying all of its checksums and recorded fingerprints
+//    encryption_passphrase="secret": decrypt the backup
+//    kms="[kms_provider]://[kms_host]/[master_key_identifier]?[parameters]" : decrypt the backup using KMS
+//    incremental_location: specify the path holding the incremental backups
+//    encryption_info_dir: specify the full backup directory of an incremental backup
+//
+// %SeeAlso: SHOW BACKUP, WEBDOCS/show-backup.html
+verify_backup_stmt:
+  VERIFY BACKUP FROM string_or_placeholder opt_with_verify_backup_options
+  {
+    $$.val = &tree.VerifyBackup{
+      Path: $4.expr(),
+      Options: *$5.showBackupOptions(),
+    }
+  }
+| VERIFY BACKUP FROM string_or_placeholder IN string_or_placeholder_opt_list opt_with_verify_backup_options
+  {
+    $$.val = &tree.VerifyBackup{
+      Path: $4.expr(),
+      InCollection: $6.stringOrPlaceholderOptList(),
+      Options: *$7.showBackupOptions(),
+    }
+  }
+| VERIFY BACKUP error // SHOW HELP: VERIFY BACKUP
+
+opt_with_verify_backup_options:
+  WITH verify_backup_options_list
+  {
+    $$.val = $2.showBackupOptions()
+  }
+| WITH OPTIONS '(' verify_backup_options_list ')'
+  {
+    $$.val = $4.showBackupOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = &tree.ShowBackupOptions{}
+  }
+
+verify_backup_options_list:
+  // Require at least one option
+  verify_backup_options
+  {
+    $$.val = $1.showBackupOptions()
+  }
+| verify_backup_options_list ',' verify_backup_options
+  {
+    if err := $1.showBackupOptions().CombineWith($3.showBackupOptions()); err != nil {
+      return setErr(sqllex, err)
+    }
+  }
+
+verify_backup_options:
+  DEEP
+  {
+    $$.val = &tree.ShowBackupOptions{Deep: true}
+  }
+| INCREMENTAL_LOCATION '=' string_or_placeholder_opt_list
+  {
+    $$.val = &tree.ShowBackupOptions{IncrementalStorage: $3.stringOrPlaceholderOptList()}
+  }
+| KMS '=' string_or_placeholder_opt_list
+  {
+    $$.val = &tree.ShowBackupOptions{DecryptionKMSURI: $3.stringOrPlaceholderOptList()}
+  }
+| ENCRYPTION_PASSPHRASE '=' string_or_placeholder
+  {
+    $$.val = &tree.ShowBackupOptions{EncryptionPassphrase: $3.expr()}
+  }
+| ENCRYPTION_INFO_DIR '=' string_or_placeholder
+  {
+    $$.val = &tree.ShowBackupOptions{EncryptionInfoDir: $3.expr()}
+  }
+
 // %Help: SHOW TENANT - display tenant information
 // %Category: Experimental
 // %Text:
@@ -6507,6 +6724,11 @@ alter_tenant_service_stmt:
 // ALTER TENANT <tenant_spec> COMPLETE REPLICATION TO LATEST
 // ALTER TENANT <tenant_spec> COMPLETE REPLICATION TO SYSTEM TIME 'time'
 // ALTER TENANT <tenant_spec> SET REPLICATION opt=value,...
+// ALTER TENANT <tenant_spec> START REPLICATION OF <tenant_spec> ON <location> [ WITH OPTIONS ... ]
+//
+// Options for START REPLICATION:
+// RESUME_FROM_PROTECTED_TS: resume replicating into the former source of the
+//   tenant from the data it retained since the tenant was cut over
 alter_tenant_replication_stmt:
   ALTER TENANT tenant_spec PAUSE REPLICATION
   {
@@ -6552,6 +6774,15 @@ alter_tenant_replication_stmt:
       Options: *$6.tenantReplicationOptions(),
     }
   }
+| ALTER TENANT tenant_spec START REPLICATION OF d_expr ON d_expr opt_with_tenant_replication_options
+  {
+    $$.val = &tree.AlterTenantReplication{
+      TenantSpec: $3.tenantSpec(),
+      ReplicationSourceTenantName: &tree.TenantSpec{IsName: true, Expr: $7.expr()},
+      ReplicationSourceAddress: $9.expr(),
+      Options: *$10.tenantReplicationOptions(),
+    }
+  }
 
 
 // %Help: ALTER TENANT CLUSTER SETTING - alter tenant cluster settings
@@ -7317,7 +7548,10 @@ show_histogram_stmt:
 
 // %Help: SHOW BACKUP - list backup contents
 // %Category: CCL
-// %Text: SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// %Text:
+// SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// SHOW BACKUP ROWS FOR TABLE <table> FROM <subdir> IN <location>
+//         [ AS OF SYSTEM TIME <expr> ] [ WHERE <primary key predicate> ]
 // %SeeAlso: WEBDOCS/show-backup.html
 show_backup_stmt:
   SHOW BACKUPS IN string_or_placeholder_opt_list
@@ -7336,6 +7570,19 @@ show_backup_stmt:
 			Options: *$8.showBackupOptions(),
 		}
 	}
+| SHOW BACKUP ROWS FOR TABLE table_name FROM string_or_placeholder IN string_or_placeholder_opt_list opt_as_of_clause opt_where_clause opt_with_show_backup_options
+	{
+		$$.val = &tree.ShowBackup{
+			From:    true,
+			Details:    tree.BackupRowDetails,
+			Table:   $6.unresolvedObjectName(),
+			Path:    $8.expr(),
+			InCollection: $10.stringOrPlaceholderOptList(),
+			AsOf:    $11.asOfClause(),
+			RowFilter: $12.expr(),
+			Options: *$13.showBackupOptions(),
+		}
+	}
 | SHOW BACKUP string_or_placeholder IN string_or_placeholder_opt_list opt_with_show_backup_options
 	{
 		$$.val = &tree.ShowBackup{
@@ -10130,12 +10377,12 @@ sequence_option_elem:
 
 // %Help: TRUNCATE - empty one or more tables
 // %Category: DML
-// %Text: TRUNCATE [TABLE] <tablename> [, ...] [CASCADE | RESTRICT]
+// %Text: TRUNCATE [TABLE] <tablename> [, ...] [CASCADE | RESTRICT] [PURGE]
 // %SeeAlso: WEBDOCS/truncate.html
 truncate_stmt:
-  TRUNCATE opt_table relation_expr_list opt_drop_behavior
+  TRUNCATE opt_table relation_expr_list opt_drop_behavior opt_purge
   {
-    $$.val = &tree.Truncate{Tables: $3.tableNames(), DropBehavior: $4.dropBehavior()}
+    $$.val = &tree.Truncate{Tables: $3.tableNames(), DropBehavior: $4.dropBehavior(), Purge: $5.bool()}
   }
 | TRUNCATE error // SHOW HELP: TRUNCATE
 
@@ -10690,6 +10937,16 @@ opt_concurrently:
     $$.val = false
   }
 
+opt_purge:
+  PURGE
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
 opt_unique:
   UNIQUE
   {
@@ -16239,6 +16496,7 @@ unreserved_keyword:
 | ATTRIBUTE
 | AUTOMATIC
 | AVAILABILITY
+| BACKFILL
 | BACKUP
 | BACKUPS
 | BACKWARD
@@ -16298,6 +16556,7 @@ unreserved_keyword:
 | DEBUG_PAUSE_ON
 | DEBUG_DUMP_METADATA_SST
 | DECLARE
+| DEEP
 | DELETE
 | DEFAULTS
 | DEFERRED
@@ -16336,6 +16595,7 @@ unreserved_keyword:
 | FAILURE
 | FILES
 | FILTER
+| FINGERPRINTS
 | FIRST
 | FOLLOWING
 | FORMAT
@@ -16412,6 +16672,7 @@ unreserved_keyword:
 | LIST
 | LOCAL
 | LOCKED
+| LOGICAL
 | LOGIN
 | LOCALITY
 | LOOKUP
@@ -16507,6 +16768,7 @@ unreserved_keyword:
 | PRIVILEGES
 | PUBLIC
 | PUBLICATION
+| PURGE
 | QUERIES
 | QUERY
 | QUOTE
@@ -16537,6 +16799,7 @@ unreserved_keyword:
 | RESTRICT
 | RESTRICTED
 | RESUME
+| RESUME_FROM_PROTECTED_TS
 | RETENTION
 | RETRY
 | RETURN
@@ -16653,7 +16916,9 @@ unreserved_keyword:
 | VALIDATE
 | VALUE
 | VARYING
+| VERIFY
 | VERIFY_BACKUP_TABLE_DATA
+| VERIFY_FINGERPRINTS
 | VIEW
 | VIEWACTIVITY
 | VIEWACTIVITYREDACTED
@@ -16700,6 +16965,7 @@ bare_label_keywords:
 | AUTHORIZATION
 | AUTOMATIC
 | AVAILABILITY
+| BACKFILL
 | BACKUP
 | BACKUPS
 | BACKWARD
@@ -16783,6 +17049,7 @@ bare_label_keywords:
 | DEC
 | DECIMAL
 | DECLARE
+| DEEP
 | DEFAULT
 | DEFAULTS
 | DEFERRABLE
@@ -16832,6 +17099,7 @@ bare_label_keywords:
 | FALSE
 | FAMILY
 | FILES
+| FINGERPRINTS
 | FIRST
 | FLOAT
 | FOLLOWING
@@ -16941,6 +17209,7 @@ bare_label_keywords:
 | LOCALTIME
 | LOCALTIMESTAMP
 | LOCKED
+| LOGICAL
 | LOGIN
 | LOOKUP
 | LOW
@@ -17047,6 +17316,7 @@ bare_label_keywords:
 | PRIVILEGES
 | PUBLIC
 | PUBLICATION
+| PURGE
 | QUERIES
 | QUERY
 | QUOTE
@@ -17079,6 +17349,7 @@ bare_label_keywords:
 | RESTRICT
 | RESTRICTED
 | RESUME
+| RESUME_FROM_PROTECTED_TS
 | RETENTION
 | RETRY
 | RETURN
@@ -17221,7 +17492,9 @@ bare_label_keywords:
 | VARBIT
 | VARCHAR
 | VARIADIC
+| VERIFY
 | VERIFY_BACKUP_TABLE_DATA
+| VERIFY_FINGERPRINTS
 | VIEW
 | VIEWACTIVITY
 | VIEWACTIVITYREDACTED
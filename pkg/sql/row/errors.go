@@ -44,6 +44,15 @@ func ConvertBatchError(ctx context.Context, tableDesc catalog.TableDescriptor, b
 			pgcode.UnsatisfiableBoundedStaleness,
 		)
 
+	case *kvpb.WriteRateLimitExceededError:
+		// The writes were rejected without taking effect because the range
+		// exceeded the write rate limits of its zone configuration. Retrying
+		// them right away would not succeed, so the client is left to back off.
+		return pgerror.WithCandidateCode(
+			origPErr.GoError(),
+			pgcode.ConfigurationLimitExceeded,
+		)
+
 	case *kvpb.ConditionFailedError:
 		if origPErr.Index == nil {
 			break
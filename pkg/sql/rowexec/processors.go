@@ -261,6 +261,24 @@ func NewProcessor(
 		}
 		return NewBackupDataProcessor(ctx, flowCtx, processorID, *core.BackupData, post)
 	}
+	if core.CompactBackups != nil {
+		if err := checkNumIn(inputs, 0); err != nil {
+			return nil, err
+		}
+		if NewCompactBackupsProcessor == nil {
+			return nil, errors.New("CompactBackups processor unimplemented")
+		}
+		return NewCompactBackupsProcessor(ctx, flowCtx, processorID, *core.CompactBackups, post)
+	}
+	if core.VerifyBackup != nil {
+		if err := checkNumIn(inputs, 0); err != nil {
+			return nil, err
+		}
+		if NewVerifyBackupProcessor == nil {
+			return nil, errors.New("VerifyBackup processor unimplemented")
+		}
+		return NewVerifyBackupProcessor(ctx, flowCtx, processorID, *core.VerifyBackup, post)
+	}
 	if core.SplitAndScatter != nil {
 		if err := checkNumIn(inputs, 0); err != nil {
 			return nil, err
@@ -298,6 +316,15 @@ func NewProcessor(
 		}
 		return NewCSVWriterProcessor(ctx, flowCtx, processorID, *core.Exporter, inputs[0])
 	}
+	if core.ExportIceberg != nil {
+		if err := checkNumIn(inputs, 1); err != nil {
+			return nil, err
+		}
+		if NewExportIcebergProcessor == nil {
+			return nil, errors.New("ExportIceberg processor unimplemented")
+		}
+		return NewExportIcebergProcessor(ctx, flowCtx, processorID, *core.ExportIceberg, inputs[0])
+	}
 
 	if core.BulkRowWriter != nil {
 		if err := checkNumIn(inputs, 1); err != nil {
@@ -407,6 +434,12 @@ var NewCloudStorageTestProcessor func(context.Context, *execinfra.FlowCtx, int32
 // NewBackupDataProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
 var NewBackupDataProcessor func(context.Context, *execinfra.FlowCtx, int32, execinfrapb.BackupDataSpec, *execinfrapb.PostProcessSpec) (execinfra.Processor, error)
 
+// NewCompactBackupsProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
+var NewCompactBackupsProcessor func(context.Context, *execinfra.FlowCtx, int32, execinfrapb.CompactBackupsSpec, *execinfrapb.PostProcessSpec) (execinfra.Processor, error)
+
+// NewVerifyBackupProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
+var NewVerifyBackupProcessor func(context.Context, *execinfra.FlowCtx, int32, execinfrapb.VerifyBackupSpec, *execinfrapb.PostProcessSpec) (execinfra.Processor, error)
+
 // NewSplitAndScatterProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
 var NewSplitAndScatterProcessor func(context.Context, *execinfra.FlowCtx, int32, execinfrapb.SplitAndScatterSpec, *execinfrapb.PostProcessSpec) (execinfra.Processor, error)
 
@@ -422,6 +455,9 @@ var NewCSVWriterProcessor func(context.Context, *execinfra.FlowCtx, int32, execi
 // NewParquetWriterProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
 var NewParquetWriterProcessor func(context.Context, *execinfra.FlowCtx, int32, execinfrapb.ExportSpec, execinfra.RowSource) (execinfra.Processor, error)
 
+// NewExportIcebergProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
+var NewExportIcebergProcessor func(context.Context, *execinfra.FlowCtx, int32, execinfrapb.ExportIcebergSpec, execinfra.RowSource) (execinfra.Processor, error)
+
 // NewChangeAggregatorProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
 var NewChangeAggregatorProcessor func(context.Context, *execinfra.FlowCtx, int32, execinfrapb.ChangeAggregatorSpec, *execinfrapb.PostProcessSpec) (execinfra.Processor, error)
 
@@ -35,6 +35,11 @@ func (e *eventLogState) IncrementSubWorkID() {
 	e.statementMetaData.SubWorkID++
 }
 
+// PurgeDroppedData implements the scbuildstmt.EventLogState interface.
+func (e *eventLogState) PurgeDroppedData() {
+	e.statements[e.statementMetaData.StatementID].PurgeDroppedData = true
+}
+
 // EventLogStateWithNewSourceElementID implements the scbuildstmt.EventLogState
 // interface.
 func (e *eventLogState) EventLogStateWithNewSourceElementID() scbuildstmt.EventLogState {
@@ -115,6 +115,10 @@ type EventLogState interface {
 	// commands.
 	IncrementSubWorkID()
 
+	// PurgeDroppedData marks the current statement as requesting that the data
+	// it drops be cleared without waiting for the GC TTL to expire.
+	PurgeDroppedData()
+
 	// EventLogStateWithNewSourceElementID returns an EventLogState with an
 	// incremented source element ID
 	EventLogStateWithNewSourceElementID() EventLogState
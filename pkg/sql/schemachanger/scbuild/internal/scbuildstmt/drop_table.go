@@ -11,19 +11,34 @@
 package scbuildstmt
 
 import (
+	"context"
+
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
+	"github.com/cockroachdb/errors"
 )
 
 // DropTable implements DROP TABLE.
 func DropTable(b BuildCtx, n *tree.DropTable) {
+	if len(n.StorageParams) > 0 {
+		var params dropTableStorageParams
+		if err := storageparam.Set(b, b.SemaCtx(), b.EvalCtx(), n.StorageParams, &params); err != nil {
+			panic(err)
+		}
+		if params.purge {
+			b.PurgeDroppedData()
+		}
+	}
 	var toCheckBackrefs []catid.DescID
 	droppedOwnedSequences := make(map[catid.DescID]catalog.DescriptorIDSet)
 	for idx := range n.Names {
@@ -93,3 +108,39 @@ func DropTable(b BuildCtx, n *tree.DropTable) {
 			"cannot drop table %s because other objects depend on it", ns.Name))
 	}
 }
+
+// dropTableStorageParams implements storageparam.Setter for the options of
+// DROP TABLE ... WITH (...).
+type dropTableStorageParams struct {
+	// purge is set if the data of the dropped tables should be cleared without
+	// waiting for the GC TTL to expire.
+	purge bool
+}
+
+var _ storageparam.Setter = (*dropTableStorageParams)(nil)
+
+// Set implements the storageparam.Setter interface.
+func (p *dropTableStorageParams) Set(
+	_ context.Context, _ *tree.SemaContext, _ *eval.Context, key string, datum tree.Datum,
+) error {
+	switch key {
+	case "purge":
+		purge, err := paramparse.GetSingleBool(key, datum)
+		if err != nil {
+			return err
+		}
+		p.purge = bool(*purge)
+		return nil
+	}
+	return pgerror.Newf(pgcode.InvalidParameterValue, "invalid storage parameter %q", key)
+}
+
+// Reset implements the storageparam.Setter interface.
+func (p *dropTableStorageParams) Reset(context.Context, *eval.Context, string) error {
+	return errors.AssertionFailedf("non-implemented codepath")
+}
+
+// RunPostChecks implements the storageparam.Setter interface.
+func (p *dropTableStorageParams) RunPostChecks() error {
+	return nil
+}
@@ -120,7 +120,8 @@ func (gj gcJobs) makeRecords(
 
 	// Create GC jobs for all database which are being dropped.
 	// Then create one GC job for all tables being dropped which are
-	// not part of a database being dropped.
+	// not part of a database being dropped, and another one for such
+	// tables whose data should be purged.
 	// Finally, create drop jobs for each index being dropped which is
 	// not part of a table being dropped.
 	gj.sort()
@@ -146,11 +147,12 @@ func (gj gcJobs) makeRecords(
 				mkJobID(), formatStatements(&s), username.NodeUserName(), j, useLegacyJob,
 			))
 	}
-	{
+	for _, purge := range []bool{false, true} {
 		var j jobspb.SchemaChangeGCDetails
 		var s stmts
+		j.Purge = purge
 		for _, t := range gj.tables {
-			if tablesBeingDropped.Contains(t.id) {
+			if tablesBeingDropped.Contains(t.id) || t.statement.Purge != purge {
 				continue
 			}
 			addTableToJob(&s, &j, t)
@@ -26,4 +26,8 @@ type StatementForDropJob struct {
 	// rolling back. This is needed to build the correct description for the
 	// job.
 	Rollback bool
+
+	// Purge is set if the statement requested that the dropped data be cleared
+	// without waiting for the GC TTL to expire.
+	Purge bool
 }
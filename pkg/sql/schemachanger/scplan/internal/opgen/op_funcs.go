@@ -38,6 +38,7 @@ func statementForDropJob(e scpb.Element, md *opGenContext) scop.StatementForDrop
 		Statement:   stmt,
 		StatementID: stmtID,
 		Rollback:    md.InRollback,
+		Purge:       md.Statements[stmtID].PurgeDroppedData && !md.InRollback,
 	}
 }
 
@@ -2378,6 +2378,8 @@ var builtinOidsArray = []string{
 	2405: `ts_rank(weights: float[], vector: tsvector, query: tsquery) -> float4`,
 	2406: `crdb_internal.fingerprint(span: bytes[], stripped: bool) -> int`,
 	2407: `crdb_internal.tenant_span() -> bytes[]`,
+	2408: `crdb_internal.start_replication_stream(tenant_name: string, spec: bytes) -> bytes`,
+	2409: `crdb_internal.start_replication_stream_for_tables(req: bytes) -> bytes`,
 }
 
 var builtinOidsBySignature map[string]oid.Oid
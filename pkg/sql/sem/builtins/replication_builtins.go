@@ -137,7 +137,8 @@ var replicationBuiltins = map[string]builtinDefinition{
 					return nil, err
 				}
 				tenantName := string(tree.MustBeDString(args[0]))
-				replicationProducerSpec, err := mgr.StartReplicationStream(ctx, roachpb.TenantName(tenantName))
+				replicationProducerSpec, err := mgr.StartReplicationStream(ctx,
+					roachpb.TenantName(tenantName), streampb.ReplicationProducerRequest{})
 				if err != nil {
 					return nil, err
 				}
@@ -153,6 +154,78 @@ var replicationBuiltins = map[string]builtinDefinition{
 				"notify that the replication is still ongoing.",
 			Volatility: volatility.Volatile,
 		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "tenant_name", Typ: types.String},
+				{Name: "spec", Typ: types.Bytes},
+			},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				mgr, err := evalCtx.StreamManagerFactory.GetReplicationStreamManager(ctx)
+				if err != nil {
+					return nil, err
+				}
+				tenantName := string(tree.MustBeDString(args[0]))
+				var req streampb.ReplicationProducerRequest
+				if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(args[1])), &req); err != nil {
+					return nil, err
+				}
+				replicationProducerSpec, err := mgr.StartReplicationStream(ctx, roachpb.TenantName(tenantName), req)
+				if err != nil {
+					return nil, err
+				}
+				rawReplicationProducerSpec, err := protoutil.Marshal(&replicationProducerSpec)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDBytes(tree.DBytes(rawReplicationProducerSpec)), err
+			},
+			Info: "This function can be used on the producer side to start a replication stream for " +
+				"the tables of the specified tenant selected by the given " +
+				"ReplicationProducerRequest. The returned stream ID uniquely identifies created stream. " +
+				"The caller must periodically invoke crdb_internal.heartbeat_stream() function to " +
+				"notify that the replication is still ongoing.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
+	"crdb_internal.start_replication_stream_for_tables": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         builtinconstants.CategoryStreamIngestion,
+			Undocumented:     true,
+			DistsqlBlocklist: true,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "req", Typ: types.Bytes},
+			},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				mgr, err := evalCtx.StreamManagerFactory.GetReplicationStreamManager(ctx)
+				if err != nil {
+					return nil, err
+				}
+				var req streampb.ReplicationProducerRequest
+				if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(args[0])), &req); err != nil {
+					return nil, err
+				}
+				replicationProducerSpec, err := mgr.StartReplicationStreamForTables(ctx, req)
+				if err != nil {
+					return nil, err
+				}
+				rawReplicationProducerSpec, err := protoutil.Marshal(&replicationProducerSpec)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDBytes(tree.DBytes(rawReplicationProducerSpec)), err
+			},
+			Info: "This function can be used on the producer side to start a replication stream for " +
+				"the tables of the current tenant named by the given ReplicationProducerRequest, " +
+				"which is consumed by logical replication. The returned stream ID uniquely identifies " +
+				"created stream. The caller must periodically invoke crdb_internal.heartbeat_stream() " +
+				"function to notify that the replication is still ongoing.",
+			Volatility: volatility.Volatile,
+		},
 	),
 
 	"crdb_internal.replication_stream_progress": makeBuiltin(
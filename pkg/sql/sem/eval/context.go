@@ -760,8 +760,21 @@ type StreamManagerFactory interface {
 // on the production side.
 type ReplicationStreamManager interface {
 	// StartReplicationStream starts a stream replication job for the specified
-	// tenant on the producer side.
-	StartReplicationStream(ctx context.Context, tenantName roachpb.TenantName) (streampb.ReplicationProducerSpec, error)
+	// tenant on the producer side. The request may restrict the stream to some
+	// of the tables of the tenant.
+	StartReplicationStream(
+		ctx context.Context,
+		tenantName roachpb.TenantName,
+		req streampb.ReplicationProducerRequest,
+	) (streampb.ReplicationProducerSpec, error)
+
+	// StartReplicationStreamForTables starts a stream replication job on the
+	// producer side for the tables of the current tenant named by the request,
+	// which is consumed by logical replication.
+	StartReplicationStreamForTables(
+		ctx context.Context,
+		req streampb.ReplicationProducerRequest,
+	) (streampb.ReplicationProducerSpec, error)
 
 	// HeartbeatReplicationStream sends a heartbeat to the replication stream producer, indicating
 	// consumer has consumed until the given 'frontier' timestamp. This updates the producer job
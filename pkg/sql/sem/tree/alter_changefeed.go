@@ -50,11 +50,13 @@ func (*AlterChangefeedAddTarget) alterChangefeedCmd()    {}
 func (*AlterChangefeedDropTarget) alterChangefeedCmd()   {}
 func (*AlterChangefeedSetOptions) alterChangefeedCmd()   {}
 func (*AlterChangefeedUnsetOptions) alterChangefeedCmd() {}
+func (*AlterChangefeedBackfill) alterChangefeedCmd()     {}
 
 var _ AlterChangefeedCmd = &AlterChangefeedAddTarget{}
 var _ AlterChangefeedCmd = &AlterChangefeedDropTarget{}
 var _ AlterChangefeedCmd = &AlterChangefeedSetOptions{}
 var _ AlterChangefeedCmd = &AlterChangefeedUnsetOptions{}
+var _ AlterChangefeedCmd = &AlterChangefeedBackfill{}
 
 // AlterChangefeedAddTarget represents an ADD <targets> command
 type AlterChangefeedAddTarget struct {
@@ -104,3 +106,28 @@ func (node *AlterChangefeedUnsetOptions) Format(ctx *FmtCtx) {
 	ctx.WriteString(" UNSET ")
 	ctx.FormatNode(&node.Options)
 }
+
+// AlterChangefeedBackfill represents a BACKFILL TABLE <table> [WHERE <expr>]
+// INTO <sink> command.
+type AlterChangefeedBackfill struct {
+	Target  *UnresolvedObjectName
+	Where   Expr
+	SinkURI Expr
+	Options KVOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterChangefeedBackfill) Format(ctx *FmtCtx) {
+	ctx.WriteString(" BACKFILL TABLE ")
+	ctx.FormatNode(node.Target)
+	if node.Where != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Where)
+	}
+	ctx.WriteString(" INTO ")
+	ctx.FormatNode(node.SinkURI)
+	if node.Options != nil {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
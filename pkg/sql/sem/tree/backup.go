@@ -47,6 +47,7 @@ type BackupOptions struct {
 	EncryptionKMSURI           StringOrPlaceholderOptList
 	IncrementalStorage         StringOrPlaceholderOptList
 	ExecutionLocality          Expr
+	Fingerprints               bool
 }
 
 var _ NodeFormatter = &BackupOptions{}
@@ -145,6 +146,7 @@ type RestoreOptions struct {
 	SchemaOnly                       bool
 	VerifyData                       bool
 	UnsafeRestoreIncompatibleVersion bool
+	VerifyFingerprints               bool
 }
 
 var _ NodeFormatter = &RestoreOptions{}
@@ -169,6 +171,12 @@ type Restore struct {
 	// ... FROM 'from' IN 'subdir'...`. Alternatively, restore_planning.go will set
 	// it for the query `RESTORE ... FROM 'from' IN LATEST...`
 	Subdir Expr
+
+	// RowFilter, if set, restricts the restore of a single table to the rows
+	// matching a predicate on its primary key, which are restored into a new
+	// table named IntoTable.
+	RowFilter Expr
+	IntoTable Name
 }
 
 var _ Statement = &Restore{}
@@ -195,6 +203,12 @@ func (node *Restore) Format(ctx *FmtCtx) {
 		ctx.WriteString(" ")
 		ctx.FormatNode(&node.AsOf)
 	}
+	if node.RowFilter != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.RowFilter)
+		ctx.WriteString(" INTO ")
+		ctx.FormatNode(&node.IntoTable)
+	}
 	if !node.Options.IsDefault() {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)
@@ -309,6 +323,11 @@ func (o *BackupOptions) Format(ctx *FmtCtx) {
 		ctx.WriteString("include_all_secondary_tenants = ")
 		ctx.FormatNode(o.IncludeAllSecondaryTenants)
 	}
+
+	if o.Fingerprints {
+		maybeAddSep()
+		ctx.WriteString("fingerprints")
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -362,6 +381,14 @@ func (o *BackupOptions) CombineWith(other *BackupOptions) error {
 		o.IncludeAllSecondaryTenants = other.IncludeAllSecondaryTenants
 	}
 
+	if o.Fingerprints {
+		if other.Fingerprints {
+			return errors.New("fingerprints option specified multiple times")
+		}
+	} else {
+		o.Fingerprints = other.Fingerprints
+	}
+
 	return nil
 }
 
@@ -374,7 +401,8 @@ func (o BackupOptions) IsDefault() bool {
 		o.EncryptionPassphrase == options.EncryptionPassphrase &&
 		cmp.Equal(o.IncrementalStorage, options.IncrementalStorage) &&
 		o.ExecutionLocality == options.ExecutionLocality &&
-		o.IncludeAllSecondaryTenants == options.IncludeAllSecondaryTenants
+		o.IncludeAllSecondaryTenants == options.IncludeAllSecondaryTenants &&
+		o.Fingerprints == options.Fingerprints
 }
 
 // Format implements the NodeFormatter interface.
@@ -492,6 +520,11 @@ func (o *RestoreOptions) Format(ctx *FmtCtx) {
 		maybeAddSep()
 		ctx.WriteString("unsafe_restore_incompatible_version")
 	}
+
+	if o.VerifyFingerprints {
+		maybeAddSep()
+		ctx.WriteString("verify_fingerprints")
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -632,6 +665,14 @@ func (o *RestoreOptions) CombineWith(other *RestoreOptions) error {
 		o.UnsafeRestoreIncompatibleVersion = other.UnsafeRestoreIncompatibleVersion
 	}
 
+	if o.VerifyFingerprints {
+		if other.VerifyFingerprints {
+			return errors.New("verify_fingerprints option specified multiple times")
+		}
+	} else {
+		o.VerifyFingerprints = other.VerifyFingerprints
+	}
+
 	return nil
 }
 
@@ -656,7 +697,8 @@ func (o RestoreOptions) IsDefault() bool {
 		o.SchemaOnly == options.SchemaOnly &&
 		o.VerifyData == options.VerifyData &&
 		o.IncludeAllSecondaryTenants == options.IncludeAllSecondaryTenants &&
-		o.UnsafeRestoreIncompatibleVersion == options.UnsafeRestoreIncompatibleVersion
+		o.UnsafeRestoreIncompatibleVersion == options.UnsafeRestoreIncompatibleVersion &&
+		o.VerifyFingerprints == options.VerifyFingerprints
 }
 
 // BackupTargetList represents a list of targets.
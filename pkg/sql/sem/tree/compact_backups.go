@@ -0,0 +1,43 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// CompactBackups represents a COMPACT BACKUPS statement.
+type CompactBackups struct {
+	// In contains the locations of the collection holding the backup chain to
+	// compact.
+	In StringOrPlaceholderOptList
+	// From and To optionally bound the times of the incremental backups to
+	// compact. Both are nil if all incremental backups are compacted.
+	From Expr
+	To   Expr
+	// Options holds the encryption and incremental location of the backup
+	// chain, and whether the job is detached.
+	Options BackupOptions
+}
+
+var _ Statement = &CompactBackups{}
+
+// Format implements the NodeFormatter interface.
+func (node *CompactBackups) Format(ctx *FmtCtx) {
+	ctx.WriteString("COMPACT BACKUPS IN ")
+	ctx.FormatNode(&node.In)
+	if node.From != nil {
+		ctx.WriteString(" FROM ")
+		ctx.FormatNode(node.From)
+		ctx.WriteString(" TO ")
+		ctx.FormatNode(node.To)
+	}
+	if !node.Options.IsDefault() {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
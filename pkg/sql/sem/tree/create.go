@@ -2207,6 +2207,14 @@ type CreateTenantFromReplication struct {
 // TenantReplicationOptions  options for the CREATE TENANT FROM REPLICATION command.
 type TenantReplicationOptions struct {
 	Retention Expr
+	// Tables and Databases, if set, restrict the replication stream to the
+	// named tables and databases of the source tenant.
+	Tables    StringOrPlaceholderOptList
+	Databases StringOrPlaceholderOptList
+	// ResumeFromProtectedTimestamp, if set, resumes the replication of a
+	// tenant into its former source from the data the source retained, rather
+	// than from an initial scan.
+	ResumeFromProtectedTimestamp bool
 }
 
 var _ NodeFormatter = &TenantReplicationOptions{}
@@ -2236,10 +2244,32 @@ func (node *CreateTenantFromReplication) Format(ctx *FmtCtx) {
 
 // Format implements the NodeFormatter interface
 func (o *TenantReplicationOptions) Format(ctx *FmtCtx) {
+	var addSep bool
+	maybeAddSep := func() {
+		if addSep {
+			ctx.WriteString(", ")
+		}
+		addSep = true
+	}
 	if o.Retention != nil {
+		maybeAddSep()
 		ctx.WriteString("RETENTION = ")
 		ctx.FormatNode(o.Retention)
 	}
+	if o.Tables != nil {
+		maybeAddSep()
+		ctx.WriteString("TABLES = ")
+		ctx.FormatNode(&o.Tables)
+	}
+	if o.Databases != nil {
+		maybeAddSep()
+		ctx.WriteString("DATABASES = ")
+		ctx.FormatNode(&o.Databases)
+	}
+	if o.ResumeFromProtectedTimestamp {
+		maybeAddSep()
+		ctx.WriteString("RESUME_FROM_PROTECTED_TS")
+	}
 }
 
 // CombineWith merges other TenantReplicationOptions into this struct.
@@ -2252,13 +2282,40 @@ func (o *TenantReplicationOptions) CombineWith(other *TenantReplicationOptions)
 	} else {
 		o.Retention = other.Retention
 	}
+
+	if o.Tables != nil {
+		if other.Tables != nil {
+			return errors.New("TABLES option specified multiple times")
+		}
+	} else {
+		o.Tables = other.Tables
+	}
+
+	if o.Databases != nil {
+		if other.Databases != nil {
+			return errors.New("DATABASES option specified multiple times")
+		}
+	} else {
+		o.Databases = other.Databases
+	}
+
+	if o.ResumeFromProtectedTimestamp {
+		if other.ResumeFromProtectedTimestamp {
+			return errors.New("RESUME_FROM_PROTECTED_TS option specified multiple times")
+		}
+	} else {
+		o.ResumeFromProtectedTimestamp = other.ResumeFromProtectedTimestamp
+	}
 	return nil
 }
 
 // IsDefault returns true if this backup options struct has default value.
 func (o TenantReplicationOptions) IsDefault() bool {
 	options := TenantReplicationOptions{}
-	return o.Retention == options.Retention
+	return o.Retention == options.Retention &&
+		o.Tables == nil &&
+		o.Databases == nil &&
+		!o.ResumeFromProtectedTimestamp
 }
 
 type SuperRegion struct {
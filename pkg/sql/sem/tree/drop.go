@@ -88,6 +88,9 @@ type DropTable struct {
 	Names        TableNames
 	IfExists     bool
 	DropBehavior DropBehavior
+	// StorageParams are the options specified in the WITH clause, e.g.
+	// WITH (purge = true).
+	StorageParams StorageParams
 }
 
 // Format implements the NodeFormatter interface.
@@ -101,6 +104,11 @@ func (node *DropTable) Format(ctx *FmtCtx) {
 		ctx.WriteByte(' ')
 		ctx.WriteString(node.DropBehavior.String())
 	}
+	if node.StorageParams != nil {
+		ctx.WriteString(" WITH (")
+		ctx.FormatNode(&node.StorageParams)
+		ctx.WriteByte(')')
+	}
 }
 
 // DropView represents a DROP VIEW statement.
@@ -0,0 +1,39 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// CreateLogicalReplicationStream represents a CREATE LOGICAL REPLICATION
+// STREAM statement.
+type CreateLogicalReplicationStream struct {
+	// From is the table of the source cluster whose changes are replicated.
+	From *UnresolvedObjectName
+	// PGURL is the address of the source cluster.
+	PGURL Expr
+	// Into is the table of this cluster that the changes are applied to.
+	Into    *UnresolvedObjectName
+	Options KVOptions
+}
+
+var _ Statement = &CreateLogicalReplicationStream{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateLogicalReplicationStream) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE LOGICAL REPLICATION STREAM FROM TABLE ")
+	ctx.FormatNode(node.From)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.PGURL)
+	ctx.WriteString(" INTO TABLE ")
+	ctx.FormatNode(node.Into)
+	if node.Options != nil {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
@@ -93,6 +93,8 @@ const (
 	BackupValidateDetails
 	// BackupConnectionTest identifies a SHOW BACKUP CONNECTION statement
 	BackupConnectionTest
+	// BackupRowDetails identifies a SHOW BACKUP ROWS statement.
+	BackupRowDetails
 )
 
 // TODO (msbutler): 22.2 after removing old style show backup syntax, rename
@@ -107,6 +109,12 @@ type ShowBackup struct {
 	From         bool
 	Details      ShowBackupDetails
 	Options      ShowBackupOptions
+
+	// Table, AsOf and RowFilter are only set for SHOW BACKUP ROWS, which shows
+	// the rows of Table matching RowFilter as of AsOf.
+	Table     *UnresolvedObjectName
+	AsOf      AsOfClause
+	RowFilter Expr
 }
 
 // Format implements the NodeFormatter interface.
@@ -127,6 +135,10 @@ func (node *ShowBackup) Format(ctx *FmtCtx) {
 		ctx.WriteString("SCHEMAS ")
 	case BackupConnectionTest:
 		ctx.WriteString("CONNECTION ")
+	case BackupRowDetails:
+		ctx.WriteString("ROWS FOR TABLE ")
+		ctx.FormatNode(node.Table)
+		ctx.WriteString(" ")
 	}
 
 	if node.From {
@@ -138,6 +150,14 @@ func (node *ShowBackup) Format(ctx *FmtCtx) {
 		ctx.WriteString(" IN ")
 		ctx.FormatNode(&node.InCollection)
 	}
+	if node.AsOf.Expr != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(&node.AsOf)
+	}
+	if node.RowFilter != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.RowFilter)
+	}
 	if !node.Options.IsDefault() {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)
@@ -167,6 +187,9 @@ type ShowBackupOptions struct {
 	CheckConnectionTransferSize Expr
 	CheckConnectionDuration     Expr
 	CheckConnectionConcurrency  Expr
+
+	// Deep is only used in VERIFY BACKUP.
+	Deep bool
 }
 
 var _ NodeFormatter = &ShowBackupOptions{}
@@ -242,6 +265,11 @@ func (o *ShowBackupOptions) Format(ctx *FmtCtx) {
 		ctx.WriteString("TRANSFER = ")
 		ctx.FormatNode(o.CheckConnectionTransferSize)
 	}
+
+	if o.Deep {
+		maybeAddSep()
+		ctx.WriteString("deep")
+	}
 }
 
 func (o ShowBackupOptions) IsDefault() bool {
@@ -257,7 +285,8 @@ func (o ShowBackupOptions) IsDefault() bool {
 		o.EncryptionInfoDir == options.EncryptionInfoDir &&
 		o.CheckConnectionTransferSize == options.CheckConnectionTransferSize &&
 		o.CheckConnectionDuration == options.CheckConnectionDuration &&
-		o.CheckConnectionConcurrency == options.CheckConnectionConcurrency
+		o.CheckConnectionConcurrency == options.CheckConnectionConcurrency &&
+		o.Deep == options.Deep
 }
 
 func combineBools(v1 bool, v2 bool, label string) (bool, error) {
@@ -351,6 +380,11 @@ func (o *ShowBackupOptions) CombineWith(other *ShowBackupOptions) error {
 		return err
 	}
 
+	o.Deep, err = combineBools(o.Deep, other.Deep, "deep")
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
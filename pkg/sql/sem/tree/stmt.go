@@ -175,7 +175,7 @@ func CanWriteData(stmt Statement) bool {
 	case *Split, *Unsplit, *Relocate, *RelocateRange, *Scatter:
 		return true
 	// Replication operations.
-	case *CreateTenantFromReplication, *AlterTenantReplication:
+	case *CreateTenantFromReplication, *AlterTenantReplication, *CreateLogicalReplicationStream:
 		return true
 	}
 	return false
@@ -215,9 +215,12 @@ type CCLOnlyStatement interface {
 var _ CCLOnlyStatement = &AlterBackup{}
 var _ CCLOnlyStatement = &AlterBackupSchedule{}
 var _ CCLOnlyStatement = &Backup{}
+var _ CCLOnlyStatement = &CompactBackups{}
 var _ CCLOnlyStatement = &ShowBackup{}
 var _ CCLOnlyStatement = &Restore{}
+var _ CCLOnlyStatement = &VerifyBackup{}
 var _ CCLOnlyStatement = &CreateChangefeed{}
+var _ CCLOnlyStatement = &CreateLogicalReplicationStream{}
 var _ CCLOnlyStatement = &AlterChangefeed{}
 var _ CCLOnlyStatement = &Import{}
 var _ CCLOnlyStatement = &Export{}
@@ -752,6 +755,17 @@ func (*CommentOnTable) StatementType() StatementType { return TypeDDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*CommentOnTable) StatementTag() string { return "COMMENT ON TABLE" }
 
+// StatementReturnType implements the Statement interface.
+func (*CompactBackups) StatementReturnType() StatementReturnType { return Rows }
+
+// StatementType implements the Statement interface.
+func (*CompactBackups) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CompactBackups) StatementTag() string { return "COMPACT BACKUPS" }
+
+func (*CompactBackups) cclOnlyStatement() {}
+
 // StatementReturnType implements the Statement interface.
 func (*CommitTransaction) StatementReturnType() StatementReturnType { return Ack }
 
@@ -844,6 +858,19 @@ func (*CreateTenant) StatementType() StatementType { return TypeDCL }
 // StatementTag returns a short string identifying the type of statement.
 func (*CreateTenant) StatementTag() string { return "CREATE TENANT" }
 
+// StatementReturnType implements the Statement interface.
+func (*CreateLogicalReplicationStream) StatementReturnType() StatementReturnType { return Rows }
+
+// StatementType implements the Statement interface.
+func (*CreateLogicalReplicationStream) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateLogicalReplicationStream) StatementTag() string {
+	return "CREATE LOGICAL REPLICATION STREAM"
+}
+
+func (*CreateLogicalReplicationStream) cclOnlyStatement() {}
+
 // StatementReturnType implements the Statement interface.
 func (*CreateTenantFromReplication) StatementReturnType() StatementReturnType { return Rows }
 
@@ -2057,6 +2084,17 @@ func (*ValuesClause) StatementType() StatementType { return TypeDML }
 // StatementTag returns a short string identifying the type of statement.
 func (*ValuesClause) StatementTag() string { return "VALUES" }
 
+// StatementReturnType implements the Statement interface.
+func (*VerifyBackup) StatementReturnType() StatementReturnType { return Rows }
+
+// StatementType implements the Statement interface.
+func (*VerifyBackup) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*VerifyBackup) StatementTag() string { return "VERIFY BACKUP" }
+
+func (*VerifyBackup) cclOnlyStatement() {}
+
 // StatementReturnType implements the Statement interface.
 func (*CreateFunction) StatementReturnType() StatementReturnType { return DDL }
 
@@ -2197,6 +2235,7 @@ func (n *CommentOnSchema) String() string                     { return AsString(
 func (n *CommentOnIndex) String() string                      { return AsString(n) }
 func (n *CommentOnTable) String() string                      { return AsString(n) }
 func (n *CommitTransaction) String() string                   { return AsString(n) }
+func (n *CompactBackups) String() string                      { return AsString(n) }
 func (n *CopyFrom) String() string                            { return AsString(n) }
 func (n *CopyTo) String() string                              { return AsString(n) }
 func (n *CreateChangefeed) String() string                    { return AsString(n) }
@@ -2204,6 +2243,7 @@ func (n *CreateDatabase) String() string                      { return AsString(
 func (n *CreateExtension) String() string                     { return AsString(n) }
 func (n *CreateFunction) String() string                      { return AsString(n) }
 func (n *CreateIndex) String() string                         { return AsString(n) }
+func (n *CreateLogicalReplicationStream) String() string      { return AsString(n) }
 func (n *CreateRole) String() string                          { return AsString(n) }
 func (n *CreateTable) String() string                         { return AsString(n) }
 func (n *CreateTenant) String() string                        { return AsString(n) }
@@ -2331,3 +2371,4 @@ func (n *UnionClause) String() string                         { return AsString(
 func (n *Unsplit) String() string                             { return AsString(n) }
 func (n *Update) String() string                              { return AsString(n) }
 func (n *ValuesClause) String() string                        { return AsString(n) }
+func (n *VerifyBackup) String() string                        { return AsString(n) }
@@ -23,6 +23,9 @@ package tree
 type Truncate struct {
 	Tables       TableNames
 	DropBehavior DropBehavior
+	// Purge is set if the data of the truncated tables should be cleared
+	// without waiting for the GC TTL to expire.
+	Purge bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -38,4 +41,7 @@ func (node *Truncate) Format(ctx *FmtCtx) {
 		ctx.WriteByte(' ')
 		ctx.WriteString(node.DropBehavior.String())
 	}
+	if node.Purge {
+		ctx.WriteString(" PURGE")
+	}
 }
@@ -0,0 +1,38 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// VerifyBackup represents a VERIFY BACKUP statement.
+type VerifyBackup struct {
+	// Path is the location of the backup, or its subdirectory in InCollection
+	// if InCollection is set.
+	Path         Expr
+	InCollection StringOrPlaceholderOptList
+	// Options holds the encryption and incremental location of the backup, and
+	// whether the verification is deep.
+	Options ShowBackupOptions
+}
+
+var _ Statement = &VerifyBackup{}
+
+// Format implements the NodeFormatter interface.
+func (node *VerifyBackup) Format(ctx *FmtCtx) {
+	ctx.WriteString("VERIFY BACKUP FROM ")
+	ctx.FormatNode(node.Path)
+	if node.InCollection != nil {
+		ctx.WriteString(" IN ")
+		ctx.FormatNode(&node.InCollection)
+	}
+	if !node.Options.IsDefault() {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
@@ -1029,6 +1029,22 @@ func (n *AlterTenantReplication) walkStmt(v Visitor) Statement {
 			ret.Cutover.Timestamp = e
 		}
 	}
+	if n.ReplicationSourceAddress != nil {
+		e, changed := WalkExpr(v, n.ReplicationSourceTenantName.Expr)
+		if changed {
+			if ret == n {
+				ret = n.copyNode()
+			}
+			ret.ReplicationSourceTenantName = &TenantSpec{IsName: true, Expr: e}
+		}
+		e, changed = WalkExpr(v, n.ReplicationSourceAddress)
+		if changed {
+			if ret == n {
+				ret = n.copyNode()
+			}
+			ret.ReplicationSourceAddress = e
+		}
+	}
 	if n.Options.Retention != nil {
 		e, changed := WalkExpr(v, n.Options.Retention)
 		if changed {
@@ -1499,6 +1515,15 @@ func (stmt *Restore) walkStmt(v Visitor) Statement {
 			ret.AsOf.Expr = e
 		}
 	}
+	if stmt.RowFilter != nil {
+		e, changed := WalkExpr(v, stmt.RowFilter)
+		if changed {
+			if ret == stmt {
+				ret = stmt.copyNode()
+			}
+			ret.RowFilter = e
+		}
+	}
 	for i, backup := range stmt.From {
 		for j, expr := range backup {
 			e, changed := WalkExpr(v, expr)
@@ -17,6 +17,7 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -114,6 +115,25 @@ func init() {
 			requiredType: types.Int,
 			setter:       func(c *zonepb.ZoneConfig, d tree.Datum) { c.NumVoters = proto.Int32(int32(tree.MustBeDInt(d))) },
 		},
+		{
+			field:        config.NumWitnesses,
+			requiredType: types.Int,
+			setter:       func(c *zonepb.ZoneConfig, d tree.Datum) { c.NumWitnesses = proto.Int32(int32(tree.MustBeDInt(d))) },
+		},
+		{
+			field:        config.RangeMaxWriteRequestsPerSecond,
+			requiredType: types.Int,
+			setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+				c.RangeMaxWriteRequestsPerSecond = proto.Int64(int64(tree.MustBeDInt(d)))
+			},
+		},
+		{
+			field:        config.RangeMaxWriteBytesPerSecond,
+			requiredType: types.Int,
+			setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+				c.RangeMaxWriteBytesPerSecond = proto.Int64(int64(tree.MustBeDInt(d)))
+			},
+		},
 		{
 			field:        config.GCTTL,
 			requiredType: types.Int,
@@ -743,6 +763,14 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 				return err
 			}
 
+			// Nodes running older binaries can't handle witness replicas.
+			if finalZone.NumWitnesses != nil && *finalZone.NumWitnesses > 0 &&
+				!params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.V23_2_Witnesses) {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"num_witnesses is not supported until upgrade to version %v is finalized",
+					clusterversion.ByKey(clusterversion.V23_2_Witnesses))
+			}
+
 			if err := validateZoneAttrsAndLocalities(
 				params.ctx, params.p.InternalSQLTxn().Regions(), params.p.ExecCfg(), &newZone,
 			); err != nil {
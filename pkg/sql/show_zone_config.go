@@ -226,6 +226,18 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (string, e
 		maybeWriteComma(f)
 		f.Printf("\tnum_voters = %d", *zone.NumVoters)
 	}
+	if zone.NumWitnesses != nil {
+		maybeWriteComma(f)
+		f.Printf("\tnum_witnesses = %d", *zone.NumWitnesses)
+	}
+	if zone.RangeMaxWriteRequestsPerSecond != nil {
+		maybeWriteComma(f)
+		f.Printf("\trange_max_write_requests_per_second = %d", *zone.RangeMaxWriteRequestsPerSecond)
+	}
+	if zone.RangeMaxWriteBytesPerSecond != nil {
+		maybeWriteComma(f)
+		f.Printf("\trange_max_write_bytes_per_second = %d", *zone.RangeMaxWriteBytesPerSecond)
+	}
 	if !zone.InheritedConstraints {
 		maybeWriteComma(f)
 		f.Printf("\tconstraints = %s", lexbase.EscapeSQLString(constraints))
@@ -454,6 +454,28 @@ var tableParams = map[string]tableParam{
 			return nil
 		},
 	},
+	catpb.ChangefeedMaskTableSettingName: {
+		onSet: func(ctx context.Context, po *Setter, semaCtx *tree.SemaContext,
+			evalCtx *eval.Context, key string, datum tree.Datum) error {
+			stringVal, err := paramparse.DatumAsString(ctx, evalCtx, key, datum)
+			if err != nil {
+				return err
+			}
+			masks, err := catpb.ParseChangefeedMasks(stringVal)
+			if err != nil {
+				return pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+			}
+			if err := tabledesc.ValidateChangefeedMasks(po.TableDesc, masks); err != nil {
+				return err
+			}
+			po.TableDesc.ChangefeedMask = catpb.FormatChangefeedMasks(masks)
+			return nil
+		},
+		onReset: func(_ context.Context, po *Setter, evalCtx *eval.Context, key string) error {
+			po.TableDesc.ChangefeedMask = ""
+			return nil
+		},
+	},
 	catpb.AutoStatsEnabledTableSettingName: {
 		onSet:   autoStatsEnabledSettingFunc,
 		onReset: autoStatsTableSettingResetFunc,
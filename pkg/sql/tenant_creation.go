@@ -143,18 +143,31 @@ func (p *planner) createTenantInternal(
 	tid = roachpb.MustMakeTenantID(tenantID)
 
 	// Initialize the tenant's keyspace.
+	if err := InitializeTenantKeyspace(ctx, p, tid, initialTenantZoneConfig); err != nil {
+		return tid, err
+	}
+	return tid, nil
+}
+
+// InitializeTenantKeyspace writes the initial values of the keyspace of the
+// given tenant, whose record was just created, such as the descriptors of its
+// system tables, and splits off the ranges of these tables.
+func InitializeTenantKeyspace(
+	ctx context.Context,
+	p PlanHookState,
+	tenantID roachpb.TenantID,
+	initialTenantZoneConfig *zonepb.ZoneConfig,
+) error {
 	var tenantVersion clusterversion.ClusterVersion
-	codec := keys.MakeSQLCodec(roachpb.MustMakeTenantID(tenantID))
-	var kvs []roachpb.KeyValue
-	var splits []roachpb.RKey
+	codec := keys.MakeSQLCodec(tenantID)
 
 	var bootstrapVersionOverride clusterversion.Key
-	if p.EvalContext().TestingKnobs.TenantLogicalVersionKeyOverride != 0 {
+	if p.ExtendedEvalContext().TestingKnobs.TenantLogicalVersionKeyOverride != 0 {
 		// An override was passed using testing knobs. Bootstrap the cluster
 		// using this override.
-		tenantVersion.Version = clusterversion.ByKey(p.EvalContext().TestingKnobs.TenantLogicalVersionKeyOverride)
-		bootstrapVersionOverride = p.EvalContext().TestingKnobs.TenantLogicalVersionKeyOverride
-	} else if !p.EvalContext().Settings.Version.IsActive(ctx, clusterversion.BinaryVersionKey) {
+		tenantVersion.Version = clusterversion.ByKey(p.ExtendedEvalContext().TestingKnobs.TenantLogicalVersionKeyOverride)
+		bootstrapVersionOverride = p.ExtendedEvalContext().TestingKnobs.TenantLogicalVersionKeyOverride
+	} else if !p.ExtendedEvalContext().Settings.Version.IsActive(ctx, clusterversion.BinaryVersionKey) {
 		// The cluster is not running the latest version.
 		// Use the previous major version to create the tenant and bootstrap it
 		// just like the previous major version binary would, using hardcoded
@@ -175,9 +188,9 @@ func (p *planner) createTenantInternal(
 		OverrideKey:             bootstrapVersionOverride,
 		Codec:                   codec,
 	}
-	kvs, splits, err = initialValuesOpts.GetInitialValuesCheckForOverrides()
+	kvs, splits, err := initialValuesOpts.GetInitialValuesCheckForOverrides()
 	if err != nil {
-		return tid, err
+		return err
 	}
 
 	{
@@ -190,7 +203,7 @@ func (p *planner) createTenantInternal(
 		// been upgraded.
 		tenantSettingKV, err := generateTenantClusterSettingKV(codec, tenantVersion)
 		if err != nil {
-			return tid, err
+			return err
 		}
 		kvs = append(kvs, tenantSettingKV)
 	}
@@ -201,10 +214,10 @@ func (p *planner) createTenantInternal(
 	}
 	if err := p.Txn().Run(ctx, b); err != nil {
 		if errors.HasType(err, (*kvpb.ConditionFailedError)(nil)) {
-			return tid, errors.Wrap(err, "programming error: "+
+			return errors.Wrap(err, "programming error: "+
 				"tenant already exists but was not in system.tenants table")
 		}
-		return tid, err
+		return err
 	}
 
 	// Create initial splits for the new tenant. This is performed
@@ -223,11 +236,11 @@ func (p *planner) createTenantInternal(
 	expTime := p.ExecCfg().Clock.Now().Add(time.Hour.Nanoseconds(), 0)
 	for _, key := range splits {
 		if err := p.ExecCfg().DB.AdminSplit(ctx, key, expTime); err != nil {
-			return tid, err
+			return err
 		}
 	}
 
-	return tid, nil
+	return nil
 }
 
 // CreateTenantRecord creates a tenant in system.tenants and installs an initial
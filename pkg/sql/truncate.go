@@ -123,7 +123,7 @@ func (t *truncateNode) startExec(params runParams) error {
 	}
 
 	for id, name := range toTruncate {
-		if err := p.truncateTable(ctx, id, tree.AsStringWithFQNames(t.n, params.Ann())); err != nil {
+		if err := p.truncateTable(ctx, id, tree.AsStringWithFQNames(t.n, params.Ann()), n.Purge); err != nil {
 			return err
 		}
 
@@ -159,8 +159,12 @@ var PreservedSplitCountMultiple = settings.RegisterIntSetting(
 // truncateTable truncates the data of a table in a single transaction. It does
 // so by dropping all existing indexes on the table and creating new ones without
 // backfilling any data into the new indexes. The old indexes are cleaned up
-// asynchronously by the SchemaChangeGCJob.
-func (p *planner) truncateTable(ctx context.Context, id descpb.ID, jobDesc string) error {
+// asynchronously by the SchemaChangeGCJob. If purge is set, the GC job clears
+// the data of the old indexes without waiting for the GC TTL to expire, when it
+// is safe to do so.
+func (p *planner) truncateTable(
+	ctx context.Context, id descpb.ID, jobDesc string, purge bool,
+) error {
 	// Read the table descriptor because it might have changed
 	// while another table in the truncation list was truncated.
 	tableDesc, err := p.Descriptors().MutableByID(p.txn).Table(ctx, id)
@@ -254,6 +258,7 @@ func (p *planner) truncateTable(ctx context.Context, id descpb.ID, jobDesc strin
 	details := jobspb.SchemaChangeGCDetails{
 		Indexes:  droppedIndexes,
 		ParentID: tableDesc.ID,
+		Purge:    purge,
 	}
 	record := CreateGCJobRecord(
 		jobDesc, p.User(), details,
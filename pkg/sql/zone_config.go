@@ -337,6 +337,23 @@ func GetHydratedZoneConfigForTable(
 	return zone, nil
 }
 
+// GetZoneIDForTable returns the ID of the zone whose configuration applies to
+// the given table ID: the table itself if it has a zone configuration, or else
+// its database or the default zone. Zone configurations which only exist to
+// hold subzones are skipped.
+func GetZoneIDForTable(
+	ctx context.Context, txn *kv.Txn, descriptors *descs.Collection, id descpb.ID,
+) (descpb.ID, error) {
+	zcHelper := descs.AsZoneConfigHydrationHelper(descriptors)
+	zoneID, _, _, _, err := getZoneConfig(
+		ctx, id, txn, zcHelper, false /* getInheritedDefault */, true, /* mayBeTable */
+	)
+	if err != nil {
+		return 0, err
+	}
+	return zoneID, nil
+}
+
 // GetHydratedZoneConfigForDatabase returns a fully hydrated zone config for a
 // given database ID.
 func GetHydratedZoneConfigForDatabase(
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -653,6 +654,12 @@ type Writer interface {
 	// decrease, we can stop tracking txnDidNotUpdateMeta and still optimize
 	// ClearIntent by always doing single-clear.
 	ClearIntent(key roachpb.Key, txnDidNotUpdateMeta bool, txnUUID uuid.UUID) error
+	// ClearLock removes a replicated lock of the given strength, which must be
+	// lock.Shared or lock.Update, held by the given transaction on the key.
+	// Intents must be removed using ClearIntent.
+	//
+	// It is safe to modify the contents of the arguments after it returns.
+	ClearLock(key roachpb.Key, str lock.Strength, txnUUID uuid.UUID) error
 	// ClearEngineKey removes the given point key from the engine. It does not
 	// affect range keys.  Note that clear actually removes entries from the
 	// storage engine. This is a general-purpose and low-level method that should
@@ -792,6 +799,13 @@ type Writer interface {
 	//
 	// It is safe to modify the contents of the arguments after Put returns.
 	PutIntent(ctx context.Context, key roachpb.Key, value []byte, txnUUID uuid.UUID) error
+	// PutLock puts a replicated lock of the given strength, which must be
+	// lock.Shared or lock.Update, held by the given transaction on the key.
+	// Unlike intents, such locks are not interleaved with the MVCC versions of
+	// the key they lock. Intents must be written using PutIntent.
+	//
+	// It is safe to modify the contents of the arguments after Put returns.
+	PutLock(key roachpb.Key, str lock.Strength, value []byte, txnUUID uuid.UUID) error
 	// PutEngineKey sets the given key to the value provided. This is a
 	// general-purpose and low-level method that should be used sparingly,
 	// only when the other Put* methods are not applicable.
@@ -941,6 +955,13 @@ type Engine interface {
 	// CompactRange ensures that the specified range of key value pairs is
 	// optimized for space efficiency.
 	CompactRange(start, end roachpb.Key) error
+	// ListSSTables returns the SSTables in the engine's LSM, across all levels.
+	ListSSTables() ([]SSTable, error)
+	// VerifySSTableChecksums reads all the blocks of the given SSTable and
+	// verifies their checksums, returning an error if the SSTable is corrupted
+	// or can't be read. It returns nil if the SSTable no longer exists, e.g.
+	// because it was compacted away since it was listed.
+	VerifySSTableChecksums(sst SSTable) error
 	// RegisterFlushCompletedCallback registers a callback that will be run for
 	// every successful flush. Only one callback can be registered at a time, so
 	// registering again replaces the previous callback. The callback must
@@ -976,6 +997,17 @@ type Engine interface {
 	SetStoreID(ctx context.Context, storeID int32) error
 }
 
+// SSTable describes an SSTable in the LSM of an engine.
+type SSTable struct {
+	// FileNum identifies the SSTable within the engine.
+	FileNum uint64
+	// Size is the size of the SSTable, in bytes.
+	Size uint64
+	// Smallest and Largest are the bounds (inclusive) of the keys in the
+	// SSTable.
+	Smallest, Largest roachpb.Key
+}
+
 // Batch is the interface for batch specific operations.
 type Batch interface {
 	// Iterators created on a batch can see some mutations performed after the
@@ -1214,6 +1246,8 @@ type EncryptionRegistries struct {
 // key, it will return nil rather than an error. Errors are returned for problem
 // at the storage layer, problem decoding the key, problem unmarshalling the
 // intent, missing transaction on the intent or multiple intents for this key.
+// Replicated locks with a strength other than lock.Exclusive are not intents
+// and are ignored.
 func GetIntent(reader Reader, key roachpb.Key) (*roachpb.Intent, error) {
 	// Translate this key from a regular key to one in the lock space so it can be
 	// used for queries.
@@ -1222,55 +1256,49 @@ func GetIntent(reader Reader, key roachpb.Key) (*roachpb.Intent, error) {
 	iter := reader.NewEngineIterator(IterOptions{Prefix: true, LowerBound: lbKey})
 	defer iter.Close()
 
-	valid, err := iter.SeekEngineKeyGE(EngineKey{Key: lbKey})
-	if err != nil {
-		return nil, err
-	}
-	if !valid {
-		return nil, nil
-	}
-
-	engineKey, err := iter.EngineKey()
-	if err != nil {
-		return nil, err
-	}
-	checkKey, err := keys.DecodeLockTableSingleKey(engineKey.Key)
-	if err != nil {
-		return nil, err
-	}
-	if !checkKey.Equal(key) {
-		// This should not be possible, a key and using prefix match means that it
-		// must match.
-		return nil, errors.AssertionFailedf("key does not match expected %v != %v", checkKey, key)
-	}
-	var meta enginepb.MVCCMetadata
-	v, err := iter.UnsafeValue()
-	if err != nil {
-		return nil, err
-	}
-	if err = protoutil.Unmarshal(v, &meta); err != nil {
-		return nil, err
-	}
-	if meta.Txn == nil {
-		return nil, errors.AssertionFailedf("txn is null for key %v, intent %v", key, meta)
-	}
-	intent := roachpb.MakeIntent(meta.Txn, key)
-
-	hasNext, err := iter.NextEngineKey()
-	if err != nil {
-		// We expect false on the call to next, but not an error.
-		return nil, err
-	}
-	// This should not be possible. There can only be one outstanding write
-	// intent for a key and with prefix match we don't find additional names.
-	if hasNext {
+	var intent *roachpb.Intent
+	var valid bool
+	var err error
+	for valid, err = iter.SeekEngineKeyGE(EngineKey{Key: lbKey}); valid; valid, err = iter.NextEngineKey() {
 		engineKey, err := iter.EngineKey()
 		if err != nil {
 			return nil, err
 		}
-		return nil, errors.AssertionFailedf("unexpected additional key found %v while looking for %v", engineKey, key)
+		ltKey, err := engineKey.ToLockTableKey()
+		if err != nil {
+			return nil, err
+		}
+		if !ltKey.Key.Equal(key) {
+			// This should not be possible, a key and using prefix match means that it
+			// must match.
+			return nil, errors.AssertionFailedf("key does not match expected %v != %v", ltKey.Key, key)
+		}
+		if ltKey.Strength != lock.Exclusive {
+			continue
+		}
+		// This should not be possible. There can only be one outstanding write
+		// intent for a key and with prefix match we don't find additional names.
+		if intent != nil {
+			return nil, errors.AssertionFailedf("unexpected additional key found %v while looking for %v", engineKey, key)
+		}
+		var meta enginepb.MVCCMetadata
+		v, err := iter.UnsafeValue()
+		if err != nil {
+			return nil, err
+		}
+		if err = protoutil.Unmarshal(v, &meta); err != nil {
+			return nil, err
+		}
+		if meta.Txn == nil {
+			return nil, errors.AssertionFailedf("txn is null for key %v, intent %v", key, meta)
+		}
+		in := roachpb.MakeIntent(meta.Txn, key)
+		intent = &in
+	}
+	if err != nil {
+		return nil, err
 	}
-	return &intent, nil
+	return intent, nil
 }
 
 // Scan returns up to max point key/value objects from start (inclusive) to end
@@ -1293,8 +1321,25 @@ func Scan(reader Reader, start, end roachpb.Key, max int64) ([]MVCCKeyValue, err
 	return kvs, err
 }
 
+// iterAtIntent returns whether the EngineIterator, which must be positioned in
+// the lock table, is at an intent rather than at a replicated lock of another
+// strength.
+func iterAtIntent(iter EngineIterator) (bool, error) {
+	engineKey, err := iter.UnsafeEngineKey()
+	if err != nil {
+		return false, err
+	}
+	ltKey, err := engineKey.ToLockTableKey()
+	if err != nil {
+		return false, err
+	}
+	return ltKey.Strength == lock.Exclusive, nil
+}
+
 // ScanIntents scans intents using only the separated intents lock table. It
-// does not take interleaved intents into account at all.
+// does not take interleaved intents into account at all. Replicated locks of
+// other strengths are returned as well, since they also need to be resolved
+// once their transaction is finalized.
 func ScanIntents(
 	ctx context.Context, reader Reader, start, end roachpb.Key, maxIntents int64, targetBytes int64,
 ) ([]roachpb.Intent, error) {
@@ -1828,6 +1873,13 @@ func ScanConflictingIntentsForDroppingLatchesEarly(
 			// not needing intent history.
 			return true /* needsIntentHistory */, nil
 		}
+		if isIntent, err := iterAtIntent(iter); err != nil {
+			return false, err
+		} else if !isIntent {
+			// Replicated shared and update locks don't conflict with non-locking
+			// reads.
+			continue
+		}
 		v, err := iter.UnsafeValue()
 		if err != nil {
 			return false, err
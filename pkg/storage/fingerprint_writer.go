@@ -205,6 +205,30 @@ func (f *fingerprintWriter) stripIndexPrefix(key []byte) []byte {
 	return remainder
 }
 
+// FingerprintPointKey returns the hash of a point key and its value that an
+// ExportRequest with the given fingerprint options combines into the
+// fingerprint of the exported span, so that the SSTs produced by an
+// ExportRequest can be fingerprinted the same way. The value is the encoded
+// roachpb.Value, as written to an exported SST.
+func FingerprintPointKey(
+	hasher hash.Hash64, opts MVCCExportFingerprintOptions, key MVCCKey, value []byte,
+) (uint64, error) {
+	f := fingerprintWriter{hasher: hasher, options: opts}
+	defer f.hasher.Reset()
+	if err := f.hashKey(key.Key); err != nil {
+		return 0, err
+	}
+	if !key.Timestamp.IsEmpty() {
+		if err := f.hashTimestamp(key.Timestamp); err != nil {
+			return 0, err
+		}
+	}
+	if err := f.hashValue(value); err != nil {
+		return 0, err
+	}
+	return f.hasher.Sum64(), nil
+}
+
 // FingerprintRangekeys iterates over the provided SSTs, that are expected to
 // contain only rangekeys, and maintains a XOR aggregate of each rangekey's
 // fingerprint.
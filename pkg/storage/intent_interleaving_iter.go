@@ -97,8 +97,14 @@ type intentInterleavingIter struct {
 
 	// intentIter is for iterating over separated intents, so that
 	// intentInterleavingIter can make them look as if they were interleaved.
-	intentIter      *pebbleIterator // EngineIterator
+	intentIter      lockTableIntentIter // EngineIterator
 	intentIterState pebble.IterValidityState
+	// seekKeyLocksKnown is set when the last SeekGE, to a key without a
+	// timestamp, determined whether replicated locks other than intents that
+	// may conflict with a write are held on the key. seekKeyHasLocks is the
+	// result. See seekKeyMayHaveNonIntentLocks.
+	seekKeyLocksKnown bool
+	seekKeyHasLocks   bool
 	// The decoded key from the lock table. This is an unsafe key
 	// in that it is only valid when intentIter has not been
 	// repositioned. It is nil if the intentIter is considered to be
@@ -257,7 +263,7 @@ func newIntentInterleavingIterator(reader Reader, opts IterOptions) MVCCIterator
 	//
 	// Note that we can reuse intentKeyBuf, intentLimitKeyBuf after
 	// NewEngineIterator returns.
-	intentIter := reader.NewEngineIterator(intentOpts).(*pebbleIterator)
+	intentIter := lockTableIntentIter{reader.NewEngineIterator(intentOpts).(*pebbleIterator)}
 
 	// The creation of these iterators can race with concurrent mutations, which
 	// may make them inconsistent with each other. So we clone here, to ensure
@@ -282,6 +288,123 @@ func newIntentInterleavingIterator(reader Reader, opts IterOptions) MVCCIterator
 	return iiIter
 }
 
+// lockTableIntentIter is an EngineIterator over the lock table that only
+// surfaces intents, i.e. locks with lock.Exclusive strength. Replicated Shared
+// and Update locks also live in the lock table, but they don't have a
+// provisional value in the MVCC keyspace, so they must not be interleaved.
+type lockTableIntentIter struct {
+	*pebbleIterator
+}
+
+// atNonIntent returns whether the iterator, in the given state, is positioned
+// at a lock that is not an intent.
+func (it lockTableIntentIter) atNonIntent(state pebble.IterValidityState) (bool, error) {
+	if state != pebble.IterValid {
+		return false, nil
+	}
+	engineKey, err := it.UnsafeEngineKey()
+	if err != nil {
+		return false, err
+	}
+	return len(engineKey.Version) == engineKeyVersionLockTableLen &&
+		lock.Strength(engineKey.Version[0]) != lock.Exclusive, nil
+}
+
+func (it lockTableIntentIter) skipNonIntentsForward(
+	state pebble.IterValidityState, err error, limit roachpb.Key,
+) (pebble.IterValidityState, error) {
+	for err == nil {
+		var skip bool
+		if skip, err = it.atNonIntent(state); err != nil || !skip {
+			break
+		}
+		state, err = it.pebbleIterator.NextEngineKeyWithLimit(limit)
+	}
+	return state, err
+}
+
+func (it lockTableIntentIter) skipNonIntentsBackward(
+	state pebble.IterValidityState, err error, limit roachpb.Key,
+) (pebble.IterValidityState, error) {
+	for err == nil {
+		var skip bool
+		if skip, err = it.atNonIntent(state); err != nil || !skip {
+			break
+		}
+		state, err = it.pebbleIterator.PrevEngineKeyWithLimit(limit)
+	}
+	return state, err
+}
+
+// SeekEngineKeyGEWithLimit implements the EngineIterator interface.
+func (it lockTableIntentIter) SeekEngineKeyGEWithLimit(
+	key EngineKey, limit roachpb.Key,
+) (pebble.IterValidityState, error) {
+	state, _, err := it.seekEngineKeyGEWithLimitAndLocks(key, limit)
+	return state, err
+}
+
+// seekEngineKeyGEWithLimitAndLocks is like SeekEngineKeyGEWithLimit, but also
+// returns whether locks other than intents were skipped on key.Key. Locks on a
+// key are ordered by descending strength, so these are only visited when the
+// key has no intent.
+func (it lockTableIntentIter) seekEngineKeyGEWithLimitAndLocks(
+	key EngineKey, limit roachpb.Key,
+) (_ pebble.IterValidityState, skippedLocks bool, _ error) {
+	state, err := it.pebbleIterator.SeekEngineKeyGEWithLimit(key, limit)
+	for err == nil {
+		var skip bool
+		if skip, err = it.atNonIntent(state); err != nil || !skip {
+			break
+		}
+		if !skippedLocks {
+			var engineKey EngineKey
+			if engineKey, err = it.UnsafeEngineKey(); err != nil {
+				break
+			}
+			skippedLocks = engineKey.Key.Equal(key.Key)
+		}
+		state, err = it.pebbleIterator.NextEngineKeyWithLimit(limit)
+	}
+	return state, skippedLocks, err
+}
+
+// NextEngineKeyWithLimit implements the EngineIterator interface.
+func (it lockTableIntentIter) NextEngineKeyWithLimit(
+	limit roachpb.Key,
+) (pebble.IterValidityState, error) {
+	state, err := it.pebbleIterator.NextEngineKeyWithLimit(limit)
+	return it.skipNonIntentsForward(state, err, limit)
+}
+
+// SeekEngineKeyLTWithLimit implements the EngineIterator interface.
+func (it lockTableIntentIter) SeekEngineKeyLTWithLimit(
+	key EngineKey, limit roachpb.Key,
+) (pebble.IterValidityState, error) {
+	state, err := it.pebbleIterator.SeekEngineKeyLTWithLimit(key, limit)
+	return it.skipNonIntentsBackward(state, err, limit)
+}
+
+// PrevEngineKeyWithLimit implements the EngineIterator interface.
+func (it lockTableIntentIter) PrevEngineKeyWithLimit(
+	limit roachpb.Key,
+) (pebble.IterValidityState, error) {
+	state, err := it.pebbleIterator.PrevEngineKeyWithLimit(limit)
+	return it.skipNonIntentsBackward(state, err, limit)
+}
+
+// seekKeyMayHaveNonIntentLocks returns whether replicated locks other than
+// intents that may conflict with a write can be held on the key the iterator
+// was last positioned at with SeekGE, with an empty timestamp. Such locks are
+// skipped by the intentIter, which notes them on the way. When the key has an
+// intent they are not visited, but then the only other locks on the key are
+// held by the intent's transaction, as locks acquired by other transactions
+// conflict with the intent. It conservatively returns true if the last seek
+// was of another kind.
+func (i *intentInterleavingIter) seekKeyMayHaveNonIntentLocks() bool {
+	return !i.seekKeyLocksKnown || i.seekKeyHasLocks
+}
+
 // TODO(sumeer): the limits generated below are tight for the current value of
 // i.iterKey.Key. And the semantics of the underlying *WithLimit methods in
 // pebble.Iterator are best-effort, but the implementation is not. Consider
@@ -475,6 +598,7 @@ func (i *intentInterleavingIter) SeekGE(key MVCCKey) {
 	i.dir = +1
 	i.valid = true
 	i.err = nil
+	i.seekKeyLocksKnown, i.seekKeyHasLocks = false, false
 
 	if i.constraint != notConstrained {
 		i.checkConstraint(key.Key, false)
@@ -501,19 +625,24 @@ func (i *intentInterleavingIter) SeekGE(key MVCCKey) {
 	}
 	if !i.iterValid && i.prefix {
 		// The prefix seek below will also certainly fail, as we didn't find an
-		// MVCC value here.
+		// MVCC value here. Nor can the key have other locks, which are only
+		// acquired on keys with a value.
 		intentSeekKey = nil
 		i.intentKey = nil
+		i.seekKeyLocksKnown = key.Timestamp.IsEmpty()
 	}
 	if intentSeekKey != nil {
 		var limitKey roachpb.Key
 		if i.iterValid && !i.prefix {
 			limitKey = i.makeUpperLimitKey()
 		}
-		iterState, err := i.intentIter.SeekEngineKeyGEWithLimit(EngineKey{Key: intentSeekKey}, limitKey)
+		iterState, skippedLocks, err :=
+			i.intentIter.seekEngineKeyGEWithLimitAndLocks(EngineKey{Key: intentSeekKey}, limitKey)
 		if err = i.tryDecodeLockKey(iterState, err); err != nil {
 			return
 		}
+		i.seekKeyLocksKnown = key.Timestamp.IsEmpty()
+		i.seekKeyHasLocks = skippedLocks
 		if err := i.maybeSkipIntentRangeKey(); err != nil {
 			return
 		}
@@ -527,6 +656,7 @@ func (i *intentInterleavingIter) SeekIntentGE(key roachpb.Key, txnUUID uuid.UUID
 	i.dir = +1
 	i.valid = true
 	i.err = nil
+	i.seekKeyLocksKnown, i.seekKeyHasLocks = false, false
 
 	if i.constraint != notConstrained {
 		i.checkConstraint(key, false)
@@ -1053,6 +1183,7 @@ func (i *intentInterleavingIter) SeekLT(key MVCCKey) {
 	i.dir = -1
 	i.valid = true
 	i.err = nil
+	i.seekKeyLocksKnown, i.seekKeyHasLocks = false, false
 
 	if i.prefix {
 		i.err = errors.Errorf("prefix iteration is not permitted with SeekLT")
@@ -12,6 +12,7 @@ package storage
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"math/rand"
@@ -20,9 +21,11 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/datapathutils"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -32,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble"
 	"github.com/stretchr/testify/require"
 )
@@ -466,6 +470,57 @@ func TestIntentInterleavingIterBoundaries(t *testing.T) {
 	}()
 }
 
+// TestIntentInterleavingIterSeekKeyLocks tests that the intentInterleavingIter
+// notes the replicated locks other than intents on the key it was seeked to,
+// which lets writers skip the separate check for conflicting locks.
+func TestIntentInterleavingIterSeekKeyLocks(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := createTestPebbleEngine()
+	defer eng.Close()
+
+	for _, key := range []roachpb.Key{testKey1, testKey2, testKey3} {
+		require.NoError(t, MVCCPut(ctx, eng, nil, key, hlc.Timestamp{Logical: 1}, hlc.ClockTimestamp{}, value1, nil))
+	}
+	// testKey1 has a shared lock, testKey2 has no locks, and testKey3 has an
+	// intent and a shared lock held by the same transaction.
+	require.NoError(t, MVCCAcquireLock(ctx, eng, txn1, lock.Shared, testKey1))
+	require.NoError(t, MVCCAcquireLock(ctx, eng, txn2, lock.Shared, testKey3))
+	require.NoError(t, MVCCPut(ctx, eng, nil, testKey3, txn2.ReadTimestamp, hlc.ClockTimestamp{}, value2, txn2))
+
+	testutils.RunTrueAndFalse(t, "prefix", func(t *testing.T, prefix bool) {
+		iter := newIntentInterleavingIterator(eng, IterOptions{Prefix: prefix, UpperBound: keys.MaxKey}).(*intentInterleavingIter)
+		defer iter.Close()
+
+		iter.SeekGE(MVCCKey{Key: testKey1})
+		require.True(t, iter.seekKeyMayHaveNonIntentLocks())
+		iter.SeekGE(MVCCKey{Key: testKey2})
+		require.False(t, iter.seekKeyMayHaveNonIntentLocks())
+		iter.SeekGE(MVCCKey{Key: testKey3})
+		require.False(t, iter.seekKeyMayHaveNonIntentLocks())
+		iter.SeekGE(MVCCKey{Key: testKey4})
+		require.False(t, iter.seekKeyMayHaveNonIntentLocks())
+
+		// Other seeks don't tell.
+		iter.SeekGE(MVCCKey{Key: testKey2, Timestamp: hlc.Timestamp{Logical: 1}})
+		require.True(t, iter.seekKeyMayHaveNonIntentLocks())
+		if !prefix {
+			iter.SeekLT(MVCCKey{Key: testKey2})
+			require.True(t, iter.seekKeyMayHaveNonIntentLocks())
+		}
+	})
+
+	// Writes still discover the lock on testKey1.
+	err := MVCCPut(ctx, eng, nil, testKey1, hlc.Timestamp{Logical: 3}, hlc.ClockTimestamp{}, value2, nil)
+	var wiErr *kvpb.WriteIntentError
+	require.True(t, errors.As(err, &wiErr), "unexpected error %v", err)
+	require.Len(t, wiErr.Intents, 1)
+	require.Equal(t, txn1ID, wiErr.Intents[0].Txn.ID)
+	require.NoError(t, MVCCPut(ctx, eng, nil, testKey2, hlc.Timestamp{Logical: 3}, hlc.ClockTimestamp{}, value2, nil))
+}
+
 type lockKeyValue struct {
 	key LockTableKey
 	val []byte
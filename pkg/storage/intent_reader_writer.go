@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
 )
 
 // This file defines wrappers for Reader and Writer, and functions to do the
@@ -66,6 +67,42 @@ func (idw intentDemuxWriter) PutIntent(
 	return buf, idw.w.PutEngineKey(engineKey, value)
 }
 
+// PutLock has the same behavior as Writer.PutLock. buf is used as
+// scratch-space to avoid allocations -- its contents will be overwritten and
+// not appended to, and a possibly different buf returned.
+func (idw intentDemuxWriter) PutLock(
+	key roachpb.Key, str lock.Strength, value []byte, txnUUID uuid.UUID, buf []byte,
+) (_ []byte, _ error) {
+	if str != lock.Shared && str != lock.Update {
+		return buf, errors.AssertionFailedf("unexpected lock strength %s", str)
+	}
+	var engineKey EngineKey
+	engineKey, buf = LockTableKey{
+		Key:      key,
+		Strength: str,
+		TxnUUID:  txnUUID[:],
+	}.ToEngineKey(buf)
+	return buf, idw.w.PutEngineKey(engineKey, value)
+}
+
+// ClearLock has the same behavior as Writer.ClearLock. buf is used as
+// scratch-space to avoid allocations -- its contents will be overwritten and
+// not appended to, and a possibly different buf returned.
+func (idw intentDemuxWriter) ClearLock(
+	key roachpb.Key, str lock.Strength, txnUUID uuid.UUID, buf []byte,
+) (_ []byte, _ error) {
+	if str != lock.Shared && str != lock.Update {
+		return buf, errors.AssertionFailedf("unexpected lock strength %s", str)
+	}
+	var engineKey EngineKey
+	engineKey, buf = LockTableKey{
+		Key:      key,
+		Strength: str,
+		TxnUUID:  txnUUID[:],
+	}.ToEngineKey(buf)
+	return buf, idw.w.ClearEngineKey(engineKey)
+}
+
 // ClearMVCCRange has the same behavior as Writer.ClearMVCCRange. buf is used as
 // scratch-space to avoid allocations -- its contents will be overwritten and
 // not appended to, and a possibly different buf returned.
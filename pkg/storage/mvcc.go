@@ -44,6 +44,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble"
 )
@@ -1815,6 +1816,18 @@ func mvccPutInternal(
 		return ok && !buf.meta.Deleted, err
 	}
 
+	// Replicated shared and update locks are not interleaved with the key's
+	// versions, so check for conflicting ones held by other transactions
+	// separately. The intentInterleavingIter notes them when seeking to the
+	// key above, so the check, which needs its own iterator, is skipped for
+	// the common case of a key without such locks. Blind writes don't use an
+	// iterator and skip this check, as they skip the check for intents.
+	if reader, ok := writer.(Reader); ok && iter != nil && mvccMayHaveNonIntentLocks(iter) {
+		if _, err := mvccCheckForConflictingLocks(ctx, reader, txn, lock.Intent, key); err != nil {
+			return false, err
+		}
+	}
+
 	// Determine the read and write timestamps for the write. For a
 	// non-transactional write, these will be identical. For a transactional
 	// write, we read at the transaction's read timestamp but write intents at its
@@ -4189,6 +4202,168 @@ func MVCCPaginate(
 	}
 }
 
+// MVCCAcquireLock attempts to acquire a replicated lock with the given
+// strength, which must be lock.Shared or lock.Update, on the key on behalf of
+// the transaction. Unlike intents, such locks are stored in the lock table
+// keyspace without a provisional value, so they are not interleaved with the
+// key's MVCC versions and are not accounted for in MVCC stats. They are
+// released when the transaction's locks are resolved after it is finalized.
+//
+// A WriteIntentError is returned if the key is locked by another transaction
+// with a conflicting strength.
+func MVCCAcquireLock(
+	ctx context.Context,
+	rw ReadWriter,
+	txn *roachpb.Transaction,
+	str lock.Strength,
+	key roachpb.Key,
+) error {
+	if len(key) == 0 {
+		return emptyKeyError()
+	}
+	if txn == nil {
+		return errors.AssertionFailedf("cannot acquire lock on %q without a transaction", key)
+	}
+	if str != lock.Shared && str != lock.Update {
+		return errors.AssertionFailedf("cannot acquire replicated lock with strength %s", str)
+	}
+	held, err := mvccCheckForConflictingLocks(ctx, rw, txn, str, key)
+	if err != nil || held {
+		return err
+	}
+	meta := enginepb.MVCCMetadata{
+		Txn:       &txn.TxnMeta,
+		Timestamp: txn.WriteTimestamp.ToLegacyTimestamp(),
+	}
+	metaBytes, err := protoutil.Marshal(&meta)
+	if err != nil {
+		return err
+	}
+	return rw.PutLock(key, str, metaBytes, txn.ID)
+}
+
+// mvccCheckForConflictingLocks checks whether a lock of the given strength can
+// be acquired on the key by the transaction, or, if str is lock.Intent, whether
+// an intent can be written to it. Intents in the lock table are only
+// considered for lock acquisition, since writers discover them through the
+// intentInterleavingIter. A WriteIntentError is returned for conflicting locks
+// held by other transactions, and whether the transaction already holds a lock
+// with the given strength on the key is returned otherwise. txn may be nil for
+// non-transactional writes.
+func mvccCheckForConflictingLocks(
+	ctx context.Context, reader Reader, txn *roachpb.Transaction, str lock.Strength, key roachpb.Key,
+) (held bool, _ error) {
+	ltStart, _ := keys.LockTableSingleKey(key, nil)
+	ltEnd, _ := keys.LockTableSingleKey(key.Next(), nil)
+	iter := reader.NewEngineIterator(IterOptions{LowerBound: ltStart, UpperBound: ltEnd})
+	defer iter.Close()
+
+	var intents []roachpb.Intent
+	var meta enginepb.MVCCMetadata
+	var valid bool
+	var err error
+	for valid, err = iter.SeekEngineKeyGE(EngineKey{Key: ltStart}); valid; valid, err = iter.NextEngineKey() {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		engineKey, err := iter.UnsafeEngineKey()
+		if err != nil {
+			return false, err
+		}
+		ltKey, err := engineKey.ToLockTableKey()
+		if err != nil {
+			return false, err
+		}
+		if txn != nil && bytes.Equal(ltKey.TxnUUID, txn.ID.GetBytes()) {
+			held = held || ltKey.Strength == str
+			continue
+		}
+		if !replicatedLocksConflict(ltKey.Strength, str) {
+			continue
+		}
+		v, err := iter.UnsafeValue()
+		if err != nil {
+			return false, err
+		}
+		if err := protoutil.Unmarshal(v, &meta); err != nil {
+			return false, err
+		}
+		if meta.Txn == nil {
+			return false, errors.AssertionFailedf("lock with no txn on key %q", key)
+		}
+		intents = append(intents, roachpb.MakeIntent(meta.Txn, key))
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(intents) > 0 {
+		return false, &kvpb.WriteIntentError{Intents: intents}
+	}
+	return held, nil
+}
+
+// mvccMayHaveNonIntentLocks returns whether the key the iterator was last
+// positioned at with SeekGE may have replicated locks other than intents that
+// conflict with a write. It returns true for iterators that can't tell.
+func mvccMayHaveNonIntentLocks(iter MVCCIterator) bool {
+	if iiIter, ok := maybeUnwrapUnsafeIter(iter).(*intentInterleavingIter); ok {
+		return iiIter.seekKeyMayHaveNonIntentLocks()
+	}
+	return true
+}
+
+// replicatedLocksConflict returns whether a replicated lock with strength held
+// conflicts with the acquisition of a lock with strength str, where
+// lock.Intent stands for writing an intent. Intents are stored with
+// lock.Exclusive strength.
+func replicatedLocksConflict(held, str lock.Strength) bool {
+	switch held {
+	case lock.Shared:
+		return str == lock.Exclusive || str == lock.Intent
+	case lock.Update:
+		return str == lock.Update || str == lock.Exclusive || str == lock.Intent
+	case lock.Exclusive:
+		// Writers discover intents through the intentInterleavingIter, which also
+		// handles the writer's own intent.
+		return str != lock.Intent
+	default:
+		return true
+	}
+}
+
+// mvccReleaseLocks releases the replicated locks other than intents held by
+// the transaction on the key. It returns whether any lock was released.
+func mvccReleaseLocks(
+	ctx context.Context, rw ReadWriter, txnID uuid.UUID, key roachpb.Key,
+) (bool, error) {
+	ltStart, _ := keys.LockTableSingleKey(key, nil)
+	ltEnd, _ := keys.LockTableSingleKey(key.Next(), nil)
+	iter := rw.NewEngineIterator(IterOptions{LowerBound: ltStart, UpperBound: ltEnd})
+	defer iter.Close()
+
+	var released bool
+	var valid bool
+	var err error
+	for valid, err = iter.SeekEngineKeyGE(EngineKey{Key: ltStart}); valid; valid, err = iter.NextEngineKey() {
+		engineKey, err := iter.UnsafeEngineKey()
+		if err != nil {
+			return false, err
+		}
+		ltKey, err := engineKey.ToLockTableKey()
+		if err != nil {
+			return false, err
+		}
+		if ltKey.Strength == lock.Exclusive || !bytes.Equal(ltKey.TxnUUID, txnID.GetBytes()) {
+			continue
+		}
+		if err := rw.ClearLock(key, ltKey.Strength, txnID); err != nil {
+			return false, err
+		}
+		released = true
+	}
+	return released, err
+}
+
 // MVCCResolveWriteIntent either commits, aborts (rolls back), or moves forward
 // in time an extant write intent for a given txn according to commit
 // parameter. ResolveWriteIntent will skip write intents of other txns.
@@ -4244,9 +4419,16 @@ func MVCCResolveWriteIntent(
 	// the database.
 	beforeBytes := rw.BufferedSize()
 	ok, err = mvccResolveWriteIntent(ctx, rw, iterAndBuf.iter, ms, intent, iterAndBuf.buf)
-	numBytes = int64(rw.BufferedSize() - beforeBytes)
 	// Using defer would be more convenient, but it is measurably slower.
 	iterAndBuf.Cleanup()
+	if err == nil && intent.Status.IsFinalized() {
+		// Release any replicated shared or update locks held by the finalized
+		// transaction, which aren't handled by mvccResolveWriteIntent.
+		var released bool
+		released, err = mvccReleaseLocks(ctx, rw, intent.Txn.ID, intent.Key)
+		ok = ok || released
+	}
+	numBytes = int64(rw.BufferedSize() - beforeBytes)
 	return ok, numBytes, nil, err
 }
 
@@ -4295,6 +4477,9 @@ type separatedIntentAndVersionIter struct {
 	engineIterValid bool
 	engineIterErr   error
 	intentKey       roachpb.Key
+	// lockStrength is the strength of the lock at intentKey. Only locks with
+	// lock.Exclusive strength are intents.
+	lockStrength lock.Strength
 }
 
 var _ iterForKeyVersions = &separatedIntentAndVersionIter{}
@@ -4321,11 +4506,13 @@ func (s *separatedIntentAndVersionIter) initIntentKey() {
 			s.engineIterValid = false
 			return
 		}
-		if s.intentKey, err = keys.DecodeLockTableSingleKey(engineKey.Key); err != nil {
+		ltKey, err := engineKey.ToLockTableKey()
+		if err != nil {
 			s.engineIterErr = err
 			s.engineIterValid = false
 			return
 		}
+		s.intentKey, s.lockStrength = ltKey.Key, ltKey.Strength
 	}
 }
 
@@ -5103,6 +5290,21 @@ func MVCCResolveWriteIntentRange(
 			sepIter.nextEngineKey()
 			continue
 		}
+		if sepIter.lockStrength != lock.Exclusive {
+			// A replicated shared or update lock, which has no provisional value.
+			// It is released once the txn is finalized.
+			if intent.Status.IsFinalized() {
+				lastResolvedKey = append(lastResolvedKey[:0], sepIter.intentKey...)
+				beforeBytes := rw.BufferedSize()
+				if err := rw.ClearLock(lastResolvedKey, sepIter.lockStrength, meta.Txn.ID); err != nil {
+					return 0, 0, nil, 0, err
+				}
+				numKeys++
+				numBytes += int64(rw.BufferedSize() - beforeBytes)
+			}
+			sepIter.nextEngineKey()
+			continue
+		}
 		// Stash the parsed meta so don't need to parse it again in
 		// mvccResolveWriteIntent. This parsing can be ~10% of the resolution cost
 		// in some benchmarks.
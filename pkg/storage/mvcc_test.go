@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -525,6 +526,69 @@ func TestMVCCGetWriteIntentError(t *testing.T) {
 	}
 }
 
+func TestMVCCAcquireReplicatedLock(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	engine := NewDefaultInMemForTesting()
+	defer engine.Close()
+
+	require.NoError(t, MVCCPut(ctx, engine, nil, testKey1, hlc.Timestamp{WallTime: 1}, hlc.ClockTimestamp{}, value1, nil))
+	statsBefore, err := ComputeStats(engine, keys.LocalMax, roachpb.KeyMax, 0)
+	require.NoError(t, err)
+
+	// Shared locks are compatible with each other and with a single Update lock.
+	require.NoError(t, MVCCAcquireLock(ctx, engine, txn1, lock.Shared, testKey1))
+	require.NoError(t, MVCCAcquireLock(ctx, engine, txn2, lock.Shared, testKey1))
+	require.NoError(t, MVCCAcquireLock(ctx, engine, txn2, lock.Update, testKey1))
+	// Reacquiring a held lock is a no-op.
+	require.NoError(t, MVCCAcquireLock(ctx, engine, txn2, lock.Update, testKey1))
+	// Intents can't be written as replicated locks of other strengths.
+	require.Error(t, MVCCAcquireLock(ctx, engine, txn1, lock.Exclusive, testKey1))
+
+	// Update locks conflict with each other.
+	err = MVCCAcquireLock(ctx, engine, txn1, lock.Update, testKey1)
+	var wiErr *kvpb.WriteIntentError
+	require.True(t, errors.As(err, &wiErr), "unexpected error %v", err)
+	require.Len(t, wiErr.Intents, 1)
+	require.Equal(t, txn2ID, wiErr.Intents[0].Txn.ID)
+
+	// The locks are not interleaved with the key's versions, are not intents,
+	// and are not accounted for in MVCC stats.
+	valueRes, err := MVCCGet(ctx, engine, testKey1, hlc.Timestamp{WallTime: 2}, MVCCGetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, valueRes.Value)
+	require.Equal(t, value1.RawBytes, valueRes.Value.RawBytes)
+	intent, err := GetIntent(engine, testKey1)
+	require.NoError(t, err)
+	require.Nil(t, intent)
+	statsAfter, err := ComputeStats(engine, keys.LocalMax, roachpb.KeyMax, 0)
+	require.NoError(t, err)
+	require.Equal(t, statsBefore, statsAfter)
+
+	// Writes conflict with the locks of both transactions.
+	err = MVCCPut(ctx, engine, nil, testKey1, hlc.Timestamp{WallTime: 3}, hlc.ClockTimestamp{}, value2, nil)
+	require.True(t, errors.As(err, &wiErr), "unexpected error %v", err)
+	require.Len(t, wiErr.Intents, 2)
+
+	// Resolving the finalized transactions releases their locks.
+	ok, _, _, err := MVCCResolveWriteIntent(ctx, engine, nil,
+		roachpb.MakeLockUpdate(txn1Commit, roachpb.Span{Key: testKey1}),
+		MVCCResolveWriteIntentOptions{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	txn2Abort := *txn2
+	txn2Abort.Status = roachpb.ABORTED
+	numKeys, _, _, _, err := MVCCResolveWriteIntentRange(ctx, engine, nil,
+		roachpb.MakeLockUpdate(&txn2Abort, roachpb.Span{Key: testKey1, EndKey: testKey1.Next()}),
+		MVCCResolveWriteIntentRangeOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), numKeys)
+
+	require.NoError(t, MVCCPut(ctx, engine, nil, testKey1, hlc.Timestamp{WallTime: 3}, hlc.ClockTimestamp{}, value2, nil))
+}
+
 func mkVal(s string, ts hlc.Timestamp) roachpb.Value {
 	v := roachpb.MakeValueFromString(s)
 	v.Timestamp = ts
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -1414,6 +1415,12 @@ func (p *Pebble) ClearIntent(key roachpb.Key, txnDidNotUpdateMeta bool, txnUUID
 	return err
 }
 
+// ClearLock implements the Engine interface.
+func (p *Pebble) ClearLock(key roachpb.Key, str lock.Strength, txnUUID uuid.UUID) error {
+	_, err := p.wrappedIntentWriter.ClearLock(key, str, txnUUID, nil)
+	return err
+}
+
 // ClearEngineKey implements the Engine interface.
 func (p *Pebble) ClearEngineKey(key EngineKey) error {
 	if len(key.Key) == 0 {
@@ -1548,6 +1555,14 @@ func (p *Pebble) PutIntent(
 	return err
 }
 
+// PutLock implements the Engine interface.
+func (p *Pebble) PutLock(
+	key roachpb.Key, str lock.Strength, value []byte, txnUUID uuid.UUID,
+) error {
+	_, err := p.wrappedIntentWriter.PutLock(key, str, value, txnUUID, nil)
+	return err
+}
+
 // PutEngineKey implements the Engine interface.
 func (p *Pebble) PutEngineKey(key EngineKey, value []byte) error {
 	if len(key.Key) == 0 {
@@ -1927,6 +1942,57 @@ func (p *Pebble) CompactRange(start, end roachpb.Key) error {
 	return p.db.Compact(bufStart, bufEnd, true /* parallel */)
 }
 
+// ListSSTables implements the Engine interface.
+func (p *Pebble) ListSSTables() ([]SSTable, error) {
+	levels, err := p.db.SSTables()
+	if err != nil {
+		return nil, err
+	}
+	var ssts []SSTable
+	for _, level := range levels {
+		for _, info := range level {
+			sst := SSTable{FileNum: uint64(info.FileNum), Size: info.Size}
+			if key, ok := DecodeEngineKey(info.Smallest.UserKey); ok {
+				sst.Smallest = key.Key
+			}
+			if key, ok := DecodeEngineKey(info.Largest.UserKey); ok {
+				sst.Largest = key.Key
+			}
+			ssts = append(ssts, sst)
+		}
+	}
+	return ssts, nil
+}
+
+// VerifySSTableChecksums implements the Engine interface.
+func (p *Pebble) VerifySSTableChecksums(sst SSTable) error {
+	// NB: the file is opened through the engine's filesystem, which takes care
+	// of decrypting it if encryption-at-rest is enabled.
+	filename := p.FS.PathJoin(p.path, fmt.Sprintf("%06d.sst", sst.FileNum))
+	f, err := p.FS.Open(filename)
+	if err != nil {
+		if oserror.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "opening SSTable %s", filename)
+	}
+	readable, err := sstable.NewSimpleReadable(f)
+	if err != nil {
+		_ = f.Close()
+		return errors.Wrapf(err, "opening SSTable %s", filename)
+	}
+	r, err := sstable.NewReader(readable, sstable.ReaderOptions{
+		Comparer:   EngineComparer,
+		MergerName: MVCCMerger.Name,
+	})
+	if err != nil {
+		_ = readable.Close()
+		return errors.Wrapf(err, "reading SSTable %s", filename)
+	}
+	defer r.Close()
+	return errors.Wrapf(r.ValidateBlockChecksums(), "verifying SSTable %s", filename)
+}
+
 // RegisterFlushCompletedCallback implements the Engine interface.
 func (p *Pebble) RegisterFlushCompletedCallback(cb func()) {
 	p.mu.Lock()
@@ -2281,6 +2347,10 @@ func (p *pebbleReadOnly) ClearIntent(
 	panic("not implemented")
 }
 
+func (p *pebbleReadOnly) ClearLock(key roachpb.Key, str lock.Strength, txnUUID uuid.UUID) error {
+	panic("not implemented")
+}
+
 func (p *pebbleReadOnly) ClearEngineKey(key EngineKey) error {
 	panic("not implemented")
 }
@@ -2349,6 +2419,12 @@ func (p *pebbleReadOnly) PutIntent(
 	panic("not implemented")
 }
 
+func (p *pebbleReadOnly) PutLock(
+	key roachpb.Key, str lock.Strength, value []byte, txnUUID uuid.UUID,
+) error {
+	panic("not implemented")
+}
+
 func (p *pebbleReadOnly) PutEngineKey(key EngineKey, value []byte) error {
 	panic("not implemented")
 }
@@ -13,6 +13,7 @@ package storage
 import (
 	"context"
 	"fmt"
+	"io"
 	"math/rand"
 	"path/filepath"
 	"sync/atomic"
@@ -1386,3 +1387,50 @@ func TestApproximateDiskBytes(t *testing.T) {
 		}
 	}
 }
+
+func TestPebbleVerifySSTableChecksums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	p, err := Open(ctx, InMemory(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, err)
+	defer p.Close()
+
+	b := p.NewWriteBatch()
+	for _, k := range []string{"a", "b", "c"} {
+		require.NoError(t, b.PutMVCC(
+			MVCCKey{Key: roachpb.Key(k), Timestamp: hlc.Timestamp{WallTime: 1}},
+			MVCCValue{Value: roachpb.MakeValueFromString(k)},
+		))
+	}
+	require.NoError(t, b.Commit(true /* sync */))
+	require.NoError(t, p.Flush())
+
+	ssts, err := p.ListSSTables()
+	require.NoError(t, err)
+	require.Len(t, ssts, 1)
+	sst := ssts[0]
+	require.Equal(t, roachpb.Key("a"), sst.Smallest)
+	require.Equal(t, roachpb.Key("c"), sst.Largest)
+	require.NoError(t, p.VerifySSTableChecksums(sst))
+
+	// SSTables which no longer exist are skipped.
+	require.NoError(t, p.VerifySSTableChecksums(SSTable{FileNum: sst.FileNum + 1000}))
+
+	// Flip a bit in the first data block of the SSTable, and rewrite it.
+	filename := p.FS.PathJoin(p.path, fmt.Sprintf("%06d.sst", sst.FileNum))
+	f, err := p.FS.Open(filename)
+	require.NoError(t, err)
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	data[0] ^= 0x1
+	f, err = p.FS.Create(filename)
+	require.NoError(t, err)
+	_, err = f.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	require.Error(t, p.VerifySSTableChecksums(sst))
+}
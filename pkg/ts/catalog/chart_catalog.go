@@ -478,6 +478,7 @@ var charts = []sectionDescription{
 					"distsender.rpc.err.txnalreadyencounterederrtype",
 					"distsender.rpc.err.unsupportedrequesterrtype",
 					"distsender.rpc.err.writeintenterrtype",
+					"distsender.rpc.err.writeratelimitexceedederrtype",
 					"distsender.rpc.err.writetooolderrtype",
 				},
 			},
@@ -615,6 +616,12 @@ var charts = []sectionDescription{
 					"kv.rangefeed.registrations",
 				},
 			},
+			{
+				Title: "Rangefeed Filtered Events",
+				Metrics: []string{
+					"kv.rangefeed.filtered_events",
+				},
+			},
 			{
 				Title: "Rangefeed Memory Allocations",
 				Metrics: []string{
@@ -1065,6 +1072,14 @@ var charts = []sectionDescription{
 				Percentiles: false,
 				Metrics:     []string{"requests.backpressure.split"},
 			},
+			{
+				Title:   "Writes Rejected by Range Write Rate Limits",
+				Metrics: []string{"kv.range_write_rate_limit.rejected_requests"},
+			},
+			{
+				Title:   "Bytes Rejected by Range Write Rate Limits",
+				Metrics: []string{"kv.range_write_rate_limit.rejected_bytes"},
+			},
 		},
 	},
 	{
@@ -3242,6 +3257,24 @@ var charts = []sectionDescription{
 				Metrics:   []string{"storage.checkpoints"},
 				AxisLabel: "Directories",
 			},
+			{
+				Title: "Scrubbed SSTables",
+				Metrics: []string{
+					"storage.scrubber.sstables_verified",
+					"storage.scrubber.corrupt_sstables",
+				},
+				AxisLabel: "SSTables",
+			},
+			{
+				Title:     "Scrubbed Bytes",
+				Metrics:   []string{"storage.scrubber.bytes_verified"},
+				AxisLabel: "Bytes",
+			},
+			{
+				Title:     "Quarantined Replicas",
+				Metrics:   []string{"storage.scrubber.quarantined_replicas"},
+				AxisLabel: "Replicas",
+			},
 			{
 				Title: "Bytes Used Per Level",
 				Metrics: []string{
@@ -3670,6 +3703,17 @@ var charts = []sectionDescription{
 					"jobs.stream_replication.resume_retry_error",
 				},
 			},
+			{
+				Title: "Logical Replication",
+				Metrics: []string{
+					"jobs.logical_replication.fail_or_cancel_completed",
+					"jobs.logical_replication.fail_or_cancel_failed",
+					"jobs.logical_replication.fail_or_cancel_retry_error",
+					"jobs.logical_replication.resume_completed",
+					"jobs.logical_replication.resume_failed",
+					"jobs.logical_replication.resume_retry_error",
+				},
+			},
 			{
 				Title: "Long Running Migrations",
 				Metrics: []string{
@@ -4056,6 +4100,7 @@ var charts = []sectionDescription{
 				Metrics: []string{
 					"kv.replica_read_batch_evaluate.dropped_latches_before_eval",
 					"kv.replica_read_batch_evaluate.without_interleaving_iter",
+					"kv.replica_read_batch_evaluate.hot_key_cache_hits",
 				},
 			},
 		},
@@ -276,6 +276,19 @@ const HotRangesTable = ({
         cell: val => <>{val.index_name}</>,
         sort: val => val.index_name,
       },
+      {
+        name: "hotKeys",
+        title: (
+          <Tooltip
+            placement="bottom"
+            title="Keys of the range which are read often and written rarely, and whose reads are served from the leaseholder's hot key cache."
+          >
+            Hot keys
+          </Tooltip>
+        ),
+        cell: val => <>{val.hot_keys?.map(k => k.pretty_key).join(", ")}</>,
+        sort: val => val.hot_keys?.length ?? 0,
+      },
       {
         name: "locality",
         title: (
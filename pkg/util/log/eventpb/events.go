@@ -47,6 +47,7 @@ func (m *CommonJobEventDetails) CommonJobDetails() *CommonJobEventDetails { retu
 
 var _ EventWithCommonJobPayload = (*Import)(nil)
 var _ EventWithCommonJobPayload = (*Restore)(nil)
+var _ EventWithCommonJobPayload = (*DeleteExpiredBackup)(nil)
 
 // RecoveryEventType describes the type of recovery for a RecoveryEvent.
 type RecoveryEventType string